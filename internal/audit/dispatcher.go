@@ -1,23 +1,27 @@
 package audit
 
 import (
+	"context"
 	"log"
 	"sync"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 )
 
 type Event struct {
-	BarbershopID uint
-	UserID       *uint
-	Action       string
-	Entity       string
-	EntityID     *uint
-	Metadata     any
+	BarbershopID uint   `json:"barbershop_id"`
+	UserID       *uint  `json:"user_id,omitempty"`
+	Action       string `json:"action"`
+	Entity       string `json:"entity"`
+	EntityID     *uint  `json:"entity_id,omitempty"`
+	Metadata     any    `json:"metadata,omitempty"`
 }
 
 type Dispatcher struct {
 	logger *Logger
 	queue  chan Event
 	wg     sync.WaitGroup
+	outbox *outbox.Outbox
 }
 
 func NewDispatcher(logger *Logger) *Dispatcher {
@@ -47,14 +51,42 @@ func (d *Dispatcher) worker() {
 	}
 }
 
+// WithOutbox grava os eventos no outbox em vez da fila em memória: nada se
+// perde com a fila cheia ou num restart, e o worker do outbox grava o log.
+func (d *Dispatcher) WithOutbox(o *outbox.Outbox) *Dispatcher {
+	d.outbox = o
+	return d
+}
+
 // Dispatch envia um evento para a fila. Nunca bloqueia nem quebra a request:
-// se a fila estiver cheia, o evento é descartado com um log de aviso.
+// sem outbox, se a fila estiver cheia, o evento é descartado com um log de aviso.
 func (d *Dispatcher) Dispatch(ev Event) {
+	if err := d.DispatchContext(context.Background(), ev); err != nil {
+		log.Println("[audit] outbox enqueue error:", ev.Action, err)
+	}
+}
+
+// DispatchContext é o Dispatch que respeita a transação ligada ao contexto
+// (outbox.ContextWithTx): o evento só é gravado se a transação confirmar.
+// O erro da gravação volta para o chamador: dentro de uma transação do
+// Postgres, um INSERT que falhou aborta a transação, e o commit precisa
+// ser desfeito com a causa certa.
+func (d *Dispatcher) DispatchContext(ctx context.Context, ev Event) error {
+	if d.outbox != nil {
+		return d.outbox.Enqueue(ctx, outbox.Message{
+			BarbershopID: ev.BarbershopID,
+			Topic:        outbox.TopicAudit,
+			Reference:    ev.Action,
+			Payload:      ev,
+		})
+	}
+
 	select {
 	case d.queue <- ev:
 	default:
 		log.Println("[audit] queue full — event dropped:", ev.Action)
 	}
+	return nil
 }
 
 // Shutdown fecha a fila e aguarda o worker persistir todos os eventos pendentes.
@@ -1,6 +1,7 @@
 package audit
 
 import (
+	"context"
 	"encoding/json"
 
 	"gorm.io/gorm"
@@ -43,3 +44,12 @@ func (l *Logger) Log(
 
 	return l.db.Create(&log).Error
 }
+
+// Deliver grava no log um evento vindo do outbox (outbox.TopicAudit).
+func (l *Logger) Deliver(_ context.Context, ev *models.OutboxEvent) error {
+	var e Event
+	if err := json.Unmarshal([]byte(ev.Payload), &e); err != nil {
+		return err
+	}
+	return l.Log(e.BarbershopID, e.UserID, e.Action, e.Entity, e.EntityID, e.Metadata)
+}
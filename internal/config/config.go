@@ -81,6 +81,10 @@ type Config struct {
 	R2BucketName      string
 	R2PublicURL       string // ex: https://pub-xxx.r2.dev
 
+	// LocalStorageDir: sem R2, as imagens vão para este diretório e são
+	// servidas em BACKEND_URL/uploads (desenvolvimento). Vazio desliga.
+	LocalStorageDir string
+
 	// =========================
 	// WHATSAPP (Evolution API)
 	// =========================
@@ -175,6 +179,8 @@ func Load() *Config {
 		R2BucketName:      getEnv("R2_BUCKET_NAME", ""),
 		R2PublicURL:       strings.TrimRight(getEnv("R2_PUBLIC_URL", ""), "/"),
 
+		LocalStorageDir: getEnv("LOCAL_STORAGE_DIR", ""),
+
 		EvolutionURL:    strings.TrimRight(getEnv("EVOLUTION_URL", ""), "/"),
 		EvolutionAPIKey: getEnv("EVOLUTION_API_KEY", ""),
 
@@ -6,6 +6,8 @@ type AvailabilityInput struct {
 	BarbershopID uint
 	BarberID     uint
 	ProductID    uint
+	ProductIDs   []uint // vários serviços, em ordem; vazio = só ProductID
+	ComboID      uint   // serviços de um combo; substitui ProductID/ProductIDs
 	Date         time.Time
 }
 
@@ -0,0 +1,23 @@
+package appointment
+
+import (
+	"context"
+	"time"
+)
+
+// CalendarSync espelha o agendamento no calendário externo do barbeiro
+// (Google Calendar) depois de cada mudança: criação, reagendamento,
+// cancelamento e no-show. Implementações são assíncronas e best-effort —
+// falhas não desfazem a operação. Chamado com um contexto ligado à
+// transação (outbox.ContextWithTx), o pedido de sincronização entra no
+// mesmo commit da mudança.
+type CalendarSync interface {
+	AppointmentChanged(ctx context.Context, barbershopID, appointmentID uint)
+}
+
+// BusyPeriod é um horário ocupado por um evento pessoal do barbeiro no
+// calendário externo. Bloqueia a agenda como o almoço.
+type BusyPeriod struct {
+	Start time.Time
+	End   time.Time
+}
@@ -17,6 +17,13 @@ type AutoCompleteCandidate struct {
 	PaymentMethod string
 }
 
+// ReminderSettings é a configuração de lembretes de uma barbearia.
+type ReminderSettings struct {
+	BarbershopID   uint
+	Timezone       string
+	OffsetsMinutes []int
+}
+
 type JobRepository interface {
 	// --------------------------------------------------
 	// P0.2 — No-show candidates
@@ -51,14 +58,44 @@ type JobRepository interface {
 	) ([]*AutoCompleteCandidate, error)
 
 	// --------------------------------------------------
-	// (Opcional) se você ainda tiver algum job de lembrete
+	// Lembretes (AppointmentReminderJob)
 	// --------------------------------------------------
+
+	// ListReminderSettings retorna as barbearias ativas com lembretes
+	// habilitados e seus offsets configurados.
+	ListReminderSettings(
+		ctx context.Context,
+	) ([]ReminderSettings, error)
+
+	// ListAppointmentsForReminder retorna agendamentos scheduled cujo
+	// start_time está a ±5 minutos de target (com Client, Barbershop e
+	// BarberProduct carregados).
 	ListAppointmentsForReminder(
 		ctx context.Context,
 		barbershopID uint,
 		target time.Time,
 	) ([]*models.Appointment, error)
 
+	// ClaimReminder reserva o envio de um lembrete de forma race-safe:
+	// INSERT ... ON CONFLICT DO NOTHING em appointment_reminders.
+	// Retorna (true) somente se o lembrete ainda não havia sido enviado.
+	ClaimReminder(
+		ctx context.Context,
+		barbershopID uint,
+		appointmentID uint,
+		offsetMinutes int,
+		channel string,
+	) (bool, error)
+
+	// ReleaseReminder desfaz um claim cujo envio falhou, permitindo que o
+	// próximo ciclo tente novamente.
+	ReleaseReminder(
+		ctx context.Context,
+		appointmentID uint,
+		offsetMinutes int,
+		channel string,
+	) error
+
 	// CancelOrphanAwaitingPayments cancela appointments que ficaram
 	// presos em awaiting_payment sem nenhum registro de payment associado.
 	// Isso ocorre quando o checkout cria o appointment mas o cliente abandona
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
 
@@ -28,6 +30,49 @@ type Repository interface {
 		productID uint,
 	) (*models.BarbershopService, error)
 
+	// GetCombo retorna o combo com os itens em ordem, ou nil quando ele não
+	// existe na barbearia.
+	GetCombo(
+		ctx context.Context,
+		barbershopID uint,
+		comboID uint,
+	) (*models.ServiceCombo, error)
+
+	// GetBarberServiceOverride retorna o preço/duração próprios do barbeiro para
+	// o serviço (catálogo por barbeiro) ou nil quando ele usa os da barbearia.
+	GetBarberServiceOverride(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		serviceID uint,
+	) (*models.BarberServiceOverride, error)
+
+	// ==================================================
+	// BARBERS
+	// ==================================================
+
+	// ListActiveBarberIDs retorna os usuários ativos da barbearia que atendem
+	// (dono e barbeiros), em ordem de cadastro.
+	ListActiveBarberIDs(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]uint, error)
+
+	// GetLastAutoAssignedBarberID retorna o barbeiro do último agendamento
+	// atribuído automaticamente na barbearia (0 se nenhum). Base do round-robin.
+	GetLastAutoAssignedBarberID(
+		ctx context.Context,
+		barbershopID uint,
+	) (uint, error)
+
+	// GetPreferredBarberID retorna o barbeiro que mais concluiu atendimentos do
+	// cliente (empate: o mais recente). 0 se o cliente não tem histórico.
+	GetPreferredBarberID(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) (uint, error)
+
 	// ==================================================
 	// CLIENT
 	// ==================================================
@@ -44,9 +89,13 @@ type Repository interface {
 	// APPOINTMENT - CREATE
 	// ==================================================
 
+	// CreateAppointment cria o appointment; onCreated (opcional) roda na
+	// mesma transação, para que os eventos do agendamento (gravados no
+	// outbox com outbox.ContextWithTx) só existam se o INSERT confirmar.
 	CreateAppointment(
 		ctx context.Context,
 		ap *models.Appointment,
+		onCreated func(ctx context.Context, tx *gorm.DB) error,
 	) error
 
 	// CreateAppointmentWithKey creates the appointment and persists the
@@ -58,6 +107,20 @@ type Repository interface {
 		idempotencyKey string,
 	) error
 
+	// CreateAppointmentIfFree serializa as criações do mesmo barbeiro (lock
+	// transacional), revalida o conflito em [conflictStart, conflictEnd) e só
+	// então cria o appointment e a chave de idempotência. Retorna time_conflict
+	// se outro agendamento ocupou o horário nesse meio tempo. onCreated
+	// (opcional) roda na mesma transação, como em CreateAppointment.
+	CreateAppointmentIfFree(
+		ctx context.Context,
+		ap *models.Appointment,
+		conflictStart time.Time,
+		conflictEnd time.Time,
+		idempotencyKey string,
+		onCreated func(ctx context.Context, tx *gorm.DB) error,
+	) error
+
 	// ==================================================
 	// TIME CONFLICT
 	// ==================================================
@@ -97,6 +160,12 @@ type Repository interface {
 		ap *models.Appointment,
 	) error
 
+	// ListAppointmentServices retorna os serviços do agendamento em ordem.
+	ListAppointmentServices(
+		ctx context.Context,
+		appointmentID uint,
+	) ([]models.AppointmentService, error)
+
 	SaveAppointmentClosure(
 		ctx context.Context,
 		closure *models.AppointmentClosure,
@@ -131,6 +200,16 @@ type Repository interface {
 		year int,
 	) (*models.ScheduleOverride, error)
 
+	// ListBusyPeriods retorna os horários ocupados do calendário externo do
+	// barbeiro que se sobrepõem a [from, to).
+	ListBusyPeriods(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		from time.Time,
+		to time.Time,
+	) ([]BusyPeriod, error)
+
 	ListAppointmentsForDay(
 		ctx context.Context,
 		barbershopID uint,
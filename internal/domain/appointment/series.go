@@ -0,0 +1,69 @@
+package appointment
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// SeriesRepository persiste séries recorrentes e suas ocorrências.
+type SeriesRepository interface {
+	CreateSeries(
+		ctx context.Context,
+		series *models.AppointmentSeries,
+	) error
+
+	// GetSeries retorna nil quando a série não existe na barbearia.
+	GetSeries(
+		ctx context.Context,
+		barbershopID uint,
+		seriesID uint,
+	) (*models.AppointmentSeries, error)
+
+	UpdateSeries(
+		ctx context.Context,
+		series *models.AppointmentSeries,
+	) error
+
+	DeleteSeries(
+		ctx context.Context,
+		barbershopID uint,
+		seriesID uint,
+	) error
+
+	// ListSeriesAppointments retorna as ocorrências ativas (scheduled ou
+	// awaiting_payment) da série com start_time >= from, em ordem cronológica.
+	ListSeriesAppointments(
+		ctx context.Context,
+		barbershopID uint,
+		seriesID uint,
+		from time.Time,
+	) ([]*models.Appointment, error)
+
+	// ReassignSeriesAppointments move para toSeriesID as ocorrências ativas
+	// de fromSeriesID com start_time >= from (divisão da série).
+	ReassignSeriesAppointments(
+		ctx context.Context,
+		barbershopID uint,
+		fromSeriesID uint,
+		toSeriesID uint,
+		from time.Time,
+	) error
+
+	// MoveAppointmentIfFree grava horário, serviço e observação do appointment
+	// sob o mesmo lock do barbeiro usado na criação, revalidando o conflito em
+	// [conflictStart, conflictEnd) sem contar o próprio appointment.
+	// Services não vazio substitui as linhas de serviço do agendamento.
+	// Retorna time_conflict se o novo horário estiver ocupado. onMoved
+	// (opcional) roda na mesma transação, como em CreateAppointment.
+	MoveAppointmentIfFree(
+		ctx context.Context,
+		ap *models.Appointment,
+		conflictStart time.Time,
+		conflictEnd time.Time,
+		onMoved func(ctx context.Context, tx *gorm.DB) error,
+	) error
+}
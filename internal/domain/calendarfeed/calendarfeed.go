@@ -0,0 +1,72 @@
+package calendarfeed
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Event é um agendamento como aparece no feed .ics.
+type Event struct {
+	AppointmentID uint
+	BarberName    string
+	ClientName    string
+	ServiceName   string // serviços unidos por " + "
+	Notes         string
+	Status        models.AppointmentStatus
+	StartTime     time.Time
+	EndTime       time.Time
+	// Sequence sobe a cada mudança de horário ou status (trigger no banco).
+	Sequence  int
+	UpdatedAt time.Time
+}
+
+type Repository interface {
+	// GetByToken retorna nil quando o token não existe.
+	GetByToken(
+		ctx context.Context,
+		token string,
+	) (*models.CalendarFeed, error)
+
+	// Get retorna nil quando o feed ainda não foi criado.
+	// barberID nil = feed da barbearia inteira.
+	Get(
+		ctx context.Context,
+		barbershopID uint,
+		barberID *uint,
+	) (*models.CalendarFeed, error)
+
+	// CreateIfMissing não falha se outro request criou o mesmo feed antes.
+	CreateIfMissing(
+		ctx context.Context,
+		feed *models.CalendarFeed,
+	) error
+
+	UpdateToken(
+		ctx context.Context,
+		feedID uint,
+		token string,
+	) error
+
+	GetBarbershopByID(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+
+	// GetBarberName retorna "" quando o barbeiro não é da barbearia.
+	GetBarberName(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+	) (string, error)
+
+	// ListEvents lista os agendamentos (inclusive cancelados) que terminam a
+	// partir de from, em ordem de início. barberID nil = todos os barbeiros.
+	ListEvents(
+		ctx context.Context,
+		barbershopID uint,
+		barberID *uint,
+		from time.Time,
+	) ([]Event, error)
+}
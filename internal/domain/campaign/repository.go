@@ -0,0 +1,212 @@
+package campaign
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// SegmentClient é um cliente que casa com o segmento.
+type SegmentClient struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone,omitempty"`
+	Email    string `json:"email,omitempty"`
+	OptedOut bool   `json:"opted_out"`
+}
+
+// PendingRecipient é um destinatário ainda não enviado, com o nome usado
+// no placeholder {{client_name}} e a situação atual do cliente.
+type PendingRecipient struct {
+	models.CampaignRecipient
+	ClientName string
+	OptedOut   bool
+	Anonymized bool
+}
+
+// Stats resume a entrega da campanha e os agendamentos atribuídos a ela.
+type Stats struct {
+	Pending   int `json:"pending"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Cancelled int `json:"cancelled"`
+
+	Bookings     int   `json:"bookings"`
+	Completed    int   `json:"completed"`
+	RevenueCents int64 `json:"revenue_cents"`
+}
+
+type Repository interface {
+	ListSegments(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]models.ClientSegment, error)
+
+	// GetSegment retorna nil quando o segmento não existe na barbearia.
+	GetSegment(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (*models.ClientSegment, error)
+
+	// SaveSegment cria (ID zero) ou atualiza o segmento.
+	SaveSegment(
+		ctx context.Context,
+		s *models.ClientSegment,
+	) error
+
+	DeleteSegment(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) error
+
+	// SegmentInUse informa se alguma campanha ainda não encerrada usa o
+	// segmento.
+	SegmentInUse(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (bool, error)
+
+	// MatchClients lista os clientes que casam com os filtros, por nome,
+	// e o total. limit 0 = todos.
+	MatchClients(
+		ctx context.Context,
+		barbershopID uint,
+		f SegmentFilters,
+		limit int,
+		offset int,
+	) ([]SegmentClient, int64, error)
+
+	ListCampaigns(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]models.Campaign, error)
+
+	// GetCampaign retorna nil quando a campanha não existe na barbearia.
+	GetCampaign(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (*models.Campaign, error)
+
+	CreateCampaign(
+		ctx context.Context,
+		c *models.Campaign,
+	) error
+
+	// UpdateCampaign grava a campanha se ela ainda não começou (rascunho ou
+	// agendada); false quando já começou ou foi encerrada.
+	UpdateCampaign(
+		ctx context.Context,
+		c *models.Campaign,
+	) (bool, error)
+
+	// DeleteCampaign apaga um rascunho; false quando não é rascunho.
+	DeleteCampaign(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (bool, error)
+
+	// CancelCampaign encerra a campanha e cancela os destinatários ainda
+	// pendentes; false quando ela já estava encerrada.
+	CancelCampaign(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+		at time.Time,
+	) (bool, error)
+
+	// ListRecipients lista os destinatários, filtrando por status quando
+	// informado, e o total.
+	ListRecipients(
+		ctx context.Context,
+		barbershopID uint,
+		campaignID uint,
+		status string,
+		limit int,
+		offset int,
+	) ([]models.CampaignRecipient, int64, error)
+
+	CampaignStats(
+		ctx context.Context,
+		barbershopID uint,
+		campaignID uint,
+	) (*Stats, error)
+
+	// DueCampaigns lista as campanhas em envio e as agendadas até now, da
+	// mais antiga para a mais nova.
+	DueCampaigns(
+		ctx context.Context,
+		now time.Time,
+	) ([]models.Campaign, error)
+
+	// StartCampaign grava os destinatários e passa a campanha agendada para
+	// em envio, numa transação; false quando ela não está mais agendada.
+	StartCampaign(
+		ctx context.Context,
+		c *models.Campaign,
+		recipients []models.CampaignRecipient,
+		at time.Time,
+	) (bool, error)
+
+	PendingRecipients(
+		ctx context.Context,
+		campaignID uint,
+		limit int,
+	) ([]PendingRecipient, error)
+
+	// UpdateRecipient grava o resultado do envio.
+	UpdateRecipient(
+		ctx context.Context,
+		r *models.CampaignRecipient,
+	) error
+
+	// FinishCampaign marca como concluída a campanha em envio.
+	FinishCampaign(
+		ctx context.Context,
+		id uint,
+		at time.Time,
+	) error
+
+	// GetBarbershop retorna nil quando a barbearia não existe.
+	GetBarbershop(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+
+	// WhatsAppConnected informa se a instância da barbearia está conectada.
+	WhatsAppConnected(
+		ctx context.Context,
+		barbershopID uint,
+	) (bool, error)
+
+	// FindRecipientByToken retorna nil quando o token não existe.
+	FindRecipientByToken(
+		ctx context.Context,
+		token string,
+	) (*models.CampaignRecipient, error)
+
+	// SetClientOptOut marca (at preenchido) ou desfaz (nil) o pedido do
+	// cliente para não receber campanhas; false quando o cliente não existe
+	// na barbearia.
+	SetClientOptOut(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		at *time.Time,
+	) (bool, error)
+
+	// OptOutByPhone marca o pedido dos clientes com o número, tolerante à
+	// formatação do telefone cadastrado; false quando nenhum casa.
+	OptOutByPhone(
+		ctx context.Context,
+		barbershopID uint,
+		phone string,
+		at time.Time,
+	) (bool, error)
+}
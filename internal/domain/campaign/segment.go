@@ -0,0 +1,111 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+)
+
+const (
+	// maxFilterIDs limita as listas de categorias, serviços e produtos.
+	maxFilterIDs  = 50
+	maxFilterDays = 3650
+)
+
+var ErrInvalidSegmentFilter = errors.New("invalid_segment_filter")
+
+// SegmentFilters seleciona clientes da barbearia. Todos os filtros
+// preenchidos precisam casar; filtro nil ou vazio é ignorado, e sem filtro
+// nenhum o segmento é a base inteira. Clientes anonimizados nunca entram.
+type SegmentFilters struct {
+	// Categoria atual do cliente (new, regular, trusted, at_risk ou as das
+	// regras da barbearia).
+	Categories []metrics.ClientCategory `json:"categories,omitempty"`
+
+	// Dias desde o último atendimento concluído. Quem nunca concluiu um
+	// atendimento não casa com esses filtros.
+	MinDaysSinceLastVisit *int `json:"min_days_since_last_visit,omitempty"`
+	MaxDaysSinceLastVisit *int `json:"max_days_since_last_visit,omitempty"`
+
+	// Gasto acumulado em centavos.
+	MinTotalSpent *int64 `json:"min_total_spent,omitempty"`
+	MaxTotalSpent *int64 `json:"max_total_spent,omitempty"`
+
+	MinNoShows *int `json:"min_no_shows,omitempty"`
+	MaxNoShows *int `json:"max_no_shows,omitempty"`
+
+	// true: só assinantes ativos; false: só quem não tem assinatura ativa.
+	ActiveSubscription *bool `json:"active_subscription,omitempty"`
+
+	// Clientes com atendimento concluído de algum dos serviços, ou pedido
+	// pago com algum dos produtos.
+	ServiceIDs []uint `json:"service_ids,omitempty"`
+	ProductIDs []uint `json:"product_ids,omitempty"`
+}
+
+// Validate recusa filtros que nunca casariam ou fora dos limites; o erro
+// nomeia o campo.
+func (f SegmentFilters) Validate() error {
+	if len(f.Categories) > maxFilterIDs {
+		return invalidFilter("categories")
+	}
+	for _, c := range f.Categories {
+		if !metrics.IsValidCategoryName(c) {
+			return invalidFilter("categories")
+		}
+	}
+
+	for _, d := range []*int{f.MinDaysSinceLastVisit, f.MaxDaysSinceLastVisit} {
+		if d != nil && (*d < 0 || *d > maxFilterDays) {
+			return invalidFilter("days_since_last_visit")
+		}
+	}
+	if f.MinDaysSinceLastVisit != nil && f.MaxDaysSinceLastVisit != nil &&
+		*f.MinDaysSinceLastVisit > *f.MaxDaysSinceLastVisit {
+		return invalidFilter("days_since_last_visit")
+	}
+
+	for _, v := range []*int64{f.MinTotalSpent, f.MaxTotalSpent} {
+		if v != nil && *v < 0 {
+			return invalidFilter("total_spent")
+		}
+	}
+	if f.MinTotalSpent != nil && f.MaxTotalSpent != nil && *f.MinTotalSpent > *f.MaxTotalSpent {
+		return invalidFilter("total_spent")
+	}
+
+	for _, n := range []*int{f.MinNoShows, f.MaxNoShows} {
+		if n != nil && *n < 0 {
+			return invalidFilter("no_shows")
+		}
+	}
+	if f.MinNoShows != nil && f.MaxNoShows != nil && *f.MinNoShows > *f.MaxNoShows {
+		return invalidFilter("no_shows")
+	}
+
+	if !validIDs(f.ServiceIDs) {
+		return invalidFilter("service_ids")
+	}
+	if !validIDs(f.ProductIDs) {
+		return invalidFilter("product_ids")
+	}
+
+	return nil
+}
+
+func validIDs(ids []uint) bool {
+	if len(ids) > maxFilterIDs {
+		return false
+	}
+	for _, id := range ids {
+		if id == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func invalidFilter(field string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSegmentFilter, field)
+}
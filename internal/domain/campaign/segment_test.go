@@ -0,0 +1,56 @@
+package campaign
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestSegmentFiltersValidate(t *testing.T) {
+	ok := []struct {
+		name string
+		f    SegmentFilters
+	}{
+		{"sem filtros", SegmentFilters{}},
+		{"sumidos há 60 dias", SegmentFilters{MinDaysSinceLastVisit: ptr(60)}},
+		{"categoria das regras", SegmentFilters{Categories: []metrics.ClientCategory{"vip", metrics.CategoryAtRisk}}},
+		{"faixa de gasto", SegmentFilters{MinTotalSpent: ptr(int64(0)), MaxTotalSpent: ptr(int64(50000))}},
+		{"assinantes do serviço", SegmentFilters{ActiveSubscription: ptr(true), ServiceIDs: []uint{3}}},
+	}
+	for _, tc := range ok {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.f.Validate(); err != nil {
+				t.Errorf("esperado válido, obtido %v", err)
+			}
+		})
+	}
+
+	bad := []struct {
+		name  string
+		f     SegmentFilters
+		field string
+	}{
+		{"categoria inválida", SegmentFilters{Categories: []metrics.ClientCategory{"VIP!"}}, "categories"},
+		{"dias negativos", SegmentFilters{MaxDaysSinceLastVisit: ptr(-1)}, "days_since_last_visit"},
+		{"dias invertidos", SegmentFilters{MinDaysSinceLastVisit: ptr(90), MaxDaysSinceLastVisit: ptr(30)}, "days_since_last_visit"},
+		{"gasto invertido", SegmentFilters{MinTotalSpent: ptr(int64(100)), MaxTotalSpent: ptr(int64(10))}, "total_spent"},
+		{"faltas negativas", SegmentFilters{MinNoShows: ptr(-2)}, "no_shows"},
+		{"serviço zero", SegmentFilters{ServiceIDs: []uint{0}}, "service_ids"},
+		{"produtos demais", SegmentFilters{ProductIDs: make([]uint, maxFilterIDs+1)}, "product_ids"},
+	}
+	for _, tc := range bad {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.f.Validate()
+			if !errors.Is(err, ErrInvalidSegmentFilter) {
+				t.Fatalf("esperado ErrInvalidSegmentFilter, obtido %v", err)
+			}
+			if !strings.HasSuffix(err.Error(), tc.field) {
+				t.Errorf("esperado erro apontando %s, obtido %q", tc.field, err)
+			}
+		})
+	}
+}
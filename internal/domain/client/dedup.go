@@ -0,0 +1,159 @@
+package client
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Normalizações usadas para achar cadastros duplicados: o agendamento
+// público cria clientes pelo telefone e pelo email como foram digitados.
+
+// NormalizePhone devolve o telefone em E.164. Sem DDI, o número é tratado
+// como brasileiro (DDD + número, com ou sem o 0 da operadora); celular
+// antigo de 8 dígitos ganha o nono dígito. Vazio quando não é um telefone.
+func NormalizePhone(raw string) string {
+	s := strings.TrimSpace(raw)
+	intl := strings.HasPrefix(s, "+")
+
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		intl = true
+	}
+
+	if !intl {
+		digits = strings.TrimPrefix(digits, "0")
+		switch len(digits) {
+		case 10, 11:
+			digits = "55" + digits
+		case 12, 13:
+			if !strings.HasPrefix(digits, "55") {
+				return ""
+			}
+		default:
+			return ""
+		}
+	}
+
+	if strings.HasPrefix(digits, "55") && len(digits) == 12 && digits[4] >= '6' {
+		digits = digits[:4] + "9" + digits[4:]
+	}
+	if len(digits) < 8 || len(digits) > 15 {
+		return ""
+	}
+	return "+" + digits
+}
+
+// NormalizeEmail compara emails sem maiúsculas; no Gmail, pontos e o
+// sufixo +tag não mudam a caixa de entrada.
+func NormalizeEmail(raw string) string {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" {
+		return ""
+	}
+
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local, _, _ = strings.Cut(local, "+")
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// NormalizeName deixa o nome em minúsculas, sem acentos, pontuação nem
+// espaços repetidos.
+func NormalizeName(raw string) string {
+	s := accentFolder.Replace(strings.ToLower(raw))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsSpace(r) {
+			return r
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// nameSimilarity é o limite de similaridade (1 - distância de edição /
+// tamanho) a partir do qual dois nomes são considerados o mesmo.
+const nameSimilarity = 0.85
+
+// SimilarNames compara nomes já normalizados: quase iguais (erro de
+// digitação) ou um contido no outro a partir do primeiro nome ("joao
+// silva" e "joao pedro silva"). Nome de uma palavra só nunca casa por
+// contenção.
+func SimilarNames(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if 1-float64(levenshtein(a, b))/float64(longest) >= nameSimilarity {
+		return true
+	}
+
+	ta, tb := strings.Fields(a), strings.Fields(b)
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+	if len(ta) < 2 || ta[0] != tb[0] {
+		return false
+	}
+	rest := tb[1:]
+	for _, tok := range ta[1:] {
+		i := indexOf(rest, tok)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+1:]
+	}
+	return true
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
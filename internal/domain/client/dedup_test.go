@@ -0,0 +1,68 @@
+package client
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"(11) 99999-0000", "+5511999990000"},
+		{"11999990000", "+5511999990000"},
+		{"011 99999 0000", "+5511999990000"},
+		{"+55 11 99999-0000", "+5511999990000"},
+		{"5511999990000", "+5511999990000"},
+		{"0055 11 99999 0000", "+5511999990000"},
+		{"(11) 9999-0000", "+5511999990000"}, // celular antigo sem o nono dígito
+		{"(11) 3333-4444", "+551133334444"},  // fixo
+		{"+351 912 345 678", "+351912345678"},
+		{"99999-0000", ""},
+		{"", ""},
+		{"abc", ""},
+	}
+	for _, tc := range cases {
+		if got := NormalizePhone(tc.in); got != tc.want {
+			t.Errorf("NormalizePhone(%q) = %q, esperado %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{" Ana@Example.com ", "ana@example.com"},
+		{"ana.souza+barbearia@gmail.com", "anasouza@gmail.com"},
+		{"Ana.Souza@googlemail.com", "anasouza@gmail.com"},
+		{"ana+x@example.com", "ana+x@example.com"},
+		{"sem-arroba", ""},
+	}
+	for _, tc := range cases {
+		if got := NormalizeEmail(tc.in); got != tc.want {
+			t.Errorf("NormalizeEmail(%q) = %q, esperado %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestSimilarNames(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"João da Silva", "joao da silva", true},
+		{"Gabriel Souza", "Gabriel Sousa", true},
+		{"João Silva", "João Pedro Silva", true},
+		{"Ana Paula", "Ana Paula Ribeiro", true},
+		{"Ana", "Ana Paula", false},
+		{"João Silva", "Pedro Silva", false},
+		{"Maria Santos", "Mario Santos Filho", false},
+		{"Carlos", "Marcos", false},
+	}
+	for _, tc := range cases {
+		got := SimilarNames(NormalizeName(tc.a), NormalizeName(tc.b))
+		if got != tc.want {
+			t.Errorf("SimilarNames(%q, %q) = %v, esperado %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
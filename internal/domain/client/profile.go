@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites do perfil do cliente.
+const (
+	MaxNoteLength        = 2000
+	MaxTagNameLength     = 40
+	MaxAllergiesLength   = 500
+	MaxBeverageLength    = 100
+	MaxFavorites         = 20
+	MaxPhotosPerVisit    = 6
+	MaxTagsPerBarbershop = 100
+)
+
+var (
+	ErrInvalidNote        = errors.New("invalid_note")
+	ErrInvalidTag         = errors.New("invalid_tag")
+	ErrInvalidPreferences = errors.New("invalid_preferences")
+)
+
+var tagColor = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// NormalizeNote devolve o texto da anotação sem espaços nas pontas.
+func NormalizeNote(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxNoteLength {
+		return "", ErrInvalidNote
+	}
+	return body, nil
+}
+
+// NormalizeTag valida o nome (espaços repetidos viram um) e a cor
+// (#rrggbb, minúsculas, ou vazia).
+func NormalizeTag(name, color string) (string, string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", "", fmt.Errorf("%w: name", ErrInvalidTag)
+	}
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color != "" && !tagColor.MatchString(color) {
+		return "", "", fmt.Errorf("%w: color", ErrInvalidTag)
+	}
+	return name, color, nil
+}
+
+// Preferences são as preferências estruturadas do cliente.
+type Preferences struct {
+	PreferredBarberID  *uint  `json:"preferred_barber_id"`
+	Allergies          string `json:"allergies"`
+	FavoriteServiceIDs []uint `json:"favorite_service_ids"`
+	FavoriteProductIDs []uint `json:"favorite_product_ids"`
+	Beverage           string `json:"beverage"`
+}
+
+// Normalize apara os textos, remove ids repetidos e valida os limites. Os
+// ids ainda precisam existir na barbearia (ProfileRepository).
+func (p *Preferences) Normalize() error {
+	p.Allergies = strings.TrimSpace(p.Allergies)
+	p.Beverage = strings.TrimSpace(p.Beverage)
+	if utf8.RuneCountInString(p.Allergies) > MaxAllergiesLength {
+		return fmt.Errorf("%w: allergies", ErrInvalidPreferences)
+	}
+	if utf8.RuneCountInString(p.Beverage) > MaxBeverageLength {
+		return fmt.Errorf("%w: beverage", ErrInvalidPreferences)
+	}
+	if p.PreferredBarberID != nil && *p.PreferredBarberID == 0 {
+		p.PreferredBarberID = nil
+	}
+
+	var err error
+	if p.FavoriteServiceIDs, err = uniqueIDs(p.FavoriteServiceIDs, "favorite_service_ids"); err != nil {
+		return err
+	}
+	if p.FavoriteProductIDs, err = uniqueIDs(p.FavoriteProductIDs, "favorite_product_ids"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uint, field string) ([]uint, error) {
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPreferences, field)
+		}
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	if len(out) > MaxFavorites {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPreferences, field)
+	}
+	return out, nil
+}
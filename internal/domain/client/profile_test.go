@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	name, color, err := NormalizeTag("  Barba   longa ", " #A1B2C3 ")
+	if err != nil || name != "Barba longa" || color != "#a1b2c3" {
+		t.Fatalf("obtido %q %q %v", name, color, err)
+	}
+	if _, _, err := NormalizeTag("VIP", ""); err != nil {
+		t.Errorf("cor vazia deveria valer: %v", err)
+	}
+
+	for _, tc := range []struct{ name, color string }{
+		{"", ""},
+		{strings.Repeat("a", MaxTagNameLength+1), ""},
+		{"VIP", "red"},
+		{"VIP", "#12345"},
+	} {
+		if _, _, err := NormalizeTag(tc.name, tc.color); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("NormalizeTag(%q, %q) deveria falhar, obtido %v", tc.name, tc.color, err)
+		}
+	}
+}
+
+func TestNormalizeNote(t *testing.T) {
+	if body, err := NormalizeNote("  máquina 2 nas laterais \n"); err != nil || body != "máquina 2 nas laterais" {
+		t.Errorf("obtido %q %v", body, err)
+	}
+	if _, err := NormalizeNote("   "); !errors.Is(err, ErrInvalidNote) {
+		t.Errorf("anotação vazia deveria falhar")
+	}
+	if _, err := NormalizeNote(strings.Repeat("á", MaxNoteLength+1)); !errors.Is(err, ErrInvalidNote) {
+		t.Errorf("anotação longa demais deveria falhar")
+	}
+}
+
+func TestPreferencesNormalize(t *testing.T) {
+	zero := uint(0)
+	p := Preferences{
+		PreferredBarberID:  &zero,
+		Allergies:          "  minoxidil ",
+		FavoriteServiceIDs: []uint{3, 1, 3},
+		FavoriteProductIDs: nil,
+		Beverage:           " café sem açúcar",
+	}
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if p.PreferredBarberID != nil || p.Allergies != "minoxidil" || p.Beverage != "café sem açúcar" {
+		t.Errorf("normalização inesperada: %+v", p)
+	}
+	if !slices.Equal(p.FavoriteServiceIDs, []uint{3, 1}) || p.FavoriteProductIDs == nil {
+		t.Errorf("ids deveriam ser únicos e nunca nil: %+v", p)
+	}
+
+	bad := []Preferences{
+		{Allergies: strings.Repeat("a", MaxAllergiesLength+1)},
+		{Beverage: strings.Repeat("a", MaxBeverageLength+1)},
+		{FavoriteProductIDs: []uint{0}},
+		{FavoriteServiceIDs: make([]uint, MaxFavorites+1)},
+	}
+	for i := range bad {
+		for j := range bad[i].FavoriteServiceIDs {
+			bad[i].FavoriteServiceIDs[j] = uint(j + 1)
+		}
+		if err := bad[i].Normalize(); !errors.Is(err, ErrInvalidPreferences) {
+			t.Errorf("caso %d deveria falhar, obtido %v", i, err)
+		}
+	}
+}
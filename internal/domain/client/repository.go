@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// NoteView é a anotação com o nome de quem escreveu.
+type NoteView struct {
+	models.ClientNote
+	AuthorName string `json:"author_name"`
+}
+
+// TagView é a etiqueta com quantos clientes ativos a usam.
+type TagView struct {
+	models.ClientTag
+	Clients int `json:"clients"`
+}
+
+// ProfileRepository guarda o perfil do cliente: anotações, etiquetas,
+// preferências e fotos.
+type ProfileRepository interface {
+	// ClientExists informa se o cliente está ativo na barbearia (nem
+	// anonimizado nem juntado a outro).
+	ClientExists(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) (bool, error)
+
+	// ListNotes lista as fixadas primeiro e depois as mais recentes.
+	ListNotes(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		limit int,
+	) ([]NoteView, error)
+
+	// GetNote retorna nil quando a anotação não é do cliente.
+	GetNote(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		id uint,
+	) (*models.ClientNote, error)
+
+	// SaveNote cria (ID zero) ou atualiza a anotação.
+	SaveNote(ctx context.Context, n *models.ClientNote) error
+
+	DeleteNote(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) error
+
+	ListTags(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]TagView, error)
+
+	// GetTag retorna nil quando a etiqueta não existe na barbearia.
+	GetTag(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (*models.ClientTag, error)
+
+	// TagNameTaken informa se outra etiqueta da barbearia já usa o nome,
+	// sem diferenciar maiúsculas.
+	TagNameTaken(
+		ctx context.Context,
+		barbershopID uint,
+		name string,
+		exceptID uint,
+	) (bool, error)
+
+	CountTags(
+		ctx context.Context,
+		barbershopID uint,
+	) (int64, error)
+
+	// SaveTag cria (ID zero) ou atualiza a etiqueta.
+	SaveTag(ctx context.Context, t *models.ClientTag) error
+
+	// DeleteTag apaga a etiqueta e tira ela dos clientes.
+	DeleteTag(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) error
+
+	ClientTags(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) ([]models.ClientTag, error)
+
+	// SetClientTags troca as etiquetas do cliente pelas de tagIDs. Retorna
+	// false, sem alterar nada, se alguma não é da barbearia.
+	SetClientTags(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		tagIDs []uint,
+	) (bool, error)
+
+	// GetPreferences retorna nil quando o cliente não tem preferências.
+	GetPreferences(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) (*Preferences, error)
+
+	SavePreferences(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		userID uint,
+		p Preferences,
+	) error
+
+	// PreferenceRefsValid confere se o barbeiro, os serviços e os produtos
+	// das preferências são da barbearia.
+	PreferenceRefsValid(
+		ctx context.Context,
+		barbershopID uint,
+		p Preferences,
+	) (bool, error)
+
+	// ListPhotos lista as fotos mais recentes primeiro. appointmentID zero
+	// lista as de todos os atendimentos.
+	ListPhotos(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		appointmentID uint,
+		limit int,
+	) ([]models.ClientPhoto, error)
+
+	// AppointmentOfClient informa se o agendamento é do cliente.
+	AppointmentOfClient(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		appointmentID uint,
+	) (bool, error)
+
+	CountAppointmentPhotos(
+		ctx context.Context,
+		barbershopID uint,
+		appointmentID uint,
+	) (int64, error)
+
+	// GetPhoto retorna nil quando a foto não é do cliente.
+	GetPhoto(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		id uint,
+	) (*models.ClientPhoto, error)
+
+	SavePhoto(ctx context.Context, p *models.ClientPhoto) error
+
+	DeletePhoto(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) error
+}
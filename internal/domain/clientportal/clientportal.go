@@ -0,0 +1,25 @@
+package clientportal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// HashSecret é o hash guardado no lugar do código de acesso e do token de
+// sessão: o banco nunca tem o segredo em claro.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizePhone remove os espaços, como o cadastro público grava o
+// telefone.
+func NormalizePhone(phone string) string {
+	return strings.Join(strings.Fields(phone), "")
+}
+
+// NormalizeEmail compara emails sem diferenciar maiúsculas.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
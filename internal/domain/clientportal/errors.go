@@ -0,0 +1,14 @@
+package clientportal
+
+import "github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+
+// Erros do login sem senha. Código errado, vencido ou de cliente
+// inexistente respondem igual, para não revelar quem tem cadastro.
+var (
+	ErrLoginTargetRequired = apperr.ErrBusiness("login_target_required")
+	ErrChannelUnavailable  = apperr.ErrBusiness("login_channel_unavailable")
+	ErrInvalidCode         = apperr.ErrBusiness("login_code_invalid")
+	ErrTooManyAttempts     = apperr.ErrBusiness("login_code_attempts_exceeded")
+	ErrPhoneTaken          = apperr.ErrBusiness("client_phone_taken")
+	ErrSessionInvalid      = apperr.ErrBusiness("client_session_invalid")
+)
@@ -0,0 +1,94 @@
+package clientportal
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Repository persiste os códigos de acesso e as sessões do portal do
+// cliente.
+type Repository interface {
+	// FindClientByPhone e FindClientByEmail retornam nil quando não há
+	// cliente (não anonimizado) com o contato na barbearia.
+	FindClientByPhone(
+		ctx context.Context,
+		barbershopID uint,
+		phone string,
+	) (*models.Client, error)
+
+	FindClientByEmail(
+		ctx context.Context,
+		barbershopID uint,
+		email string,
+	) (*models.Client, error)
+
+	// GetClient retorna nil quando o cliente não existe na barbearia.
+	GetClient(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) (*models.Client, error)
+
+	// UpdateClientContact grava nome, telefone e email. Telefone de outro
+	// cliente da barbearia retorna ErrPhoneTaken.
+	UpdateClientContact(
+		ctx context.Context,
+		c *models.Client,
+	) error
+
+	// CreateCode grava o código e invalida os anteriores ainda não usados
+	// do cliente: só o último vale.
+	CreateCode(
+		ctx context.Context,
+		c *models.ClientLoginCode,
+	) error
+
+	// LatestCode retorna o último código do cliente ainda não usado; nil
+	// quando não há.
+	LatestCode(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) (*models.ClientLoginCode, error)
+
+	IncrementAttempts(
+		ctx context.Context,
+		codeID uint,
+	) error
+
+	// UseCode marca o código como usado. false quando outra requisição o
+	// usou antes.
+	UseCode(
+		ctx context.Context,
+		codeID uint,
+		now time.Time,
+	) (bool, error)
+
+	CreateSession(
+		ctx context.Context,
+		s *models.ClientSession,
+	) error
+
+	// FindSession busca a sessão ativa (não revogada nem vencida em now)
+	// pelo hash do token, na barbearia do slug. nil quando não há.
+	FindSession(
+		ctx context.Context,
+		slug string,
+		tokenHash string,
+		now time.Time,
+	) (*models.ClientSession, error)
+
+	TouchSession(
+		ctx context.Context,
+		sessionID uint,
+		now time.Time,
+	) error
+
+	RevokeSession(
+		ctx context.Context,
+		sessionID uint,
+		now time.Time,
+	) error
+}
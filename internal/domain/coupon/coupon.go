@@ -0,0 +1,107 @@
+package coupon
+
+import (
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Line é um item cobrável da compra: serviço, produto ou plano, já com o
+// valor que o cliente pagaria sem cupom.
+type Line struct {
+	ItemID      uint
+	AmountCents int64
+}
+
+// Purchase descreve onde o cupom está sendo usado. Kind é o escopo dos
+// itens (models.CouponAppliesToServices, Products ou Plans).
+type Purchase struct {
+	Kind  string
+	Lines []Line
+}
+
+func (p Purchase) Total() int64 {
+	var total int64
+	for _, l := range p.Lines {
+		total += l.AmountCents
+	}
+	return total
+}
+
+// CheckAvailable valida o cupom em si: ativo, dentro da janela e com usos
+// sobrando. O limite total é revalidado no resgate, sob trava.
+func CheckAvailable(c *models.Coupon, now time.Time) error {
+	if !c.Active {
+		return ErrInactive
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return ErrNotStarted
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return ErrExpired
+	}
+	if c.MaxUses != nil && c.UsesCount >= *c.MaxUses {
+		return ErrExhausted
+	}
+	return nil
+}
+
+// NeedsClient: limites por cliente e primeira visita só valem para um
+// cliente identificado.
+func NeedsClient(c *models.Coupon) bool {
+	return c.FirstVisitOnly || c.MaxUsesPerClient != nil
+}
+
+// PerClientLimit é o limite de usos por cliente; cupom de primeira visita
+// vale uma vez por cliente. nil = sem limite.
+func PerClientLimit(c *models.Coupon) *int {
+	if c.MaxUsesPerClient != nil {
+		return c.MaxUsesPerClient
+	}
+	if c.FirstVisitOnly {
+		one := 1
+		return &one
+	}
+	return nil
+}
+
+// Discount calcula o desconto do cupom na compra. O valor mínimo vale sobre
+// o total; o desconto, só sobre as linhas do escopo do cupom.
+func Discount(c *models.Coupon, p Purchase) (int64, error) {
+	if c.AppliesTo != models.CouponAppliesToAll && c.AppliesTo != p.Kind {
+		return 0, ErrNotApplicable
+	}
+
+	targets := make(map[uint]bool, len(c.TargetIDs))
+	for _, id := range c.TargetIDs {
+		targets[id] = true
+	}
+
+	var eligible int64
+	for _, l := range p.Lines {
+		// Alvos só restringem cupons de um escopo específico.
+		if c.AppliesTo != models.CouponAppliesToAll && len(targets) > 0 && !targets[l.ItemID] {
+			continue
+		}
+		eligible += l.AmountCents
+	}
+	if eligible <= 0 {
+		return 0, ErrNotApplicable
+	}
+
+	if p.Total() < c.MinOrderCents {
+		return 0, ErrMinOrder
+	}
+
+	var discount int64
+	switch c.DiscountType {
+	case models.CouponDiscountPercent:
+		discount = eligible * c.DiscountValue / 100
+	default:
+		discount = c.DiscountValue
+	}
+	if discount > eligible {
+		discount = eligible
+	}
+	return discount, nil
+}
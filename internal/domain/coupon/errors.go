@@ -0,0 +1,20 @@
+package coupon
+
+import "github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+
+// Erros de aplicação do cupom: o código vira a resposta do checkout.
+var (
+	ErrNotFound       = apperr.ErrBusiness("coupon_not_found")
+	ErrInactive       = apperr.ErrBusiness("coupon_inactive")
+	ErrNotStarted     = apperr.ErrBusiness("coupon_not_started")
+	ErrExpired        = apperr.ErrBusiness("coupon_expired")
+	ErrExhausted      = apperr.ErrBusiness("coupon_exhausted")
+	ErrClientLimit    = apperr.ErrBusiness("coupon_client_limit")
+	ErrFirstVisitOnly = apperr.ErrBusiness("coupon_first_visit_only")
+	ErrMinOrder       = apperr.ErrBusiness("coupon_min_order")
+	ErrNotApplicable  = apperr.ErrBusiness("coupon_not_applicable")
+	ErrRequiresClient = apperr.ErrBusiness("coupon_requires_client")
+)
+
+// ErrCodeTaken: já existe cupom com o mesmo código na barbearia.
+var ErrCodeTaken = apperr.ErrBusiness("coupon_code_taken")
@@ -0,0 +1,58 @@
+package coupon
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Repository persiste cupons e seus resgates.
+type Repository interface {
+	// CountTargets conta quantos dos IDs existem na barbearia, na tabela do
+	// escopo (serviços, produtos ou planos).
+	CountTargets(
+		ctx context.Context,
+		barbershopID uint,
+		appliesTo string,
+		ids []uint,
+	) (int64, error)
+
+	// Create grava o cupom e seus alvos (c.TargetIDs). Código repetido na
+	// barbearia retorna ErrCodeTaken.
+	Create(ctx context.Context, c *models.Coupon) error
+
+	// Update substitui os dados e os alvos do cupom; uses_count não muda.
+	Update(ctx context.Context, c *models.Coupon) error
+
+	// Get retorna nil quando o cupom não existe na barbearia.
+	Get(ctx context.Context, barbershopID uint, couponID uint) (*models.Coupon, error)
+
+	// GetByCode busca o cupom sem diferenciar maiúsculas; nil quando não existe.
+	GetByCode(ctx context.Context, barbershopID uint, code string) (*models.Coupon, error)
+
+	List(ctx context.Context, barbershopID uint) ([]models.Coupon, error)
+
+	// HasCompletedVisit informa se o cliente já foi atendido na barbearia.
+	HasCompletedVisit(ctx context.Context, barbershopID uint, clientID uint) (bool, error)
+
+	// Redeem registra o resgate: incrementa uses_count só se ainda houver
+	// uso (ErrExhausted) e confere o limite por cliente com o cupom travado
+	// (ErrClientLimit). perClientLimit nil = sem limite.
+	Redeem(ctx context.Context, r *models.CouponRedemption, perClientLimit *int) error
+
+	// AttachAppointment vincula um resgate reservado ao agendamento criado.
+	AttachAppointment(ctx context.Context, redemptionID uint, appointmentID uint, discountCents int64) error
+
+	// Release desfaz um resgate que não chegou a ser usado e devolve o uso.
+	Release(ctx context.Context, redemptionID uint) error
+
+	// VoidForAppointment anula os usos do agendamento cancelado ou expirado
+	// e os devolve ao cupom. Usos já anulados são ignorados.
+	VoidForAppointment(ctx context.Context, appointmentID uint) error
+
+	// VoidForOrder anula os usos do pedido cancelado, como VoidForAppointment.
+	VoidForOrder(ctx context.Context, orderID uint) error
+
+	// ListRedemptions lista os usos do cupom, dos mais recentes aos mais antigos.
+	ListRedemptions(ctx context.Context, barbershopID uint, couponID uint) ([]models.CouponRedemption, error)
+}
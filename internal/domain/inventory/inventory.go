@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Tipos de movimento do razão de estoque.
+const (
+	MovementSale         = "sale"          // pedido pago (loja online, carrinho, pagamento do agendamento)
+	MovementClosureAddon = "closure_addon" // venda adicional no fechamento do atendimento
+	MovementAdjustment   = "adjustment"    // ajuste manual ou contagem física
+	MovementPurchase     = "purchase"      // entrada de compra do fornecedor
+	MovementLoss         = "loss"          // perda, quebra, vencimento
+	MovementReturn       = "return"        // devolução de cliente
+)
+
+// CountLine é a quantidade contada de um produto na contagem física.
+type CountLine struct {
+	ProductID uint
+	Counted   int
+}
+
+// LowStockProduct é um produto no limite de estoque baixo ainda não avisado.
+type LowStockProduct struct {
+	ProductID    uint
+	BarbershopID uint
+	Name         string
+	Stock        int
+	Threshold    int
+}
+
+// Repository persiste o razão de estoque. Todo lançamento atualiza
+// products.stock na mesma transação; um lote é aplicado por inteiro ou nada.
+type Repository interface {
+	// Record aplica os movimentos (Quantity assinada) e preenche ID e
+	// StockAfter. product.ErrInsufficientStock quando o saldo ficaria
+	// negativo; product.ErrProductNotFound quando o produto não é da barbearia.
+	Record(
+		ctx context.Context,
+		movements []*models.InventoryMovement,
+	) error
+
+	// Count ajusta o estoque para as quantidades contadas, gravando um
+	// movimento adjustment para cada diferença. Produtos sem diferença não
+	// geram movimento.
+	Count(
+		ctx context.Context,
+		barbershopID uint,
+		lines []CountLine,
+		userID *uint,
+		note string,
+	) ([]models.InventoryMovement, error)
+
+	// ListByProduct lista os movimentos do mais recente para o mais antigo;
+	// beforeID > 0 pagina a partir do movimento anterior a ele.
+	ListByProduct(
+		ctx context.Context,
+		barbershopID uint,
+		productID uint,
+		beforeID uint,
+		limit int,
+	) ([]models.InventoryMovement, error)
+
+	// ListLowStockPending lista produtos ativos com estoque no limite ou
+	// abaixo dele e cujo aviso ainda não foi enviado.
+	ListLowStockPending(
+		ctx context.Context,
+		limit int,
+	) ([]LowStockProduct, error)
+
+	// MarkLowStockNotified marca os produtos como avisados.
+	MarkLowStockNotified(
+		ctx context.Context,
+		productIDs []uint,
+		at time.Time,
+	) error
+
+	// GetAlertRecipients retorna o nome da barbearia e os e-mails dos donos ativos.
+	GetAlertRecipients(
+		ctx context.Context,
+		barbershopID uint,
+	) (string, []string, error)
+}
@@ -0,0 +1,13 @@
+package loyalty
+
+import "github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+
+// Erros da troca de recompensa no fechamento.
+var (
+	ErrProgramInactive     = apperr.ErrBusiness("loyalty_program_inactive")
+	ErrRewardNotFound      = apperr.ErrBusiness("loyalty_reward_not_found")
+	ErrRewardInactive      = apperr.ErrBusiness("loyalty_reward_inactive")
+	ErrRewardNotApplicable = apperr.ErrBusiness("loyalty_reward_not_applicable")
+	ErrInsufficientPoints  = apperr.ErrBusiness("loyalty_insufficient_points")
+	ErrRequiresClient      = apperr.ErrBusiness("loyalty_requires_client")
+)
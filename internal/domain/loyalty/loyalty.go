@@ -0,0 +1,90 @@
+package loyalty
+
+import (
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// PointsFor converte o valor gasto em pontos: PointsPerReal por real
+// inteiro (R$45,90 a 1 ponto/real = 45 pontos).
+func PointsFor(p *models.LoyaltyProgram, amountCents int64) int {
+	if p == nil || amountCents <= 0 {
+		return 0
+	}
+	return int(amountCents / 100 * int64(p.PointsPerReal))
+}
+
+// ExpiresAt é a validade de um lote creditado em now. nil = não expira.
+func ExpiresAt(p *models.LoyaltyProgram, now time.Time) *time.Time {
+	if p.ExpiryMonths <= 0 {
+		return nil
+	}
+	t := now.AddDate(0, p.ExpiryMonths, 0)
+	return &t
+}
+
+// ReversalFor calcula quantos pontos reverter quando o valor que gerou o
+// crédito cai para newAmountCents. Os pontos mantidos são proporcionais ao
+// novo valor; reversed é o que já foi revertido antes. Aumentos não geram
+// pontos novos.
+func ReversalFor(earn *models.LoyaltyEntry, reversed int, newAmountCents int64) int {
+	if earn == nil || earn.AmountCents <= 0 {
+		return 0
+	}
+	if newAmountCents < 0 {
+		newAmountCents = 0
+	}
+
+	keep := earn.Points
+	if newAmountCents < earn.AmountCents {
+		keep = int(int64(earn.Points) * newAmountCents / earn.AmountCents)
+	}
+
+	toReverse := earn.Points - reversed - keep
+	if toReverse < 0 {
+		return 0
+	}
+	return toReverse
+}
+
+// RewardLine é um serviço do atendimento que a recompensa pode cobrir.
+type RewardLine struct {
+	ServiceID   uint
+	AmountCents int64
+}
+
+// RewardDiscount calcula o desconto da recompensa sobre o que ainda seria
+// cobrado (chargeCents). free_service cobre a linha do serviço da
+// recompensa — ou a primeira, quando ela vale para qualquer serviço.
+func RewardDiscount(r *models.LoyaltyReward, lines []RewardLine, chargeCents int64) (int64, error) {
+	if chargeCents <= 0 {
+		return 0, ErrRewardNotApplicable
+	}
+
+	var discount int64
+	switch r.Kind {
+	case models.LoyaltyRewardFreeService:
+		found := false
+		for _, l := range lines {
+			if r.ServiceID == nil || *r.ServiceID == l.ServiceID {
+				discount = l.AmountCents
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, ErrRewardNotApplicable
+		}
+	default:
+		discount = r.DiscountCents
+	}
+
+	if discount > chargeCents {
+		discount = chargeCents
+	}
+	if discount <= 0 {
+		return 0, ErrRewardNotApplicable
+	}
+	return discount, nil
+}
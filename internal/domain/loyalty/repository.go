@@ -0,0 +1,112 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Account é o saldo de pontos do cliente: Balance soma os lotes ainda
+// válidos; ExpiringPoints é a parte que vence até o horizonte consultado.
+type Account struct {
+	Balance        int
+	ExpiringPoints int
+	NextExpiresAt  *time.Time
+}
+
+// Repository persiste o programa, o catálogo de recompensas e o extrato.
+type Repository interface {
+	// GetProgram retorna nil quando a barbearia nunca configurou o programa.
+	GetProgram(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.LoyaltyProgram, error)
+
+	SaveProgram(
+		ctx context.Context,
+		p *models.LoyaltyProgram,
+	) error
+
+	ServiceExists(
+		ctx context.Context,
+		barbershopID uint,
+		serviceID uint,
+	) (bool, error)
+
+	CreateReward(
+		ctx context.Context,
+		r *models.LoyaltyReward,
+	) error
+
+	UpdateReward(
+		ctx context.Context,
+		r *models.LoyaltyReward,
+	) error
+
+	// GetReward retorna nil quando a recompensa não existe na barbearia.
+	GetReward(
+		ctx context.Context,
+		barbershopID uint,
+		rewardID uint,
+	) (*models.LoyaltyReward, error)
+
+	ListRewards(
+		ctx context.Context,
+		barbershopID uint,
+		onlyActive bool,
+	) ([]models.LoyaltyReward, error)
+
+	// Earn grava o crédito (lote). No-op quando o atendimento ou o pedido
+	// já pontuou; nesse caso e.ID fica zero.
+	Earn(
+		ctx context.Context,
+		e *models.LoyaltyEntry,
+	) error
+
+	// FindEarn devolve o crédito do atendimento (appointmentID) ou do pedido
+	// (orderID) e quantos pontos já foram revertidos dele. nil quando não
+	// pontuou.
+	FindEarn(
+		ctx context.Context,
+		appointmentID *uint,
+		orderID *uint,
+	) (*models.LoyaltyEntry, int, error)
+
+	// Debit consome -e.Points dos lotes válidos em now, dos que vencem
+	// primeiro (fromLotID, quando informado, é consumido antes), e grava o
+	// débito. Sem saldo suficiente, falha com ErrInsufficientPoints — ou,
+	// com partial, debita o que houver (e.Points passa a refletir o
+	// debitado; zero = nada gravado).
+	Debit(
+		ctx context.Context,
+		e *models.LoyaltyEntry,
+		now time.Time,
+		fromLotID *uint,
+		partial bool,
+	) error
+
+	// GetAccount calcula o saldo em now e o que vence até horizon.
+	GetAccount(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		now time.Time,
+		horizon time.Time,
+	) (*Account, error)
+
+	// ListEntries lista o extrato do cliente, do mais recente ao mais antigo.
+	ListEntries(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		limit int,
+	) ([]models.LoyaltyEntry, error)
+
+	// ExpireLots zera os lotes vencidos em now, gravando um débito expire
+	// para cada um. Retorna quantos lotes expiraram.
+	ExpireLots(
+		ctx context.Context,
+		now time.Time,
+	) (int64, error)
+}
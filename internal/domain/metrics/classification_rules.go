@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// MaxClassificationRules limita o tamanho da lista de regras.
+	MaxClassificationRules = 20
+
+	maxRuleDays = 3650
+)
+
+var categoryNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,29}$`)
+
+var (
+	ErrTooManyRules          = errors.New("too_many_classification_rules")
+	ErrInvalidRuleCategory   = errors.New("invalid_rule_category")
+	ErrReservedRuleCategory  = errors.New("reserved_rule_category")
+	ErrRuleWithoutConditions = errors.New("rule_without_conditions")
+	ErrInvalidRuleThreshold  = errors.New("invalid_rule_threshold")
+)
+
+// RuleError aponta qual regra (1-based) da lista falhou na validação.
+type RuleError struct {
+	Position int
+	Err      error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("regra %d: %s", e.Position, e.Err)
+}
+
+func (e *RuleError) Unwrap() error { return e.Err }
+
+// ClassificationRule atribui Category ao cliente quando todas as condições
+// preenchidas casam; condição nil é ignorada.
+type ClassificationRule struct {
+	Category ClientCategory `json:"category"`
+
+	// Contagens e taxas sobre o histórico do cliente. A taxa de cancelamento
+	// soma os cancelamentos tardios aos comuns.
+	MinCompleted     *int     `json:"min_completed,omitempty"`
+	MinNoShowRate    *float64 `json:"min_no_show_rate,omitempty"`
+	MinCancelRate    *float64 `json:"min_cancel_rate,omitempty"`
+	MaxNoShows       *int     `json:"max_no_shows,omitempty"`
+	MaxCancellations *int     `json:"max_cancellations,omitempty"`
+
+	// Gasto acumulado em centavos.
+	MinTotalSpent *int64 `json:"min_total_spent,omitempty"`
+
+	// Dias desde o último atendimento concluído: InactiveDays casa acima do
+	// limite, ActiveWithinDays até ele. Ambos exigem um atendimento concluído.
+	InactiveDays     *int `json:"inactive_days,omitempty"`
+	ActiveWithinDays *int `json:"active_within_days,omitempty"`
+}
+
+// ClassificationRules é a lista ordenada de regras: a primeira que casa
+// define a categoria.
+type ClassificationRules []ClassificationRule
+
+// Classify aplica as regras em now. Sem histórico o cliente é new; sem
+// regra que case, regular.
+func (rules ClassificationRules) Classify(m *ClientMetrics, now time.Time) ClientCategory {
+	if m.TotalAppointments == 0 {
+		return CategoryNew
+	}
+
+	for _, r := range rules {
+		if r.matches(m, now) {
+			return r.Category
+		}
+	}
+
+	return CategoryRegular
+}
+
+func (r ClassificationRule) matches(m *ClientMetrics, now time.Time) bool {
+	total := float64(m.TotalAppointments)
+	cancels := m.CancelledAppointments + m.LateCancelledAppointments
+
+	if r.MinCompleted != nil && m.CompletedAppointments < *r.MinCompleted {
+		return false
+	}
+	if r.MinNoShowRate != nil && float64(m.NoShowAppointments)/total < *r.MinNoShowRate {
+		return false
+	}
+	if r.MinCancelRate != nil && float64(cancels)/total < *r.MinCancelRate {
+		return false
+	}
+	if r.MaxNoShows != nil && m.NoShowAppointments > *r.MaxNoShows {
+		return false
+	}
+	if r.MaxCancellations != nil && cancels > *r.MaxCancellations {
+		return false
+	}
+	if r.MinTotalSpent != nil && m.TotalSpent < *r.MinTotalSpent {
+		return false
+	}
+
+	if r.InactiveDays != nil || r.ActiveWithinDays != nil {
+		if m.LastCompletedAt == nil {
+			return false
+		}
+		since := now.Sub(*m.LastCompletedAt)
+		if r.InactiveDays != nil && since <= days(*r.InactiveDays) {
+			return false
+		}
+		if r.ActiveWithinDays != nil && since > days(*r.ActiveWithinDays) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate checa cada regra; o erro é um *RuleError com a posição.
+func (rules ClassificationRules) Validate() error {
+	if len(rules) > MaxClassificationRules {
+		return ErrTooManyRules
+	}
+
+	for i, r := range rules {
+		if err := r.validate(); err != nil {
+			return &RuleError{Position: i + 1, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (r ClassificationRule) validate() error {
+	if !IsValidCategoryName(r.Category) {
+		return ErrInvalidRuleCategory
+	}
+	// new é reservado aos clientes sem histórico.
+	if r.Category == CategoryNew {
+		return ErrReservedRuleCategory
+	}
+
+	if r.MinCompleted == nil && r.MinNoShowRate == nil && r.MinCancelRate == nil &&
+		r.MaxNoShows == nil && r.MaxCancellations == nil && r.MinTotalSpent == nil &&
+		r.InactiveDays == nil && r.ActiveWithinDays == nil {
+		return ErrRuleWithoutConditions
+	}
+
+	for _, n := range []*int{r.MinCompleted, r.MaxNoShows, r.MaxCancellations} {
+		if n != nil && *n < 0 {
+			return ErrInvalidRuleThreshold
+		}
+	}
+	for _, rate := range []*float64{r.MinNoShowRate, r.MinCancelRate} {
+		if rate != nil && (*rate <= 0 || *rate > 1) {
+			return ErrInvalidRuleThreshold
+		}
+	}
+	if r.MinTotalSpent != nil && *r.MinTotalSpent < 0 {
+		return ErrInvalidRuleThreshold
+	}
+	for _, d := range []*int{r.InactiveDays, r.ActiveWithinDays} {
+		if d != nil && (*d <= 0 || *d > maxRuleDays) {
+			return ErrInvalidRuleThreshold
+		}
+	}
+	// Inativo há mais de N dias e ativo nos últimos M ≤ N nunca casa.
+	if r.InactiveDays != nil && r.ActiveWithinDays != nil && *r.ActiveWithinDays <= *r.InactiveDays {
+		return ErrInvalidRuleThreshold
+	}
+
+	return nil
+}
+
+// Categories lista as categorias que as regras podem atribuir, além de new
+// e regular, sem repetição e na ordem das regras.
+func (rules ClassificationRules) Categories() []ClientCategory {
+	out := []ClientCategory{CategoryNew, CategoryRegular}
+	seen := map[ClientCategory]bool{CategoryNew: true, CategoryRegular: true}
+
+	for _, r := range rules {
+		if !seen[r.Category] {
+			seen[r.Category] = true
+			out = append(out, r.Category)
+		}
+	}
+
+	return out
+}
+
+// IsValidCategoryName aceita nomes em snake_case de 2 a 30 caracteres.
+func IsValidCategoryName(c ClientCategory) bool {
+	return categoryNamePattern.MatchString(string(c))
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
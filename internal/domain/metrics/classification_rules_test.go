@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func daysAgo(now time.Time, n int) *time.Time {
+	t := now.AddDate(0, 0, -n)
+	return &t
+}
+
+func TestDefaultClassificationRules(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	rules := DefaultClassificationRules()
+
+	cases := []struct {
+		name string
+		m    ClientMetrics
+		want ClientCategory
+	}{
+		{"sem histórico", ClientMetrics{}, CategoryNew},
+		{"no-show 20%", ClientMetrics{TotalAppointments: 5, CompletedAppointments: 4, NoShowAppointments: 1, LastCompletedAt: daysAgo(now, 1)}, CategoryAtRisk},
+		{"cancelamento tardio conta na taxa", ClientMetrics{TotalAppointments: 5, CompletedAppointments: 3, CancelledAppointments: 1, LateCancelledAppointments: 1, LastCompletedAt: daysAgo(now, 1)}, CategoryAtRisk},
+		{"inativo há 91 dias", ClientMetrics{TotalAppointments: 2, CompletedAppointments: 2, LastCompletedAt: daysAgo(now, 91)}, CategoryAtRisk},
+		{"fiel", ClientMetrics{TotalAppointments: 5, CompletedAppointments: 5, LastCompletedAt: daysAgo(now, 10)}, CategoryTrusted},
+		{"fiel sem visita recente", ClientMetrics{TotalAppointments: 5, CompletedAppointments: 5, LastCompletedAt: daysAgo(now, 40)}, CategoryRegular},
+		{"fiel com um cancelamento", ClientMetrics{TotalAppointments: 6, CompletedAppointments: 5, CancelledAppointments: 1, LastCompletedAt: daysAgo(now, 10)}, CategoryRegular},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rules.Classify(&tc.m, now); got != tc.want {
+				t.Errorf("esperado %s, obtido %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCustomClassificationRules(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	rules := ClassificationRules{
+		{Category: "dormant", InactiveDays: ptr(180)},
+		{Category: "vip", MinTotalSpent: ptr(int64(100000)), ActiveWithinDays: ptr(60)},
+		{Category: CategoryAtRisk, MinNoShowRate: ptr(0.5)},
+	}
+
+	vip := &ClientMetrics{TotalAppointments: 10, CompletedAppointments: 8, NoShowAppointments: 2, TotalSpent: 150000, LastCompletedAt: daysAgo(now, 5)}
+	if got := rules.Classify(vip, now); got != "vip" {
+		t.Errorf("esperado vip, obtido %s", got)
+	}
+
+	// A ordem decide: sumido há 200 dias é dormant antes de ser vip.
+	vip.LastCompletedAt = daysAgo(now, 200)
+	if got := rules.Classify(vip, now); got != "dormant" {
+		t.Errorf("esperado dormant, obtido %s", got)
+	}
+
+	// Nenhuma regra casa → regular.
+	casual := &ClientMetrics{TotalAppointments: 3, CompletedAppointments: 3, TotalSpent: 9000, LastCompletedAt: daysAgo(now, 5)}
+	if got := rules.Classify(casual, now); got != CategoryRegular {
+		t.Errorf("esperado regular, obtido %s", got)
+	}
+
+	want := []ClientCategory{CategoryNew, CategoryRegular, "dormant", "vip", CategoryAtRisk}
+	got := rules.Categories()
+	if len(got) != len(want) {
+		t.Fatalf("categorias: esperado %v, obtido %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("categorias: esperado %v, obtido %v", want, got)
+		}
+	}
+}
+
+func TestValidateClassificationRules(t *testing.T) {
+	cases := []struct {
+		name string
+		rule ClassificationRule
+		want error
+	}{
+		{"válida", ClassificationRule{Category: "vip", MinTotalSpent: ptr(int64(50000))}, nil},
+		{"categoria com maiúscula", ClassificationRule{Category: "VIP", MinCompleted: ptr(1)}, ErrInvalidRuleCategory},
+		{"categoria new", ClassificationRule{Category: CategoryNew, MinCompleted: ptr(1)}, ErrReservedRuleCategory},
+		{"sem condição", ClassificationRule{Category: "vip"}, ErrRuleWithoutConditions},
+		{"taxa acima de 1", ClassificationRule{Category: CategoryAtRisk, MinNoShowRate: ptr(1.5)}, ErrInvalidRuleThreshold},
+		{"taxa zero", ClassificationRule{Category: CategoryAtRisk, MinCancelRate: ptr(0.0)}, ErrInvalidRuleThreshold},
+		{"contagem negativa", ClassificationRule{Category: "vip", MaxNoShows: ptr(-1)}, ErrInvalidRuleThreshold},
+		{"dias zero", ClassificationRule{Category: "dormant", InactiveDays: ptr(0)}, ErrInvalidRuleThreshold},
+		{"janela impossível", ClassificationRule{Category: "dormant", InactiveDays: ptr(90), ActiveWithinDays: ptr(30)}, ErrInvalidRuleThreshold},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ClassificationRules{{Category: "vip", MinCompleted: ptr(10)}, tc.rule}.Validate()
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("esperado %v, obtido %v", tc.want, err)
+			}
+			var ruleErr *RuleError
+			if err != nil && (!errors.As(err, &ruleErr) || ruleErr.Position != 2) {
+				t.Errorf("esperado erro na regra 2, obtido %v", err)
+			}
+		})
+	}
+
+	if err := DefaultClassificationRules().Validate(); err != nil {
+		t.Errorf("regras padrão deveriam ser válidas: %v", err)
+	}
+
+	tooMany := make(ClassificationRules, MaxClassificationRules+1)
+	for i := range tooMany {
+		tooMany[i] = ClassificationRule{Category: "vip", MinCompleted: ptr(i)}
+	}
+	if err := tooMany.Validate(); !errors.Is(err, ErrTooManyRules) {
+		t.Errorf("esperado %v, obtido %v", ErrTooManyRules, err)
+	}
+}
@@ -64,23 +64,17 @@ func (m *ClientMetrics) OnAppointmentCompleted(at time.Time, amount int64) {
 	m.TotalSpent += amount
 	m.LastCompletedAt = &at
 	m.LastAppointmentAt = &at
-
-	m.recalculateCategory()
 }
 
 func (m *ClientMetrics) OnAppointmentCanceled(at time.Time) {
 	m.CancelledAppointments++
 	m.LastCanceledAt = &at
-
-	m.recalculateCategory()
 }
 
 func (m *ClientMetrics) OnAppointmentNoShow(at time.Time) {
 	m.NoShowAppointments++
 	m.LastNoShowAt = &at
 	m.LastAppointmentAt = &at
-
-	m.recalculateCategory()
 }
 
 func (m *ClientMetrics) OnAppointmentRescheduled(at time.Time, late bool) {
@@ -91,15 +85,11 @@ func (m *ClientMetrics) OnAppointmentRescheduled(at time.Time, late bool) {
 		m.LateRescheduledAppointments++
 		m.LastLateRescheduledAt = &at
 	}
-
-	m.recalculateCategory()
 }
 
 func (m *ClientMetrics) OnLateCancellation(at time.Time) {
 	m.LateCancelledAppointments++
 	m.LastLateCanceledAt = &at
-
-	m.recalculateCategory()
 }
 
 func (m *ClientMetrics) SetManualCategory(category ClientCategory, expiresAt *time.Time) {
@@ -108,10 +98,12 @@ func (m *ClientMetrics) SetManualCategory(category ClientCategory, expiresAt *ti
 	m.ManualCategoryExpiresAt = expiresAt
 }
 
-func (m *ClientMetrics) recalculateCategory() {
+// RecalculateCategory reclassifica o cliente com as regras da barbearia.
+// O override manual é mantido até expirar; expirado, volta ao automático.
+func (m *ClientMetrics) RecalculateCategory(rules ClassificationRules, now time.Time) {
 	if m.CategorySource == CategorySourceManual {
 		// If the manual override has an expiration and it has passed, revert to auto.
-		if m.ManualCategoryExpiresAt != nil && time.Now().UTC().After(*m.ManualCategoryExpiresAt) {
+		if m.ManualCategoryExpiresAt != nil && now.After(*m.ManualCategoryExpiresAt) {
 			m.CategorySource = CategorySourceAuto
 			m.ManualCategoryExpiresAt = nil
 		} else {
@@ -119,6 +111,6 @@ func (m *ClientMetrics) recalculateCategory() {
 		}
 	}
 
-	m.Category = Classify(m)
+	m.Category = rules.Classify(m, now)
 	m.CategorySource = CategorySourceAuto
 }
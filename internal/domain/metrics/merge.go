@@ -0,0 +1,101 @@
+package metrics
+
+import "time"
+
+// MergeClientMetrics junta as métricas de dois cadastros da mesma pessoa.
+// Os contadores são de eventos distintos e se somam; as datas ficam com a
+// primeira e a última de cada tipo. A categoria é a do cadastro mantido,
+// salvo quando só o outro tinha override manual: a decisão do dono vale.
+// A categoria deve ser recalculada depois (RecalculateCategory).
+func MergeClientMetrics(target, source *ClientMetrics) *ClientMetrics {
+	m := *target
+
+	m.TotalAppointments += source.TotalAppointments
+	m.CompletedAppointments += source.CompletedAppointments
+	m.CancelledAppointments += source.CancelledAppointments
+	m.NoShowAppointments += source.NoShowAppointments
+	m.RescheduledAppointments += source.RescheduledAppointments
+	m.LateCancelledAppointments += source.LateCancelledAppointments
+	m.LateRescheduledAppointments += source.LateRescheduledAppointments
+	m.TotalSpent += source.TotalSpent
+
+	m.FirstAppointmentAt = earliest(target.FirstAppointmentAt, source.FirstAppointmentAt)
+	m.LastAppointmentAt = latest(target.LastAppointmentAt, source.LastAppointmentAt)
+	m.LastCompletedAt = latest(target.LastCompletedAt, source.LastCompletedAt)
+	m.LastCanceledAt = latest(target.LastCanceledAt, source.LastCanceledAt)
+	m.LastNoShowAt = latest(target.LastNoShowAt, source.LastNoShowAt)
+	m.LastLateCanceledAt = latest(target.LastLateCanceledAt, source.LastLateCanceledAt)
+	m.LastLateRescheduledAt = latest(target.LastLateRescheduledAt, source.LastLateRescheduledAt)
+
+	if target.CategorySource != CategorySourceManual && source.CategorySource == CategorySourceManual {
+		m.Category = source.Category
+		m.CategorySource = CategorySourceManual
+		m.ManualCategoryExpiresAt = source.ManualCategoryExpiresAt
+	}
+
+	return &m
+}
+
+// UnmergeClientMetrics desfaz a junção no cadastro mantido: current são
+// as métricas de agora, before as dele antes da junção e source as do
+// cadastro que volta. Os contadores de source saem; cada data volta à de
+// before se ainda é a que veio de source (um evento depois da junção
+// prevalece). O override manual herdado de source também sai.
+func UnmergeClientMetrics(current, before, source *ClientMetrics) *ClientMetrics {
+	m := *current
+
+	m.TotalAppointments = max(0, m.TotalAppointments-source.TotalAppointments)
+	m.CompletedAppointments = max(0, m.CompletedAppointments-source.CompletedAppointments)
+	m.CancelledAppointments = max(0, m.CancelledAppointments-source.CancelledAppointments)
+	m.NoShowAppointments = max(0, m.NoShowAppointments-source.NoShowAppointments)
+	m.RescheduledAppointments = max(0, m.RescheduledAppointments-source.RescheduledAppointments)
+	m.LateCancelledAppointments = max(0, m.LateCancelledAppointments-source.LateCancelledAppointments)
+	m.LateRescheduledAppointments = max(0, m.LateRescheduledAppointments-source.LateRescheduledAppointments)
+	m.TotalSpent = max(0, m.TotalSpent-source.TotalSpent)
+
+	m.FirstAppointmentAt = revert(current.FirstAppointmentAt, before.FirstAppointmentAt, source.FirstAppointmentAt)
+	m.LastAppointmentAt = revert(current.LastAppointmentAt, before.LastAppointmentAt, source.LastAppointmentAt)
+	m.LastCompletedAt = revert(current.LastCompletedAt, before.LastCompletedAt, source.LastCompletedAt)
+	m.LastCanceledAt = revert(current.LastCanceledAt, before.LastCanceledAt, source.LastCanceledAt)
+	m.LastNoShowAt = revert(current.LastNoShowAt, before.LastNoShowAt, source.LastNoShowAt)
+	m.LastLateCanceledAt = revert(current.LastLateCanceledAt, before.LastLateCanceledAt, source.LastLateCanceledAt)
+	m.LastLateRescheduledAt = revert(current.LastLateRescheduledAt, before.LastLateRescheduledAt, source.LastLateRescheduledAt)
+
+	if before.CategorySource != CategorySourceManual && source.CategorySource == CategorySourceManual &&
+		m.CategorySource == CategorySourceManual && m.Category == source.Category &&
+		sameTime(m.ManualCategoryExpiresAt, source.ManualCategoryExpiresAt) {
+		m.Category = before.Category
+		m.CategorySource = before.CategorySource
+		m.ManualCategoryExpiresAt = before.ManualCategoryExpiresAt
+	}
+
+	return &m
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+func revert(current, before, source *time.Time) *time.Time {
+	if source != nil && sameTime(current, source) && !sameTime(before, source) {
+		return before
+	}
+	return current
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeAndUnmergeClientMetrics(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	target := &ClientMetrics{
+		ClientID: 1, TotalAppointments: 3, CompletedAppointments: 3, TotalSpent: 15000,
+		FirstAppointmentAt: daysAgo(now, 40), LastAppointmentAt: daysAgo(now, 10), LastCompletedAt: daysAgo(now, 10),
+		Category: CategoryRegular, CategorySource: CategorySourceAuto,
+	}
+	source := &ClientMetrics{
+		ClientID: 2, TotalAppointments: 3, CompletedAppointments: 2, NoShowAppointments: 1, TotalSpent: 8000,
+		FirstAppointmentAt: daysAgo(now, 200), LastAppointmentAt: daysAgo(now, 5), LastNoShowAt: daysAgo(now, 5),
+		LastCompletedAt: daysAgo(now, 60),
+		Category: "vip", CategorySource: CategorySourceManual,
+	}
+
+	merged := MergeClientMetrics(target, source)
+
+	if merged.ClientID != 1 || merged.TotalAppointments != 6 || merged.CompletedAppointments != 5 ||
+		merged.NoShowAppointments != 1 || merged.TotalSpent != 23000 {
+		t.Fatalf("contadores somados incorretamente: %+v", merged)
+	}
+	if !merged.FirstAppointmentAt.Equal(*source.FirstAppointmentAt) ||
+		!merged.LastAppointmentAt.Equal(*source.LastAppointmentAt) ||
+		!merged.LastCompletedAt.Equal(*target.LastCompletedAt) {
+		t.Errorf("datas deveriam ser a primeira e a última de cada tipo")
+	}
+	if merged.Category != "vip" || merged.CategorySource != CategorySourceManual {
+		t.Errorf("override manual do cadastro juntado deveria valer, obtido %s/%s", merged.Category, merged.CategorySource)
+	}
+
+	// depois da junção, um atendimento novo no cadastro mantido
+	current := *merged
+	current.OnAppointmentCreated(now)
+	current.OnAppointmentCompleted(now, 5000)
+
+	back := UnmergeClientMetrics(&current, target, source)
+
+	if back.TotalAppointments != 4 || back.CompletedAppointments != 4 || back.NoShowAppointments != 0 || back.TotalSpent != 20000 {
+		t.Errorf("contadores do cadastro que volta deveriam sair: %+v", back)
+	}
+	if !back.FirstAppointmentAt.Equal(*target.FirstAppointmentAt) {
+		t.Errorf("primeira data deveria voltar à do cadastro mantido")
+	}
+	if !back.LastAppointmentAt.Equal(now) || !back.LastCompletedAt.Equal(now) {
+		t.Errorf("evento depois da junção deveria prevalecer")
+	}
+	if back.LastNoShowAt != nil {
+		t.Errorf("falta do cadastro que volta deveria sair, obtido %v", back.LastNoShowAt)
+	}
+	if back.Category != CategoryRegular || back.CategorySource != CategorySourceAuto {
+		t.Errorf("override herdado deveria sair, obtido %s/%s", back.Category, back.CategorySource)
+	}
+}
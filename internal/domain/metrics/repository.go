@@ -1,6 +1,9 @@
 package metrics
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type ClientMetricsRepository interface {
 	GetOrCreate(
@@ -16,3 +19,44 @@ type ClientMetricsRepository interface {
 
 	Save(ctx context.Context, m *ClientMetrics) error
 }
+
+// RulesConfig é a configuração de classificação salva pela barbearia.
+type RulesConfig struct {
+	BarbershopID uint
+	// Rules nil = regras padrão (DefaultClassificationRules).
+	Rules             ClassificationRules
+	ReclassifyPending bool
+	ReclassifiedAt    *time.Time
+	UpdatedAt         time.Time
+}
+
+type ClassificationRulesRepository interface {
+	// GetRules retorna nil quando a barbearia nunca configurou as regras.
+	GetRules(
+		ctx context.Context,
+		barbershopID uint,
+	) (*RulesConfig, error)
+
+	// SaveRules grava as regras (nil = volta ao padrão) e marca a
+	// barbearia para reclassificação.
+	SaveRules(
+		ctx context.Context,
+		barbershopID uint,
+		rules ClassificationRules,
+	) error
+
+	// PendingReclassification lista as barbearias com regras alteradas
+	// desde a última reclassificação.
+	PendingReclassification(ctx context.Context) ([]RulesConfig, error)
+
+	// MarkReclassified limpa a marca, desde que as regras não tenham mudado
+	// depois de cfg.UpdatedAt.
+	MarkReclassified(
+		ctx context.Context,
+		cfg RulesConfig,
+		at time.Time,
+	) error
+
+	// UpdateCategory grava só a categoria do cliente, sem tocar nos contadores.
+	UpdateCategory(ctx context.Context, m *ClientMetrics) error
+}
@@ -12,43 +12,171 @@ type AppointmentNotifier interface {
 }
 
 type AppointmentConfirmedInput struct {
-	BarbershopID    uint   // necessário para o WhatsApp notifier identificar a instância
-	ClientName      string
-	ClientEmail     string
-	ClientPhone     string // usado pelo WhatsApp notifier
-	BarbershopName  string
-	BarbershopPhone string
-	BarbershopSlug  string // para link público no WhatsApp
-	ServiceName     string
-	StartTime       time.Time
-	EndTime         time.Time
-	Timezone        string
-	TicketURL       string
+	BarbershopID      uint // necessário para o WhatsApp notifier identificar a instância
+	AppointmentID     uint // referência no histórico de entregas do outbox
+	ClientName        string
+	ClientEmail       string
+	ClientPhone       string // usado pelo WhatsApp notifier
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopSlug    string // para link público no WhatsApp
+	BarbershopAddress string
+	ServiceName       string
+	StartTime         time.Time
+	EndTime           time.Time
+	Timezone          string
+	TicketURL         string
+	Paid              bool // confirmação após pagamento aprovado (template payment_confirmed)
 }
 
 type AppointmentCancelledInput struct {
-	BarbershopID uint   // necessário para o WhatsApp notifier identificar a instância
-	ClientName   string
-	ClientEmail  string
-	ClientPhone  string
-	BarbershopName string
-	BarbershopSlug string
-	StartTime    time.Time
-	Timezone     string
+	BarbershopID      uint // necessário para o WhatsApp notifier identificar a instância
+	AppointmentID     uint // referência no histórico de entregas do outbox
+	ClientName        string
+	ClientEmail       string
+	ClientPhone       string
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopSlug    string
+	BarbershopAddress string
+	ServiceName       string
+	StartTime         time.Time
+	Timezone          string
 }
 
 type AppointmentRescheduledInput struct {
-	BarbershopID    uint   // necessário para o WhatsApp notifier identificar a instância
-	ClientName      string
-	ClientEmail     string
-	ClientPhone     string
-	BarbershopName  string
-	BarbershopPhone string
-	BarbershopSlug  string
-	ServiceName     string
-	OldStartTime    time.Time
-	NewStartTime    time.Time
-	NewEndTime      time.Time
-	Timezone        string
-	NewTicketURL    string
+	BarbershopID      uint // necessário para o WhatsApp notifier identificar a instância
+	AppointmentID     uint // referência no histórico de entregas do outbox
+	ClientName        string
+	ClientEmail       string
+	ClientPhone       string
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopSlug    string
+	BarbershopAddress string
+	ServiceName       string
+	OldStartTime      time.Time
+	NewStartTime      time.Time
+	NewEndTime        time.Time
+	Timezone          string
+	NewTicketURL      string
+}
+
+// ReminderNotifier envia o lembrete de um agendamento próximo.
+// Implementado por cada canal (email, WhatsApp) — o AppointmentReminderJob
+// registra o envio por canal para nunca repetir o mesmo lembrete.
+type ReminderNotifier interface {
+	NotifyReminder(ctx context.Context, input AppointmentReminderInput) error
+}
+
+type AppointmentReminderInput struct {
+	BarbershopID      uint
+	ClientName        string
+	ClientEmail       string
+	ClientPhone       string
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopAddress string
+	ServiceName       string
+	StartTime         time.Time
+	EndTime           time.Time
+	Timezone          string
+	TicketURL         string
+}
+
+// WaitlistOfferNotifier avisa um cliente da lista de espera que um horário
+// compatível foi liberado. A oferta vale até ExpiresAt; ClaimURL confirma.
+type WaitlistOfferNotifier interface {
+	NotifyWaitlistOffer(ctx context.Context, input WaitlistOfferInput) error
+}
+
+type WaitlistOfferInput struct {
+	BarbershopID   uint
+	ClientName     string
+	ClientEmail    string
+	ClientPhone    string
+	BarbershopName string
+	ServiceName    string
+	StartTime      time.Time
+	EndTime        time.Time
+	ExpiresAt      time.Time
+	Timezone       string
+	ClaimURL       string
+}
+
+// SubscriptionRenewalNotifier avisa o cliente do resultado da cobrança de
+// renovação automática da assinatura.
+type SubscriptionRenewalNotifier interface {
+	NotifySubscriptionRenewal(ctx context.Context, input SubscriptionRenewalInput) error
+}
+
+// SubscriptionRenewalInput: com Failed, ValidUntil é quando a assinatura
+// expira sem pagamento e NextAttemptAt a próxima tentativa (nil = nenhuma);
+// sem Failed, ValidUntil é o fim do período renovado.
+type SubscriptionRenewalInput struct {
+	BarbershopID   uint
+	ClientName     string
+	ClientEmail    string
+	ClientPhone    string
+	BarbershopName string
+	PlanName       string
+	AmountCents    int64
+	CardLastFour   string
+	Failed         bool
+	ValidUntil     time.Time
+	NextAttemptAt  *time.Time
+	Timezone       string
+}
+
+// LowStockNotifier avisa o dono dos produtos que chegaram ao limite de
+// estoque baixo.
+type LowStockNotifier interface {
+	NotifyLowStock(ctx context.Context, input LowStockInput) error
+}
+
+type LowStockInput struct {
+	BarbershopID   uint
+	BarbershopName string
+	OwnerEmail     string
+	Products       []LowStockItem
+}
+
+type LowStockItem struct {
+	Name      string
+	Stock     int
+	Threshold int
+}
+
+// LoginCodeNotifier entrega ao cliente o código de acesso ao portal.
+// Cada canal usa o seu contato: email em ClientEmail, WhatsApp em
+// ClientPhone.
+type LoginCodeNotifier interface {
+	NotifyLoginCode(ctx context.Context, input LoginCodeInput) error
+}
+
+type LoginCodeInput struct {
+	BarbershopID     uint
+	BarbershopName   string
+	ClientName       string
+	ClientEmail      string
+	ClientPhone      string
+	Code             string
+	ExpiresInMinutes int
+}
+
+// CampaignNotifier entrega a mensagem de uma campanha. Body (e Subject, no
+// email) já vêm com os placeholders trocados; cada canal acrescenta como
+// deixar de receber.
+type CampaignNotifier interface {
+	NotifyCampaign(ctx context.Context, input CampaignMessageInput) error
+}
+
+type CampaignMessageInput struct {
+	BarbershopID   uint
+	BarbershopName string
+	ClientEmail    string
+	ClientPhone    string
+	Subject        string
+	Body           string
+	UnsubscribeURL string // link de descadastro do email
 }
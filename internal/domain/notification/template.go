@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// TemplateRepository guarda os textos personalizados de cada barbearia.
+type TemplateRepository interface {
+	// Find retorna nil quando a barbearia usa o texto padrão.
+	Find(
+		ctx context.Context,
+		barbershopID uint,
+		kind string,
+		channel string,
+	) (*models.NotificationTemplate, error)
+
+	List(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]models.NotificationTemplate, error)
+
+	Save(
+		ctx context.Context,
+		t *models.NotificationTemplate,
+	) error
+
+	// Delete volta a mensagem ao texto padrão.
+	Delete(
+		ctx context.Context,
+		barbershopID uint,
+		kind string,
+		channel string,
+	) error
+
+	// GetBarbershop retorna nil quando a barbearia não existe (dados reais
+	// da prévia).
+	GetBarbershop(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+}
@@ -12,4 +12,8 @@ var (
 	ErrInvalidTotal    = errors.New("invalid total amount")
 
 	ErrInvalidStatus = errors.New("invalid status transition")
+
+	ErrNothingToReturn       = errors.New("nothing to return")
+	ErrProductNotInOrder     = errors.New("product not in order")
+	ErrReturnExceedsQuantity = errors.New("return exceeds remaining quantity")
 )
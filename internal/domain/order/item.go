@@ -10,4 +10,7 @@ type OrderItem struct {
 	Quantity  int
 	UnitPrice int64
 	LineTotal int64
+
+	// ReturnedQuantity: unidades já devolvidas.
+	ReturnedQuantity int
 }
@@ -28,6 +28,9 @@ type Order struct {
 	DiscountAmount int64
 	TotalAmount    int64
 
+	// CouponID: cupom que gerou o DiscountAmount (nil = sem cupom).
+	CouponID *uint
+
 	Items []OrderItem
 
 	CreatedAt time.Time
@@ -78,6 +81,19 @@ func (o *Order) AddItem(
 	return nil
 }
 
+// ApplyDiscount aplica o desconto de um cupom sobre o subtotal. O pedido
+// precisa continuar com valor a pagar.
+func (o *Order) ApplyDiscount(couponID uint, amount int64) error {
+	if amount < 0 || amount >= o.SubtotalAmount {
+		return ErrInvalidDiscount
+	}
+
+	o.CouponID = &couponID
+	o.DiscountAmount = amount
+	o.recalculateTotals()
+	return nil
+}
+
 func (o *Order) recalculateTotals() {
 	var subtotal int64
 
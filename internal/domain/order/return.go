@@ -0,0 +1,101 @@
+package order
+
+// ReturnLine é a parte de uma linha do pedido que volta para o estoque.
+type ReturnLine struct {
+	OrderItemID uint
+	ProductID   uint
+	Quantity    int
+	AmountCents int64
+}
+
+// RemainingQuantity é o que ainda pode ser devolvido da linha.
+func (i OrderItem) RemainingQuantity() int {
+	return i.Quantity - i.ReturnedQuantity
+}
+
+// FullyReturned indica que todas as unidades do pedido já voltaram.
+func (o *Order) FullyReturned() bool {
+	for _, it := range o.Items {
+		if it.RemainingQuantity() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanCancellation devolve tudo o que ainda não foi devolvido.
+func (o *Order) PlanCancellation() []ReturnLine {
+	lines := make([]ReturnLine, 0, len(o.Items))
+	for _, it := range o.Items {
+		if qty := it.RemainingQuantity(); qty > 0 {
+			lines = append(lines, returnLine(it, qty))
+		}
+	}
+	return lines
+}
+
+// PlanReturn distribui as quantidades pedidas por produto entre as linhas do
+// pedido, na ordem em que foram lançadas.
+func (o *Order) PlanReturn(requested map[uint]int) ([]ReturnLine, error) {
+	if len(requested) == 0 {
+		return nil, ErrNothingToReturn
+	}
+
+	lines := make([]ReturnLine, 0, len(requested))
+	for productID, qty := range requested {
+		if qty <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+
+		found := false
+		for _, it := range o.Items {
+			if it.ProductID != productID {
+				continue
+			}
+			found = true
+
+			take := min(qty, it.RemainingQuantity())
+			if take <= 0 {
+				continue
+			}
+			lines = append(lines, returnLine(it, take))
+			qty -= take
+			if qty == 0 {
+				break
+			}
+		}
+
+		if !found {
+			return nil, ErrProductNotInOrder
+		}
+		if qty > 0 {
+			return nil, ErrReturnExceedsQuantity
+		}
+	}
+
+	return lines, nil
+}
+
+// RefundAmount é quanto devolver ao cliente pelas linhas: o valor dos itens,
+// limitado ao que o pedido ainda tem a devolver (total pago menos o já
+// devolvido). O limite absorve descontos do pedido.
+func (o *Order) RefundAmount(lines []ReturnLine, alreadyReturned int64) int64 {
+	var amount int64
+	for _, l := range lines {
+		amount += l.AmountCents
+	}
+
+	if remaining := o.TotalAmount - alreadyReturned; amount > remaining {
+		amount = max(remaining, 0)
+	}
+	return amount
+}
+
+func returnLine(it OrderItem, qty int) ReturnLine {
+	return ReturnLine{
+		OrderItemID: it.ID,
+		ProductID:   it.ProductID,
+		Quantity:    qty,
+		AmountCents: int64(qty) * it.UnitPrice,
+	}
+}
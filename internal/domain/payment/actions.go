@@ -32,3 +32,54 @@ func Expire(p *models.Payment, now time.Time) error {
 
 	return nil
 }
+
+// ApplyRefund registra a devolução de amount centavos no pagamento.
+// Devolver o saldo restante leva a refunded; qualquer valor menor, a partially_refunded.
+func ApplyRefund(p *models.Payment, amount int64, now time.Time) error {
+
+	current := Status(p.Status)
+
+	if !current.IsRefundable() {
+		return ErrPaymentNotRefundable()
+	}
+
+	remaining := p.Amount - p.RefundedAmount
+	if amount <= 0 || amount > remaining {
+		return ErrInvalidRefundAmount()
+	}
+
+	target := StatusPartiallyRefunded
+	if amount == remaining {
+		target = StatusRefunded
+	}
+
+	if err := current.MustTransitionTo(target); err != nil {
+		return err
+	}
+
+	p.Status = models.PaymentStatus(target)
+	p.RefundedAmount += amount
+	p.RefundedAt = &now
+
+	return nil
+}
+
+// MarkChargedBack registra a contestação do pagamento no cartão.
+// O provider devolve ao cliente todo o saldo ainda não estornado —
+// retorna esse valor para o registro da perda.
+func MarkChargedBack(p *models.Payment, now time.Time) (int64, error) {
+
+	current := Status(p.Status)
+
+	if err := current.MustTransitionTo(StatusChargedBack); err != nil {
+		return 0, err
+	}
+
+	amount := p.Amount - p.RefundedAmount
+
+	p.Status = models.PaymentStatus(StatusChargedBack)
+	p.RefundedAmount = p.Amount
+	p.RefundedAt = &now
+
+	return amount, nil
+}
@@ -25,3 +25,11 @@ func ErrInvalidPaymentTransition(from Status, to Status) error {
 func ErrInvalidTarget() error {
 	return apperr.ErrBusiness("invalid_payment_target")
 }
+
+func ErrPaymentNotRefundable() error {
+	return apperr.ErrBusiness("payment_not_refundable")
+}
+
+func ErrInvalidRefundAmount() error {
+	return apperr.ErrBusiness("invalid_refund_amount")
+}
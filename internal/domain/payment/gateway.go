@@ -15,6 +15,7 @@ type PaymentGateway interface {
 	CreateCardPayment(ctx context.Context, input CardPaymentInput) (*CardPaymentResult, error)
 	CreateHostedCheckout(ctx context.Context, input HostedCheckoutInput) (*HostedCheckoutResult, error)
 	GetPaymentStatus(ctx context.Context, providerPaymentID string) (ProviderPaymentStatus, error)
+	RefundPayment(ctx context.Context, input RefundInput) (*RefundResult, error)
 }
 
 // ProviderPaymentStatus representa os estados normalizados que qualquer provider pode retornar.
@@ -27,6 +28,9 @@ const (
 	ProviderStatusRejected  ProviderPaymentStatus = "rejected"
 	ProviderStatusCancelled ProviderPaymentStatus = "cancelled"
 	ProviderStatusInProcess ProviderPaymentStatus = "in_process"
+	ProviderStatusRefunded  ProviderPaymentStatus = "refunded"
+	// ProviderStatusChargedBack: o cliente contestou a compra junto ao emissor do cartão.
+	ProviderStatusChargedBack ProviderPaymentStatus = "charged_back"
 )
 
 // ── Inputs ────────────────────────────────────────────────────────────────────
@@ -70,6 +74,13 @@ type HostedCheckoutBackURLs struct {
 	Failure string
 }
 
+// RefundInput devolve AmountCents do pagamento ao cliente.
+// AmountCents == 0 significa estorno total do saldo restante no provider.
+type RefundInput struct {
+	ProviderPaymentID string
+	AmountCents       int64
+}
+
 // ── Results ───────────────────────────────────────────────────────────────────
 
 // ProviderPaymentID é string para suportar IDs numéricos (MP) e UUIDs (PagBank, Stone).
@@ -92,3 +103,8 @@ type HostedCheckoutResult struct {
 	RedirectURL        string
 	SandboxURL         string
 }
+
+type RefundResult struct {
+	ProviderRefundID string
+	AmountCents      int64 // valor efetivamente devolvido pelo provider
+}
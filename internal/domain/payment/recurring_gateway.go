@@ -0,0 +1,38 @@
+package payment
+
+import "context"
+
+// RecurringGateway é implementado pelos providers que guardam o cartão do
+// cliente no cofre para cobrar sem o cliente presente (renovação de
+// assinatura). O backend só conhece a referência do cofre, nunca o cartão.
+type RecurringGateway interface {
+	StoreCard(ctx context.Context, input StoreCardInput) (*StoredCard, error)
+	ChargeStoredCard(ctx context.Context, input StoredCardChargeInput) (*CardPaymentResult, error)
+}
+
+// StoreCardInput: CardToken é o token do SDK do provider usado na compra.
+type StoreCardInput struct {
+	PayerEmail      string
+	PayerCPF        string
+	CardToken       string
+	PaymentMethodID string // "visa", "master", ...
+}
+
+// StoredCard é a referência do cartão no cofre do provider. CustomerRef é o
+// cliente no provider (Mercado Pago); vazio quando o provider não usa.
+type StoredCard struct {
+	CustomerRef string
+	CardRef     string
+	Brand       string
+	LastFour    string
+}
+
+type StoredCardChargeInput struct {
+	AmountCents       int64
+	Description       string
+	ExternalReference string
+	NotificationURL   string
+	PayerEmail        string
+	PayerCPF          string
+	Card              StoredCard
+}
@@ -97,6 +97,12 @@ type TxRepository interface {
 		appointmentID uint,
 	) (*models.Payment, error)
 
+	GetByIDForUpdate(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (*models.Payment, error)
+
 	GetAppointmentForUpdate(
 		ctx context.Context,
 		barbershopID uint,
@@ -115,6 +121,18 @@ type TxRepository interface {
 		now time.Time,
 	) ([]*models.Payment, error)
 
+	// VoidAppointmentCouponsTx / VoidOrderCouponsTx anulam os usos de cupom
+	// do agendamento ou pedido cancelado e os devolvem ao cupom.
+	VoidAppointmentCouponsTx(
+		ctx context.Context,
+		appointmentID uint,
+	) error
+
+	VoidOrderCouponsTx(
+		ctx context.Context,
+		orderID uint,
+	) error
+
 	Create(
 		ctx context.Context,
 		p *models.Payment,
@@ -138,6 +156,14 @@ type TxRepository interface {
 		p *models.Payment,
 	) error
 
+	// ApplyRefundTx persiste status/refunded_amount do payment e grava a
+	// devolução no extrato payment_refunds.
+	ApplyRefundTx(
+		ctx context.Context,
+		p *models.Payment,
+		refund *models.PaymentRefund,
+	) error
+
 	UpdateAppointmentTx(
 		ctx context.Context,
 		ap *models.Appointment,
@@ -172,17 +198,30 @@ type TxRepository interface {
 		orderID uint,
 	) ([]models.OrderItem, error)
 
+	// DecreaseProductStock dá baixa da venda no razão de estoque (movimento
+	// sale vinculado ao pedido).
 	DecreaseProductStock(
 		ctx context.Context,
 		barbershopID uint,
 		productID uint,
 		quantity int,
+		orderID uint,
 	) error
 
 	// Subscription activation (used when payment.SubscriptionID != nil)
 	GetSubscriptionForUpdate(ctx context.Context, id uint) (*models.Subscription, error)
 	GetPlanByID(ctx context.Context, id uint) (*models.Plan, error)
 	ActivateSubscriptionTx(ctx context.Context, id uint, periodStart, periodEnd time.Time) error
+	// RenewSubscriptionTx aplica a cobrança de renovação paga (payment.SubscriptionRenewal).
+	RenewSubscriptionTx(ctx context.Context, sub *models.Subscription, durationDays int, paidAt time.Time) error
+
+	// Pacote pré-pago (used when payment.ClientPackageID != nil)
+	GetClientPackageForUpdate(ctx context.Context, id uint) (*models.ClientPackage, error)
+	ActivateClientPackageTx(ctx context.Context, id uint, purchasedAt time.Time) error
+
+	// Context liga ctx a esta transação: o que for gravado no outbox com ele
+	// (auditoria, notificações, agenda) só vale se o Commit confirmar.
+	Context(ctx context.Context) context.Context
 
 	Commit() error
 	Rollback() error
@@ -3,17 +3,31 @@ package payment
 type Status string
 
 const (
-	StatusPending Status = "pending"
-	StatusPaid    Status = "paid"
-	StatusExpired Status = "expired"
+	StatusPending           Status = "pending"
+	StatusPaid              Status = "paid"
+	StatusExpired           Status = "expired"
+	StatusRefunded          Status = "refunded"
+	StatusPartiallyRefunded Status = "partially_refunded"
+	StatusChargedBack       Status = "charged_back"
 )
 
 // --------------------------------------------------
 // Helpers
 // --------------------------------------------------
 
+// IsFinal indica que o pagamento já saiu de pending — não pode mais ser pago
+// nem expirar. Estornos e chargebacks partem de um pagamento já liquidado.
 func (s Status) IsFinal() bool {
-	return s == StatusPaid || s == StatusExpired
+	switch s {
+	case StatusPaid, StatusExpired, StatusRefunded, StatusPartiallyRefunded, StatusChargedBack:
+		return true
+	}
+	return false
+}
+
+// IsRefundable indica que ainda há dinheiro recebido que pode ser devolvido.
+func (s Status) IsRefundable() bool {
+	return s == StatusPaid || s == StatusPartiallyRefunded
 }
 
 // Transições válidas do estado atual → target
@@ -24,13 +38,19 @@ func (s Status) CanTransitionTo(target Status) bool {
 	case StatusPending:
 		return target == StatusPaid || target == StatusExpired
 
-	case StatusPaid:
-		// Pago nunca pode mudar
-		return false
+	case StatusPaid, StatusPartiallyRefunded:
+		// Pago só muda por devolução do dinheiro (estorno ou contestação)
+		return target == StatusRefunded ||
+			target == StatusPartiallyRefunded ||
+			target == StatusChargedBack
 
 	case StatusExpired:
 		// Expirado nunca pode virar pago
 		return false
+
+	case StatusRefunded, StatusChargedBack:
+		// Dinheiro já devolvido integralmente
+		return false
 	}
 
 	return false
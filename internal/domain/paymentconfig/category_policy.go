@@ -64,15 +64,8 @@ func (p CategoryPaymentPolicy) Validate() error {
 // INTERNAL HELPERS (domínio puro)
 // ======================================================
 
+// Além das categorias padrão, a barbearia pode ter as próprias (vip,
+// dormant...) definidas nas regras de classificação.
 func isValidClientCategory(c domainMetrics.ClientCategory) bool {
-	switch c {
-	case
-		domainMetrics.CategoryNew,
-		domainMetrics.CategoryRegular,
-		domainMetrics.CategoryAtRisk,
-		domainMetrics.CategoryTrusted:
-		return true
-	default:
-		return false
-	}
+	return domainMetrics.IsValidCategoryName(c)
 }
@@ -15,6 +15,9 @@ type Product struct {
 	Stock         int
 	Active        bool
 	OnlineVisible bool
+
+	// LowStockThreshold: nil = sem alerta de estoque baixo.
+	LowStockThreshold *int
 }
 
 type Repository interface {
@@ -0,0 +1,27 @@
+package report
+
+// Document é o relatório pronto para o email e o anexo: destaques no topo
+// e seções em tabela, com os valores já formatados.
+type Document struct {
+	Report         string
+	Period         string
+	Title          string
+	BarbershopName string
+	DateFrom       string // dd/mm/aaaa
+	DateTo         string // dd/mm/aaaa
+
+	Highlights []Metric
+	Sections   []Section
+}
+
+type Metric struct {
+	Label string
+	Value string
+}
+
+// Section é uma tabela do relatório; Rows tem uma célula por coluna.
+type Section struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+}
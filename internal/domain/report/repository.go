@@ -0,0 +1,75 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type Repository interface {
+	ListSubscriptions(
+		ctx context.Context,
+		barbershopID uint,
+	) ([]models.ReportSubscription, error)
+
+	// GetSubscription retorna nil quando a assinatura não existe na
+	// barbearia.
+	GetSubscription(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (*models.ReportSubscription, error)
+
+	// SaveSubscription cria (ID zero) ou atualiza a assinatura.
+	SaveSubscription(
+		ctx context.Context,
+		s *models.ReportSubscription,
+	) error
+
+	// DeleteSubscription apaga a assinatura; o histórico fica, sem o
+	// vínculo. false quando ela não existe na barbearia.
+	DeleteSubscription(
+		ctx context.Context,
+		barbershopID uint,
+		id uint,
+	) (bool, error)
+
+	// DueSubscriptions lista as assinaturas ativas com envio vencido até
+	// now, da mais atrasada para a mais recente.
+	DueSubscriptions(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]models.ReportSubscription, error)
+
+	// AdvanceSubscription grava o próximo envio e, quando informado, o
+	// último envio feito.
+	AdvanceSubscription(
+		ctx context.Context,
+		id uint,
+		nextRunAt time.Time,
+		lastSentAt *time.Time,
+	) error
+
+	RecordDelivery(
+		ctx context.Context,
+		d *models.ReportDelivery,
+	) error
+
+	// ListDeliveries lista o histórico, do mais recente, filtrando pela
+	// assinatura quando informada, e o total.
+	ListDeliveries(
+		ctx context.Context,
+		barbershopID uint,
+		subscriptionID *uint,
+		limit int,
+		offset int,
+	) ([]models.ReportDelivery, int64, error)
+
+	// GetBarbershop retorna nil quando a barbearia não existe.
+	GetBarbershop(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+}
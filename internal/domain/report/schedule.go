@@ -0,0 +1,109 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Relatórios que podem ser assinados: as mesmas consultas do painel.
+const (
+	ReportDashboard = "dashboard"
+	ReportFinancial = "financial"
+	ReportImpact    = "impact"
+)
+
+// Periodicidade do envio. Cada envio cobre o último período fechado:
+// daily o dia anterior, weekly a semana anterior (segunda a domingo) e
+// monthly o mês anterior.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// Formato do anexo.
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+var ErrInvalidSendTime = errors.New("invalid_send_time")
+
+// ParseSendTime lê o horário HH:MM (00:00 a 23:59).
+func ParseSendTime(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil || len(s) != 5 {
+		return 0, 0, ErrInvalidSendTime
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// QueryPeriod traduz a periodicidade para o período das consultas
+// (day|week|month).
+func QueryPeriod(period string) string {
+	switch period {
+	case PeriodDaily:
+		return "day"
+	case PeriodMonthly:
+		return "month"
+	default:
+		return "week"
+	}
+}
+
+// NextRun é o primeiro envio depois de after: todo dia, toda segunda-feira
+// ou todo dia 1º, no horário sendTime do fuso loc. Retorna em UTC.
+func NextRun(period, sendTime string, loc *time.Location, after time.Time) (time.Time, error) {
+	h, m, err := ParseSendTime(sendTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := after.In(loc)
+	var next func(i int) time.Time
+	switch period {
+	case PeriodDaily:
+		next = func(i int) time.Time {
+			return time.Date(local.Year(), local.Month(), local.Day()+i, h, m, 0, 0, loc)
+		}
+	case PeriodWeekly:
+		monday := local.Day() - (int(local.Weekday())+6)%7
+		next = func(i int) time.Time {
+			return time.Date(local.Year(), local.Month(), monday+7*i, h, m, 0, 0, loc)
+		}
+	case PeriodMonthly:
+		next = func(i int) time.Time {
+			return time.Date(local.Year(), local.Month()+time.Month(i), 1, h, m, 0, 0, loc)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("report: unknown period %q", period)
+	}
+
+	run := next(0)
+	if !run.After(after) {
+		run = next(1)
+	}
+	return run.UTC(), nil
+}
+
+// CoveredPeriod é o último período fechado em at, no fuso loc: o início
+// (meia-noite local do primeiro dia) e o último dia, inclusive.
+func CoveredPeriod(period string, at time.Time, loc *time.Location) (start, lastDay time.Time) {
+	local := at.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var end time.Time
+	switch period {
+	case PeriodDaily:
+		end = today
+		start = today.AddDate(0, 0, -1)
+	case PeriodMonthly:
+		end = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+		start = end.AddDate(0, -1, 0)
+	default:
+		end = today.AddDate(0, 0, -((int(local.Weekday()) + 6) % 7))
+		start = end.AddDate(0, 0, -7)
+	}
+	return start, end.AddDate(0, 0, -1)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextRun(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("tzdata indisponível")
+	}
+	// quarta-feira, 15/10/2025 10:00 em São Paulo
+	after := time.Date(2025, 10, 15, 10, 0, 0, 0, loc)
+
+	cases := []struct {
+		name     string
+		period   string
+		sendTime string
+		want     time.Time
+	}{
+		{"diário ainda hoje", PeriodDaily, "18:30", time.Date(2025, 10, 15, 18, 30, 0, 0, loc)},
+		{"diário amanhã", PeriodDaily, "08:00", time.Date(2025, 10, 16, 8, 0, 0, 0, loc)},
+		{"semanal na próxima segunda", PeriodWeekly, "08:00", time.Date(2025, 10, 20, 8, 0, 0, 0, loc)},
+		{"mensal no dia 1º", PeriodMonthly, "07:00", time.Date(2025, 11, 1, 7, 0, 0, 0, loc)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NextRun(tc.period, tc.sendTime, loc, after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("esperado %s, obtido %s", tc.want, got.In(loc))
+			}
+		})
+	}
+
+	// No exato horário do envio, o próximo é o da semana seguinte.
+	monday := time.Date(2025, 10, 20, 8, 0, 0, 0, loc)
+	got, _ := NextRun(PeriodWeekly, "08:00", loc, monday)
+	if want := monday.AddDate(0, 0, 7); !got.Equal(want) {
+		t.Errorf("esperado %s, obtido %s", want, got.In(loc))
+	}
+
+	if _, err := NextRun(PeriodDaily, "24:00", loc, after); !errors.Is(err, ErrInvalidSendTime) {
+		t.Errorf("horário inválido deveria falhar, obtido %v", err)
+	}
+}
+
+func TestCoveredPeriod(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	// segunda-feira, 20/10/2025 08:00
+	at := time.Date(2025, 10, 20, 8, 0, 0, 0, loc)
+
+	cases := []struct {
+		period   string
+		from, to string
+	}{
+		{PeriodDaily, "2025-10-19", "2025-10-19"},
+		{PeriodWeekly, "2025-10-13", "2025-10-19"},
+		{PeriodMonthly, "2025-09-01", "2025-09-30"},
+	}
+	for _, tc := range cases {
+		start, last := CoveredPeriod(tc.period, at, loc)
+		if got := start.Format("2006-01-02"); got != tc.from {
+			t.Errorf("%s: início esperado %s, obtido %s", tc.period, tc.from, got)
+		}
+		if got := last.Format("2006-01-02"); got != tc.to {
+			t.Errorf("%s: fim esperado %s, obtido %s", tc.period, tc.to, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(ReportFinancial, PeriodWeekly, FormatPDF, "08:00"); err != nil {
+		t.Errorf("esperado válido, obtido %v", err)
+	}
+	if err := Validate(ReportImpact, PeriodDaily, FormatCSV, "08:00"); !errors.Is(err, ErrPeriodUnsupported) {
+		t.Errorf("impacto diário deveria ser recusado, obtido %v", err)
+	}
+	if err := Validate(ReportDashboard, PeriodDaily, "xlsx", "08:00"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("formato inválido deveria ser recusado, obtido %v", err)
+	}
+	if err := Validate(ReportDashboard, PeriodDaily, FormatCSV, "8:00"); !errors.Is(err, ErrInvalidSendTime) {
+		t.Errorf("horário sem zero deveria ser recusado, obtido %v", err)
+	}
+}
+
+func TestNormalizeRecipients(t *testing.T) {
+	got, err := NormalizeRecipients([]string{" Dono@Barbearia.com ", "dono@barbearia.com", "", "gerente@barbearia.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "dono@barbearia.com" || got[1] != "gerente@barbearia.com" {
+		t.Errorf("obtido %v", got)
+	}
+
+	for _, in := range [][]string{nil, {"sem-arroba"}, {"Dono <dono@b.com>"}} {
+		if _, err := NormalizeRecipients(in); !errors.Is(err, ErrInvalidRecipients) {
+			t.Errorf("%v deveria ser recusado, obtido %v", in, err)
+		}
+	}
+}
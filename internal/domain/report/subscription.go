@@ -0,0 +1,70 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+const maxRecipients = 10
+
+var (
+	ErrInvalidReport     = errors.New("invalid_report")
+	ErrInvalidPeriod     = errors.New("invalid_report_period")
+	ErrInvalidFormat     = errors.New("invalid_report_format")
+	ErrInvalidRecipients = errors.New("invalid_recipients")
+
+	// ErrPeriodUnsupported: financeiro e impacto não têm visão diária.
+	ErrPeriodUnsupported = errors.New("report_period_unsupported")
+)
+
+// Validate confere relatório, periodicidade, formato e horário.
+func Validate(report, period, format, sendTime string) error {
+	switch report {
+	case ReportDashboard, ReportFinancial, ReportImpact:
+	default:
+		return ErrInvalidReport
+	}
+	switch period {
+	case PeriodDaily, PeriodWeekly, PeriodMonthly:
+	default:
+		return ErrInvalidPeriod
+	}
+	if period == PeriodDaily && report != ReportDashboard {
+		return ErrPeriodUnsupported
+	}
+	switch format {
+	case FormatCSV, FormatPDF:
+	default:
+		return ErrInvalidFormat
+	}
+	_, _, err := ParseSendTime(sendTime)
+	return err
+}
+
+// NormalizeRecipients valida os emails (de 1 a 10), em minúsculas e sem
+// repetidos; o erro nomeia o endereço recusado.
+func NormalizeRecipients(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool, len(in))
+	for _, raw := range in {
+		addr := strings.ToLower(strings.TrimSpace(raw))
+		if addr == "" {
+			continue
+		}
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil || parsed.Address != addr {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecipients, raw)
+		}
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		out = append(out, addr)
+	}
+	if len(out) == 0 || len(out) > maxRecipients {
+		return nil, ErrInvalidRecipients
+	}
+	return out, nil
+}
@@ -8,4 +8,5 @@ var (
 	ErrInvalidName     = errors.New("invalid_name")
 	ErrInvalidDuration = errors.New("invalid_duration")
 	ErrInvalidPrice    = errors.New("invalid_price")
+	ErrBarberNotFound  = errors.New("barber_not_found")
 )
@@ -35,4 +35,40 @@ type Repository interface {
 		category string,
 		query string,
 	) ([]*Service, error)
+
+	// ==================================================
+	// PER-BARBER CATALOG
+	// ==================================================
+
+	BarberExists(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+	) (bool, error)
+
+	// GetBarberOverride retorna nil quando o barbeiro usa os valores da barbearia.
+	GetBarberOverride(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		serviceID uint,
+	) (*BarberOverride, error)
+
+	ListBarberOverrides(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+	) ([]*BarberOverride, error)
+
+	UpsertBarberOverride(
+		ctx context.Context,
+		o *BarberOverride,
+	) error
+
+	DeleteBarberOverride(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		serviceID uint,
+	) error
 }
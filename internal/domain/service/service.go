@@ -18,3 +18,30 @@ type Service struct {
 	CategoryID   *uint
 	Images       []ServiceImage
 }
+
+// BarberOverride personaliza o serviço para um barbeiro: preço e/ou duração
+// próprios, ou Offered = false quando ele não faz o serviço. Campos nil
+// herdam os valores do serviço da barbearia.
+type BarberOverride struct {
+	BarbershopID uint
+	BarberID     uint
+	ServiceID    uint
+	Offered      bool
+	Price        *int64
+	DurationMin  *int
+}
+
+// ApplyTo devolve uma cópia do serviço com o preço e a duração do barbeiro.
+func (o *BarberOverride) ApplyTo(s *Service) *Service {
+	effective := *s
+	if o == nil {
+		return &effective
+	}
+	if o.Price != nil {
+		effective.Price = *o.Price
+	}
+	if o.DurationMin != nil {
+		effective.DurationMin = *o.DurationMin
+	}
+	return &effective
+}
@@ -0,0 +1,6 @@
+package servicepackage
+
+import "errors"
+
+// ErrNoCreditsLeft: o pacote não tem mais créditos para debitar.
+var ErrNoCreditsLeft = errors.New("package_no_credits_left")
@@ -0,0 +1,127 @@
+package servicepackage
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Repository persiste pacotes pré-pagos, pacotes comprados e combos.
+type Repository interface {
+	// CountServices conta quantos dos serviços informados existem na barbearia.
+	CountServices(
+		ctx context.Context,
+		barbershopID uint,
+		serviceIDs []uint,
+	) (int64, error)
+
+	// CreatePackage grava o pacote e seus serviços (pkg.ServiceIDs).
+	CreatePackage(
+		ctx context.Context,
+		pkg *models.ServicePackage,
+	) error
+
+	// UpdatePackage substitui os dados e os serviços do pacote.
+	UpdatePackage(
+		ctx context.Context,
+		pkg *models.ServicePackage,
+	) error
+
+	// GetPackage retorna nil quando o pacote não existe na barbearia.
+	GetPackage(
+		ctx context.Context,
+		barbershopID uint,
+		packageID uint,
+	) (*models.ServicePackage, error)
+
+	ListPackages(
+		ctx context.Context,
+		barbershopID uint,
+		onlyActive bool,
+	) ([]models.ServicePackage, error)
+
+	// FindOrCreateClient encontra o cliente pelo telefone ou cria um novo.
+	FindOrCreateClient(
+		ctx context.Context,
+		barbershopID uint,
+		name string,
+		phone string,
+	) (*models.Client, error)
+
+	CreateClientPackage(
+		ctx context.Context,
+		cp *models.ClientPackage,
+	) error
+
+	// GetClientPackage retorna nil quando a compra não existe na barbearia.
+	GetClientPackage(
+		ctx context.Context,
+		barbershopID uint,
+		clientPackageID uint,
+	) (*models.ClientPackage, error)
+
+	// ActivateClientPackage ativa uma compra pending_payment, com validade a
+	// partir de purchasedAt. No-op quando ela já saiu de pending_payment.
+	ActivateClientPackage(
+		ctx context.Context,
+		clientPackageID uint,
+		purchasedAt time.Time,
+	) error
+
+	// ListClientPackages lista as compras ativas e encerradas do cliente,
+	// das mais recentes para as mais antigas.
+	ListClientPackages(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) ([]models.ClientPackage, error)
+
+	// FindUsableForUpdate trava e retorna o pacote do cliente que pode cobrir
+	// o serviço em now — ativo, dentro da validade, com saldo e que inclui o
+	// serviço —, o que vence primeiro. nil quando nenhum cobre.
+	FindUsableForUpdate(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+		serviceID uint,
+		now time.Time,
+	) (*models.ClientPackage, error)
+
+	// ConsumeCredit debita um crédito; falha se o pacote não tem saldo.
+	ConsumeCredit(
+		ctx context.Context,
+		clientPackageID uint,
+	) error
+
+	// ExpireClientPackages encerra os pacotes ativos vencidos em now.
+	ExpireClientPackages(
+		ctx context.Context,
+		now time.Time,
+	) (int64, error)
+
+	// CreateCombo grava o combo e seus itens (combo.Items).
+	CreateCombo(
+		ctx context.Context,
+		combo *models.ServiceCombo,
+	) error
+
+	// UpdateCombo substitui os dados e os itens do combo.
+	UpdateCombo(
+		ctx context.Context,
+		combo *models.ServiceCombo,
+	) error
+
+	// GetCombo retorna nil quando o combo não existe na barbearia.
+	GetCombo(
+		ctx context.Context,
+		barbershopID uint,
+		comboID uint,
+	) (*models.ServiceCombo, error)
+
+	ListCombos(
+		ctx context.Context,
+		barbershopID uint,
+		onlyActive bool,
+	) ([]models.ServiceCombo, error)
+}
@@ -0,0 +1,84 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// RenewalLeadTime: a cobrança da renovação sai antes do fim do período, para
+// que uma recusa ainda tenha retentativas sem o cliente perder o plano.
+const RenewalLeadTime = 3 * 24 * time.Hour
+
+// GracePeriod: encerrado o período sem a renovação paga, a assinatura fica
+// em carência (past_due) antes de expirar.
+const GracePeriod = 7 * 24 * time.Hour
+
+// RenewalRetryDelays é a espera antes de cada nova tentativa após uma recusa
+// (1ª recusa → +1 dia, 2ª → +2 dias, 3ª → +3 dias).
+var RenewalRetryDelays = []time.Duration{
+	24 * time.Hour,
+	48 * time.Hour,
+	72 * time.Hour,
+}
+
+// MaxRenewalAttempts conta a cobrança inicial e as retentativas.
+func MaxRenewalAttempts() int {
+	return len(RenewalRetryDelays) + 1
+}
+
+// NextRenewalAttempt devolve quando cobrar de novo após a recusa número
+// attempts (1 = primeira), ou nil quando as tentativas acabaram ou a próxima
+// cairia depois de deadline (fim da carência).
+func NextRenewalAttempt(attempts int, failedAt, deadline time.Time) *time.Time {
+	if attempts < 1 || attempts > len(RenewalRetryDelays) {
+		return nil
+	}
+	next := failedAt.Add(RenewalRetryDelays[attempts-1])
+	if !next.Before(deadline) {
+		return nil
+	}
+	return &next
+}
+
+// Renewal é uma assinatura com renovação automática e o que é preciso para
+// cobrá-la e avisar o cliente.
+type Renewal struct {
+	Subscription   Subscription
+	Card           models.SubscriptionCard
+	ClientName     string
+	ClientPhone    string
+	ClientEmail    string
+	BarbershopName string
+	Timezone       string
+}
+
+// RenewalRepository persiste o cartão guardado e o estado da renovação
+// automática.
+type RenewalRepository interface {
+	// SaveCard grava (ou substitui) o cartão da assinatura.
+	SaveCard(ctx context.Context, card *models.SubscriptionCard) error
+	GetCard(ctx context.Context, subscriptionID uint) (*models.SubscriptionCard, error)
+	SetAutoRenew(ctx context.Context, subscriptionID uint, autoRenew bool) error
+
+	// FindRenewableSubscriptionID devolve a assinatura ativa ou em carência
+	// do cliente, ou 0 quando não há.
+	FindRenewableSubscriptionID(ctx context.Context, barbershopID, clientID uint) (uint, error)
+
+	// ListDueRenewals lista as assinaturas a cobrar agora: ativas a menos de
+	// RenewalLeadTime do fim do período ou em carência, com tentativa vencida
+	// e sem cobrança de renovação pendente.
+	ListDueRenewals(ctx context.Context, now time.Time, limit int) ([]Renewal, error)
+	GetRenewal(ctx context.Context, subscriptionID uint) (*Renewal, error)
+
+	// FailRenewalPayment marca a cobrança pendente como expirada. false quando
+	// ela já não estava pendente (falha já registrada ou pagamento confirmado).
+	FailRenewalPayment(ctx context.Context, paymentID uint) (bool, error)
+	RecordRenewalFailure(ctx context.Context, subscriptionID uint, attempts int, nextAttemptAt *time.Time) error
+}
+
+// RenewalListener é avisado quando a cobrança de uma renovação é confirmada.
+type RenewalListener interface {
+	SubscriptionRenewed(ctx context.Context, subscriptionID uint)
+}
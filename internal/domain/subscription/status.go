@@ -7,4 +7,7 @@ const (
 	StatusCancelled      Status = "cancelled"
 	StatusExpired        Status = "expired"
 	StatusPendingPayment Status = "pending_payment"
+	// StatusPastDue: período encerrado sem a renovação paga; o cliente está
+	// na carência enquanto as retentativas de cobrança continuam.
+	StatusPastDue Status = "past_due"
 )
@@ -13,5 +13,20 @@ type Subscription struct {
 	CutsUsedInPeriod     int
 	CutsReservedInPeriod int
 
+	AutoRenew        bool
+	RenewalAttempts  int
+	NextRenewalAt    *time.Time
+	RenewedPeriodEnd *time.Time
+	GraceUntil       *time.Time
+
 	Plan *Plan
 }
+
+// RenewalDeadline é quando a assinatura expira se a renovação não for paga:
+// o fim da carência.
+func (s Subscription) RenewalDeadline() time.Time {
+	if s.GraceUntil != nil {
+		return *s.GraceUntil
+	}
+	return s.CurrentPeriodEnd.Add(GracePeriod)
+}
@@ -0,0 +1,100 @@
+package waitlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// FreedSlot é o horário liberado por um agendamento que deixou de existir
+// (cancelamento pelo painel, pelo ticket ou por pagamento expirado).
+type FreedSlot struct {
+	BarbershopID uint
+	BarberID     uint
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// SlotListener é avisado dentro da transação do cancelamento (contexto
+// ligado a ela por outbox.ContextWithTx); a implementação do outbox grava o
+// horário e as ofertas saem pelo worker, depois do commit. Erro na gravação
+// deve desfazer o cancelamento: o chamador o devolve na transação.
+type SlotListener interface {
+	SlotFreed(ctx context.Context, slot FreedSlot) error
+}
+
+type Repository interface {
+	GetBarbershopByID(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+
+	// GetService retorna nil quando o serviço não existe na barbearia.
+	GetService(
+		ctx context.Context,
+		barbershopID uint,
+		serviceID uint,
+	) (*models.BarbershopService, error)
+
+	// GetBarberServiceOverride retorna nil quando o barbeiro usa os valores
+	// da barbearia (catálogo por barbeiro).
+	GetBarberServiceOverride(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		serviceID uint,
+	) (*models.BarberServiceOverride, error)
+
+	// ActiveBarberExists indica se o barbeiro é da barbearia e está ativo.
+	ActiveBarberExists(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+	) (bool, error)
+
+	// HasOpenEntry indica se o cliente (telefone ou email) já espera pelo serviço.
+	HasOpenEntry(
+		ctx context.Context,
+		barbershopID uint,
+		serviceID uint,
+		phone string,
+		email string,
+	) (bool, error)
+
+	Create(
+		ctx context.Context,
+		entry *models.WaitlistEntry,
+	) error
+
+	// GetByToken retorna nil quando o token não existe.
+	GetByToken(
+		ctx context.Context,
+		token string,
+	) (*models.WaitlistEntry, error)
+
+	// ListOfferCandidates lista, das mais antigas para as mais novas, as
+	// entradas que aceitam o dia e o barbeiro informados e que estão livres
+	// para receber oferta (waiting ou com oferta vencida em now).
+	ListOfferCandidates(
+		ctx context.Context,
+		barbershopID uint,
+		barberID uint,
+		day time.Time,
+		now time.Time,
+		limit int,
+	) ([]*models.WaitlistEntry, error)
+
+	// SetOffer grava a oferta de entry (Offered*, status offered) apenas se a
+	// entrada ainda está livre em now. false = outra liberação chegou antes.
+	SetOffer(
+		ctx context.Context,
+		entry *models.WaitlistEntry,
+		now time.Time,
+	) (bool, error)
+
+	Update(
+		ctx context.Context,
+		entry *models.WaitlistEntry,
+	) error
+}
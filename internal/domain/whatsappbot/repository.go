@@ -0,0 +1,76 @@
+package whatsappbot
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// UpcomingAppointment é um agendamento futuro em aberto do cliente, com o
+// ticket que o bot usa para cancelar e remarcar.
+type UpcomingAppointment struct {
+	ID          uint
+	BarberID    uint
+	ServiceIDs  []uint // serviços em ordem; o primeiro é o principal
+	ServiceName string
+	StartTime   time.Time
+	TicketToken string
+}
+
+// Repository guarda as conversas do bot e faz as leituras que os menus
+// precisam.
+type Repository interface {
+	// LockConversation serializa as mensagens de um número na instância: fn
+	// roda com a conversa travada até retornar e recebe um Repository na
+	// mesma transação, para ler e salvar o estado sem outra mensagem do
+	// cliente no meio. Erro de fn desfaz o que foi salvo.
+	LockConversation(
+		ctx context.Context,
+		instanceName string,
+		phone string,
+		fn func(ctx context.Context, repo Repository) error,
+	) error
+
+	// GetConversation retorna nil quando não há conversa do número na
+	// instância.
+	GetConversation(
+		ctx context.Context,
+		instanceName string,
+		phone string,
+	) (*models.WhatsAppConversation, error)
+
+	// SaveConversation cria ou substitui a conversa do número na instância.
+	SaveConversation(
+		ctx context.Context,
+		c *models.WhatsAppConversation,
+	) error
+
+	DeleteConversation(
+		ctx context.Context,
+		instanceName string,
+		phone string,
+	) error
+
+	// GetBarbershop retorna nil quando a barbearia não existe.
+	GetBarbershop(
+		ctx context.Context,
+		barbershopID uint,
+	) (*models.Barbershop, error)
+
+	// FindClient busca o cliente pelo número do WhatsApp, tolerante à
+	// formatação do telefone cadastrado. nil quando não há.
+	FindClient(
+		ctx context.Context,
+		barbershopID uint,
+		phone string,
+	) (*models.Client, error)
+
+	// ListUpcoming lista os agendamentos futuros em aberto do cliente que
+	// têm ticket válido, do mais próximo ao mais distante.
+	ListUpcoming(
+		ctx context.Context,
+		barbershopID uint,
+		clientID uint,
+	) ([]UpcomingAppointment, error)
+}
@@ -1,10 +1,11 @@
 package dto
 
 type PublicCheckoutOrderDTO struct {
-	OrderID    uint   `json:"order_id"`
-	Status     string `json:"status"`
-	TotalCents int64  `json:"total_cents"`
-	ItemsCount int    `json:"items_count"`
+	OrderID       uint   `json:"order_id"`
+	Status        string `json:"status"`
+	TotalCents    int64  `json:"total_cents"`
+	DiscountCents int64  `json:"discount_cents"`
+	ItemsCount    int    `json:"items_count"`
 }
 
 type PublicCheckoutNextStepDTO struct {
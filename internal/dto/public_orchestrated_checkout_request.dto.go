@@ -1,7 +1,10 @@
 package dto
 
 type PublicOrchestratedCheckoutRequestDTO struct {
-	ServiceID      uint    `json:"service_id" binding:"required"`
+	ServiceID      uint    `json:"service_id"`
+	ServiceIDs     []uint  `json:"service_ids,omitempty"` // vários serviços, em ordem; substitui service_id
+	ComboID        uint    `json:"combo_id,omitempty"`    // serviços de um combo; substitui service_id/service_ids
+	BarberID       *uint   `json:"barber_id,omitempty"` // omitido = qualquer barbeiro disponível
 	Date           string  `json:"date" binding:"required"` // YYYY-MM-DD
 	Time           string  `json:"time" binding:"required"` // HH:mm
 	ClientName     string  `json:"client_name" binding:"required"`
@@ -9,5 +12,6 @@ type PublicOrchestratedCheckoutRequestDTO struct {
 	ClientEmail    string  `json:"client_email"`
 	Notes          string  `json:"notes"`
 	CartKey        *string `json:"cart_key,omitempty"`
+	CouponCode     string  `json:"coupon_code,omitempty"` // cupom de produtos vai para o pedido; os demais, para o agendamento
 	IdempotencyKey string  `json:"-"`
 }
@@ -22,6 +22,7 @@ type PublicOrchestratedCheckoutOrderDTO struct {
 type PublicOrchestratedCheckoutSummaryDTO struct {
 	ServiceAmountCents  int64 `json:"service_amount_cents"`
 	ProductsAmountCents int64 `json:"products_amount_cents"`
+	DiscountCents       int64 `json:"discount_cents"` // cupom (agendamento + pedido)
 	TotalAmountCents    int64 `json:"total_amount_cents"`
 }
 
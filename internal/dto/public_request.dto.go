@@ -4,13 +4,21 @@ type PublicCreateAppointmentRequest struct {
 	ClientName  string `json:"client_name" binding:"required"`
 	ClientPhone string `json:"client_phone" binding:"required"`
 	ClientEmail string `json:"client_email"`
-	ServiceID   uint   `json:"service_id" binding:"required"`
+	ServiceID   uint   `json:"service_id"`
+	ServiceIDs  []uint `json:"service_ids"` // vários serviços, em ordem; substitui service_id
+	ComboID     uint   `json:"combo_id"`    // serviços de um combo; substitui service_id/service_ids
+	BarberID    *uint  `json:"barber_id,omitempty"` // omitido = qualquer barbeiro disponível
 	Date        string `json:"date" binding:"required"` // YYYY-MM-DD
 	Time        string `json:"time" binding:"required"` // HH:mm
 	Notes       string `json:"notes"`
+	CouponCode  string `json:"coupon_code"`
 }
 
 type PublicAddCartItemRequest struct {
 	ProductID uint `json:"product_id" binding:"required"`
 	Quantity  int  `json:"quantity" binding:"required"`
 }
+
+type PublicCheckoutCartRequest struct {
+	CouponCode string `json:"coupon_code"`
+}
@@ -7,13 +7,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/dto"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
-	gcal "github.com/BruksfildServices01/barber-scheduler/internal/integration/calendar"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
-	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/httpresp"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
 	"github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
@@ -31,9 +28,6 @@ type AppointmentHandler struct {
 	listByDate    *appointment.ListAppointmentsByDate
 	listByMonth   *appointment.ListAppointmentsByMonth
 	noShow        *appointment.MarkAppointmentNoShow
-	db            *gorm.DB
-	googleCfg     gcal.OAuthConfig
-	googleCipher  *crypt.Cipher
 }
 
 type CompleteAppointmentItemRequest struct {
@@ -58,6 +52,9 @@ type CompleteAppointmentRequest struct {
 
 	OperationalNote       string `json:"operational_note"`
 	ConfirmNormalCharging bool   `json:"confirm_normal_charging"`
+
+	// Recompensa de fidelidade trocada pelo cliente (opcional).
+	LoyaltyRewardID *uint `json:"loyalty_reward_id"`
 }
 
 func NewAppointmentHandler(
@@ -67,9 +64,6 @@ func NewAppointmentHandler(
 	noShow *appointment.MarkAppointmentNoShow,
 	listByDate *appointment.ListAppointmentsByDate,
 	listByMonth *appointment.ListAppointmentsByMonth,
-	db *gorm.DB,
-	googleCfg gcal.OAuthConfig,
-	googleCipher *crypt.Cipher,
 ) *AppointmentHandler {
 	return &AppointmentHandler{
 		createUC:     create,
@@ -78,9 +72,6 @@ func NewAppointmentHandler(
 		noShow:       noShow,
 		listByDate:   listByDate,
 		listByMonth:  listByMonth,
-		db:           db,
-		googleCfg:    googleCfg,
-		googleCipher: googleCipher,
 	}
 }
 
@@ -92,10 +83,13 @@ type CreateAppointmentRequest struct {
 	ClientName  string `json:"client_name" binding:"required"`
 	ClientPhone string `json:"client_phone" binding:"required"`
 	ClientEmail string `json:"client_email"`
-	ProductID   uint   `json:"product_id" binding:"required"`
+	ProductID   uint   `json:"product_id"`
+	ProductIDs  []uint `json:"product_ids"` // vários serviços, em ordem; substitui product_id
+	ComboID     uint   `json:"combo_id"`    // serviços de um combo; substitui product_id/product_ids
 	Date        string `json:"date" binding:"required"` // YYYY-MM-DD
 	Time        string `json:"time" binding:"required"` // HH:mm
 	Notes       string `json:"notes"`
+	CouponCode  string `json:"coupon_code"`
 }
 
 ////////////////////////////////////////////////////////
@@ -107,7 +101,7 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 	barberID := c.MustGet(middleware.ContextUserID).(uint)
 
 	var req CreateAppointmentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.ProductID == 0 && len(req.ProductIDs) == 0 && req.ComboID == 0) {
 		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
 		return
 	}
@@ -123,10 +117,13 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 			ClientPhone:    req.ClientPhone,
 			ClientEmail:    req.ClientEmail,
 			ProductID:      req.ProductID,
+			ProductIDs:     req.ProductIDs,
+			ComboID:        req.ComboID,
 			Date:           req.Date,
 			Time:           req.Time,
 			Notes:          req.Notes,
 			IdempotencyKey: idempotencyKey,
+			CouponCode:     req.CouponCode,
 		},
 	)
 
@@ -135,11 +132,6 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Sincroniza com Google Calendar do barbeiro de forma assíncrona (best-effort).
-	if h.db != nil {
-		gcal.SyncAppointmentToGoogle(h.db, h.googleCfg, h.googleCipher, barberID, barbershopID, ap)
-	}
-
 	c.JSON(http.StatusCreated, ap)
 }
 
@@ -199,9 +191,13 @@ func (h *AppointmentHandler) Complete(c *gin.Context) {
 			SuggestionRemoved:     req.SuggestionRemoved,
 			OperationalNote:       req.OperationalNote,
 			ConfirmNormalCharging: req.ConfirmNormalCharging,
+			LoyaltyRewardID:       req.LoyaltyRewardID,
 		},
 	)
 	if err != nil {
+		if writeLoyaltyError(c, err) {
+			return
+		}
 		switch {
 		case apperr.IsBusiness(err, "appointment_not_found"):
 			httperr.NotFound(c, "appointment_not_found", "Agendamento não encontrado.")
@@ -236,6 +232,13 @@ func (h *AppointmentHandler) Complete(c *gin.Context) {
 				"Confirmação de cobrança normal é obrigatória.",
 			)
 
+		case apperr.IsBusiness(err, "actual_service_requires_single_service"):
+			httperr.BadRequest(
+				c,
+				"actual_service_requires_single_service",
+				"O serviço realizado só pode ser trocado em agendamentos de um serviço.",
+			)
+
 		case isSubscriptionConsumeFailure(err):
 			httperr.Internal(
 				c,
@@ -328,7 +331,11 @@ func (h *AppointmentHandler) Cancel(c *gin.Context) {
 
 func (h *AppointmentHandler) ListByDate(c *gin.Context) {
 	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
-	barberID := c.MustGet(middleware.ContextUserID).(uint)
+	barberID, ok := agendaBarberID(c)
+	if !ok {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return
+	}
 
 	dateStr := c.Query("date")
 	if dateStr == "" {
@@ -358,7 +365,11 @@ func (h *AppointmentHandler) ListByDate(c *gin.Context) {
 
 func (h *AppointmentHandler) ListByMonth(c *gin.Context) {
 	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
-	barberID := c.MustGet(middleware.ContextUserID).(uint)
+	barberID, ok := agendaBarberID(c)
+	if !ok {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return
+	}
 
 	year, err := strconv.Atoi(c.Query("year"))
 	if err != nil {
@@ -392,6 +403,10 @@ func (h *AppointmentHandler) ListByMonth(c *gin.Context) {
 ////////////////////////////////////////////////////////
 
 func mapCreateErrors(c *gin.Context, err error) {
+	if writeCouponError(c, err) {
+		return
+	}
+
 	switch {
 	case apperr.IsBusiness(err, "duplicate_request"):
 		httperr.Write(
@@ -410,6 +425,18 @@ func mapCreateErrors(c *gin.Context, err error) {
 	case apperr.IsBusiness(err, "product_not_found"):
 		httperr.BadRequest(c, "product_not_found", "Serviço não encontrado.")
 
+	case apperr.IsBusiness(err, "service_not_offered"):
+		httperr.BadRequest(c, "service_not_offered", "Este barbeiro não faz este serviço.")
+
+	case apperr.IsBusiness(err, "too_many_services"):
+		httperr.BadRequest(c, "too_many_services", "Máximo de 5 serviços por agendamento.")
+
+	case apperr.IsBusiness(err, "duplicate_service"):
+		httperr.BadRequest(c, "duplicate_service", "Serviço repetido no agendamento.")
+
+	case apperr.IsBusiness(err, "combo_not_found"):
+		httperr.BadRequest(c, "combo_not_found", "Combo não encontrado.")
+
 	case apperr.IsBusiness(err, "outside_working_hours"):
 		httperr.BadRequest(c, "outside_working_hours", "Fora do horário de atendimento.")
 
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+)
+
+// AppointmentSeriesHandler gerencia séries de agendamentos recorrentes.
+// Barbeiros (role "barber") só criam e veem as próprias séries; o dono
+// gerencia as de qualquer barbeiro.
+type AppointmentSeriesHandler struct {
+	createUC *appointment.CreateSeries
+	getUC    *appointment.GetSeries
+	updateUC *appointment.UpdateSeries
+	cancelUC *appointment.CancelSeries
+}
+
+func NewAppointmentSeriesHandler(
+	createUC *appointment.CreateSeries,
+	getUC *appointment.GetSeries,
+	updateUC *appointment.UpdateSeries,
+	cancelUC *appointment.CancelSeries,
+) *AppointmentSeriesHandler {
+	return &AppointmentSeriesHandler{
+		createUC: createUC,
+		getUC:    getUC,
+		updateUC: updateUC,
+		cancelUC: cancelUC,
+	}
+}
+
+type createSeriesRequest struct {
+	BarberID      *uint  `json:"barber_id"` // omitido = o próprio usuário
+	ClientName    string `json:"client_name" binding:"required"`
+	ClientPhone   string `json:"client_phone" binding:"required"`
+	ClientEmail   string `json:"client_email"`
+	ProductID     uint   `json:"product_id" binding:"required"`
+	StartDate     string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	Time          string `json:"time" binding:"required"`       // HH:mm
+	IntervalWeeks int    `json:"interval_weeks"`                // omitido = semanal
+	UntilDate     string `json:"until_date"`                    // YYYY-MM-DD
+	Count         int    `json:"count"`
+	Notes         string `json:"notes"`
+}
+
+type updateSeriesRequest struct {
+	Scope         string  `json:"scope" binding:"required"` // this | following | all
+	AppointmentID uint    `json:"appointment_id"`
+	Date          string  `json:"date"`
+	Time          string  `json:"time"`
+	ProductID     uint    `json:"product_id"`
+	Notes         *string `json:"notes"`
+}
+
+// seriesBarberScope devolve o barbeiro ao qual o usuário está restrito
+// (0 = dono, vê todas as séries).
+func seriesBarberScope(c *gin.Context) uint {
+	if middleware.IsOwner(c) {
+		return 0
+	}
+	return c.GetUint(middleware.ContextUserID)
+}
+
+// ======================================================
+// POST /me/appointment-series
+// ======================================================
+
+func (h *AppointmentSeriesHandler) Create(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+	userID := c.GetUint(middleware.ContextUserID)
+
+	var req createSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	barberID := userID
+	if req.BarberID != nil {
+		barberID = *req.BarberID
+	}
+	if !middleware.IsOwner(c) && barberID != userID {
+		httperr.Write(c, http.StatusForbidden, "barber_not_allowed", "Você só pode agendar na sua própria agenda.")
+		return
+	}
+
+	result, err := h.createUC.Execute(c.Request.Context(), appointment.CreateSeriesInput{
+		BarbershopID:  barbershopID,
+		BarberID:      barberID,
+		UserID:        userID,
+		ClientName:    req.ClientName,
+		ClientPhone:   req.ClientPhone,
+		ClientEmail:   req.ClientEmail,
+		ProductID:     req.ProductID,
+		StartDate:     req.StartDate,
+		Time:          req.Time,
+		IntervalWeeks: req.IntervalWeeks,
+		UntilDate:     req.UntilDate,
+		Count:         req.Count,
+		Notes:         req.Notes,
+	})
+	if err != nil {
+		if !writeSeriesError(c, err) {
+			mapCreateErrors(c, err)
+		}
+		return
+	}
+
+	if result.Series == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error_code":  "series_no_occurrences",
+			"message":     "Nenhuma ocorrência da série pôde ser agendada.",
+			"occurrences": result.Occurrences,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// ======================================================
+// GET /me/appointment-series/:id
+// ======================================================
+
+func (h *AppointmentSeriesHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httperr.BadRequest(c, "invalid_id", "ID inválido.")
+		return
+	}
+
+	series, upcoming, err := h.getUC.Execute(
+		c.Request.Context(),
+		c.GetUint(middleware.ContextBarbershopID),
+		seriesBarberScope(c),
+		uint(id),
+	)
+	if err != nil {
+		if !writeSeriesError(c, err) {
+			httperr.Internal(c, "failed_to_load_series", "Erro ao carregar a série.")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series":       series,
+		"appointments": upcoming,
+	})
+}
+
+// ======================================================
+// PATCH /me/appointment-series/:id
+// ======================================================
+
+func (h *AppointmentSeriesHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httperr.BadRequest(c, "invalid_id", "ID inválido.")
+		return
+	}
+
+	var req updateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	result, err := h.updateUC.Execute(c.Request.Context(), appointment.UpdateSeriesInput{
+		BarbershopID:  c.GetUint(middleware.ContextBarbershopID),
+		BarberID:      seriesBarberScope(c),
+		UserID:        c.GetUint(middleware.ContextUserID),
+		SeriesID:      uint(id),
+		Scope:         req.Scope,
+		AppointmentID: req.AppointmentID,
+		Date:          req.Date,
+		Time:          req.Time,
+		ProductID:     req.ProductID,
+		Notes:         req.Notes,
+	})
+	if err != nil {
+		if !writeSeriesError(c, err) {
+			mapCreateErrors(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ======================================================
+// DELETE /me/appointment-series/:id
+// ======================================================
+
+func (h *AppointmentSeriesHandler) Cancel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httperr.BadRequest(c, "invalid_id", "ID inválido.")
+		return
+	}
+
+	result, err := h.cancelUC.Execute(
+		c.Request.Context(),
+		c.GetUint(middleware.ContextBarbershopID),
+		seriesBarberScope(c),
+		c.GetUint(middleware.ContextUserID),
+		uint(id),
+	)
+	if err != nil {
+		if !writeSeriesError(c, err) {
+			httperr.Internal(c, "failed_to_cancel_series", "Erro ao cancelar a série.")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// writeSeriesError responde os erros de negócio próprios da série e retorna
+// false para os demais.
+func writeSeriesError(c *gin.Context, err error) bool {
+	switch {
+	case apperr.IsBusiness(err, "series_not_found"):
+		httperr.NotFound(c, "series_not_found", "Série não encontrada.")
+	case apperr.IsBusiness(err, "appointment_not_found"):
+		httperr.NotFound(c, "appointment_not_found", "Agendamento não encontrado nesta série.")
+	case apperr.IsBusiness(err, "series_cancelled"):
+		httperr.Write(c, http.StatusConflict, "series_cancelled", "Esta série já foi cancelada.")
+	case apperr.IsBusiness(err, "invalid_series_interval"):
+		httperr.BadRequest(c, "invalid_series_interval", "Intervalo inválido (de 1 a 12 semanas).")
+	case apperr.IsBusiness(err, "series_end_required"):
+		httperr.BadRequest(c, "series_end_required", "Informe a data final ou o número de ocorrências.")
+	case apperr.IsBusiness(err, "invalid_series_end"):
+		httperr.BadRequest(c, "invalid_series_end", "Informe apenas uma data final válida ou o número de ocorrências.")
+	case apperr.IsBusiness(err, "series_too_long"):
+		httperr.BadRequest(c, "series_too_long", "A série pode ter no máximo 52 ocorrências.")
+	case apperr.IsBusiness(err, "invalid_series_scope"):
+		httperr.BadRequest(c, "invalid_series_scope", "Escopo inválido. Use: this, following ou all (data só com this).")
+	case apperr.IsBusiness(err, "series_appointment_required"):
+		httperr.BadRequest(c, "series_appointment_required", "Informe o agendamento de referência.")
+	case apperr.IsBusiness(err, "nothing_to_update"):
+		httperr.BadRequest(c, "nothing_to_update", "Nada para alterar.")
+	default:
+		return false
+	}
+	return true
+}
@@ -203,7 +203,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			Email:        email,
 			PasswordHash: string(hashed),
 			Phone:        req.Phone,
-			Role:         "owner",
+			Role:         models.UserRoleOwner,
 		}
 
 		if err := tx.Create(&user).Error; err != nil {
@@ -213,25 +213,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		// -------------------------------
 		// Horários padrão
 		// -------------------------------
-		var workingHours []models.WorkingHours
-
-		for weekday := 0; weekday <= 6; weekday++ {
-			active := weekday >= 1 && weekday <= 5
-
-			wh := models.WorkingHours{
-				BarbershopID: shop.ID,
-				BarberID:     user.ID,
-				Weekday:      weekday,
-				Active:       active,
-			}
-
-			if active {
-				wh.StartTime = "09:00"
-				wh.EndTime = "17:00"
-			}
-
-			workingHours = append(workingHours, wh)
-		}
+		workingHours := defaultWorkingHours(shop.ID, user.ID)
 
 		if err := tx.Create(&workingHours).Error; err != nil {
 			return err
@@ -350,6 +332,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // ======================================================
 
 func (h *AuthHandler) generateToken(user *models.User) (string, error) {
+	return signUserToken(h.config.JWTSecret, user)
+}
+
+// signUserToken emite o JWT de sessão (24h) — compartilhado entre login,
+// registro e aceite de convite de equipe.
+func signUserToken(secret string, user *models.User) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":          user.ID,
 		"barbershopId": user.BarbershopID,
@@ -359,7 +347,7 @@ func (h *AuthHandler) generateToken(user *models.User) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(h.config.JWTSecret))
+	return token.SignedString([]byte(secret))
 }
 
 // ======================================================
@@ -397,6 +385,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if !user.Active {
+		httperr.Unauthorized(c, "account_disabled", "account_disabled")
+		return
+	}
+
 	token, err := h.generateToken(&user)
 	if err != nil {
 		httperr.Internal(c, "failed_to_generate_token", "failed_to_generate_token")
@@ -409,6 +402,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			"name":          user.Name,
 			"email":         user.Email,
 			"phone":         user.Phone,
+			"role":          user.Role,
 			"barbershop_id": user.BarbershopID,
 		},
 		"barbershop": gin.H{
@@ -422,6 +416,32 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// defaultWorkingHours gera o expediente inicial de um barbeiro:
+// segunda a sexta, 09:00–17:00. Usado no registro do dono e no aceite de convite.
+func defaultWorkingHours(barbershopID, barberID uint) []models.WorkingHours {
+	workingHours := make([]models.WorkingHours, 0, 7)
+
+	for weekday := 0; weekday <= 6; weekday++ {
+		active := weekday >= 1 && weekday <= 5
+
+		wh := models.WorkingHours{
+			BarbershopID: barbershopID,
+			BarberID:     barberID,
+			Weekday:      weekday,
+			Active:       active,
+		}
+
+		if active {
+			wh.StartTime = "09:00"
+			wh.EndTime = "17:00"
+		}
+
+		workingHours = append(workingHours, wh)
+	}
+
+	return workingHours
+}
+
 // uniqueSlug garante que o slug seja único no banco.
 // Se o slug base já existe, adiciona um sufixo aleatório de 4 dígitos.
 func uniqueSlug(db *gorm.DB, base string) string {
@@ -47,6 +47,8 @@ type CreateProductRequest struct {
 	Stock         int    `json:"stock"`
 	Active        bool   `json:"active"`
 	OnlineVisible bool   `json:"online_visible"`
+
+	LowStockThreshold *int `json:"low_stock_threshold"`
 }
 
 type UpdateProductRequest struct {
@@ -57,6 +59,9 @@ type UpdateProductRequest struct {
 	Stock         *int    `json:"stock,omitempty"`
 	Active        *bool   `json:"active,omitempty"`
 	OnlineVisible *bool   `json:"online_visible,omitempty"`
+
+	LowStockThreshold   *int `json:"low_stock_threshold,omitempty"`
+	RemoveLowStockAlert bool `json:"remove_low_stock_alert"`
 }
 
 //
@@ -84,6 +89,7 @@ func (h *ProductHandler) List(c *gin.Context) {
 	maxPriceStr := strings.TrimSpace(c.Query("max_price"))
 	minStockStr := strings.TrimSpace(c.Query("min_stock"))
 	maxStockStr := strings.TrimSpace(c.Query("max_stock"))
+	lowStockStr := strings.TrimSpace(c.Query("low_stock"))
 
 	q := h.db.WithContext(c.Request.Context()).
 		Model(&models.Product{}).
@@ -132,6 +138,10 @@ func (h *ProductHandler) List(c *gin.Context) {
 		q = q.Where("stock <= ?", v)
 	}
 
+	if lowStockStr == "true" {
+		q = q.Where("low_stock_threshold IS NOT NULL AND stock <= low_stock_threshold")
+	}
+
 	var products []models.Product
 	if err := q.Order("id ASC").Find(&products).Error; err != nil {
 		httperr.Internal(c, "failed_to_list_products", "failed_to_list_products")
@@ -172,6 +182,8 @@ func (h *ProductHandler) Create(c *gin.Context) {
 			Stock:         req.Stock,
 			Active:        req.Active,
 			OnlineVisible: req.OnlineVisible,
+
+			LowStockThreshold: req.LowStockThreshold,
 		},
 	)
 	if err != nil {
@@ -184,6 +196,8 @@ func (h *ProductHandler) Create(c *gin.Context) {
 			httperr.BadRequest(c, "invalid_price", "invalid_price")
 		case "invalid_stock":
 			httperr.BadRequest(c, "invalid_stock", "invalid_stock")
+		case "invalid_low_stock_threshold":
+			httperr.BadRequest(c, "invalid_low_stock_threshold", "invalid_low_stock_threshold")
 		case "invalid_online_visible_without_stock":
 			httperr.BadRequest(c, "invalid_online_visible_without_stock", "invalid_online_visible_without_stock")
 		default:
@@ -268,6 +282,8 @@ func (h *ProductHandler) Update(c *gin.Context) {
 		return
 	}
 
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
 	var req UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		httperr.BadRequest(c, "invalid_request", err.Error())
@@ -284,6 +300,7 @@ func (h *ProductHandler) Update(c *gin.Context) {
 		productUC.UpdateProductInput{
 			BarbershopID:  barbershopID,
 			ProductID:     uint(idUint),
+			UserID:        &userID,
 			Name:          req.Name,
 			Description:   req.Description,
 			Category:      req.Category,
@@ -291,6 +308,9 @@ func (h *ProductHandler) Update(c *gin.Context) {
 			Stock:         req.Stock,
 			Active:        req.Active,
 			OnlineVisible: req.OnlineVisible,
+
+			LowStockThreshold:   req.LowStockThreshold,
+			RemoveLowStockAlert: req.RemoveLowStockAlert,
 		},
 	)
 	if err != nil {
@@ -303,6 +323,8 @@ func (h *ProductHandler) Update(c *gin.Context) {
 			httperr.BadRequest(c, "invalid_price", "invalid_price")
 		case "invalid_stock":
 			httperr.BadRequest(c, "invalid_stock", "invalid_stock")
+		case "invalid_low_stock_threshold":
+			httperr.BadRequest(c, "invalid_low_stock_threshold", "invalid_low_stock_threshold")
 		case "invalid_online_visible_without_stock":
 			httperr.BadRequest(c, "invalid_online_visible_without_stock", "invalid_online_visible_without_stock")
 		default:
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	domainService "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	serviceUC "github.com/BruksfildServices01/barber-scheduler/internal/usecase/service"
+)
+
+// BarberServiceHandler expõe o catálogo por barbeiro: preço e duração próprios
+// de cada serviço e quais serviços o barbeiro não faz.
+type BarberServiceHandler struct {
+	listUC  *serviceUC.ListBarberServices
+	setUC   *serviceUC.SetBarberService
+	resetUC *serviceUC.ResetBarberService
+}
+
+func NewBarberServiceHandler(
+	listUC *serviceUC.ListBarberServices,
+	setUC *serviceUC.SetBarberService,
+	resetUC *serviceUC.ResetBarberService,
+) *BarberServiceHandler {
+	return &BarberServiceHandler{
+		listUC:  listUC,
+		setUC:   setUC,
+		resetUC: resetUC,
+	}
+}
+
+type barberServiceResponse struct {
+	ServiceID          uint   `json:"service_id"`
+	Name               string `json:"name"`
+	Active             bool   `json:"active"`
+	Offered            bool   `json:"offered"`
+	Customized         bool   `json:"customized"`
+	Price              int64  `json:"price"`
+	DurationMin        int    `json:"duration_min"`
+	DefaultPrice       int64  `json:"default_price"`
+	DefaultDurationMin int    `json:"default_duration_min"`
+}
+
+func toBarberServiceResponse(bs serviceUC.BarberService) barberServiceResponse {
+	return barberServiceResponse{
+		ServiceID:          bs.Service.ID,
+		Name:               bs.Service.Name,
+		Active:             bs.Service.Active,
+		Offered:            bs.Offered,
+		Customized:         bs.Customized,
+		Price:              bs.Service.Price,
+		DurationMin:        bs.Service.DurationMin,
+		DefaultPrice:       bs.Base.Price,
+		DefaultDurationMin: bs.Base.DurationMin,
+	}
+}
+
+type setBarberServiceRequest struct {
+	Offered     *bool  `json:"offered"`      // omitido = true
+	Price       *int64 `json:"price"`        // cents; null = preço da barbearia
+	DurationMin *int   `json:"duration_min"` // null = duração da barbearia
+}
+
+// ======================================================
+// GET /me/barbers/:id/services
+// ======================================================
+
+// List devolve todos os serviços da barbearia com os valores do barbeiro.
+// O barbeiro (role "barber") só consulta o próprio catálogo.
+func (h *BarberServiceHandler) List(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	barberID, ok := parseBarberParam(c)
+	if !ok {
+		return
+	}
+	if !middleware.IsOwner(c) && barberID != c.GetUint(middleware.ContextUserID) {
+		httperr.Write(c, http.StatusForbidden, "forbidden", "Você só pode consultar o seu próprio catálogo.")
+		return
+	}
+
+	items, err := h.listUC.Execute(c.Request.Context(), barbershopID, barberID)
+	if err != nil {
+		writeBarberServiceError(c, err)
+		return
+	}
+
+	resp := make([]barberServiceResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, toBarberServiceResponse(item))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ======================================================
+// PUT /me/barbers/:id/services/:serviceId
+// ======================================================
+
+func (h *BarberServiceHandler) Set(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	barberID, ok := parseBarberParam(c)
+	if !ok {
+		return
+	}
+	serviceID, err := strconv.ParseUint(c.Param("serviceId"), 10, 64)
+	if err != nil || serviceID == 0 {
+		httperr.BadRequest(c, "invalid_service_id", "Serviço inválido.")
+		return
+	}
+
+	var req setBarberServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	offered := true
+	if req.Offered != nil {
+		offered = *req.Offered
+	}
+
+	item, err := h.setUC.Execute(c.Request.Context(), serviceUC.SetBarberServiceInput{
+		BarbershopID: barbershopID,
+		BarberID:     barberID,
+		ServiceID:    uint(serviceID),
+		Offered:      offered,
+		Price:        req.Price,
+		DurationMin:  req.DurationMin,
+	})
+	if err != nil {
+		writeBarberServiceError(c, err)
+		return
+	}
+
+	EvictPublicServicesCache(barbershopID)
+	c.JSON(http.StatusOK, toBarberServiceResponse(*item))
+}
+
+// ======================================================
+// DELETE /me/barbers/:id/services/:serviceId
+// ======================================================
+
+// Reset volta o barbeiro aos valores da barbearia para o serviço.
+func (h *BarberServiceHandler) Reset(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	barberID, ok := parseBarberParam(c)
+	if !ok {
+		return
+	}
+	serviceID, err := strconv.ParseUint(c.Param("serviceId"), 10, 64)
+	if err != nil || serviceID == 0 {
+		httperr.BadRequest(c, "invalid_service_id", "Serviço inválido.")
+		return
+	}
+
+	if err := h.resetUC.Execute(c.Request.Context(), barbershopID, barberID, uint(serviceID)); err != nil {
+		writeBarberServiceError(c, err)
+		return
+	}
+
+	EvictPublicServicesCache(barbershopID)
+	c.Status(http.StatusNoContent)
+}
+
+func parseBarberParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeBarberServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domainService.ErrBarberNotFound):
+		httperr.NotFound(c, "barber_not_found", "Barbeiro não encontrado.")
+	case errors.Is(err, domainService.ErrServiceNotFound):
+		httperr.NotFound(c, "service_not_found", "Serviço não encontrado.")
+	case errors.Is(err, domainService.ErrInvalidPrice):
+		httperr.BadRequest(c, "invalid_price", "Preço inválido.")
+	case errors.Is(err, domainService.ErrInvalidDuration):
+		httperr.BadRequest(c, "invalid_duration", "Duração inválida.")
+	case errors.Is(err, domainService.ErrInvalidContext):
+		httperr.BadRequest(c, "invalid_context", "Contexto inválido.")
+	default:
+		httperr.Internal(c, "failed_to_update_barber_services", "Erro ao processar o catálogo do barbeiro.")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,13 @@ type UpdateBarbershopConfigRequest struct {
 	MinAdvanceMinutes        *int    `json:"min_advance_minutes"`
 	ScheduleToleranceMinutes *int    `json:"schedule_tolerance_minutes"`
 
+	// Lembretes automáticos
+	RemindersEnabled       *bool `json:"reminders_enabled"`
+	ReminderOffsetsMinutes []int `json:"reminder_offsets_minutes"`
+
+	// Atribuição automática de barbeiro ("qualquer barbeiro")
+	BarberAssignmentStrategy *string `json:"barber_assignment_strategy"`
+
 	// Endereço estruturado
 	CEP          *string `json:"cep"`
 	StreetName   *string `json:"street_name"`
@@ -153,6 +161,29 @@ func (h *BarbershopHandler) UpdateMeBarbershop(c *gin.Context) {
 		shop.ScheduleToleranceMinutes = *req.ScheduleToleranceMinutes
 	}
 
+	if req.RemindersEnabled != nil {
+		shop.RemindersEnabled = *req.RemindersEnabled
+	}
+
+	if req.ReminderOffsetsMinutes != nil {
+		offsets, ok := normalizeReminderOffsets(req.ReminderOffsetsMinutes)
+		if !ok {
+			httperr.BadRequest(c, "invalid_reminder_offsets", "Lembretes: informe até 3 antecedências distintas entre 15 minutos e 7 dias.")
+			return
+		}
+		shop.ReminderOffsetsMinutes = offsets
+	}
+
+	if req.BarberAssignmentStrategy != nil {
+		switch strategy := strings.TrimSpace(*req.BarberAssignmentStrategy); strategy {
+		case models.BarberAssignmentLeastLoaded, models.BarberAssignmentRoundRobin, models.BarberAssignmentPreferred:
+			shop.BarberAssignmentStrategy = strategy
+		default:
+			httperr.BadRequest(c, "invalid_barber_assignment_strategy", "Estratégia de atribuição deve ser least_loaded, round_robin ou preferred.")
+			return
+		}
+	}
+
 	if err := h.db.Save(&shop).Error; err != nil {
 		httperr.Internal(c, "failed_to_update_barbershop", "Erro ao salvar as configurações da barbearia.")
 		return
@@ -203,3 +234,31 @@ func (h *BarbershopHandler) UpdateSlug(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"slug": slug})
 }
+
+const (
+	maxReminderOffsets       = 3
+	minReminderOffsetMinutes = 15
+	maxReminderOffsetMinutes = 7 * 24 * 60
+)
+
+// normalizeReminderOffsets valida os offsets de lembrete (em minutos antes do
+// horário) e os devolve sem duplicatas, do maior para o menor.
+func normalizeReminderOffsets(in []int) (models.IntSlice, bool) {
+	if len(in) > maxReminderOffsets {
+		return nil, false
+	}
+	seen := make(map[int]bool, len(in))
+	out := make(models.IntSlice, 0, len(in))
+	for _, m := range in {
+		if m < minReminderOffsetMinutes || m > maxReminderOffsetMinutes {
+			return nil, false
+		}
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		out = append(out, m)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out, true
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucCalendarFeed "github.com/BruksfildServices01/barber-scheduler/internal/usecase/calendarfeed"
+)
+
+// CalendarFeedHandler expõe o feed .ics assinável (Apple Calendar, Outlook):
+// o barbeiro/owner obtém e rotaciona a URL secreta; o calendário do cliente
+// busca o feed pela URL pública.
+type CalendarFeedHandler struct {
+	getUC      *ucCalendarFeed.GetFeed
+	rotateUC   *ucCalendarFeed.RotateFeedToken
+	renderUC   *ucCalendarFeed.RenderFeed
+	backendURL string
+}
+
+func NewCalendarFeedHandler(
+	getUC *ucCalendarFeed.GetFeed,
+	rotateUC *ucCalendarFeed.RotateFeedToken,
+	renderUC *ucCalendarFeed.RenderFeed,
+	backendURL string,
+) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		getUC:      getUC,
+		rotateUC:   rotateUC,
+		renderUC:   renderUC,
+		backendURL: backendURL,
+	}
+}
+
+type calendarFeedResponse struct {
+	Scope     string    `json:"scope"` // "barber" | "shop"
+	URL       string    `json:"url"`
+	WebcalURL string    `json:"webcal_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (h *CalendarFeedHandler) toResponse(feed *models.CalendarFeed) calendarFeedResponse {
+	scope := "barber"
+	if feed.BarberID == nil {
+		scope = "shop"
+	}
+
+	url := h.backendURL + "/api/public/calendar/" + feed.Token + ".ics"
+	webcal := url
+	if i := strings.Index(url, "://"); i >= 0 {
+		webcal = "webcal" + url[i:]
+	}
+
+	return calendarFeedResponse{
+		Scope:     scope,
+		URL:       url,
+		WebcalURL: webcal,
+		UpdatedAt: feed.UpdatedAt,
+	}
+}
+
+// feedOwner: as rotas "/shop" são do feed da barbearia (barberID nil).
+func feedOwner(c *gin.Context, shop bool) (uint, *uint) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	if shop {
+		return barbershopID, nil
+	}
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+	return barbershopID, &userID
+}
+
+// GET /api/me/calendar-feed
+// GET /api/me/calendar-feed/shop (owner)
+func (h *CalendarFeedHandler) get(c *gin.Context, shop bool) {
+	barbershopID, barberID := feedOwner(c, shop)
+
+	feed, err := h.getUC.Execute(c.Request.Context(), barbershopID, barberID)
+	if err != nil {
+		httperr.Internal(c, "calendar_feed_failed", "Erro ao carregar o feed da agenda.")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toResponse(feed))
+}
+
+// POST /api/me/calendar-feed/rotate
+// POST /api/me/calendar-feed/shop/rotate (owner)
+func (h *CalendarFeedHandler) rotate(c *gin.Context, shop bool) {
+	barbershopID, barberID := feedOwner(c, shop)
+
+	feed, err := h.rotateUC.Execute(c.Request.Context(), barbershopID, barberID)
+	if err != nil {
+		httperr.Internal(c, "calendar_feed_rotate_failed", "Erro ao gerar novo link da agenda.")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toResponse(feed))
+}
+
+func (h *CalendarFeedHandler) Get(c *gin.Context)        { h.get(c, false) }
+func (h *CalendarFeedHandler) GetShop(c *gin.Context)    { h.get(c, true) }
+func (h *CalendarFeedHandler) Rotate(c *gin.Context)     { h.rotate(c, false) }
+func (h *CalendarFeedHandler) RotateShop(c *gin.Context) { h.rotate(c, true) }
+
+// Serve entrega o VCALENDAR. Responde 304 quando If-None-Match bate com o ETag.
+// GET /api/public/calendar/:token (com ou sem ".ics")
+func (h *CalendarFeedHandler) Serve(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	feed, err := h.renderUC.Execute(c.Request.Context(), token, time.Now().UTC())
+	if err != nil {
+		if apperr.IsBusiness(err, "calendar_feed_not_found") {
+			httperr.NotFound(c, "calendar_feed_not_found", "Feed não encontrado.")
+			return
+		}
+		httperr.Internal(c, "calendar_feed_failed", "Erro ao gerar o feed da agenda.")
+		return
+	}
+
+	c.Header("ETag", feed.ETag)
+	c.Header("Cache-Control", "private, max-age=300")
+
+	if etagMatches(c.GetHeader("If-None-Match"), feed.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed.Body)
+}
+
+// etagMatches compara If-None-Match (lista separada por vírgula, "*" ou
+// ETags fracos) com o ETag atual.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
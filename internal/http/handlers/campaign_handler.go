@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucCampaign "github.com/BruksfildServices01/barber-scheduler/internal/usecase/campaign"
+)
+
+// CampaignHandler administra as campanhas de reativação (rascunho,
+// agendamento, acompanhamento da entrega) e o descadastro dos clientes.
+type CampaignHandler struct {
+	listUC        *ucCampaign.ListCampaigns
+	getUC         *ucCampaign.GetCampaign
+	createUC      *ucCampaign.CreateCampaign
+	updateUC      *ucCampaign.UpdateCampaign
+	deleteUC      *ucCampaign.DeleteCampaign
+	scheduleUC    *ucCampaign.ScheduleCampaign
+	cancelUC      *ucCampaign.CancelCampaign
+	recipientsUC  *ucCampaign.ListRecipients
+	previewUC     *ucCampaign.PreviewCampaign
+	unsubscribeUC *ucCampaign.Unsubscribe
+	optOutUC      *ucCampaign.SetMarketingOptOut
+}
+
+func NewCampaignHandler(
+	listUC *ucCampaign.ListCampaigns,
+	getUC *ucCampaign.GetCampaign,
+	createUC *ucCampaign.CreateCampaign,
+	updateUC *ucCampaign.UpdateCampaign,
+	deleteUC *ucCampaign.DeleteCampaign,
+	scheduleUC *ucCampaign.ScheduleCampaign,
+	cancelUC *ucCampaign.CancelCampaign,
+	recipientsUC *ucCampaign.ListRecipients,
+	previewUC *ucCampaign.PreviewCampaign,
+	unsubscribeUC *ucCampaign.Unsubscribe,
+	optOutUC *ucCampaign.SetMarketingOptOut,
+) *CampaignHandler {
+	return &CampaignHandler{
+		listUC:        listUC,
+		getUC:         getUC,
+		createUC:      createUC,
+		updateUC:      updateUC,
+		deleteUC:      deleteUC,
+		scheduleUC:    scheduleUC,
+		cancelUC:      cancelUC,
+		recipientsUC:  recipientsUC,
+		previewUC:     previewUC,
+		unsubscribeUC: unsubscribeUC,
+		optOutUC:      optOutUC,
+	}
+}
+
+type CampaignRequest struct {
+	Name      string `json:"name" binding:"required"`
+	SegmentID *uint  `json:"segment_id"`
+	Channel   string `json:"channel" binding:"required"`
+	Subject   string `json:"subject"` // só email
+	Body      string `json:"body" binding:"required"`
+}
+
+type CampaignScheduleRequest struct {
+	// vazio = enviar agora
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+type CampaignPreviewRequest struct {
+	Channel string `json:"channel" binding:"required"`
+	Subject string `json:"subject"`
+	Body    string `json:"body" binding:"required"`
+}
+
+type MarketingOptOutRequest struct {
+	OptOut *bool `json:"opt_out" binding:"required"`
+}
+
+// GET /api/me/campaigns
+func (h *CampaignHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_campaigns", "failed_to_list_campaigns")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"campaigns": list})
+}
+
+// GET /api/me/campaigns/:id
+// Inclui o resumo de entrega e os agendamentos atribuídos.
+func (h *CampaignHandler) Get(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	out, err := h.getUC.Execute(c.Request.Context(), barbershopID, id)
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_get_campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// POST /api/me/campaigns
+func (h *CampaignHandler) Create(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.createUC.Execute(c.Request.Context(), req.input(barbershopID, 0))
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_create_campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// PUT /api/me/campaigns/:id
+func (h *CampaignHandler) Update(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	var req CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.updateUC.Execute(c.Request.Context(), req.input(barbershopID, id))
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_update_campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (r CampaignRequest) input(barbershopID, id uint) ucCampaign.CampaignInput {
+	return ucCampaign.CampaignInput{
+		BarbershopID: barbershopID,
+		ID:           id,
+		Name:         r.Name,
+		SegmentID:    r.SegmentID,
+		Channel:      r.Channel,
+		Subject:      r.Subject,
+		Body:         r.Body,
+	}
+}
+
+// DELETE /api/me/campaigns/:id
+func (h *CampaignHandler) Delete(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUC.Execute(c.Request.Context(), barbershopID, id); err != nil {
+		writeCampaignError(c, err, "failed_to_delete_campaign")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /api/me/campaigns/:id/schedule
+func (h *CampaignHandler) Schedule(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	var req CampaignScheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httperr.BadRequest(c, "invalid_request", err.Error())
+			return
+		}
+	}
+
+	out, err := h.scheduleUC.Execute(c.Request.Context(), ucCampaign.ScheduleCampaignInput{
+		BarbershopID: barbershopID,
+		ID:           id,
+		At:           req.ScheduledAt,
+	})
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_schedule_campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// POST /api/me/campaigns/:id/cancel
+func (h *CampaignHandler) Cancel(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	if err := h.cancelUC.Execute(c.Request.Context(), barbershopID, id); err != nil {
+		writeCampaignError(c, err, "failed_to_cancel_campaign")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /api/me/campaigns/:id/recipients?status=&page=&limit=
+func (h *CampaignHandler) Recipients(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_campaign_id")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	out, err := h.recipientsUC.Execute(c.Request.Context(), ucCampaign.ListRecipientsInput{
+		BarbershopID: barbershopID,
+		CampaignID:   id,
+		Status:       c.Query("status"),
+		Limit:        limit,
+		Offset:       (page - 1) * limit,
+	})
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_list_recipients")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":       page,
+		"limit":      limit,
+		"total":      out.Total,
+		"recipients": out.Recipients,
+	})
+}
+
+// POST /api/me/campaigns/preview
+func (h *CampaignHandler) Preview(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req CampaignPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.previewUC.Execute(c.Request.Context(), ucCampaign.PreviewCampaignInput{
+		BarbershopID: barbershopID,
+		Channel:      req.Channel,
+		Subject:      req.Subject,
+		Body:         req.Body,
+	})
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_preview_campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// PUT /api/me/clients/:id/marketing-opt-out
+// Ajuste manual do descadastro (ex.: cliente pediu no balcão).
+func (h *CampaignHandler) SetOptOut(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var req MarketingOptOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	if err := h.optOutUC.Execute(c.Request.Context(), barbershopID, clientID, *req.OptOut); err != nil {
+		writeCampaignError(c, err, "failed_to_update_opt_out")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"opt_out": *req.OptOut})
+}
+
+// GET /api/public/unsubscribe/:token
+// Página de descadastro: mostra de qual barbearia o cliente sai.
+func (h *CampaignHandler) UnsubscribeInfo(c *gin.Context) {
+	out, err := h.unsubscribeUC.View(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_load_unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// POST /api/public/unsubscribe/:token
+func (h *CampaignHandler) Unsubscribe(c *gin.Context) {
+	out, err := h.unsubscribeUC.Execute(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
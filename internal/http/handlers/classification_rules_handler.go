@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainMetrics "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
+)
+
+// ClassificationRulesHandler administra as regras de classificação de
+// clientes da barbearia e a simulação antes de salvar.
+type ClassificationRulesHandler struct {
+	getUC      *ucMetrics.GetClassificationRules
+	updateUC   *ucMetrics.UpdateClassificationRules
+	simulateUC *ucMetrics.SimulateClassification
+}
+
+func NewClassificationRulesHandler(
+	getUC *ucMetrics.GetClassificationRules,
+	updateUC *ucMetrics.UpdateClassificationRules,
+	simulateUC *ucMetrics.SimulateClassification,
+) *ClassificationRulesHandler {
+	return &ClassificationRulesHandler{
+		getUC:      getUC,
+		updateUC:   updateUC,
+		simulateUC: simulateUC,
+	}
+}
+
+type ClassificationRulesRequest struct {
+	Rules domainMetrics.ClassificationRules `json:"rules" binding:"required"`
+}
+
+var classificationRuleMessages = map[error]string{
+	domainMetrics.ErrInvalidRuleCategory:   "Categoria inválida: use letras minúsculas, números e _ (2 a 30 caracteres).",
+	domainMetrics.ErrReservedRuleCategory:  "A categoria new é reservada aos clientes sem histórico.",
+	domainMetrics.ErrRuleWithoutConditions: "A regra precisa de ao menos uma condição.",
+	domainMetrics.ErrInvalidRuleThreshold:  "Limite fora do intervalo permitido.",
+}
+
+// writeClassificationRulesError traduz os erros de validação das regras.
+func writeClassificationRulesError(c *gin.Context, err error, fallback string) {
+	var ruleErr *domainMetrics.RuleError
+	switch {
+	case errors.As(err, &ruleErr):
+		// a mensagem aponta a regra recusada
+		msg := fmt.Sprintf("Regra %d: %s", ruleErr.Position, classificationRuleMessages[ruleErr.Err])
+		httperr.BadRequest(c, ruleErr.Err.Error(), msg)
+	case errors.Is(err, domainMetrics.ErrTooManyRules):
+		httperr.BadRequest(c, err.Error(), fmt.Sprintf("Máximo de %d regras.", domainMetrics.MaxClassificationRules))
+	case errors.Is(err, ucMetrics.ErrInvalidBarbershop):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// GET /api/me/classification-rules
+func (h *ClassificationRulesHandler) Get(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	view, err := h.getUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_classification_rules", "failed_to_load_classification_rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// PUT /api/me/classification-rules
+func (h *ClassificationRulesHandler) Update(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req ClassificationRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	if err := h.updateUC.Execute(c.Request.Context(), ucMetrics.UpdateClassificationRulesInput{
+		BarbershopID: barbershopID,
+		Rules:        req.Rules,
+	}); err != nil {
+		writeClassificationRulesError(c, err, "failed_to_save_classification_rules")
+		return
+	}
+
+	h.Get(c)
+}
+
+// DELETE /api/me/classification-rules (volta às regras padrão)
+func (h *ClassificationRulesHandler) Reset(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	if err := h.updateUC.Execute(c.Request.Context(), ucMetrics.UpdateClassificationRulesInput{
+		BarbershopID: barbershopID,
+	}); err != nil {
+		writeClassificationRulesError(c, err, "failed_to_reset_classification_rules")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /api/me/classification-rules/simulate
+func (h *ClassificationRulesHandler) Simulate(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req ClassificationRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.simulateUC.Execute(c.Request.Context(), barbershopID, req.Rules)
+	if err != nil {
+		writeClassificationRulesError(c, err, "failed_to_simulate_classification")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
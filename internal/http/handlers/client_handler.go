@@ -18,8 +18,9 @@ import (
 
 type ClientListItemResponse struct {
 	models.Client
-	Category string `json:"category"`
-	Premium  bool   `json:"premium"`
+	Category string             `json:"category"`
+	Premium  bool               `json:"premium"`
+	Tags     []models.ClientTag `json:"tags"`
 }
 
 type ClientListResponse struct {
@@ -60,6 +61,16 @@ func (h *ClientHandler) List(c *gin.Context) {
 	categoryFilter := c.Query("category")
 	premiumFilter := c.Query("premium") == "true"
 
+	var tagFilter uint64
+	if raw := c.Query("tag"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_tag_id"})
+			return
+		}
+		tagFilter = id
+	}
+
 	page := 1
 	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
 		page = p
@@ -98,10 +109,11 @@ func (h *ClientHandler) List(c *gin.Context) {
 	categoryCounts["premium"] = int(premiumCount)
 
 	// 2. Base query — filtros via subquery, sem carregar IDs em memória.
-	// Clientes anonimizados são excluídos por padrão (LGPD — dados pessoais removidos).
+	// Clientes anonimizados são excluídos por padrão (LGPD — dados pessoais removidos),
+	// assim como cadastros juntados a outro.
 	q := h.db.WithContext(ctx).Model(&models.Client{}).
 		Where("barbershop_id = ?", barbershopID).
-		Where("anonymized_at IS NULL")
+		Where("anonymized_at IS NULL AND merged_into_id IS NULL")
 
 	if query != "" {
 		like := "%" + query + "%"
@@ -121,6 +133,13 @@ func (h *ClientHandler) List(c *gin.Context) {
 		)`, barbershopID, categoryFilter)
 	}
 
+	if tagFilter != 0 {
+		q = q.Where(`id IN (
+			SELECT client_id FROM client_tag_assignments
+			WHERE barbershop_id = ? AND tag_id = ?
+		)`, barbershopID, tagFilter)
+	}
+
 	// 3. Count.
 	var total int64
 	if err := q.Count(&total).Error; err != nil {
@@ -145,7 +164,7 @@ func (h *ClientHandler) List(c *gin.Context) {
 		return
 	}
 
-	// 5. Carrega category e premium APENAS para os IDs desta página.
+	// 5. Carrega category, premium e etiquetas APENAS para os IDs desta página.
 	pageIDs := make([]uint, len(clients))
 	for i, cl := range clients {
 		pageIDs[i] = cl.ID
@@ -176,13 +195,33 @@ func (h *ClientHandler) List(c *gin.Context) {
 		premiumSet[id] = true
 	}
 
+	var tagRows []struct {
+		models.ClientTag
+		ClientID uint `gorm:"column:client_id"`
+	}
+	h.db.WithContext(ctx).Raw(`
+		SELECT t.*, a.client_id FROM client_tag_assignments a
+		JOIN client_tags t ON t.id = a.tag_id
+		WHERE a.barbershop_id = ? AND a.client_id IN ?
+		ORDER BY LOWER(t.name), t.id
+	`, barbershopID, pageIDs).Scan(&tagRows)
+	tagsByClient := make(map[uint][]models.ClientTag)
+	for _, r := range tagRows {
+		tagsByClient[r.ClientID] = append(tagsByClient[r.ClientID], r.ClientTag)
+	}
+
 	// 6. Monta resposta.
 	out := make([]ClientListItemResponse, 0, len(clients))
 	for _, client := range clients {
+		tags := tagsByClient[client.ID]
+		if tags == nil {
+			tags = []models.ClientTag{}
+		}
 		out = append(out, ClientListItemResponse{
 			Client:   client,
 			Category: categoryByClient[client.ID],
 			Premium:  premiumSet[client.ID],
+			Tags:     tags,
 		})
 	}
 
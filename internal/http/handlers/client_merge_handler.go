@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucClient "github.com/BruksfildServices01/barber-scheduler/internal/usecase/client"
+)
+
+type ClientMergeHandler struct {
+	duplicates *ucClient.FindDuplicates
+	merge      *ucClient.MergeClients
+}
+
+func NewClientMergeHandler(
+	duplicates *ucClient.FindDuplicates,
+	merge *ucClient.MergeClients,
+) *ClientMergeHandler {
+	return &ClientMergeHandler{duplicates: duplicates, merge: merge}
+}
+
+// Duplicates sugere pares de cadastros que parecem ser a mesma pessoa.
+// GET /api/me/clients/duplicates?limit=
+func (h *ClientMergeHandler) Duplicates(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	pairs, err := h.duplicates.Execute(c.Request.Context(), barbershopID, limit)
+	if err != nil {
+		httperr.Internal(c, "internal_error", "Erro ao buscar duplicados.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": pairs})
+}
+
+type mergeClientsRequest struct {
+	SourceClientID uint `json:"source_client_id" binding:"required"`
+}
+
+// Merge junta source_client_id no cliente da URL, que é o mantido.
+// POST /api/me/clients/:id/merge
+func (h *ClientMergeHandler) Merge(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	targetID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var req mergeClientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "source_client_id é obrigatório.")
+		return
+	}
+
+	merge, err := h.merge.Execute(c.Request.Context(), barbershopID, targetID, req.SourceClientID, userID)
+	if err != nil {
+		writeClientMergeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, merge)
+}
+
+// ListMerges lista as junções feitas, para conferir ou desfazer.
+// GET /api/me/clients/merges
+func (h *ClientMergeHandler) ListMerges(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	list, err := h.merge.List(c.Request.Context(), barbershopID, limit)
+	if err != nil {
+		httperr.Internal(c, "internal_error", "Erro ao listar junções.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// Undo desfaz uma junção.
+// POST /api/me/clients/merges/:id/undo
+func (h *ClientMergeHandler) Undo(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	mergeID, ok := parseIDParam(c, "invalid_merge_id")
+	if !ok {
+		return
+	}
+
+	merge, err := h.merge.Undo(c.Request.Context(), barbershopID, mergeID, userID)
+	if err != nil {
+		writeClientMergeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, merge)
+}
+
+func writeClientMergeError(c *gin.Context, err error) {
+	switch {
+	case apperr.IsBusiness(err, "client_not_found"):
+		httperr.NotFound(c, "client_not_found", "Cliente não encontrado.")
+	case apperr.IsBusiness(err, "merge_not_found"):
+		httperr.NotFound(c, "merge_not_found", "Junção não encontrada.")
+	case apperr.IsBusiness(err, "same_client"):
+		httperr.BadRequest(c, "same_client", "Escolha dois cadastros diferentes.")
+	case apperr.IsBusiness(err, "client_anonymized"):
+		httperr.Write(c, http.StatusConflict, "client_anonymized", "Os dados deste cliente foram removidos a pedido do titular.")
+	case apperr.IsBusiness(err, "client_already_merged"):
+		httperr.Write(c, http.StatusConflict, "client_already_merged", "Um dos cadastros já foi juntado a outro.")
+	case apperr.IsBusiness(err, "merge_already_undone"):
+		httperr.Write(c, http.StatusConflict, "merge_already_undone", "Esta junção já foi desfeita.")
+	case apperr.IsBusiness(err, "merge_not_reversible"):
+		httperr.Write(c, http.StatusConflict, "merge_not_reversible", "O cadastro juntado mudou desde a junção.")
+	case apperr.IsBusiness(err, "both_have_subscriptions"):
+		httperr.Write(c, http.StatusUnprocessableEntity, "both_have_subscriptions", "Os dois cadastros têm assinatura em aberto; cancele uma antes de juntar.")
+	default:
+		httperr.Internal(c, "internal_error", "Erro ao processar junção.")
+	}
+}
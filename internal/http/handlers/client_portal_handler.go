@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	domainPortal "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	clienthistory "github.com/BruksfildServices01/barber-scheduler/internal/query/client_history"
+	qPortal "github.com/BruksfildServices01/barber-scheduler/internal/query/clientportal"
+	ucPortal "github.com/BruksfildServices01/barber-scheduler/internal/usecase/clientportal"
+)
+
+// ClientPortalHandler é o portal do cliente: login sem senha por código e a
+// área /me com agendamentos, assinatura, pacotes, pedidos e dados de
+// contato.
+type ClientPortalHandler struct {
+	db              *gorm.DB
+	requestCodeUC   *ucPortal.RequestCode
+	verifyCodeUC    *ucPortal.VerifyCode
+	sessions        *ucPortal.Sessions
+	updateContactUC *ucPortal.UpdateContact
+	portal          *qPortal.Query
+	history         *clienthistory.Service
+}
+
+func NewClientPortalHandler(
+	db *gorm.DB,
+	requestCodeUC *ucPortal.RequestCode,
+	verifyCodeUC *ucPortal.VerifyCode,
+	sessions *ucPortal.Sessions,
+	updateContactUC *ucPortal.UpdateContact,
+	portal *qPortal.Query,
+	history *clienthistory.Service,
+) *ClientPortalHandler {
+	return &ClientPortalHandler{
+		db:              db,
+		requestCodeUC:   requestCodeUC,
+		verifyCodeUC:    verifyCodeUC,
+		sessions:        sessions,
+		updateContactUC: updateContactUC,
+		portal:          portal,
+		history:         history,
+	}
+}
+
+type ClientLoginCodeRequest struct {
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+}
+
+type ClientLoginVerifyRequest struct {
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+	Code  string `json:"code" binding:"required"`
+}
+
+type ClientContactRequest struct {
+	Name  *string `json:"name"`
+	Phone *string `json:"phone"`
+	Email *string `json:"email"`
+}
+
+// ClientProfileDTO é o cliente logado com o resumo do histórico.
+type ClientProfileDTO struct {
+	ID      uint                            `json:"id"`
+	Name    string                          `json:"name"`
+	Phone   string                          `json:"phone"`
+	Email   string                          `json:"email"`
+	History *clienthistory.ClientHistoryDTO `json:"history,omitempty"`
+}
+
+func clientProfile(c *models.Client) ClientProfileDTO {
+	return ClientProfileDTO{ID: c.ID, Name: c.Name, Phone: c.Phone, Email: c.Email}
+}
+
+// writeClientPortalError responde os erros do login e da edição de contato
+// e retorna false para os demais.
+func writeClientPortalError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domainPortal.ErrLoginTargetRequired):
+		httperr.BadRequest(c, "login_target_required", "Informe o telefone ou o email.")
+	case errors.Is(err, domainPortal.ErrChannelUnavailable):
+		httperr.BadRequest(c, "login_channel_unavailable", "Este canal de envio não está disponível.")
+	case errors.Is(err, domainPortal.ErrInvalidCode):
+		httperr.Write(c, http.StatusUnauthorized, "login_code_invalid", "Código inválido ou expirado.")
+	case errors.Is(err, domainPortal.ErrTooManyAttempts):
+		httperr.Write(c, http.StatusTooManyRequests, "login_code_attempts_exceeded", "Muitas tentativas. Peça um novo código.")
+	case errors.Is(err, domainPortal.ErrSessionInvalid):
+		httperr.Write(c, http.StatusUnauthorized, "client_session_invalid", "Sessão inválida.")
+	case errors.Is(err, domainPortal.ErrPhoneTaken):
+		httperr.Write(c, http.StatusConflict, "client_phone_taken", "Telefone já cadastrado para outro cliente.")
+	case errors.Is(err, ucPortal.ErrInvalidName),
+		errors.Is(err, ucPortal.ErrInvalidPhone),
+		errors.Is(err, ucPortal.ErrInvalidEmail):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// portalClient lê o cliente e a barbearia da sessão do portal.
+func portalClient(c *gin.Context) (barbershopID, clientID uint) {
+	return c.MustGet(middleware.ContextBarbershopID).(uint),
+		c.MustGet(middleware.ContextClientID).(uint)
+}
+
+// ──────────────────────────────────────────────────────────────────
+// POST /api/public/:slug/me/login/code
+// ──────────────────────────────────────────────────────────────────
+
+// RequestCode responde 202 haja ou não cliente com o contato, para não
+// revelar quem tem cadastro.
+func (h *ClientPortalHandler) RequestCode(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req ClientLoginCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	err := h.requestCodeUC.Execute(c.Request.Context(), ucPortal.RequestCodeInput{
+		BarbershopID:   shop.ID,
+		BarbershopName: shop.Name,
+		Phone:          req.Phone,
+		Email:          req.Email,
+	})
+	if err != nil {
+		if writeClientPortalError(c, err) {
+			return
+		}
+		log.Printf("[CLIENT_PORTAL] request code failed barbershop=%d: %v", shop.ID, err)
+		httperr.Internal(c, "failed_to_request_login_code", "Erro ao gerar o código de acesso.")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "code_sent_if_registered"})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// POST /api/public/:slug/me/login/verify
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) VerifyCode(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req ClientLoginVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.verifyCodeUC.Execute(c.Request.Context(), ucPortal.VerifyCodeInput{
+		BarbershopID: shop.ID,
+		Phone:        req.Phone,
+		Email:        req.Email,
+		Code:         req.Code,
+	})
+	if err != nil {
+		if writeClientPortalError(c, err) {
+			return
+		}
+		httperr.Internal(c, "failed_to_verify_login_code", "Erro ao validar o código.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      out.Token,
+		"expires_at": out.ExpiresAt,
+		"client":     clientProfile(out.Client),
+	})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// POST /api/public/:slug/me/logout
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Logout(c *gin.Context) {
+	sessionID := c.MustGet(middleware.ContextClientSessionID).(uint)
+
+	if err := h.sessions.Logout(c.Request.Context(), sessionID); err != nil {
+		httperr.Internal(c, "failed_to_logout", "Erro ao encerrar a sessão.")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/me
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Me(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	var client models.Client
+	err := h.db.WithContext(c.Request.Context()).
+		Where("id = ? AND barbershop_id = ?", clientID, barbershopID).
+		First(&client).Error
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_client", "Erro ao carregar seus dados.")
+		return
+	}
+
+	out := clientProfile(&client)
+
+	// O histórico é complementar: sem ele o portal ainda mostra os dados.
+	history, err := h.history.GetClientHistory(c.Request.Context(), int64(barbershopID), int64(clientID))
+	if err != nil {
+		log.Printf("[CLIENT_PORTAL] failed to load history client=%d: %v", clientID, err)
+	} else {
+		out.History = history
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// ──────────────────────────────────────────────────────────────────
+// PUT /api/public/:slug/me
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) UpdateContact(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	var req ClientContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	client, err := h.updateContactUC.Execute(c.Request.Context(), ucPortal.UpdateContactInput{
+		BarbershopID: barbershopID,
+		ClientID:     clientID,
+		Name:         req.Name,
+		Phone:        req.Phone,
+		Email:        req.Email,
+	})
+	if err != nil {
+		if writeClientPortalError(c, err) {
+			return
+		}
+		httperr.Internal(c, "failed_to_update_contact", "Erro ao atualizar seus dados.")
+		return
+	}
+
+	c.JSON(http.StatusOK, clientProfile(client))
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/me/appointments?scope=upcoming|past&limit=
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Appointments(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	scope := c.DefaultQuery("scope", qPortal.ScopeUpcoming)
+	if scope != qPortal.ScopeUpcoming && scope != qPortal.ScopePast {
+		httperr.BadRequest(c, "invalid_scope", "scope deve ser upcoming ou past.")
+		return
+	}
+
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			httperr.BadRequest(c, "invalid_limit", "limit deve estar entre 1 e 100.")
+			return
+		}
+		limit = n
+	}
+
+	list, err := h.portal.Appointments(c.Request.Context(), barbershopID, clientID, scope, limit)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_appointments", "Erro ao listar agendamentos.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appointments": list})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/me/subscription
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Subscription(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	sub, err := h.portal.Subscription(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_subscription", "Erro ao carregar a assinatura.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/me/packages
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Packages(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	list, err := h.portal.Packages(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_packages", "Erro ao listar pacotes.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"packages": list})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/me/orders
+// ──────────────────────────────────────────────────────────────────
+
+func (h *ClientPortalHandler) Orders(c *gin.Context) {
+	barbershopID, clientID := portalClient(c)
+
+	list, err := h.portal.Orders(c.Request.Context(), barbershopID, clientID, 50)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_orders", "Erro ao listar pedidos.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"orders": list})
+}
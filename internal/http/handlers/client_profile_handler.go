@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	domainClient "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucProfile "github.com/BruksfildServices01/barber-scheduler/internal/usecase/clientprofile"
+)
+
+// ClientProfileHandler cuida do perfil do cliente usado no atendimento:
+// anotações da equipe, etiquetas, preferências e fotos de antes/depois.
+type ClientProfileHandler struct {
+	listNotesUC   *ucProfile.ListClientNotes
+	saveNoteUC    *ucProfile.SaveClientNote
+	deleteNoteUC  *ucProfile.DeleteClientNote
+	listTagsUC    *ucProfile.ListTags
+	saveTagUC     *ucProfile.SaveTag
+	deleteTagUC   *ucProfile.DeleteTag
+	setTagsUC     *ucProfile.SetClientTags
+	getPrefsUC    *ucProfile.GetClientPreferences
+	savePrefsUC   *ucProfile.SaveClientPreferences
+	listPhotosUC  *ucProfile.ListClientPhotos
+	addPhotoUC    *ucProfile.AddClientPhoto
+	deletePhotoUC *ucProfile.DeleteClientPhoto
+}
+
+func NewClientProfileHandler(
+	listNotesUC *ucProfile.ListClientNotes,
+	saveNoteUC *ucProfile.SaveClientNote,
+	deleteNoteUC *ucProfile.DeleteClientNote,
+	listTagsUC *ucProfile.ListTags,
+	saveTagUC *ucProfile.SaveTag,
+	deleteTagUC *ucProfile.DeleteTag,
+	setTagsUC *ucProfile.SetClientTags,
+	getPrefsUC *ucProfile.GetClientPreferences,
+	savePrefsUC *ucProfile.SaveClientPreferences,
+	listPhotosUC *ucProfile.ListClientPhotos,
+	addPhotoUC *ucProfile.AddClientPhoto,
+	deletePhotoUC *ucProfile.DeleteClientPhoto,
+) *ClientProfileHandler {
+	return &ClientProfileHandler{
+		listNotesUC:   listNotesUC,
+		saveNoteUC:    saveNoteUC,
+		deleteNoteUC:  deleteNoteUC,
+		listTagsUC:    listTagsUC,
+		saveTagUC:     saveTagUC,
+		deleteTagUC:   deleteTagUC,
+		setTagsUC:     setTagsUC,
+		getPrefsUC:    getPrefsUC,
+		savePrefsUC:   savePrefsUC,
+		listPhotosUC:  listPhotosUC,
+		addPhotoUC:    addPhotoUC,
+		deletePhotoUC: deletePhotoUC,
+	}
+}
+
+type ClientNoteRequest struct {
+	Body   string `json:"body" binding:"required"`
+	Pinned bool   `json:"pinned"`
+}
+
+type ClientTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+type ClientTagsRequest struct {
+	TagIDs []uint `json:"tag_ids"`
+}
+
+func writeClientProfileError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domainClient.ErrInvalidNote):
+		httperr.BadRequest(c, domainClient.ErrInvalidNote.Error(), err.Error())
+	case errors.Is(err, domainClient.ErrInvalidTag):
+		// a mensagem aponta o campo recusado
+		httperr.BadRequest(c, domainClient.ErrInvalidTag.Error(), err.Error())
+	case errors.Is(err, domainClient.ErrInvalidPreferences):
+		httperr.BadRequest(c, domainClient.ErrInvalidPreferences.Error(), err.Error())
+	case errors.Is(err, ucProfile.ErrInvalidBarbershop),
+		errors.Is(err, ucProfile.ErrInvalidTags),
+		errors.Is(err, ucProfile.ErrInvalidPreferenceRefs),
+		errors.Is(err, ucProfile.ErrInvalidPhotoKind):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucProfile.ErrClientNotFound),
+		errors.Is(err, ucProfile.ErrNoteNotFound),
+		errors.Is(err, ucProfile.ErrTagNotFound),
+		errors.Is(err, ucProfile.ErrAppointmentNotFound),
+		errors.Is(err, ucProfile.ErrPhotoNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	case errors.Is(err, ucProfile.ErrNoteForbidden):
+		httperr.Write(c, http.StatusForbidden, err.Error(), err.Error())
+	case errors.Is(err, ucProfile.ErrTagNameTaken),
+		errors.Is(err, ucProfile.ErrTooManyTags),
+		errors.Is(err, ucProfile.ErrTooManyPhotos):
+		httperr.Write(c, http.StatusConflict, err.Error(), err.Error())
+	case errors.Is(err, ucProfile.ErrStorageUnavailable):
+		httperr.Write(c, http.StatusServiceUnavailable, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// parseSubIDParam lê um id de rota que não é o :id do cliente.
+func parseSubIDParam(c *gin.Context, name, code string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		httperr.BadRequest(c, code, code)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ======================================================
+// ANOTAÇÕES
+// ======================================================
+
+// GET /api/me/clients/:id/notes
+func (h *ClientProfileHandler) ListNotes(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	list, err := h.listNotesUC.Execute(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_list_notes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notes": list})
+}
+
+// POST /api/me/clients/:id/notes
+func (h *ClientProfileHandler) CreateNote(c *gin.Context) {
+	h.saveNote(c, 0, http.StatusCreated)
+}
+
+// PUT /api/me/clients/:id/notes/:noteId
+// Só quem escreveu ou o dono podem editar.
+func (h *ClientProfileHandler) UpdateNote(c *gin.Context) {
+	noteID, ok := parseSubIDParam(c, "noteId", "invalid_note_id")
+	if !ok {
+		return
+	}
+	h.saveNote(c, noteID, http.StatusOK)
+}
+
+func (h *ClientProfileHandler) saveNote(c *gin.Context, noteID uint, status int) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var req ClientNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	note, err := h.saveNoteUC.Execute(c.Request.Context(), ucProfile.SaveClientNoteInput{
+		BarbershopID: barbershopID,
+		ClientID:     clientID,
+		ID:           noteID,
+		UserID:       userID,
+		IsOwner:      c.GetString(middleware.ContextUserRole) == "owner",
+		Body:         req.Body,
+		Pinned:       req.Pinned,
+	})
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_save_note")
+		return
+	}
+
+	c.JSON(status, note)
+}
+
+// DELETE /api/me/clients/:id/notes/:noteId
+func (h *ClientProfileHandler) DeleteNote(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+	noteID, ok := parseSubIDParam(c, "noteId", "invalid_note_id")
+	if !ok {
+		return
+	}
+
+	isOwner := c.GetString(middleware.ContextUserRole) == "owner"
+	if err := h.deleteNoteUC.Execute(c.Request.Context(), barbershopID, clientID, noteID, userID, isOwner); err != nil {
+		writeClientProfileError(c, err, "failed_to_delete_note")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ======================================================
+// ETIQUETAS
+// ======================================================
+
+// GET /api/me/client-tags
+func (h *ClientProfileHandler) ListTags(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listTagsUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_tags", "failed_to_list_tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": list})
+}
+
+// POST /api/me/client-tags
+func (h *ClientProfileHandler) CreateTag(c *gin.Context) {
+	h.saveTag(c, 0, http.StatusCreated)
+}
+
+// PUT /api/me/client-tags/:id
+func (h *ClientProfileHandler) UpdateTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "invalid_tag_id")
+	if !ok {
+		return
+	}
+	h.saveTag(c, id, http.StatusOK)
+}
+
+func (h *ClientProfileHandler) saveTag(c *gin.Context, id uint, status int) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req ClientTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	tag, err := h.saveTagUC.Execute(c.Request.Context(), ucProfile.SaveTagInput{
+		BarbershopID: barbershopID,
+		ID:           id,
+		Name:         req.Name,
+		Color:        req.Color,
+	})
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_save_tag")
+		return
+	}
+
+	c.JSON(status, tag)
+}
+
+// DELETE /api/me/client-tags/:id
+// Tira a etiqueta de todos os clientes.
+func (h *ClientProfileHandler) DeleteTag(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_tag_id")
+	if !ok {
+		return
+	}
+
+	if err := h.deleteTagUC.Execute(c.Request.Context(), barbershopID, id); err != nil {
+		writeClientProfileError(c, err, "failed_to_delete_tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PUT /api/me/clients/:id/tags
+// Substitui as etiquetas do cliente; lista vazia tira todas.
+func (h *ClientProfileHandler) SetClientTags(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var req ClientTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	tags, err := h.setTagsUC.Execute(c.Request.Context(), barbershopID, clientID, req.TagIDs)
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_set_client_tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// ======================================================
+// PREFERÊNCIAS
+// ======================================================
+
+// GET /api/me/clients/:id/preferences
+func (h *ClientProfileHandler) GetPreferences(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	prefs, err := h.getPrefsUC.Execute(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_get_preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// PUT /api/me/clients/:id/preferences
+func (h *ClientProfileHandler) UpdatePreferences(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var req domainClient.Preferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	prefs, err := h.savePrefsUC.Execute(c.Request.Context(), barbershopID, clientID, userID, req)
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_save_preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// ======================================================
+// FOTOS
+// ======================================================
+
+// GET /api/me/clients/:id/photos?appointment_id=
+func (h *ClientProfileHandler) ListPhotos(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	var appointmentID uint
+	if raw := c.Query("appointment_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			httperr.BadRequest(c, "invalid_appointment_id", "invalid_appointment_id")
+			return
+		}
+		appointmentID = uint(id)
+	}
+
+	list, err := h.listPhotosUC.Execute(c.Request.Context(), barbershopID, clientID, appointmentID)
+	if err != nil {
+		writeClientProfileError(c, err, "failed_to_list_photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"photos": list})
+}
+
+// POST /api/me/clients/:id/photos
+// Multipart: photo (arquivo), appointment_id e kind (before|after).
+func (h *ClientProfileHandler) UploadPhoto(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	appointmentID, err := strconv.ParseUint(c.PostForm("appointment_id"), 10, 64)
+	if err != nil || appointmentID == 0 {
+		httperr.BadRequest(c, "invalid_appointment_id", "invalid_appointment_id")
+		return
+	}
+
+	raw, _, err := readImageFromRequest(c, "photo")
+	if err != nil {
+		httperr.BadRequest(c, err.Error(), err.Error())
+		return
+	}
+
+	photo, err := h.addPhotoUC.Execute(c.Request.Context(), ucProfile.AddClientPhotoInput{
+		BarbershopID:  barbershopID,
+		ClientID:      clientID,
+		AppointmentID: uint(appointmentID),
+		UserID:        userID,
+		Kind:          c.PostForm("kind"),
+		Raw:           raw,
+	})
+	if err != nil {
+		writeClientProfileError(c, err, "upload_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// DELETE /api/me/clients/:id/photos/:photoId
+func (h *ClientProfileHandler) DeletePhoto(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+	photoID, ok := parseSubIDParam(c, "photoId", "invalid_photo_id")
+	if !ok {
+		return
+	}
+
+	if err := h.deletePhotoUC.Execute(c.Request.Context(), barbershopID, clientID, photoID); err != nil {
+		writeClientProfileError(c, err, "failed_to_delete_photo")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
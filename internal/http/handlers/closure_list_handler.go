@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -46,7 +47,7 @@ type ClosureDetailResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// List handles GET /api/me/closures?page=&limit=
+// List handles GET /api/me/closures?page=&limit=&barber_id=
 func (h *ClosureListHandler) List(c *gin.Context) {
 	barbershopID := c.GetUint(middleware.ContextBarbershopID)
 	if barbershopID == 0 {
@@ -61,6 +62,12 @@ func (h *ClosureListHandler) List(c *gin.Context) {
 	}
 	offset := (page - 1) * limit
 
+	barberID, ok := closureBarberFilter(c)
+	if !ok {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return
+	}
+
 	type row struct {
 		ID            uint      `gorm:"column:id"`
 		ServiceName   string    `gorm:"column:service_name"`
@@ -77,6 +84,7 @@ func (h *ClosureListHandler) List(c *gin.Context) {
 		Table("appointment_closures ac").
 		Joins("JOIN appointments a ON a.id = ac.appointment_id").
 		Where("ac.barbershop_id = ?", barbershopID).
+		Where("(? = 0 OR a.barber_id = ?)", barberID, barberID).
 		Count(&total).Error; err != nil {
 		httperr.Internal(c, "failed_to_count_closures", "Falha ao contar atendimentos.")
 		return
@@ -97,9 +105,10 @@ func (h *ClosureListHandler) List(c *gin.Context) {
 		JOIN appointments a ON a.id = ac.appointment_id
 		LEFT JOIN clients c ON c.id = a.client_id
 		WHERE ac.barbershop_id = ?
+		  AND (? = 0 OR a.barber_id = ?)
 		ORDER BY a.start_time DESC, ac.id DESC
 		LIMIT ? OFFSET ?
-	`, barbershopID, limit, offset).Scan(&rows).Error
+	`, barbershopID, barberID, barberID, limit, offset).Scan(&rows).Error
 	if err != nil {
 		httperr.Internal(c, "failed_to_list_closures", "Falha ao listar atendimentos.")
 		return
@@ -147,6 +156,12 @@ func (h *ClosureListHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	// Barbeiro só enxerga os próprios atendimentos.
+	var barberID uint
+	if !middleware.IsOwner(c) {
+		barberID = c.GetUint(middleware.ContextUserID)
+	}
+
 	type detailRow struct {
 		ID              uint      `gorm:"column:id"`
 		AppointmentID   uint      `gorm:"column:appointment_id"`
@@ -181,8 +196,9 @@ func (h *ClosureListHandler) GetByID(c *gin.Context) {
 		JOIN appointments a ON a.id = ac.appointment_id
 		LEFT JOIN clients c ON c.id = a.client_id
 		WHERE ac.id = ? AND ac.barbershop_id = ?
+		  AND (? = 0 OR a.barber_id = ?)
 		LIMIT 1
-	`, id64, barbershopID).Scan(&r).Error
+	`, id64, barbershopID, barberID, barberID).Scan(&r).Error
 	if err != nil {
 		httperr.Internal(c, "failed_to_get_closure", "Falha ao buscar atendimento.")
 		return
@@ -214,3 +230,21 @@ func (h *ClosureListHandler) GetByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// closureBarberFilter retorna o barbeiro usado para filtrar os atendimentos
+// (0 = todos). Barbeiros só veem os próprios; o dono pode filtrar via ?barber_id=.
+func closureBarberFilter(c *gin.Context) (uint, bool) {
+	if !middleware.IsOwner(c) {
+		return c.GetUint(middleware.ContextUserID), true
+	}
+
+	raw := strings.TrimSpace(c.Query("barber_id"))
+	if raw == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return uint(v), true
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucCoupon "github.com/BruksfildServices01/barber-scheduler/internal/usecase/coupon"
+)
+
+// CouponHandler administra os cupons de desconto da barbearia.
+type CouponHandler struct {
+	createUC          *ucCoupon.CreateCoupon
+	updateUC          *ucCoupon.UpdateCoupon
+	listUC            *ucCoupon.ListCoupons
+	listRedemptionsUC *ucCoupon.ListRedemptions
+}
+
+func NewCouponHandler(
+	createUC *ucCoupon.CreateCoupon,
+	updateUC *ucCoupon.UpdateCoupon,
+	listUC *ucCoupon.ListCoupons,
+	listRedemptionsUC *ucCoupon.ListRedemptions,
+) *CouponHandler {
+	return &CouponHandler{
+		createUC:          createUC,
+		updateUC:          updateUC,
+		listUC:            listUC,
+		listRedemptionsUC: listRedemptionsUC,
+	}
+}
+
+type CouponRequest struct {
+	Code             string     `json:"code" binding:"required"`
+	Description      string     `json:"description"`
+	DiscountType     string     `json:"discount_type" binding:"required"`  // percent | fixed
+	DiscountValue    int64      `json:"discount_value" binding:"required"` // percent: 1..100 | fixed: centavos
+	AppliesTo        string     `json:"applies_to"`                        // all | services | products | plans
+	TargetIDs        []uint     `json:"target_ids"`                        // vazio = todos do escopo
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at"`
+	MaxUses          *int       `json:"max_uses"`
+	MaxUsesPerClient *int       `json:"max_uses_per_client"`
+	MinOrderCents    int64      `json:"min_order_cents"`
+	FirstVisitOnly   bool       `json:"first_visit_only"`
+	Active           *bool      `json:"active"` // só na edição; omitido = mantém
+}
+
+func (r CouponRequest) input(barbershopID uint) ucCoupon.CouponInput {
+	return ucCoupon.CouponInput{
+		BarbershopID:     barbershopID,
+		Code:             r.Code,
+		Description:      r.Description,
+		DiscountType:     r.DiscountType,
+		DiscountValue:    r.DiscountValue,
+		AppliesTo:        r.AppliesTo,
+		TargetIDs:        r.TargetIDs,
+		StartsAt:         r.StartsAt,
+		EndsAt:           r.EndsAt,
+		MaxUses:          r.MaxUses,
+		MaxUsesPerClient: r.MaxUsesPerClient,
+		MinOrderCents:    r.MinOrderCents,
+		FirstVisitOnly:   r.FirstVisitOnly,
+	}
+}
+
+// writeCouponAdminError traduz os erros de validação do cadastro de cupons.
+func writeCouponAdminError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ucCoupon.ErrInvalidBarbershop),
+		errors.Is(err, ucCoupon.ErrInvalidCode),
+		errors.Is(err, ucCoupon.ErrInvalidDescription),
+		errors.Is(err, ucCoupon.ErrInvalidDiscountType),
+		errors.Is(err, ucCoupon.ErrInvalidDiscountValue),
+		errors.Is(err, ucCoupon.ErrInvalidAppliesTo),
+		errors.Is(err, ucCoupon.ErrInvalidTargetIDs),
+		errors.Is(err, ucCoupon.ErrInvalidWindow),
+		errors.Is(err, ucCoupon.ErrInvalidMaxUses),
+		errors.Is(err, ucCoupon.ErrInvalidMinOrder):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucCoupon.ErrCouponNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	case errors.Is(err, couponDomain.ErrCodeTaken):
+		httperr.Write(c, http.StatusConflict, "coupon_code_taken", "Já existe um cupom com este código.")
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// writeCouponError responde os erros de aplicação de cupom nos checkouts e
+// retorna false para os demais.
+func writeCouponError(c *gin.Context, err error) bool {
+	switch {
+	case apperr.IsBusiness(err, "coupon_not_found"):
+		httperr.BadRequest(c, "coupon_not_found", "Cupom não encontrado.")
+	case apperr.IsBusiness(err, "coupon_inactive"):
+		httperr.BadRequest(c, "coupon_inactive", "Cupom inativo.")
+	case apperr.IsBusiness(err, "coupon_not_started"):
+		httperr.BadRequest(c, "coupon_not_started", "Cupom ainda não está valendo.")
+	case apperr.IsBusiness(err, "coupon_expired"):
+		httperr.BadRequest(c, "coupon_expired", "Cupom expirado.")
+	case apperr.IsBusiness(err, "coupon_exhausted"):
+		httperr.Write(c, http.StatusConflict, "coupon_exhausted", "Cupom esgotado.")
+	case apperr.IsBusiness(err, "coupon_client_limit"):
+		httperr.Write(c, http.StatusConflict, "coupon_client_limit", "Você já usou este cupom o máximo de vezes.")
+	case apperr.IsBusiness(err, "coupon_first_visit_only"):
+		httperr.BadRequest(c, "coupon_first_visit_only", "Cupom válido só para a primeira visita.")
+	case apperr.IsBusiness(err, "coupon_min_order"):
+		httperr.BadRequest(c, "coupon_min_order", "Valor mínimo do cupom não atingido.")
+	case apperr.IsBusiness(err, "coupon_not_applicable"):
+		httperr.BadRequest(c, "coupon_not_applicable", "Cupom não vale para estes itens.")
+	case apperr.IsBusiness(err, "coupon_requires_client"):
+		httperr.BadRequest(c, "coupon_requires_client", "Este cupom exige identificação do cliente.")
+	default:
+		return false
+	}
+	return true
+}
+
+// POST /api/me/coupons (owner)
+func (h *CouponHandler) Create(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	coupon, err := h.createUC.Execute(c.Request.Context(), req.input(barbershopID))
+	if err != nil {
+		writeCouponAdminError(c, err, "failed_to_create_coupon")
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+// PUT /api/me/coupons/:id (owner)
+func (h *CouponHandler) Update(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	couponID, ok := parseIDParam(c, "invalid_coupon_id")
+	if !ok {
+		return
+	}
+
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	coupon, err := h.updateUC.Execute(c.Request.Context(), couponID, req.Active, req.input(barbershopID))
+	if err != nil {
+		writeCouponAdminError(c, err, "failed_to_update_coupon")
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+// GET /api/me/coupons (owner)
+func (h *CouponHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_coupons", "failed_to_list_coupons")
+		return
+	}
+	if list == nil {
+		list = []models.Coupon{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coupons": list})
+}
+
+// GET /api/me/coupons/:id/redemptions (owner)
+func (h *CouponHandler) ListRedemptions(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	couponID, ok := parseIDParam(c, "invalid_coupon_id")
+	if !ok {
+		return
+	}
+
+	list, err := h.listRedemptionsUC.Execute(c.Request.Context(), barbershopID, couponID)
+	if err != nil {
+		writeCouponAdminError(c, err, "failed_to_list_redemptions")
+		return
+	}
+	if list == nil {
+		list = []models.CouponRedemption{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"redemptions": list})
+}
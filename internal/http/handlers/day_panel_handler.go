@@ -28,7 +28,8 @@ func NewDayPanelHandler(query *daypanel.Query) *DayPanelHandler {
 // Query params:
 //
 //	date      — YYYY-MM-DD in the shop's local timezone (default: today)
-//	barber_id — filter by a specific barber (default: all barbers)
+//	barber_id — filter by a specific barber (default: all barbers; owner only —
+//	            barbers are always scoped to their own appointments)
 func (h *DayPanelHandler) Get(c *gin.Context) {
 	barbershopID := c.GetUint(middleware.ContextBarbershopID)
 
@@ -44,6 +45,11 @@ func (h *DayPanelHandler) Get(c *gin.Context) {
 		barberID = uint(v)
 	}
 
+	// Barbers (role "barber") only see their own cards.
+	if !middleware.IsOwner(c) {
+		barberID = c.GetUint(middleware.ContextUserID)
+	}
+
 	resp, err := h.query.Execute(c.Request.Context(), daypanel.Input{
 		BarbershopID: barbershopID,
 		BarberID:     barberID,
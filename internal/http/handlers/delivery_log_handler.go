@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+)
+
+// DeliveryLogHandler expõe ao owner o log de entregas do outbox
+// (notificações, sincronização de agenda e auditoria) e o reenvio dos
+// eventos que esgotaram as tentativas.
+type DeliveryLogHandler struct {
+	db     *gorm.DB
+	outbox *outbox.Outbox
+}
+
+func NewDeliveryLogHandler(db *gorm.DB, o *outbox.Outbox) *DeliveryLogHandler {
+	return &DeliveryLogHandler{db: db, outbox: o}
+}
+
+// List lista os eventos do outbox, mais recentes primeiro.
+// GET /api/me/deliveries?status=&topic=&page=&limit=
+func (h *DeliveryLogHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page <= 0 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	q := h.db.WithContext(c.Request.Context()).
+		Model(&models.OutboxEvent{}).
+		Where("barbershop_id = ?", barbershopID)
+
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case models.OutboxStatusPending, models.OutboxStatusDelivered, models.OutboxStatusDead:
+			q = q.Where("status = ?", status)
+		default:
+			httperr.BadRequest(c, "invalid_status", "Status inválido.")
+			return
+		}
+	}
+
+	if topic := c.Query("topic"); topic != "" {
+		q = q.Where("topic = ?", topic)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		httperr.Internal(c, "deliveries_count_failed", "Erro ao contar entregas.")
+		return
+	}
+
+	var events []models.OutboxEvent
+	if err := q.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&events).Error; err != nil {
+		httperr.Internal(c, "deliveries_list_failed", "Erro ao listar entregas.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":       page,
+		"limit":      limit,
+		"total":      total,
+		"deliveries": events,
+	})
+}
+
+// Retry devolve à fila um evento morto, zerando as tentativas.
+// POST /api/me/deliveries/:id/retry
+func (h *DeliveryLogHandler) Retry(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_delivery_id")
+	if !ok {
+		return
+	}
+
+	err := h.outbox.Retry(c.Request.Context(), barbershopID, id)
+	switch {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.Is(err, outbox.ErrEventNotFound):
+		httperr.NotFound(c, "delivery_not_found", "Entrega não encontrada.")
+	case errors.Is(err, outbox.ErrEventNotDead):
+		httperr.Write(c, http.StatusConflict, "delivery_not_dead", "Só entregas com falha definitiva podem ser reenviadas.")
+	default:
+		httperr.Internal(c, "delivery_retry_failed", "Erro ao reenviar entrega.")
+	}
+}
@@ -190,6 +190,14 @@ func (h *GoogleOAuthHandler) Disconnect(c *gin.Context) {
 		return
 	}
 
+	// Sem a conta conectada, os eventos pessoais deixam de bloquear a agenda.
+	if err := h.db.WithContext(c.Request.Context()).
+		Where("barber_id = ? AND barbershop_id = ?", userID, barbershopID).
+		Delete(&models.BarberBusyPeriod{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disconnect"})
+		return
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
@@ -230,6 +238,8 @@ func (h *GoogleOAuthHandler) saveToken(
 	existing.AccessToken  = encAccess
 	existing.RefreshToken = encRefresh
 	existing.TokenExpiry  = token.Expiry
+	// Reconexão pode ser outra conta Google: o próximo sync de ocupados é completo.
+	existing.SyncToken    = nil
 	return h.db.WithContext(ctx).Save(&existing).Error
 }
 
@@ -26,10 +26,10 @@ var allowedMIME = map[string]string{
 
 type ImageHandler struct {
 	db  *gorm.DB
-	r2  *storage.R2Service
+	r2  storage.Storage
 }
 
-func NewImageHandler(db *gorm.DB, r2 *storage.R2Service) *ImageHandler {
+func NewImageHandler(db *gorm.DB, r2 storage.Storage) *ImageHandler {
 	return &ImageHandler{db: db, r2: r2}
 }
 
@@ -74,6 +74,14 @@ func (h *InternalAppointmentHandler) Create(c *gin.Context) {
 		return
 	}
 
+	// Barbeiro (role "barber") só agenda na própria agenda.
+	if !middleware.IsOwner(c) && req.BarberID != c.GetUint(middleware.ContextUserID) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "barber_not_allowed",
+		})
+		return
+	}
+
 	// 3️⃣ Execute use case
 	appointment, err := h.createUC.Execute(
 		c.Request.Context(),
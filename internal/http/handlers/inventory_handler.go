@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domainInventory "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	domainProduct "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucInventory "github.com/BruksfildServices01/barber-scheduler/internal/usecase/inventory"
+)
+
+// InventoryHandler expõe o razão de estoque: entradas de compra, perdas,
+// devoluções, contagem física e o histórico por produto.
+type InventoryHandler struct {
+	entriesUC   *ucInventory.RecordStockEntries
+	countUC     *ucInventory.RecordStockCount
+	movementsUC *ucInventory.ListStockMovements
+}
+
+func NewInventoryHandler(
+	entriesUC *ucInventory.RecordStockEntries,
+	countUC *ucInventory.RecordStockCount,
+	movementsUC *ucInventory.ListStockMovements,
+) *InventoryHandler {
+	return &InventoryHandler{
+		entriesUC:   entriesUC,
+		countUC:     countUC,
+		movementsUC: movementsUC,
+	}
+}
+
+type StockEntryItemRequest struct {
+	ProductID     uint   `json:"product_id" binding:"required"`
+	Quantity      int    `json:"quantity" binding:"required"`
+	UnitCostCents *int64 `json:"unit_cost_cents"` // só em compras
+}
+
+type StockEntryRequest struct {
+	Type  string                  `json:"type" binding:"required"` // purchase | loss | return
+	Note  string                  `json:"note"`
+	Items []StockEntryItemRequest `json:"items" binding:"required"`
+}
+
+type StockCountLineRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Counted   *int `json:"counted" binding:"required"`
+}
+
+type StockCountRequest struct {
+	Note  string                  `json:"note"`
+	Items []StockCountLineRequest `json:"items" binding:"required"`
+}
+
+var stockValidationCodes = []string{
+	"invalid_movement_type",
+	"invalid_product_id",
+	"invalid_quantity",
+	"invalid_unit_cost",
+	"empty_items",
+	"too_many_items",
+	"duplicate_product",
+	"note_too_long",
+}
+
+// writeStockError traduz os erros de lançamento de estoque.
+func writeStockError(c *gin.Context, err error, fallback string) {
+	for _, code := range stockValidationCodes {
+		if apperr.IsBusiness(err, code) {
+			httperr.BadRequest(c, code, code)
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, domainProduct.ErrProductNotFound):
+		httperr.NotFound(c, "product_not_found", "product_not_found")
+	case errors.Is(err, domainProduct.ErrInsufficientStock):
+		httperr.Write(c, http.StatusConflict, "insufficient_stock", "Estoque insuficiente para a saída.")
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// POST /api/me/stock/entries (owner)
+func (h *InventoryHandler) RecordEntries(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	var req StockEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	items := make([]ucInventory.EntryItem, 0, len(req.Items))
+	for _, it := range req.Items {
+		items = append(items, ucInventory.EntryItem{
+			ProductID:     it.ProductID,
+			Quantity:      it.Quantity,
+			UnitCostCents: it.UnitCostCents,
+		})
+	}
+
+	movements, err := h.entriesUC.Execute(c.Request.Context(), ucInventory.RecordStockEntriesInput{
+		BarbershopID: barbershopID,
+		UserID:       userID,
+		Type:         req.Type,
+		Note:         req.Note,
+		Items:        items,
+	})
+	if err != nil {
+		writeStockError(c, err, "failed_to_record_stock_entry")
+		return
+	}
+
+	EvictPublicProductsCache(barbershopID)
+	c.JSON(http.StatusCreated, gin.H{"movements": movements})
+}
+
+// POST /api/me/stock/counts (owner)
+func (h *InventoryHandler) RecordCount(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	var req StockCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	lines := make([]domainInventory.CountLine, 0, len(req.Items))
+	for _, it := range req.Items {
+		lines = append(lines, domainInventory.CountLine{
+			ProductID: it.ProductID,
+			Counted:   *it.Counted,
+		})
+	}
+
+	movements, err := h.countUC.Execute(c.Request.Context(), ucInventory.RecordStockCountInput{
+		BarbershopID: barbershopID,
+		UserID:       userID,
+		Note:         req.Note,
+		Lines:        lines,
+	})
+	if err != nil {
+		writeStockError(c, err, "failed_to_record_stock_count")
+		return
+	}
+
+	if movements == nil {
+		movements = []models.InventoryMovement{}
+	}
+
+	EvictPublicProductsCache(barbershopID)
+	c.JSON(http.StatusOK, gin.H{"adjustments": movements})
+}
+
+// GET /api/me/products/:id/stock-movements?before_id=&limit=
+func (h *InventoryHandler) ListMovements(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	productID, ok := parseIDParam(c, "invalid_id")
+	if !ok {
+		return
+	}
+
+	beforeID, _ := strconv.ParseUint(c.Query("before_id"), 10, 64)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	movements, err := h.movementsUC.Execute(c.Request.Context(), barbershopID, productID, uint(beforeID), limit)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_stock_movements", "failed_to_list_stock_movements")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"movements": movements})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
+)
+
+// LoyaltyHandler administra o programa de fidelidade: configuração,
+// catálogo de recompensas e extrato de pontos do cliente.
+type LoyaltyHandler struct {
+	getProgramUC    *ucLoyalty.GetProgram
+	updateProgramUC *ucLoyalty.UpdateProgram
+	createRewardUC  *ucLoyalty.CreateReward
+	updateRewardUC  *ucLoyalty.UpdateReward
+	listRewardsUC   *ucLoyalty.ListRewards
+	clientUC        *ucLoyalty.GetClientLoyalty
+}
+
+func NewLoyaltyHandler(
+	getProgramUC *ucLoyalty.GetProgram,
+	updateProgramUC *ucLoyalty.UpdateProgram,
+	createRewardUC *ucLoyalty.CreateReward,
+	updateRewardUC *ucLoyalty.UpdateReward,
+	listRewardsUC *ucLoyalty.ListRewards,
+	clientUC *ucLoyalty.GetClientLoyalty,
+) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		getProgramUC:    getProgramUC,
+		updateProgramUC: updateProgramUC,
+		createRewardUC:  createRewardUC,
+		updateRewardUC:  updateRewardUC,
+		listRewardsUC:   listRewardsUC,
+		clientUC:        clientUC,
+	}
+}
+
+type LoyaltyProgramRequest struct {
+	Active        bool `json:"active"`
+	PointsPerReal int  `json:"points_per_real" binding:"required"`
+	ExpiryMonths  int  `json:"expiry_months"` // 0 = pontos não expiram
+}
+
+type LoyaltyRewardRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Kind          string `json:"kind" binding:"required"` // free_service | discount
+	PointsCost    int    `json:"points_cost" binding:"required"`
+	ServiceID     *uint  `json:"service_id"`     // free_service; omitido = qualquer serviço
+	DiscountCents int64  `json:"discount_cents"` // discount
+	Active        *bool  `json:"active"`         // só na edição; omitido = mantém
+}
+
+func (r LoyaltyRewardRequest) input(barbershopID uint) ucLoyalty.RewardInput {
+	return ucLoyalty.RewardInput{
+		BarbershopID:  barbershopID,
+		Name:          r.Name,
+		Kind:          r.Kind,
+		PointsCost:    r.PointsCost,
+		ServiceID:     r.ServiceID,
+		DiscountCents: r.DiscountCents,
+	}
+}
+
+// writeLoyaltyAdminError traduz os erros de validação do programa e das
+// recompensas.
+func writeLoyaltyAdminError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ucLoyalty.ErrInvalidBarbershop),
+		errors.Is(err, ucLoyalty.ErrInvalidPointsPerReal),
+		errors.Is(err, ucLoyalty.ErrInvalidExpiryMonths),
+		errors.Is(err, ucLoyalty.ErrInvalidRewardName),
+		errors.Is(err, ucLoyalty.ErrInvalidRewardKind),
+		errors.Is(err, ucLoyalty.ErrInvalidPointsCost),
+		errors.Is(err, ucLoyalty.ErrInvalidRewardService),
+		errors.Is(err, ucLoyalty.ErrInvalidRewardDiscount):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucLoyalty.ErrRewardNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// writeLoyaltyError responde os erros da troca de recompensa no fechamento
+// e retorna false para os demais.
+func writeLoyaltyError(c *gin.Context, err error) bool {
+	switch {
+	case apperr.IsBusiness(err, "loyalty_program_inactive"):
+		httperr.BadRequest(c, "loyalty_program_inactive", "Programa de fidelidade desativado.")
+	case apperr.IsBusiness(err, "loyalty_reward_not_found"):
+		httperr.BadRequest(c, "loyalty_reward_not_found", "Recompensa não encontrada.")
+	case apperr.IsBusiness(err, "loyalty_reward_inactive"):
+		httperr.BadRequest(c, "loyalty_reward_inactive", "Recompensa inativa.")
+	case apperr.IsBusiness(err, "loyalty_reward_not_applicable"):
+		httperr.BadRequest(c, "loyalty_reward_not_applicable", "Recompensa não vale para este atendimento.")
+	case apperr.IsBusiness(err, "loyalty_insufficient_points"):
+		httperr.Write(c, http.StatusConflict, "loyalty_insufficient_points", "Pontos insuficientes para esta recompensa.")
+	case apperr.IsBusiness(err, "loyalty_requires_client"):
+		httperr.BadRequest(c, "loyalty_requires_client", "Recompensa exige cliente identificado.")
+	default:
+		return false
+	}
+	return true
+}
+
+// GET /api/me/loyalty/program
+func (h *LoyaltyHandler) GetProgram(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	program, err := h.getProgramUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_loyalty_program", "failed_to_load_loyalty_program")
+		return
+	}
+
+	c.JSON(http.StatusOK, program)
+}
+
+// PUT /api/me/loyalty/program (owner)
+func (h *LoyaltyHandler) UpdateProgram(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req LoyaltyProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	program, err := h.updateProgramUC.Execute(c.Request.Context(), ucLoyalty.ProgramInput{
+		BarbershopID:  barbershopID,
+		Active:        req.Active,
+		PointsPerReal: req.PointsPerReal,
+		ExpiryMonths:  req.ExpiryMonths,
+	})
+	if err != nil {
+		writeLoyaltyAdminError(c, err, "failed_to_update_loyalty_program")
+		return
+	}
+
+	c.JSON(http.StatusOK, program)
+}
+
+// GET /api/me/loyalty/rewards
+func (h *LoyaltyHandler) ListRewards(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	onlyActive := c.Query("active") == "true"
+	list, err := h.listRewardsUC.Execute(c.Request.Context(), barbershopID, onlyActive)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_loyalty_rewards", "failed_to_list_loyalty_rewards")
+		return
+	}
+	if list == nil {
+		list = []models.LoyaltyReward{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rewards": list})
+}
+
+// POST /api/me/loyalty/rewards (owner)
+func (h *LoyaltyHandler) CreateReward(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req LoyaltyRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	reward, err := h.createRewardUC.Execute(c.Request.Context(), req.input(barbershopID))
+	if err != nil {
+		writeLoyaltyAdminError(c, err, "failed_to_create_loyalty_reward")
+		return
+	}
+
+	c.JSON(http.StatusCreated, reward)
+}
+
+// PUT /api/me/loyalty/rewards/:id (owner)
+func (h *LoyaltyHandler) UpdateReward(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	rewardID, ok := parseIDParam(c, "invalid_reward_id")
+	if !ok {
+		return
+	}
+
+	var req LoyaltyRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	reward, err := h.updateRewardUC.Execute(c.Request.Context(), rewardID, req.Active, req.input(barbershopID))
+	if err != nil {
+		writeLoyaltyAdminError(c, err, "failed_to_update_loyalty_reward")
+		return
+	}
+
+	c.JSON(http.StatusOK, reward)
+}
+
+// GET /api/me/clients/:id/loyalty
+func (h *LoyaltyHandler) ClientLoyalty(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	out, err := h.clientUC.Execute(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_client_loyalty", "failed_to_load_client_loyalty")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
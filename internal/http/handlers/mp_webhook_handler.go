@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	infraMP "github.com/BruksfildServices01/barber-scheduler/internal/integration/payment/mercadopago"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
+	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 )
 
 // MPWebhookHandler processa as notificações IPN do Mercado Pago e serve o
@@ -34,6 +36,12 @@ type MPWebhookHandler struct {
 	// registry é opcional — quando presente, habilita polling de status via
 	// provider genérico (PagBank etc.) no endpoint CheckPaymentStatus.
 	registry *paymentinfra.ProviderRegistry
+	// syncReversal é opcional — quando presente, estornos e chargebacks
+	// informados pelo MP atualizam o payment interno.
+	syncReversal *ucPayment.SyncPaymentReversal
+	// renewalFailure é opcional — quando presente, recusas de cobranças de
+	// renovação de assinatura entram no dunning.
+	renewalFailure *ucSubscription.FailRenewalCharge
 }
 
 func NewMPWebhookHandler(
@@ -59,6 +67,20 @@ func (h *MPWebhookHandler) WithRegistry(r *paymentinfra.ProviderRegistry) *MPWeb
 	return h
 }
 
+// WithReversalSync configura o use case que aplica estornos e chargebacks
+// recebidos do MP. Retorna o próprio handler para encadeamento.
+func (h *MPWebhookHandler) WithReversalSync(uc *ucPayment.SyncPaymentReversal) *MPWebhookHandler {
+	h.syncReversal = uc
+	return h
+}
+
+// WithRenewalFailure configura o use case que registra recusas de cobranças de
+// renovação automática. Retorna o próprio handler para encadeamento.
+func (h *MPWebhookHandler) WithRenewalFailure(uc *ucSubscription.FailRenewalCharge) *MPWebhookHandler {
+	h.renewalFailure = uc
+	return h
+}
+
 // mpNotification é o corpo do IPN enviado pelo Mercado Pago.
 type mpNotification struct {
 	Action string `json:"action"`
@@ -180,6 +202,10 @@ func (h *MPWebhookHandler) CheckPaymentStatus(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "expired"})
 		return
 	}
+	if p.Status == "refunded" || p.Status == "partially_refunded" || p.Status == "charged_back" {
+		c.JSON(http.StatusOK, gin.H{"status": string(p.Status)})
+		return
+	}
 
 	// Tenta o caminho Mercado Pago primeiro:
 	// MPPaymentID pode ser nil — pagamentos transparentes gravam apenas TxID ("mp_pay:<id>").
@@ -320,7 +346,13 @@ func (h *MPWebhookHandler) processPayment(ctx context.Context, mpPaymentIDStr st
 		return fmt.Errorf("failed to get MP payment: %w", err)
 	}
 
-	if resp.Status != "approved" {
+	reversed := resp.Status == "refunded" || resp.Status == "charged_back" || resp.TransactionAmountRefunded > 0
+
+	if (resp.Status == "rejected" || resp.Status == "cancelled") && h.renewalFailure != nil {
+		return h.renewalFailure.Execute(ctx, resp.ExternalReference, resp.StatusDetail)
+	}
+
+	if resp.Status != "approved" && !reversed {
 		return nil
 	}
 
@@ -328,5 +360,26 @@ func (h *MPWebhookHandler) processPayment(ctx context.Context, mpPaymentIDStr st
 		return fmt.Errorf("empty external_reference for MP payment %d", mpPaymentID)
 	}
 
-	return h.markMPPaid.Execute(ctx, resp.ExternalReference, mpPaymentIDStr)
+	if resp.Status == "approved" {
+		if err := h.markMPPaid.Execute(ctx, resp.ExternalReference, mpPaymentIDStr); err != nil {
+			return err
+		}
+	}
+
+	// Estorno parcial mantém status "approved" no MP (status_detail=partially_refunded);
+	// o total devolvido vem em transaction_amount_refunded.
+	if reversed && h.syncReversal != nil {
+		refundID := ""
+		if n := len(resp.Refunds); n > 0 {
+			refundID = strconv.Itoa(resp.Refunds[n-1].ID)
+		}
+		return h.syncReversal.Execute(ctx, ucPayment.PaymentReversalInput{
+			ExternalReference:  resp.ExternalReference,
+			ProviderRefundID:   refundID,
+			RefundedTotalCents: int64(math.Round(resp.TransactionAmountRefunded * 100)),
+			ChargedBack:        resp.Status == "charged_back",
+		})
+	}
+
+	return nil
 }
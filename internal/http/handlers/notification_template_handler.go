@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+	ucTemplate "github.com/BruksfildServices01/barber-scheduler/internal/usecase/notificationtemplate"
+)
+
+// NotificationTemplateHandler administra os textos personalizados das
+// mensagens ao cliente (email e WhatsApp) e a prévia com dados de exemplo.
+type NotificationTemplateHandler struct {
+	listUC    *ucTemplate.ListTemplates
+	saveUC    *ucTemplate.SaveTemplate
+	resetUC   *ucTemplate.ResetTemplate
+	previewUC *ucTemplate.PreviewTemplate
+}
+
+func NewNotificationTemplateHandler(
+	listUC *ucTemplate.ListTemplates,
+	saveUC *ucTemplate.SaveTemplate,
+	resetUC *ucTemplate.ResetTemplate,
+	previewUC *ucTemplate.PreviewTemplate,
+) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{
+		listUC:    listUC,
+		saveUC:    saveUC,
+		resetUC:   resetUC,
+		previewUC: previewUC,
+	}
+}
+
+type NotificationTemplateRequest struct {
+	Subject string `json:"subject"` // só email
+	Body    string `json:"body" binding:"required"`
+}
+
+type NotificationTemplatePreviewRequest struct {
+	Kind    string `json:"kind" binding:"required"`
+	Channel string `json:"channel" binding:"required"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"` // vazio = prévia do texto padrão
+}
+
+// writeTemplateError traduz os erros de validação dos templates.
+func writeTemplateError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, notification.ErrTemplateUnknownKind),
+		errors.Is(err, notification.ErrTemplateUnknownChannel),
+		errors.Is(err, notification.ErrTemplateEmptyBody),
+		errors.Is(err, notification.ErrTemplateBodyTooLong),
+		errors.Is(err, notification.ErrTemplateSubjectRequired),
+		errors.Is(err, notification.ErrTemplateSubjectInvalid),
+		errors.Is(err, notification.ErrTemplateUnclosedPlaceholder):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, notification.ErrTemplateUnknownPlaceholder):
+		// a mensagem aponta o placeholder recusado
+		httperr.BadRequest(c, "template_unknown_placeholder", err.Error())
+	case errors.Is(err, ucTemplate.ErrBarbershopNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// GET /api/me/notification-templates
+func (h *NotificationTemplateHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_templates", "failed_to_list_templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": list})
+}
+
+// PUT /api/me/notification-templates/:kind/:channel
+func (h *NotificationTemplateHandler) Save(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	t, err := h.saveUC.Execute(c.Request.Context(), ucTemplate.SaveInput{
+		BarbershopID: barbershopID,
+		Kind:         c.Param("kind"),
+		Channel:      c.Param("channel"),
+		Subject:      req.Subject,
+		Body:         req.Body,
+	})
+	if err != nil {
+		writeTemplateError(c, err, "failed_to_save_template")
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+// DELETE /api/me/notification-templates/:kind/:channel
+func (h *NotificationTemplateHandler) Reset(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	err := h.resetUC.Execute(c.Request.Context(), barbershopID, c.Param("kind"), c.Param("channel"))
+	if err != nil {
+		writeTemplateError(c, err, "failed_to_reset_template")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /api/me/notification-templates/preview
+func (h *NotificationTemplateHandler) Preview(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req NotificationTemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.previewUC.Execute(c.Request.Context(), ucTemplate.PreviewInput{
+		BarbershopID: barbershopID,
+		Kind:         req.Kind,
+		Channel:      req.Channel,
+		Subject:      req.Subject,
+		Body:         req.Body,
+	})
+	if err != nil {
+		writeTemplateError(c, err, "failed_to_preview_template")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -39,8 +39,9 @@ func NewOrderHandler(
 }
 
 type CreateOrderRequest struct {
-	ClientID *uint                    `json:"client_id,omitempty"`
-	Items    []CreateOrderItemRequest `json:"items" binding:"required"`
+	ClientID   *uint                    `json:"client_id,omitempty"`
+	Items      []CreateOrderItemRequest `json:"items" binding:"required"`
+	CouponCode string                   `json:"coupon_code,omitempty"`
 }
 
 type CreateOrderItemRequest struct {
@@ -66,6 +67,7 @@ type OrderItemResponse struct {
 	Quantity            int    `json:"quantity"`
 	UnitPrice           int64  `json:"unit_price"`
 	LineTotal           int64  `json:"line_total"`
+	ReturnedQuantity    int    `json:"returned_quantity"`
 }
 
 type RichOrderClientInfo struct {
@@ -121,9 +123,13 @@ func (h *OrderHandler) Create(c *gin.Context) {
 			BarbershopID: barbershopID,
 			ClientID:     req.ClientID,
 			Items:        items,
+			CouponCode:   strings.TrimSpace(req.CouponCode),
 		},
 	)
 	if err != nil {
+		if writeCouponError(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, productDomain.ErrProductNotFound):
 			httperr.BadRequest(c, "product_not_found", "Produto não encontrado.")
@@ -195,6 +201,7 @@ func (h *OrderHandler) GetByID(c *gin.Context) {
 			Quantity:            it.Quantity,
 			UnitPrice:           it.UnitPrice,
 			LineTotal:           it.LineTotal,
+			ReturnedQuantity:    it.ReturnedQuantity,
 		})
 	}
 
@@ -280,6 +287,7 @@ func toOrderResponse(order *orderDomain.Order) OrderResponse {
 			Quantity:            it.Quantity,
 			UnitPrice:           it.UnitPrice,
 			LineTotal:           it.LineTotal,
+			ReturnedQuantity:    it.ReturnedQuantity,
 		})
 	}
 
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+	ucOrder "github.com/BruksfildServices01/barber-scheduler/internal/usecase/order"
+)
+
+// OrderReturnHandler expõe o cancelamento de pedidos e a devolução de itens,
+// com estorno do pagamento ou reembolso manual.
+type OrderReturnHandler struct {
+	returnUC       *ucOrder.ReturnOrder
+	markRefundedUC *ucOrder.MarkReturnRefunded
+	orderRepo      *infraRepo.OrderGormRepository
+}
+
+func NewOrderReturnHandler(
+	returnUC *ucOrder.ReturnOrder,
+	markRefundedUC *ucOrder.MarkReturnRefunded,
+	orderRepo *infraRepo.OrderGormRepository,
+) *OrderReturnHandler {
+	return &OrderReturnHandler{
+		returnUC:       returnUC,
+		markRefundedUC: markRefundedUC,
+		orderRepo:      orderRepo,
+	}
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ReturnOrderItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required"`
+}
+
+type ReturnOrderRequest struct {
+	Reason string                   `json:"reason" binding:"required"`
+	Items  []ReturnOrderItemRequest `json:"items" binding:"required"`
+}
+
+// writeOrderReturnError traduz os erros de cancelamento e devolução.
+func writeOrderReturnError(c *gin.Context, err error) {
+	switch {
+	case apperr.IsBusiness(err, "order_not_found"):
+		httperr.NotFound(c, "order_not_found", "Pedido não encontrado.")
+	case apperr.IsBusiness(err, "return_reason_required"):
+		httperr.BadRequest(c, "return_reason_required", "Informe o motivo.")
+	case apperr.IsBusiness(err, "return_reason_too_long"):
+		httperr.BadRequest(c, "return_reason_too_long", "Motivo muito longo.")
+	case apperr.IsBusiness(err, "order_already_cancelled"):
+		httperr.Write(c, http.StatusConflict, "order_already_cancelled", "Pedido já cancelado.")
+	case apperr.IsBusiness(err, "order_not_paid"):
+		httperr.Write(c, http.StatusConflict, "order_not_paid", "Pedido ainda não pago: cancele o pedido inteiro.")
+	case apperr.IsBusiness(err, "order_payment_in_progress"):
+		httperr.Write(c, http.StatusConflict, "order_payment_in_progress", "Há um pagamento em andamento para este pedido.")
+	case errors.Is(err, orderDomain.ErrNothingToReturn):
+		httperr.BadRequest(c, "nothing_to_return", "Nenhum item a devolver.")
+	case errors.Is(err, orderDomain.ErrInvalidQuantity):
+		httperr.BadRequest(c, "invalid_quantity", "Quantidade inválida.")
+	case errors.Is(err, orderDomain.ErrProductNotInOrder):
+		httperr.BadRequest(c, "product_not_in_order", "Produto não faz parte do pedido.")
+	case errors.Is(err, orderDomain.ErrReturnExceedsQuantity):
+		httperr.Write(c, http.StatusConflict, "return_exceeds_quantity", "Quantidade maior que a restante no pedido.")
+	default:
+		httperr.Internal(c, "failed_to_return_order", "Falha ao registrar a devolução.")
+	}
+}
+
+// POST /api/me/orders/:id/cancel (owner)
+func (h *OrderReturnHandler) Cancel(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	orderID, ok := parseIDParam(c, "invalid_order_id")
+	if !ok {
+		return
+	}
+
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "return_reason_required", "Informe o motivo.")
+		return
+	}
+
+	ret, err := h.returnUC.Execute(c.Request.Context(), ucOrder.ReturnOrderInput{
+		BarbershopID: barbershopID,
+		OrderID:      orderID,
+		UserID:       &userID,
+		Reason:       req.Reason,
+		Cancel:       true,
+	})
+	if err != nil {
+		writeOrderReturnError(c, err)
+		return
+	}
+
+	EvictPublicProductsCache(barbershopID)
+	c.JSON(http.StatusOK, ret)
+}
+
+// POST /api/me/orders/:id/returns (owner)
+func (h *OrderReturnHandler) Return(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	orderID, ok := parseIDParam(c, "invalid_order_id")
+	if !ok {
+		return
+	}
+
+	var req ReturnOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Payload inválido.")
+		return
+	}
+
+	items := make([]ucOrder.ReturnOrderItemInput, 0, len(req.Items))
+	for _, it := range req.Items {
+		items = append(items, ucOrder.ReturnOrderItemInput{
+			ProductID: it.ProductID,
+			Quantity:  it.Quantity,
+		})
+	}
+
+	ret, err := h.returnUC.Execute(c.Request.Context(), ucOrder.ReturnOrderInput{
+		BarbershopID: barbershopID,
+		OrderID:      orderID,
+		UserID:       &userID,
+		Reason:       req.Reason,
+		Items:        items,
+	})
+	if err != nil {
+		writeOrderReturnError(c, err)
+		return
+	}
+
+	EvictPublicProductsCache(barbershopID)
+	c.JSON(http.StatusCreated, ret)
+}
+
+// GET /api/me/orders/:id/returns (owner)
+func (h *OrderReturnHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	orderID, ok := parseIDParam(c, "invalid_order_id")
+	if !ok {
+		return
+	}
+
+	returns, err := h.orderRepo.ListReturns(c.Request.Context(), barbershopID, orderID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_order_returns", "Falha ao listar devoluções.")
+		return
+	}
+	if returns == nil {
+		returns = []models.OrderReturn{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"returns": returns})
+}
+
+// POST /api/me/orders/:id/returns/:returnId/refunded (owner)
+func (h *OrderReturnHandler) MarkRefunded(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	orderID, ok := parseIDParam(c, "invalid_order_id")
+	if !ok {
+		return
+	}
+	returnID, err := strconv.ParseUint(c.Param("returnId"), 10, 64)
+	if err != nil || returnID == 0 {
+		httperr.BadRequest(c, "invalid_return_id", "ID da devolução inválido.")
+		return
+	}
+
+	ret, err := h.markRefundedUC.Execute(c.Request.Context(), barbershopID, orderID, uint(returnID), &userID)
+	if err != nil {
+		switch {
+		case apperr.IsBusiness(err, "order_return_not_found"):
+			httperr.NotFound(c, "order_return_not_found", "Devolução não encontrada.")
+		case apperr.IsBusiness(err, "refund_not_pending"):
+			httperr.Write(c, http.StatusConflict, "refund_not_pending", "Esta devolução não aguarda reembolso manual.")
+		default:
+			httperr.Internal(c, "failed_to_mark_refunded", "Falha ao registrar o reembolso.")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucPackage "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicepackage"
+)
+
+// PackageHandler administra pacotes pré-pagos e combos da barbearia.
+type PackageHandler struct {
+	createPackageUC      *ucPackage.CreatePackage
+	updatePackageUC      *ucPackage.UpdatePackage
+	listPackagesUC       *ucPackage.ListPackages
+	createComboUC        *ucPackage.CreateCombo
+	updateComboUC        *ucPackage.UpdateCombo
+	listCombosUC         *ucPackage.ListCombos
+	listClientPackagesUC *ucPackage.ListClientPackages
+}
+
+func NewPackageHandler(
+	createPackageUC *ucPackage.CreatePackage,
+	updatePackageUC *ucPackage.UpdatePackage,
+	listPackagesUC *ucPackage.ListPackages,
+	createComboUC *ucPackage.CreateCombo,
+	updateComboUC *ucPackage.UpdateCombo,
+	listCombosUC *ucPackage.ListCombos,
+	listClientPackagesUC *ucPackage.ListClientPackages,
+) *PackageHandler {
+	return &PackageHandler{
+		createPackageUC:      createPackageUC,
+		updatePackageUC:      updatePackageUC,
+		listPackagesUC:       listPackagesUC,
+		createComboUC:        createComboUC,
+		updateComboUC:        updateComboUC,
+		listCombosUC:         listCombosUC,
+		listClientPackagesUC: listClientPackagesUC,
+	}
+}
+
+type PackageRequest struct {
+	Name         string `json:"name" binding:"required"`
+	PriceCents   int64  `json:"price_cents" binding:"min=0"`
+	Credits      int    `json:"credits" binding:"min=0"`
+	ValidityDays int    `json:"validity_days" binding:"min=0"`
+	ServiceIDs   []uint `json:"service_ids"`
+	Active       *bool  `json:"active"` // só na edição; omitido = mantém
+}
+
+type ComboRequest struct {
+	Name            string `json:"name" binding:"required"`
+	PriceCents      *int64 `json:"price_cents"`      // preço fixo do combo
+	DiscountPercent int    `json:"discount_percent"` // ou desconto sobre o preço cheio
+	ServiceIDs      []uint `json:"service_ids"`      // na ordem de execução
+	Active          *bool  `json:"active"`           // só na edição; omitido = mantém
+}
+
+// writePackageError traduz os erros de validação de pacote/combo.
+func writePackageError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ucPackage.ErrInvalidBarbershop),
+		errors.Is(err, ucPackage.ErrInvalidName),
+		errors.Is(err, ucPackage.ErrInvalidPrice),
+		errors.Is(err, ucPackage.ErrInvalidCredits),
+		errors.Is(err, ucPackage.ErrInvalidValidity),
+		errors.Is(err, ucPackage.ErrInvalidDiscount),
+		errors.Is(err, ucPackage.ErrInvalidComboPrice),
+		errors.Is(err, ucPackage.ErrServiceIDsRequired),
+		errors.Is(err, ucPackage.ErrInvalidServiceIDs),
+		errors.Is(err, ucPackage.ErrComboTooFew),
+		errors.Is(err, ucPackage.ErrComboTooMany):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucPackage.ErrPackageNotFound),
+		errors.Is(err, ucPackage.ErrComboNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+func parseIDParam(c *gin.Context, code string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		httperr.BadRequest(c, code, code)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ======================================================
+// PACOTES
+// ======================================================
+
+func (h *PackageHandler) CreatePackage(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	pkg, err := h.createPackageUC.Execute(c.Request.Context(), ucPackage.PackageInput{
+		BarbershopID: barbershopID,
+		Name:         req.Name,
+		PriceCents:   req.PriceCents,
+		Credits:      req.Credits,
+		ValidityDays: req.ValidityDays,
+		ServiceIDs:   req.ServiceIDs,
+	})
+	if err != nil {
+		writePackageError(c, err, "failed_to_create_package")
+		return
+	}
+
+	c.JSON(http.StatusCreated, pkg)
+}
+
+func (h *PackageHandler) UpdatePackage(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	packageID, ok := parseIDParam(c, "invalid_package_id")
+	if !ok {
+		return
+	}
+
+	var req PackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	pkg, err := h.updatePackageUC.Execute(c.Request.Context(), packageID, req.Active, ucPackage.PackageInput{
+		BarbershopID: barbershopID,
+		Name:         req.Name,
+		PriceCents:   req.PriceCents,
+		Credits:      req.Credits,
+		ValidityDays: req.ValidityDays,
+		ServiceIDs:   req.ServiceIDs,
+	})
+	if err != nil {
+		writePackageError(c, err, "failed_to_update_package")
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg)
+}
+
+func (h *PackageHandler) ListPackages(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listPackagesUC.Execute(c.Request.Context(), barbershopID, false)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list", "failed_to_list")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"packages": list})
+}
+
+// ListClientPackages: GET /me/clients/:id/packages — pacotes comprados pelo cliente.
+func (h *PackageHandler) ListClientPackages(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	clientID, ok := parseIDParam(c, "invalid_client_id")
+	if !ok {
+		return
+	}
+
+	list, err := h.listClientPackagesUC.Execute(c.Request.Context(), barbershopID, clientID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list", "failed_to_list")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"packages": list})
+}
+
+// ======================================================
+// COMBOS
+// ======================================================
+
+func (h *PackageHandler) CreateCombo(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req ComboRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	combo, err := h.createComboUC.Execute(c.Request.Context(), ucPackage.ComboInput{
+		BarbershopID:    barbershopID,
+		Name:            req.Name,
+		PriceCents:      req.PriceCents,
+		DiscountPercent: req.DiscountPercent,
+		ServiceIDs:      req.ServiceIDs,
+	})
+	if err != nil {
+		writePackageError(c, err, "failed_to_create_combo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, combo)
+}
+
+func (h *PackageHandler) UpdateCombo(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	comboID, ok := parseIDParam(c, "invalid_combo_id")
+	if !ok {
+		return
+	}
+
+	var req ComboRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	combo, err := h.updateComboUC.Execute(c.Request.Context(), comboID, req.Active, ucPackage.ComboInput{
+		BarbershopID:    barbershopID,
+		Name:            req.Name,
+		PriceCents:      req.PriceCents,
+		DiscountPercent: req.DiscountPercent,
+		ServiceIDs:      req.ServiceIDs,
+	})
+	if err != nil {
+		writePackageError(c, err, "failed_to_update_combo")
+		return
+	}
+
+	c.JSON(http.StatusOK, combo)
+}
+
+func (h *PackageHandler) ListCombos(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listCombosUC.Execute(c.Request.Context(), barbershopID, false)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list", "failed_to_list")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"combos": list})
+}
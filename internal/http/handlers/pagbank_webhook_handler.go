@@ -14,6 +14,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/integration/payment/pagbank"
 	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
 	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
+	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 )
 
 // PagBankWebhookHandler processa notificações de pagamento do PagBank.
@@ -21,24 +22,37 @@ import (
 type PagBankWebhookHandler struct {
 	db         *gorm.DB
 	markAsPaid *ucPayment.MarkMPPaymentAsPaid
+	reversal   *ucPayment.SyncPaymentReversal
 	cipher     *crypt.Cipher
 	sandbox    bool
+	// renewalFailure é opcional — quando presente, recusas de cobranças de
+	// renovação de assinatura entram no dunning.
+	renewalFailure *ucSubscription.FailRenewalCharge
 }
 
 func NewPagBankWebhookHandler(
 	db *gorm.DB,
 	markAsPaid *ucPayment.MarkMPPaymentAsPaid,
+	reversal *ucPayment.SyncPaymentReversal,
 	cipher *crypt.Cipher,
 	sandbox bool,
 ) *PagBankWebhookHandler {
 	return &PagBankWebhookHandler{
 		db:         db,
 		markAsPaid: markAsPaid,
+		reversal:   reversal,
 		cipher:     cipher,
 		sandbox:    sandbox,
 	}
 }
 
+// WithRenewalFailure configura o use case que registra recusas de cobranças de
+// renovação automática. Retorna o próprio handler para encadeamento.
+func (h *PagBankWebhookHandler) WithRenewalFailure(uc *ucSubscription.FailRenewalCharge) *PagBankWebhookHandler {
+	h.renewalFailure = uc
+	return h
+}
+
 // Handle processa POST /api/webhooks/pagbank
 func (h *PagBankWebhookHandler) Handle(c *gin.Context) {
 	// Lê o body para validação de assinatura e parsing.
@@ -95,14 +109,47 @@ func (h *PagBankWebhookHandler) Handle(c *gin.Context) {
 		}
 	}
 
-	if !isPaid {
-		c.Status(http.StatusOK)
-		return
+	if isPaid {
+		// Reutiliza o use case existente — que aceita externalReference (nosso payment.ID) e providerPaymentID.
+		if err := h.markAsPaid.Execute(c.Request.Context(), referenceID, providerPaymentID); err != nil {
+			log.Printf("[PAGBANK_WEBHOOK] markAsPaid error ref=%s provider_id=%s: %v", referenceID, providerPaymentID, err)
+		}
+	}
+
+	// Recusa de cartão: só importa para cobranças de renovação (o use case
+	// ignora os demais pagamentos). CANCELED com valor devolvido é estorno.
+	if !isPaid && h.renewalFailure != nil {
+		for _, charge := range payload.Order.Charges {
+			declined := charge.Status == "DECLINED" ||
+				(charge.Status == "CANCELED" && charge.RefundedCents() <= 0)
+			if !declined {
+				continue
+			}
+			if err := h.renewalFailure.Execute(c.Request.Context(), referenceID, charge.Status); err != nil {
+				log.Printf("[PAGBANK_WEBHOOK] renewal failure error ref=%s charge=%s: %v", referenceID, charge.ID, err)
+			}
+			break
+		}
 	}
 
-	// Reutiliza o use case existente — que aceita externalReference (nosso payment.ID) e providerPaymentID.
-	if err := h.markAsPaid.Execute(c.Request.Context(), referenceID, providerPaymentID); err != nil {
-		log.Printf("[PAGBANK_WEBHOOK] markAsPaid error ref=%s provider_id=%s: %v", referenceID, providerPaymentID, err)
+	// Estorno (total ou parcial) e chargeback chegam como atualização da charge.
+	// Estorno total deixa a charge CANCELED; o valor devolvido vem em amount.summary.refunded.
+	if h.reversal != nil {
+		for _, charge := range payload.Order.Charges {
+			if !charge.IsChargedBack() && charge.RefundedCents() <= 0 {
+				continue
+			}
+			err := h.reversal.Execute(c.Request.Context(), ucPayment.PaymentReversalInput{
+				ExternalReference:  referenceID,
+				ProviderRefundID:   charge.ID,
+				RefundedTotalCents: charge.RefundedCents(),
+				ChargedBack:        charge.IsChargedBack(),
+			})
+			if err != nil {
+				log.Printf("[PAGBANK_WEBHOOK] reversal error ref=%s charge=%s: %v", referenceID, charge.ID, err)
+			}
+			break
+		}
 	}
 
 	c.Status(http.StatusOK)
@@ -12,6 +12,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
 	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
 )
@@ -216,16 +217,22 @@ func (h *PaymentHandler) CashDue(c *gin.Context) {
 			a.id AS appointment_id,
 			a.start_time,
 			COALESCE(c.name, 'Cliente não identificado') AS client_name,
-			COALESCE(bs.price, 0) AS amount_cents,
-			COALESCE(bs.name, 'Serviço') AS service_name
+			COALESCE(lines.price_cents, bs.price, 0) AS amount_cents,
+			COALESCE(lines.names, bs.name, 'Serviço') AS service_name
 		FROM appointments a
 		LEFT JOIN clients c ON c.id = a.client_id
 		LEFT JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents,
+		         string_agg(s.service_name, ' + ' ORDER BY s.position) AS names
+		  FROM appointment_services s
+		  WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'scheduled'
 		  AND NOT EXISTS (
 		    SELECT 1 FROM payments p
-		    WHERE p.appointment_id = a.id AND p.status = 'paid'
+		    WHERE p.appointment_id = a.id AND ` + shared.RevenuePaymentSQL + `
 		  )`
 
 	args := []any{barbershopID}
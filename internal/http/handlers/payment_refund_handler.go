@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	paymentinfra "github.com/BruksfildServices01/barber-scheduler/internal/integration/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
+)
+
+// PaymentRefundHandler expõe o estorno de pagamentos para o dono da barbearia.
+type PaymentRefundHandler struct {
+	db       *gorm.DB
+	refund   *ucPayment.RefundPayment
+	registry *paymentinfra.ProviderRegistry
+}
+
+func NewPaymentRefundHandler(
+	db *gorm.DB,
+	refund *ucPayment.RefundPayment,
+	registry *paymentinfra.ProviderRegistry,
+) *PaymentRefundHandler {
+	return &PaymentRefundHandler{
+		db:       db,
+		refund:   refund,
+		registry: registry,
+	}
+}
+
+type refundPaymentRequest struct {
+	// AmountCents omitido ou 0 = estorno total do saldo restante.
+	AmountCents int64  `json:"amount_cents"`
+	Reason      string `json:"reason"`
+}
+
+type refundPaymentResponse struct {
+	PaymentID           uint   `json:"payment_id"`
+	Status              string `json:"status"`
+	AmountCents         int64  `json:"amount_cents"`
+	RefundedAmountCents int64  `json:"refunded_amount_cents"`
+}
+
+// Refund handles POST /api/me/payments/:id/refund
+func (h *PaymentRefundHandler) Refund(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	paymentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || paymentID == 0 {
+		httperr.BadRequest(c, "invalid_payment_id", "ID de pagamento inválido.")
+		return
+	}
+
+	var req refundPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	var p models.Payment
+	if err := h.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", paymentID, barbershopID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httperr.NotFound(c, "payment_not_found", "Pagamento não encontrado.")
+			return
+		}
+		httperr.Internal(c, "failed_to_load_payment", "Erro ao carregar pagamento.")
+		return
+	}
+
+	gw, err := h.gatewayFor(ctx, &p)
+	if err != nil {
+		log.Printf("[REFUND] gateway error barbershop=%d payment=%d: %v", barbershopID, p.ID, err)
+		httperr.Internal(c, "payment_gateway_error", "Erro ao inicializar gateway de pagamento.")
+		return
+	}
+
+	updated, err := h.refund.Execute(ctx, ucPayment.RefundPaymentInput{
+		BarbershopID: barbershopID,
+		PaymentID:    p.ID,
+		AmountCents:  req.AmountCents,
+		Reason:       req.Reason,
+	}, gw)
+	if err != nil {
+		switch {
+		case apperr.IsBusiness(err, "payment_not_found"):
+			httperr.NotFound(c, "payment_not_found", "Pagamento não encontrado.")
+		case apperr.IsBusiness(err, "payment_not_refundable"):
+			httperr.Write(c, http.StatusConflict, "payment_not_refundable", "Este pagamento não pode ser estornado.")
+		case apperr.IsBusiness(err, "invalid_refund_amount"):
+			httperr.BadRequest(c, "invalid_refund_amount", "Valor de estorno inválido.")
+		case apperr.IsBusiness(err, "refund_not_supported"):
+			httperr.BadRequest(c, "refund_not_supported", "Este pagamento não pode ser estornado pelo sistema. Faça o estorno no painel do provedor.")
+		default:
+			log.Printf("[REFUND] barbershop=%d payment=%d error=%v", barbershopID, p.ID, err)
+			httperr.Write(c, http.StatusBadGateway, "refund_failed", "O provedor de pagamento recusou o estorno.")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, refundPaymentResponse{
+		PaymentID:           updated.ID,
+		Status:              string(updated.Status),
+		AmountCents:         updated.Amount,
+		RefundedAmountCents: updated.RefundedAmount,
+	})
+}
+
+// gatewayFor retorna o gateway do provider que criou o pagamento.
+// Sem provider registrado (payments antigos ou gateway global), retorna nil e o
+// use case usa o gateway padrão configurado via MP_PROVIDER.
+func (h *PaymentRefundHandler) gatewayFor(ctx context.Context, p *models.Payment) (domain.TransparentGateway, error) {
+	if h.registry == nil {
+		return nil, nil
+	}
+
+	var (
+		gw  domain.TransparentGateway
+		err error
+	)
+	if p.Provider != nil && *p.Provider != "" {
+		gw, err = h.registry.GatewayForProvider(ctx, p.BarbershopID, *p.Provider)
+	} else {
+		var paymentCfg models.BarbershopPaymentConfig
+		_ = h.db.WithContext(ctx).Where("barbershop_id = ?", p.BarbershopID).First(&paymentCfg).Error
+		paymentCfg.BarbershopID = p.BarbershopID
+		gw, err = h.registry.TransparentGatewayFor(ctx, paymentCfg)
+	}
+
+	if errors.Is(err, paymentinfra.ErrPaymentNotConfigured) {
+		return nil, nil
+	}
+	return gw, err
+}
@@ -48,13 +48,16 @@ func (h *PublicCheckoutHandler) Checkout(c *gin.Context) {
 	}
 
 	var req dto.PublicOrchestratedCheckoutRequestDTO
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.ServiceID == 0 && len(req.ServiceIDs) == 0 && req.ComboID == 0) {
 		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
 		return
 	}
 	req.IdempotencyKey = strings.TrimSpace(c.GetHeader("X-Idempotency-Key"))
 	out, err := h.uc.Execute(c.Request.Context(), barbershopID, req)
 	if err != nil {
+		if writeCouponError(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, domainService.ErrServiceNotFound):
 			httperr.BadRequest(c, "service_not_found", "Serviço não encontrado.")
@@ -68,6 +71,21 @@ func (h *PublicCheckoutHandler) Checkout(c *gin.Context) {
 		case apperr.IsBusiness(err, "too_soon"):
 			httperr.BadRequest(c, "too_soon", "Horário inválido.")
 
+		case apperr.IsBusiness(err, "barber_not_found"):
+			httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+
+		case apperr.IsBusiness(err, "service_not_offered"):
+			httperr.BadRequest(c, "service_not_offered", "Este barbeiro não faz este serviço.")
+
+		case apperr.IsBusiness(err, "too_many_services"):
+			httperr.BadRequest(c, "too_many_services", "Máximo de 5 serviços por agendamento.")
+
+		case apperr.IsBusiness(err, "duplicate_service"):
+			httperr.BadRequest(c, "duplicate_service", "Serviço repetido no agendamento.")
+
+		case apperr.IsBusiness(err, "combo_not_found"):
+			httperr.BadRequest(c, "combo_not_found", "Combo não encontrado.")
+
 		case apperr.IsBusiness(err, "outside_working_hours"):
 			httperr.BadRequest(c, "outside_working_hours", "Fora do horário de atendimento.")
 
@@ -155,8 +155,27 @@ func (h *PublicHandler) ListServices(c *gin.Context) {
 	category := strings.TrimSpace(strings.ToLower(c.Query("category")))
 	query := strings.TrimSpace(c.Query("query"))
 
+	// barber_id: catálogo do barbeiro escolhido (preço/duração próprios e sem
+	// os serviços que ele não faz).
+	barberID, ok := parsePublicBarberID(c.Query("barber_id"))
+	if !ok {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return
+	}
+	if barberID != 0 {
+		repo := infraRepo.NewAppointmentGormRepository(h.db)
+		if err := appointmentUC.ValidateBookingBarber(c.Request.Context(), repo, shop.ID, barberID); err != nil {
+			if apperr.IsBusiness(err, "barber_not_found") {
+				httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+				return
+			}
+			httperr.Internal(c, "failed_to_list_services", "Erro ao listar serviços.")
+			return
+		}
+	}
+
 	// Cache somente para listagem sem filtros (caso mais comum no fluxo de booking).
-	useCache := category == "" && query == ""
+	useCache := category == "" && query == "" && barberID == 0
 
 	if useCache {
 		pubServicesCacheMu.RLock()
@@ -180,6 +199,7 @@ func (h *PublicHandler) ListServices(c *gin.Context) {
 			BarbershopID: shop.ID,
 			Category:     category,
 			Query:        query,
+			BarberID:     barberID,
 		},
 	)
 	if err != nil {
@@ -461,15 +481,28 @@ func (h *PublicHandler) CheckoutCart(c *gin.Context) {
 		return
 	}
 
+	// Corpo opcional: {"coupon_code": "..."}.
+	var req dto.PublicCheckoutCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+			return
+		}
+	}
+
 	cartKey := strings.TrimSpace(c.GetHeader("X-Cart-Key"))
 	order, err := h.checkoutCartUC.Execute(
 		c.Request.Context(),
 		cartUC.CheckoutCartInput{
 			CartKey:      cartKey,
 			BarbershopID: shop.ID,
+			CouponCode:   req.CouponCode,
 		},
 	)
 	if err != nil {
+		if writeCouponError(c, err) {
+			return
+		}
 		switch {
 		case errors.Is(err, cartUC.ErrCheckoutInvalidCartKey):
 			httperr.BadRequest(c, "invalid_cart_key", "Carrinho inválido.")
@@ -489,8 +522,9 @@ func (h *PublicHandler) CheckoutCart(c *gin.Context) {
 		Order: dto.PublicCheckoutOrderDTO{
 			OrderID:    order.ID,
 			Status:     string(order.Status),
-			TotalCents: order.TotalAmount,
-			ItemsCount: len(order.Items),
+			TotalCents:    order.TotalAmount,
+			DiscountCents: order.DiscountAmount,
+			ItemsCount:    len(order.Items),
 		},
 		NextStep: dto.PublicCheckoutNextStepDTO{
 			Action:     "order_payment_required",
@@ -557,6 +591,44 @@ func (h *PublicHandler) GetServiceSuggestion(c *gin.Context) {
 	})
 }
 
+////////////////////////////////////////////////////////
+// PUBLIC BARBERS
+////////////////////////////////////////////////////////
+
+// ListBarbers lista os barbeiros ativos para o cliente escolher no booking.
+// Sem escolha (barber_id omitido ou "any"), o sistema atribui um barbeiro livre.
+func (h *PublicHandler) ListBarbers(c *gin.Context) {
+	shop, ok := h.getPublicBarbershop(c)
+	if !ok {
+		return
+	}
+
+	type barberDto struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var barbers []barberDto
+	if err := h.db.WithContext(c.Request.Context()).
+		Model(&models.User{}).
+		Select("id, name").
+		Where("barbershop_id = ? AND active = true", shop.ID).
+		Order("id ASC").
+		Scan(&barbers).Error; err != nil {
+		httperr.Internal(c, "failed_to_list_barbers", "Erro ao listar barbeiros.")
+		return
+	}
+	if barbers == nil {
+		barbers = []barberDto{}
+	}
+
+	setCacheControl(c, 60)
+	c.JSON(http.StatusOK, gin.H{
+		"barbershop": gin.H{"id": shop.ID, "name": shop.Name, "slug": shop.Slug},
+		"barbers":    barbers,
+	})
+}
+
 ////////////////////////////////////////////////////////
 // AVAILABILITY (timezone-safe)
 ////////////////////////////////////////////////////////
@@ -564,28 +636,58 @@ func (h *PublicHandler) GetServiceSuggestion(c *gin.Context) {
 func (h *PublicHandler) AvailabilityForClient(c *gin.Context) {
 	dateStr := strings.TrimSpace(c.Query("date"))
 	productIDStr := strings.TrimSpace(c.Query("product_id"))
+	productIDsStr := strings.TrimSpace(c.Query("product_ids")) // "1,2": vários serviços, em ordem
+	comboIDStr := strings.TrimSpace(c.Query("combo_id"))       // serviços de um combo
 
-	if dateStr == "" || productIDStr == "" {
+	if dateStr == "" || (productIDStr == "" && productIDsStr == "" && comboIDStr == "") {
 		httperr.BadRequest(c, "missing_params", "Data e serviço obrigatórios.")
 		return
 	}
 
-	productID, err := strconv.ParseUint(productIDStr, 10, 64)
-	if err != nil || productID == 0 {
+	var productID uint64
+	if productIDStr != "" {
+		var err error
+		productID, err = strconv.ParseUint(productIDStr, 10, 64)
+		if err != nil || productID == 0 {
+			httperr.BadRequest(c, "invalid_product_id", "Serviço inválido.")
+			return
+		}
+	}
+
+	productIDs, ok := parseIDList(productIDsStr)
+	if !ok {
 		httperr.BadRequest(c, "invalid_product_id", "Serviço inválido.")
 		return
 	}
 
+	var comboID uint64
+	if comboIDStr != "" {
+		var err error
+		comboID, err = strconv.ParseUint(comboIDStr, 10, 64)
+		if err != nil || comboID == 0 {
+			httperr.BadRequest(c, "invalid_combo_id", "Combo inválido.")
+			return
+		}
+	}
+
 	shop, ok := h.getPublicBarbershop(c)
 	if !ok {
 		return
 	}
 
-	var barber models.User
-	if err := h.db.
-		Where("barbershop_id = ? AND role = ?", shop.ID, "owner").
-		First(&barber).Error; err != nil {
-		httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+	barberID, ok := parsePublicBarberID(c.Query("barber_id"))
+	if !ok {
+		httperr.BadRequest(c, "invalid_barber_id", "Barbeiro inválido.")
+		return
+	}
+
+	repo := infraRepo.NewAppointmentGormRepository(h.db)
+	if err := appointmentUC.ValidateBookingBarber(c.Request.Context(), repo, shop.ID, barberID); err != nil {
+		if apperr.IsBusiness(err, "barber_not_found") {
+			httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+			return
+		}
+		httperr.Internal(c, "availability_failed", "Erro ao calcular horários.")
 		return
 	}
 
@@ -599,15 +701,16 @@ func (h *PublicHandler) AvailabilityForClient(c *gin.Context) {
 
 	date := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, loc)
 
-	repo := infraRepo.NewAppointmentGormRepository(h.db)
 	uc := appointmentUC.NewGetAvailability(repo)
 
 	slots, err := uc.Execute(
 		c.Request.Context(),
 		domain.AvailabilityInput{
 			BarbershopID: shop.ID,
-			BarberID:     barber.ID,
+			BarberID:     barberID,
 			ProductID:    uint(productID),
+			ProductIDs:   productIDs,
+			ComboID:      uint(comboID),
 			Date:         date,
 		},
 	)
@@ -616,19 +719,61 @@ func (h *PublicHandler) AvailabilityForClient(c *gin.Context) {
 			httperr.BadRequest(c, "product_not_found", "Serviço inválido.")
 			return
 		}
+		if apperr.IsBusiness(err, "too_many_services") || apperr.IsBusiness(err, "duplicate_service") ||
+			apperr.IsBusiness(err, "combo_not_found") {
+			mapPublicCreateErrors(c, err)
+			return
+		}
 		httperr.Internal(c, "availability_failed", "Erro ao calcular horários.")
 		return
 	}
 
 	setNoStore(c)
 	c.JSON(http.StatusOK, gin.H{
-		"date":     dateStr,
-		"timezone": shop.Timezone,
-		"slots":    slots,
+		"date":      dateStr,
+		"timezone":  shop.Timezone,
+		"barber_id": barberID,
+		"slots":     slots,
 	})
 }
 
+// parseIDList interpreta uma lista de IDs separados por vírgula ("1,2,3").
+// Vazio devolve nil.
+func parseIDList(raw string) ([]uint, bool) {
+	if raw == "" {
+		return nil, true
+	}
+	parts := strings.Split(raw, ",")
+	ids := make([]uint, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || v == 0 {
+			return nil, false
+		}
+		ids = append(ids, uint(v))
+	}
+	return ids, true
+}
+
+// parsePublicBarberID interpreta o barbeiro escolhido no booking público.
+// Vazio ou "any" = qualquer barbeiro disponível (0).
+func parsePublicBarberID(raw string) (uint, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "any") {
+		return 0, true
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return uint(v), true
+}
+
 func mapPublicCreateErrors(c *gin.Context, err error) {
+	if writeCouponError(c, err) {
+		return
+	}
+
 	switch {
 	case apperr.IsBusiness(err, "barbershop_not_found"):
 		httperr.NotFound(c, "barbershop_not_found", "Barbearia não encontrada.")
@@ -650,6 +795,21 @@ func mapPublicCreateErrors(c *gin.Context, err error) {
 	case apperr.IsBusiness(err, "product_not_found"):
 		httperr.BadRequest(c, "product_not_found", "Serviço não encontrado.")
 
+	case apperr.IsBusiness(err, "barber_not_found"):
+		httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+
+	case apperr.IsBusiness(err, "service_not_offered"):
+		httperr.BadRequest(c, "service_not_offered", "Este barbeiro não faz este serviço.")
+
+	case apperr.IsBusiness(err, "too_many_services"):
+		httperr.BadRequest(c, "too_many_services", "Máximo de 5 serviços por agendamento.")
+
+	case apperr.IsBusiness(err, "duplicate_service"):
+		httperr.BadRequest(c, "duplicate_service", "Serviço repetido no agendamento.")
+
+	case apperr.IsBusiness(err, "combo_not_found"):
+		httperr.BadRequest(c, "combo_not_found", "Combo não encontrado.")
+
 	case apperr.IsBusiness(err, "outside_working_hours"):
 		httperr.BadRequest(c, "outside_working_hours", "Fora do horário de atendimento.")
 
@@ -672,16 +832,19 @@ func (h *PublicHandler) CreateAppointment(c *gin.Context) {
 	}
 
 	var req dto.PublicCreateAppointmentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.ServiceID == 0 && len(req.ServiceIDs) == 0 && req.ComboID == 0) {
 		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
 		return
 	}
 
-	var barber models.User
-	if err := h.db.
-		Where("barbershop_id = ? AND role = ?", shop.ID, "owner").
-		First(&barber).Error; err != nil {
-		httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+	var barberID uint
+	if req.BarberID != nil {
+		barberID = *req.BarberID
+	}
+
+	repo := infraRepo.NewAppointmentGormRepository(h.db)
+	if err := appointmentUC.ValidateBookingBarber(c.Request.Context(), repo, shop.ID, barberID); err != nil {
+		mapPublicCreateErrors(c, err)
 		return
 	}
 
@@ -691,15 +854,18 @@ func (h *PublicHandler) CreateAppointment(c *gin.Context) {
 		c.Request.Context(),
 		appointmentUC.CreatePrivateAppointmentInput{
 			BarbershopID:   shop.ID,
-			BarberID:       barber.ID,
+			BarberID:       barberID,
 			ClientName:     req.ClientName,
 			ClientPhone:    req.ClientPhone,
 			ClientEmail:    req.ClientEmail,
 			ProductID:      req.ServiceID,
+			ProductIDs:     req.ServiceIDs,
+			ComboID:        req.ComboID,
 			Date:           req.Date,
 			Time:           req.Time,
 			Notes:          req.Notes,
 			IdempotencyKey: idempotencyKey,
+			CouponCode:     req.CouponCode,
 		},
 	)
 	if err != nil {
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	paymentinfra "github.com/BruksfildServices01/barber-scheduler/internal/integration/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+	ucPackage "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicepackage"
+)
+
+// PublicPackageHandler expõe a vitrine de pacotes e combos e a compra de
+// pacotes pelo cliente.
+type PublicPackageHandler struct {
+	db             *gorm.DB
+	listPackagesUC *ucPackage.ListPackages
+	listCombosUC   *ucPackage.ListCombos
+	purchaseUC     *ucPackage.PurchasePackage
+	registry       *paymentinfra.ProviderRegistry
+}
+
+func NewPublicPackageHandler(
+	db *gorm.DB,
+	listPackagesUC *ucPackage.ListPackages,
+	listCombosUC *ucPackage.ListCombos,
+	purchaseUC *ucPackage.PurchasePackage,
+	registry *paymentinfra.ProviderRegistry,
+) *PublicPackageHandler {
+	return &PublicPackageHandler{
+		db:             db,
+		listPackagesUC: listPackagesUC,
+		listCombosUC:   listCombosUC,
+		purchaseUC:     purchaseUC,
+		registry:       registry,
+	}
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/packages
+// ──────────────────────────────────────────────────────────────────
+
+func (h *PublicPackageHandler) ListPackages(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	list, err := h.listPackagesUC.Execute(c.Request.Context(), shop.ID, true)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_packages", "Erro ao listar pacotes.")
+		return
+	}
+
+	setCacheControl(c, 120)
+	c.JSON(http.StatusOK, gin.H{"packages": list})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/combos
+// ──────────────────────────────────────────────────────────────────
+
+func (h *PublicPackageHandler) ListCombos(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	list, err := h.listCombosUC.Execute(c.Request.Context(), shop.ID, true)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_combos", "Erro ao listar combos.")
+		return
+	}
+
+	setCacheControl(c, 120)
+	c.JSON(http.StatusOK, gin.H{"combos": list})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// POST /api/public/:slug/packages/purchase
+// ──────────────────────────────────────────────────────────────────
+
+type purchasePackageRequest struct {
+	PackageID       uint   `json:"package_id"       binding:"required"`
+	ClientName      string `json:"client_name"      binding:"required"`
+	ClientPhone     string `json:"client_phone"     binding:"required"`
+	PayerEmail      string `json:"payer_email"      binding:"required,email"`
+	PayerCPF        string `json:"payer_cpf"`
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+	Token           string `json:"token"`
+	Installments    int    `json:"installments"`
+}
+
+type purchasePackageResponse struct {
+	ClientPackageID uint   `json:"client_package_id"`
+	PaymentID       uint   `json:"payment_id"`
+	MPPaymentID     int64  `json:"mp_payment_id"`
+	Status          string `json:"status"`
+	// PIX
+	QRCode       string `json:"qr_code,omitempty"`
+	QRCodeBase64 string `json:"qr_code_base64,omitempty"`
+	TicketURL    string `json:"ticket_url,omitempty"`
+}
+
+func (h *PublicPackageHandler) Purchase(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	var paymentCfg models.BarbershopPaymentConfig
+	hasCfg := h.db.WithContext(c.Request.Context()).
+		Where("barbershop_id = ?", shop.ID).
+		First(&paymentCfg).Error == nil
+	if !hasCfg {
+		paymentCfg.BarbershopID = shop.ID
+	}
+
+	gw, err := h.registry.TransparentGatewayFor(c.Request.Context(), paymentCfg)
+	if err != nil {
+		if errors.Is(err, paymentinfra.ErrPaymentNotConfigured) {
+			httperr.BadRequest(c, "payment_not_configured", "Esta barbearia ainda não configurou o pagamento online.")
+			return
+		}
+		log.Printf("[package purchase] gateway error barbershop=%d: %v", shop.ID, err)
+		httperr.Internal(c, "purchase_failed", "Erro ao processar compra do pacote.")
+		return
+	}
+
+	var req purchasePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	if req.Installments <= 0 {
+		req.Installments = 1
+	}
+
+	result, err := h.purchaseUC.Execute(c.Request.Context(), ucPackage.PurchasePackageInput{
+		BarbershopID:    shop.ID,
+		PackageID:       req.PackageID,
+		ClientName:      req.ClientName,
+		ClientPhone:     req.ClientPhone,
+		PayerEmail:      req.PayerEmail,
+		PayerCPF:        req.PayerCPF,
+		PaymentMethodID: req.PaymentMethodID,
+		Token:           req.Token,
+		Installments:    req.Installments,
+	}, gw)
+	if err != nil {
+		switch {
+		case apperr.IsBusiness(err, "package_not_found"):
+			httperr.BadRequest(c, "package_not_found", "Pacote não encontrado.")
+		case apperr.IsBusiness(err, "payment_rejected"):
+			httperr.BadRequest(c, "payment_rejected", "Pagamento recusado. Verifique os dados do cartão.")
+		default:
+			log.Printf("[package purchase] unexpected error: %v", err)
+			httperr.Internal(c, "purchase_failed", "Erro ao processar compra do pacote.")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, purchasePackageResponse{
+		ClientPackageID: result.ClientPackageID,
+		PaymentID:       result.PaymentID,
+		MPPaymentID:     result.MPPaymentID,
+		Status:          result.Status,
+		QRCode:          result.QRCode,
+		QRCodeBase64:    result.QRCodeBase64,
+		TicketURL:       result.TicketURL,
+	})
+}
+
+// ──────────────────────────────────────────────────────────────────
+// GET /api/public/:slug/packages/purchases/:id/payment/status
+// Polling para PIX — retorna status da compra do pacote
+// ──────────────────────────────────────────────────────────────────
+
+func (h *PublicPackageHandler) PaymentStatus(c *gin.Context) {
+	shop, ok := resolveShopBySlug(c, h.db)
+	if !ok {
+		return
+	}
+
+	cpID64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || cpID64 == 0 {
+		httperr.BadRequest(c, "invalid_client_package_id", "ID inválido.")
+		return
+	}
+
+	repo := infraRepo.NewServicePackageGormRepository(h.db)
+	cp, err := repo.GetClientPackage(c.Request.Context(), shop.ID, uint(cpID64))
+	if err != nil {
+		httperr.Internal(c, "failed_to_load_package", "Erro ao carregar pacote.")
+		return
+	}
+	if cp == nil {
+		httperr.NotFound(c, "client_package_not_found", "Pacote não encontrado.")
+		return
+	}
+
+	// Se ainda pendente, tenta verificar o status no provider e ativar inline.
+	if cp.Status == models.ClientPackagePendingPayment {
+		h.tryActivateFromProvider(c.Request.Context(), cp, shop)
+	}
+
+	setNoStore(c)
+	c.JSON(http.StatusOK, gin.H{
+		"client_package_id": cp.ID,
+		"status":            cp.Status,
+		"expires_at":        cp.ExpiresAt,
+	})
+}
+
+// tryActivateFromProvider ativa a compra quando o pagamento já está pago
+// (webhook chegou antes) ou o provider o aprovou. Atualiza cp in-place.
+func (h *PublicPackageHandler) tryActivateFromProvider(ctx context.Context, cp *models.ClientPackage, shop *models.Barbershop) {
+	var pmt models.Payment
+	if err := h.db.WithContext(ctx).
+		Where("client_package_id = ?", cp.ID).
+		Order("id DESC").
+		First(&pmt).Error; err != nil {
+		return
+	}
+
+	switch pmt.Status {
+	case "paid":
+	case "pending":
+		if !providerApproved(ctx, h.db, h.registry, shop.ID, &pmt) {
+			return
+		}
+	default:
+		return // expirado ou outro estado terminal — não ativa
+	}
+
+	// Ativa a compra e marca o payment como paid. Idempotente: os WHERE
+	// condicionais são no-op se já no estado final.
+	now := time.Now().UTC()
+	txErr := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := infraRepo.NewServicePackageGormRepository(tx).ActivateClientPackage(ctx, cp.ID, now); err != nil {
+			return err
+		}
+		return tx.Model(&models.Payment{}).
+			Where("id = ? AND status = ?", pmt.ID, "pending").
+			Updates(map[string]any{"status": "paid", "paid_at": now}).Error
+	})
+	if txErr != nil {
+		log.Printf("[paymentstatus] failed to activate client package %d: %v", cp.ID, txErr)
+		return
+	}
+
+	reloaded, err := infraRepo.NewServicePackageGormRepository(h.db).GetClientPackage(ctx, shop.ID, cp.ID)
+	if err == nil && reloaded != nil {
+		*cp = *reloaded
+	}
+}
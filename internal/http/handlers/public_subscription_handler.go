@@ -104,6 +104,10 @@ type purchaseSubscriptionRequest struct {
 	PaymentMethodID string `json:"payment_method_id" binding:"required"`
 	Token           string `json:"token"`
 	Installments    int    `json:"installments"`
+	// AutoRenew guarda o cartão para a renovação automática (só cartão).
+	AutoRenew bool `json:"auto_renew"`
+	// CouponCode: desconto na primeira cobrança.
+	CouponCode string `json:"coupon_code"`
 }
 
 type purchaseSubscriptionResponse struct {
@@ -111,6 +115,7 @@ type purchaseSubscriptionResponse struct {
 	PaymentID      uint   `json:"payment_id"`
 	MPPaymentID    int64  `json:"mp_payment_id"`
 	Status         string `json:"status"`
+	AutoRenew      bool   `json:"auto_renew"`
 	// PIX
 	QRCode       string `json:"qr_code,omitempty"`
 	QRCodeBase64 string `json:"qr_code_base64,omitempty"`
@@ -165,10 +170,15 @@ func (h *PublicSubscriptionHandler) Purchase(c *gin.Context) {
 		PaymentMethodID: req.PaymentMethodID,
 		Token:           req.Token,
 		Installments:    req.Installments,
+		AutoRenew:       req.AutoRenew,
+		CouponCode:      req.CouponCode,
 	}
 
 	result, err := h.purchaseUC.Execute(c.Request.Context(), input, gw)
 	if err != nil {
+		if writeCouponError(c, err) {
+			return
+		}
 		switch {
 		case apperr.IsBusiness(err, "plan_not_found"):
 			httperr.BadRequest(c, "plan_not_found", "Plano não encontrado.")
@@ -188,6 +198,7 @@ func (h *PublicSubscriptionHandler) Purchase(c *gin.Context) {
 		PaymentID:      result.PaymentID,
 		MPPaymentID:    result.MPPaymentID,
 		Status:         result.Status,
+		AutoRenew:      result.AutoRenew,
 		QRCode:         result.QRCode,
 		QRCodeBase64:   result.QRCodeBase64,
 		TicketURL:      result.TicketURL,
@@ -259,7 +270,26 @@ func (h *PublicSubscriptionHandler) tryActivateFromProvider(ctx context.Context,
 		return // expirado ou outro estado terminal — não ativa
 	}
 
-	// 3. Determina o ID externo a consultar no provider.
+	// 3. Consulta o provider.
+	if !providerApproved(ctx, h.db, h.registry, shop.ID, &pmt) {
+		return
+	}
+
+	// 4. Aprovado — ativa subscription e marca payment como paid.
+	h.activateSubscription(ctx, sub, &pmt)
+}
+
+// providerApproved consulta o provider correto pelo campo payment.provider e
+// indica se o pagamento pendente foi aprovado. Suporta PagBank (via
+// provider_payment_id) e MP legado (via mp_payment_id).
+func providerApproved(
+	ctx context.Context,
+	db *gorm.DB,
+	registry *paymentinfra.ProviderRegistry,
+	shopID uint,
+	pmt *models.Payment,
+) bool {
+	// Determina o ID externo a consultar no provider.
 	// Prefere provider_payment_id (campo novo), fallback para mp_payment_id (legado MP).
 	providerPaymentID := ""
 	if pmt.ProviderPaymentID != nil && *pmt.ProviderPaymentID != "" {
@@ -268,46 +298,41 @@ func (h *PublicSubscriptionHandler) tryActivateFromProvider(ctx context.Context,
 		providerPaymentID = strconv.FormatInt(*pmt.MPPaymentID, 10)
 	}
 	if providerPaymentID == "" {
-		return // nenhum ID disponível para polling ainda
+		return false // nenhum ID disponível para polling ainda
 	}
 
-	// 4. Seleciona o gateway.
+	// Seleciona o gateway.
 	// Se o payment registrou o provider na criação, usa esse provider específico.
 	// Fallback: usa o provider ativo mais recente (pagamentos antigos sem campo provider).
 	var gw domain.TransparentGateway
 	if pmt.Provider != nil && *pmt.Provider != "" {
 		var err error
-		gw, err = h.registry.GatewayForProvider(ctx, shop.ID, *pmt.Provider)
+		gw, err = registry.GatewayForProvider(ctx, shopID, *pmt.Provider)
 		if err != nil {
-			return
+			return false
 		}
 	} else {
 		var paymentCfg models.BarbershopPaymentConfig
-		_ = h.db.WithContext(ctx).Where("barbershop_id = ?", shop.ID).First(&paymentCfg).Error
-		paymentCfg.BarbershopID = shop.ID
+		_ = db.WithContext(ctx).Where("barbershop_id = ?", shopID).First(&paymentCfg).Error
+		paymentCfg.BarbershopID = shopID
 		var err error
-		gw, err = h.registry.TransparentGatewayFor(ctx, paymentCfg)
+		gw, err = registry.TransparentGatewayFor(ctx, paymentCfg)
 		if err != nil {
-			return
+			return false
 		}
 	}
 
-	// 5. Consulta status via duck typing — qualquer gateway que implementa GetPaymentStatus.
+	// Consulta status via duck typing — qualquer gateway que implementa GetPaymentStatus.
 	type statusChecker interface {
 		GetPaymentStatus(ctx context.Context, providerPaymentID string) (domain.ProviderPaymentStatus, error)
 	}
 	checker, ok := gw.(statusChecker)
 	if !ok {
-		return
+		return false
 	}
 
 	status, err := checker.GetPaymentStatus(ctx, providerPaymentID)
-	if err != nil || status != domain.ProviderStatusApproved {
-		return
-	}
-
-	// 6. Aprovado — ativa subscription e marca payment como paid.
-	h.activateSubscription(ctx, sub, &pmt)
+	return err == nil && status == domain.ProviderStatusApproved
 }
 
 // activateSubscription ativa uma subscription pending_payment e marca o payment como paid.
@@ -514,9 +539,14 @@ func (h *PublicSubscriptionHandler) LookupSubscriber(c *gin.Context) {
 // ──────────────────────────────────────────────────────────────────
 
 func (h *PublicSubscriptionHandler) resolveShop(c *gin.Context) (*models.Barbershop, bool) {
+	return resolveShopBySlug(c, h.db)
+}
+
+// resolveShopBySlug carrega a barbearia do :slug ou responde 404/500.
+func resolveShopBySlug(c *gin.Context, db *gorm.DB) (*models.Barbershop, bool) {
 	slug := c.Param("slug")
 	var shop models.Barbershop
-	if err := h.db.WithContext(c.Request.Context()).
+	if err := db.WithContext(c.Request.Context()).
 		Where("slug = ?", slug).
 		First(&shop).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	domainReport "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucReport "github.com/BruksfildServices01/barber-scheduler/internal/usecase/report"
+)
+
+// ReportSubscriptionHandler administra os relatórios enviados por email
+// (assinaturas, envio manual e histórico).
+type ReportSubscriptionHandler struct {
+	listUC       *ucReport.ListSubscriptions
+	createUC     *ucReport.CreateSubscription
+	updateUC     *ucReport.UpdateSubscription
+	deleteUC     *ucReport.DeleteSubscription
+	sendNowUC    *ucReport.SendNow
+	deliveriesUC *ucReport.ListDeliveries
+}
+
+func NewReportSubscriptionHandler(
+	listUC *ucReport.ListSubscriptions,
+	createUC *ucReport.CreateSubscription,
+	updateUC *ucReport.UpdateSubscription,
+	deleteUC *ucReport.DeleteSubscription,
+	sendNowUC *ucReport.SendNow,
+	deliveriesUC *ucReport.ListDeliveries,
+) *ReportSubscriptionHandler {
+	return &ReportSubscriptionHandler{
+		listUC:       listUC,
+		createUC:     createUC,
+		updateUC:     updateUC,
+		deleteUC:     deleteUC,
+		sendNowUC:    sendNowUC,
+		deliveriesUC: deliveriesUC,
+	}
+}
+
+type ReportSubscriptionRequest struct {
+	Report     string   `json:"report" binding:"required"`
+	Period     string   `json:"period" binding:"required"`
+	Format     string   `json:"format" binding:"required"`
+	Recipients []string `json:"recipients" binding:"required"`
+	// HH:MM no fuso da barbearia
+	SendTime string `json:"send_time" binding:"required"`
+	Active   *bool  `json:"active"`
+}
+
+func writeReportError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domainReport.ErrInvalidRecipients):
+		// a mensagem aponta o endereço recusado
+		httperr.BadRequest(c, "invalid_recipients", err.Error())
+	case errors.Is(err, domainReport.ErrInvalidReport),
+		errors.Is(err, domainReport.ErrInvalidPeriod),
+		errors.Is(err, domainReport.ErrInvalidFormat),
+		errors.Is(err, domainReport.ErrInvalidSendTime),
+		errors.Is(err, domainReport.ErrPeriodUnsupported),
+		errors.Is(err, ucReport.ErrInvalidBarbershop):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucReport.ErrBarbershopNotFound),
+		errors.Is(err, ucReport.ErrSubscriptionNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	case errors.Is(err, ucReport.ErrEmailUnavailable):
+		httperr.Write(c, http.StatusConflict, err.Error(), err.Error())
+	default:
+		httperr.Internal(c, fallback, fallback)
+	}
+}
+
+// GET /api/me/report-subscriptions
+func (h *ReportSubscriptionHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_report_subscriptions", "failed_to_list_report_subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": list})
+}
+
+// POST /api/me/report-subscriptions
+func (h *ReportSubscriptionHandler) Create(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+	userID := c.MustGet(middleware.ContextUserID).(uint)
+
+	var req ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	in := req.input(barbershopID, 0)
+	in.UserID = &userID
+
+	out, err := h.createUC.Execute(c.Request.Context(), in)
+	if err != nil {
+		writeReportError(c, err, "failed_to_create_report_subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// PUT /api/me/report-subscriptions/:id
+func (h *ReportSubscriptionHandler) Update(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_report_subscription_id")
+	if !ok {
+		return
+	}
+
+	var req ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.updateUC.Execute(c.Request.Context(), req.input(barbershopID, id))
+	if err != nil {
+		writeReportError(c, err, "failed_to_update_report_subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (r ReportSubscriptionRequest) input(barbershopID, id uint) ucReport.SubscriptionInput {
+	return ucReport.SubscriptionInput{
+		BarbershopID: barbershopID,
+		ID:           id,
+		Report:       r.Report,
+		Period:       r.Period,
+		Format:       r.Format,
+		Recipients:   r.Recipients,
+		SendTime:     r.SendTime,
+		Active:       r.Active,
+	}
+}
+
+// DELETE /api/me/report-subscriptions/:id
+func (h *ReportSubscriptionHandler) Delete(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_report_subscription_id")
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUC.Execute(c.Request.Context(), barbershopID, id); err != nil {
+		writeReportError(c, err, "failed_to_delete_report_subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /api/me/report-subscriptions/:id/send
+// Envia agora o último período fechado. Falha de envio volta como registro
+// do histórico com status failed, não como erro HTTP.
+func (h *ReportSubscriptionHandler) SendNow(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_report_subscription_id")
+	if !ok {
+		return
+	}
+
+	out, err := h.sendNowUC.Execute(c.Request.Context(), barbershopID, id)
+	if err != nil {
+		writeReportError(c, err, "failed_to_send_report")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /api/me/report-deliveries?subscription_id=&page=&limit=
+func (h *ReportSubscriptionHandler) Deliveries(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var subscriptionID *uint
+	if raw := c.Query("subscription_id"); raw != "" {
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v == 0 {
+			httperr.BadRequest(c, "invalid_report_subscription_id", "invalid_report_subscription_id")
+			return
+		}
+		id := uint(v)
+		subscriptionID = &id
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	out, err := h.deliveriesUC.Execute(c.Request.Context(), ucReport.ListDeliveriesInput{
+		BarbershopID:   barbershopID,
+		SubscriptionID: subscriptionID,
+		Limit:          limit,
+		Offset:         (page - 1) * limit,
+	})
+	if err != nil {
+		writeReportError(c, err, "failed_to_list_report_deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":       page,
+		"limit":      limit,
+		"total":      out.Total,
+		"deliveries": out.Deliveries,
+	})
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	domainCampaign "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	ucCampaign "github.com/BruksfildServices01/barber-scheduler/internal/usecase/campaign"
+)
+
+// SegmentHandler administra os segmentos de clientes usados pelas
+// campanhas e a prévia de quem entra em cada um.
+type SegmentHandler struct {
+	listUC    *ucCampaign.ListSegments
+	saveUC    *ucCampaign.SaveSegment
+	deleteUC  *ucCampaign.DeleteSegment
+	previewUC *ucCampaign.PreviewSegment
+}
+
+func NewSegmentHandler(
+	listUC *ucCampaign.ListSegments,
+	saveUC *ucCampaign.SaveSegment,
+	deleteUC *ucCampaign.DeleteSegment,
+	previewUC *ucCampaign.PreviewSegment,
+) *SegmentHandler {
+	return &SegmentHandler{
+		listUC:    listUC,
+		saveUC:    saveUC,
+		deleteUC:  deleteUC,
+		previewUC: previewUC,
+	}
+}
+
+type SegmentRequest struct {
+	Name    string                        `json:"name" binding:"required"`
+	Filters domainCampaign.SegmentFilters `json:"filters"`
+}
+
+type SegmentPreviewRequest struct {
+	Filters domainCampaign.SegmentFilters `json:"filters"`
+}
+
+// writeCampaignError traduz os erros de segmentos e campanhas; o que vier
+// da validação do texto segue para writeTemplateError.
+func writeCampaignError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domainCampaign.ErrInvalidSegmentFilter):
+		// a mensagem aponta o filtro recusado
+		httperr.BadRequest(c, "invalid_segment_filter", err.Error())
+	case errors.Is(err, ucCampaign.ErrInvalidBarbershop),
+		errors.Is(err, ucCampaign.ErrInvalidName),
+		errors.Is(err, ucCampaign.ErrSegmentRequired),
+		errors.Is(err, ucCampaign.ErrInvalidSchedule),
+		errors.Is(err, ucCampaign.ErrInvalidRecipientState):
+		httperr.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ucCampaign.ErrBarbershopNotFound),
+		errors.Is(err, ucCampaign.ErrSegmentNotFound),
+		errors.Is(err, ucCampaign.ErrCampaignNotFound),
+		errors.Is(err, ucCampaign.ErrClientNotFound),
+		errors.Is(err, ucCampaign.ErrUnsubscribeNotFound):
+		httperr.NotFound(c, err.Error(), err.Error())
+	case errors.Is(err, ucCampaign.ErrSegmentInUse),
+		errors.Is(err, ucCampaign.ErrCampaignNotEditable),
+		errors.Is(err, ucCampaign.ErrCampaignNotDeletable),
+		errors.Is(err, ucCampaign.ErrCampaignFinished),
+		errors.Is(err, ucCampaign.ErrChannelUnavailable),
+		errors.Is(err, ucCampaign.ErrWhatsAppNotConnected):
+		httperr.Write(c, http.StatusConflict, err.Error(), err.Error())
+	default:
+		writeTemplateError(c, err, fallback)
+	}
+}
+
+// GET /api/me/segments
+func (h *SegmentHandler) List(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	list, err := h.listUC.Execute(c.Request.Context(), barbershopID)
+	if err != nil {
+		httperr.Internal(c, "failed_to_list_segments", "failed_to_list_segments")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"segments": list})
+}
+
+// POST /api/me/segments
+func (h *SegmentHandler) Create(c *gin.Context) {
+	h.save(c, 0, http.StatusCreated)
+}
+
+// PUT /api/me/segments/:id
+func (h *SegmentHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "invalid_segment_id")
+	if !ok {
+		return
+	}
+	h.save(c, id, http.StatusOK)
+}
+
+func (h *SegmentHandler) save(c *gin.Context, id uint, status int) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	s, err := h.saveUC.Execute(c.Request.Context(), ucCampaign.SaveSegmentInput{
+		BarbershopID: barbershopID,
+		ID:           id,
+		Name:         req.Name,
+		Filters:      req.Filters,
+	})
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_save_segment")
+		return
+	}
+
+	c.JSON(status, s)
+}
+
+// DELETE /api/me/segments/:id
+func (h *SegmentHandler) Delete(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_segment_id")
+	if !ok {
+		return
+	}
+
+	if err := h.deleteUC.Execute(c.Request.Context(), barbershopID, id); err != nil {
+		writeCampaignError(c, err, "failed_to_delete_segment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// POST /api/me/segments/preview
+// Prévia de filtros ainda não salvos.
+func (h *SegmentHandler) Preview(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	var req SegmentPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	out, err := h.previewUC.Execute(c.Request.Context(), barbershopID, req.Filters)
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_preview_segment")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// GET /api/me/segments/:id/preview
+func (h *SegmentHandler) PreviewSaved(c *gin.Context) {
+	barbershopID := c.MustGet(middleware.ContextBarbershopID).(uint)
+
+	id, ok := parseIDParam(c, "invalid_segment_id")
+	if !ok {
+		return
+	}
+
+	out, err := h.previewUC.ExecuteSaved(c.Request.Context(), barbershopID, id)
+	if err != nil {
+		writeCampaignError(c, err, "failed_to_preview_segment")
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
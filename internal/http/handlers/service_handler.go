@@ -221,7 +221,8 @@ func (h *ServiceHandler) Delete(c *gin.Context) {
 	var activeCount int64
 	if err := h.db.WithContext(c.Request.Context()).
 		Model(&models.Appointment{}).
-		Where("barber_product_id = ? AND barbershop_id = ? AND status IN ('scheduled','awaiting_payment')", idUint, barbershopID).
+		Where("barbershop_id = ? AND status IN ('scheduled','awaiting_payment')", barbershopID).
+		Where("barber_product_id = ? OR id IN (SELECT appointment_id FROM appointment_services WHERE service_id = ?)", idUint, idUint).
 		Count(&activeCount).Error; err != nil {
 		httperr.Internal(c, "failed_to_check_appointments", "failed_to_check_appointments")
 		return
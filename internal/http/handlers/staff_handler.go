@@ -0,0 +1,549 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	"github.com/BruksfildServices01/barber-scheduler/internal/config"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/validators"
+)
+
+// staffInvitationTTL é a validade do link de convite enviado por e-mail.
+const staffInvitationTTL = 7 * 24 * time.Hour
+
+// StaffMailer é satisfeito por EmailNotifier e NoopNotifier.
+type StaffMailer interface {
+	SendStaffInvitation(ctx context.Context, to, barbershopName, inviteLink string) error
+}
+
+// StaffHandler gerencia a equipe da barbearia: convites para barbeiros,
+// aceite do convite e ativação/desativação de contas.
+type StaffHandler struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	mailer StaffMailer
+	audit  *audit.Dispatcher
+}
+
+func NewStaffHandler(
+	db *gorm.DB,
+	cfg *config.Config,
+	mailer StaffMailer,
+	auditDispatcher *audit.Dispatcher,
+) *StaffHandler {
+	return &StaffHandler{
+		db:     db,
+		cfg:    cfg,
+		mailer: mailer,
+		audit:  auditDispatcher,
+	}
+}
+
+type staffMemberResponse struct {
+	ID     uint            `json:"id"`
+	Name   string          `json:"name"`
+	Email  string          `json:"email"`
+	Phone  string          `json:"phone"`
+	Role   models.UserRole `json:"role"`
+	Active bool            `json:"active"`
+}
+
+// ======================================================
+// GET /me/staff
+// ======================================================
+
+// List retorna dono e barbeiros da barbearia (ativos e inativos).
+func (h *StaffHandler) List(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	var users []models.User
+	if err := h.db.WithContext(c.Request.Context()).
+		Where("barbershop_id = ?", barbershopID).
+		Order("id ASC").
+		Find(&users).Error; err != nil {
+		httperr.Internal(c, "failed_to_list_staff", "Erro ao listar equipe.")
+		return
+	}
+
+	resp := make([]staffMemberResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, staffMemberResponse{
+			ID:     u.ID,
+			Name:   u.Name,
+			Email:  u.Email,
+			Phone:  u.Phone,
+			Role:   u.Role,
+			Active: u.Active,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ======================================================
+// PATCH /me/staff/:id/active
+// ======================================================
+
+type setStaffActiveRequest struct {
+	Active *bool `json:"active" binding:"required"`
+}
+
+// SetActive ativa ou desativa um barbeiro. O dono não pode ser desativado.
+func (h *StaffHandler) SetActive(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+	ownerID := c.GetUint(middleware.ContextUserID)
+
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 {
+		httperr.BadRequest(c, "invalid_id", "ID inválido.")
+		return
+	}
+
+	var req setStaffActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var user models.User
+	if err := h.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id64, barbershopID).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httperr.NotFound(c, "staff_not_found", "Barbeiro não encontrado.")
+			return
+		}
+		httperr.Internal(c, "failed_to_load_staff", "Erro ao carregar barbeiro.")
+		return
+	}
+
+	if user.Role == models.UserRoleOwner {
+		httperr.BadRequest(c, "cannot_change_owner", "O dono da barbearia não pode ser desativado.")
+		return
+	}
+
+	if err := h.db.WithContext(ctx).
+		Model(&user).
+		Update("active", *req.Active).Error; err != nil {
+		httperr.Internal(c, "failed_to_update_staff", "Erro ao atualizar barbeiro.")
+		return
+	}
+
+	middleware.EvictUserCache(user.ID)
+
+	action := "staff_activated"
+	if !*req.Active {
+		action = "staff_deactivated"
+	}
+	h.audit.Dispatch(audit.Event{
+		BarbershopID: barbershopID,
+		UserID:       &ownerID,
+		Action:       action,
+		Entity:       "user",
+		EntityID:     &user.ID,
+	})
+
+	c.JSON(http.StatusOK, staffMemberResponse{
+		ID:     user.ID,
+		Name:   user.Name,
+		Email:  user.Email,
+		Phone:  user.Phone,
+		Role:   user.Role,
+		Active: *req.Active,
+	})
+}
+
+// ======================================================
+// POST /me/staff/invitations
+// ======================================================
+
+type createStaffInvitationRequest struct {
+	Name  string `json:"name"  binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+// Invite cria um convite e envia o link de aceite por e-mail.
+// Um novo convite para o mesmo e-mail revoga os convites pendentes anteriores.
+func (h *StaffHandler) Invite(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+	ownerID := c.GetUint(middleware.ContextUserID)
+
+	var req createStaffInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Nome e e-mail válidos são obrigatórios.")
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if name == "" || len(name) > 100 {
+		httperr.BadRequest(c, "invalid_name", "Nome inválido.")
+		return
+	}
+	if !validators.IsEmailDomainValid(email) {
+		httperr.BadRequest(c, "invalid_email_domain", "Domínio de e-mail inválido.")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// users.email é único globalmente — o convidado precisa de um e-mail ainda não cadastrado.
+	var existing int64
+	if err := h.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("email = ?", email).
+		Count(&existing).Error; err != nil {
+		httperr.Internal(c, "failed_to_create_invitation", "Erro ao criar convite.")
+		return
+	}
+	if existing > 0 {
+		httperr.Write(c, http.StatusConflict, "email_already_registered", "Este e-mail já possui uma conta.")
+		return
+	}
+
+	var shop models.Barbershop
+	if err := h.db.WithContext(ctx).First(&shop, barbershopID).Error; err != nil {
+		httperr.Internal(c, "failed_to_load_barbershop", "Erro ao carregar barbearia.")
+		return
+	}
+
+	token, err := newStaffInvitationToken()
+	if err != nil {
+		httperr.Internal(c, "token_generation_failed", "Erro ao gerar convite.")
+		return
+	}
+
+	now := time.Now()
+	inv := models.StaffInvitation{
+		BarbershopID: barbershopID,
+		InvitedBy:    &ownerID,
+		Name:         name,
+		Email:        email,
+		Token:        token,
+		ExpiresAt:    now.Add(staffInvitationTTL),
+	}
+
+	txErr := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.StaffInvitation{}).
+			Where("barbershop_id = ? AND email = ? AND accepted_at IS NULL AND revoked_at IS NULL", barbershopID, email).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(&inv).Error
+	})
+	if txErr != nil {
+		httperr.Internal(c, "failed_to_create_invitation", "Erro ao criar convite.")
+		return
+	}
+
+	// Envia e-mail (falha não desfaz o convite — o dono pode reenviar)
+	inviteLink := fmt.Sprintf("%s/convite-equipe?token=%s", h.cfg.AppURL, token)
+	if err := h.mailer.SendStaffInvitation(ctx, email, shop.Name, inviteLink); err != nil {
+		log.Printf("[STAFF] invitation email failed barbershop=%d invitation=%d: %v", barbershopID, inv.ID, err)
+	}
+
+	h.audit.Dispatch(audit.Event{
+		BarbershopID: barbershopID,
+		UserID:       &ownerID,
+		Action:       "staff_invited",
+		Entity:       "staff_invitation",
+		EntityID:     &inv.ID,
+		Metadata:     map[string]any{"email": email},
+	})
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+// ======================================================
+// GET /me/staff/invitations
+// ======================================================
+
+// ListInvitations retorna os convites ainda pendentes.
+func (h *StaffHandler) ListInvitations(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	var invitations []models.StaffInvitation
+	if err := h.db.WithContext(c.Request.Context()).
+		Where("barbershop_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+			barbershopID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		httperr.Internal(c, "failed_to_list_invitations", "Erro ao listar convites.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invitations})
+}
+
+// ======================================================
+// DELETE /me/staff/invitations/:id
+// ======================================================
+
+// RevokeInvitation invalida um convite pendente.
+func (h *StaffHandler) RevokeInvitation(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 {
+		httperr.BadRequest(c, "invalid_id", "ID inválido.")
+		return
+	}
+
+	res := h.db.WithContext(c.Request.Context()).
+		Model(&models.StaffInvitation{}).
+		Where("id = ? AND barbershop_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id64, barbershopID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		httperr.Internal(c, "failed_to_revoke_invitation", "Erro ao revogar convite.")
+		return
+	}
+	if res.RowsAffected == 0 {
+		httperr.NotFound(c, "invitation_not_found", "Convite não encontrado.")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ======================================================
+// GET /auth/staff-invitations/:token (público)
+// ======================================================
+
+// GetInvitation mostra ao convidado os dados do convite antes do aceite.
+func (h *StaffHandler) GetInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var inv models.StaffInvitation
+	if err := h.db.WithContext(ctx).
+		Where("token = ?", c.Param("token")).
+		First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httperr.NotFound(c, "invalid_token", "Convite não encontrado.")
+			return
+		}
+		httperr.Internal(c, "db_error", "")
+		return
+	}
+
+	if code, msg, ok := staffInvitationUnavailable(&inv, time.Now()); ok {
+		httperr.BadRequest(c, code, msg)
+		return
+	}
+
+	var shop models.Barbershop
+	if err := h.db.WithContext(ctx).First(&shop, inv.BarbershopID).Error; err != nil {
+		httperr.Internal(c, "failed_to_load_barbershop", "Erro ao carregar barbearia.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":       inv.Name,
+		"email":      inv.Email,
+		"expires_at": inv.ExpiresAt,
+		"barbershop": gin.H{
+			"id":   shop.ID,
+			"name": shop.Name,
+			"slug": shop.Slug,
+		},
+	})
+}
+
+// ======================================================
+// POST /auth/staff-invitations/accept (público)
+// ======================================================
+
+type acceptStaffInvitationRequest struct {
+	Token    string `json:"token"    binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+}
+
+// AcceptInvitation cria a conta do barbeiro (role "barber") com o expediente
+// padrão e devolve o JWT de sessão, como no login.
+func (h *StaffHandler) AcceptInvitation(c *gin.Context) {
+	var req acceptStaffInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "token and password (min 6 chars) are required")
+		return
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		httperr.Internal(c, "hash_failed", "")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var (
+		user     models.User
+		errCode  string
+		errMsg   string
+		notFound bool
+	)
+
+	txErr := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// FOR UPDATE: dois aceites simultâneos do mesmo token não criam duas contas.
+		var inv models.StaffInvitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token = ?", req.Token).
+			First(&inv).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				notFound = true
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		if code, msg, ok := staffInvitationUnavailable(&inv, now); ok {
+			errCode, errMsg = code, msg
+			return nil
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = inv.Name
+		}
+
+		barbershopID := inv.BarbershopID
+		user = models.User{
+			BarbershopID: &barbershopID,
+			Name:         name,
+			Email:        inv.Email,
+			PasswordHash: string(hashed),
+			Phone:        strings.TrimSpace(req.Phone),
+			Role:         models.UserRoleBarber,
+			Active:       true,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		workingHours := defaultWorkingHours(barbershopID, user.ID)
+		if err := tx.Create(&workingHours).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&inv).Updates(map[string]any{
+			"accepted_at":      now,
+			"accepted_user_id": user.ID,
+		}).Error
+	})
+
+	if txErr != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(txErr, &pgErr) && pgErr.Code == "23505" {
+			httperr.Write(c, http.StatusConflict, "email_already_registered", "Este e-mail já possui uma conta.")
+			return
+		}
+		httperr.Internal(c, "failed_to_accept_invitation", "Erro ao aceitar convite.")
+		return
+	}
+	if notFound {
+		httperr.NotFound(c, "invalid_token", "Convite não encontrado.")
+		return
+	}
+	if errCode != "" {
+		httperr.BadRequest(c, errCode, errMsg)
+		return
+	}
+
+	h.audit.Dispatch(audit.Event{
+		BarbershopID: *user.BarbershopID,
+		UserID:       &user.ID,
+		Action:       "staff_joined",
+		Entity:       "user",
+		EntityID:     &user.ID,
+	})
+
+	token, err := signUserToken(h.cfg.JWTSecret, &user)
+	if err != nil {
+		httperr.Internal(c, "failed_to_generate_token", "failed_to_generate_token")
+		return
+	}
+
+	var shop models.Barbershop
+	_ = h.db.WithContext(ctx).First(&shop, *user.BarbershopID).Error
+
+	c.JSON(http.StatusCreated, gin.H{
+		"user": gin.H{
+			"id":            user.ID,
+			"name":          user.Name,
+			"email":         user.Email,
+			"phone":         user.Phone,
+			"role":          user.Role,
+			"barbershop_id": user.BarbershopID,
+		},
+		"barbershop": gin.H{
+			"id":      shop.ID,
+			"name":    shop.Name,
+			"slug":    shop.Slug,
+			"phone":   shop.Phone,
+			"address": shop.Address,
+		},
+		"token": token,
+	})
+}
+
+// staffInvitationUnavailable explica por que um convite não pode mais ser aceito.
+func staffInvitationUnavailable(inv *models.StaffInvitation, now time.Time) (code, msg string, unavailable bool) {
+	switch {
+	case inv.AcceptedAt != nil:
+		return "invitation_already_used", "Este convite já foi aceito.", true
+	case inv.RevokedAt != nil:
+		return "invitation_revoked", "Este convite foi cancelado.", true
+	case !now.Before(inv.ExpiresAt):
+		return "invitation_expired", "Este convite expirou.", true
+	}
+	return "", "", false
+}
+
+func newStaffInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// agendaBarberID resolve de qual barbeiro é a agenda consultada: o barbeiro
+// (role "barber") só enxerga a própria; o dono vê a própria ou a de outro
+// barbeiro via ?barber_id=. Retorna false quando o parâmetro é inválido.
+func agendaBarberID(c *gin.Context) (uint, bool) {
+	userID := c.GetUint(middleware.ContextUserID)
+	if !middleware.IsOwner(c) {
+		return userID, true
+	}
+
+	raw := strings.TrimSpace(c.Query("barber_id"))
+	if raw == "" {
+		return userID, true
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return uint(v), true
+}
@@ -24,6 +24,7 @@ type SubscriptionHandler struct {
 	activateUC *subscription.ActivateSubscription
 	cancelUC   *subscription.CancelSubscription
 	getUC      *subscription.GetActiveSubscription
+	autoRenew  *subscription.SetAutoRenew
 	listQ      subscriptionLister
 	audit      *audit.Dispatcher
 }
@@ -32,6 +33,7 @@ func NewSubscriptionHandler(
 	activateUC *subscription.ActivateSubscription,
 	cancelUC *subscription.CancelSubscription,
 	getUC *subscription.GetActiveSubscription,
+	autoRenewUC *subscription.SetAutoRenew,
 	listQ subscriptionLister,
 	auditDispatcher *audit.Dispatcher,
 ) *SubscriptionHandler {
@@ -39,6 +41,7 @@ func NewSubscriptionHandler(
 		activateUC: activateUC,
 		cancelUC:   cancelUC,
 		getUC:      getUC,
+		autoRenew:  autoRenewUC,
 		listQ:      listQ,
 		audit:      auditDispatcher,
 	}
@@ -143,6 +146,62 @@ func (h *SubscriptionHandler) Cancel(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+type setAutoRenewRequest struct {
+	AutoRenew *bool `json:"auto_renew" binding:"required"`
+}
+
+// SetAutoRenew processa PUT /me/subscriptions/:clientID/auto-renew.
+func (h *SubscriptionHandler) SetAutoRenew(c *gin.Context) {
+	barbershopID := c.GetUint(middleware.ContextBarbershopID)
+	if barbershopID == 0 {
+		httperr.Unauthorized(c, "invalid_barbershop", "invalid_barbershop")
+		return
+	}
+
+	clientID64, err := strconv.ParseUint(c.Param("clientID"), 10, 64)
+	if err != nil {
+		httperr.BadRequest(c, "invalid_client_id", "invalid_client_id")
+		return
+	}
+
+	var req setAutoRenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "invalid_request")
+		return
+	}
+
+	clientID := uint(clientID64)
+	err = h.autoRenew.Execute(c.Request.Context(), barbershopID, clientID, *req.AutoRenew)
+	if err != nil {
+		switch {
+		case errors.Is(err, subscription.ErrInvalidInput):
+			httperr.BadRequest(c, "invalid_input", "invalid_input")
+
+		case errors.Is(err, subscription.ErrActiveSubscriptionNotFound):
+			httperr.NotFound(c, "active_subscription_not_found", "active_subscription_not_found")
+
+		case errors.Is(err, subscription.ErrCardNotStored):
+			httperr.BadRequest(c, "card_not_stored", "card_not_stored")
+
+		default:
+			httperr.Internal(c, "failed_to_update_subscription", "failed_to_update_subscription")
+		}
+		return
+	}
+
+	h.audit.Dispatch(audit.Event{
+		BarbershopID: barbershopID,
+		Action:       "subscription_auto_renew_changed",
+		Entity:       "client",
+		EntityID:     &clientID,
+		Metadata: map[string]any{
+			"auto_renew": *req.AutoRenew,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"auto_renew": *req.AutoRenew})
+}
+
 func (h *SubscriptionHandler) GetActive(c *gin.Context) {
 	barbershopID := c.GetUint(middleware.ContextBarbershopID)
 	if barbershopID == 0 {
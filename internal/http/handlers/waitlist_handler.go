@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/http/httperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/usecase/waitlist"
+)
+
+// WaitlistHandler expõe a lista de espera pública: o cliente entra na lista
+// pelo slug da barbearia e acompanha, aceita ou desiste pelo token recebido.
+type WaitlistHandler struct {
+	db      *gorm.DB
+	joinUC  *ucWaitlist.JoinWaitlist
+	getUC   *ucWaitlist.GetWaitlistEntry
+	claimUC *ucWaitlist.ClaimWaitlistOffer
+	leaveUC *ucWaitlist.LeaveWaitlist
+}
+
+func NewWaitlistHandler(
+	db *gorm.DB,
+	joinUC *ucWaitlist.JoinWaitlist,
+	getUC *ucWaitlist.GetWaitlistEntry,
+	claimUC *ucWaitlist.ClaimWaitlistOffer,
+	leaveUC *ucWaitlist.LeaveWaitlist,
+) *WaitlistHandler {
+	return &WaitlistHandler{
+		db:      db,
+		joinUC:  joinUC,
+		getUC:   getUC,
+		claimUC: claimUC,
+		leaveUC: leaveUC,
+	}
+}
+
+type joinWaitlistRequest struct {
+	ServiceID   uint                `json:"service_id" binding:"required"`
+	BarberID    *uint               `json:"barber_id"` // omitido = qualquer barbeiro
+	ClientName  string              `json:"client_name" binding:"required"`
+	ClientPhone string              `json:"client_phone"`
+	ClientEmail string              `json:"client_email"`
+	DateFrom    string              `json:"date_from" binding:"required"` // YYYY-MM-DD
+	DateTo      string              `json:"date_to" binding:"required"`   // YYYY-MM-DD
+	TimeWindows []models.TimeWindow `json:"time_windows"`                 // vazio = qualquer horário
+}
+
+type waitlistOfferResponse struct {
+	StartTime time.Time `json:"start_time"`
+	BarberID  uint      `json:"barber_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type waitlistEntryResponse struct {
+	ID            uint                   `json:"id"`
+	Token         string                 `json:"token,omitempty"`
+	Status        string                 `json:"status"`
+	ServiceID     uint                   `json:"service_id"`
+	BarberID      *uint                  `json:"barber_id,omitempty"`
+	DateFrom      string                 `json:"date_from"`
+	DateTo        string                 `json:"date_to"`
+	TimeWindows   models.TimeWindows     `json:"time_windows"`
+	Offer         *waitlistOfferResponse `json:"offer,omitempty"`
+	AppointmentID *uint                  `json:"appointment_id,omitempty"`
+}
+
+// toWaitlistEntryResponse só inclui a oferta enquanto ela pode ser aceita;
+// oferta vencida aparece como "waiting".
+func toWaitlistEntryResponse(e *models.WaitlistEntry, now time.Time) waitlistEntryResponse {
+	resp := waitlistEntryResponse{
+		ID:            e.ID,
+		Status:        e.Status,
+		ServiceID:     e.ServiceID,
+		BarberID:      e.BarberID,
+		DateFrom:      e.DateFrom.Format("2006-01-02"),
+		DateTo:        e.DateTo.Format("2006-01-02"),
+		TimeWindows:   e.TimeWindows,
+		AppointmentID: e.AppointmentID,
+	}
+	if e.OfferActive(now) {
+		resp.Offer = &waitlistOfferResponse{
+			StartTime: *e.OfferedStartTime,
+			BarberID:  *e.OfferedBarberID,
+			ExpiresAt: *e.OfferExpiresAt,
+		}
+	} else if e.Status == models.WaitlistStatusOffered {
+		resp.Status = models.WaitlistStatusWaiting
+	}
+	return resp
+}
+
+// ======================================================
+// POST /public/:slug/waitlist
+// ======================================================
+
+func (h *WaitlistHandler) Join(c *gin.Context) {
+	var shop models.Barbershop
+	if err := h.db.WithContext(c.Request.Context()).
+		Where("slug = ?", c.Param("slug")).
+		First(&shop).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			httperr.NotFound(c, "barbershop_not_found", "Barbearia não encontrada.")
+			return
+		}
+		httperr.Internal(c, "failed_to_load_barbershop", "Erro ao carregar barbearia.")
+		return
+	}
+
+	var req joinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httperr.BadRequest(c, "invalid_request", "Dados inválidos.")
+		return
+	}
+
+	var barberID uint
+	if req.BarberID != nil {
+		barberID = *req.BarberID
+	}
+
+	entry, err := h.joinUC.Execute(c.Request.Context(), ucWaitlist.JoinWaitlistInput{
+		BarbershopID: shop.ID,
+		ServiceID:    req.ServiceID,
+		BarberID:     barberID,
+		ClientName:   req.ClientName,
+		ClientPhone:  req.ClientPhone,
+		ClientEmail:  req.ClientEmail,
+		DateFrom:     req.DateFrom,
+		DateTo:       req.DateTo,
+		TimeWindows:  req.TimeWindows,
+	})
+	if err != nil {
+		switch {
+		case apperr.IsBusiness(err, "invalid_client_name"):
+			httperr.BadRequest(c, "invalid_client_name", "Informe seu nome.")
+		case apperr.IsBusiness(err, "contact_required"):
+			httperr.BadRequest(c, "contact_required", "Informe um telefone ou e-mail para receber a oferta.")
+		case apperr.IsBusiness(err, "invalid_date_range"):
+			httperr.BadRequest(c, "invalid_date_range", "Período inválido (máximo de 60 dias, a partir de hoje).")
+		case apperr.IsBusiness(err, "invalid_time_window"):
+			httperr.BadRequest(c, "invalid_time_window", "Faixa de horário inválida.")
+		case apperr.IsBusiness(err, "service_not_found"):
+			httperr.BadRequest(c, "service_not_found", "Serviço não encontrado.")
+		case apperr.IsBusiness(err, "barber_not_found"):
+			httperr.BadRequest(c, "barber_not_found", "Barbeiro não encontrado.")
+		case apperr.IsBusiness(err, "service_not_offered"):
+			httperr.BadRequest(c, "service_not_offered", "Este barbeiro não faz este serviço.")
+		case apperr.IsBusiness(err, "waitlist_already_joined"):
+			httperr.Write(c, http.StatusConflict, "waitlist_already_joined", "Você já está na lista de espera deste serviço.")
+		default:
+			httperr.Internal(c, "failed_to_join_waitlist", "Erro ao entrar na lista de espera.")
+		}
+		return
+	}
+
+	resp := toWaitlistEntryResponse(entry, time.Now().UTC())
+	resp.Token = entry.Token
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ======================================================
+// GET /public/waitlist/:token
+// ======================================================
+
+func (h *WaitlistHandler) View(c *gin.Context) {
+	entry, err := h.getUC.Execute(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if !writeWaitlistError(c, err) {
+			httperr.Internal(c, "failed_to_load_waitlist_entry", "Erro ao carregar a lista de espera.")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, toWaitlistEntryResponse(entry, time.Now().UTC()))
+}
+
+// ======================================================
+// POST /public/waitlist/:token/claim
+// ======================================================
+
+// Claim aceita a oferta e cria o agendamento pelo fluxo normal de booking.
+// A resposta é o agendamento, como em POST /public/:slug/appointments.
+func (h *WaitlistHandler) Claim(c *gin.Context) {
+	ap, err := h.claimUC.Execute(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if !writeWaitlistError(c, err) {
+			mapPublicCreateErrors(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, ap)
+}
+
+// ======================================================
+// DELETE /public/waitlist/:token
+// ======================================================
+
+func (h *WaitlistHandler) Leave(c *gin.Context) {
+	if err := h.leaveUC.Execute(c.Request.Context(), c.Param("token")); err != nil {
+		if !writeWaitlistError(c, err) {
+			httperr.Internal(c, "failed_to_leave_waitlist", "Erro ao sair da lista de espera.")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": models.WaitlistStatusCancelled})
+}
+
+// writeWaitlistError responde os erros de negócio da lista de espera e
+// retorna false para os demais.
+func writeWaitlistError(c *gin.Context, err error) bool {
+	switch {
+	case apperr.IsBusiness(err, "waitlist_entry_not_found"):
+		httperr.NotFound(c, "waitlist_entry_not_found", "Inscrição na lista de espera não encontrada.")
+	case apperr.IsBusiness(err, "waitlist_offer_not_found"):
+		httperr.Write(c, http.StatusConflict, "waitlist_offer_not_found", "Não há horário oferecido no momento.")
+	case apperr.IsBusiness(err, "waitlist_offer_expired"):
+		httperr.Write(c, http.StatusGone, "waitlist_offer_expired", "A oferta expirou. Você continua na lista de espera.")
+	case apperr.IsBusiness(err, "waitlist_slot_taken"):
+		httperr.Write(c, http.StatusConflict, "waitlist_slot_taken", "Este horário já foi preenchido. Você continua na lista de espera.")
+	case apperr.IsBusiness(err, "waitlist_offer_already_claimed"):
+		httperr.Write(c, http.StatusConflict, "waitlist_offer_already_claimed", "Este horário já foi agendado por você.")
+	default:
+		return false
+	}
+	return true
+}
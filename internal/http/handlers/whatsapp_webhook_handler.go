@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"strings"
@@ -11,9 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
-	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
-	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+	ucWhatsAppBot "github.com/BruksfildServices01/barber-scheduler/internal/usecase/whatsappbot"
 )
 
 // maskPhone mascara um número de telefone para logs, mantendo apenas os
@@ -34,16 +32,22 @@ func maskPhone(raw string) string {
 	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
 }
 
-// WhatsAppWebhookHandler recebe mensagens da Evolution API e responde
-// automaticamente com o comprovante de agendamento do cliente.
+// WhatsAppWebhookHandler recebe mensagens da Evolution API e as repassa ao
+// bot de atendimento, que conduz a conversa (agendar, cancelar, remarcar).
 type WhatsAppWebhookHandler struct {
 	db           *gorm.DB
-	evolutionURL string
 	evolutionKey string
+	bot          *ucWhatsAppBot.Bot
 }
 
-func NewWhatsAppWebhookHandler(db *gorm.DB, evolutionURL, evolutionKey string) *WhatsAppWebhookHandler {
-	return &WhatsAppWebhookHandler{db: db, evolutionURL: evolutionURL, evolutionKey: evolutionKey}
+func NewWhatsAppWebhookHandler(db *gorm.DB, evolutionKey string) *WhatsAppWebhookHandler {
+	return &WhatsAppWebhookHandler{db: db, evolutionKey: evolutionKey}
+}
+
+// WithBot liga o bot de atendimento. Sem ele, as mensagens são ignoradas.
+func (h *WhatsAppWebhookHandler) WithBot(bot *ucWhatsAppBot.Bot) *WhatsAppWebhookHandler {
+	h.bot = bot
+	return h
 }
 
 type evolutionWebhookPayload struct {
@@ -111,141 +115,37 @@ func (h *WhatsAppWebhookHandler) Receive(c *gin.Context) {
 		return
 	}
 
-	go h.processMessage(inst, clientPhone)
-	c.Status(http.StatusOK)
-}
-
-// ── Processamento assíncrono ───────────────────────────────────────────────────
-
-type appointmentForReply struct {
-	ClientName       string    `gorm:"column:client_name"`
-	ClientPhone      string    `gorm:"column:client_phone"`
-	BarbershopName   string    `gorm:"column:barbershop_name"`
-	BarbershopPhone  string    `gorm:"column:barbershop_phone"`
-	ServiceName      string    `gorm:"column:service_name"`
-	ServiceImageURL  string    `gorm:"column:service_image_url"`
-	StartTime        time.Time `gorm:"column:start_time"`
-	EndTime          time.Time `gorm:"column:end_time"`
-	Timezone         string    `gorm:"column:timezone"`
-	Token            string    `gorm:"column:token"`
-}
-
-func (h *WhatsAppWebhookHandler) processMessage(inst models.BarbershopWhatsAppInstance, clientPhone string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	// Últimos 8 dígitos do telefone para busca tolerante a formatação
-	suffix := clientPhone
-	if len(suffix) > 8 {
-		suffix = suffix[len(suffix)-8:]
+	text := payload.Data.Message.Conversation
+	if text == "" {
+		text = payload.Data.Message.ExtendedTextMessage.Text
 	}
-
-	var data appointmentForReply
-	err := h.db.WithContext(ctx).Raw(`
-		SELECT
-			c.name  AS client_name,
-			c.phone AS client_phone,
-			b.name  AS barbershop_name,
-			b.phone AS barbershop_phone,
-			bs.name AS service_name,
-			COALESCE(
-				(SELECT url FROM barbershop_service_images
-				 WHERE service_id = bs.id
-				 ORDER BY position ASC LIMIT 1),
-				''
-			)       AS service_image_url,
-			a.start_time,
-			a.end_time,
-			b.timezone,
-			COALESCE(t.token, '') AS token
-		FROM clients c
-		JOIN appointments a
-			ON  a.client_id    = c.id
-			AND a.barbershop_id = ?
-			AND a.status IN ('scheduled', 'awaiting_payment')
-			AND a.start_time >= NOW()
-		JOIN barbershops b
-			ON b.id = a.barbershop_id
-		JOIN barbershop_services bs
-			ON bs.id = a.barber_product_id
-		LEFT JOIN appointment_tickets t
-			ON t.appointment_id = a.id AND t.expires_at > NOW()
-		WHERE c.barbershop_id = ?
-		  AND REGEXP_REPLACE(c.phone, '[^0-9]', '', 'g') LIKE ?
-		ORDER BY a.start_time ASC
-		LIMIT 1
-	`, inst.BarbershopID, inst.BarbershopID, "%"+suffix).Scan(&data).Error
-
-	if err != nil || data.ClientName == "" {
-		log.Printf("[WhatsApp webhook] no active appointment for phone %s (barbershop %d)", maskPhone(clientPhone), inst.BarbershopID)
+	if h.bot == nil || strings.TrimSpace(text) == "" {
+		c.Status(http.StatusOK)
 		return
 	}
 
-	client := notification.NewEvolutionClient(h.evolutionURL, h.evolutionKey)
-	msg := h.buildReply(data)
-
-	var sendErr error
-	if data.ServiceImageURL != "" {
-		// Envia foto do serviço com a mensagem como legenda
-		sendErr = client.SendMedia(ctx, inst.InstanceName, clientPhone, data.ServiceImageURL, msg)
-	}
-	// Fallback para texto puro se não tiver imagem ou se o envio de mídia falhar
-	if data.ServiceImageURL == "" || sendErr != nil {
-		if sendErr != nil {
-			log.Printf("[WhatsApp webhook] media send failed, falling back to text: %v", sendErr)
-		}
-		sendErr = client.SendText(ctx, inst.InstanceName, clientPhone, msg)
-	}
-	if sendErr != nil {
-		log.Printf("[WhatsApp webhook] reply failed for %s: %v", maskPhone(clientPhone), sendErr)
-	}
+	// Mensagens do mesmo número que chegam juntas são serializadas pelo bot
+	// (lock da conversa), na ordem em que obtêm o lock.
+	go h.processMessage(inst, clientPhone, text)
+	c.Status(http.StatusOK)
 }
 
-func (h *WhatsAppWebhookHandler) buildReply(d appointmentForReply) string {
-	loc := timezone.Location(d.Timezone)
-	start := d.StartTime.In(loc)
-	end := d.EndTime.In(loc)
-
-	weekdays := [...]string{"Domingo", "Segunda-feira", "Terça-feira", "Quarta-feira", "Quinta-feira", "Sexta-feira", "Sábado"}
-	months   := [...]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
-
-	dateStr := fmt.Sprintf("%s, %d de %s", weekdays[start.Weekday()], start.Day(), months[start.Month()-1])
-	timeStr := fmt.Sprintf("%02d:%02d – %02d:%02d", start.Hour(), start.Minute(), end.Hour(), end.Minute())
-
-	lines := []string{
-		fmt.Sprintf("✅ *Agendamento confirmado, %s!*", d.ClientName),
-		"",
-		fmt.Sprintf("✂️  *%s*", d.ServiceName),
-		fmt.Sprintf("📅  %s", dateStr),
-		fmt.Sprintf("🕐  %s", timeStr),
-		"",
-		"━━━━━━━━━━━━━━━━━",
-	}
-
-	if d.Token != "" {
-		lines = append(lines,
-			"",
-			"🎫 *Seu ticket:*",
-			fmt.Sprintf("https://corteon.app/ticket/%s", d.Token),
-			"_(cancelar ou remarcar pelo link acima)_",
-		)
-	}
+// ── Processamento assíncrono ───────────────────────────────────────────────────
 
-	lines = append(lines,
-		"",
-		"━━━━━━━━━━━━━━━━━",
-		"",
-		"📌 *Lembre-se:*",
-		"• Chegue 5 minutos antes",
-		"• Faltas sem aviso afetam sua prioridade",
-	)
+func (h *WhatsAppWebhookHandler) processMessage(inst models.BarbershopWhatsAppInstance, clientPhone, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	if d.BarbershopPhone != "" {
-		lines = append(lines, "", fmt.Sprintf("📞  *Dúvidas:* %s", d.BarbershopPhone))
+	err := h.bot.Handle(ctx, ucWhatsAppBot.Message{
+		BarbershopID: inst.BarbershopID,
+		BarberID:     inst.BarberID,
+		InstanceName: inst.InstanceName,
+		Phone:        clientPhone,
+		Text:         text,
+	})
+	if err != nil {
+		log.Printf("[WhatsApp webhook] bot failed for %s (barbershop %d): %v", maskPhone(clientPhone), inst.BarbershopID, err)
 	}
-
-	lines = append(lines, "", fmt.Sprintf("_Mensagem automática · %s_", d.BarbershopName))
-	return strings.Join(lines, "\n")
 }
 
 func extractPhone(remoteJid string) string {
@@ -34,6 +34,69 @@ var (
 	barbershopSFGroup singleflight.Group
 )
 
+// userActiveCacheTTL limita por quanto tempo um barbeiro desativado ainda
+// consegue usar um token emitido antes da desativação (além do evict explícito).
+const userActiveCacheTTL = 30 * time.Second
+
+type userActiveCacheEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+var (
+	userActiveCacheMu sync.RWMutex
+	userActiveCache   = make(map[uint]*userActiveCacheEntry)
+	userActiveSFGroup singleflight.Group
+)
+
+// EvictUserCache invalida o status ativo de um usuário.
+// Deve ser chamado sempre que users.active for alterado.
+func EvictUserCache(userID uint) {
+	userActiveCacheMu.Lock()
+	delete(userActiveCache, userID)
+	userActiveCacheMu.Unlock()
+}
+
+// isUserActive consulta users.active com cache em memória e singleflight,
+// no mesmo esquema do status da barbearia.
+func isUserActive(c *gin.Context, db *gorm.DB, userID uint) (bool, error) {
+	userActiveCacheMu.RLock()
+	cached, hit := userActiveCache[userID]
+	valid := hit && time.Now().Before(cached.expiresAt)
+	userActiveCacheMu.RUnlock()
+
+	if valid {
+		return cached.active, nil
+	}
+
+	v, err, _ := userActiveSFGroup.Do(fmt.Sprintf("user:%d", userID), func() (any, error) {
+		var user struct {
+			ID     uint
+			Active bool
+		}
+		if err := db.WithContext(c.Request.Context()).
+			Table("users").
+			Select("id, active").
+			Where("id = ?", userID).
+			First(&user).Error; err != nil {
+			return false, err
+		}
+
+		userActiveCacheMu.Lock()
+		userActiveCache[userID] = &userActiveCacheEntry{
+			active:    user.Active,
+			expiresAt: time.Now().Add(userActiveCacheTTL),
+		}
+		userActiveCacheMu.Unlock()
+
+		return user.Active, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
 const (
 	ContextUserID       = "userID"
 	ContextBarbershopID = "barbershopID"
@@ -161,6 +224,21 @@ func AuthMiddleware(cfg *config.Config, db *gorm.DB) gin.HandlerFunc {
 			shopSubscriptionExpiresAt = res.subscriptionExpiresAt
 		}
 
+		// Barbeiro desativado pelo dono perde o acesso mesmo com token válido.
+		active, err := isUserActive(c, db, uint(userID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session_expired"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service_unavailable"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "account_disabled"})
+			return
+		}
+
 		// Cobrança de plataforma desativada — acesso livre para todos os usuários.
 		_ = shopStatus
 		_ = shopTrialEndsAt
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	ContextClientID        = "clientID"
+	ContextClientSessionID = "clientSessionID"
+)
+
+// ClientSessionAuthenticator resolve o token do portal do cliente na sessão
+// ativa da barbearia do slug (nil quando o token não vale).
+type ClientSessionAuthenticator interface {
+	Authenticate(ctx context.Context, slug, token string) (*models.ClientSession, error)
+}
+
+// ClientSessionMiddleware autentica as rotas /public/:slug/me com o token de
+// sessão do portal. É separado do AuthMiddleware: o JWT da equipe não abre o
+// portal e o token do cliente não abre as rotas da equipe.
+func ClientSessionMiddleware(auth ClientSessionAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing_authorization_header"})
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_authorization_header"})
+			return
+		}
+
+		session, err := auth.Authenticate(c.Request.Context(), c.Param("slug"), strings.TrimSpace(parts[1]))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service_unavailable"})
+			return
+		}
+		if session == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+
+		c.Set(ContextClientSessionID, session.ID)
+		c.Set(ContextClientID, session.ClientID)
+		c.Set(ContextBarbershopID, session.BarbershopID)
+		c.Next()
+	}
+}
@@ -18,3 +18,10 @@ func RequireOwner(c *gin.Context) {
 	}
 	c.Next()
 }
+
+// IsOwner indica se o usuário autenticado é o dono da barbearia.
+// Handlers usam para restringir barbeiros (role "barber") à própria agenda.
+func IsOwner(c *gin.Context) bool {
+	role, _ := c.Get(ContextUserRole)
+	return role == "owner"
+}
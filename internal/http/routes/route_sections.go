@@ -26,6 +26,7 @@ func registerPublicRoutes(
 	g.GET("/:slug/services", pub.ListServices)
 	g.GET("/:slug/products", pub.ListProducts)
 	g.GET("/:slug/services/:id/suggestion", pub.GetServiceSuggestion)
+	g.GET("/:slug/barbers", pub.ListBarbers)
 	g.GET("/:slug/availability", pub.AvailabilityForClient)
 	g.POST("/:slug/appointments", pub.CreateAppointment)
 
@@ -66,6 +67,223 @@ func registerPublicRoutes(
 	g.PATCH("/ticket/:token", ticket.Reschedule)
 }
 
+// registerWaitlistRoutes registra a lista de espera pública: entrada pelo slug
+// e acompanhamento, aceite da oferta e desistência pelo token da entrada.
+func registerWaitlistRoutes(
+	api *gin.RouterGroup,
+	cfg *config.Config,
+	waitlist *handlers.WaitlistHandler,
+) {
+	g := api.Group("/public")
+
+	g.POST(
+		"/:slug/waitlist",
+		middleware.NewRateLimitByKey(func(c *gin.Context) string {
+			return middleware.ClientIPKey(c) + ":" + c.Param("slug")
+		}, 10, 60, cfg.RedisURL), // 10 req/minuto
+		waitlist.Join,
+	)
+
+	ipKey := func(c *gin.Context) string { return middleware.ClientIPKey(c) }
+
+	g.GET("/waitlist/:token", waitlist.View)
+	g.POST("/waitlist/:token/claim",
+		middleware.NewRateLimitByKey(ipKey, 20, 60, cfg.RedisURL), // 20 req/minuto
+		waitlist.Claim,
+	)
+	g.DELETE("/waitlist/:token", waitlist.Leave)
+}
+
+// registerCalendarFeedRoutes registra o feed .ics assinável: link e rotação
+// do token no painel, e a URL pública que o calendário do cliente consulta.
+func registerCalendarFeedRoutes(
+	api *gin.RouterGroup,
+	g *gin.RouterGroup,
+	cfg *config.Config,
+	feeds *handlers.CalendarFeedHandler,
+) {
+	g.GET("/me/calendar-feed", feeds.Get)
+	g.POST("/me/calendar-feed/rotate", feeds.Rotate)
+	g.GET("/me/calendar-feed/shop", middleware.RequireOwner, feeds.GetShop)
+	g.POST("/me/calendar-feed/shop/rotate", middleware.RequireOwner, feeds.RotateShop)
+
+	api.GET("/public/calendar/:token",
+		middleware.NewRateLimitByKey(func(c *gin.Context) string {
+			return middleware.ClientIPKey(c)
+		}, 60, 60, cfg.RedisURL), // 60 req/minuto
+		feeds.Serve,
+	)
+}
+
+// registerInventoryRoutes registra o razão de estoque: entradas de compra,
+// perdas, devoluções, contagem física e o histórico por produto.
+func registerInventoryRoutes(
+	g *gin.RouterGroup,
+	inventory *handlers.InventoryHandler,
+) {
+	g.POST("/me/stock/entries", middleware.RequireOwner, inventory.RecordEntries)
+	g.POST("/me/stock/counts", middleware.RequireOwner, inventory.RecordCount)
+	g.GET("/me/products/:id/stock-movements", inventory.ListMovements)
+}
+
+// registerOrderReturnRoutes registra o cancelamento de pedidos e a devolução
+// de itens, com o histórico de devoluções e o reembolso manual.
+func registerOrderReturnRoutes(
+	g *gin.RouterGroup,
+	returns *handlers.OrderReturnHandler,
+) {
+	g.POST("/me/orders/:id/cancel", middleware.RequireOwner, returns.Cancel)
+	g.POST("/me/orders/:id/returns", middleware.RequireOwner, returns.Return)
+	g.GET("/me/orders/:id/returns", middleware.RequireOwner, returns.List)
+	g.POST("/me/orders/:id/returns/:returnId/refunded", middleware.RequireOwner, returns.MarkRefunded)
+}
+
+// registerCouponRoutes registra o cadastro de cupons do owner. O código é
+// aplicado nos checkouts públicos, no booking e na compra de planos.
+func registerCouponRoutes(
+	g *gin.RouterGroup,
+	coupons *handlers.CouponHandler,
+) {
+	g.GET("/me/coupons", middleware.RequireOwner, coupons.List)
+	g.POST("/me/coupons", middleware.RequireOwner, coupons.Create)
+	g.PUT("/me/coupons/:id", middleware.RequireOwner, coupons.Update)
+	g.GET("/me/coupons/:id/redemptions", middleware.RequireOwner, coupons.ListRedemptions)
+}
+
+// registerLoyaltyRoutes registra o programa de fidelidade: configuração e
+// recompensas do owner, catálogo e extrato de pontos para a equipe usar no
+// fechamento.
+func registerLoyaltyRoutes(
+	g *gin.RouterGroup,
+	loyalty *handlers.LoyaltyHandler,
+) {
+	g.GET("/me/loyalty/program", loyalty.GetProgram)
+	g.PUT("/me/loyalty/program", middleware.RequireOwner, loyalty.UpdateProgram)
+	g.GET("/me/loyalty/rewards", loyalty.ListRewards)
+	g.POST("/me/loyalty/rewards", middleware.RequireOwner, loyalty.CreateReward)
+	g.PUT("/me/loyalty/rewards/:id", middleware.RequireOwner, loyalty.UpdateReward)
+	g.GET("/me/clients/:id/loyalty", loyalty.ClientLoyalty)
+}
+
+// registerNotificationTemplateRoutes registra o editor dos textos das
+// mensagens ao cliente (owner only).
+func registerNotificationTemplateRoutes(
+	g *gin.RouterGroup,
+	templates *handlers.NotificationTemplateHandler,
+) {
+	g.GET("/me/notification-templates", middleware.RequireOwner, templates.List)
+	g.POST("/me/notification-templates/preview", middleware.RequireOwner, templates.Preview)
+	g.PUT("/me/notification-templates/:kind/:channel", middleware.RequireOwner, templates.Save)
+	g.DELETE("/me/notification-templates/:kind/:channel", middleware.RequireOwner, templates.Reset)
+}
+
+// registerClassificationRulesRoutes registra as regras de classificação de
+// clientes e a simulação antes de salvar (owner only).
+func registerClassificationRulesRoutes(
+	g *gin.RouterGroup,
+	rules *handlers.ClassificationRulesHandler,
+) {
+	g.GET("/me/classification-rules", middleware.RequireOwner, rules.Get)
+	g.PUT("/me/classification-rules", middleware.RequireOwner, rules.Update)
+	g.DELETE("/me/classification-rules", middleware.RequireOwner, rules.Reset)
+	g.POST("/me/classification-rules/simulate", middleware.RequireOwner, rules.Simulate)
+}
+
+// registerCampaignRoutes registra segmentos e campanhas (owner only), o
+// descadastro manual do cliente e a página pública de descadastro.
+func registerCampaignRoutes(
+	api *gin.RouterGroup,
+	g *gin.RouterGroup,
+	cfg *config.Config,
+	segments *handlers.SegmentHandler,
+	campaigns *handlers.CampaignHandler,
+) {
+	g.GET("/me/segments", middleware.RequireOwner, segments.List)
+	g.POST("/me/segments", middleware.RequireOwner, segments.Create)
+	g.POST("/me/segments/preview", middleware.RequireOwner, segments.Preview)
+	g.PUT("/me/segments/:id", middleware.RequireOwner, segments.Update)
+	g.DELETE("/me/segments/:id", middleware.RequireOwner, segments.Delete)
+	g.GET("/me/segments/:id/preview", middleware.RequireOwner, segments.PreviewSaved)
+
+	g.GET("/me/campaigns", middleware.RequireOwner, campaigns.List)
+	g.POST("/me/campaigns", middleware.RequireOwner, campaigns.Create)
+	g.POST("/me/campaigns/preview", middleware.RequireOwner, campaigns.Preview)
+	g.GET("/me/campaigns/:id", middleware.RequireOwner, campaigns.Get)
+	g.PUT("/me/campaigns/:id", middleware.RequireOwner, campaigns.Update)
+	g.DELETE("/me/campaigns/:id", middleware.RequireOwner, campaigns.Delete)
+	g.POST("/me/campaigns/:id/schedule", middleware.RequireOwner, campaigns.Schedule)
+	g.POST("/me/campaigns/:id/cancel", middleware.RequireOwner, campaigns.Cancel)
+	g.GET("/me/campaigns/:id/recipients", middleware.RequireOwner, campaigns.Recipients)
+
+	g.PUT("/me/clients/:id/marketing-opt-out", middleware.RequireOwner, campaigns.SetOptOut)
+
+	ipKey := func(c *gin.Context) string { return middleware.ClientIPKey(c) }
+	pub := api.Group("/public")
+	pub.GET("/unsubscribe/:token",
+		middleware.NewRateLimitByKey(ipKey, 30, 60, cfg.RedisURL), // 30 req/minuto
+		campaigns.UnsubscribeInfo,
+	)
+	pub.POST("/unsubscribe/:token",
+		middleware.NewRateLimitByKey(ipKey, 10, 60, cfg.RedisURL), // 10 req/minuto
+		campaigns.Unsubscribe,
+	)
+}
+
+// registerReportRoutes registra os relatórios enviados por email: assinaturas,
+// envio manual e histórico. Só o dono.
+func registerReportRoutes(
+	g *gin.RouterGroup,
+	reports *handlers.ReportSubscriptionHandler,
+) {
+	g.GET("/me/report-subscriptions", middleware.RequireOwner, reports.List)
+	g.POST("/me/report-subscriptions", middleware.RequireOwner, reports.Create)
+	g.PUT("/me/report-subscriptions/:id", middleware.RequireOwner, reports.Update)
+	g.DELETE("/me/report-subscriptions/:id", middleware.RequireOwner, reports.Delete)
+	g.POST("/me/report-subscriptions/:id/send", middleware.RequireOwner, reports.SendNow)
+	g.GET("/me/report-deliveries", middleware.RequireOwner, reports.Deliveries)
+}
+
+// registerDeliveryLogRoutes registra o log de entregas do outbox
+// (notificações, agenda e auditoria) e o reenvio das que falharam de vez.
+func registerDeliveryLogRoutes(
+	g *gin.RouterGroup,
+	deliveries *handlers.DeliveryLogHandler,
+) {
+	g.GET("/me/deliveries", middleware.RequireOwner, deliveries.List)
+	g.POST("/me/deliveries/:id/retry", middleware.RequireOwner, deliveries.Retry)
+}
+
+// registerPackageRoutes registra pacotes pré-pagos e combos: catálogo do
+// owner, vitrine pública e compra do pacote pelo cliente.
+func registerPackageRoutes(
+	api *gin.RouterGroup,
+	g *gin.RouterGroup,
+	cfg *config.Config,
+	packages *handlers.PackageHandler,
+	pubPackages *handlers.PublicPackageHandler,
+) {
+	g.POST("/me/packages", middleware.RequireOwner, packages.CreatePackage)
+	g.GET("/me/packages", packages.ListPackages)
+	g.PUT("/me/packages/:id", middleware.RequireOwner, packages.UpdatePackage)
+	g.GET("/me/clients/:id/packages", packages.ListClientPackages)
+
+	g.POST("/me/combos", middleware.RequireOwner, packages.CreateCombo)
+	g.GET("/me/combos", packages.ListCombos)
+	g.PUT("/me/combos/:id", middleware.RequireOwner, packages.UpdateCombo)
+
+	pub := api.Group("/public")
+	pub.GET("/:slug/packages", pubPackages.ListPackages)
+	pub.GET("/:slug/combos", pubPackages.ListCombos)
+	pub.POST(
+		"/:slug/packages/purchase",
+		middleware.NewRateLimitByKey(func(c *gin.Context) string {
+			return middleware.ClientIPKey(c) + ":" + c.Param("slug")
+		}, 10, 60, cfg.RedisURL), // 10 req/minuto
+		pubPackages.Purchase,
+	)
+	pub.GET("/:slug/packages/purchases/:id/payment/status", pubPackages.PaymentStatus)
+}
+
 // registerWebhookAndAuthRoutes registra webhooks públicos e rotas de autenticação.
 func registerWebhookAndAuthRoutes(
 	r *gin.Engine,
@@ -104,6 +322,37 @@ func registerWebhookAndAuthRoutes(
 	api.POST("/billing/webhook", middleware.MaxBodySize(64*1024), billing.Webhook)
 }
 
+// registerStaffRoutes registra a gestão da equipe (convites, ativação e
+// catálogo por barbeiro) e as rotas públicas de aceite de convite.
+func registerStaffRoutes(
+	api *gin.RouterGroup,
+	g *gin.RouterGroup,
+	cfg *config.Config,
+	staff *handlers.StaffHandler,
+	barberService *handlers.BarberServiceHandler,
+) {
+	ipKey := func(c *gin.Context) string { return middleware.ClientIPKey(c) }
+
+	api.GET("/auth/staff-invitations/:token",
+		middleware.NewRateLimitByKey(ipKey, 30, 300, cfg.RedisURL), // 30/5min
+		staff.GetInvitation,
+	)
+	api.POST("/auth/staff-invitations/accept",
+		middleware.NewRateLimitByKeyStrict(ipKey, 10, 300, cfg.RedisURL), // 10/5min
+		staff.AcceptInvitation,
+	)
+
+	g.GET("/me/staff", staff.List)
+	g.PATCH("/me/staff/:id/active", middleware.RequireOwner, staff.SetActive)
+	g.GET("/me/staff/invitations", middleware.RequireOwner, staff.ListInvitations)
+	g.POST("/me/staff/invitations", middleware.RequireOwner, staff.Invite)
+	g.DELETE("/me/staff/invitations/:id", middleware.RequireOwner, staff.RevokeInvitation)
+
+	g.GET("/me/barbers/:id/services", barberService.List)
+	g.PUT("/me/barbers/:id/services/:serviceId", middleware.RequireOwner, barberService.Set)
+	g.DELETE("/me/barbers/:id/services/:serviceId", middleware.RequireOwner, barberService.Reset)
+}
+
 // registerCatalogRoutes registra rotas de catálogo: barbershop, serviços, produtos e horários.
 func registerCatalogRoutes(
 	g *gin.RouterGroup,
@@ -158,6 +407,7 @@ func registerClientRoutes(
 	clientCategoryOverride *handlers.ClientCategoryOverrideHandler,
 	crm *handlers.CRMHandler,
 	clientAnonymize *handlers.ClientAnonymizeHandler,
+	clientMerge *handlers.ClientMergeHandler,
 	paymentPolicy *handlers.PaymentPolicyHandler,
 ) {
 	g.GET("/me/clients", client.List)
@@ -167,11 +417,52 @@ func registerClientRoutes(
 	g.PUT("/me/clients/:id/category", clientCategoryOverride.Update)
 	// LGPD — anonimização de dados pessoais a pedido do titular
 	g.POST("/me/clients/:id/anonymize", middleware.RequireOwner, clientAnonymize.Anonymize)
+	// Cadastros duplicados — sugestões, junção e desfazer
+	g.GET("/me/clients/duplicates", middleware.RequireOwner, clientMerge.Duplicates)
+	g.POST("/me/clients/:id/merge", middleware.RequireOwner, clientMerge.Merge)
+	g.GET("/me/clients/merges", middleware.RequireOwner, clientMerge.ListMerges)
+	g.POST("/me/clients/merges/:id/undo", middleware.RequireOwner, clientMerge.Undo)
 
 	g.GET("/me/payment-policies", middleware.RequireOwner, paymentPolicy.Get)
 	g.PUT("/me/payment-policies", middleware.RequireOwner, paymentPolicy.Update)
 }
 
+// registerClientProfileRoutes registra anotações, etiquetas, preferências e
+// fotos do cliente. Só o dono gerencia o catálogo de etiquetas.
+func registerClientProfileRoutes(
+	g *gin.RouterGroup,
+	profile *handlers.ClientProfileHandler,
+) {
+	g.GET("/me/clients/:id/notes", profile.ListNotes)
+	g.POST("/me/clients/:id/notes", profile.CreateNote)
+	g.PUT("/me/clients/:id/notes/:noteId", profile.UpdateNote)
+	g.DELETE("/me/clients/:id/notes/:noteId", profile.DeleteNote)
+
+	g.GET("/me/client-tags", profile.ListTags)
+	g.POST("/me/client-tags", middleware.RequireOwner, profile.CreateTag)
+	g.PUT("/me/client-tags/:id", middleware.RequireOwner, profile.UpdateTag)
+	g.DELETE("/me/client-tags/:id", middleware.RequireOwner, profile.DeleteTag)
+	g.PUT("/me/clients/:id/tags", profile.SetClientTags)
+
+	g.GET("/me/clients/:id/preferences", profile.GetPreferences)
+	g.PUT("/me/clients/:id/preferences", profile.UpdatePreferences)
+
+	g.GET("/me/clients/:id/photos", profile.ListPhotos)
+	g.POST("/me/clients/:id/photos", profile.UploadPhoto)
+	g.DELETE("/me/clients/:id/photos/:photoId", profile.DeletePhoto)
+}
+
+// registerAppointmentSeriesRoutes registra as séries de agendamentos recorrentes.
+func registerAppointmentSeriesRoutes(
+	g *gin.RouterGroup,
+	series *handlers.AppointmentSeriesHandler,
+) {
+	g.POST("/me/appointment-series", series.Create)
+	g.GET("/me/appointment-series/:id", series.Get)
+	g.PATCH("/me/appointment-series/:id", series.Update)
+	g.DELETE("/me/appointment-series/:id", series.Cancel)
+}
+
 // registerAppointmentRoutes registra agendamentos, pagamentos, pedidos e fechamentos.
 func registerAppointmentRoutes(
 	g *gin.RouterGroup,
@@ -179,6 +470,7 @@ func registerAppointmentRoutes(
 	internalAppt *handlers.InternalAppointmentHandler,
 	closureAdj *handlers.ClosureAdjustmentHandler,
 	payment *handlers.PaymentHandler,
+	paymentRefund *handlers.PaymentRefundHandler,
 	opSummary *handlers.OperationalSummaryHandler,
 	paymentReport *handlers.PaymentReportHandler,
 	order *handlers.OrderHandler,
@@ -195,10 +487,11 @@ func registerAppointmentRoutes(
 
 	g.POST("/me/internal-appointments", internalAppt.Create)
 
-	g.GET("/me/payments", payment.List)
+	g.GET("/me/payments", middleware.RequireOwner, payment.List)
 	g.GET("/me/payments/cash-due", payment.CashDue)
 	g.GET("/me/summary", opSummary.Get)
-	g.GET("/me/payments/summary", paymentReport.Summary)
+	g.GET("/me/payments/summary", middleware.RequireOwner, paymentReport.Summary)
+	g.POST("/me/payments/:id/refund", middleware.RequireOwner, paymentRefund.Refund)
 
 	g.POST("/me/orders", order.Create)
 	g.GET("/me/orders", order.List)
@@ -237,6 +530,7 @@ func registerAdminRoutes(
 	g.POST("/me/subscriptions", middleware.RequireOwner, subscription.Activate)
 	g.DELETE("/me/subscriptions/:clientID", middleware.RequireOwner, subscription.Cancel)
 	g.GET("/me/subscriptions/:clientID", subscription.GetActive)
+	g.PUT("/me/subscriptions/:clientID/auto-renew", middleware.RequireOwner, subscription.SetAutoRenew)
 
 	g.GET("/me/billing/status", billing.Status)
 	g.POST("/me/billing/checkout", middleware.RequireOwner, billing.Checkout)
@@ -251,3 +545,37 @@ func registerAdminRoutes(
 		g.DELETE("/me/profile/photo", middleware.RequireOwner, image.DeleteProfilePhoto)
 	}
 }
+
+// registerClientPortalRoutes registra o portal do cliente: login sem senha
+// por código (limites fail-closed, como o login da equipe) e a área /me,
+// autenticada pelo token de sessão do portal.
+func registerClientPortalRoutes(
+	api *gin.RouterGroup,
+	cfg *config.Config,
+	portal *handlers.ClientPortalHandler,
+	sessions middleware.ClientSessionAuthenticator,
+) {
+	g := api.Group("/public")
+
+	slugKey := func(c *gin.Context) string {
+		return middleware.ClientIPKey(c) + ":" + c.Param("slug")
+	}
+
+	g.POST("/:slug/me/login/code",
+		middleware.NewRateLimitByKeyStrict(slugKey, 5, 300, cfg.RedisURL), // 5/5min
+		portal.RequestCode,
+	)
+	g.POST("/:slug/me/login/verify",
+		middleware.NewRateLimitByKeyStrict(slugKey, 10, 300, cfg.RedisURL), // 10/5min
+		portal.VerifyCode,
+	)
+
+	me := g.Group("/:slug/me", middleware.ClientSessionMiddleware(sessions))
+	me.GET("", portal.Me)
+	me.PUT("", portal.UpdateContact)
+	me.POST("/logout", portal.Logout)
+	me.GET("/appointments", portal.Appointments)
+	me.GET("/subscription", portal.Subscription)
+	me.GET("/packages", portal.Packages)
+	me.GET("/orders", portal.Orders)
+}
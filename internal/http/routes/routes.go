@@ -10,6 +10,7 @@ import (
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	"github.com/BruksfildServices01/barber-scheduler/internal/config"
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
 	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/handlers"
@@ -17,16 +18,21 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
 	"github.com/BruksfildServices01/barber-scheduler/internal/idempotency"
 	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	"github.com/BruksfildServices01/barber-scheduler/internal/integration/payment/mercadopago"
 	paymentinfra "github.com/BruksfildServices01/barber-scheduler/internal/integration/payment"
 	gcal "github.com/BruksfildServices01/barber-scheduler/internal/integration/calendar"
+	"github.com/BruksfildServices01/barber-scheduler/internal/integration/email"
 	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
 	"github.com/BruksfildServices01/barber-scheduler/internal/storage"
 	"github.com/BruksfildServices01/barber-scheduler/internal/jobs"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 	"github.com/BruksfildServices01/barber-scheduler/internal/http/middleware"
 	ucAppointment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+	ucCalendarFeed "github.com/BruksfildServices01/barber-scheduler/internal/usecase/calendarfeed"
 	ucCart        "github.com/BruksfildServices01/barber-scheduler/internal/usecase/cart"
 	ucClientPkg   "github.com/BruksfildServices01/barber-scheduler/internal/usecase/client"
+	ucInventory "github.com/BruksfildServices01/barber-scheduler/internal/usecase/inventory"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 	ucOrder "github.com/BruksfildServices01/barber-scheduler/internal/usecase/order"
 	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
@@ -35,9 +41,21 @@ import (
 	ucPublic "github.com/BruksfildServices01/barber-scheduler/internal/usecase/public"
 	ucService "github.com/BruksfildServices01/barber-scheduler/internal/usecase/service"
 	ucTicket "github.com/BruksfildServices01/barber-scheduler/internal/usecase/ticket"
+	ucWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/usecase/waitlist"
 	ucServiceSuggestion "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicesuggestion"
+	ucPackage "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicepackage"
+	ucCoupon "github.com/BruksfildServices01/barber-scheduler/internal/usecase/coupon"
+	ucClientPortal "github.com/BruksfildServices01/barber-scheduler/internal/usecase/clientportal"
+	ucClientProfile "github.com/BruksfildServices01/barber-scheduler/internal/usecase/clientprofile"
+	ucNotificationTemplate "github.com/BruksfildServices01/barber-scheduler/internal/usecase/notificationtemplate"
+	ucWhatsAppBot "github.com/BruksfildServices01/barber-scheduler/internal/usecase/whatsappbot"
+	ucCampaign "github.com/BruksfildServices01/barber-scheduler/internal/usecase/campaign"
+	ucReport "github.com/BruksfildServices01/barber-scheduler/internal/usecase/report"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
 	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 
+	clienthistory "github.com/BruksfildServices01/barber-scheduler/internal/query/client_history"
+	qClientPortal "github.com/BruksfildServices01/barber-scheduler/internal/query/clientportal"
 	"github.com/BruksfildServices01/barber-scheduler/internal/query/crm"
 	"github.com/BruksfildServices01/barber-scheduler/internal/query/dashboard"
 	"github.com/BruksfildServices01/barber-scheduler/internal/query/daypanel"
@@ -61,23 +79,39 @@ func RegisterRoutes(
 	paymentRepo := infraRepo.NewPaymentGormRepository(db)
 	paymentConfigRepo := infraRepo.NewBarbershopPaymentConfigGormRepository(db)
 	clientMetricsRepo := infraRepo.NewClientMetricsGormRepository(db)
+	classificationRulesRepo := infraRepo.NewClassificationRulesGormRepository(db)
+	campaignRepo := infraRepo.NewCampaignGormRepository(db)
 
 	orderRepo := infraRepo.NewOrderGormRepository(db)
 	productRepo := infraRepo.NewProductGormRepository(db)
+	inventoryRepo := infraRepo.NewInventoryGormRepository(db)
 	serviceRepo := infraRepo.NewServiceGormRepository(db)
 	serviceSuggestionRepo := infraRepo.NewServiceSuggestionGormRepository(db)
 	subscriptionRepo := infraRepo.NewSubscriptionGormRepository(db)
 
 	ticketRepo := infraRepo.NewTicketGormRepository(db)
+	waitlistRepo := infraRepo.NewWaitlistGormRepository(db)
+	calendarFeedRepo := infraRepo.NewCalendarFeedGormRepository(db)
+	servicePackageRepo := infraRepo.NewServicePackageGormRepository(db)
+	couponRepo := infraRepo.NewCouponGormRepository(db)
+	loyaltyRepo := infraRepo.NewLoyaltyGormRepository(db)
+	notificationTemplateRepo := infraRepo.NewNotificationTemplateGormRepository(db)
 
 	idemStore := idempotency.NewGormStore(db)
 	cartMemoryStore := cartStore.NewPostgresStore(db)
 
 	// ======================================================
-	// AUDIT
+	// OUTBOX + AUDIT
 	// ======================================================
+	// Auditoria, notificações de agendamento e sincronização com o Google
+	// Calendar são gravadas no outbox (na transação da mudança, nos fluxos
+	// que a expõem) e entregues pelo job de entrega com retentativas.
+	eventOutbox := outbox.New(db)
+	outboxWorker := outbox.NewWorker(db)
+
 	auditLogger := audit.New(db)
-	auditDispatcher := audit.NewDispatcher(auditLogger)
+	auditDispatcher := audit.NewDispatcher(auditLogger).WithOutbox(eventOutbox)
+	outboxWorker.Handle(outbox.TopicAudit, "", auditLogger.Deliver)
 
 	// ======================================================
 	// MERCADO PAGO
@@ -118,12 +152,21 @@ func RegisterRoutes(
 		notifier = notification.NewNoopNotifier()
 	}
 
-	var apptNotifier domainNotification.AppointmentNotifier
+	// Sem canal ativo, o notifier do outbox não grava nada. Os textos
+	// personalizados da barbearia (notification_templates) valem nos dois
+	// canais.
+	var apptChannels []string
 	if cfg.EmailEnabled {
-		apptNotifier = notification.NewAsyncAppointmentNotifier(notification.NewEmailNotifier(cfg))
-	} else {
-		apptNotifier = notification.NewNoopNotifier()
+		apptChannels = append(apptChannels, outbox.ChannelEmail)
+		outboxWorker.HandleAppointmentNotifier(outbox.ChannelEmail,
+			notification.NewEmailNotifier(cfg).WithTemplates(notificationTemplateRepo))
 	}
+	if cfg.EvolutionURL != "" {
+		apptChannels = append(apptChannels, outbox.ChannelWhatsApp)
+		outboxWorker.HandleAppointmentNotifier(outbox.ChannelWhatsApp,
+			notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL).WithTemplates(notificationTemplateRepo))
+	}
+	var apptNotifier domainNotification.AppointmentNotifier = eventOutbox.Notifier(apptChannels...)
 
 	// ======================================================
 	// PAYMENT USE CASES
@@ -158,6 +201,13 @@ func RegisterRoutes(
 		cfg.AppURL,
 	)
 
+	refundPaymentUC := ucPayment.NewRefundPayment(
+		paymentRepo,
+		transparentGateway,
+		auditDispatcher,
+	)
+	syncPaymentReversalUC := ucPayment.NewSyncPaymentReversal(paymentRepo, auditDispatcher)
+
 	listPaymentsUC := ucPayment.NewListPaymentsForBarbershop(paymentRepo)
 	getPaymentSummaryUC := ucPayment.NewGetPaymentSummary(paymentRepo)
 
@@ -177,10 +227,10 @@ func RegisterRoutes(
 	// ======================================================
 	// METRICS USE CASES
 	// ======================================================
-	updateClientMetricsUC := ucMetrics.NewUpdateClientMetrics(clientMetricsRepo, db)
-	getClientCategoryUC := ucMetrics.NewGetClientCategory(clientMetricsRepo)
-	getClientsWithCategoryUC := ucMetrics.NewGetClientsWithCategory(clientMetricsRepo)
-	setClientCategoryUC := ucMetrics.NewSetClientCategory(clientMetricsRepo)
+	updateClientMetricsUC := ucMetrics.NewUpdateClientMetrics(clientMetricsRepo, db).WithRules(classificationRulesRepo)
+	getClientCategoryUC := ucMetrics.NewGetClientCategory(clientMetricsRepo).WithRules(classificationRulesRepo)
+	getClientsWithCategoryUC := ucMetrics.NewGetClientsWithCategory(clientMetricsRepo).WithRules(classificationRulesRepo)
+	setClientCategoryUC := ucMetrics.NewSetClientCategory(clientMetricsRepo).WithRules(classificationRulesRepo)
 
 	// ======================================================
 	// SUBSCRIPTION USE CASES
@@ -205,6 +255,47 @@ func RegisterRoutes(
 		cfg.BackendURL,
 	)
 
+	// ======================================================
+	// PACOTES E COMBOS
+	// ======================================================
+	createPackageUC := ucPackage.NewCreatePackage(servicePackageRepo)
+	updatePackageUC := ucPackage.NewUpdatePackage(servicePackageRepo)
+	listPackagesUC := ucPackage.NewListPackages(servicePackageRepo)
+	createComboUC := ucPackage.NewCreateCombo(servicePackageRepo)
+	updateComboUC := ucPackage.NewUpdateCombo(servicePackageRepo)
+	listCombosUC := ucPackage.NewListCombos(servicePackageRepo)
+	listClientPackagesUC := ucPackage.NewListClientPackages(servicePackageRepo)
+	purchasePackageUC := ucPackage.NewPurchasePackage(
+		servicePackageRepo,
+		paymentRepo,
+		transparentGateway,
+		auditDispatcher,
+		cfg.BackendURL,
+	)
+
+	// ======================================================
+	// CUPONS
+	// ======================================================
+	createCouponUC := ucCoupon.NewCreateCoupon(couponRepo)
+	updateCouponUC := ucCoupon.NewUpdateCoupon(couponRepo)
+	listCouponsUC := ucCoupon.NewListCoupons(couponRepo)
+	listCouponRedemptionsUC := ucCoupon.NewListRedemptions(couponRepo)
+	couponApplier := ucCoupon.NewApplier(couponRepo)
+	purchaseSubscriptionUC.WithCoupons(couponApplier)
+
+	// ======================================================
+	// FIDELIDADE
+	// ======================================================
+	getLoyaltyProgramUC := ucLoyalty.NewGetProgram(loyaltyRepo)
+	updateLoyaltyProgramUC := ucLoyalty.NewUpdateProgram(loyaltyRepo)
+	createLoyaltyRewardUC := ucLoyalty.NewCreateReward(loyaltyRepo)
+	updateLoyaltyRewardUC := ucLoyalty.NewUpdateReward(loyaltyRepo)
+	listLoyaltyRewardsUC := ucLoyalty.NewListRewards(loyaltyRepo)
+	getClientLoyaltyUC := ucLoyalty.NewGetClientLoyalty(loyaltyRepo)
+	loyaltyLedger := ucLoyalty.NewLedger(loyaltyRepo)
+	createTransparentPaymentUC.WithLoyalty(loyaltyLedger)
+	markMPPaymentAsPaidUC.WithLoyalty(loyaltyLedger)
+
 	// ======================================================
 	// PAYMENT CONFIG
 	// ======================================================
@@ -217,6 +308,9 @@ func RegisterRoutes(
 	// ======================================================
 	createServiceUC := ucService.NewCreateService(serviceRepo)
 	updateServiceUC := ucService.NewUpdateService(serviceRepo)
+	listBarberServicesUC := ucService.NewListBarberServices(serviceRepo)
+	setBarberServiceUC := ucService.NewSetBarberService(serviceRepo)
+	resetBarberServiceUC := ucService.NewResetBarberService(serviceRepo)
 	listPublicServicesUC := ucService.NewListPublicServices(serviceRepo)
 
 	// ======================================================
@@ -231,13 +325,13 @@ func RegisterRoutes(
 	// PRODUCT USE CASES
 	// ======================================================
 	createProductUC := ucProduct.NewCreateProduct(productRepo)
-	updateProductUC := ucProduct.NewUpdateProduct(productRepo)
+	updateProductUC := ucProduct.NewUpdateProduct(productRepo, inventoryRepo)
 	listPublicProductsUC := ucProduct.NewListPublicProducts(productRepo)
 
 	// ======================================================
 	// ORDER USE CASES
 	// ======================================================
-	createOrderUC := ucOrder.NewCreateOrder(db, orderRepo, productRepo)
+	createOrderUC := ucOrder.NewCreateOrder(db, orderRepo, productRepo).WithCoupons(couponRepo)
 	getOrderUC := ucOrder.NewGetOrder(orderRepo)
 	listOrdersAdminUC := ucOrder.NewListOrdersAdmin(orderRepo)
 
@@ -261,7 +355,7 @@ func RegisterRoutes(
 		getActiveSubscriptionUC,
 		reserveSubscriptionCutUC,
 		idemStore,
-	)
+	).WithCoupons(couponApplier)
 
 	completeAppointmentUC := ucAppointment.NewCompleteAppointment(
 		db,
@@ -269,11 +363,12 @@ func RegisterRoutes(
 		paymentRepo,
 		orderRepo,
 		productRepo,
+		inventoryRepo,
 		subscriptionRepo,
 		auditDispatcher,
 		updateClientMetricsUC,
 		consumeCutUC,
-	)
+	).WithPackages(servicePackageRepo).WithLoyalty(loyaltyLedger)
 
 	cancelAppointmentUC := ucAppointment.NewCancelAppointment(
 		db,
@@ -282,7 +377,7 @@ func RegisterRoutes(
 		auditDispatcher,
 		updateClientMetricsUC,
 		releaseSubscriptionCutUC,
-	)
+	).WithCoupons(couponRepo)
 
 	markNoShowUC := ucAppointment.NewMarkAppointmentNoShow(
 		db,
@@ -298,14 +393,111 @@ func RegisterRoutes(
 	createInternalAppointmentUC := ucAppointment.NewCreateInternalAppointment(appointmentRepo)
 	getOperationalSummaryUC := ucAppointment.NewGetOperationalSummary(appointmentRepo)
 
+	createSeriesUC := ucAppointment.NewCreateSeries(appointmentRepo, appointmentRepo, createAppointmentUC, auditDispatcher)
+	getSeriesUC := ucAppointment.NewGetSeries(appointmentRepo)
+	updateSeriesUC := ucAppointment.NewUpdateSeries(appointmentRepo, appointmentRepo, auditDispatcher)
+	cancelSeriesUC := ucAppointment.NewCancelSeries(
+		db,
+		appointmentRepo,
+		appointmentRepo,
+		subscriptionRepo,
+		auditDispatcher,
+		releaseSubscriptionCutUC,
+	).WithCoupons(couponRepo)
+
 	// ======================================================
 	// TICKET USE CASES
 	// ======================================================
 	generateTicketUC := ucTicket.NewGenerateTicket(ticketRepo)
 	viewTicketUC := ucTicket.NewViewTicket(db)
-	cancelViaTicketUC := ucTicket.NewCancelViaTicket(db, ticketRepo, apptNotifier, updateClientMetricsUC, auditDispatcher)
+	cancelViaTicketUC := ucTicket.NewCancelViaTicket(db, ticketRepo, apptNotifier, updateClientMetricsUC, auditDispatcher).WithCoupons(couponRepo)
 	rescheduleViaTicketUC := ucTicket.NewRescheduleViaTicket(db, ticketRepo, apptNotifier, updateClientMetricsUC, auditDispatcher, cfg.AppURL)
 
+	// ======================================================
+	// WAITLIST USE CASES
+	// ======================================================
+	// Ofertas: email quando habilitado, WhatsApp quando a Evolution API está configurada.
+	var waitlistEmail domainNotification.WaitlistOfferNotifier
+	if cfg.EmailEnabled {
+		waitlistEmail = notification.NewEmailNotifier(cfg)
+	}
+	var waitlistWhatsApp domainNotification.WaitlistOfferNotifier
+	if cfg.EvolutionURL != "" {
+		waitlistWhatsApp = notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL)
+	}
+
+	offerFreedSlotUC := ucWaitlist.NewOfferFreedSlot(waitlistRepo, waitlistEmail, waitlistWhatsApp, auditDispatcher, cfg.AppURL)
+	joinWaitlistUC := ucWaitlist.NewJoinWaitlist(waitlistRepo)
+	getWaitlistEntryUC := ucWaitlist.NewGetWaitlistEntry(waitlistRepo)
+	claimWaitlistOfferUC := ucWaitlist.NewClaimWaitlistOffer(waitlistRepo, createAppointmentUC, auditDispatcher)
+	leaveWaitlistUC := ucWaitlist.NewLeaveWaitlist(waitlistRepo)
+
+	// Cancelamentos liberam horários para a lista de espera.
+	// Os cancelamentos gravam o horário liberado no outbox; o worker faz as ofertas.
+	outboxWorker.HandleWaitlist(offerFreedSlotUC)
+	waitlistListener := eventOutbox.Waitlist()
+	cancelAppointmentUC.WithWaitlist(waitlistListener)
+	cancelViaTicketUC.WithWaitlist(waitlistListener)
+	expirePaymentsUC.WithWaitlist(waitlistListener)
+	cancelSeriesUC.WithWaitlist(waitlistListener)
+
+	// ======================================================
+	// PORTAL DO CLIENTE
+	// ======================================================
+	// Código de acesso: por email quando habilitado, por WhatsApp quando a
+	// Evolution API está configurada. Canal sem notifier recusa o pedido.
+	var loginCodeEmail domainNotification.LoginCodeNotifier
+	if cfg.EmailEnabled {
+		loginCodeEmail = notification.NewEmailNotifier(cfg)
+	}
+	var loginCodeWhatsApp domainNotification.LoginCodeNotifier
+	if cfg.EvolutionURL != "" {
+		loginCodeWhatsApp = notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL)
+	}
+
+	clientPortalRepo := infraRepo.NewClientPortalGormRepository(db)
+	requestLoginCodeUC := ucClientPortal.NewRequestCode(clientPortalRepo, loginCodeEmail, loginCodeWhatsApp)
+	verifyLoginCodeUC := ucClientPortal.NewVerifyCode(clientPortalRepo)
+	clientSessions := ucClientPortal.NewSessions(clientPortalRepo)
+	updateClientContactUC := ucClientPortal.NewUpdateContact(clientPortalRepo)
+
+	// ======================================================
+	// BOT DE ATENDIMENTO NO WHATSAPP
+	// ======================================================
+	// Usa os mesmos casos de uso do agendamento público e do ticket.
+	var whatsAppBot *ucWhatsAppBot.Bot
+	if cfg.EvolutionURL != "" {
+		whatsAppBot = ucWhatsAppBot.NewBot(
+			infraRepo.NewWhatsAppBotGormRepository(db),
+			listPublicServicesUC,
+			ucAppointment.NewGetAvailability(appointmentRepo),
+			createAppointmentUC,
+			generateTicketUC,
+			cancelViaTicketUC,
+			rescheduleViaTicketUC,
+			notification.NewEvolutionClient(cfg.EvolutionURL, cfg.EvolutionAPIKey),
+			cfg.AppURL,
+		).WithOptOut(ucCampaign.NewOptOutByPhone(campaignRepo))
+	}
+
+	// Canais das campanhas: só os configurados aceitam envio.
+	campaignSenders := ucCampaign.Senders{}
+	if cfg.EmailEnabled {
+		campaignSenders[models.NotificationChannelEmail] = notification.NewEmailNotifier(cfg)
+	}
+	if cfg.EvolutionURL != "" {
+		campaignSenders[models.NotificationChannelWhatsApp] = notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL)
+	}
+
+	// Relatórios por email: as mesmas consultas do painel, enviadas pelo
+	// transporte das notificações. Sem email, nada é enviado.
+	reportRepo := infraRepo.NewReportGormRepository(db)
+	reportBuilder := ucReport.NewQueryBuilder(dashboard.New(db), financial.New(db), impact.New(db))
+	var reportSender email.Sender
+	if cfg.EmailEnabled {
+		reportSender = notification.NewEmailNotifier(cfg)
+	}
+
 	// ======================================================
 	// PAYMENT CIPHER (AES-256 para credenciais de providers e tokens Google)
 	// Inicializado aqui para ser usado tanto em payment providers quanto no Google Calendar.
@@ -320,6 +512,45 @@ func RegisterRoutes(
 		log.Println("[PAYMENT] cipher inicializado para credentials_encrypted e Google tokens")
 	}
 
+	providerRegistry := paymentinfra.NewProviderRegistry(db, paymentCipher, cfg.PagBankSandbox)
+
+	// ======================================================
+	// RENOVAÇÃO AUTOMÁTICA DE ASSINATURAS
+	// ======================================================
+	// Avisos: email quando habilitado, WhatsApp quando a Evolution API está configurada.
+	var renewalEmail domainNotification.SubscriptionRenewalNotifier
+	if cfg.EmailEnabled {
+		renewalEmail = notification.NewEmailNotifier(cfg)
+	}
+	var renewalWhatsApp domainNotification.SubscriptionRenewalNotifier
+	if cfg.EvolutionURL != "" {
+		renewalWhatsApp = notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL)
+	}
+
+	subscriptionRenewalRepo := infraRepo.NewSubscriptionRenewalGormRepository(db, paymentCipher)
+	purchaseSubscriptionUC.WithRenewals(subscriptionRenewalRepo)
+	setAutoRenewUC := ucSubscription.NewSetAutoRenew(subscriptionRenewalRepo)
+	failRenewalChargeUC := ucSubscription.NewFailRenewalCharge(
+		subscriptionRenewalRepo,
+		paymentRepo,
+		auditDispatcher,
+		renewalEmail,
+		renewalWhatsApp,
+	)
+	renewSubscriptionsUC := ucSubscription.NewRenewSubscriptions(
+		subscriptionRenewalRepo,
+		paymentRepo,
+		providerRegistry,
+		failRenewalChargeUC,
+		renewalEmail,
+		renewalWhatsApp,
+		cfg.BackendURL,
+	)
+	// Cobranças aprovadas (na hora ou pelo webhook) renovam pelo MarkMPPaymentAsPaid,
+	// que avisa o cliente de volta pelo listener.
+	markMPPaymentAsPaidUC.WithRenewalListener(renewSubscriptionsUC)
+	renewSubscriptionsUC.WithConfirmer(markMPPaymentAsPaidUC)
+
 	// ======================================================
 	// GOOGLE CALENDAR CONFIG
 	// ======================================================
@@ -329,6 +560,26 @@ func RegisterRoutes(
 		RedirectURL:  cfg.GoogleRedirectURL,
 	}
 
+	// Espelha o ciclo de vida dos agendamentos na agenda Google do barbeiro
+	// (criar, reagendar, cancelar, falta).
+	// Sem Google configurado, o GoogleSync já ignora as mudanças; com ele, as
+	// sincronizações passam pelo outbox.
+	googleSync := gcal.NewGoogleSync(db, googleCalCfg, paymentCipher)
+	var calendarSync domainAppointment.CalendarSync = googleSync
+	if cfg.GoogleClientID != "" {
+		calendarSync = eventOutbox.Calendar()
+		outboxWorker.HandleCalendar(googleSync)
+	}
+	createAppointmentUC.WithCalendarSync(calendarSync)
+	createInternalAppointmentUC.WithCalendarSync(calendarSync)
+	cancelAppointmentUC.WithCalendarSync(calendarSync)
+	markNoShowUC.WithCalendarSync(calendarSync)
+	updateSeriesUC.WithCalendarSync(calendarSync)
+	cancelSeriesUC.WithCalendarSync(calendarSync)
+	cancelViaTicketUC.WithCalendarSync(calendarSync)
+	rescheduleViaTicketUC.WithCalendarSync(calendarSync)
+	expirePaymentsUC.WithCalendarSync(calendarSync)
+
 	// ======================================================
 	// PUBLIC ORCHESTRATION USE CASES
 	// ======================================================
@@ -342,9 +593,7 @@ func RegisterRoutes(
 		apptNotifier,
 		cfg.AppURL,
 		getPublicServiceSuggestionUC,
-		googleCalCfg,
-		paymentCipher,
-	)
+	).WithCoupons(couponRepo)
 
 	// ======================================================
 	// JOBS (P0.3 - leader lock Postgres)
@@ -368,6 +617,20 @@ func RegisterRoutes(
 		expireSubscriptionsUC := ucSubscription.NewExpireSubscriptions(subscriptionRepo)
 		expireSubscriptionsJob := jobs.NewExpireSubscriptionsJob(expireSubscriptionsUC)
 
+		renewSubscriptionsJob := jobs.NewRenewSubscriptionsJob(renewSubscriptionsUC)
+
+		expireClientPackagesUC := ucPackage.NewExpireClientPackages(servicePackageRepo)
+		expireClientPackagesJob := jobs.NewExpireClientPackagesJob(expireClientPackagesUC)
+
+		expireLoyaltyPointsUC := ucLoyalty.NewExpirePoints(loyaltyRepo)
+		expireLoyaltyPointsJob := jobs.NewExpireLoyaltyPointsJob(expireLoyaltyPointsUC)
+
+		reclassifyClientsUC := ucMetrics.NewReclassifyClients(clientMetricsRepo, classificationRulesRepo)
+		reclassifyClientsJob := jobs.NewReclassifyClientsJob(reclassifyClientsUC)
+
+		dispatchCampaignsUC := ucCampaign.NewDispatchCampaigns(campaignRepo, campaignSenders, cfg.AppURL)
+		dispatchCampaignsJob := jobs.NewDispatchCampaignsJob(dispatchCampaignsUC)
+
 		const everyExpire = 10 * time.Minute
 		const ttlExpire = 13 * time.Minute
 		const everyAutoComplete = 50 * time.Minute
@@ -405,6 +668,145 @@ func RegisterRoutes(
 			_ = locker.Unlock(ctx, "job:expire_subscriptions")
 		})
 
+		scheduler.Every(everyHour, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:renew_subscriptions", ttlHour)
+			if err != nil || !ok {
+				return
+			}
+			renewSubscriptionsJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:renew_subscriptions")
+		})
+
+		scheduler.Every(everyHour, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:expire_client_packages", ttlHour)
+			if err != nil || !ok {
+				return
+			}
+			expireClientPackagesJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:expire_client_packages")
+		})
+
+		scheduler.Every(everyHour, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:expire_loyalty_points", ttlHour)
+			if err != nil || !ok {
+				return
+			}
+			expireLoyaltyPointsJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:expire_loyalty_points")
+		})
+
+		// Reclassificação após mudança nas regras de classificação de clientes.
+		const everyReclassify = 5 * time.Minute
+		const ttlReclassify = 20 * time.Minute
+
+		scheduler.Every(everyReclassify, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:reclassify_clients", ttlReclassify)
+			if err != nil || !ok {
+				return
+			}
+			reclassifyClientsJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:reclassify_clients")
+		})
+
+		// Campanhas: a cada minuto, dentro do limite de envio de cada barbearia.
+		const everyCampaigns = time.Minute
+		const ttlCampaigns = 10 * time.Minute
+
+		scheduler.Every(everyCampaigns, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:dispatch_campaigns", ttlCampaigns)
+			if err != nil || !ok {
+				return
+			}
+			dispatchCampaignsJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:dispatch_campaigns")
+		})
+
+		// Lembretes: email quando habilitado, WhatsApp quando a Evolution API está configurada.
+		var reminderEmail domainNotification.ReminderNotifier
+		if cfg.EmailEnabled {
+			reminderEmail = notification.NewEmailNotifier(cfg).WithTemplates(notificationTemplateRepo)
+		}
+		var reminderWhatsApp domainNotification.ReminderNotifier
+		if cfg.EvolutionURL != "" {
+			reminderWhatsApp = notification.NewWhatsAppNotifier(cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.AppURL).WithTemplates(notificationTemplateRepo)
+		}
+
+		reminderJob := jobs.NewAppointmentReminderJob(
+			appointmentRepo,
+			ticketRepo,
+			reminderEmail,
+			reminderWhatsApp,
+			cfg.AppURL,
+		)
+
+		const everyReminder = 5 * time.Minute
+		const ttlReminder = 6 * time.Minute
+
+		scheduler.Every(everyReminder, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:appointment_reminders", ttlReminder)
+			if err != nil || !ok {
+				return
+			}
+			if err := reminderJob.Run(ctx); err != nil {
+				log.Printf("[AppointmentReminderJob] error=%v\n", err)
+			}
+			_ = locker.Unlock(ctx, "job:appointment_reminders")
+		})
+
+		syncGoogleBusyJob := jobs.NewSyncGoogleBusyJob(googleSync)
+
+		scheduler.Every(everyReminder, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:sync_google_busy", ttlReminder)
+			if err != nil || !ok {
+				return
+			}
+			syncGoogleBusyJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:sync_google_busy")
+		})
+
+		// Estoque baixo: só há canal de aviso ao dono com e-mail habilitado.
+		if cfg.EmailEnabled {
+			notifyLowStockUC := ucInventory.NewNotifyLowStock(inventoryRepo, notification.NewEmailNotifier(cfg))
+			notifyLowStockJob := jobs.NewNotifyLowStockJob(notifyLowStockUC)
+
+			scheduler.Every(everyReminder, func(ctx context.Context) {
+				ok, err := locker.TryLock(ctx, "job:notify_low_stock", ttlReminder)
+				if err != nil || !ok {
+					return
+				}
+				notifyLowStockJob.Run(ctx)
+				_ = locker.Unlock(ctx, "job:notify_low_stock")
+			})
+		}
+
+		// Relatórios agendados: enviados até 5 minutos depois do horário.
+		if reportSender != nil {
+			sendReportsJob := jobs.NewSendReportsJob(ucReport.NewDispatchReports(reportRepo, reportBuilder, reportSender))
+
+			scheduler.Every(everyReminder, func(ctx context.Context) {
+				ok, err := locker.TryLock(ctx, "job:send_reports", ttlReminder)
+				if err != nil || !ok {
+					return
+				}
+				sendReportsJob.Run(ctx)
+				_ = locker.Unlock(ctx, "job:send_reports")
+			})
+		}
+
+		// Entrega do outbox: a cada 15s, um nó por vez.
+		deliverOutboxJob := jobs.NewDeliverOutboxJob(outboxWorker)
+		const everyOutbox = 15 * time.Second
+		const ttlOutbox = 2 * time.Minute
+
+		scheduler.Every(everyOutbox, func(ctx context.Context) {
+			ok, err := locker.TryLock(ctx, "job:deliver_outbox", ttlOutbox)
+			if err != nil || !ok {
+				return
+			}
+			deliverOutboxJob.Run(ctx)
+			_ = locker.Unlock(ctx, "job:deliver_outbox")
+		})
+
 		pruneJob := jobs.NewPruneJob(db)
 		const everyDay = 24 * time.Hour
 		const ttlDay = 25 * time.Hour
@@ -431,6 +833,19 @@ func RegisterRoutes(
 		pwMailer = notification.NewNoopNotifier()
 	}
 	passwordResetHandler := handlers.NewPasswordResetHandler(db, cfg.AppURL, pwMailer)
+
+	var staffMailer handlers.StaffMailer
+	if cfg.EmailEnabled {
+		staffMailer = notification.NewEmailNotifier(cfg)
+	} else {
+		staffMailer = notification.NewNoopNotifier()
+	}
+	staffHandler := handlers.NewStaffHandler(db, cfg, staffMailer, auditDispatcher)
+	barberServiceHandler := handlers.NewBarberServiceHandler(
+		listBarberServicesUC,
+		setBarberServiceUC,
+		resetBarberServiceUC,
+	)
 	meHandler := handlers.NewMeHandler(db)
 	barbershopHandler := handlers.NewBarbershopHandler(db)
 
@@ -457,6 +872,13 @@ func RegisterRoutes(
 	workingHoursHandler := handlers.NewWorkingHoursHandler(db, auditDispatcher)
 	scheduleOverrideHandler := handlers.NewScheduleOverrideHandler(db)
 	auditLogsHandler := handlers.NewAuditLogsHandler(db)
+	deliveryLogHandler := handlers.NewDeliveryLogHandler(db, eventOutbox)
+	notificationTemplateHandler := handlers.NewNotificationTemplateHandler(
+		ucNotificationTemplate.NewListTemplates(notificationTemplateRepo),
+		ucNotificationTemplate.NewSaveTemplate(notificationTemplateRepo),
+		ucNotificationTemplate.NewResetTemplate(notificationTemplateRepo),
+		ucNotificationTemplate.NewPreviewTemplate(notificationTemplateRepo, cfg.AppURL),
+	)
 
 	clientHandler := handlers.NewClientHandler(
 		db,
@@ -480,6 +902,42 @@ func RegisterRoutes(
 		setClientCategoryUC,
 	)
 
+	classificationRulesHandler := handlers.NewClassificationRulesHandler(
+		ucMetrics.NewGetClassificationRules(classificationRulesRepo),
+		ucMetrics.NewUpdateClassificationRules(classificationRulesRepo),
+		ucMetrics.NewSimulateClassification(clientMetricsRepo, classificationRulesRepo),
+	)
+
+	segmentHandler := handlers.NewSegmentHandler(
+		ucCampaign.NewListSegments(campaignRepo),
+		ucCampaign.NewSaveSegment(campaignRepo),
+		ucCampaign.NewDeleteSegment(campaignRepo),
+		ucCampaign.NewPreviewSegment(campaignRepo),
+	)
+
+	campaignHandler := handlers.NewCampaignHandler(
+		ucCampaign.NewListCampaigns(campaignRepo),
+		ucCampaign.NewGetCampaign(campaignRepo),
+		ucCampaign.NewCreateCampaign(campaignRepo),
+		ucCampaign.NewUpdateCampaign(campaignRepo),
+		ucCampaign.NewDeleteCampaign(campaignRepo),
+		ucCampaign.NewScheduleCampaign(campaignRepo, campaignSenders),
+		ucCampaign.NewCancelCampaign(campaignRepo),
+		ucCampaign.NewListRecipients(campaignRepo),
+		ucCampaign.NewPreviewCampaign(campaignRepo, cfg.AppURL),
+		ucCampaign.NewUnsubscribe(campaignRepo),
+		ucCampaign.NewSetMarketingOptOut(campaignRepo),
+	)
+
+	reportSubscriptionHandler := handlers.NewReportSubscriptionHandler(
+		ucReport.NewListSubscriptions(reportRepo),
+		ucReport.NewCreateSubscription(reportRepo),
+		ucReport.NewUpdateSubscription(reportRepo),
+		ucReport.NewDeleteSubscription(reportRepo),
+		ucReport.NewSendNow(reportRepo, reportBuilder, reportSender),
+		ucReport.NewListDeliveries(reportRepo),
+	)
+
 	paymentPolicyHandler := handlers.NewPaymentPolicyHandler(
 		getPaymentPoliciesUC,
 		updatePaymentPoliciesUC,
@@ -487,7 +945,7 @@ func RegisterRoutes(
 	)
 
 	whatsappHandler        := handlers.NewWhatsAppHandler(db, cfg.EvolutionURL, cfg.EvolutionAPIKey, cfg.BackendURL)
-	whatsappWebhookHandler := handlers.NewWhatsAppWebhookHandler(db, cfg.EvolutionURL, cfg.EvolutionAPIKey)
+	whatsappWebhookHandler := handlers.NewWhatsAppWebhookHandler(db, cfg.EvolutionAPIKey).WithBot(whatsAppBot)
 
 	mpOAuthHandler := handlers.NewMPOAuthHandler(
 		db,
@@ -505,9 +963,13 @@ func RegisterRoutes(
 		markNoShowUC,
 		listByDateUC,
 		listByMonthUC,
-		db,
-		googleCalCfg,
-		paymentCipher,
+	)
+
+	appointmentSeriesHandler := handlers.NewAppointmentSeriesHandler(
+		createSeriesUC,
+		getSeriesUC,
+		updateSeriesUC,
+		cancelSeriesUC,
 	)
 
 	internalAppointmentHandler := handlers.NewInternalAppointmentHandler(
@@ -533,14 +995,33 @@ func RegisterRoutes(
 
 	publicTicketHandler := handlers.NewPublicTicketHandler(viewTicketUC, cancelViaTicketUC, rescheduleViaTicketUC)
 
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(
+		ucCalendarFeed.NewGetFeed(calendarFeedRepo),
+		ucCalendarFeed.NewRotateFeedToken(calendarFeedRepo),
+		ucCalendarFeed.NewRenderFeed(calendarFeedRepo),
+		cfg.BackendURL,
+	)
+
+	inventoryHandler := handlers.NewInventoryHandler(
+		ucInventory.NewRecordStockEntries(inventoryRepo),
+		ucInventory.NewRecordStockCount(inventoryRepo),
+		ucInventory.NewListStockMovements(inventoryRepo),
+	)
+
+	waitlistHandler := handlers.NewWaitlistHandler(
+		db,
+		joinWaitlistUC,
+		getWaitlistEntryUC,
+		claimWaitlistOfferUC,
+		leaveWaitlistUC,
+	)
+
 	mpPaymentHandler := handlers.NewMPPaymentHandler(
 		db,
 		createPaymentForAppointmentUC,
 		createMPPreferenceUC,
 	)
 
-	providerRegistry := paymentinfra.NewProviderRegistry(db, paymentCipher, cfg.PagBankSandbox)
-
 	pagbankOAuthHandler := handlers.NewPagBankOAuthHandler(
 		db,
 		cfg.PagBankClientID,
@@ -555,9 +1036,10 @@ func RegisterRoutes(
 	pagbankWebhookHandler := handlers.NewPagBankWebhookHandler(
 		db,
 		markMPPaymentAsPaidUC,
+		syncPaymentReversalUC,
 		paymentCipher,
 		cfg.PagBankSandbox,
-	)
+	).WithRenewalFailure(failRenewalChargeUC)
 
 	transparentPaymentHandler := handlers.NewTransparentPaymentHandler(
 		db,
@@ -572,7 +1054,9 @@ func RegisterRoutes(
 		cfg.MPWebhookSecret,
 		cfg.MPProvider == "mp", // requireSignature: obrigatório quando em modo produção real
 		db,
-	).WithRegistry(providerRegistry)
+	).WithRegistry(providerRegistry).
+		WithReversalSync(syncPaymentReversalUC).
+		WithRenewalFailure(failRenewalChargeUC)
 
 	orderHandler := handlers.NewOrderHandler(
 		createOrderUC,
@@ -581,9 +1065,16 @@ func RegisterRoutes(
 		orderRepo,
 	)
 
+	orderReturnHandler := handlers.NewOrderReturnHandler(
+		ucOrder.NewReturnOrder(db, orderRepo, productRepo, refundPaymentUC, providerRegistry, auditDispatcher).WithLoyalty(loyaltyLedger).WithCoupons(couponRepo),
+		ucOrder.NewMarkReturnRefunded(orderRepo, auditDispatcher),
+		orderRepo,
+	)
+
 	closureListHandler := handlers.NewClosureListHandler(db)
 
 	paymentHandler := handlers.NewPaymentHandler(db, listPaymentsUC)
+	paymentRefundHandler := handlers.NewPaymentRefundHandler(db, refundPaymentUC, providerRegistry)
 	paymentReportHandler := handlers.NewPaymentReportHandler(
 		getPaymentSummaryUC,
 		appointmentRepo,
@@ -592,16 +1083,56 @@ func RegisterRoutes(
 		getOperationalSummaryUC,
 	)
 	planHandler := handlers.NewPlanHandler(createPlanUC, updatePlanUC, setPlanActiveUC, listPlansUC, deletePlanUC)
+	packageHandler := handlers.NewPackageHandler(
+		createPackageUC,
+		updatePackageUC,
+		listPackagesUC,
+		createComboUC,
+		updateComboUC,
+		listCombosUC,
+		listClientPackagesUC,
+	)
+
+	couponHandler := handlers.NewCouponHandler(
+		createCouponUC,
+		updateCouponUC,
+		listCouponsUC,
+		listCouponRedemptionsUC,
+	)
+
+	loyaltyHandler := handlers.NewLoyaltyHandler(
+		getLoyaltyProgramUC,
+		updateLoyaltyProgramUC,
+		createLoyaltyRewardUC,
+		updateLoyaltyRewardUC,
+		listLoyaltyRewardsUC,
+		getClientLoyaltyUC,
+	)
+
+	clientPortalHandler := handlers.NewClientPortalHandler(
+		db,
+		requestLoginCodeUC,
+		verifyLoginCodeUC,
+		clientSessions,
+		updateClientContactUC,
+		qClientPortal.NewQuery(db),
+		clienthistory.NewService(clienthistory.NewRepository(db), getClientCategoryUC, getActiveSubscriptionUC),
+	)
 
 	dayPanelQuery := daypanel.New(db)
 	dayPanelHandler := handlers.NewDayPanelHandler(dayPanelQuery)
 
-	crmQuery := crm.New(db)
+	crmQuery := crm.New(db).WithRules(classificationRulesRepo)
 	crmHandler := handlers.NewCRMHandler(crmQuery, auditDispatcher)
 
 	anonymizeClientUC      := ucClientPkg.NewAnonymizeClient(db, auditDispatcher)
 	clientAnonymizeHandler := handlers.NewClientAnonymizeHandler(anonymizeClientUC)
 
+	clientMergeHandler := handlers.NewClientMergeHandler(
+		ucClientPkg.NewFindDuplicates(db),
+		ucClientPkg.NewMergeClients(db, auditDispatcher, clientMetricsRepo).WithRules(classificationRulesRepo),
+	)
+
 	dashboardQuery := dashboard.New(db)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardQuery)
 
@@ -611,24 +1142,49 @@ func RegisterRoutes(
 	impactQuery := impact.New(db)
 	impactHandler := handlers.NewImpactHandler(impactQuery)
 
-	adjustClosureUC := ucAppointment.NewAdjustClosure(db, auditDispatcher)
+	adjustClosureUC := ucAppointment.NewAdjustClosure(db, auditDispatcher).WithLoyalty(loyaltyLedger)
 	closureAdjustmentHandler := handlers.NewClosureAdjustmentHandler(adjustClosureUC)
 
-	// R2 storage — only active when credentials are configured.
-	var imageHandler *handlers.ImageHandler
+	// R2 storage — only active when credentials are configured. Sem R2,
+	// LOCAL_STORAGE_DIR grava em disco e serve em /uploads (desenvolvimento).
+	var imageStore storage.Storage
 	if cfg.R2AccountID != "" && cfg.R2BucketName != "" {
-		r2 := storage.NewR2Service(
+		imageStore = storage.NewR2Service(
 			cfg.R2AccountID,
 			cfg.R2AccessKeyID,
 			cfg.R2SecretAccessKey,
 			cfg.R2BucketName,
 			cfg.R2PublicURL,
 		)
-		imageHandler = handlers.NewImageHandler(db, r2)
 		log.Println("[R2] storage enabled, bucket:", cfg.R2BucketName)
+	} else if cfg.LocalStorageDir != "" {
+		imageStore = storage.NewLocalStorage(cfg.LocalStorageDir, cfg.BackendURL+"/uploads")
+		r.Static("/uploads", cfg.LocalStorageDir)
+		log.Println("[storage] local storage enabled, dir:", cfg.LocalStorageDir)
 	} else {
 		log.Println("[R2] storage disabled (credentials not set)")
 	}
+	var imageHandler *handlers.ImageHandler
+	if imageStore != nil {
+		imageHandler = handlers.NewImageHandler(db, imageStore)
+	}
+	anonymizeClientUC.WithStorage(imageStore)
+
+	clientProfileRepo := infraRepo.NewClientProfileGormRepository(db)
+	clientProfileHandler := handlers.NewClientProfileHandler(
+		ucClientProfile.NewListClientNotes(clientProfileRepo),
+		ucClientProfile.NewSaveClientNote(clientProfileRepo),
+		ucClientProfile.NewDeleteClientNote(clientProfileRepo),
+		ucClientProfile.NewListTags(clientProfileRepo),
+		ucClientProfile.NewSaveTag(clientProfileRepo),
+		ucClientProfile.NewDeleteTag(clientProfileRepo),
+		ucClientProfile.NewSetClientTags(clientProfileRepo),
+		ucClientProfile.NewGetClientPreferences(clientProfileRepo),
+		ucClientProfile.NewSaveClientPreferences(clientProfileRepo),
+		ucClientProfile.NewListClientPhotos(clientProfileRepo),
+		ucClientProfile.NewAddClientPhoto(clientProfileRepo, imageStore),
+		ucClientProfile.NewDeleteClientPhoto(clientProfileRepo, imageStore),
+	)
 
 	subscriptionQuery := subscription.New(db)
 
@@ -636,6 +1192,7 @@ func RegisterRoutes(
 		activateSubscriptionUC,
 		cancelSubscriptionUC,
 		getActiveSubscriptionUC,
+		setAutoRenewUC,
 		subscriptionQuery,
 		auditDispatcher,
 	)
@@ -646,6 +1203,13 @@ func RegisterRoutes(
 		purchaseSubscriptionUC,
 		providerRegistry,
 	)
+	publicPackageHandler := handlers.NewPublicPackageHandler(
+		db,
+		listPackagesUC,
+		listCombosUC,
+		purchasePackageUC,
+		providerRegistry,
+	)
 
 	billingHandler := handlers.NewBillingHandler(db, cfg, idemStore)
 
@@ -658,6 +1222,10 @@ func RegisterRoutes(
 		mpPaymentHandler, transparentPaymentHandler,
 		publicSubscriptionHandler, publicTicketHandler)
 
+	registerWaitlistRoutes(api, cfg, waitlistHandler)
+
+	registerClientPortalRoutes(api, cfg, clientPortalHandler, clientSessions)
+
 	// Fallback para quando o webhook MP não chega: frontend consulta status diretamente.
 	api.GET("/public/:slug/appointments/:id/payment/status",
 		middleware.NewRateLimitByKey(func(c *gin.Context) string {
@@ -672,26 +1240,30 @@ func RegisterRoutes(
 	secured := api.Group("/")
 	secured.Use(middleware.AuthMiddleware(cfg, db))
 
+	registerStaffRoutes(api, secured, cfg, staffHandler, barberServiceHandler)
+
 	registerCatalogRoutes(secured, meHandler, barbershopHandler,
 		serviceHandler, serviceCategoryHandler, serviceSuggestionHandler,
 		productHandler, workingHoursHandler, scheduleOverrideHandler)
 
 	registerClientRoutes(secured, clientHandler, clientHistoryHandler,
 		clientCategoryHandler, clientCategoryOverrideHandler, crmHandler,
-		clientAnonymizeHandler, paymentPolicyHandler)
+		clientAnonymizeHandler, clientMergeHandler, paymentPolicyHandler)
+
+	registerClientProfileRoutes(secured, clientProfileHandler)
 
 	// ── WhatsApp ────────────────────────────────────────────────────────────
 	// ── Mercado Pago OAuth ─────────────────────────────────────────
-	secured.GET("/me/mercadopago/oauth/start",    mpOAuthHandler.Start)
+	secured.GET("/me/mercadopago/oauth/start",    middleware.RequireOwner, mpOAuthHandler.Start)
 	secured.GET("/me/mercadopago/oauth/status",   mpOAuthHandler.Status)
-	secured.DELETE("/me/mercadopago/oauth",       mpOAuthHandler.Disconnect)
+	secured.DELETE("/me/mercadopago/oauth",       middleware.RequireOwner, mpOAuthHandler.Disconnect)
 	// Callback público — MP redireciona aqui após autorização
 	api.GET("/mercadopago/oauth/callback",        mpOAuthHandler.Callback)
 
 	// PagBank OAuth
-	secured.GET("/me/pagbank/oauth/start",        pagbankOAuthHandler.Start)
+	secured.GET("/me/pagbank/oauth/start",        middleware.RequireOwner, pagbankOAuthHandler.Start)
 	secured.GET("/me/pagbank/oauth/status",        pagbankOAuthHandler.Status)
-	secured.DELETE("/me/pagbank/oauth",            pagbankOAuthHandler.Disconnect)
+	secured.DELETE("/me/pagbank/oauth",            middleware.RequireOwner, pagbankOAuthHandler.Disconnect)
 	// Callback público — PagBank redireciona aqui após autorização
 	api.GET("/pagbank/oauth/callback",             pagbankOAuthHandler.Callback)
 	// Webhook de pagamento PagBank
@@ -705,17 +1277,41 @@ func RegisterRoutes(
 	api.GET("/google/oauth/callback",      googleOAuthHandler.Callback)
 
 	secured.GET("/me/whatsapp/status",          whatsappHandler.Status)
-	secured.POST("/me/whatsapp/connect",        whatsappHandler.Connect)
-	secured.POST("/me/whatsapp/pairing-code",   whatsappHandler.PairingCode)
-	secured.DELETE("/me/whatsapp/connect",      whatsappHandler.Disconnect)
+	secured.POST("/me/whatsapp/connect",        middleware.RequireOwner, whatsappHandler.Connect)
+	secured.POST("/me/whatsapp/pairing-code",   middleware.RequireOwner, whatsappHandler.PairingCode)
+	secured.DELETE("/me/whatsapp/connect",      middleware.RequireOwner, whatsappHandler.Disconnect)
 
 	// Webhook público — Evolution API dispara aqui quando cliente manda mensagem
 	api.POST("/webhooks/whatsapp", whatsappWebhookHandler.Receive)
 
 	registerAppointmentRoutes(secured, appointmentHandler, internalAppointmentHandler,
-		closureAdjustmentHandler, paymentHandler, operationalSummaryHandler,
+		closureAdjustmentHandler, paymentHandler, paymentRefundHandler, operationalSummaryHandler,
 		paymentReportHandler, orderHandler, closureListHandler, auditLogsHandler)
 
+	registerAppointmentSeriesRoutes(secured, appointmentSeriesHandler)
+
+	registerCalendarFeedRoutes(api, secured, cfg, calendarFeedHandler)
+
+	registerInventoryRoutes(secured, inventoryHandler)
+
+	registerOrderReturnRoutes(secured, orderReturnHandler)
+
+	registerPackageRoutes(api, secured, cfg, packageHandler, publicPackageHandler)
+
+	registerCouponRoutes(secured, couponHandler)
+
+	registerLoyaltyRoutes(secured, loyaltyHandler)
+
+	registerDeliveryLogRoutes(secured, deliveryLogHandler)
+
+	registerNotificationTemplateRoutes(secured, notificationTemplateHandler)
+
+	registerClassificationRulesRoutes(secured, classificationRulesHandler)
+
+	registerCampaignRoutes(api, secured, cfg, segmentHandler, campaignHandler)
+
+	registerReportRoutes(secured, reportSubscriptionHandler)
+
 	registerAdminRoutes(secured, planHandler, dashboardHandler, financialHandler,
 		dayPanelHandler, impactHandler, subscriptionHandler, billingHandler, imageHandler)
 
@@ -2,8 +2,10 @@ package calendar
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,19 +14,26 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
 )
 
-// SyncAppointmentToGoogle cria um evento no Google Calendar do barbeiro
-// de forma assíncrona (best-effort — falhas são apenas logadas).
-// cipher é usado para descriptografar os tokens armazenados no banco;
-// nil desativa a criptografia (modo dev sem PAYMENT_CREDENTIALS_ENCRYPTION_KEY).
-func SyncAppointmentToGoogle(
-	db *gorm.DB,
-	cfg OAuthConfig,
-	cipher *crypt.Cipher,
-	barberID uint,
-	barbershopID uint,
-	ap *models.Appointment,
-) {
-	if cfg.ClientID == "" {
+// GoogleSync espelha os agendamentos no Google Calendar do barbeiro e traz
+// de volta os horários ocupados por eventos pessoais (barber_busy_periods).
+// Implementa domain/appointment.CalendarSync.
+type GoogleSync struct {
+	db     *gorm.DB
+	cfg    OAuthConfig
+	cipher *crypt.Cipher
+}
+
+// NewGoogleSync cria o sincronizador. cipher é usado para descriptografar os
+// tokens armazenados no banco; nil desativa a criptografia (modo dev sem
+// PAYMENT_CREDENTIALS_ENCRYPTION_KEY).
+func NewGoogleSync(db *gorm.DB, cfg OAuthConfig, cipher *crypt.Cipher) *GoogleSync {
+	return &GoogleSync{db: db, cfg: cfg, cipher: cipher}
+}
+
+// AppointmentChanged sincroniza o agendamento de forma assíncrona
+// (best-effort — falhas são apenas logadas).
+func (s *GoogleSync) AppointmentChanged(_ context.Context, barbershopID, appointmentID uint) {
+	if s.cfg.ClientID == "" {
 		return
 	}
 
@@ -32,46 +41,97 @@ func SyncAppointmentToGoogle(
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		if err := syncAppointment(ctx, db, cfg, cipher, barberID, barbershopID, ap); err != nil {
-			log.Printf("[GOOGLE_CAL] sync failed barber=%d appointment=%d: %v",
-				barberID, ap.ID, err)
+		if err := s.SyncAppointment(ctx, barbershopID, appointmentID); err != nil {
+			log.Printf("[GOOGLE_CAL] sync failed appointment=%d: %v", appointmentID, err)
 		}
 	}()
 }
 
-func syncAppointment(
-	ctx context.Context,
-	db *gorm.DB,
-	cfg OAuthConfig,
-	cipher *crypt.Cipher,
-	barberID uint,
-	barbershopID uint,
-	ap *models.Appointment,
-) error {
+// SyncAppointment reconcilia o evento do agendamento com o status atual:
+//   - scheduled/awaiting_payment/completed: cria o evento (se ainda não
+//     existe) ou atualiza horário e título;
+//   - no_show: atualiza o título e libera o horário na agenda;
+//   - cancelled: apaga o evento.
+//
+// Se o barbeiro apagou o evento no Google, a referência é descartada e o
+// evento volta a ser criado na próxima alteração do agendamento.
+func (s *GoogleSync) SyncAppointment(ctx context.Context, barbershopID, appointmentID uint) error {
+	var ap models.Appointment
+	err := s.db.WithContext(ctx).
+		Preload("Client").
+		Preload("BarberProduct").
+		Preload("Services", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ? AND barbershop_id = ?", appointmentID, barbershopID).
+		First(&ap).Error
+	if err != nil {
+		return fmt.Errorf("load appointment: %w", err)
+	}
+	if ap.BarberID == nil {
+		return nil
+	}
+
 	// Carrega token válido do barbeiro
 	var token models.BarberGoogleToken
-	if err := db.WithContext(ctx).Where("user_id = ?", barberID).First(&token).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("user_id = ?", *ap.BarberID).First(&token).Error; err != nil {
 		return nil // barbeiro não conectou Google — sem erro
 	}
 
-	accessToken, err := ensureValidToken(ctx, db, cfg, cipher, &token)
+	accessToken, err := ensureValidToken(ctx, s.db, s.cfg, s.cipher, &token)
 	if err != nil {
 		return fmt.Errorf("token: %w", err)
 	}
 
+	if ap.Status == models.AppointmentStatusCancelled {
+		if ap.GoogleEventID == nil {
+			return nil
+		}
+		if err := DeleteEvent(ctx, accessToken, *ap.GoogleEventID); err != nil {
+			return err
+		}
+		return s.saveEventID(ctx, ap.ID, nil)
+	}
+
 	// Carrega dados adicionais para montar o evento
-	summary, description := buildEventText(ctx, db, ap)
-
-	// Carrega timezone da barbearia
-	timezone := loadTimezone(ctx, db, barbershopID)
-
-	return CreateEvent(ctx, accessToken, EventInput{
-		Summary:     summary,
-		Description: description,
-		Start:       ap.StartTime,
-		End:         ap.EndTime,
-		Timezone:    timezone,
-	})
+	summary, description := buildEventText(ctx, s.db, &ap)
+	noShow := ap.Status == models.AppointmentStatusNoShow
+	if noShow {
+		summary = "Não compareceu · " + summary
+	}
+
+	input := EventInput{
+		Summary:       summary,
+		Description:   description,
+		Start:         ap.StartTime,
+		End:           ap.EndTime,
+		Timezone:      loadTimezone(ctx, s.db, barbershopID),
+		AppointmentID: ap.ID,
+		Free:          noShow,
+	}
+
+	if ap.GoogleEventID == nil {
+		// Só agendamentos ainda por acontecer ganham evento novo.
+		if ap.Status != models.AppointmentStatusScheduled && ap.Status != models.AppointmentStatusAwaitingPayment {
+			return nil
+		}
+		eventID, err := CreateEvent(ctx, accessToken, input)
+		if err != nil {
+			return err
+		}
+		return s.saveEventID(ctx, ap.ID, &eventID)
+	}
+
+	err = UpdateEvent(ctx, accessToken, *ap.GoogleEventID, input)
+	if errors.Is(err, ErrEventNotFound) {
+		return s.saveEventID(ctx, ap.ID, nil)
+	}
+	return err
+}
+
+func (s *GoogleSync) saveEventID(ctx context.Context, appointmentID uint, eventID *string) error {
+	return s.db.WithContext(ctx).
+		Model(&models.Appointment{}).
+		Where("id = ?", appointmentID).
+		UpdateColumn("google_event_id", eventID).Error
 }
 
 func ensureValidToken(ctx context.Context, db *gorm.DB, cfg OAuthConfig, cipher *crypt.Cipher, token *models.BarberGoogleToken) (string, error) {
@@ -155,7 +215,13 @@ func buildEventText(ctx context.Context, db *gorm.DB, ap *models.Appointment) (s
 		}
 	}
 
-	if ap.BarberProduct != nil {
+	if len(ap.Services) > 1 {
+		names := make([]string, 0, len(ap.Services))
+		for _, line := range ap.Services {
+			names = append(names, line.ServiceName)
+		}
+		serviceName = strings.Join(names, " + ")
+	} else if ap.BarberProduct != nil {
 		serviceName = ap.BarberProduct.Name
 	} else if ap.BarberProductID != nil {
 		var s models.BarbershopService
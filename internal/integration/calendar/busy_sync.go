@@ -0,0 +1,121 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+// busyLookback: a sincronização completa começa um pouco antes de agora para
+// pegar eventos em andamento.
+const busyLookback = 24 * time.Hour
+
+// SyncBusyPeriods traz, para cada barbeiro com Google conectado, os eventos
+// pessoais alterados desde o último sync (syncToken) e atualiza
+// barber_busy_periods. Eventos livres, recusados, apagados ou espelhos de
+// agendamentos não bloqueiam a agenda. Retorna quantos barbeiros foram
+// sincronizados.
+func (s *GoogleSync) SyncBusyPeriods(ctx context.Context) (int, error) {
+	if s.cfg.ClientID == "" {
+		return 0, nil
+	}
+
+	var tokens []models.BarberGoogleToken
+	if err := s.db.WithContext(ctx).Find(&tokens).Error; err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for i := range tokens {
+		if err := s.syncBarberBusy(ctx, &tokens[i]); err != nil {
+			log.Printf("[GOOGLE_CAL] busy sync failed barber=%d: %v", tokens[i].UserID, err)
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+func (s *GoogleSync) syncBarberBusy(ctx context.Context, token *models.BarberGoogleToken) error {
+	accessToken, err := ensureValidToken(ctx, s.db, s.cfg, s.cipher, token)
+	if err != nil {
+		return fmt.Errorf("token: %w", err)
+	}
+
+	loc := timezone.Location(loadTimezone(ctx, s.db, token.BarbershopID))
+	now := time.Now().UTC()
+
+	syncToken := ""
+	if token.SyncToken != nil {
+		syncToken = *token.SyncToken
+	}
+
+	changes, err := ListEventChanges(ctx, accessToken, syncToken, now.Add(-busyLookback), loc)
+	if errors.Is(err, ErrSyncTokenExpired) {
+		syncToken = ""
+		changes, err = ListEventChanges(ctx, accessToken, "", now.Add(-busyLookback), loc)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Sync completo é uma foto nova da agenda: descarta o que havia.
+		if syncToken == "" {
+			if err := tx.Where("barber_id = ?", token.UserID).
+				Delete(&models.BarberBusyPeriod{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, ev := range changes.Events {
+			if err := applyBusyChange(ctx, tx, token, ev); err != nil {
+				return err
+			}
+		}
+
+		// Períodos já encerrados não bloqueiam mais nada.
+		if err := tx.Where("barber_id = ? AND end_time < ?", token.UserID, now.Add(-busyLookback)).
+			Delete(&models.BarberBusyPeriod{}).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{"busy_synced_at": now}
+		if changes.NextSyncToken != "" {
+			updates["sync_token"] = changes.NextSyncToken
+		}
+		return tx.Model(&models.BarberGoogleToken{}).
+			Where("id = ?", token.ID).
+			Updates(updates).Error
+	})
+}
+
+func applyBusyChange(ctx context.Context, tx *gorm.DB, token *models.BarberGoogleToken, ev EventChange) error {
+	if ev.Cancelled || ev.Free || ev.AppointmentID != "" || !ev.End.After(ev.Start) {
+		return tx.WithContext(ctx).
+			Where("barber_id = ? AND external_event_id = ?", token.UserID, ev.ID).
+			Delete(&models.BarberBusyPeriod{}).Error
+	}
+
+	period := models.BarberBusyPeriod{
+		BarbershopID:    token.BarbershopID,
+		BarberID:        token.UserID,
+		ExternalEventID: ev.ID,
+		StartTime:       ev.Start,
+		EndTime:         ev.End,
+	}
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "barber_id"}, {Name: "external_event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"start_time", "end_time", "updated_at"}),
+		}).
+		Create(&period).Error
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,12 +18,18 @@ import (
 const (
 	googleAuthURL  = "https://accounts.google.com/o/oauth2/v2/auth"
 	googleTokenURL = "https://oauth2.googleapis.com/token"
-	calendarAPIURL = "https://www.googleapis.com/calendar/v3/calendars/primary/events"
 
-	// Escopo mínimo: criar/editar eventos na agenda principal do usuário.
+	// Escopo mínimo: criar/editar/ler eventos na agenda principal do usuário.
 	calendarScope = "https://www.googleapis.com/auth/calendar.events"
+
+	// appointmentProperty marca (extendedProperties.private) os eventos criados
+	// pelo sistema, para o sync de ocupados não bloquear o próprio agendamento.
+	appointmentProperty = "corteon_appointment_id"
 )
 
+// calendarAPIURL é variável para os testes apontarem para um servidor local.
+var calendarAPIURL = "https://www.googleapis.com/calendar/v3/calendars/primary/events"
+
 var httpClient = &http.Client{Timeout: 15 * time.Second}
 
 // OAuthConfig guarda as credenciais do app Google.
@@ -131,6 +138,10 @@ type EventInput struct {
 	Start       time.Time
 	End         time.Time
 	Timezone    string // ex: "America/Sao_Paulo"
+	// AppointmentID marca o evento como espelho do agendamento.
+	AppointmentID uint
+	// Free deixa o evento como "disponível" na agenda (ex: no-show).
+	Free bool
 }
 
 type calendarDateTime struct {
@@ -148,17 +159,21 @@ type calendarReminders struct {
 	Overrides  []calendarReminder `json:"overrides"`
 }
 
+type calendarExtendedProperties struct {
+	Private map[string]string `json:"private,omitempty"`
+}
+
 type calendarEventRequest struct {
-	Summary     string            `json:"summary"`
-	Description string            `json:"description,omitempty"`
-	Start       calendarDateTime  `json:"start"`
-	End         calendarDateTime  `json:"end"`
-	Reminders   calendarReminders `json:"reminders"`
+	Summary            string                      `json:"summary"`
+	Description        string                      `json:"description,omitempty"`
+	Start              calendarDateTime            `json:"start"`
+	End                calendarDateTime            `json:"end"`
+	Reminders          calendarReminders           `json:"reminders"`
+	Transparency       string                      `json:"transparency,omitempty"`
+	ExtendedProperties *calendarExtendedProperties `json:"extendedProperties,omitempty"`
 }
 
-// CreateEvent cria um evento na agenda principal do barbeiro.
-// accessToken deve ser um token válido (não expirado).
-func CreateEvent(ctx context.Context, accessToken string, input EventInput) error {
+func buildEventRequest(input EventInput) calendarEventRequest {
 	tz := input.Timezone
 	if tz == "" {
 		tz = "America/Sao_Paulo"
@@ -182,30 +197,94 @@ func CreateEvent(ctx context.Context, accessToken string, input EventInput) erro
 				{Method: "popup", Minutes: 15},
 			},
 		},
+		Transparency: "opaque",
 	}
+	if input.Free {
+		event.Transparency = "transparent"
+		event.Reminders.Overrides = []calendarReminder{}
+	}
+	if input.AppointmentID != 0 {
+		event.ExtendedProperties = &calendarExtendedProperties{
+			Private: map[string]string{appointmentProperty: fmt.Sprint(input.AppointmentID)},
+		}
+	}
+	return event
+}
 
-	body, err := json.Marshal(event)
+// CreateEvent cria um evento na agenda principal do barbeiro e devolve o ID
+// do evento no Google. accessToken deve ser um token válido (não expirado).
+func CreateEvent(ctx context.Context, accessToken string, input EventInput) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := doEventRequest(ctx, http.MethodPost, calendarAPIURL, accessToken, buildEventRequest(input), &created); err != nil {
+		return "", fmt.Errorf("google calendar create: %w", err)
+	}
+	return created.ID, nil
+}
+
+// UpdateEvent substitui os dados do evento (horário, título, transparência).
+// Retorna ErrEventNotFound quando o evento foi apagado no Google.
+func UpdateEvent(ctx context.Context, accessToken, eventID string, input EventInput) error {
+	err := doEventRequest(ctx, http.MethodPatch, eventURL(eventID), accessToken, buildEventRequest(input), nil)
 	if err != nil {
-		return fmt.Errorf("google calendar marshal: %w", err)
+		return fmt.Errorf("google calendar update: %w", err)
+	}
+	return nil
+}
+
+// DeleteEvent apaga o evento. Evento já apagado no Google não é erro.
+func DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+	err := doEventRequest(ctx, http.MethodDelete, eventURL(eventID), accessToken, nil, nil)
+	if err != nil && !errors.Is(err, ErrEventNotFound) {
+		return fmt.Errorf("google calendar delete: %w", err)
 	}
+	return nil
+}
+
+// ErrEventNotFound: o evento não existe mais no Google (404/410).
+var ErrEventNotFound = errors.New("google calendar event not found")
+
+func eventURL(eventID string) string {
+	return calendarAPIURL + "/" + url.PathEscape(eventID)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, calendarAPIURL,
-		bytes.NewReader(body))
+func doEventRequest(ctx context.Context, method, endpoint, accessToken string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("google calendar create: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return ErrEventNotFound
+	}
 	if resp.StatusCode >= 300 {
 		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("google calendar %d: %s", resp.StatusCode, string(data))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(data))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
 	}
 	return nil
 }
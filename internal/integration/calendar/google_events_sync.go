@@ -0,0 +1,173 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrSyncTokenExpired: o Google invalidou o syncToken (410 Gone) e exige uma
+// nova sincronização completa.
+var ErrSyncTokenExpired = errors.New("google calendar sync token expired")
+
+// EventChange é um evento novo, alterado ou apagado desde o último sync.
+type EventChange struct {
+	ID        string
+	Cancelled bool // apagado ou recusado
+	// Free: marcado como "disponível" — não bloqueia a agenda.
+	Free bool
+	// AppointmentID vem preenchido quando o evento é espelho de um agendamento nosso.
+	AppointmentID string
+	Start         time.Time
+	End           time.Time
+	// AllDay: Start/End são meia-noite no fuso da barbearia.
+	AllDay bool
+}
+
+// EventChanges é o resultado de ListEventChanges.
+type EventChanges struct {
+	Events        []EventChange
+	NextSyncToken string
+}
+
+type calendarEventTime struct {
+	DateTime string `json:"dateTime"`
+	Date     string `json:"date"`
+}
+
+type calendarEventItem struct {
+	ID                 string                      `json:"id"`
+	Status             string                      `json:"status"`
+	Transparency       string                      `json:"transparency"`
+	Start              calendarEventTime           `json:"start"`
+	End                calendarEventTime           `json:"end"`
+	ExtendedProperties *calendarExtendedProperties `json:"extendedProperties"`
+	Attendees          []struct {
+		Self           bool   `json:"self"`
+		ResponseStatus string `json:"responseStatus"`
+	} `json:"attendees"`
+}
+
+type calendarEventsPage struct {
+	Items         []calendarEventItem `json:"items"`
+	NextPageToken string              `json:"nextPageToken"`
+	NextSyncToken string              `json:"nextSyncToken"`
+}
+
+// ListEventChanges lista os eventos da agenda principal alterados desde
+// syncToken. Sem syncToken faz a sincronização completa a partir de timeMin.
+// Recorrências vêm expandidas em ocorrências (singleEvents). loc resolve as
+// datas de eventos de dia inteiro.
+func ListEventChanges(
+	ctx context.Context,
+	accessToken string,
+	syncToken string,
+	timeMin time.Time,
+	loc *time.Location,
+) (*EventChanges, error) {
+	out := &EventChanges{}
+	pageToken := ""
+
+	for {
+		params := url.Values{}
+		params.Set("singleEvents", "true")
+		params.Set("maxResults", "250")
+		if syncToken != "" {
+			params.Set("syncToken", syncToken)
+		} else {
+			params.Set("timeMin", timeMin.UTC().Format(time.RFC3339))
+		}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		page, err := fetchEventsPage(ctx, accessToken, calendarAPIURL+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			out.Events = append(out.Events, toEventChange(item, loc))
+		}
+
+		if page.NextPageToken == "" {
+			out.NextSyncToken = page.NextSyncToken
+			return out, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func fetchEventsPage(ctx context.Context, accessToken, endpoint string) (*calendarEventsPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google calendar list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusGone {
+		return nil, ErrSyncTokenExpired
+	}
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("google calendar list %d: %s", resp.StatusCode, string(data))
+	}
+
+	var page calendarEventsPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("google calendar list parse: %w", err)
+	}
+	return &page, nil
+}
+
+func toEventChange(item calendarEventItem, loc *time.Location) EventChange {
+	ev := EventChange{
+		ID:        item.ID,
+		Cancelled: item.Status == "cancelled",
+		Free:      item.Transparency == "transparent",
+	}
+	if item.ExtendedProperties != nil {
+		ev.AppointmentID = item.ExtendedProperties.Private[appointmentProperty]
+	}
+	for _, a := range item.Attendees {
+		if a.Self && a.ResponseStatus == "declined" {
+			ev.Cancelled = true
+		}
+	}
+	if ev.Cancelled {
+		return ev
+	}
+
+	if item.Start.DateTime != "" {
+		start, err1 := time.Parse(time.RFC3339, item.Start.DateTime)
+		end, err2 := time.Parse(time.RFC3339, item.End.DateTime)
+		if err1 != nil || err2 != nil {
+			ev.Cancelled = true // formato inesperado: não bloqueia
+			return ev
+		}
+		ev.Start, ev.End = start.UTC(), end.UTC()
+		return ev
+	}
+
+	// Dia inteiro: end.date é exclusivo (dia seguinte ao último).
+	start, err1 := time.ParseInLocation("2006-01-02", item.Start.Date, loc)
+	end, err2 := time.ParseInLocation("2006-01-02", item.End.Date, loc)
+	if err1 != nil || err2 != nil {
+		ev.Cancelled = true
+		return ev
+	}
+	ev.AllDay = true
+	ev.Start, ev.End = start.UTC(), end.UTC()
+	return ev
+}
@@ -0,0 +1,187 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useFakeCalendar aponta calendarAPIURL para o servidor de teste.
+func useFakeCalendar(t *testing.T, h http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	prev := calendarAPIURL
+	calendarAPIURL = srv.URL + "/events"
+	t.Cleanup(func() {
+		calendarAPIURL = prev
+		srv.Close()
+	})
+}
+
+func TestCreateEvent_ReturnsIDAndTagsAppointment(t *testing.T) {
+	var got calendarEventRequest
+	useFakeCalendar(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("método = %s, esperado POST", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("body inválido: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "evt_1"})
+	})
+
+	start := time.Date(2030, 1, 7, 13, 0, 0, 0, time.UTC)
+	id, err := CreateEvent(context.Background(), "tok", EventInput{
+		Summary:       "Corte · João",
+		Start:         start,
+		End:           start.Add(time.Hour),
+		Timezone:      "America/Sao_Paulo",
+		AppointmentID: 42,
+	})
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if id != "evt_1" {
+		t.Errorf("id = %q, esperado evt_1", id)
+	}
+	if got.ExtendedProperties == nil || got.ExtendedProperties.Private[appointmentProperty] != "42" {
+		t.Errorf("evento sem marcação do agendamento: %+v", got.ExtendedProperties)
+	}
+	if got.Transparency != "opaque" {
+		t.Errorf("transparency = %q, esperado opaque", got.Transparency)
+	}
+}
+
+func TestUpdateEvent_DeletedInGoogle(t *testing.T) {
+	useFakeCalendar(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+
+	err := UpdateEvent(context.Background(), "tok", "evt_1", EventInput{})
+	if !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("esperado ErrEventNotFound, obtido: %v", err)
+	}
+	// Apagar evento que não existe mais não é erro.
+	if err := DeleteEvent(context.Background(), "tok", "evt_1"); err != nil {
+		t.Errorf("DeleteEvent: inesperado erro: %v", err)
+	}
+}
+
+func TestListEventChanges(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+
+	t.Run("pagina e devolve o nextSyncToken da última página", func(t *testing.T) {
+		useFakeCalendar(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("syncToken") != "sync_old" {
+				t.Errorf("syncToken = %q, esperado sync_old", q.Get("syncToken"))
+			}
+			if q.Get("timeMin") != "" {
+				t.Error("timeMin não pode acompanhar syncToken")
+			}
+			if q.Get("pageToken") == "" {
+				_, _ = w.Write([]byte(`{
+					"items": [{
+						"id": "pessoal",
+						"status": "confirmed",
+						"start": {"dateTime": "2030-01-07T10:00:00-03:00"},
+						"end":   {"dateTime": "2030-01-07T11:30:00-03:00"}
+					}],
+					"nextPageToken": "p2"
+				}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{
+				"items": [
+					{"id": "apagado", "status": "cancelled"},
+					{
+						"id": "espelho",
+						"status": "confirmed",
+						"start": {"dateTime": "2030-01-07T14:00:00-03:00"},
+						"end":   {"dateTime": "2030-01-07T15:00:00-03:00"},
+						"extendedProperties": {"private": {"corteon_appointment_id": "42"}}
+					},
+					{
+						"id": "ferias",
+						"status": "confirmed",
+						"start": {"date": "2030-01-08"},
+						"end":   {"date": "2030-01-09"}
+					},
+					{
+						"id": "recusado",
+						"status": "confirmed",
+						"start": {"dateTime": "2030-01-07T16:00:00-03:00"},
+						"end":   {"dateTime": "2030-01-07T17:00:00-03:00"},
+						"attendees": [{"self": true, "responseStatus": "declined"}]
+					}
+				],
+				"nextSyncToken": "sync_new"
+			}`))
+		})
+
+		changes, err := ListEventChanges(context.Background(), "tok", "sync_old", time.Time{}, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if changes.NextSyncToken != "sync_new" {
+			t.Errorf("NextSyncToken = %q, esperado sync_new", changes.NextSyncToken)
+		}
+		if len(changes.Events) != 5 {
+			t.Fatalf("esperado 5 eventos, obtido %d", len(changes.Events))
+		}
+
+		personal := changes.Events[0]
+		wantStart := time.Date(2030, 1, 7, 13, 0, 0, 0, time.UTC)
+		if personal.Cancelled || !personal.Start.Equal(wantStart) || !personal.End.Equal(wantStart.Add(90*time.Minute)) {
+			t.Errorf("evento pessoal inesperado: %+v", personal)
+		}
+		if !changes.Events[1].Cancelled {
+			t.Error("evento apagado deveria vir como cancelado")
+		}
+		if changes.Events[2].AppointmentID != "42" {
+			t.Errorf("espelho deveria trazer o agendamento, obtido %q", changes.Events[2].AppointmentID)
+		}
+
+		allDay := changes.Events[3]
+		wantDay := time.Date(2030, 1, 8, 0, 0, 0, 0, loc)
+		if !allDay.AllDay || !allDay.Start.Equal(wantDay) || !allDay.End.Equal(wantDay.AddDate(0, 0, 1)) {
+			t.Errorf("evento de dia inteiro inesperado: %+v", allDay)
+		}
+		if !changes.Events[4].Cancelled {
+			t.Error("convite recusado deveria vir como cancelado")
+		}
+	})
+
+	t.Run("sync completo usa timeMin", func(t *testing.T) {
+		timeMin := time.Date(2030, 1, 6, 12, 0, 0, 0, time.UTC)
+		useFakeCalendar(t, func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("timeMin"); got != "2030-01-06T12:00:00Z" {
+				t.Errorf("timeMin = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"items": [], "nextSyncToken": "s1"}`))
+		})
+
+		changes, err := ListEventChanges(context.Background(), "tok", "", timeMin, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if changes.NextSyncToken != "s1" || len(changes.Events) != 0 {
+			t.Errorf("resultado inesperado: %+v", changes)
+		}
+	})
+
+	t.Run("syncToken inválido retorna ErrSyncTokenExpired", func(t *testing.T) {
+		useFakeCalendar(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		})
+
+		_, err := ListEventChanges(context.Background(), "tok", "sync_old", time.Time{}, loc)
+		if !errors.Is(err, ErrSyncTokenExpired) {
+			t.Errorf("esperado ErrSyncTokenExpired, obtido: %v", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+// Status de VEVENT aceitos no feed (RFC 5545 §3.8.1.11).
+const (
+	FeedStatusConfirmed = "CONFIRMED"
+	FeedStatusTentative = "TENTATIVE"
+	FeedStatusCancelled = "CANCELLED"
+)
+
+// FeedEvent é um VEVENT do feed assinável.
+type FeedEvent struct {
+	// UID estável: o mesmo agendamento é sempre o mesmo evento no cliente.
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// Stamp é a última alteração do agendamento, não a hora da requisição:
+	// o corpo do feed só muda quando os dados mudam (ETag estável).
+	Stamp    time.Time
+	Sequence int
+	Status   string
+}
+
+// BuildFeed monta um VCALENDAR com vários eventos para assinatura
+// (Apple Calendar, Outlook). Diferente do ICSGenerator, não tem METHOD:
+// o feed é publicado, não enviado como convite.
+func BuildFeed(name string, events []FeedEvent) []byte {
+	var b strings.Builder
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//CorteOn//Agenda//PT-BR")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "X-WR-CALNAME:"+escapeICSText(name))
+	// Sugere ao cliente atualizar a cada 15 minutos.
+	writeICSLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT15M")
+	writeICSLine(&b, "X-PUBLISHED-TTL:PT15M")
+
+	for _, ev := range events {
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+ev.UID)
+		writeICSLine(&b, "DTSTAMP:"+ev.Stamp.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "DTSTART:"+ev.Start.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "DTEND:"+ev.End.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "SEQUENCE:"+strconv.Itoa(ev.Sequence))
+		writeICSLine(&b, "STATUS:"+ev.Status)
+		writeICSLine(&b, "SUMMARY:"+escapeICSText(ev.Summary))
+		if ev.Description != "" {
+			writeICSLine(&b, "DESCRIPTION:"+escapeICSText(ev.Description))
+		}
+		if ev.Status == FeedStatusCancelled {
+			writeICSLine(&b, "TRANSP:TRANSPARENT")
+		}
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+// escapeICSText escapa valores TEXT (RFC 5545 §3.3.11).
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeICSLine dobra linhas acima de 75 octetos (RFC 5545 §3.1) sem cortar
+// caracteres UTF-8 no meio. A continuação começa com espaço, que conta no limite.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
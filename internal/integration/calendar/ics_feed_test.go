@@ -0,0 +1,43 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestBuildFeed_EscapesAndFoldsLines(t *testing.T) {
+	start := time.Date(2030, 1, 7, 13, 0, 0, 0, time.UTC)
+	body := string(BuildFeed("Agenda", []FeedEvent{{
+		UID:         "appointment-1@corteon",
+		Summary:     "Corte, barba; sobrancelha",
+		Description: strings.Repeat("Observação longa ", 10) + "\nsegunda linha",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Stamp:       start,
+		Status:      FeedStatusConfirmed,
+	}}))
+
+	if !strings.Contains(body, `SUMMARY:Corte\, barba\; sobrancelha`+"\r\n") {
+		t.Errorf("SUMMARY sem escape:\n%s", body)
+	}
+	if strings.Contains(body, "METHOD:") {
+		t.Error("feed publicado não deveria ter METHOD")
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("linha com %d octetos: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("dobra cortou caractere UTF-8: %q", line)
+		}
+	}
+
+	// Desdobrar devolve o texto original escapado.
+	unfolded := strings.ReplaceAll(body, "\r\n ", "")
+	if !strings.Contains(unfolded, `\nsegunda linha`) {
+		t.Errorf("quebra de linha da descrição deveria virar \\n:\n%s", unfolded)
+	}
+}
@@ -0,0 +1,87 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+)
+
+// BuildMIME monta a mensagem completa (cabeçalhos e corpo) para SMTP:
+// texto e HTML como multipart/alternative e anexos em base64 num
+// multipart/mixed.
+func BuildMIME(from string, msg Message) []byte {
+	var b bytes.Buffer
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	contentType, body := bodyPart(msg)
+	if len(msg.Attachments) == 0 {
+		b.WriteString("Content-Type: " + contentType + "\r\n\r\n")
+		b.Write(body)
+		return b.Bytes()
+	}
+
+	mixed := multipart.NewWriter(&b)
+	b.WriteString("Content-Type: multipart/mixed; boundary=" + mixed.Boundary() + "\r\n\r\n")
+
+	part, _ := mixed.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	part.Write(body)
+
+	for _, a := range msg.Attachments {
+		ct := mime.TypeByExtension(filepath.Ext(a.Filename))
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		part, _ := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {ct},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		writeBase64(part, a.Content)
+	}
+	mixed.Close()
+
+	return b.Bytes()
+}
+
+// bodyPart devolve o Content-Type e o conteúdo do corpo: texto puro ou,
+// com HTML, as duas alternativas.
+func bodyPart(msg Message) (string, []byte) {
+	if msg.HTML == "" {
+		return "text/plain; charset=UTF-8", []byte(msg.Body + "\r\n")
+	}
+
+	text := msg.Body
+	if text == "" {
+		text = "Este e-mail requer um cliente com suporte a HTML."
+	}
+
+	var b bytes.Buffer
+	alt := multipart.NewWriter(&b)
+	for _, p := range []struct{ ct, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		part, _ := alt.CreatePart(textproto.MIMEHeader{"Content-Type": {p.ct}})
+		part.Write([]byte(p.body + "\r\n"))
+	}
+	alt.Close()
+
+	return "multipart/alternative; boundary=" + alt.Boundary(), b.Bytes()
+}
+
+// writeBase64 quebra o base64 em linhas de 76 caracteres (RFC 2045).
+func writeBase64(w io.Writer, content []byte) {
+	enc := base64.StdEncoding.EncodeToString(content)
+	for len(enc) > 76 {
+		io.WriteString(w, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	io.WriteString(w, enc+"\r\n")
+}
@@ -0,0 +1,77 @@
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildMIMEWithAttachment(t *testing.T) {
+	raw := BuildMIME("Corteon <no-reply@corteon.test>", Message{
+		To:      "dono@barbearia.test",
+		Subject: "Relatório semanal — março",
+		Body:    "resumo",
+		HTML:    "<p>resumo</p>",
+		Attachments: []Attachment{
+			{Filename: "relatorio.csv", Content: []byte("a,b\n1,2\n")},
+		},
+	})
+
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != "Relatório semanal — março" {
+		t.Errorf("assunto %q %v", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("content-type %q %v", mediaType, err)
+	}
+	r := multipart.NewReader(m.Body, params["boundary"])
+
+	body, err := r.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := body.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/alternative") {
+		t.Errorf("corpo deveria ter as alternativas, obtido %q", ct)
+	}
+	content, _ := io.ReadAll(body)
+	if !bytes.Contains(content, []byte("<p>resumo</p>")) || !bytes.Contains(content, []byte("text/plain")) {
+		t.Errorf("corpo sem texto e HTML: %s", content)
+	}
+
+	att, err := r.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if att.FileName() != "relatorio.csv" {
+		t.Errorf("nome do anexo %q", att.FileName())
+	}
+	if _, err := r.NextPart(); err != io.EOF {
+		t.Errorf("deveria haver só um anexo: %v", err)
+	}
+}
+
+func TestBuildMIMEPlainText(t *testing.T) {
+	raw := BuildMIME("a@b.test", Message{To: "c@d.test", Subject: "Oi", Body: "texto"})
+
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := m.Header.Get("Content-Type"); ct != "text/plain; charset=UTF-8" {
+		t.Errorf("content-type %q", ct)
+	}
+	body, _ := io.ReadAll(m.Body)
+	if strings.TrimSpace(string(body)) != "texto" {
+		t.Errorf("corpo %q", body)
+	}
+}
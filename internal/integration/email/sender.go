@@ -7,10 +7,13 @@ type Attachment struct {
 	Content  []byte
 }
 
+// Message é o email a enviar. Body é o texto puro; com HTML preenchido, os
+// dois vão como alternativas e o cliente de email escolhe.
 type Message struct {
 	To          string
 	Subject     string
 	Body        string
+	HTML        string
 	Attachments []Attachment
 }
 
@@ -30,18 +30,11 @@ func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
 	auth := smtp.PlainAuth("", s.user, s.pass, s.host)
 
-	body := fmt.Sprintf(
-		"To: %s\r\nSubject: %s\r\n\r\n%s",
-		msg.To,
-		msg.Subject,
-		msg.Body,
-	)
-
 	return smtp.SendMail(
 		addr,
 		auth,
 		s.from,
 		[]string{msg.To},
-		[]byte(body),
+		BuildMIME(s.from, msg),
 	)
 }
@@ -3,13 +3,17 @@ package mp
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/mercadopago/sdk-go/pkg/config"
+	"github.com/mercadopago/sdk-go/pkg/customer"
+	"github.com/mercadopago/sdk-go/pkg/customercard"
 	"github.com/mercadopago/sdk-go/pkg/payment"
 	"github.com/mercadopago/sdk-go/pkg/preference"
+	"github.com/mercadopago/sdk-go/pkg/refund"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
 )
@@ -18,6 +22,11 @@ import (
 type Gateway struct {
 	preferenceClient preference.Client
 	paymentClient    payment.Client
+	refundClient     refund.Client
+	customerClient   customer.Client
+	cardClient       customercard.Client
+	// accessToken é usado nas chamadas sem cliente no SDK (token de cartão guardado).
+	accessToken string
 }
 
 // New cria o gateway MP com o access token fornecido.
@@ -29,6 +38,10 @@ func New(accessToken string) (*Gateway, error) {
 	return &Gateway{
 		preferenceClient: preference.NewClient(cfg),
 		paymentClient:    payment.NewClient(cfg),
+		refundClient:     refund.NewClient(cfg),
+		customerClient:   customer.NewClient(cfg),
+		cardClient:       customercard.NewClient(cfg),
+		accessToken:      accessToken,
 	}, nil
 }
 
@@ -254,6 +267,30 @@ func (g *Gateway) GetPaymentStatus(ctx context.Context, providerPaymentID string
 	return mapMPStatus(resp.Status), nil
 }
 
+// RefundPayment implementa domain.PaymentGateway.
+// Sem AmountCents o MP estorna o saldo restante do pagamento.
+func (g *Gateway) RefundPayment(ctx context.Context, input domain.RefundInput) (*domain.RefundResult, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(input.ProviderPaymentID, "mp_pay:"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("mp: invalid payment id %q: %w", input.ProviderPaymentID, err)
+	}
+
+	var resp *refund.Response
+	if input.AmountCents > 0 {
+		resp, err = g.refundClient.CreatePartialRefund(ctx, int(id), float64(input.AmountCents)/100)
+	} else {
+		resp, err = g.refundClient.Create(ctx, int(id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mp create refund: %w", err)
+	}
+
+	return &domain.RefundResult{
+		ProviderRefundID: strconv.Itoa(resp.ID),
+		AmountCents:      int64(math.Round(resp.Amount * 100)),
+	}, nil
+}
+
 // ProviderName retorna o identificador do provider gravado em payments.provider.
 // Usado para associar um payment ao seu gateway de origem e permitir polling correto.
 func (g *Gateway) ProviderName() string {
@@ -271,6 +308,10 @@ func mapMPStatus(s string) domain.ProviderPaymentStatus {
 		return domain.ProviderStatusCancelled
 	case "in_process", "authorized":
 		return domain.ProviderStatusInProcess
+	case "refunded":
+		return domain.ProviderStatusRefunded
+	case "charged_back":
+		return domain.ProviderStatusChargedBack
 	default:
 		return domain.ProviderStatusPending
 	}
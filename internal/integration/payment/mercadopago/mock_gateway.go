@@ -89,6 +89,35 @@ func (g *MockGateway) GetPaymentStatus(_ context.Context, _ string) (domain.Prov
 	return domain.ProviderStatusPending, nil
 }
 
+// RefundPayment simula um estorno aprovado. Estorno total sem valor informado
+// retorna AmountCents zerado — quem chama já conhece o saldo restante.
+func (g *MockGateway) RefundPayment(_ context.Context, input domain.RefundInput) (*domain.RefundResult, error) {
+	return &domain.RefundResult{
+		ProviderRefundID: fmt.Sprintf("mock-refund-%d", time.Now().UnixNano()),
+		AmountCents:      input.AmountCents,
+	}, nil
+}
+
+// StoreCard simula o cofre: devolve referências fictícias do cartão.
+func (g *MockGateway) StoreCard(_ context.Context, input domain.StoreCardInput) (*domain.StoredCard, error) {
+	fakeID := time.Now().UnixNano()
+	return &domain.StoredCard{
+		CustomerRef: fmt.Sprintf("mock-customer-%d", fakeID),
+		CardRef:     fmt.Sprintf("mock-card-%d", fakeID),
+		Brand:       input.PaymentMethodID,
+		LastFour:    "0000",
+	}, nil
+}
+
+// ChargeStoredCard simula a cobrança do cartão guardado aprovada na hora.
+func (g *MockGateway) ChargeStoredCard(_ context.Context, _ domain.StoredCardChargeInput) (*domain.CardPaymentResult, error) {
+	return &domain.CardPaymentResult{
+		ProviderPaymentID: strconv.FormatInt(time.Now().UnixNano(), 10),
+		Status:            domain.ProviderStatusApproved,
+		StatusDetail:      "accredited",
+	}, nil
+}
+
 // ProviderName retorna o identificador do provider para o mock (mesmo valor do gateway real).
 func (g *MockGateway) ProviderName() string {
 	return "mercadopago"
@@ -0,0 +1,159 @@
+package mp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mercadopago/sdk-go/pkg/customer"
+	"github.com/mercadopago/sdk-go/pkg/customercard"
+	"github.com/mercadopago/sdk-go/pkg/payment"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+)
+
+const cardTokensURL = "https://api.mercadopago.com/v1/card_tokens"
+
+var httpClient = &http.Client{Timeout: 20 * time.Second}
+
+// StoreCard implementa domain.RecurringGateway: associa o cartão do pagamento
+// aprovado a um customer do MP (criado pelo e-mail na primeira vez). O cartão
+// guardado é referenciado por customer_id + card_id.
+func (g *Gateway) StoreCard(ctx context.Context, input domain.StoreCardInput) (*domain.StoredCard, error) {
+	if input.CardToken == "" || input.PayerEmail == "" {
+		return nil, fmt.Errorf("mp: token e e-mail do pagador obrigatórios")
+	}
+
+	customerID, err := g.findOrCreateCustomer(ctx, input.PayerEmail, input.PayerCPF)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := g.cardClient.Create(ctx, customerID, customercard.Request{
+		Token:           input.CardToken,
+		PaymentMethodID: input.PaymentMethodID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mp store card: %w", err)
+	}
+
+	brand := card.PaymentMethod.ID
+	if brand == "" {
+		brand = input.PaymentMethodID
+	}
+	return &domain.StoredCard{
+		CustomerRef: customerID,
+		CardRef:     card.ID,
+		Brand:       brand,
+		LastFour:    card.LastFourDigits,
+	}, nil
+}
+
+// ChargeStoredCard implementa domain.RecurringGateway: gera um token a partir
+// do cartão guardado e cobra à vista em nome do customer.
+func (g *Gateway) ChargeStoredCard(ctx context.Context, input domain.StoredCardChargeInput) (*domain.CardPaymentResult, error) {
+	token, err := g.storedCardToken(ctx, input.Card)
+	if err != nil {
+		return nil, err
+	}
+
+	req := payment.Request{
+		TransactionAmount: float64(input.AmountCents) / 100,
+		Description:       input.Description,
+		ExternalReference: input.ExternalReference,
+		PaymentMethodID:   input.Card.Brand,
+		Token:             token,
+		Installments:      1,
+		Payer: &payment.PayerRequest{
+			Type:  "customer",
+			ID:    input.Card.CustomerRef,
+			Email: input.PayerEmail,
+		},
+	}
+	if isPublicURL(input.NotificationURL) {
+		req.NotificationURL = input.NotificationURL
+	}
+
+	resp, err := g.paymentClient.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("mp charge stored card: %w", err)
+	}
+
+	return &domain.CardPaymentResult{
+		ProviderPaymentID: strconv.FormatInt(int64(resp.ID), 10),
+		Status:            mapMPStatus(resp.Status),
+		StatusDetail:      resp.StatusDetail,
+	}, nil
+}
+
+func (g *Gateway) findOrCreateCustomer(ctx context.Context, email, cpf string) (string, error) {
+	found, err := g.customerClient.Search(ctx, customer.SearchRequest{
+		Limit:   1,
+		Filters: map[string]string{"email": email},
+	})
+	if err != nil {
+		return "", fmt.Errorf("mp search customer: %w", err)
+	}
+	if found != nil && len(found.Results) > 0 {
+		return found.Results[0].ID, nil
+	}
+
+	req := customer.Request{Email: email}
+	if cpf != "" {
+		req.Identification = &customer.IdentificationRequest{Type: "CPF", Number: cpf}
+	}
+	created, err := g.customerClient.Create(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("mp create customer: %w", err)
+	}
+	return created.ID, nil
+}
+
+// storedCardToken gera o token de uso único do cartão guardado. O SDK não
+// expõe card_id no cardtoken.Request, por isso a chamada é feita direto.
+func (g *Gateway) storedCardToken(ctx context.Context, card domain.StoredCard) (string, error) {
+	raw, err := json.Marshal(map[string]string{
+		"card_id":     card.CardRef,
+		"customer_id": card.CustomerRef,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cardTokensURL, bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+g.accessToken)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("mp card token http: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("mp card token read body: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("mp card token error %d: %s", resp.StatusCode, string(data))
+	}
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", fmt.Errorf("mp card token unmarshal: %w", err)
+	}
+	if out.ID == "" {
+		return "", fmt.Errorf("mp: resposta sem token do cartão")
+	}
+	return out.ID, nil
+}
@@ -210,6 +210,58 @@ func (g *Gateway) GetPaymentStatus(ctx context.Context, providerPaymentID string
 	return domain.ProviderStatusPending, nil
 }
 
+// RefundPayment implementa domain.PaymentGateway.
+// O estorno no PagBank é feito na cobrança (CHAR_). Para PIX gravamos o ID do
+// QR code, então a cobrança paga é localizada pelo pedido antes do cancelamento.
+func (g *Gateway) RefundPayment(ctx context.Context, input domain.RefundInput) (*domain.RefundResult, error) {
+	charge, err := g.paidCharge(ctx, input.ProviderPaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := input.AmountCents
+	if amount <= 0 {
+		amount = charge.Amount.Summary.Paid - charge.Amount.Summary.Refunded
+		if amount <= 0 {
+			amount = charge.Amount.Value
+		}
+	}
+
+	var resp chargeResponse
+	req := cancelChargeRequest{Amount: chargeAmount{Value: amount}}
+	if err := g.post(ctx, "/charges/"+charge.ID+"/cancel", req, &resp); err != nil {
+		return nil, fmt.Errorf("pagbank refund charge: %w", err)
+	}
+
+	// PagBank não gera ID próprio para o estorno — o ID da cobrança identifica a operação.
+	return &domain.RefundResult{
+		ProviderRefundID: charge.ID,
+		AmountCents:      amount,
+	}, nil
+}
+
+// paidCharge resolve a cobrança por trás de um ID de cobrança, pedido ou QR code.
+func (g *Gateway) paidCharge(ctx context.Context, providerPaymentID string) (*chargeResponse, error) {
+	if strings.HasPrefix(providerPaymentID, "CHAR_") {
+		var charge chargeResponse
+		if err := g.get(ctx, "/charges/"+providerPaymentID, &charge); err != nil {
+			return nil, fmt.Errorf("pagbank get charge: %w", err)
+		}
+		return &charge, nil
+	}
+
+	var order orderResponse
+	if err := g.get(ctx, "/orders/"+providerPaymentID, &order); err != nil {
+		return nil, fmt.Errorf("pagbank get order: %w", err)
+	}
+	for i := range order.Charges {
+		if order.Charges[i].Status == "PAID" {
+			return &order.Charges[i], nil
+		}
+	}
+	return nil, fmt.Errorf("pagbank: nenhuma cobrança paga para %s", providerPaymentID)
+}
+
 // ── domain.TransparentGateway (interface antiga — compatibilidade) ─────────────
 
 // CreatePayment implementa domain.TransparentGateway para compatibilidade com
@@ -2,6 +2,9 @@ package pagbank
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
@@ -45,3 +48,147 @@ func TestGateway_ImplementsProviderNamer(t *testing.T) {
 	type providerNamer interface{ ProviderName() string }
 	var _ providerNamer = (*Gateway)(nil)
 }
+
+// TestGateway_ImplementsPaymentGateway garante que *Gateway satisfaz a interface
+// completa domain.PaymentGateway, incluindo RefundPayment.
+func TestGateway_ImplementsPaymentGateway(t *testing.T) {
+	var _ domain.PaymentGateway = (*Gateway)(nil)
+}
+
+// TestGateway_RefundPayment_PixResolveCharge valida que o estorno de um PIX
+// (ID de QR code) localiza a cobrança paga do pedido e a cancela com o valor pedido.
+func TestGateway_RefundPayment_PixResolveCharge(t *testing.T) {
+	var cancelPath string
+	var cancelBody cancelChargeRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/orders/QRC_1":
+			_ = json.NewEncoder(w).Encode(orderResponse{
+				ID:      "ORDE_1",
+				Charges: []chargeResponse{{ID: "CHAR_1", Status: "PAID"}},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/charges/CHAR_1/cancel":
+			cancelPath = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&cancelBody)
+			_ = json.NewEncoder(w).Encode(chargeResponse{ID: "CHAR_1", Status: "PAID"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	g := &Gateway{accessToken: "tok", baseURL: srv.URL}
+	res, err := g.RefundPayment(context.Background(), domain.RefundInput{
+		ProviderPaymentID: "QRC_1",
+		AmountCents:       1500,
+	})
+	if err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if cancelPath == "" {
+		t.Fatal("cobrança não foi cancelada")
+	}
+	if cancelBody.Amount.Value != 1500 {
+		t.Errorf("amount enviado = %d, want 1500", cancelBody.Amount.Value)
+	}
+	if res.ProviderRefundID != "CHAR_1" || res.AmountCents != 1500 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+// TestGateway_RefundPayment_TotalUsaSaldoDaCharge valida que sem valor o estorno
+// devolve o saldo ainda não estornado da cobrança.
+func TestGateway_RefundPayment_TotalUsaSaldoDaCharge(t *testing.T) {
+	var cancelBody cancelChargeRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/charges/CHAR_2":
+			charge := chargeResponse{ID: "CHAR_2", Status: "PAID"}
+			charge.Amount.Value = 5000
+			charge.Amount.Summary = chargeAmountSummary{Total: 5000, Paid: 5000, Refunded: 1000}
+			_ = json.NewEncoder(w).Encode(charge)
+		case r.Method == http.MethodPost && r.URL.Path == "/charges/CHAR_2/cancel":
+			_ = json.NewDecoder(r.Body).Decode(&cancelBody)
+			_ = json.NewEncoder(w).Encode(chargeResponse{ID: "CHAR_2", Status: "CANCELED"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	g := &Gateway{accessToken: "tok", baseURL: srv.URL}
+	res, err := g.RefundPayment(context.Background(), domain.RefundInput{ProviderPaymentID: "CHAR_2"})
+	if err != nil {
+		t.Fatalf("RefundPayment: %v", err)
+	}
+	if cancelBody.Amount.Value != 4000 || res.AmountCents != 4000 {
+		t.Errorf("amount = %d / %d, want 4000", cancelBody.Amount.Value, res.AmountCents)
+	}
+}
+
+// TestGateway_ImplementsRecurringGateway garante que *Gateway pode guardar o
+// cartão para a renovação automática de assinaturas.
+func TestGateway_ImplementsRecurringGateway(t *testing.T) {
+	var _ domain.RecurringGateway = (*Gateway)(nil)
+}
+
+// TestGateway_StoredCard_GuardaECobraPeloID valida o ciclo da renovação: o
+// cartão criptografado vira um CARD_ no cofre e a cobrança seguinte envia só
+// o ID, sem o cartão criptografado.
+func TestGateway_StoredCard_GuardaECobraPeloID(t *testing.T) {
+	var stored storeCardRequest
+	var order cardOrderRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/tokens/cards":
+			_ = json.NewDecoder(r.Body).Decode(&stored)
+			_ = json.NewEncoder(w).Encode(storedCardResponse{ID: "CARD_1", Brand: "VISA", LastDigits: "1111"})
+		case r.Method == http.MethodPost && r.URL.Path == "/orders":
+			_ = json.NewDecoder(r.Body).Decode(&order)
+			_ = json.NewEncoder(w).Encode(orderResponse{
+				ID:      "ORDE_1",
+				Charges: []chargeResponse{{ID: "CHAR_1", Status: "DECLINED"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	g := &Gateway{accessToken: "tok", baseURL: srv.URL}
+	card, err := g.StoreCard(context.Background(), domain.StoreCardInput{CardToken: "enc-123"})
+	if err != nil {
+		t.Fatalf("StoreCard: %v", err)
+	}
+	if stored.Encrypted != "enc-123" {
+		t.Errorf("encrypted enviado = %q", stored.Encrypted)
+	}
+	if card.CardRef != "CARD_1" || card.Brand != "visa" || card.LastFour != "1111" {
+		t.Errorf("card = %+v", card)
+	}
+
+	res, err := g.ChargeStoredCard(context.Background(), domain.StoredCardChargeInput{
+		AmountCents:       4990,
+		ExternalReference: "42",
+		Card:              *card,
+	})
+	if err != nil {
+		t.Fatalf("ChargeStoredCard: %v", err)
+	}
+	if len(order.Charges) != 1 {
+		t.Fatalf("charges = %d, want 1", len(order.Charges))
+	}
+	pm := order.Charges[0].PaymentMethod
+	if pm.Card.ID != "CARD_1" || pm.Card.Encrypted != "" {
+		t.Errorf("card enviado = %+v", pm.Card)
+	}
+	if order.Charges[0].Amount.Value != 4990 || order.ReferenceID != "42" {
+		t.Errorf("order = %+v", order)
+	}
+	if res.Status != domain.ProviderStatusRejected || res.ProviderPaymentID != "CHAR_1" {
+		t.Errorf("result = %+v", res)
+	}
+}
@@ -0,0 +1,92 @@
+package pagbank
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+)
+
+// StoreCard implementa domain.RecurringGateway: guarda no cofre do PagBank o
+// cartão criptografado pelo SDK JS e devolve o ID CARD_, que substitui o
+// cartão nas cobranças seguintes.
+func (g *Gateway) StoreCard(ctx context.Context, input domain.StoreCardInput) (*domain.StoredCard, error) {
+	if input.CardToken == "" {
+		return nil, fmt.Errorf("pagbank: cartão criptografado obrigatório")
+	}
+
+	var resp storedCardResponse
+	if err := g.post(ctx, "/tokens/cards", storeCardRequest{Encrypted: input.CardToken}, &resp); err != nil {
+		return nil, fmt.Errorf("pagbank store card: %w", err)
+	}
+	if resp.ID == "" {
+		return nil, fmt.Errorf("pagbank: resposta sem id do cartão")
+	}
+
+	brand := strings.ToLower(resp.Brand)
+	if brand == "" {
+		brand = input.PaymentMethodID
+	}
+	return &domain.StoredCard{
+		CardRef:  resp.ID,
+		Brand:    brand,
+		LastFour: resp.LastDigits,
+	}, nil
+}
+
+// ChargeStoredCard implementa domain.RecurringGateway: cobra à vista o cartão
+// guardado, sem o cliente presente.
+func (g *Gateway) ChargeStoredCard(ctx context.Context, input domain.StoredCardChargeInput) (*domain.CardPaymentResult, error) {
+	taxID := strings.ReplaceAll(input.PayerCPF, ".", "")
+
+	req := cardOrderRequest{
+		ReferenceID: input.ExternalReference,
+		Customer: orderCustomer{
+			Name:  input.PayerEmail,
+			Email: input.PayerEmail,
+			TaxID: taxID,
+		},
+		Items: []orderItem{
+			{
+				Name:       input.Description,
+				Quantity:   1,
+				UnitAmount: input.AmountCents,
+			},
+		},
+		Charges: []orderCharge{
+			{
+				ReferenceID: input.ExternalReference,
+				Amount:      chargeAmount{Value: input.AmountCents},
+				PaymentMethod: paymentMethod{
+					Type:         "CREDIT_CARD",
+					Installments: 1,
+					Capture:      true,
+					Card:         cardData{ID: input.Card.CardRef},
+					Holder: cardHolder{
+						Name:  input.PayerEmail,
+						TaxID: taxID,
+					},
+				},
+			},
+		},
+	}
+	if input.NotificationURL != "" {
+		req.NotificationURLs = []string{input.NotificationURL}
+	}
+
+	var resp orderResponse
+	if err := g.post(ctx, "/orders", req, &resp); err != nil {
+		return nil, fmt.Errorf("pagbank charge stored card: %w", err)
+	}
+	if len(resp.Charges) == 0 {
+		return nil, fmt.Errorf("pagbank: resposta sem charge")
+	}
+
+	charge := resp.Charges[0]
+	return &domain.CardPaymentResult{
+		ProviderPaymentID: charge.ID,
+		Status:            domain.ProviderPaymentStatus(mapStatus(charge.Status)),
+		StatusDetail:      charge.PaymentResponse.Message,
+	}, nil
+}
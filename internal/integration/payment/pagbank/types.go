@@ -28,7 +28,19 @@ type chargeAmount struct {
 }
 
 type cardData struct {
-	Encrypted string `json:"encrypted"` // token do SDK JS do PagBank
+	Encrypted string `json:"encrypted,omitempty"` // token do SDK JS do PagBank
+	ID        string `json:"id,omitempty"`        // cartão guardado (CARD_), na renovação
+}
+
+// storeCardRequest guarda o cartão no cofre do PagBank (POST /tokens/cards).
+type storeCardRequest struct {
+	Encrypted string `json:"encrypted"`
+}
+
+type storedCardResponse struct {
+	ID         string `json:"id"` // CARD_XXXXX
+	Brand      string `json:"brand"`
+	LastDigits string `json:"last_digits"`
 }
 
 type cardHolder struct {
@@ -68,6 +80,12 @@ type cardOrderRequest struct {
 	NotificationURLs []string      `json:"notification_urls,omitempty"`
 }
 
+// cancelChargeRequest devolve amount.value centavos de uma cobrança paga.
+// PagBank chama estorno de "cancelamento" — total ou parcial.
+type cancelChargeRequest struct {
+	Amount chargeAmount `json:"amount"`
+}
+
 // ── Responses ──────────────────────────────────────────────────────────────────
 
 type qrCodeLink struct {
@@ -88,10 +106,23 @@ type chargePaymentResponse struct {
 	Message string `json:"message"`
 }
 
+// chargeAmountSummary detalha quanto da cobrança foi pago e quanto já foi devolvido.
+type chargeAmountSummary struct {
+	Total    int64 `json:"total"`
+	Paid     int64 `json:"paid"`
+	Refunded int64 `json:"refunded"`
+}
+
+type chargeAmountResponse struct {
+	Value   int64               `json:"value"`
+	Summary chargeAmountSummary `json:"summary"`
+}
+
 type chargeResponse struct {
 	ID              string                `json:"id"`
 	ReferenceID     string                `json:"reference_id"`
-	Status          string                `json:"status"` // PAID | WAITING | DECLINED | CANCELED | IN_ANALYSIS
+	Status          string                `json:"status"` // PAID | WAITING | DECLINED | CANCELED | IN_ANALYSIS | CHARGEBACK
+	Amount          chargeAmountResponse  `json:"amount"`
 	PaymentResponse chargePaymentResponse `json:"payment_response"`
 }
 
@@ -124,9 +155,20 @@ type webhookQRCode struct {
 }
 
 type webhookCharge struct {
-	ID          string `json:"id"`
-	ReferenceID string `json:"reference_id"`
-	Status      string `json:"status"` // PAID | WAITING | DECLINED | CANCELED | IN_ANALYSIS
+	ID          string               `json:"id"`
+	ReferenceID string               `json:"reference_id"`
+	Status      string               `json:"status"` // PAID | WAITING | DECLINED | CANCELED | IN_ANALYSIS | CHARGEBACK
+	Amount      chargeAmountResponse `json:"amount"`
+}
+
+// RefundedCents retorna o total já devolvido ao cliente nesta cobrança.
+func (c webhookCharge) RefundedCents() int64 {
+	return c.Amount.Summary.Refunded
+}
+
+// IsChargedBack indica que o cliente contestou a cobrança junto ao emissor do cartão.
+func (c webhookCharge) IsChargedBack() bool {
+	return c.Status == "CHARGEBACK"
 }
 
 // ── Status mapping ────────────────────────────────────────────────────────────
@@ -140,6 +182,8 @@ func mapStatus(s string) string {
 		return "rejected"
 	case "IN_ANALYSIS":
 		return "in_process"
+	case "CHARGEBACK":
+		return "charged_back"
 	default: // WAITING, etc.
 		return "pending"
 	}
@@ -331,6 +331,47 @@ func TestParseWebhookPayload_EmptyBody(t *testing.T) {
 	}
 }
 
+func TestParseWebhookPayload_ChargeEstornadaParcialmente(t *testing.T) {
+	body := []byte(`{
+		"order": {
+			"id": "ORD_1",
+			"reference_id": "42",
+			"charges": [{
+				"id": "CHAR_1",
+				"status": "PAID",
+				"amount": {"value": 5000, "summary": {"total": 5000, "paid": 5000, "refunded": 2000}}
+			}]
+		}
+	}`)
+
+	p, err := ParseWebhookPayload(body)
+	if err != nil {
+		t.Fatalf("parse falhou: %v", err)
+	}
+	charge := p.Order.Charges[0]
+	if charge.RefundedCents() != 2000 {
+		t.Errorf("refunded esperado=2000, obtido=%d", charge.RefundedCents())
+	}
+	if charge.IsChargedBack() {
+		t.Error("charge PAID não deve ser tratada como chargeback")
+	}
+}
+
+func TestParseWebhookPayload_Chargeback(t *testing.T) {
+	body := []byte(`{"order": {"reference_id": "42", "charges": [{"id": "CHAR_1", "status": "CHARGEBACK"}]}}`)
+
+	p, err := ParseWebhookPayload(body)
+	if err != nil {
+		t.Fatalf("parse falhou: %v", err)
+	}
+	if !p.Order.Charges[0].IsChargedBack() {
+		t.Error("status CHARGEBACK deve ser tratado como chargeback")
+	}
+	if got := mapStatus("CHARGEBACK"); got != "charged_back" {
+		t.Errorf("mapStatus(CHARGEBACK) = %q, want charged_back", got)
+	}
+}
+
 // ── Helpers internos ──────────────────────────────────────────────────────────
 
 // rewriteHostTransport retorna um RoundTripper que redireciona todas as
@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// AppointmentReminderJob envia lembretes de agendamentos próximos por email e
+// WhatsApp, nos offsets configurados por barbearia (padrão: 24h e 2h antes).
+//
+// ListAppointmentsForReminder busca uma janela de ±5 minutos em torno de
+// now+offset; como o job roda a cada 5 minutos, cada agendamento cai em pelo
+// menos um ciclo. O registro em appointment_reminders (claim antes do envio)
+// garante que o mesmo lembrete nunca saia duas vezes pelo mesmo canal.
+type AppointmentReminderJob struct {
+	repo     domainAppointment.JobRepository
+	tickets  domainTicket.Repository
+	email    domainNotification.ReminderNotifier
+	whatsapp domainNotification.ReminderNotifier
+	appURL   string
+	now      func() time.Time
+}
+
+// NewAppointmentReminderJob cria o job. email e whatsapp podem ser nil quando
+// o canal não está configurado.
+func NewAppointmentReminderJob(
+	repo domainAppointment.JobRepository,
+	tickets domainTicket.Repository,
+	email domainNotification.ReminderNotifier,
+	whatsapp domainNotification.ReminderNotifier,
+	appURL string,
+) *AppointmentReminderJob {
+	return &AppointmentReminderJob{
+		repo:     repo,
+		tickets:  tickets,
+		email:    email,
+		whatsapp: whatsapp,
+		appURL:   appURL,
+		now:      time.Now,
+	}
+}
+
+func (j *AppointmentReminderJob) Run(ctx context.Context) error {
+	if j.email == nil && j.whatsapp == nil {
+		return nil
+	}
+
+	shops, err := j.repo.ListReminderSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := j.now().UTC()
+
+	for _, shop := range shops {
+		sent := 0
+
+		for _, offset := range shop.OffsetsMinutes {
+			target := now.Add(time.Duration(offset) * time.Minute)
+
+			apps, err := j.repo.ListAppointmentsForReminder(ctx, shop.BarbershopID, target)
+			if err != nil {
+				log.Printf("[AppointmentReminderJob] barbershop=%d offset=%d list_error=%v", shop.BarbershopID, offset, err)
+				continue
+			}
+
+			for _, ap := range apps {
+				// Agendamento criado depois do momento do lembrete: o cliente
+				// acabou de receber a confirmação, não faz sentido lembrar.
+				if ap.StartTime.Sub(ap.CreatedAt) < time.Duration(offset)*time.Minute {
+					continue
+				}
+				sent += j.remind(ctx, shop, offset, ap)
+			}
+		}
+
+		if sent > 0 {
+			log.Printf("[AppointmentReminderJob] barbershop=%d sent=%d", shop.BarbershopID, sent)
+		}
+	}
+
+	return nil
+}
+
+// remind envia o lembrete por cada canal disponível e retorna quantos saíram.
+func (j *AppointmentReminderJob) remind(
+	ctx context.Context,
+	shop domainAppointment.ReminderSettings,
+	offset int,
+	ap *models.Appointment,
+) int {
+	if ap.Client == nil || ap.Client.AnonymizedAt != nil {
+		return 0
+	}
+
+	input := domainNotification.AppointmentReminderInput{
+		BarbershopID: shop.BarbershopID,
+		ClientName:   ap.Client.Name,
+		ClientEmail:  ap.Client.Email,
+		ClientPhone:  ap.Client.Phone,
+		StartTime:    ap.StartTime,
+		EndTime:      ap.EndTime,
+		Timezone:     shop.Timezone,
+	}
+	if ap.Barbershop != nil {
+		input.BarbershopName = ap.Barbershop.Name
+		input.BarbershopPhone = ap.Barbershop.Phone
+		input.BarbershopAddress = ap.Barbershop.Address
+	}
+	if ap.BarberProduct != nil {
+		input.ServiceName = ap.BarberProduct.Name
+	}
+	if j.tickets != nil && j.appURL != "" {
+		if ticket, err := j.tickets.GetByAppointmentID(ctx, ap.ID); err == nil {
+			input.TicketURL = j.appURL + "/ticket/" + ticket.Token
+		}
+	}
+
+	sent := 0
+
+	if j.email != nil && input.ClientEmail != "" {
+		if j.send(ctx, shop.BarbershopID, ap.ID, offset, models.ReminderChannelEmail, j.email, input) {
+			sent++
+		}
+	}
+	if j.whatsapp != nil && input.ClientPhone != "" {
+		if j.send(ctx, shop.BarbershopID, ap.ID, offset, models.ReminderChannelWhatsApp, j.whatsapp, input) {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+func (j *AppointmentReminderJob) send(
+	ctx context.Context,
+	barbershopID uint,
+	appointmentID uint,
+	offset int,
+	channel string,
+	notifier domainNotification.ReminderNotifier,
+	input domainNotification.AppointmentReminderInput,
+) bool {
+	ok, err := j.repo.ClaimReminder(ctx, barbershopID, appointmentID, offset, channel)
+	if err != nil {
+		log.Printf("[AppointmentReminderJob] appointment=%d channel=%s claim_error=%v", appointmentID, channel, err)
+		return false
+	}
+	if !ok {
+		// já enviado em um ciclo anterior
+		return false
+	}
+
+	if err := notifier.NotifyReminder(ctx, input); err != nil {
+		log.Printf("[AppointmentReminderJob] appointment=%d channel=%s send_error=%v", appointmentID, channel, err)
+		if err := j.repo.ReleaseReminder(ctx, appointmentID, offset, channel); err != nil {
+			log.Printf("[AppointmentReminderJob] appointment=%d channel=%s release_error=%v", appointmentID, channel, err)
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,260 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeReminderRepo simula o banco do job: a janela de ±5 minutos de
+// ListAppointmentsForReminder e o ON CONFLICT DO NOTHING de
+// appointment_reminders.
+type fakeReminderRepo struct {
+	domainAppointment.JobRepository
+
+	mu           sync.Mutex
+	settings     []domainAppointment.ReminderSettings
+	appointments []*models.Appointment
+	claims       map[string]bool
+	released     []string
+}
+
+func newFakeReminderRepo(offsets ...int) *fakeReminderRepo {
+	return &fakeReminderRepo{
+		settings: []domainAppointment.ReminderSettings{
+			{BarbershopID: 1, Timezone: "America/Sao_Paulo", OffsetsMinutes: offsets},
+		},
+		claims: map[string]bool{},
+	}
+}
+
+func reminderKey(appointmentID uint, offset int, channel string) string {
+	return fmt.Sprintf("%d/%d/%s", appointmentID, offset, channel)
+}
+
+func (r *fakeReminderRepo) ListReminderSettings(context.Context) ([]domainAppointment.ReminderSettings, error) {
+	return r.settings, nil
+}
+
+func (r *fakeReminderRepo) ListAppointmentsForReminder(
+	_ context.Context,
+	barbershopID uint,
+	target time.Time,
+) ([]*models.Appointment, error) {
+	var out []*models.Appointment
+	for _, ap := range r.appointments {
+		if *ap.BarbershopID != barbershopID {
+			continue
+		}
+		if ap.StartTime.Before(target.Add(-5*time.Minute)) || ap.StartTime.After(target.Add(5*time.Minute)) {
+			continue
+		}
+		out = append(out, ap)
+	}
+	return out, nil
+}
+
+func (r *fakeReminderRepo) ClaimReminder(
+	_ context.Context,
+	_ uint,
+	appointmentID uint,
+	offset int,
+	channel string,
+) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := reminderKey(appointmentID, offset, channel)
+	if r.claims[key] {
+		return false, nil
+	}
+	r.claims[key] = true
+	return true, nil
+}
+
+func (r *fakeReminderRepo) ReleaseReminder(
+	_ context.Context,
+	appointmentID uint,
+	offset int,
+	channel string,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := reminderKey(appointmentID, offset, channel)
+	delete(r.claims, key)
+	r.released = append(r.released, key)
+	return nil
+}
+
+type fakeReminderNotifier struct {
+	sent []domainNotification.AppointmentReminderInput
+	err  error
+}
+
+func (f *fakeReminderNotifier) NotifyReminder(_ context.Context, input domainNotification.AppointmentReminderInput) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, input)
+	return nil
+}
+
+func reminderAppointment(id uint, start, created time.Time) *models.Appointment {
+	shopID := uint(1)
+	return &models.Appointment{
+		ID:           id,
+		BarbershopID: &shopID,
+		Status:       models.AppointmentStatusScheduled,
+		StartTime:    start,
+		EndTime:      start.Add(30 * time.Minute),
+		CreatedAt:    created,
+		Client:       &models.Client{Name: fmt.Sprintf("Cliente %d", id), Email: "c@x.com", Phone: "11999990000"},
+	}
+}
+
+func newTestReminderJob(
+	repo *fakeReminderRepo,
+	email, whatsapp *fakeReminderNotifier,
+	now time.Time,
+) *AppointmentReminderJob {
+	var emailNotifier, whatsappNotifier domainNotification.ReminderNotifier
+	if email != nil {
+		emailNotifier = email
+	}
+	if whatsapp != nil {
+		whatsappNotifier = whatsapp
+	}
+	j := NewAppointmentReminderJob(repo, nil, emailNotifier, whatsappNotifier, "")
+	j.now = func() time.Time { return now }
+	return j
+}
+
+// TestAppointmentReminderJob_OffsetWindows: cada offset só lembra os
+// agendamentos a ±5 minutos de now+offset.
+func TestAppointmentReminderJob_OffsetWindows(t *testing.T) {
+	now := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+	created := now.AddDate(0, 0, -3)
+
+	repo := newFakeReminderRepo(1440, 120)
+	repo.appointments = []*models.Appointment{
+		reminderAppointment(1, now.Add(24*time.Hour), created),              // offset 24h
+		reminderAppointment(2, now.Add(2*time.Hour+3*time.Minute), created), // offset 2h
+		reminderAppointment(3, now.Add(5*time.Hour), created),               // fora das janelas
+	}
+	email := &fakeReminderNotifier{}
+
+	if err := newTestReminderJob(repo, email, nil, now).Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(email.sent) != 2 {
+		t.Fatalf("esperado 2 lembretes, obtido %d", len(email.sent))
+	}
+	for _, key := range []string{
+		reminderKey(1, 1440, models.ReminderChannelEmail),
+		reminderKey(2, 120, models.ReminderChannelEmail),
+	} {
+		if !repo.claims[key] {
+			t.Errorf("lembrete %s não registrado; claims = %v", key, repo.claims)
+		}
+	}
+	if len(repo.claims) != 2 {
+		t.Errorf("esperado 2 claims, obtido %v", repo.claims)
+	}
+}
+
+// TestAppointmentReminderJob_SkipsAppointmentCreatedInsideWindow: agendamento
+// marcado depois do momento do lembrete não recebe esse lembrete.
+func TestAppointmentReminderJob_SkipsAppointmentCreatedInsideWindow(t *testing.T) {
+	now := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+
+	repo := newFakeReminderRepo(120)
+	repo.appointments = []*models.Appointment{
+		// Criado há 1 minuto para daqui a 1h57: marcado com menos de 2h de
+		// antecedência, o lembrete de 2h não se aplica.
+		reminderAppointment(1, now.Add(time.Hour+57*time.Minute), now.Add(-time.Minute)),
+		// Criado há 1h para daqui a 2h+1min: lembrete de 2h ainda vale.
+		reminderAppointment(2, now.Add(2*time.Hour+time.Minute), now.Add(-time.Hour)),
+	}
+	email := &fakeReminderNotifier{}
+
+	if err := newTestReminderJob(repo, email, nil, now).Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(email.sent) != 1 || email.sent[0].ClientName != "Cliente 2" {
+		t.Fatalf("esperado só o lembrete do agendamento 2, obtido %+v", email.sent)
+	}
+	if repo.claims[reminderKey(1, 120, models.ReminderChannelEmail)] {
+		t.Error("agendamento 1 não deveria ter claim")
+	}
+}
+
+// TestAppointmentReminderJob_ClaimDedupe: ciclos seguintes (janelas que se
+// sobrepõem) não repetem o lembrete já enviado em nenhum canal.
+func TestAppointmentReminderJob_ClaimDedupe(t *testing.T) {
+	now := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+
+	repo := newFakeReminderRepo(120)
+	repo.appointments = []*models.Appointment{
+		reminderAppointment(1, now.Add(2*time.Hour+2*time.Minute), now.AddDate(0, 0, -1)),
+	}
+	email := &fakeReminderNotifier{}
+	whatsapp := &fakeReminderNotifier{}
+
+	// O job roda a cada 5 minutos: o agendamento cai nas duas janelas.
+	for _, at := range []time.Time{now, now.Add(5 * time.Minute)} {
+		if err := newTestReminderJob(repo, email, whatsapp, at).Run(context.Background()); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+
+	if len(email.sent) != 1 {
+		t.Errorf("email: esperado 1 lembrete, obtido %d", len(email.sent))
+	}
+	if len(whatsapp.sent) != 1 {
+		t.Errorf("whatsapp: esperado 1 lembrete, obtido %d", len(whatsapp.sent))
+	}
+}
+
+// TestAppointmentReminderJob_ReleasesClaimOnSendFailure: envio que falha
+// desfaz o claim e o próximo ciclo tenta de novo.
+func TestAppointmentReminderJob_ReleasesClaimOnSendFailure(t *testing.T) {
+	now := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)
+
+	repo := newFakeReminderRepo(120)
+	repo.appointments = []*models.Appointment{
+		reminderAppointment(1, now.Add(2*time.Hour+2*time.Minute), now.AddDate(0, 0, -1)),
+	}
+	email := &fakeReminderNotifier{err: errors.New("smtp indisponível")}
+	key := reminderKey(1, 120, models.ReminderChannelEmail)
+
+	if err := newTestReminderJob(repo, email, nil, now).Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if repo.claims[key] {
+		t.Fatal("claim deveria ser desfeito após falha no envio")
+	}
+	if len(repo.released) != 1 || repo.released[0] != key {
+		t.Fatalf("esperado release de %s, obtido %v", key, repo.released)
+	}
+
+	email.err = nil
+	if err := newTestReminderJob(repo, email, nil, now.Add(5*time.Minute)).Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(email.sent) != 1 {
+		t.Fatalf("esperado reenvio no ciclo seguinte, obtido %d lembretes", len(email.sent))
+	}
+	if !repo.claims[key] {
+		t.Error("lembrete reenviado deveria ficar registrado")
+	}
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+)
+
+// DeliverOutboxJob entrega os eventos pendentes do outbox (notificações,
+// sincronização de agenda, auditoria). Roda sob o lock de job, então só um
+// nó entrega por vez.
+type DeliverOutboxJob struct {
+	worker *outbox.Worker
+}
+
+func NewDeliverOutboxJob(worker *outbox.Worker) *DeliverOutboxJob {
+	return &DeliverOutboxJob{worker: worker}
+}
+
+func (j *DeliverOutboxJob) Run(ctx context.Context) {
+	delivered, failed, err := j.worker.Run(ctx)
+	if err != nil {
+		log.Printf("[DeliverOutboxJob] error=%v\n", err)
+	}
+
+	if delivered > 0 || failed > 0 {
+		log.Printf("[DeliverOutboxJob] delivered=%d failed=%d\n", delivered, failed)
+	}
+}
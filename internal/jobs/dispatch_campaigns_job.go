@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucCampaign "github.com/BruksfildServices01/barber-scheduler/internal/usecase/campaign"
+)
+
+// DispatchCampaignsJob começa as campanhas agendadas e envia os
+// destinatários pendentes, respeitando o limite por barbearia e canal.
+type DispatchCampaignsJob struct {
+	useCase *ucCampaign.DispatchCampaigns
+}
+
+func NewDispatchCampaignsJob(useCase *ucCampaign.DispatchCampaigns) *DispatchCampaignsJob {
+	return &DispatchCampaignsJob{useCase: useCase}
+}
+
+func (j *DispatchCampaignsJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[DispatchCampaignsJob] started at=%s\n", now.Format(time.RFC3339))
+
+	res, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[DispatchCampaignsJob] error=%v\n", err)
+	}
+
+	if res.Started+res.Sent+res.Failed+res.Skipped+res.Finished > 0 {
+		log.Printf("[DispatchCampaignsJob] started=%d sent=%d failed=%d skipped=%d finished=%d\n",
+			res.Started, res.Sent, res.Failed, res.Skipped, res.Finished)
+	}
+
+	log.Printf("[DispatchCampaignsJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucPackage "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicepackage"
+)
+
+type ExpireClientPackagesJob struct {
+	useCase *ucPackage.ExpireClientPackages
+}
+
+func NewExpireClientPackagesJob(useCase *ucPackage.ExpireClientPackages) *ExpireClientPackagesJob {
+	return &ExpireClientPackagesJob{useCase: useCase}
+}
+
+func (j *ExpireClientPackagesJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[ExpireClientPackagesJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[ExpireClientPackagesJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[ExpireClientPackagesJob] expired %d package(s)\n", n)
+	}
+
+	log.Printf("[ExpireClientPackagesJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
+)
+
+type ExpireLoyaltyPointsJob struct {
+	useCase *ucLoyalty.ExpirePoints
+}
+
+func NewExpireLoyaltyPointsJob(useCase *ucLoyalty.ExpirePoints) *ExpireLoyaltyPointsJob {
+	return &ExpireLoyaltyPointsJob{useCase: useCase}
+}
+
+func (j *ExpireLoyaltyPointsJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[ExpireLoyaltyPointsJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[ExpireLoyaltyPointsJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[ExpireLoyaltyPointsJob] expired %d lot(s)\n", n)
+	}
+
+	log.Printf("[ExpireLoyaltyPointsJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucInventory "github.com/BruksfildServices01/barber-scheduler/internal/usecase/inventory"
+)
+
+// NotifyLowStockJob avisa os donos dos produtos que chegaram ao limite de
+// estoque baixo.
+type NotifyLowStockJob struct {
+	useCase *ucInventory.NotifyLowStock
+}
+
+func NewNotifyLowStockJob(useCase *ucInventory.NotifyLowStock) *NotifyLowStockJob {
+	return &NotifyLowStockJob{useCase: useCase}
+}
+
+func (j *NotifyLowStockJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[NotifyLowStockJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.useCase.Execute(ctx, now)
+	if err != nil {
+		log.Printf("[NotifyLowStockJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[NotifyLowStockJob] notified %d product(s)\n", n)
+	}
+
+	log.Printf("[NotifyLowStockJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
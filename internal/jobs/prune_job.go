@@ -15,6 +15,8 @@ import (
 //   - audit_logs:        90 dias
 //   - idempotency_keys:  30 dias  (nenhum webhook de pagamento replaya após isso)
 //   - carts:             expirados há mais de 1 hora
+//   - whatsapp_conversations: vencidas (o bot recomeça do menu de qualquer forma)
+//   - outbox_events:     entregues há mais de 30 dias, mortos há mais de 90 dias
 type PruneJob struct {
 	db *gorm.DB
 }
@@ -77,5 +79,28 @@ func (j *PruneJob) Run(ctx context.Context) {
 		log.Printf("[PruneJob] appointment_tickets deleted=%d", res.RowsAffected)
 	}
 
+	// whatsapp_conversations: conversa vencida do bot não é retomada
+	res = j.db.WithContext(ctx).
+		Exec("DELETE FROM whatsapp_conversations WHERE expires_at < ?", now)
+	if res.Error != nil {
+		log.Printf("[PruneJob] whatsapp_conversations error=%v", res.Error)
+	} else if res.RowsAffected > 0 {
+		log.Printf("[PruneJob] whatsapp_conversations deleted=%d", res.RowsAffected)
+	}
+
+	// outbox_events: entregues saem em 30 dias; mortos ficam 90 dias no log
+	// de entregas para o dono poder reenviar
+	deliveredCutoff := now.AddDate(0, 0, -30)
+	deadCutoff := now.AddDate(0, 0, -90)
+	res = j.db.WithContext(ctx).
+		Exec(`DELETE FROM outbox_events
+			WHERE (status = 'delivered' AND updated_at < ?)
+			   OR (status = 'dead' AND updated_at < ?)`, deliveredCutoff, deadCutoff)
+	if res.Error != nil {
+		log.Printf("[PruneJob] outbox_events error=%v", res.Error)
+	} else if res.RowsAffected > 0 {
+		log.Printf("[PruneJob] outbox_events deleted=%d", res.RowsAffected)
+	}
+
 	log.Printf("[PruneJob] finished at=%s", time.Now().UTC().Format(time.RFC3339))
 }
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
+)
+
+// ReclassifyClientsJob recalcula a categoria dos clientes das barbearias
+// que alteraram as regras de classificação.
+type ReclassifyClientsJob struct {
+	useCase *ucMetrics.ReclassifyClients
+}
+
+func NewReclassifyClientsJob(useCase *ucMetrics.ReclassifyClients) *ReclassifyClientsJob {
+	return &ReclassifyClientsJob{useCase: useCase}
+}
+
+func (j *ReclassifyClientsJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[ReclassifyClientsJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[ReclassifyClientsJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[ReclassifyClientsJob] reclassified %d client(s)\n", n)
+	}
+
+	log.Printf("[ReclassifyClientsJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
+)
+
+type RenewSubscriptionsJob struct {
+	useCase *ucSubscription.RenewSubscriptions
+}
+
+func NewRenewSubscriptionsJob(useCase *ucSubscription.RenewSubscriptions) *RenewSubscriptionsJob {
+	return &RenewSubscriptionsJob{useCase: useCase}
+}
+
+func (j *RenewSubscriptionsJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[RenewSubscriptionsJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[RenewSubscriptionsJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[RenewSubscriptionsJob] charged %d renewal(s)\n", n)
+	}
+
+	log.Printf("[RenewSubscriptionsJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
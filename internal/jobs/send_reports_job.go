@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ucReport "github.com/BruksfildServices01/barber-scheduler/internal/usecase/report"
+)
+
+// SendReportsJob envia os relatórios agendados que venceram e agenda o
+// próximo envio de cada assinatura.
+type SendReportsJob struct {
+	useCase *ucReport.DispatchReports
+}
+
+func NewSendReportsJob(useCase *ucReport.DispatchReports) *SendReportsJob {
+	return &SendReportsJob{useCase: useCase}
+}
+
+func (j *SendReportsJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[SendReportsJob] started at=%s\n", now.Format(time.RFC3339))
+
+	res, err := j.useCase.Execute(ctx)
+	if err != nil {
+		log.Printf("[SendReportsJob] error=%v\n", err)
+	}
+
+	if res.Sent+res.Partial+res.Failed > 0 {
+		log.Printf("[SendReportsJob] sent=%d partial=%d failed=%d\n", res.Sent, res.Partial, res.Failed)
+	}
+
+	log.Printf("[SendReportsJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
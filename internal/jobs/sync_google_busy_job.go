@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	gcal "github.com/BruksfildServices01/barber-scheduler/internal/integration/calendar"
+)
+
+// SyncGoogleBusyJob traz os compromissos pessoais dos barbeiros com Google
+// Calendar conectado para barber_busy_periods.
+type SyncGoogleBusyJob struct {
+	sync *gcal.GoogleSync
+}
+
+func NewSyncGoogleBusyJob(sync *gcal.GoogleSync) *SyncGoogleBusyJob {
+	return &SyncGoogleBusyJob{sync: sync}
+}
+
+func (j *SyncGoogleBusyJob) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	log.Printf("[SyncGoogleBusyJob] started at=%s\n", now.Format(time.RFC3339))
+
+	n, err := j.sync.SyncBusyPeriods(ctx)
+	if err != nil {
+		log.Printf("[SyncGoogleBusyJob] error=%v\n", err)
+		return
+	}
+
+	if n > 0 {
+		log.Printf("[SyncGoogleBusyJob] synced %d barber(s)\n", n)
+	}
+
+	log.Printf("[SyncGoogleBusyJob] finished at=%s\n", time.Now().UTC().Format(time.RFC3339))
+}
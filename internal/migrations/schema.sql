@@ -37,7 +37,10 @@ CREATE TYPE no_show_source_type AS ENUM (
 CREATE TYPE payment_status AS ENUM (
   'pending',
   'paid',
-  'expired'
+  'expired',
+  'refunded',           -- migration 018
+  'partially_refunded', -- migration 018
+  'charged_back'        -- migration 018
 );
 
 CREATE TYPE client_category AS ENUM (
@@ -72,7 +75,8 @@ CREATE TYPE subscription_status AS ENUM (
   'active',
   'cancelled',
   'expired',
-  'pending_payment'
+  'pending_payment',
+  'past_due'         -- carência da renovação automática (migration 026)
 );
 
 CREATE TYPE coverage_status AS ENUM (
@@ -121,6 +125,14 @@ CREATE TABLE barbershops (
   timezone                  VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo',
   photo_url                 TEXT,
 
+  -- Lembretes automáticos (017)
+  reminders_enabled         BOOLEAN NOT NULL DEFAULT true,
+  reminder_offsets_minutes  TEXT    NOT NULL DEFAULT '[1440,120]',
+
+  -- Atribuição automática de barbeiro (020)
+  barber_assignment_strategy VARCHAR(20) NOT NULL DEFAULT 'least_loaded'
+    CHECK (barber_assignment_strategy IN ('least_loaded', 'round_robin', 'preferred')),
+
   -- SaaS billing
   status TEXT NOT NULL DEFAULT 'trial'
     CHECK (status IN ('pending_payment', 'trial', 'active', 'inactive', 'suspended')),
@@ -174,6 +186,7 @@ CREATE TABLE users (
   phone         VARCHAR(20),
   role          user_role NOT NULL DEFAULT 'owner',
   seen_tours    TEXT      NOT NULL DEFAULT '[]',
+  active        BOOLEAN   NOT NULL DEFAULT true, -- migration 019
   created_at TIMESTAMPTZ DEFAULT now(),
   updated_at TIMESTAMPTZ DEFAULT now()
 );
@@ -510,6 +523,7 @@ CREATE TABLE appointments (
   no_show_at       TIMESTAMPTZ,
   no_show_source   no_show_source_type,
   reschedule_count INTEGER NOT NULL DEFAULT 0,
+  auto_assigned    BOOLEAN NOT NULL DEFAULT false, -- migration 020
 
   created_at TIMESTAMPTZ DEFAULT now(),
   updated_at TIMESTAMPTZ DEFAULT now()
@@ -598,9 +612,12 @@ CREATE TABLE payments (
   status          payment_status NOT NULL,
   paid_at         TIMESTAMPTZ,
   expires_at      TIMESTAMPTZ,
+  refunded_amount BIGINT        NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0), -- migration 018
+  refunded_at     TIMESTAMPTZ,                                                   -- migration 018
   created_at TIMESTAMPTZ DEFAULT now(),
   updated_at TIMESTAMPTZ DEFAULT now(),
 
+  CONSTRAINT payment_refund_within_amount CHECK (refunded_amount <= amount),
   CONSTRAINT payment_exactly_one_target CHECK (
     (appointment_id IS NOT NULL AND order_id IS NULL          AND subscription_id IS NULL)
     OR (appointment_id IS NULL  AND order_id IS NOT NULL      AND subscription_id IS NULL)
@@ -967,4 +984,1210 @@ CREATE OR REPLACE TRIGGER trg_barber_google_tokens_updated
 BEFORE UPDATE ON barber_google_tokens
 FOR EACH ROW EXECUTE FUNCTION set_updated_at();
 
+-- ============================================================
+-- APPOINTMENT REMINDERS (migration 017)
+-- ============================================================
+-- Registro de lembretes enviados pelo AppointmentReminderJob.
+-- A linha é inserida ANTES do envio (claim) — o UNIQUE garante que o
+-- mesmo lembrete (offset + canal) nunca seja enviado duas vezes, mesmo
+-- com múltiplas instâncias. Em caso de falha no envio a linha é removida
+-- para que o próximo ciclo tente novamente.
+-- barbershops.reminder_offsets_minutes: JSON com os offsets em minutos
+-- antes do start_time (padrão 24h e 2h).
+
+CREATE TABLE IF NOT EXISTS appointment_reminders (
+  id             BIGSERIAL   PRIMARY KEY,
+  barbershop_id  BIGINT      NOT NULL REFERENCES barbershops(id)  ON DELETE CASCADE,
+  appointment_id BIGINT      NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+  offset_minutes INTEGER     NOT NULL CHECK (offset_minutes > 0),
+  channel        VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'whatsapp')),
+  sent_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (appointment_id, offset_minutes, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_reminders_barbershop
+  ON appointment_reminders(barbershop_id, sent_at);
+
+-- ============================================================
+-- PAYMENT REFUNDS (migration 018)
+-- ============================================================
+-- Cada devolução de dinheiro de um pagamento: estorno pedido pela barbearia
+-- (POST /me/payments/:id/refund), estorno feito direto no painel do provider
+-- (recebido via webhook) ou chargeback. payments.refunded_amount é o
+-- acumulado; esta tabela é o extrato usado pelo financeiro (perdas).
+
+CREATE TABLE IF NOT EXISTS payment_refunds (
+  id                 BIGSERIAL    PRIMARY KEY,
+  barbershop_id      BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  payment_id         BIGINT       NOT NULL REFERENCES payments(id)    ON DELETE CASCADE,
+  kind               VARCHAR(20)  NOT NULL CHECK (kind IN ('refund', 'chargeback')),
+  amount             BIGINT       NOT NULL CHECK (amount > 0),
+  provider_refund_id VARCHAR(100),
+  reason             TEXT,
+  created_at         TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_barbershop_created
+  ON payment_refunds(barbershop_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_payment
+  ON payment_refunds(payment_id);
+
+-- ============================================================
+-- STAFF INVITATIONS (migration 019)
+-- ============================================================
+-- Convites do dono para barbeiros da equipe. O token segue por e-mail e é
+-- de uso único: ao aceitar, o convidado define a senha e vira um usuário
+-- role = 'barber' da barbearia (accepted_user_id). Convites revogados ou
+-- vencidos não podem ser aceitos.
+-- users.active: barbeiro desativado não faz login nem aparece no booking
+-- público; o histórico de agendamentos é preservado.
+
+CREATE TABLE IF NOT EXISTS staff_invitations (
+  id               BIGSERIAL    PRIMARY KEY,
+  barbershop_id    BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  invited_by       BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+  name             VARCHAR(100) NOT NULL,
+  email            VARCHAR(100) NOT NULL,
+  token            VARCHAR(64)  NOT NULL UNIQUE,
+  expires_at       TIMESTAMPTZ  NOT NULL,
+  accepted_at      TIMESTAMPTZ,
+  accepted_user_id BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+  revoked_at       TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_invitations_barbershop
+  ON staff_invitations(barbershop_id, created_at);
+
+-- ============================================================
+-- BARBER AUTO-ASSIGNMENT (migration 020)
+-- ============================================================
+-- Quando o cliente escolhe "qualquer barbeiro", o sistema atribui um barbeiro
+-- livre segundo barbershops.barber_assignment_strategy. appointments.auto_assigned
+-- marca esses agendamentos; o round-robin parte do último deles.
+
+CREATE INDEX IF NOT EXISTS idx_appointments_auto_assigned
+  ON appointments(barbershop_id, id DESC)
+  WHERE auto_assigned;
+
+-- ============================================================
+-- BARBER SERVICE OVERRIDES (migration 021)
+-- ============================================================
+-- Catálogo por barbeiro: sobrescreve preço e/ou duração de um serviço da
+-- barbearia para um barbeiro específico, ou marca que ele não faz o serviço
+-- (offered = false). Sem linha, o barbeiro atende com os valores da barbearia.
+-- price/duration_min NULL = herda de barbershop_services.
+
+CREATE TABLE IF NOT EXISTS barber_service_overrides (
+  id            BIGSERIAL   PRIMARY KEY,
+  barbershop_id BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  barber_id     BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  service_id    BIGINT      NOT NULL REFERENCES barbershop_services(id) ON DELETE CASCADE,
+  offered       BOOLEAN     NOT NULL DEFAULT true,
+  price         BIGINT      CHECK (price IS NULL OR price >= 0),
+  duration_min  INTEGER     CHECK (duration_min IS NULL OR duration_min > 0),
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (barber_id, service_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_barber_service_overrides_barbershop
+  ON barber_service_overrides(barbershop_id, barber_id);
+
+CREATE TRIGGER trg_barber_service_overrides_updated
+BEFORE UPDATE ON barber_service_overrides
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- ============================================================
+-- WAITLIST (migration 022)
+-- ============================================================
+-- Lista de espera pública: o cliente registra serviço, período e faixas de
+-- horário preferidas. Quando um horário é liberado (cancelamento pelo painel,
+-- pelo ticket ou por pagamento expirado), as entradas compatíveis mais antigas
+-- recebem uma oferta com prazo (offer_expires_at) e um link com o token.
+-- Oferta vencida volta a contar como "waiting" sem precisar de job.
+-- time_windows: JSON [{"start":"09:00","end":"12:00"}]; vazio = qualquer hora.
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+  id                 BIGSERIAL    PRIMARY KEY,
+  barbershop_id      BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  service_id         BIGINT       NOT NULL REFERENCES barbershop_services(id) ON DELETE CASCADE,
+  barber_id          BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+  client_name        VARCHAR(100) NOT NULL,
+  client_phone       VARCHAR(20),
+  client_email       VARCHAR(100),
+  date_from          DATE         NOT NULL,
+  date_to            DATE         NOT NULL,
+  time_windows       TEXT         NOT NULL DEFAULT '[]',
+  status             VARCHAR(20)  NOT NULL DEFAULT 'waiting'
+                       CHECK (status IN ('waiting','offered','booked','cancelled')),
+  token              VARCHAR(64)  NOT NULL UNIQUE,
+  offered_start_time TIMESTAMPTZ,
+  offered_barber_id  BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+  offer_expires_at   TIMESTAMPTZ,
+  appointment_id     BIGINT       REFERENCES appointments(id) ON DELETE SET NULL,
+  created_at         TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at         TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CHECK (date_from <= date_to)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_open
+  ON waitlist_entries(barbershop_id, date_from, date_to, created_at)
+  WHERE status IN ('waiting','offered');
+
+CREATE TRIGGER trg_waitlist_entries_updated
+BEFORE UPDATE ON waitlist_entries
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- ============================================================
+-- APPOINTMENT SERIES (migration 023)
+-- ============================================================
+-- Agendamentos recorrentes de clientes fixos: a série guarda o padrão
+-- (a cada interval_weeks semanas, no dia da semana de start_date, às
+-- time_of_day, até until_date ou por occurrence_count ocorrências) e as
+-- ocorrências são criadas de uma vez como appointments com series_id.
+-- Cada ocorrência passa pelo fluxo normal de booking (expediente, exceções,
+-- conflito e cota da assinatura); as que falham ficam só no relatório.
+-- "Editar esta e as seguintes" divide a série: a parte antiga termina na
+-- véspera (until_date) e as ocorrências seguintes passam para a nova série.
+
+CREATE TABLE IF NOT EXISTS appointment_series (
+  id                 BIGSERIAL    PRIMARY KEY,
+  barbershop_id      BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  barber_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  client_id          BIGINT       NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  service_id         BIGINT       NOT NULL REFERENCES barbershop_services(id) ON DELETE CASCADE,
+  interval_weeks     INTEGER      NOT NULL DEFAULT 1 CHECK (interval_weeks BETWEEN 1 AND 12),
+  start_date         DATE         NOT NULL,
+  time_of_day        VARCHAR(5)   NOT NULL,
+  until_date         DATE,
+  occurrence_count   INTEGER      CHECK (occurrence_count IS NULL OR occurrence_count > 0),
+  notes              VARCHAR(255) NOT NULL DEFAULT '',
+  status             VARCHAR(20)  NOT NULL DEFAULT 'active'
+                       CHECK (status IN ('active','cancelled')),
+  created_by_user_id BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+  created_at         TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at         TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_series_barbershop
+  ON appointment_series(barbershop_id, barber_id, status);
+
+CREATE TRIGGER trg_appointment_series_updated
+BEFORE UPDATE ON appointment_series
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE appointments
+  ADD COLUMN IF NOT EXISTS series_id BIGINT REFERENCES appointment_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_series
+  ON appointments(series_id, start_time)
+  WHERE series_id IS NOT NULL;
+
+-- ============================================================
+-- APPOINTMENT SERVICES (migration 024)
+-- ============================================================
+-- Vários serviços num mesmo agendamento (ex.: corte + barba), em ordem.
+-- Cada linha guarda o snapshot do serviço no booking: nome, preço e duração
+-- do barbeiro (catálogo por barbeiro) e a cobertura da assinatura daquela
+-- linha — cada serviço coberto reserva um corte (reserved_cut).
+-- appointments.barber_product_id continua apontando para o primeiro serviço
+-- (principal) e end_time = start_time + soma das durações.
+-- Agendamentos anteriores ganham uma linha a partir de barber_product_id.
+
+CREATE TABLE IF NOT EXISTS appointment_services (
+  id              BIGSERIAL       PRIMARY KEY,
+  appointment_id  BIGINT          NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+  barbershop_id   BIGINT          NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  service_id      BIGINT          REFERENCES barbershop_services(id) ON DELETE SET NULL,
+  position        INTEGER         NOT NULL DEFAULT 0,
+  service_name    VARCHAR(150)    NOT NULL DEFAULT '',
+  price_cents     BIGINT          NOT NULL DEFAULT 0 CHECK (price_cents >= 0),
+  duration_min    INTEGER         NOT NULL CHECK (duration_min > 0),
+  coverage_status coverage_status NOT NULL DEFAULT 'none',
+  reserved_cut    BOOLEAN         NOT NULL DEFAULT false,
+  created_at      TIMESTAMPTZ     NOT NULL DEFAULT now(),
+  UNIQUE (appointment_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_services_barbershop_service
+  ON appointment_services(barbershop_id, service_id);
+
+INSERT INTO appointment_services (
+  appointment_id, barbershop_id, service_id, position, service_name,
+  price_cents, duration_min, coverage_status, reserved_cut
+)
+SELECT
+  a.id,
+  a.barbershop_id,
+  a.barber_product_id,
+  0,
+  bs.name,
+  COALESCE(o.price, bs.price),
+  GREATEST(EXTRACT(EPOCH FROM (a.end_time - a.start_time))::int / 60, 1),
+  a.coverage_status,
+  a.reserved_subscription_cut
+FROM appointments a
+JOIN barbershop_services bs ON bs.id = a.barber_product_id
+LEFT JOIN barber_service_overrides o
+  ON o.barber_id = a.barber_id AND o.service_id = a.barber_product_id
+WHERE a.barbershop_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM appointment_services s WHERE s.appointment_id = a.id);
+
+-- Parte do valor de referência coberta pela assinatura quando só alguns
+-- serviços do agendamento são cobertos (subscription_covered = false).
+ALTER TABLE appointment_closures
+  ADD COLUMN IF NOT EXISTS subscription_covered_cents BIGINT NOT NULL DEFAULT 0;
+
+-- ============================================================
+-- SERVICE PACKAGES AND COMBOS (migration 025)
+-- ============================================================
+-- Pacotes pré-pagos ("10 cortes por R$400"): o cliente compra uma vez, pelo
+-- mesmo fluxo PIX/cartão das assinaturas, e recebe um saldo de créditos com
+-- validade. Cada serviço coberto pelo pacote consome um crédito na conclusão
+-- do atendimento (Complete). client_packages guarda o snapshot da compra
+-- (nome, preço, créditos e validade) para que editar o pacote não mude o
+-- que o cliente já comprou.
+-- Combos ("corte + barba com 15% off") são conjuntos de serviços vendidos
+-- com preço de pacote: preço fixo (price_cents) ou desconto sobre a soma dos
+-- preços do barbeiro (discount_percent). O booking de um combo cria os
+-- serviços em appointment_services já com o preço rateado.
+
+CREATE TABLE IF NOT EXISTS service_packages (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  name          VARCHAR(100) NOT NULL,
+  price_cents   BIGINT       NOT NULL CHECK (price_cents >= 0),
+  credits       INTEGER      NOT NULL CHECK (credits > 0),
+  validity_days INTEGER      NOT NULL CHECK (validity_days > 0),
+  active        BOOLEAN      NOT NULL DEFAULT true,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_packages_barbershop ON service_packages(barbershop_id);
+
+CREATE TRIGGER trg_service_packages_updated
+BEFORE UPDATE ON service_packages
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS service_package_services (
+  package_id BIGINT NOT NULL REFERENCES service_packages(id)    ON DELETE CASCADE,
+  service_id BIGINT NOT NULL REFERENCES barbershop_services(id) ON DELETE CASCADE,
+  PRIMARY KEY (package_id, service_id)
+);
+
+CREATE TABLE IF NOT EXISTS client_packages (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id)      ON DELETE CASCADE,
+  client_id     BIGINT       NOT NULL REFERENCES clients(id)          ON DELETE CASCADE,
+  package_id    BIGINT       NOT NULL REFERENCES service_packages(id) ON DELETE RESTRICT,
+  status        VARCHAR(20)  NOT NULL DEFAULT 'pending_payment'
+                  CHECK (status IN ('pending_payment','active','expired','cancelled')),
+  package_name  VARCHAR(100) NOT NULL,
+  price_cents   BIGINT       NOT NULL CHECK (price_cents >= 0),
+  credits_total INTEGER      NOT NULL CHECK (credits_total > 0),
+  credits_used  INTEGER      NOT NULL DEFAULT 0 CHECK (credits_used >= 0),
+  validity_days INTEGER      NOT NULL CHECK (validity_days > 0),
+  purchased_at  TIMESTAMPTZ,
+  expires_at    TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CHECK (credits_used <= credits_total)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_packages_client
+  ON client_packages(barbershop_id, client_id, status);
+CREATE INDEX IF NOT EXISTS idx_client_packages_expiring
+  ON client_packages(expires_at)
+  WHERE status = 'active';
+
+CREATE TRIGGER trg_client_packages_updated
+BEFORE UPDATE ON client_packages
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Pagamento da compra do pacote: quarto alvo possível de um payment.
+ALTER TABLE payments
+  ADD COLUMN IF NOT EXISTS client_package_id BIGINT REFERENCES client_packages(id) ON DELETE SET NULL;
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payment_exactly_one_target;
+ALTER TABLE payments ADD CONSTRAINT payment_exactly_one_target CHECK (
+  num_nonnulls(appointment_id, order_id, subscription_id, client_package_id) = 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_client_package
+  ON payments(barbershop_id, client_package_id)
+  WHERE client_package_id IS NOT NULL;
+
+-- Créditos consumidos na conclusão e o valor dos serviços que cobriram.
+ALTER TABLE appointment_closures
+  ADD COLUMN IF NOT EXISTS client_package_id     BIGINT  REFERENCES client_packages(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS package_credits_used  INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS package_covered_cents BIGINT  NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS service_combos (
+  id               BIGSERIAL    PRIMARY KEY,
+  barbershop_id    BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  name             VARCHAR(100) NOT NULL,
+  price_cents      BIGINT       CHECK (price_cents >= 0),
+  discount_percent INTEGER      NOT NULL DEFAULT 0 CHECK (discount_percent BETWEEN 0 AND 100),
+  active           BOOLEAN      NOT NULL DEFAULT true,
+  created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CHECK ((price_cents IS NULL) <> (discount_percent = 0))
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_combos_barbershop ON service_combos(barbershop_id);
+
+CREATE TRIGGER trg_service_combos_updated
+BEFORE UPDATE ON service_combos
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS service_combo_items (
+  combo_id   BIGINT  NOT NULL REFERENCES service_combos(id)      ON DELETE CASCADE,
+  service_id BIGINT  NOT NULL REFERENCES barbershop_services(id) ON DELETE CASCADE,
+  position   INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (combo_id, service_id)
+);
+
+ALTER TABLE appointments
+  ADD COLUMN IF NOT EXISTS combo_id BIGINT REFERENCES service_combos(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_combo
+  ON appointments(barbershop_id, combo_id)
+  WHERE combo_id IS NOT NULL;
+
+-- ============================================================
+-- SUBSCRIPTION AUTO-RENEWAL (migration 026)
+-- ============================================================
+
+-- Estado da renovação automática. renewed_period_end guarda o fim do próximo
+-- período já pago (a virada acontece no job de expiração); grace_until é o
+-- fim da carência (status past_due) antes de a assinatura expirar.
+ALTER TABLE subscriptions
+  ADD COLUMN IF NOT EXISTS auto_renew         BOOLEAN     NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS renewal_attempts   INTEGER     NOT NULL DEFAULT 0 CHECK (renewal_attempts >= 0),
+  ADD COLUMN IF NOT EXISTS next_renewal_at    TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS renewed_period_end TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS grace_until        TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_auto_renew
+  ON subscriptions(current_period_end)
+  WHERE auto_renew = true AND status IN ('active', 'past_due');
+
+-- Cartão guardado no cofre do provider para a renovação. Só referências:
+-- o número do cartão nunca passa pelo backend.
+CREATE TABLE IF NOT EXISTS subscription_cards (
+  id              BIGSERIAL    PRIMARY KEY,
+  barbershop_id   BIGINT       NOT NULL REFERENCES barbershops(id)   ON DELETE CASCADE,
+  subscription_id BIGINT       NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+  provider        VARCHAR(50)  NOT NULL,
+  customer_ref    VARCHAR(100) NOT NULL DEFAULT '',
+  card_ref        VARCHAR(100) NOT NULL,
+  brand           VARCHAR(30)  NOT NULL DEFAULT '',
+  last_four       VARCHAR(4)   NOT NULL DEFAULT '',
+  payer_email     VARCHAR(150) NOT NULL DEFAULT '',
+  payer_cpf       VARCHAR(14)  NOT NULL DEFAULT '',
+  created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CONSTRAINT uq_subscription_cards_subscription UNIQUE (subscription_id)
+);
+
+CREATE TRIGGER trg_subscription_cards_updated
+BEFORE UPDATE ON subscription_cards
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Cobrança de renovação (cartão guardado) de uma assinatura já ativa.
+ALTER TABLE payments
+  ADD COLUMN IF NOT EXISTS subscription_renewal BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_payments_subscription_renewal_pending
+  ON payments(subscription_id)
+  WHERE subscription_renewal = true AND status = 'pending';
+
+-- ============================================================
+-- GOOGLE CALENDAR TWO-WAY SYNC (migration 027)
+-- ============================================================
+
+-- Evento espelhado no Google Calendar do barbeiro: atualizado no reagendamento
+-- e no no-show, apagado no cancelamento.
+ALTER TABLE appointments
+  ADD COLUMN IF NOT EXISTS google_event_id VARCHAR(1024);
+
+-- Sync incremental da agenda do barbeiro (events.list com syncToken).
+ALTER TABLE barber_google_tokens
+  ADD COLUMN IF NOT EXISTS sync_token     TEXT,
+  ADD COLUMN IF NOT EXISTS busy_synced_at TIMESTAMPTZ;
+
+-- Horários ocupados por eventos pessoais do barbeiro no Google Calendar.
+-- Bloqueiam disponibilidade e criação de agendamentos como o almoço.
+CREATE TABLE IF NOT EXISTS barber_busy_periods (
+  id                BIGSERIAL     PRIMARY KEY,
+  barbershop_id     BIGINT        NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  barber_id         BIGINT        NOT NULL REFERENCES users(id)       ON DELETE CASCADE,
+  external_event_id VARCHAR(1024) NOT NULL,
+  start_time        TIMESTAMPTZ   NOT NULL,
+  end_time          TIMESTAMPTZ   NOT NULL,
+  created_at        TIMESTAMPTZ   NOT NULL DEFAULT now(),
+  updated_at        TIMESTAMPTZ   NOT NULL DEFAULT now(),
+  CHECK (end_time > start_time),
+  CONSTRAINT uq_barber_busy_periods_event UNIQUE (barber_id, external_event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_barber_busy_periods_range
+  ON barber_busy_periods(barbershop_id, barber_id, start_time, end_time);
+
+CREATE TRIGGER trg_barber_busy_periods_updated
+BEFORE UPDATE ON barber_busy_periods
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- ============================================================
+-- ICAL FEEDS (migration 028)
+-- ============================================================
+-- Feed .ics assinável (Apple Calendar, Outlook) por barbeiro e um da barbearia
+-- inteira (barber_id NULL). O token secreto na URL é a única autenticação;
+-- rotacionar invalida a URL antiga.
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+  id            BIGSERIAL   PRIMARY KEY,
+  barbershop_id BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  barber_id     BIGINT      REFERENCES users(id) ON DELETE CASCADE,
+  token         VARCHAR(64) NOT NULL UNIQUE,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_calendar_feeds_barber
+  ON calendar_feeds(barbershop_id, barber_id)
+  WHERE barber_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_calendar_feeds_shop
+  ON calendar_feeds(barbershop_id)
+  WHERE barber_id IS NULL;
+
+CREATE TRIGGER trg_calendar_feeds_updated
+BEFORE UPDATE ON calendar_feeds
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- SEQUENCE do VEVENT: sobe quando horário ou status mudam, seja qual for o
+-- caminho (painel, ticket, série, job). O valor gravado pela aplicação é
+-- ignorado — o trigger é a fonte da verdade.
+ALTER TABLE appointments
+  ADD COLUMN IF NOT EXISTS calendar_sequence INTEGER NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION bump_appointment_calendar_sequence()
+RETURNS trigger AS $$
+BEGIN
+  NEW.calendar_sequence = OLD.calendar_sequence;
+  IF NEW.start_time IS DISTINCT FROM OLD.start_time
+     OR NEW.end_time IS DISTINCT FROM OLD.end_time
+     OR NEW.status IS DISTINCT FROM OLD.status THEN
+    NEW.calendar_sequence = OLD.calendar_sequence + 1;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_appointments_calendar_sequence
+BEFORE UPDATE ON appointments
+FOR EACH ROW EXECUTE FUNCTION bump_appointment_calendar_sequence();
+
+-- ============================================================
+-- INVENTORY MOVEMENTS (migration 029)
+-- ============================================================
+-- Razão de estoque: toda alteração de products.stock grava um movimento com a
+-- quantidade assinada e o saldo resultante. SUM(quantity) por produto é o
+-- estoque; products.stock é o saldo materializado, atualizado na mesma TX.
+
+CREATE TABLE IF NOT EXISTS inventory_movements (
+  id              BIGSERIAL    PRIMARY KEY,
+  barbershop_id   BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  product_id      BIGINT       NOT NULL REFERENCES products(id)    ON DELETE CASCADE,
+  type            VARCHAR(20)  NOT NULL
+    CHECK (type IN ('sale', 'closure_addon', 'adjustment', 'purchase', 'loss', 'return')),
+  quantity        INTEGER      NOT NULL CHECK (quantity <> 0),
+  stock_after     INTEGER      NOT NULL CHECK (stock_after >= 0),
+  unit_cost_cents BIGINT       CHECK (unit_cost_cents >= 0),
+  order_id        BIGINT       REFERENCES orders(id)       ON DELETE SET NULL,
+  appointment_id  BIGINT       REFERENCES appointments(id) ON DELETE SET NULL,
+  user_id         BIGINT       REFERENCES users(id)        ON DELETE SET NULL,
+  note            VARCHAR(255) NOT NULL DEFAULT '',
+  created_at      TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_product
+  ON inventory_movements(barbershop_id, product_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_order
+  ON inventory_movements(order_id)
+  WHERE order_id IS NOT NULL;
+
+-- Alerta de estoque baixo: low_stock_threshold NULL = sem alerta.
+-- low_stock_notified_at evita repetir o aviso; volta a NULL quando o estoque
+-- sobe acima do limite.
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS low_stock_threshold   INTEGER CHECK (low_stock_threshold >= 0),
+  ADD COLUMN IF NOT EXISTS low_stock_notified_at TIMESTAMPTZ;
+
+-- Saldo de abertura dos produtos existentes, para que o razão feche com o estoque.
+INSERT INTO inventory_movements (barbershop_id, product_id, type, quantity, stock_after, note)
+SELECT p.barbershop_id, p.id, 'adjustment', p.stock, p.stock, 'Estoque inicial'
+FROM products p
+WHERE p.stock <> 0
+  AND NOT EXISTS (SELECT 1 FROM inventory_movements m WHERE m.product_id = p.id);
+
+-- ============================================================
+-- ORDER RETURNS (migration 030)
+-- ============================================================
+-- Cancelamento de pedido e devolução parcial de itens. Cada devolução repõe o
+-- estoque (movimento 'return' no razão) e devolve o dinheiro pelo gateway
+-- quando há pagamento vinculado; sem isso, fica marcada para reembolso manual.
+
+ALTER TABLE order_items
+  ADD COLUMN IF NOT EXISTS returned_quantity INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+  ADD CONSTRAINT chk_order_items_returned_quantity
+  CHECK (returned_quantity >= 0 AND returned_quantity <= quantity);
+
+CREATE TABLE IF NOT EXISTS order_returns (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  order_id      BIGINT       NOT NULL REFERENCES orders(id)      ON DELETE CASCADE,
+  user_id       BIGINT       REFERENCES users(id)                ON DELETE SET NULL,
+  kind          VARCHAR(20)  NOT NULL CHECK (kind IN ('cancellation', 'return')),
+  reason        VARCHAR(255) NOT NULL,
+  amount_cents  BIGINT       NOT NULL CHECK (amount_cents >= 0),
+  payment_id    BIGINT       REFERENCES payments(id)             ON DELETE SET NULL,
+  -- none: nada a devolver | refunded: estornado pelo gateway |
+  -- manual_pending: devolver por fora (dinheiro, maquininha) | manual_refunded: devolvido por fora
+  refund_status VARCHAR(20)  NOT NULL
+    CHECK (refund_status IN ('none', 'refunded', 'manual_pending', 'manual_refunded')),
+  refunded_at   TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_returns_order
+  ON order_returns(order_id);
+
+CREATE INDEX IF NOT EXISTS idx_order_returns_barbershop_created
+  ON order_returns(barbershop_id, created_at);
+
+CREATE TRIGGER trg_order_returns_updated
+BEFORE UPDATE ON order_returns
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS order_return_items (
+  id              BIGSERIAL PRIMARY KEY,
+  order_return_id BIGINT    NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+  order_item_id   BIGINT    NOT NULL REFERENCES order_items(id)   ON DELETE CASCADE,
+  product_id      BIGINT    NOT NULL REFERENCES products(id)      ON DELETE RESTRICT,
+  quantity        INTEGER   NOT NULL CHECK (quantity > 0),
+  amount_cents    BIGINT    NOT NULL CHECK (amount_cents >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_return_items_return
+  ON order_return_items(order_return_id);
+
+-- ============================================================
+-- COUPONS (migration 031)
+-- ============================================================
+-- Cupons de desconto: percentual ou valor fixo, com janela de validade,
+-- limite total e por cliente, valor mínimo e restrição a serviços, produtos
+-- ou planos. uses_count é o contador atômico do limite total: o UPDATE
+-- condicional que o incrementa também trava o cupom, serializando os
+-- resgates concorrentes do mesmo código.
+
+CREATE TABLE IF NOT EXISTS coupons (
+  id                  BIGSERIAL    PRIMARY KEY,
+  barbershop_id       BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  code                VARCHAR(40)  NOT NULL,
+  description         VARCHAR(255) NOT NULL DEFAULT '',
+  discount_type       VARCHAR(10)  NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+  -- percent: 1..100 | fixed: centavos
+  discount_value      BIGINT       NOT NULL CHECK (discount_value > 0),
+  applies_to          VARCHAR(10)  NOT NULL DEFAULT 'all'
+    CHECK (applies_to IN ('all', 'services', 'products', 'plans')),
+  starts_at           TIMESTAMPTZ,
+  ends_at             TIMESTAMPTZ,
+  max_uses            INTEGER      CHECK (max_uses > 0),
+  max_uses_per_client INTEGER      CHECK (max_uses_per_client > 0),
+  min_order_cents     BIGINT       NOT NULL DEFAULT 0 CHECK (min_order_cents >= 0),
+  first_visit_only    BOOLEAN      NOT NULL DEFAULT false,
+  active              BOOLEAN      NOT NULL DEFAULT true,
+  uses_count          INTEGER      NOT NULL DEFAULT 0,
+  created_at          TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at          TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_coupons_percent CHECK (discount_type <> 'percent' OR discount_value <= 100),
+  CONSTRAINT chk_coupons_window  CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at),
+  CONSTRAINT chk_coupons_uses    CHECK (uses_count >= 0 AND (max_uses IS NULL OR uses_count <= max_uses))
+);
+
+-- Código único por barbearia, sem diferenciar maiúsculas.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_coupons_barbershop_code
+  ON coupons(barbershop_id, UPPER(code));
+
+CREATE TRIGGER trg_coupons_updated
+BEFORE UPDATE ON coupons
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- Serviços, produtos ou planos aceitos pelo cupom (conforme applies_to).
+-- Sem linhas = todos daquele tipo.
+CREATE TABLE IF NOT EXISTS coupon_targets (
+  coupon_id BIGINT NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+  target_id BIGINT NOT NULL,
+  PRIMARY KEY (coupon_id, target_id)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+  id              BIGSERIAL   PRIMARY KEY,
+  barbershop_id   BIGINT      NOT NULL REFERENCES barbershops(id)   ON DELETE CASCADE,
+  coupon_id       BIGINT      NOT NULL REFERENCES coupons(id)       ON DELETE CASCADE,
+  client_id       BIGINT      REFERENCES clients(id)                ON DELETE SET NULL,
+  appointment_id  BIGINT      REFERENCES appointments(id)           ON DELETE SET NULL,
+  order_id        BIGINT      REFERENCES orders(id)                 ON DELETE SET NULL,
+  subscription_id BIGINT      REFERENCES subscriptions(id)          ON DELETE SET NULL,
+  discount_cents  BIGINT      NOT NULL DEFAULT 0 CHECK (discount_cents >= 0),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_client
+  ON coupon_redemptions(coupon_id, client_id);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_barbershop_created
+  ON coupon_redemptions(barbershop_id, created_at);
+
+-- Desconto aplicado no booking (o pedido usa orders.discount_amount).
+ALTER TABLE appointments
+  ADD COLUMN IF NOT EXISTS coupon_id      BIGINT REFERENCES coupons(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS discount_cents BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE orders
+  ADD COLUMN IF NOT EXISTS coupon_id BIGINT REFERENCES coupons(id) ON DELETE SET NULL;
+
+-- ============================================================
+-- LOYALTY (migration 032)
+-- ============================================================
+-- Programa de fidelidade: pontos por real gasto no fechamento e nos pedidos
+-- pagos, trocados por recompensas (serviço grátis ou desconto) no fechamento.
+-- loyalty_entries é o extrato: o saldo é a soma de points. Cada crédito
+-- (earn) é um lote com validade própria; débitos consomem remaining_points
+-- dos lotes que vencem primeiro e o job de expiração zera o que sobrou.
+
+CREATE TABLE IF NOT EXISTS loyalty_programs (
+  barbershop_id   BIGINT      PRIMARY KEY REFERENCES barbershops(id) ON DELETE CASCADE,
+  active          BOOLEAN     NOT NULL DEFAULT false,
+  points_per_real INTEGER     NOT NULL DEFAULT 1 CHECK (points_per_real > 0),
+  -- 0 = pontos não expiram
+  expiry_months   INTEGER     NOT NULL DEFAULT 12 CHECK (expiry_months >= 0),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_loyalty_programs_updated
+BEFORE UPDATE ON loyalty_programs
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS loyalty_rewards (
+  id             BIGSERIAL    PRIMARY KEY,
+  barbershop_id  BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  name           VARCHAR(100) NOT NULL,
+  kind           VARCHAR(20)  NOT NULL CHECK (kind IN ('free_service', 'discount')),
+  points_cost    INTEGER      NOT NULL CHECK (points_cost > 0),
+  service_id     BIGINT       REFERENCES barbershop_services(id) ON DELETE SET NULL,
+  discount_cents BIGINT       NOT NULL DEFAULT 0 CHECK (discount_cents >= 0),
+  active         BOOLEAN      NOT NULL DEFAULT true,
+  created_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_loyalty_rewards_kind CHECK (
+    (kind = 'free_service' AND discount_cents = 0) OR
+    (kind = 'discount' AND service_id IS NULL AND discount_cents > 0)
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_rewards_barbershop
+  ON loyalty_rewards(barbershop_id);
+
+CREATE TRIGGER trg_loyalty_rewards_updated
+BEFORE UPDATE ON loyalty_rewards
+FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+  id               BIGSERIAL   PRIMARY KEY,
+  barbershop_id    BIGINT      NOT NULL REFERENCES barbershops(id)     ON DELETE CASCADE,
+  client_id        BIGINT      NOT NULL REFERENCES clients(id)         ON DELETE CASCADE,
+  kind             VARCHAR(10) NOT NULL CHECK (kind IN ('earn', 'redeem', 'reverse', 'expire')),
+  -- positivo no earn, negativo nos demais
+  points           INTEGER     NOT NULL,
+  -- saldo ainda disponível do lote (só earn)
+  remaining_points INTEGER     NOT NULL DEFAULT 0 CHECK (remaining_points >= 0),
+  -- valor que gerou os pontos (earn) ou desconto concedido (redeem)
+  amount_cents     BIGINT      NOT NULL DEFAULT 0,
+  appointment_id   BIGINT      REFERENCES appointments(id)    ON DELETE SET NULL,
+  order_id         BIGINT      REFERENCES orders(id)          ON DELETE SET NULL,
+  reward_id        BIGINT      REFERENCES loyalty_rewards(id) ON DELETE SET NULL,
+  expires_at       TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_loyalty_entries_sign CHECK (
+    (kind = 'earn' AND points > 0 AND remaining_points <= points) OR
+    (kind <> 'earn' AND points < 0 AND remaining_points = 0)
+  )
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_client
+  ON loyalty_entries(barbershop_id, client_id, created_at);
+
+-- Lotes com saldo, para o consumo FIFO e o job de expiração.
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_open_lots
+  ON loyalty_entries(expires_at)
+  WHERE kind = 'earn' AND remaining_points > 0;
+
+-- Um crédito por atendimento e por pedido: confirmações repetidas
+-- (webhook reenviado, retentativa) não pontuam de novo.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_loyalty_entries_earn_appointment
+  ON loyalty_entries(appointment_id) WHERE kind = 'earn';
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_loyalty_entries_earn_order
+  ON loyalty_entries(order_id) WHERE kind = 'earn';
+
+-- ============================================================
+-- CLIENT PORTAL (migration 033)
+-- ============================================================
+-- Portal do cliente com login sem senha: um código de 6 dígitos vai pelo
+-- WhatsApp ou por email e, confirmado, vira uma sessão própria do cliente
+-- (separada do JWT da equipe). Código e token são guardados só como hash
+-- SHA-256.
+
+CREATE TABLE IF NOT EXISTS client_login_codes (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id     BIGINT       NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  channel       VARCHAR(10)  NOT NULL CHECK (channel IN ('whatsapp', 'email')),
+  code_hash     VARCHAR(64)  NOT NULL,
+  attempts      INTEGER      NOT NULL DEFAULT 0,
+  expires_at    TIMESTAMPTZ  NOT NULL,
+  used_at       TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_login_codes_client
+  ON client_login_codes(client_id, created_at);
+
+CREATE TABLE IF NOT EXISTS client_sessions (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id     BIGINT       NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  token_hash    VARCHAR(64)  NOT NULL UNIQUE,
+  expires_at    TIMESTAMPTZ  NOT NULL,
+  revoked_at    TIMESTAMPTZ,
+  last_seen_at  TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_sessions_client
+  ON client_sessions(client_id);
+
+-- ============================================================
+-- WHATSAPP BOT (migration 034)
+-- ============================================================
+-- Conversa do bot de agendamento pelo WhatsApp: uma linha por número em
+-- cada instância, com a etapa atual e as opções mostradas no último menu.
+-- A conversa vence após um período sem mensagens e recomeça do menu.
+
+CREATE TABLE IF NOT EXISTS whatsapp_conversations (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  instance_name VARCHAR(100) NOT NULL,
+  phone         VARCHAR(20)  NOT NULL,
+  state         VARCHAR(30)  NOT NULL,
+  data          JSONB        NOT NULL DEFAULT '{}',
+  expires_at    TIMESTAMPTZ  NOT NULL,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_whatsapp_conversations_instance_phone UNIQUE (instance_name, phone)
+);
+
+CREATE INDEX IF NOT EXISTS idx_whatsapp_conversations_expires
+  ON whatsapp_conversations(expires_at);
+
+-- ============================================================
+-- OUTBOX (migration 035)
+-- ============================================================
+-- Efeitos colaterais (notificações por canal, sincronização com o Google
+-- Calendar e auditoria) gravados na mesma transação da mudança que os
+-- originou. O worker entrega os pendentes com backoff exponencial; depois
+-- do limite de tentativas o evento fica 'dead' até o owner reenviar.
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id              BIGSERIAL    PRIMARY KEY,
+  barbershop_id   BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  topic           VARCHAR(50)  NOT NULL,
+  channel         VARCHAR(20)  NOT NULL DEFAULT '',
+  reference       VARCHAR(60)  NOT NULL DEFAULT '',
+  payload         JSONB        NOT NULL DEFAULT '{}',
+  status          VARCHAR(20)  NOT NULL DEFAULT 'pending',
+  attempts        INT          NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  last_error      TEXT,
+  delivered_at    TIMESTAMPTZ,
+  created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_outbox_events_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due
+  ON outbox_events(next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_barbershop
+  ON outbox_events(barbershop_id, created_at DESC);
+
+-- ============================================================
+-- NOTIFICATION TEMPLATES (migration 036)
+-- ============================================================
+-- Texto personalizado de cada mensagem ao cliente, por barbearia e canal.
+-- Sem linha para (kind, channel), vale o texto padrão do sistema. Os
+-- placeholders ({{client_name}}, {{date}}...) são validados ao salvar.
+
+CREATE TABLE IF NOT EXISTS notification_templates (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  kind          VARCHAR(40)  NOT NULL,
+  channel       VARCHAR(20)  NOT NULL,
+  subject       VARCHAR(200) NOT NULL DEFAULT '',
+  body          TEXT         NOT NULL,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_notification_templates_kind_channel UNIQUE (barbershop_id, kind, channel),
+  CONSTRAINT chk_notification_templates_channel CHECK (channel IN ('email', 'whatsapp'))
+);
+
+-- ============================================================
+-- CLIENT CLASSIFICATION RULES (migration 037)
+-- ============================================================
+-- Regras de classificação de clientes por barbearia: lista ordenada de
+-- limiares (a primeira regra que casa define a categoria). rules NULL =
+-- regras padrão. Salvar marca reclassify_pending; o job de reclassificação
+-- recalcula a categoria de todos os clientes e limpa a marca.
+--
+-- A categoria deixa de ser um ENUM fixo para aceitar categorias próprias
+-- da barbearia (vip, dormant...).
+
+ALTER TABLE client_metrics
+  ALTER COLUMN category DROP DEFAULT,
+  ALTER COLUMN category TYPE VARCHAR(30) USING category::text,
+  ALTER COLUMN category SET DEFAULT 'new';
+
+ALTER TABLE client_crm_categories
+  ALTER COLUMN category DROP DEFAULT,
+  ALTER COLUMN category TYPE VARCHAR(30) USING category::text,
+  ALTER COLUMN category SET DEFAULT 'new';
+
+ALTER TABLE category_payment_policies
+  ALTER COLUMN category TYPE VARCHAR(30) USING category::text;
+
+DROP TYPE IF EXISTS client_category;
+
+CREATE TABLE IF NOT EXISTS client_classification_rules (
+  barbershop_id      BIGINT      PRIMARY KEY REFERENCES barbershops(id) ON DELETE CASCADE,
+  rules              JSONB,
+  reclassify_pending BOOLEAN     NOT NULL DEFAULT true,
+  reclassified_at    TIMESTAMPTZ,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_classification_rules_pending
+  ON client_classification_rules(barbershop_id) WHERE reclassify_pending;
+
+-- ============================================================
+-- CLIENT SEGMENTS & CAMPAIGNS (migration 038)
+-- ============================================================
+-- Segmentos salvos (filtros sobre categoria, última visita, gasto, faltas,
+-- assinatura e serviços/produtos comprados) e campanhas que mandam uma
+-- mensagem por email ou WhatsApp aos clientes do segmento.
+--
+-- Os destinatários são gravados quando a campanha começa, com o status de
+-- entrega de cada um. Quem pediu para não receber campanhas fica com
+-- clients.marketing_opt_out_at preenchido (link no email, PARAR no WhatsApp).
+
+ALTER TABLE clients
+  ADD COLUMN IF NOT EXISTS marketing_opt_out_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS client_segments (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  name          VARCHAR(100) NOT NULL,
+  filters       JSONB        NOT NULL DEFAULT '{}',
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_segments_barbershop ON client_segments(barbershop_id);
+
+CREATE TABLE IF NOT EXISTS campaigns (
+  id            BIGSERIAL    PRIMARY KEY,
+  barbershop_id BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  segment_id    BIGINT       REFERENCES client_segments(id) ON DELETE SET NULL,
+  name          VARCHAR(100) NOT NULL,
+  channel       VARCHAR(20)  NOT NULL,
+  subject       VARCHAR(200) NOT NULL DEFAULT '',
+  body          TEXT         NOT NULL,
+  status        VARCHAR(20)  NOT NULL DEFAULT 'draft',
+  scheduled_at  TIMESTAMPTZ,
+  started_at    TIMESTAMPTZ,
+  finished_at   TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_campaigns_channel CHECK (channel IN ('email', 'whatsapp')),
+  CONSTRAINT chk_campaigns_status  CHECK (status IN ('draft', 'scheduled', 'sending', 'completed', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_barbershop ON campaigns(barbershop_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaigns_due
+  ON campaigns(scheduled_at) WHERE status IN ('scheduled', 'sending');
+
+CREATE TABLE IF NOT EXISTS campaign_recipients (
+  id                BIGSERIAL    PRIMARY KEY,
+  campaign_id       BIGINT       NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+  barbershop_id     BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id         BIGINT       NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  destination       VARCHAR(100) NOT NULL DEFAULT '',
+  status            VARCHAR(20)  NOT NULL DEFAULT 'pending',
+  error             VARCHAR(255),
+  unsubscribe_token VARCHAR(64)  NOT NULL,
+  sent_at           TIMESTAMPTZ,
+  created_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_campaign_recipients_client UNIQUE (campaign_id, client_id),
+  CONSTRAINT uq_campaign_recipients_token  UNIQUE (unsubscribe_token),
+  CONSTRAINT chk_campaign_recipients_status
+    CHECK (status IN ('pending', 'sent', 'failed', 'skipped', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_recipients_pending
+  ON campaign_recipients(campaign_id, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_campaign_recipients_sent
+  ON campaign_recipients(client_id, sent_at) WHERE status = 'sent';
+
+-- Atribuição por último toque: o agendamento criado até 7 dias depois de
+-- uma mensagem entregue ao cliente conta para a campanha mais recente.
+CREATE OR REPLACE VIEW campaign_attributions AS
+SELECT DISTINCT ON (a.id)
+  a.id           AS appointment_id,
+  cr.campaign_id,
+  cr.barbershop_id,
+  cr.client_id,
+  a.status       AS appointment_status,
+  a.created_at   AS booked_at,
+  cr.sent_at
+FROM campaign_recipients cr
+JOIN appointments a
+  ON a.client_id = cr.client_id
+ AND a.barbershop_id = cr.barbershop_id
+ AND a.created_at >= cr.sent_at
+ AND a.created_at < cr.sent_at + INTERVAL '7 days'
+WHERE cr.status = 'sent'
+ORDER BY a.id, cr.sent_at DESC;
+
+-- ============================================================
+-- CLIENT MERGES (migration 039)
+-- ============================================================
+-- Junção de cadastros duplicados da mesma pessoa. O cadastro juntado não é
+-- apagado: fica com merged_into_id apontando o mantido, sem telefone e
+-- email, fora das listas. client_merges guarda o que foi movido e o estado
+-- anterior (contatos e métricas dos dois) para desfazer a junção.
+
+ALTER TABLE clients
+  ADD COLUMN IF NOT EXISTS merged_into_id BIGINT REFERENCES clients(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS merged_at      TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_clients_merged_into
+  ON clients(merged_into_id) WHERE merged_into_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS client_merges (
+  id                BIGSERIAL   PRIMARY KEY,
+  barbershop_id     BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  target_client_id  BIGINT      NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  source_client_id  BIGINT      NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  merged_by_user_id BIGINT      REFERENCES users(id)                ON DELETE SET NULL,
+  snapshot          JSONB       NOT NULL,
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+  undone_at         TIMESTAMPTZ,
+  undone_by_user_id BIGINT      REFERENCES users(id)                ON DELETE SET NULL,
+  CHECK (target_client_id <> source_client_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_merges_barbershop
+  ON client_merges(barbershop_id, created_at DESC);
+
+-- Um cadastro só pode estar juntado a outro uma vez (até desfazer).
+CREATE UNIQUE INDEX IF NOT EXISTS uq_client_merges_open_source
+  ON client_merges(source_client_id) WHERE undone_at IS NULL;
+
+-- ============================================================
+-- CLIENT PROFILE (migration 040)
+-- ============================================================
+-- O que a equipe sabe do cliente além das métricas: anotações com autor,
+-- etiquetas definidas pela barbearia, preferências estruturadas e fotos de
+-- antes/depois de cada atendimento (arquivos no storage de imagens).
+
+CREATE TABLE IF NOT EXISTS client_notes (
+  id             BIGSERIAL   PRIMARY KEY,
+  barbershop_id  BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id      BIGINT      NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  author_user_id BIGINT      REFERENCES users(id)                ON DELETE SET NULL,
+  body           TEXT        NOT NULL,
+  pinned         BOOLEAN     NOT NULL DEFAULT false,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_notes_client
+  ON client_notes(client_id, pinned DESC, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS client_tags (
+  id            BIGSERIAL   PRIMARY KEY,
+  barbershop_id BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  name          VARCHAR(40) NOT NULL,
+  color         VARCHAR(7)  NOT NULL DEFAULT '',
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_client_tags_name
+  ON client_tags(barbershop_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS client_tag_assignments (
+  id            BIGSERIAL   PRIMARY KEY,
+  barbershop_id BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id     BIGINT      NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  tag_id        BIGINT      NOT NULL REFERENCES client_tags(id) ON DELETE CASCADE,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_client_tag_assignments UNIQUE (client_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_tag_assignments_tag
+  ON client_tag_assignments(tag_id);
+
+-- Uma linha por cliente. Os ids favoritos são arrays JSON.
+CREATE TABLE IF NOT EXISTS client_preferences (
+  id                   BIGSERIAL    PRIMARY KEY,
+  barbershop_id        BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  client_id            BIGINT       NOT NULL REFERENCES clients(id)     ON DELETE CASCADE,
+  preferred_barber_id  BIGINT       REFERENCES users(id)                ON DELETE SET NULL,
+  allergies            VARCHAR(500) NOT NULL DEFAULT '',
+  favorite_service_ids JSONB        NOT NULL DEFAULT '[]',
+  favorite_product_ids JSONB        NOT NULL DEFAULT '[]',
+  beverage             VARCHAR(100) NOT NULL DEFAULT '',
+  updated_by_user_id   BIGINT       REFERENCES users(id)                ON DELETE SET NULL,
+  created_at           TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at           TIMESTAMPTZ  NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_client_preferences_client UNIQUE (client_id)
+);
+
+CREATE TABLE IF NOT EXISTS client_photos (
+  id                  BIGSERIAL    PRIMARY KEY,
+  barbershop_id       BIGINT       NOT NULL REFERENCES barbershops(id)  ON DELETE CASCADE,
+  client_id           BIGINT       NOT NULL REFERENCES clients(id)      ON DELETE CASCADE,
+  appointment_id      BIGINT       NOT NULL REFERENCES appointments(id) ON DELETE CASCADE,
+  kind                VARCHAR(10)  NOT NULL CHECK (kind IN ('before', 'after')),
+  url                 VARCHAR(500) NOT NULL,
+  uploaded_by_user_id BIGINT       REFERENCES users(id)                 ON DELETE SET NULL,
+  created_at          TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_photos_client
+  ON client_photos(client_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_client_photos_appointment
+  ON client_photos(appointment_id);
+
+-- ============================================================
+-- REPORT SUBSCRIPTIONS (migration 041)
+-- ============================================================
+-- Relatórios (dashboard, financeiro, impacto) enviados por email no horário
+-- da barbearia, com anexo CSV ou PDF, e o histórico de cada envio.
+
+CREATE TABLE IF NOT EXISTS report_subscriptions (
+  id                 BIGSERIAL   PRIMARY KEY,
+  barbershop_id      BIGINT      NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  report             VARCHAR(20) NOT NULL CHECK (report IN ('dashboard', 'financial', 'impact')),
+  period             VARCHAR(10) NOT NULL CHECK (period IN ('daily', 'weekly', 'monthly')),
+  format             VARCHAR(5)  NOT NULL CHECK (format IN ('csv', 'pdf')),
+  recipients         JSONB       NOT NULL DEFAULT '[]',
+  send_time          VARCHAR(5)  NOT NULL DEFAULT '08:00',
+  active             BOOLEAN     NOT NULL DEFAULT true,
+  next_run_at        TIMESTAMPTZ NOT NULL,
+  last_sent_at       TIMESTAMPTZ,
+  created_by_user_id BIGINT      REFERENCES users(id) ON DELETE SET NULL,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_subscriptions_barbershop
+  ON report_subscriptions(barbershop_id);
+CREATE INDEX IF NOT EXISTS idx_report_subscriptions_due
+  ON report_subscriptions(next_run_at)
+  WHERE active;
+
+-- Uma linha por envio (agendado ou manual), com o período coberto e o
+-- resultado. status: sent (todos receberam), partial ou failed.
+CREATE TABLE IF NOT EXISTS report_deliveries (
+  id              BIGSERIAL    PRIMARY KEY,
+  barbershop_id   BIGINT       NOT NULL REFERENCES barbershops(id) ON DELETE CASCADE,
+  subscription_id BIGINT       REFERENCES report_subscriptions(id) ON DELETE SET NULL,
+  report          VARCHAR(20)  NOT NULL,
+  period          VARCHAR(10)  NOT NULL,
+  format          VARCHAR(5)   NOT NULL,
+  period_start    DATE         NOT NULL,
+  period_end      DATE         NOT NULL,
+  recipients      JSONB        NOT NULL DEFAULT '[]',
+  trigger         VARCHAR(10)  NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+  status          VARCHAR(10)  NOT NULL CHECK (status IN ('sent', 'partial', 'failed')),
+  error           VARCHAR(255),
+  attachment_name VARCHAR(100) NOT NULL DEFAULT '',
+  created_at      TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_deliveries_barbershop
+  ON report_deliveries(barbershop_id, created_at DESC);
+
+-- ============================================================
+-- COUPON REDEMPTION VOID (migration 042)
+-- ============================================================
+-- Agendamento cancelado ou expirado e pedido cancelado anulam o uso do
+-- cupom na mesma transação: o uso volta para o cupom (uses_count) e deixa
+-- de contar no limite por cliente e no dashboard. A linha fica no histórico.
+
+ALTER TABLE coupon_redemptions
+  ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_appointment
+  ON coupon_redemptions(appointment_id) WHERE voided_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_order
+  ON coupon_redemptions(order_id) WHERE voided_at IS NULL;
+
+-- ============================================================
+-- SUBSCRIPTION CARD PAYER ENCRYPTION (migration 043)
+-- ============================================================
+-- E-mail e CPF do pagador do cartão guardado passam a ser gravados
+-- criptografados (AES-256-GCM, base64) — o ciphertext não cabe nos
+-- VARCHAR originais. Linhas antigas em texto puro continuam legíveis e são
+-- cifradas na próxima gravação do cartão.
+
+ALTER TABLE subscription_cards
+  ALTER COLUMN payer_email TYPE TEXT,
+  ALTER COLUMN payer_cpf   TYPE TEXT;
+
 COMMIT;
@@ -60,6 +60,9 @@ type Appointment struct {
 	BarberProductID *uint              `gorm:"index"`
 	BarberProduct   *BarbershopService `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
+	// Serviços do agendamento, em ordem. O primeiro é o BarberProduct.
+	Services []AppointmentService `gorm:"foreignKey:AppointmentID"`
+
 	StartTime time.Time `gorm:"type:timestamptz;not null"`
 	EndTime   time.Time `gorm:"type:timestamptz;not null"`
 
@@ -70,6 +73,23 @@ type Appointment struct {
 	Notes          string `gorm:"size:255"`
 	RescheduleCount int    `gorm:"not null;default:0"`
 
+	// Barbeiro escolhido pelo sistema (cliente pediu "qualquer barbeiro").
+	AutoAssigned bool `gorm:"not null;default:false"`
+
+	// Série recorrente de origem (nil = agendamento avulso).
+	SeriesID *uint `gorm:"index"`
+
+	// Combo agendado (nil = serviços avulsos). Os preços das linhas já vêm
+	// rateados pelo preço do combo.
+	ComboID *uint `gorm:"index"`
+
+	// Cupom aplicado no booking: DiscountCents sai da cobrança do agendamento.
+	CouponID      *uint `gorm:"index"`
+	DiscountCents int64 `gorm:"type:bigint;not null;default:0"`
+
+	// Evento espelhado no Google Calendar do barbeiro (nil = não sincronizado).
+	GoogleEventID *string `gorm:"size:1024"`
+
 	// Subscription coverage snapshot — decidido no booking, não muda depois
 	SubscriptionID          *uint                     `gorm:"index"`
 	Subscription            *Subscription             `gorm:"constraint:OnDelete:SET NULL;"`
@@ -84,3 +104,22 @@ type Appointment struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// ReservedCuts é quantos cortes da assinatura o agendamento tem reservados:
+// um por serviço coberto. Agendamentos sem linhas de serviço reservam no
+// máximo um (ReservedSubscriptionCut).
+func (a *Appointment) ReservedCuts() int {
+	if !a.ReservedSubscriptionCut {
+		return 0
+	}
+	n := 0
+	for _, s := range a.Services {
+		if s.ReservedCut {
+			n++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
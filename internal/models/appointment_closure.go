@@ -21,6 +21,14 @@ type AppointmentClosure struct {
 	SubscriptionPlanID        *uint
 
 	SubscriptionCovered    bool `gorm:"not null"`
+	// Parte do valor coberta pela assinatura quando só alguns serviços do
+	// agendamento são cobertos (SubscriptionCovered = false).
+	SubscriptionCoveredCents int64 `gorm:"type:bigint;not null;default:0"`
+	// Pacote pré-pago usado na conclusão: créditos consumidos e o valor dos
+	// serviços que eles cobriram.
+	ClientPackageID     *uint `gorm:"index"`
+	PackageCreditsUsed  int   `gorm:"not null;default:0"`
+	PackageCoveredCents int64 `gorm:"type:bigint;not null;default:0"`
 	RequiresNormalCharging bool `gorm:"not null"`
 	ConfirmNormalCharging  bool `gorm:"not null"`
 
@@ -29,7 +37,7 @@ type AppointmentClosure struct {
 	// Sprint 6: fechamento operacional real
 	ActualServiceID   *uint  `gorm:"index"`
 	ActualServiceName string `gorm:"size:150"`
-	PaymentMethod     string `gorm:"size:20"` // cash|card|pix|subscription
+	PaymentMethod     string `gorm:"size:20"` // cash|card|pix|subscription|package
 	AdditionalOrderID *uint  `gorm:"index"`
 	SuggestionRemoved bool   `gorm:"not null;default:false"`
 
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	ReminderChannelEmail    = "email"
+	ReminderChannelWhatsApp = "whatsapp"
+)
+
+// AppointmentReminder registra um lembrete já enviado (ou em envio) para um
+// agendamento. A combinação (appointment, offset, canal) é única — é ela que
+// impede o mesmo lembrete de sair duas vezes.
+type AppointmentReminder struct {
+	ID            uint      `gorm:"primaryKey"`
+	BarbershopID  uint      `gorm:"not null;index"`
+	AppointmentID uint      `gorm:"not null;uniqueIndex:uq_appointment_reminder"`
+	OffsetMinutes int       `gorm:"not null;uniqueIndex:uq_appointment_reminder"`
+	Channel       string    `gorm:"size:20;not null;uniqueIndex:uq_appointment_reminder"`
+	SentAt        time.Time `gorm:"not null"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Status de uma série recorrente.
+const (
+	AppointmentSeriesStatusActive    = "active"
+	AppointmentSeriesStatusCancelled = "cancelled"
+)
+
+// AppointmentSeries é o padrão de um agendamento recorrente de cliente fixo:
+// a cada IntervalWeeks semanas, no dia da semana de StartDate, às TimeOfDay
+// (horário local da barbearia), até UntilDate ou por OccurrenceCount
+// ocorrências. As ocorrências são appointments com SeriesID.
+type AppointmentSeries struct {
+	ID           uint `gorm:"primaryKey" json:"id"`
+	BarbershopID uint `gorm:"not null;index" json:"barbershop_id"`
+	BarberID     uint `gorm:"not null" json:"barber_id"`
+	ClientID     uint `gorm:"not null" json:"client_id"`
+	ServiceID    uint `gorm:"not null" json:"service_id"`
+
+	IntervalWeeks   int        `gorm:"not null;default:1" json:"interval_weeks"`
+	StartDate       time.Time  `gorm:"type:date;not null" json:"start_date"`
+	TimeOfDay       string     `gorm:"size:5;not null" json:"time_of_day"` // "HH:MM"
+	UntilDate       *time.Time `gorm:"type:date" json:"until_date,omitempty"`
+	OccurrenceCount *int       `json:"occurrence_count,omitempty"`
+
+	Notes  string `gorm:"size:255;not null;default:''" json:"notes"`
+	Status string `gorm:"size:20;not null;default:'active'" json:"status"`
+
+	CreatedByUserID *uint `json:"created_by_user_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (AppointmentSeries) TableName() string {
+	return "appointment_series"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// AppointmentService é um serviço de um agendamento com vários serviços
+// (ex.: corte + barba), na ordem de execução (Position). Guarda o snapshot do
+// booking: nome, preço e duração do barbeiro e a cobertura da assinatura da
+// linha — cada serviço coberto reserva um corte (ReservedCut).
+type AppointmentService struct {
+	ID uint `gorm:"primaryKey"`
+
+	AppointmentID uint `gorm:"not null;uniqueIndex:uq_appointment_service_position"`
+	BarbershopID  uint `gorm:"not null;index"`
+	ServiceID     *uint
+	Position      int `gorm:"not null;default:0;uniqueIndex:uq_appointment_service_position"`
+
+	ServiceName string `gorm:"size:150;not null;default:''"`
+	PriceCents  int64  `gorm:"type:bigint;not null;default:0"`
+	DurationMin int    `gorm:"not null"`
+
+	CoverageStatus AppointmentCoverageStatus `gorm:"type:coverage_status;not null;default:'none'"`
+	ReservedCut    bool                      `gorm:"not null;default:false"`
+
+	CreatedAt time.Time
+}
+
+func (AppointmentService) TableName() string {
+	return "appointment_services"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// BarberBusyPeriod é um horário ocupado por um evento pessoal do barbeiro no
+// Google Calendar. Bloqueia a agenda como o almoço.
+type BarberBusyPeriod struct {
+	ID              uint      `gorm:"primaryKey"`
+	BarbershopID    uint      `gorm:"not null"`
+	BarberID        uint      `gorm:"not null"`
+	ExternalEventID string    `gorm:"size:1024;not null"`
+	StartTime       time.Time `gorm:"type:timestamptz;not null"`
+	EndTime         time.Time `gorm:"type:timestamptz;not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (BarberBusyPeriod) TableName() string { return "barber_busy_periods" }
@@ -12,6 +12,10 @@ type BarberGoogleToken struct {
 	AccessToken  string    `gorm:"type:text;not null"`
 	RefreshToken string    `gorm:"type:text;not null"`
 	TokenExpiry  time.Time `gorm:"not null"`
+	// SyncToken é o nextSyncToken do último events.list (sync incremental
+	// dos horários ocupados); nil força uma sincronização completa.
+	SyncToken    *string
+	BusySyncedAt *time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
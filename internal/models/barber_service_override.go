@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// BarberServiceOverride personaliza um serviço da barbearia para um barbeiro:
+// preço e/ou duração próprios, ou Offered = false quando ele não faz o serviço.
+// Campos nil herdam os valores de BarbershopService.
+type BarberServiceOverride struct {
+	ID           uint `gorm:"primaryKey"`
+	BarbershopID uint `gorm:"not null;index"`
+	BarberID     uint `gorm:"not null;uniqueIndex:uq_barber_service_override"`
+	ServiceID    uint `gorm:"not null;uniqueIndex:uq_barber_service_override"`
+
+	// Sem default no GORM: com ele, Offered = false (valor zero) ficaria
+	// fora do INSERT e o banco gravaria true. O default fica no schema.
+	Offered     bool   `gorm:"not null"`
+	Price       *int64 `gorm:"type:bigint"`
+	DurationMin *int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (BarberServiceOverride) TableName() string {
+	return "barber_service_overrides"
+}
+
+// ApplyTo devolve uma cópia do serviço com o preço e a duração do barbeiro.
+func (o *BarberServiceOverride) ApplyTo(svc *BarbershopService) *BarbershopService {
+	effective := *svc
+	if o == nil {
+		return &effective
+	}
+	if o.Price != nil {
+		effective.Price = *o.Price
+	}
+	if o.DurationMin != nil {
+		effective.DurationMin = *o.DurationMin
+	}
+	return &effective
+}
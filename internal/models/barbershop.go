@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type Barbershop struct {
 	ID                uint                     `gorm:"primaryKey"`
@@ -22,6 +27,15 @@ type Barbershop struct {
 	PhotoURL          *string                  `gorm:"size:512"`
 	PaymentConfig     *BarbershopPaymentConfig `gorm:"constraint:OnDelete:CASCADE;"`
 
+	// Lembretes automáticos (AppointmentReminderJob).
+	// ReminderOffsetsMinutes: minutos antes do start_time em que cada lembrete é enviado.
+	RemindersEnabled       bool     `gorm:"not null;default:true"`
+	ReminderOffsetsMinutes IntSlice `gorm:"type:text;not null;default:'[1440,120]'"`
+
+	// Estratégia para atribuir o barbeiro quando o cliente escolhe "qualquer um":
+	// least_loaded | round_robin | preferred.
+	BarberAssignmentStrategy string `gorm:"size:20;not null;default:'least_loaded'"`
+
 	// SaaS billing
 	Status                string     `gorm:"size:30;not null;default:'trial'"`
 	TrialEndsAt           *time.Time `gorm:"index"`
@@ -30,3 +44,45 @@ type Barbershop struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Estratégias de atribuição automática de barbeiro.
+const (
+	BarberAssignmentLeastLoaded = "least_loaded" // menos agendamentos no dia
+	BarberAssignmentRoundRobin  = "round_robin"  // rodízio pela ordem de cadastro
+	BarberAssignmentPreferred   = "preferred"    // barbeiro habitual do cliente
+)
+
+// IntSlice persists []int as JSON text in PostgreSQL.
+type IntSlice []int
+
+func (s IntSlice) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (s *IntSlice) Scan(value interface{}) error {
+	if value == nil {
+		*s = IntSlice{}
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("IntSlice: cannot scan type %T", value)
+	}
+	if len(b) == 0 || string(b) == "null" {
+		*s = IntSlice{}
+		return nil
+	}
+	return json.Unmarshal(b, s)
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CalendarFeed é o feed .ics assinável de um barbeiro ou, com BarberID nil,
+// da barbearia inteira. Token é o segredo da URL.
+type CalendarFeed struct {
+	ID           uint   `gorm:"primaryKey"`
+	BarbershopID uint   `gorm:"not null"`
+	BarberID     *uint  `gorm:"index"`
+	Token        string `gorm:"size:64;not null;uniqueIndex"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (CalendarFeed) TableName() string { return "calendar_feeds" }
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+const (
+	CampaignStatusDraft     = "draft"
+	CampaignStatusScheduled = "scheduled"
+	CampaignStatusSending   = "sending"
+	CampaignStatusCompleted = "completed"
+	CampaignStatusCancelled = "cancelled"
+)
+
+const (
+	CampaignRecipientPending   = "pending"
+	CampaignRecipientSent      = "sent"
+	CampaignRecipientFailed    = "failed"
+	CampaignRecipientSkipped   = "skipped"
+	CampaignRecipientCancelled = "cancelled"
+)
+
+// ClientSegment é um filtro de clientes salvo pela barbearia. Filters é o
+// JSON de campaign.SegmentFilters.
+type ClientSegment struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"barbershop_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	Filters      string `gorm:"type:jsonb;not null;default:'{}'" json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ClientSegment) TableName() string { return "client_segments" }
+
+// Campaign é uma mensagem enviada aos clientes de um segmento por um canal.
+// Body (e Subject, no email) aceitam os placeholders da campanha.
+type Campaign struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"barbershop_id"`
+	SegmentID    *uint  `json:"segment_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	Channel      string `gorm:"size:20;not null" json:"channel"`
+	Subject      string `gorm:"size:200;not null;default:''" json:"subject,omitempty"`
+	Body         string `gorm:"type:text;not null" json:"body"`
+	Status       string `gorm:"size:20;not null;default:draft" json:"status"`
+
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Campaign) TableName() string { return "campaigns" }
+
+// CampaignRecipient é um cliente da campanha, gravado quando ela começa.
+// Destination é o email ou telefone usado no envio.
+type CampaignRecipient struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	CampaignID       uint       `gorm:"not null;index" json:"campaign_id"`
+	BarbershopID     uint       `gorm:"not null" json:"-"`
+	ClientID         uint       `gorm:"not null" json:"client_id"`
+	Destination      string     `gorm:"size:100;not null;default:''" json:"destination"`
+	Status           string     `gorm:"size:20;not null;default:pending" json:"status"`
+	Error            *string    `gorm:"size:255" json:"error,omitempty"`
+	UnsubscribeToken string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	SentAt           *time.Time `json:"sent_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (CampaignRecipient) TableName() string { return "campaign_recipients" }
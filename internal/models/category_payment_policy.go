@@ -6,7 +6,7 @@ type CategoryPaymentPolicy struct {
 	ID           uint `gorm:"primaryKey"`
 	BarbershopID uint `gorm:"index;not null"`
 
-	Category    ClientCategory     `gorm:"type:client_category;not null"`
+	Category    ClientCategory     `gorm:"type:varchar(30);not null"`
 	Requirement PaymentRequirement `gorm:"type:payment_requirement;not null"`
 
 	CreatedAt time.Time
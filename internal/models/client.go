@@ -16,6 +16,15 @@ type Client struct {
 	AnonymizedAt     *time.Time `gorm:"column:anonymized_at"`
 	AnonymizedReason *string    `gorm:"size:50;column:anonymized_reason"`
 
+	// Preenchido quando o cliente pede para não receber campanhas; os avisos
+	// dos agendamentos continuam.
+	MarketingOptOutAt *time.Time `gorm:"column:marketing_opt_out_at"`
+
+	// Preenchidos quando o cadastro foi juntado a outro (duplicado); ver
+	// ClientMerge.
+	MergedIntoID *uint      `gorm:"column:merged_into_id"`
+	MergedAt     *time.Time `gorm:"column:merged_at"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
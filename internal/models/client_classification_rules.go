@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ClientClassificationRules guarda, em JSON, a lista ordenada de regras de
+// classificação de clientes da barbearia. Rules nil = regras padrão.
+type ClientClassificationRules struct {
+	BarbershopID      uint    `gorm:"primaryKey"`
+	Rules             *string `gorm:"type:jsonb"`
+	ReclassifyPending bool    `gorm:"not null;default:true"`
+	ReclassifiedAt    *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (ClientClassificationRules) TableName() string { return "client_classification_rules" }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ClientMerge registra a junção de um cadastro duplicado (Source) no
+// cadastro mantido (Target). Snapshot é o JSON com o estado anterior e os
+// registros movidos, usado para desfazer a junção.
+type ClientMerge struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID   uint   `gorm:"not null;index" json:"barbershop_id"`
+	TargetClientID uint   `gorm:"not null" json:"target_client_id"`
+	SourceClientID uint   `gorm:"not null" json:"source_client_id"`
+	MergedByUserID *uint  `json:"merged_by_user_id,omitempty"`
+	Snapshot       string `gorm:"type:jsonb;not null" json:"-"`
+
+	CreatedAt      time.Time  `json:"created_at"`
+	UndoneAt       *time.Time `json:"undone_at,omitempty"`
+	UndoneByUserID *uint      `json:"undone_by_user_id,omitempty"`
+}
+
+func (ClientMerge) TableName() string { return "client_merges" }
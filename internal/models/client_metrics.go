@@ -26,7 +26,7 @@ type ClientMetrics struct {
 	LastLateCanceledAt    *time.Time
 	LastLateRescheduledAt *time.Time
 
-	Category                 ClientCategory     `gorm:"type:client_category;not null;default:'new'"`
+	Category                 ClientCategory     `gorm:"type:varchar(30);not null;default:'new'"`
 	CategorySource           CategorySourceType `gorm:"type:category_source_type;not null;default:'auto'"`
 	ManualCategoryExpiresAt  *time.Time
 
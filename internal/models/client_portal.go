@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	ClientLoginChannelWhatsApp = "whatsapp"
+	ClientLoginChannelEmail    = "email"
+)
+
+// ClientLoginCode é o código de uso único do login sem senha do portal.
+// Só o hash do código é guardado.
+type ClientLoginCode struct {
+	ID           uint      `gorm:"primaryKey"`
+	BarbershopID uint      `gorm:"not null;index"`
+	ClientID     uint      `gorm:"not null;index"`
+	Channel      string    `gorm:"size:10;not null"`
+	CodeHash     string    `gorm:"size:64;not null"`
+	Attempts     int       `gorm:"not null;default:0"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+func (ClientLoginCode) TableName() string { return "client_login_codes" }
+
+// ClientSession é a sessão do cliente no portal, independente do JWT da
+// equipe. Só o hash do token é guardado.
+type ClientSession struct {
+	ID           uint      `gorm:"primaryKey"`
+	BarbershopID uint      `gorm:"not null;index"`
+	ClientID     uint      `gorm:"not null;index"`
+	TokenHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	LastSeenAt   *time.Time
+	CreatedAt    time.Time
+}
+
+func (ClientSession) TableName() string { return "client_sessions" }
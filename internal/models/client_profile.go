@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+const (
+	ClientPhotoBefore = "before"
+	ClientPhotoAfter  = "after"
+)
+
+// ClientNote é uma anotação livre da equipe sobre o cliente. Pinned
+// aparece no card do Painel do Dia.
+type ClientNote struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"-"`
+	ClientID     uint   `gorm:"not null" json:"client_id"`
+	AuthorUserID *uint  `json:"author_user_id,omitempty"`
+	Body         string `gorm:"type:text;not null" json:"body"`
+	Pinned       bool   `gorm:"not null;default:false" json:"pinned"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ClientNote) TableName() string { return "client_notes" }
+
+// ClientTag é uma etiqueta definida pela barbearia. Color é #rrggbb ou vazio.
+type ClientTag struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"-"`
+	Name         string `gorm:"size:40;not null" json:"name"`
+	Color        string `gorm:"size:7;not null;default:''" json:"color"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ClientTag) TableName() string { return "client_tags" }
+
+type ClientTagAssignment struct {
+	ID           uint `gorm:"primaryKey"`
+	BarbershopID uint `gorm:"not null"`
+	ClientID     uint `gorm:"not null"`
+	TagID        uint `gorm:"not null"`
+
+	CreatedAt time.Time
+}
+
+func (ClientTagAssignment) TableName() string { return "client_tag_assignments" }
+
+// ClientPreferences são as preferências estruturadas do cliente, uma linha
+// por cliente. Os ids favoritos são arrays JSON.
+type ClientPreferences struct {
+	ID                 uint `gorm:"primaryKey"`
+	BarbershopID       uint `gorm:"not null"`
+	ClientID           uint `gorm:"not null;uniqueIndex"`
+	PreferredBarberID  *uint
+	Allergies          string `gorm:"size:500;not null;default:''"`
+	FavoriteServiceIDs string `gorm:"type:jsonb;not null;default:'[]'"`
+	FavoriteProductIDs string `gorm:"type:jsonb;not null;default:'[]'"`
+	Beverage           string `gorm:"size:100;not null;default:''"`
+	UpdatedByUserID    *uint
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (ClientPreferences) TableName() string { return "client_preferences" }
+
+// ClientPhoto é uma foto de antes ou depois de um atendimento.
+type ClientPhoto struct {
+	ID               uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID     uint   `gorm:"not null;index" json:"-"`
+	ClientID         uint   `gorm:"not null" json:"client_id"`
+	AppointmentID    uint   `gorm:"not null" json:"appointment_id"`
+	Kind             string `gorm:"size:10;not null" json:"kind"`
+	URL              string `gorm:"size:500;not null" json:"url"`
+	UploadedByUserID *uint  `json:"uploaded_by_user_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ClientPhoto) TableName() string { return "client_photos" }
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+const (
+	CouponDiscountPercent = "percent"
+	CouponDiscountFixed   = "fixed"
+)
+
+// Onde o cupom vale. CouponTargets restringe a serviços, produtos ou planos
+// específicos do escopo.
+const (
+	CouponAppliesToAll      = "all"
+	CouponAppliesToServices = "services"
+	CouponAppliesToProducts = "products"
+	CouponAppliesToPlans    = "plans"
+)
+
+// Coupon é um código promocional da barbearia.
+type Coupon struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"barbershop_id"`
+	Code         string `gorm:"size:40;not null" json:"code"`
+	Description  string `gorm:"size:255;not null;default:''" json:"description"`
+
+	DiscountType  string `gorm:"size:10;not null" json:"discount_type"`
+	DiscountValue int64  `gorm:"not null" json:"discount_value"` // percent: 1..100 | fixed: centavos
+
+	AppliesTo string `gorm:"size:10;not null;default:'all'" json:"applies_to"`
+	TargetIDs []uint `gorm:"-" json:"target_ids"`
+
+	StartsAt         *time.Time `json:"starts_at,omitempty"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	MaxUses          *int       `json:"max_uses,omitempty"`
+	MaxUsesPerClient *int       `json:"max_uses_per_client,omitempty"`
+	MinOrderCents    int64      `gorm:"not null;default:0" json:"min_order_cents"`
+	FirstVisitOnly   bool       `gorm:"not null;default:false" json:"first_visit_only"`
+	Active           bool       `gorm:"not null;default:true" json:"active"`
+
+	UsesCount int `gorm:"not null;default:0" json:"uses_count"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Coupon) TableName() string { return "coupons" }
+
+// CouponRedemption é um uso do cupom, vinculado ao agendamento, pedido ou
+// assinatura em que o desconto foi aplicado.
+type CouponRedemption struct {
+	ID             uint  `gorm:"primaryKey" json:"id"`
+	BarbershopID   uint  `gorm:"not null" json:"-"`
+	CouponID       uint  `gorm:"not null" json:"coupon_id"`
+	ClientID       *uint `json:"client_id,omitempty"`
+	AppointmentID  *uint `json:"appointment_id,omitempty"`
+	OrderID        *uint `json:"order_id,omitempty"`
+	SubscriptionID *uint `json:"subscription_id,omitempty"`
+	DiscountCents  int64 `gorm:"not null;default:0" json:"discount_cents"`
+
+	// VoidedAt: a compra não se concretizou (cancelamento, pagamento
+	// expirado) e o uso voltou para o cupom.
+	VoidedAt *time.Time `json:"voided_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CouponRedemption) TableName() string { return "coupon_redemptions" }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// InventoryMovement é um lançamento do razão de estoque. Quantity é assinada
+// (entrada > 0, saída < 0) e StockAfter é o saldo do produto logo após o
+// lançamento.
+type InventoryMovement struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null" json:"-"`
+	ProductID    uint   `gorm:"not null" json:"product_id"`
+	Type         string `gorm:"size:20;not null" json:"type"`
+
+	Quantity      int    `gorm:"not null" json:"quantity"`
+	StockAfter    int    `gorm:"not null" json:"stock_after"`
+	UnitCostCents *int64 `json:"unit_cost_cents,omitempty"`
+
+	OrderID       *uint  `json:"order_id,omitempty"`
+	AppointmentID *uint  `json:"appointment_id,omitempty"`
+	UserID        *uint  `json:"user_id,omitempty"`
+	Note          string `gorm:"size:255;not null;default:''" json:"note"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (InventoryMovement) TableName() string { return "inventory_movements" }
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// LoyaltyProgram é a configuração do programa de fidelidade da barbearia.
+// ExpiryMonths = 0: os pontos não expiram. ExpiryMonths não tem default no
+// GORM: com ele, o 0 (valor zero) ficaria fora do INSERT e o banco gravaria
+// 12. O default fica no schema.
+type LoyaltyProgram struct {
+	BarbershopID  uint `gorm:"primaryKey;autoIncrement:false" json:"barbershop_id"`
+	Active        bool `gorm:"not null;default:false" json:"active"`
+	PointsPerReal int  `gorm:"not null;default:1" json:"points_per_real"`
+	ExpiryMonths  int  `gorm:"not null" json:"expiry_months"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (LoyaltyProgram) TableName() string { return "loyalty_programs" }
+
+const (
+	LoyaltyRewardFreeService = "free_service"
+	LoyaltyRewardDiscount    = "discount"
+)
+
+// LoyaltyReward é uma recompensa do catálogo, trocada por pontos no
+// fechamento do atendimento. free_service zera um serviço (ServiceID, ou
+// qualquer um quando nil); discount abate DiscountCents.
+type LoyaltyReward struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID  uint   `gorm:"not null;index" json:"barbershop_id"`
+	Name          string `gorm:"size:100;not null" json:"name"`
+	Kind          string `gorm:"size:20;not null" json:"kind"`
+	PointsCost    int    `gorm:"not null" json:"points_cost"`
+	ServiceID     *uint  `json:"service_id,omitempty"`
+	DiscountCents int64  `gorm:"not null;default:0" json:"discount_cents"`
+	Active        bool   `gorm:"not null;default:true" json:"active"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (LoyaltyReward) TableName() string { return "loyalty_rewards" }
+
+const (
+	LoyaltyEntryEarn    = "earn"
+	LoyaltyEntryRedeem  = "redeem"
+	LoyaltyEntryReverse = "reverse"
+	LoyaltyEntryExpire  = "expire"
+)
+
+// LoyaltyEntry é uma linha do extrato de pontos. Points é positivo no earn
+// e negativo nos débitos; no earn, RemainingPoints é o que ainda resta do
+// lote para consumir ou expirar.
+type LoyaltyEntry struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	BarbershopID    uint       `gorm:"not null" json:"-"`
+	ClientID        uint       `gorm:"not null" json:"client_id"`
+	Kind            string     `gorm:"size:10;not null" json:"kind"`
+	Points          int        `gorm:"not null" json:"points"`
+	RemainingPoints int        `gorm:"not null;default:0" json:"remaining_points"`
+	AmountCents     int64      `gorm:"not null;default:0" json:"amount_cents"`
+	AppointmentID   *uint      `json:"appointment_id,omitempty"`
+	OrderID         *uint      `json:"order_id,omitempty"`
+	RewardID        *uint      `json:"reward_id,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (LoyaltyEntry) TableName() string { return "loyalty_entries" }
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Mensagens ao cliente que a barbearia pode personalizar.
+const (
+	NotificationKindAppointmentConfirmed   = "appointment_confirmed"
+	NotificationKindAppointmentCancelled   = "appointment_cancelled"
+	NotificationKindAppointmentRescheduled = "appointment_rescheduled"
+	NotificationKindPaymentConfirmed       = "payment_confirmed"
+	NotificationKindAppointmentReminder    = "appointment_reminder"
+)
+
+const (
+	NotificationChannelEmail    = "email"
+	NotificationChannelWhatsApp = "whatsapp"
+)
+
+// NotificationTemplate é o texto personalizado de uma mensagem em um canal.
+// Subject só vale para email. Sem template salvo, vale o texto padrão.
+type NotificationTemplate struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"barbershop_id"`
+	Kind         string `gorm:"size:40;not null" json:"kind"`
+	Channel      string `gorm:"size:20;not null" json:"channel"`
+	Subject      string `gorm:"size:200;not null;default:''" json:"subject,omitempty"`
+	Body         string `gorm:"type:text;not null" json:"body"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (NotificationTemplate) TableName() string { return "notification_templates" }
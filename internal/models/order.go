@@ -32,6 +32,9 @@ type Order struct {
 	DiscountAmount int64 `gorm:"type:bigint;not null;default:0"`
 	TotalAmount    int64 `gorm:"type:bigint;not null;default:0"`
 
+	// Cupom que gerou o DiscountAmount (nil = sem cupom).
+	CouponID *uint `gorm:"index"`
+
 	Items []OrderItem `gorm:"foreignKey:OrderID"`
 
 	CreatedAt time.Time
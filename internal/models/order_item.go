@@ -11,4 +11,7 @@ type OrderItem struct {
 	Quantity  int   `gorm:"not null"`
 	UnitPrice int64 `gorm:"type:bigint;not null"`
 	LineTotal int64 `gorm:"type:bigint;not null"`
+
+	// ReturnedQuantity: unidades já devolvidas (cancelamento ou devolução parcial).
+	ReturnedQuantity int `gorm:"not null;default:0"`
 }
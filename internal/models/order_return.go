@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	OrderReturnKindCancellation = "cancellation"
+	OrderReturnKindReturn       = "return"
+)
+
+// Situação do reembolso da devolução.
+const (
+	OrderRefundNone           = "none"            // nada a devolver
+	OrderRefundRefunded       = "refunded"        // estornado pelo gateway
+	OrderRefundManualPending  = "manual_pending"  // devolver por fora (dinheiro, maquininha)
+	OrderRefundManualRefunded = "manual_refunded" // devolvido por fora
+)
+
+// OrderReturn registra o cancelamento de um pedido pago ou a devolução de
+// parte dos itens, com o motivo e o valor devolvido ao cliente.
+type OrderReturn struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null" json:"-"`
+	OrderID      uint   `gorm:"not null;index" json:"order_id"`
+	UserID       *uint  `json:"user_id,omitempty"`
+	Kind         string `gorm:"size:20;not null" json:"kind"`
+	Reason       string `gorm:"size:255;not null" json:"reason"`
+	AmountCents  int64  `gorm:"not null" json:"amount_cents"`
+
+	PaymentID    *uint      `json:"payment_id,omitempty"`
+	RefundStatus string     `gorm:"size:20;not null" json:"refund_status"`
+	RefundedAt   *time.Time `json:"refunded_at,omitempty"`
+
+	Items []OrderReturnItem `gorm:"foreignKey:OrderReturnID" json:"items"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (OrderReturn) TableName() string { return "order_returns" }
+
+type OrderReturnItem struct {
+	ID            uint  `gorm:"primaryKey" json:"id"`
+	OrderReturnID uint  `gorm:"not null" json:"-"`
+	OrderItemID   uint  `gorm:"not null" json:"order_item_id"`
+	ProductID     uint  `gorm:"not null" json:"product_id"`
+	Quantity      int   `gorm:"not null" json:"quantity"`
+	AmountCents   int64 `gorm:"not null" json:"amount_cents"`
+}
+
+func (OrderReturnItem) TableName() string { return "order_return_items" }
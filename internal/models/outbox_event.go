@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// OutboxEvent é um efeito colateral a entregar depois do commit: uma
+// notificação em um canal, uma sincronização de agenda ou um registro de
+// auditoria. Payload guarda, em JSON, a entrada do destino.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	BarbershopID  uint       `gorm:"not null;index" json:"-"`
+	Topic         string     `gorm:"size:50;not null" json:"topic"`
+	Channel       string     `gorm:"size:20;not null" json:"channel"`
+	Reference     string     `gorm:"size:60;not null" json:"reference"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"-"`
+	Status        string     `gorm:"size:20;not null" json:"status"`
+	Attempts      int        `gorm:"not null" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	LastError     *string    `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (OutboxEvent) TableName() string { return "outbox_events" }
@@ -15,6 +15,11 @@ type Payment struct {
 	BundledOrderID *uint         `gorm:"column:bundled_order_id;index"`
 	SubscriptionID *uint         `gorm:"index"`
 	Subscription   *Subscription `gorm:"constraint:OnDelete:SET NULL;"`
+	// ClientPackageID: pagamento da compra de um pacote pré-pago.
+	ClientPackageID *uint `gorm:"index"`
+	// SubscriptionRenewal marca a cobrança de renovação de uma assinatura já
+	// ativa (cartão guardado), em vez da compra.
+	SubscriptionRenewal bool `gorm:"not null;default:false"`
 	TxID              *string `gorm:"column:txid;size:100;uniqueIndex"`
 	MPPaymentID       *int64  `gorm:"column:mp_payment_id;index"`
 	// Provider identifica o gateway que criou este pagamento ("mercadopago", "pagbank").
@@ -28,6 +33,10 @@ type Payment struct {
 	Amount        int64         `gorm:"type:bigint;not null"`
 	Status        PaymentStatus `gorm:"type:payment_status;not null"`
 	PaidAt        *time.Time
+	// RefundedAmount acumula o total devolvido ao cliente (estornos + chargeback).
+	// O detalhe de cada devolução fica em payment_refunds.
+	RefundedAmount int64      `gorm:"type:bigint;not null;default:0"`
+	RefundedAt     *time.Time
 	ExpiresAt     *time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
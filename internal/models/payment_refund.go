@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	PaymentRefundKindRefund     = "refund"
+	PaymentRefundKindChargeback = "chargeback"
+)
+
+// PaymentRefund registra cada devolução de dinheiro de um pagamento — estorno
+// solicitado pela barbearia, estorno feito direto no painel do provider ou
+// chargeback aberto pelo cliente no cartão.
+type PaymentRefund struct {
+	ID               uint    `gorm:"primaryKey"`
+	BarbershopID     uint    `gorm:"index;not null"`
+	PaymentID        uint    `gorm:"index;not null"`
+	Payment          *Payment `gorm:"constraint:OnDelete:CASCADE;"`
+	Kind             string  `gorm:"size:20;not null"`
+	Amount           int64   `gorm:"type:bigint;not null"`
+	ProviderRefundID *string `gorm:"size:100"`
+	Reason           string  `gorm:"type:text"`
+	CreatedAt        time.Time
+}
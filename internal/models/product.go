@@ -17,6 +17,11 @@ type Product struct {
 	OnlineVisible bool    `gorm:"not null;default:false"`
 	ImageURL      *string `gorm:"size:512"`
 
+	// LowStockThreshold: avisa o dono quando Stock chega a esse valor (nil = sem alerta).
+	// LowStockNotifiedAt marca o aviso já enviado; zera quando o estoque volta a subir.
+	LowStockThreshold  *int
+	LowStockNotifiedAt *time.Time
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const (
+	ReportDeliverySent    = "sent"
+	ReportDeliveryPartial = "partial"
+	ReportDeliveryFailed  = "failed"
+)
+
+const (
+	ReportTriggerSchedule = "schedule"
+	ReportTriggerManual   = "manual"
+)
+
+// ReportSubscription envia um relatório por email a cada período, no
+// horário da barbearia. Recipients é um array JSON de emails; NextRunAt é o
+// próximo envio, em UTC.
+type ReportSubscription struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID    uint   `gorm:"not null;index" json:"-"`
+	Report          string `gorm:"size:20;not null" json:"report"`
+	Period          string `gorm:"size:10;not null" json:"period"`
+	Format          string `gorm:"size:5;not null" json:"format"`
+	Recipients      string `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+	SendTime        string `gorm:"size:5;not null;default:'08:00'" json:"send_time"`
+	Active          bool   `gorm:"not null;default:true" json:"active"`
+	CreatedByUserID *uint  `json:"created_by_user_id,omitempty"`
+
+	NextRunAt  time.Time  `gorm:"not null" json:"next_run_at"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ReportSubscription) TableName() string { return "report_subscriptions" }
+
+// ReportDelivery é um envio de relatório, agendado ou manual. PeriodStart e
+// PeriodEnd são os dias (inclusive) cobertos; Recipients é um array JSON.
+type ReportDelivery struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	BarbershopID   uint      `gorm:"not null;index" json:"-"`
+	SubscriptionID *uint     `json:"subscription_id,omitempty"`
+	Report         string    `gorm:"size:20;not null" json:"report"`
+	Period         string    `gorm:"size:10;not null" json:"period"`
+	Format         string    `gorm:"size:5;not null" json:"format"`
+	PeriodStart    time.Time `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd      time.Time `gorm:"type:date;not null" json:"period_end"`
+	Recipients     string    `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+	Trigger        string    `gorm:"size:10;not null" json:"trigger"`
+	Status         string    `gorm:"size:10;not null" json:"status"`
+	Error          *string   `gorm:"size:255" json:"error,omitempty"`
+	AttachmentName string    `gorm:"size:100;not null;default:''" json:"attachment_name"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ReportDelivery) TableName() string { return "report_deliveries" }
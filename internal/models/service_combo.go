@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ServiceCombo é um conjunto de serviços vendido com preço de pacote
+// ("corte + barba com 15% off"): preço fixo (PriceCents) ou desconto sobre a
+// soma dos preços do barbeiro (DiscountPercent) — nunca os dois.
+type ServiceCombo struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID    uint   `gorm:"not null;index" json:"barbershop_id"`
+	Name            string `gorm:"size:100;not null" json:"name"`
+	PriceCents      *int64 `gorm:"type:bigint" json:"price_cents,omitempty"`
+	DiscountPercent int    `gorm:"not null;default:0" json:"discount_percent"`
+	Active          bool   `gorm:"not null;default:true" json:"active"`
+
+	Items []ServiceComboItem `gorm:"foreignKey:ComboID" json:"items"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ServiceCombo) TableName() string {
+	return "service_combos"
+}
+
+// ServiceIDs devolve os serviços do combo na ordem de execução.
+func (c *ServiceCombo) ServiceIDs() []uint {
+	ids := make([]uint, 0, len(c.Items))
+	for _, it := range c.Items {
+		ids = append(ids, it.ServiceID)
+	}
+	return ids
+}
+
+// BundlePrice é o preço do combo dado o preço cheio dos serviços.
+func (c *ServiceCombo) BundlePrice(fullPrice int64) int64 {
+	if c.PriceCents != nil {
+		return *c.PriceCents
+	}
+	return fullPrice * int64(100-c.DiscountPercent) / 100
+}
+
+type ServiceComboItem struct {
+	ComboID   uint `gorm:"primaryKey" json:"-"`
+	ServiceID uint `gorm:"primaryKey" json:"service_id"`
+	Position  int  `gorm:"not null;default:0" json:"position"`
+}
+
+func (ServiceComboItem) TableName() string {
+	return "service_combo_items"
+}
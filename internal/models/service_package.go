@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// ServicePackage é um pacote pré-pago do catálogo ("10 cortes por R$400"):
+// Credits créditos para os serviços listados, válidos por ValidityDays a
+// partir da compra.
+type ServicePackage struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BarbershopID uint   `gorm:"not null;index" json:"barbershop_id"`
+	Name         string `gorm:"size:100;not null" json:"name"`
+	PriceCents   int64  `gorm:"type:bigint;not null" json:"price_cents"`
+	Credits      int    `gorm:"not null" json:"credits"`
+	ValidityDays int    `gorm:"not null" json:"validity_days"`
+	Active       bool   `gorm:"not null;default:true" json:"active"`
+
+	ServiceIDs []uint `gorm:"-" json:"service_ids"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ServicePackage) TableName() string {
+	return "service_packages"
+}
+
+type ClientPackageStatus string
+
+const (
+	ClientPackagePendingPayment ClientPackageStatus = "pending_payment"
+	ClientPackageActive         ClientPackageStatus = "active"
+	ClientPackageExpired        ClientPackageStatus = "expired"
+	ClientPackageCancelled      ClientPackageStatus = "cancelled"
+)
+
+// ClientPackage é um pacote comprado pelo cliente, com o snapshot do catálogo
+// no momento da compra. PurchasedAt e ExpiresAt são preenchidos quando o
+// pagamento é confirmado.
+type ClientPackage struct {
+	ID           uint                `gorm:"primaryKey" json:"id"`
+	BarbershopID uint                `gorm:"not null;index" json:"barbershop_id"`
+	ClientID     uint                `gorm:"not null;index" json:"client_id"`
+	PackageID    uint                `gorm:"not null" json:"package_id"`
+	Status       ClientPackageStatus `gorm:"size:20;not null;default:'pending_payment'" json:"status"`
+
+	PackageName  string `gorm:"size:100;not null" json:"package_name"`
+	PriceCents   int64  `gorm:"type:bigint;not null" json:"price_cents"`
+	CreditsTotal int    `gorm:"not null" json:"credits_total"`
+	CreditsUsed  int    `gorm:"not null;default:0" json:"credits_used"`
+	ValidityDays int    `gorm:"not null" json:"validity_days"`
+
+	PurchasedAt *time.Time `json:"purchased_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ClientPackage) TableName() string {
+	return "client_packages"
+}
+
+// CreditsLeft é o saldo de créditos do pacote.
+func (p *ClientPackage) CreditsLeft() int {
+	if p.CreditsUsed >= p.CreditsTotal {
+		return 0
+	}
+	return p.CreditsTotal - p.CreditsUsed
+}
+
+// Usable indica se o pacote pode cobrir um serviço em now: ativo, dentro da
+// validade e com saldo.
+func (p *ClientPackage) Usable(now time.Time) bool {
+	return p.Status == ClientPackageActive &&
+		p.ExpiresAt != nil && now.Before(*p.ExpiresAt) &&
+		p.CreditsLeft() > 0
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// StaffInvitation é o convite enviado pelo dono para um barbeiro da equipe.
+// O token é de uso único: ao aceitar, o convidado vira um User com
+// role = "barber" na barbearia do convite.
+type StaffInvitation struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	BarbershopID   uint       `gorm:"index;not null" json:"barbershop_id"`
+	InvitedBy      *uint      `json:"invited_by,omitempty"`
+	Name           string     `gorm:"size:100;not null" json:"name"`
+	Email          string     `gorm:"size:100;not null" json:"email"`
+	Token          string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *uint      `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Pending indica se o convite ainda pode ser aceito.
+func (i *StaffInvitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
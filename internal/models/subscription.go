@@ -25,6 +25,13 @@ type Subscription struct {
 	CurrentPeriodEnd       time.Time
 	CutsUsedInPeriod       int
 	CutsReservedInPeriod   int
+	// Renovação automática: RenewedPeriodEnd é o fim do próximo período já
+	// pago; GraceUntil, o fim da carência (status past_due).
+	AutoRenew              bool
+	RenewalAttempts        int
+	NextRenewalAt          *time.Time
+	RenewedPeriodEnd       *time.Time
+	GraceUntil             *time.Time
 	CreatedAt              time.Time
 	UpdatedAt              time.Time
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// SubscriptionCard é a referência do cartão guardado no cofre do provider
+// para a renovação automática de uma assinatura. CustomerRef é o cliente no
+// provider (Mercado Pago); vazio quando o provider não usa.
+//
+// PayerEmail e PayerCPF ficam criptografados no banco (AES-256-GCM, mesma
+// chave das credenciais de provider); o repositório cifra ao gravar e
+// decifra ao ler, então aqui os campos trazem sempre o texto puro.
+type SubscriptionCard struct {
+	ID             uint   `gorm:"primaryKey"`
+	BarbershopID   uint   `gorm:"not null;index"`
+	SubscriptionID uint   `gorm:"not null;uniqueIndex"`
+	Provider       string `gorm:"size:50;not null"`
+	CustomerRef    string `gorm:"size:100;not null;default:''"`
+	CardRef        string `gorm:"size:100;not null"`
+	Brand          string `gorm:"size:30;not null;default:''"`
+	LastFour       string `gorm:"size:4;not null;default:''"`
+	PayerEmail     string `gorm:"type:text;not null;default:''"`
+	PayerCPF       string `gorm:"column:payer_cpf;type:text;not null;default:''"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (SubscriptionCard) TableName() string {
+	return "subscription_cards"
+}
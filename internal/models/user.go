@@ -47,6 +47,11 @@ func (s *StringSlice) Scan(value interface{}) error {
 	return json.Unmarshal(cleaned, s)
 }
 
+const (
+	UserRoleOwner  UserRole = "owner"
+	UserRoleBarber UserRole = "barber"
+)
+
 type User struct {
 	ID           uint        `gorm:"primaryKey" json:"id"`
 	BarbershopID *uint       `json:"barbershop_id"`
@@ -58,6 +63,8 @@ type User struct {
 	Phone        string      `gorm:"size:20"`
 	Role         UserRole    `gorm:"type:user_role;not null;default:'owner'"`
 	SeenTours    StringSlice `gorm:"type:text;not null;default:'[]'" json:"seen_tours"`
+	// Active = false: barbeiro desativado pelo dono — sem login e fora do booking.
+	Active bool `gorm:"not null;default:true" json:"active"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Status de uma entrada da lista de espera.
+const (
+	WaitlistStatusWaiting   = "waiting"   // aguardando um horário
+	WaitlistStatusOffered   = "offered"   // recebeu oferta, ainda não respondeu
+	WaitlistStatusBooked    = "booked"    // aceitou a oferta e virou agendamento
+	WaitlistStatusCancelled = "cancelled" // saiu da lista
+)
+
+// WaitlistEntry registra o interesse de um cliente em um serviço quando não há
+// horário livre. Ao liberar um horário compatível (cancelamento ou pagamento
+// expirado) a entrada recebe uma oferta com prazo: Offered* guardam o horário
+// oferecido até o cliente aceitar pelo link com Token.
+type WaitlistEntry struct {
+	ID           uint  `gorm:"primaryKey" json:"id"`
+	BarbershopID uint  `gorm:"not null;index" json:"barbershop_id"`
+	ServiceID    uint  `gorm:"not null" json:"service_id"`
+	BarberID     *uint `json:"barber_id,omitempty"` // nil = qualquer barbeiro
+
+	ClientName  string `gorm:"size:100;not null" json:"client_name"`
+	ClientPhone string `gorm:"size:20" json:"client_phone,omitempty"`
+	ClientEmail string `gorm:"size:100" json:"client_email,omitempty"`
+
+	// Período de interesse (dias no calendário da barbearia).
+	DateFrom    time.Time   `gorm:"type:date;not null" json:"date_from"`
+	DateTo      time.Time   `gorm:"type:date;not null" json:"date_to"`
+	TimeWindows TimeWindows `gorm:"type:text;not null;default:'[]'" json:"time_windows"`
+
+	Status string `gorm:"size:20;not null;default:'waiting'" json:"status"`
+	Token  string `gorm:"size:64;uniqueIndex;not null" json:"-"`
+
+	OfferedStartTime *time.Time `json:"offered_start_time,omitempty"`
+	OfferedBarberID  *uint      `json:"offered_barber_id,omitempty"`
+	OfferExpiresAt   *time.Time `json:"offer_expires_at,omitempty"`
+	AppointmentID    *uint      `json:"appointment_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// OfferActive indica se a entrada tem uma oferta que ainda pode ser aceita.
+func (e *WaitlistEntry) OfferActive(now time.Time) bool {
+	return e.Status == WaitlistStatusOffered &&
+		e.OfferedStartTime != nil &&
+		e.OfferExpiresAt != nil &&
+		now.Before(*e.OfferExpiresAt)
+}
+
+// TimeWindow é uma faixa de horário preferida, em "HH:MM" no fuso da barbearia.
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// TimeWindows persists []TimeWindow as JSON text in PostgreSQL.
+// Vazio = qualquer horário.
+type TimeWindows []TimeWindow
+
+func (w TimeWindows) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (w *TimeWindows) Scan(value interface{}) error {
+	if value == nil {
+		*w = TimeWindows{}
+		return nil
+	}
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("TimeWindows: cannot scan type %T", value)
+	}
+	if len(b) == 0 || string(b) == "null" {
+		*w = TimeWindows{}
+		return nil
+	}
+	return json.Unmarshal(b, w)
+}
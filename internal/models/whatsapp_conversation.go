@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// WhatsAppConversation é o estado da conversa do bot de agendamento com um
+// número em uma instância. Data guarda, em JSON, as escolhas feitas e as
+// opções do último menu enviado.
+type WhatsAppConversation struct {
+	ID           uint      `gorm:"primaryKey"`
+	BarbershopID uint      `gorm:"not null;index"`
+	InstanceName string    `gorm:"size:100;not null"`
+	Phone        string    `gorm:"size:20;not null"`
+	State        string    `gorm:"size:30;not null"`
+	Data         string    `gorm:"type:jsonb;not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (WhatsAppConversation) TableName() string { return "whatsapp_conversations" }
@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Campanhas usam o mesmo formato dos templates personalizados, com os
+// placeholders do cliente e da barbearia. No email, o texto vai no layout
+// padrão com o link de descadastro; no WhatsApp, com a instrução do PARAR.
+
+var campaignPlaceholders = []string{
+	"client_name",
+	"barbershop_name",
+	"barbershop_phone",
+	"address",
+	"booking_link",
+}
+
+// campaignOptOutLine fecha toda mensagem de campanha no WhatsApp. O bot
+// reconhece PARAR e descadastra o número.
+const campaignOptOutLine = "_Para não receber mais mensagens como esta, responda PARAR._"
+
+// CampaignPlaceholders lista os placeholders aceitos nas campanhas.
+func CampaignPlaceholders() []string {
+	return slices.Clone(campaignPlaceholders)
+}
+
+// ValidateCampaign aplica ao texto da campanha as regras dos templates:
+// canal conhecido, assunto só no email, tamanho e placeholders da lista.
+func ValidateCampaign(channel, subject, body string) error {
+	if err := checkChannel(channel); err != nil {
+		return err
+	}
+	return validateMessage(channel, subject, body, campaignPlaceholders)
+}
+
+// CampaignVars são os valores dos placeholders para um cliente.
+// bookingURL é a página pública de agendamento da barbearia.
+func CampaignVars(clientName, barbershopName, barbershopPhone, address, bookingURL string) map[string]string {
+	return map[string]string{
+		"client_name":      clientName,
+		"barbershop_name":  barbershopName,
+		"barbershop_phone": barbershopPhone,
+		"address":          address,
+		"booking_link":     bookingURL,
+	}
+}
+
+// RenderCampaign troca os placeholders do assunto e do texto. O assunto
+// sai em uma linha só.
+func RenderCampaign(subject, body string, vars map[string]string) (string, string, error) {
+	text, err := expandTemplate(body, vars, campaignPlaceholders)
+	if err != nil {
+		return "", "", err
+	}
+	subject, err = expandTemplate(subject, vars, campaignPlaceholders)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(subject), text, nil
+}
+
+func campaignWhatsAppText(body string) string {
+	return body + "\n\n" + campaignOptOutLine
+}
+
+// PreviewCampaign renderiza a campanha como o cliente a receberia, com os
+// dados da barbearia e um cliente de exemplo.
+func PreviewCampaign(channel, subject, body string, s TemplateSample) (*RenderedTemplate, error) {
+	if err := ValidateCampaign(channel, subject, body); err != nil {
+		return nil, err
+	}
+
+	vars := CampaignVars("Maria Silva", s.BarbershopName, s.BarbershopPhone, s.BarbershopAddress,
+		s.AppURL+"/"+s.BarbershopSlug)
+	subj, text, err := RenderCampaign(subject, body, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if channel != models.NotificationChannelEmail {
+		return &RenderedTemplate{Body: campaignWhatsAppText(text)}, nil
+	}
+	html, err := customMessageHTML(s.BarbershopName, text, s.AppURL+"/descadastrar/exemplo")
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedTemplate{Subject: subj, Body: html}, nil
+}
@@ -0,0 +1,389 @@
+package notification
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+// Templates personalizados: o texto da barbearia com placeholders
+// {{client_name}}, {{date}}... trocados pelos dados da mensagem. Não é
+// html/template — o dono escolhe entre os placeholders da mensagem, sem
+// lógica nem HTML. No email, o texto vai escapado no layout padrão.
+
+var (
+	ErrTemplateUnknownKind         = errors.New("template_unknown_kind")
+	ErrTemplateUnknownChannel      = errors.New("template_unknown_channel")
+	ErrTemplateEmptyBody           = errors.New("template_empty_body")
+	ErrTemplateBodyTooLong         = errors.New("template_body_too_long")
+	ErrTemplateSubjectRequired     = errors.New("template_subject_required")
+	ErrTemplateSubjectInvalid      = errors.New("template_subject_invalid")
+	ErrTemplateUnclosedPlaceholder = errors.New("template_unclosed_placeholder")
+	ErrTemplateUnknownPlaceholder  = errors.New("template_unknown_placeholder")
+)
+
+const (
+	maxTemplateBody    = 4000
+	maxTemplateSubject = 150
+)
+
+// Assuntos padrão dos emails personalizáveis.
+const (
+	subjectAppointmentConfirmed   = "Agendamento confirmado – Corteon"
+	subjectAppointmentCancelled   = "Agendamento cancelado – Corteon"
+	subjectAppointmentRescheduled = "Agendamento remarcado – Corteon"
+	subjectAppointmentReminder    = "Lembrete de agendamento – Corteon"
+)
+
+var commonPlaceholders = []string{
+	"client_name",
+	"service",
+	"date",
+	"time",
+	"address",
+	"barbershop_name",
+	"barbershop_phone",
+}
+
+// templatePlaceholders são os placeholders aceitos em cada mensagem.
+var templatePlaceholders = map[string][]string{
+	models.NotificationKindAppointmentConfirmed:   append(slices.Clone(commonPlaceholders), "ticket_link"),
+	models.NotificationKindPaymentConfirmed:       append(slices.Clone(commonPlaceholders), "ticket_link"),
+	models.NotificationKindAppointmentCancelled:   slices.Clone(commonPlaceholders),
+	models.NotificationKindAppointmentRescheduled: append(slices.Clone(commonPlaceholders), "ticket_link", "old_date", "old_time"),
+	models.NotificationKindAppointmentReminder:    append(slices.Clone(commonPlaceholders), "ticket_link"),
+}
+
+// TemplateKinds lista as mensagens personalizáveis.
+func TemplateKinds() []string {
+	return []string{
+		models.NotificationKindAppointmentConfirmed,
+		models.NotificationKindPaymentConfirmed,
+		models.NotificationKindAppointmentCancelled,
+		models.NotificationKindAppointmentRescheduled,
+		models.NotificationKindAppointmentReminder,
+	}
+}
+
+// TemplatePlaceholders lista os placeholders aceitos na mensagem.
+func TemplatePlaceholders(kind string) []string {
+	return slices.Clone(templatePlaceholders[kind])
+}
+
+// CheckKindChannel recusa mensagem ou canal desconhecidos.
+func CheckKindChannel(kind, channel string) error {
+	if _, ok := templatePlaceholders[kind]; !ok {
+		return ErrTemplateUnknownKind
+	}
+	return checkChannel(channel)
+}
+
+func checkChannel(channel string) error {
+	if channel != models.NotificationChannelEmail && channel != models.NotificationChannelWhatsApp {
+		return ErrTemplateUnknownChannel
+	}
+	return nil
+}
+
+// ValidateTemplate recusa templates que não renderizariam: mensagem ou canal
+// desconhecidos, texto vazio ou longo demais, assunto ausente (email) ou
+// presente (WhatsApp), chaves sem fechar e placeholders fora da lista.
+func ValidateTemplate(kind, channel, subject, body string) error {
+	if err := CheckKindChannel(kind, channel); err != nil {
+		return err
+	}
+	return validateMessage(channel, subject, body, templatePlaceholders[kind])
+}
+
+// validateMessage aplica ao texto as regras de ValidateTemplate com os
+// placeholders de allowed.
+func validateMessage(channel, subject, body string, allowed []string) error {
+	if channel == models.NotificationChannelEmail {
+		if strings.TrimSpace(subject) == "" {
+			return ErrTemplateSubjectRequired
+		}
+		if strings.ContainsAny(subject, "\r\n") || utf8.RuneCountInString(subject) > maxTemplateSubject {
+			return ErrTemplateSubjectInvalid
+		}
+	} else if subject != "" {
+		return ErrTemplateSubjectInvalid
+	}
+
+	if strings.TrimSpace(body) == "" {
+		return ErrTemplateEmptyBody
+	}
+	if utf8.RuneCountInString(body) > maxTemplateBody {
+		return ErrTemplateBodyTooLong
+	}
+
+	if _, err := expandTemplate(subject, nil, allowed); err != nil {
+		return err
+	}
+	_, err := expandTemplate(body, nil, allowed)
+	return err
+}
+
+// expandTemplate troca os placeholders de text pelos valores de vars,
+// recusando os que não estão em allowed.
+func expandTemplate(text string, vars map[string]string, allowed []string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, "{{")
+		if i < 0 {
+			b.WriteString(text)
+			return b.String(), nil
+		}
+		b.WriteString(text[:i])
+
+		rest := text[i+2:]
+		j := strings.Index(rest, "}}")
+		if j < 0 {
+			return "", ErrTemplateUnclosedPlaceholder
+		}
+		name := strings.TrimSpace(rest[:j])
+		if !slices.Contains(allowed, name) {
+			return "", fmt.Errorf("%w: {{%s}}", ErrTemplateUnknownPlaceholder, name)
+		}
+		b.WriteString(vars[name])
+		text = rest[j+2:]
+	}
+}
+
+//go:embed templates/custom_message.html
+var customMessageRaw string
+
+var customMessageTmpl = template.Must(template.New("custom_message").Parse(customMessageRaw))
+
+type customMessageData struct {
+	BarbershopName string
+	Lines          []string
+	// UnsubscribeURL só nas campanhas: link para sair da lista.
+	UnsubscribeURL string
+}
+
+// renderCustom aplica o template salvo. No email devolve o assunto e o HTML
+// do layout padrão, uma linha do texto por parágrafo; no WhatsApp, o texto.
+func renderCustom(t *models.NotificationTemplate, vars map[string]string) (subject, body string, err error) {
+	allowed := templatePlaceholders[t.Kind]
+
+	text, err := expandTemplate(t.Body, vars, allowed)
+	if err != nil {
+		return "", "", err
+	}
+	if t.Channel != models.NotificationChannelEmail {
+		return "", text, nil
+	}
+
+	subject, err = expandTemplate(t.Subject, vars, allowed)
+	if err != nil {
+		return "", "", err
+	}
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	html, err := customMessageHTML(vars["barbershop_name"], text, "")
+	return subject, html, err
+}
+
+// customMessageHTML põe o texto no layout padrão, uma linha por parágrafo.
+func customMessageHTML(barbershopName, text, unsubscribeURL string) (string, error) {
+	return execTemplate(customMessageTmpl, customMessageData{
+		BarbershopName: barbershopName,
+		Lines:          strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"),
+		UnsubscribeURL: unsubscribeURL,
+	})
+}
+
+// findTemplate devolve o primeiro template personalizado da barbearia entre
+// kinds, na ordem. Sem repositório, sem template ou com erro na busca, nil:
+// vale o texto padrão.
+func findTemplate(
+	ctx context.Context,
+	repo domain.TemplateRepository,
+	barbershopID uint,
+	channel string,
+	kinds ...string,
+) *models.NotificationTemplate {
+	if repo == nil || barbershopID == 0 {
+		return nil
+	}
+	for _, kind := range kinds {
+		t, err := repo.Find(ctx, barbershopID, kind, channel)
+		if err != nil {
+			log.Printf("[TEMPLATE] lookup failed barbershop=%d kind=%s channel=%s: %v", barbershopID, kind, channel, err)
+			return nil
+		}
+		if t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// confirmedKinds: a confirmação após pagamento usa o template
+// payment_confirmed e, sem ele, o de agendamento confirmado.
+func confirmedKinds(in domain.AppointmentConfirmedInput) []string {
+	if in.Paid {
+		return []string{models.NotificationKindPaymentConfirmed, models.NotificationKindAppointmentConfirmed}
+	}
+	return []string{models.NotificationKindAppointmentConfirmed}
+}
+
+// ── valores dos placeholders ─────────────────────────────────────────────────
+
+func baseVars(clientName, service, address, barbershopName, barbershopPhone string, start time.Time) map[string]string {
+	return map[string]string{
+		"client_name":      clientName,
+		"service":          service,
+		"date":             formatDate(start),
+		"time":             formatTime(start),
+		"address":          address,
+		"barbershop_name":  barbershopName,
+		"barbershop_phone": barbershopPhone,
+	}
+}
+
+func confirmedVars(in domain.AppointmentConfirmedInput) map[string]string {
+	v := baseVars(in.ClientName, in.ServiceName, in.BarbershopAddress, in.BarbershopName, in.BarbershopPhone,
+		in.StartTime.In(timezone.Location(in.Timezone)))
+	v["ticket_link"] = in.TicketURL
+	return v
+}
+
+func cancelledVars(in domain.AppointmentCancelledInput) map[string]string {
+	return baseVars(in.ClientName, in.ServiceName, in.BarbershopAddress, in.BarbershopName, in.BarbershopPhone,
+		in.StartTime.In(timezone.Location(in.Timezone)))
+}
+
+func rescheduledVars(in domain.AppointmentRescheduledInput) map[string]string {
+	loc := timezone.Location(in.Timezone)
+	v := baseVars(in.ClientName, in.ServiceName, in.BarbershopAddress, in.BarbershopName, in.BarbershopPhone,
+		in.NewStartTime.In(loc))
+	v["ticket_link"] = in.NewTicketURL
+	v["old_date"] = formatDate(in.OldStartTime.In(loc))
+	v["old_time"] = formatTime(in.OldStartTime.In(loc))
+	return v
+}
+
+func reminderVars(in domain.AppointmentReminderInput) map[string]string {
+	v := baseVars(in.ClientName, in.ServiceName, in.BarbershopAddress, in.BarbershopName, in.BarbershopPhone,
+		in.StartTime.In(timezone.Location(in.Timezone)))
+	v["ticket_link"] = in.TicketURL
+	return v
+}
+
+// ── prévia ───────────────────────────────────────────────────────────────────
+
+// TemplateSample são os dados reais da barbearia usados na prévia; cliente,
+// serviço e horário são de exemplo.
+type TemplateSample struct {
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopAddress string
+	Timezone          string
+	AppURL            string
+	BarbershopSlug    string
+}
+
+// RenderedTemplate é a mensagem pronta. Subject só no email.
+type RenderedTemplate struct {
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+}
+
+// PreviewTemplate renderiza a mensagem com dados de exemplo. Sem body,
+// mostra o texto padrão do sistema.
+func PreviewTemplate(kind, channel, subject, body string, s TemplateSample) (*RenderedTemplate, error) {
+	if err := CheckKindChannel(kind, channel); err != nil {
+		return nil, err
+	}
+
+	loc := timezone.Location(s.Timezone)
+	day := time.Now().In(loc).AddDate(0, 0, 2)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 14, 30, 0, 0, loc)
+	end := start.Add(45 * time.Minute)
+	ticketURL := s.AppURL + "/ticket/exemplo"
+
+	var (
+		vars        map[string]string
+		emailSubj   string
+		renderEmail func() (string, error)
+		whatsapp    func() string
+	)
+
+	switch kind {
+	case models.NotificationKindAppointmentConfirmed, models.NotificationKindPaymentConfirmed:
+		in := domain.AppointmentConfirmedInput{
+			ClientName: "Maria Silva", ServiceName: "Corte + Barba",
+			BarbershopName: s.BarbershopName, BarbershopPhone: s.BarbershopPhone, BarbershopAddress: s.BarbershopAddress,
+			StartTime: start, EndTime: end, Timezone: s.Timezone, TicketURL: ticketURL,
+			Paid: kind == models.NotificationKindPaymentConfirmed,
+		}
+		vars, emailSubj = confirmedVars(in), subjectAppointmentConfirmed
+		renderEmail = func() (string, error) { return renderAppointmentConfirmed(in) }
+		whatsapp = func() string { return confirmedMessage(in) }
+
+	case models.NotificationKindAppointmentCancelled:
+		in := domain.AppointmentCancelledInput{
+			ClientName: "Maria Silva", ServiceName: "Corte + Barba",
+			BarbershopName: s.BarbershopName, BarbershopPhone: s.BarbershopPhone, BarbershopAddress: s.BarbershopAddress,
+			StartTime: start, Timezone: s.Timezone,
+		}
+		vars, emailSubj = cancelledVars(in), subjectAppointmentCancelled
+		renderEmail = func() (string, error) { return renderAppointmentCancelled(in) }
+		whatsapp = func() string { return cancelledMessage(in) }
+
+	case models.NotificationKindAppointmentRescheduled:
+		in := domain.AppointmentRescheduledInput{
+			ClientName: "Maria Silva", ServiceName: "Corte + Barba",
+			BarbershopName: s.BarbershopName, BarbershopPhone: s.BarbershopPhone, BarbershopAddress: s.BarbershopAddress,
+			OldStartTime: start.AddDate(0, 0, -1).Add(-4*time.Hour - 30*time.Minute), NewStartTime: start, NewEndTime: end,
+			Timezone: s.Timezone, NewTicketURL: ticketURL,
+		}
+		vars, emailSubj = rescheduledVars(in), subjectAppointmentRescheduled
+		renderEmail = func() (string, error) { return renderAppointmentRescheduled(in) }
+		whatsapp = func() string { return rescheduledMessage(in) }
+
+	case models.NotificationKindAppointmentReminder:
+		in := domain.AppointmentReminderInput{
+			ClientName: "Maria Silva", ServiceName: "Corte + Barba",
+			BarbershopName: s.BarbershopName, BarbershopPhone: s.BarbershopPhone, BarbershopAddress: s.BarbershopAddress,
+			StartTime: start, EndTime: end, Timezone: s.Timezone, TicketURL: ticketURL,
+		}
+		vars, emailSubj = reminderVars(in), subjectAppointmentReminder
+		renderEmail = func() (string, error) { return renderAppointmentReminder(in) }
+		whatsapp = func() string { return reminderMessage(in) }
+	}
+
+	if body != "" {
+		if err := ValidateTemplate(kind, channel, subject, body); err != nil {
+			return nil, err
+		}
+		subj, out, err := renderCustom(&models.NotificationTemplate{
+			Kind: kind, Channel: channel, Subject: subject, Body: body,
+		}, vars)
+		if err != nil {
+			return nil, err
+		}
+		return &RenderedTemplate{Subject: subj, Body: out}, nil
+	}
+
+	if channel == models.NotificationChannelWhatsApp {
+		return &RenderedTemplate{Body: whatsapp()}, nil
+	}
+	html, err := renderEmail()
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedTemplate{Subject: emailSubj, Body: html}, nil
+}
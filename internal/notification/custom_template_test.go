@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	const (
+		confirmed = models.NotificationKindAppointmentConfirmed
+		cancelled = models.NotificationKindAppointmentCancelled
+		email     = models.NotificationChannelEmail
+		whatsapp  = models.NotificationChannelWhatsApp
+	)
+
+	cases := []struct {
+		name    string
+		kind    string
+		channel string
+		subject string
+		body    string
+		want    error
+	}{
+		{"email válido", confirmed, email, "Até {{date}}!", "Olá {{ client_name }}, {{service}} às {{time}}.\n{{ticket_link}}", nil},
+		{"whatsapp válido", cancelled, whatsapp, "", "{{client_name}}, cancelamos {{date}}.", nil},
+		{"mensagem desconhecida", "birthday", email, "Oi", "Oi", ErrTemplateUnknownKind},
+		{"canal desconhecido", confirmed, "sms", "", "Oi", ErrTemplateUnknownChannel},
+		{"texto vazio", confirmed, whatsapp, "", "  \n ", ErrTemplateEmptyBody},
+		{"texto longo demais", confirmed, whatsapp, "", strings.Repeat("a", maxTemplateBody+1), ErrTemplateBodyTooLong},
+		{"email sem assunto", confirmed, email, " ", "Oi", ErrTemplateSubjectRequired},
+		{"assunto com quebra de linha", confirmed, email, "Oi\nBcc: x@y.com", "Oi", ErrTemplateSubjectInvalid},
+		{"whatsapp com assunto", confirmed, whatsapp, "Oi", "Oi", ErrTemplateSubjectInvalid},
+		{"chaves sem fechar", confirmed, whatsapp, "", "Olá {{client_name", ErrTemplateUnclosedPlaceholder},
+		{"placeholder desconhecido", confirmed, whatsapp, "", "Olá {{cpf}}", ErrTemplateUnknownPlaceholder},
+		{"placeholder de outra mensagem", cancelled, whatsapp, "", "{{ticket_link}}", ErrTemplateUnknownPlaceholder},
+		{"placeholder desconhecido no assunto", confirmed, email, "{{.ClientName}}", "Oi", ErrTemplateUnknownPlaceholder},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTemplate(tc.kind, tc.channel, tc.subject, tc.body)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("esperado %v, obtido %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestRenderCustomEscapesEmail(t *testing.T) {
+	tmpl := &models.NotificationTemplate{
+		Kind:    models.NotificationKindAppointmentConfirmed,
+		Channel: models.NotificationChannelEmail,
+		Subject: "Confirmado, {{client_name}}",
+		Body:    "Olá {{client_name}}!\n\nTe esperamos em {{address}}.",
+	}
+	vars := map[string]string{
+		"client_name":     "<script>alert(1)</script>",
+		"address":         "Rua A, 10",
+		"barbershop_name": "Barbearia do Zé",
+	}
+
+	subject, html, err := renderCustom(tmpl, vars)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if subject != "Confirmado, <script>alert(1)</script>" {
+		t.Errorf("assunto inesperado: %q", subject)
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("o nome do cliente deveria sair escapado no HTML")
+	}
+	if !strings.Contains(html, "Te esperamos em Rua A, 10.") || !strings.Contains(html, "Barbearia do Zé") {
+		t.Errorf("HTML sem o texto esperado: %s", html)
+	}
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	sample := TemplateSample{
+		BarbershopName:    "Barbearia do Zé",
+		BarbershopAddress: "Rua A, 10",
+		Timezone:          "America/Sao_Paulo",
+		AppURL:            "https://app.example.com",
+	}
+
+	t.Run("texto personalizado no WhatsApp", func(t *testing.T) {
+		out, err := PreviewTemplate(models.NotificationKindAppointmentRescheduled, models.NotificationChannelWhatsApp,
+			"", "{{client_name}}: de {{old_time}} para {{time}} em {{address}}. {{ticket_link}}", sample)
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		want := "Maria Silva: de 10:00 para 14:30 em Rua A, 10. https://app.example.com/ticket/exemplo"
+		if out.Body != want || out.Subject != "" {
+			t.Errorf("esperado %q, obtido %+v", want, out)
+		}
+	})
+
+	t.Run("sem texto mostra o padrão", func(t *testing.T) {
+		out, err := PreviewTemplate(models.NotificationKindAppointmentCancelled, models.NotificationChannelEmail, "", "", sample)
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if out.Subject != subjectAppointmentCancelled || !strings.Contains(out.Body, "Maria Silva") {
+			t.Errorf("prévia padrão inesperada: %q", out.Subject)
+		}
+	})
+
+	t.Run("template inválido é recusado", func(t *testing.T) {
+		_, err := PreviewTemplate(models.NotificationKindAppointmentReminder, models.NotificationChannelWhatsApp, "", "{{cpf}}", sample)
+		if !errors.Is(err, ErrTemplateUnknownPlaceholder) {
+			t.Errorf("esperado placeholder desconhecido, obtido %v", err)
+		}
+	})
+}
+
+type fakeTemplateRepo struct {
+	domain.TemplateRepository
+	saved map[string]*models.NotificationTemplate
+}
+
+func (f *fakeTemplateRepo) Find(_ context.Context, _ uint, kind, channel string) (*models.NotificationTemplate, error) {
+	return f.saved[kind+"/"+channel], nil
+}
+
+func TestWhatsAppMessageUsesTemplate(t *testing.T) {
+	in := domain.AppointmentConfirmedInput{
+		BarbershopID:   1,
+		ClientName:     "Ana",
+		ServiceName:    "Corte",
+		BarbershopName: "Barbearia do Zé",
+		StartTime:      time.Date(2026, 3, 14, 17, 30, 0, 0, time.UTC),
+		Timezone:       "America/Sao_Paulo",
+		Paid:           true,
+	}
+	repo := &fakeTemplateRepo{saved: map[string]*models.NotificationTemplate{}}
+	n := NewWhatsAppNotifier("", "", "").WithTemplates(repo)
+	ctx := context.Background()
+
+	if got := n.message(ctx, 1, confirmedVars(in), confirmedMessage(in), confirmedKinds(in)...); got != confirmedMessage(in) {
+		t.Errorf("sem template deveria usar o texto padrão, obtido %q", got)
+	}
+
+	repo.saved["appointment_confirmed/whatsapp"] = &models.NotificationTemplate{
+		Kind: models.NotificationKindAppointmentConfirmed, Channel: models.NotificationChannelWhatsApp,
+		Body: "{{client_name}}, {{service}} em {{date}} às {{time}}",
+	}
+	if got := n.message(ctx, 1, confirmedVars(in), confirmedMessage(in), confirmedKinds(in)...); got != "Ana, Corte em sábado, 14 de março às 14:30" {
+		t.Errorf("pagamento sem template próprio deveria usar o de confirmação, obtido %q", got)
+	}
+
+	repo.saved["payment_confirmed/whatsapp"] = &models.NotificationTemplate{
+		Kind: models.NotificationKindPaymentConfirmed, Channel: models.NotificationChannelWhatsApp,
+		Body: "Pagamento recebido, {{client_name}}!",
+	}
+	if got := n.message(ctx, 1, confirmedVars(in), confirmedMessage(in), confirmedKinds(in)...); got != "Pagamento recebido, Ana!" {
+		t.Errorf("esperado template de pagamento, obtido %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"net/smtp"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/config"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
 
 type EmailNotifier struct {
@@ -25,6 +27,8 @@ type EmailNotifier struct {
 	// SMTP (fallback quando brevoAPIKey está vazio)
 	smtpAddr string
 	smtpAuth smtp.Auth
+
+	templates domain.TemplateRepository
 }
 
 func NewEmailNotifier(cfg *config.Config) *EmailNotifier {
@@ -46,6 +50,28 @@ func NewEmailNotifier(cfg *config.Config) *EmailNotifier {
 	return n
 }
 
+// WithTemplates liga os textos personalizados das barbearias. Sem ele, vale
+// sempre o template padrão.
+func (n *EmailNotifier) WithTemplates(repo domain.TemplateRepository) *EmailNotifier {
+	n.templates = repo
+	return n
+}
+
+// custom renderiza o template personalizado da barbearia, se houver. ok
+// falso: usar o padrão.
+func (n *EmailNotifier) custom(ctx context.Context, barbershopID uint, vars map[string]string, kinds ...string) (subject, html string, ok bool) {
+	t := findTemplate(ctx, n.templates, barbershopID, models.NotificationChannelEmail, kinds...)
+	if t == nil {
+		return "", "", false
+	}
+	subject, html, err := renderCustom(t, vars)
+	if err != nil {
+		log.Printf("[EMAIL] template %s render error barbershop=%d: %v", t.Kind, barbershopID, err)
+		return "", "", false
+	}
+	return subject, html, true
+}
+
 // ── Pagamento confirmado (Checkout Transparente / PIX) ───────────────────────
 
 func (n *EmailNotifier) Notify(ctx context.Context, input domain.PaymentConfirmedInput) error {
@@ -70,14 +96,19 @@ func (n *EmailNotifier) Notify(ctx context.Context, input domain.PaymentConfirme
 func (n *EmailNotifier) NotifyConfirmed(ctx context.Context, input domain.AppointmentConfirmedInput) error {
 	log.Println("[EMAIL] NotifyConfirmed to:", input.ClientEmail)
 
-	html, err := renderAppointmentConfirmed(input)
-	if err != nil {
-		log.Printf("[EMAIL] NotifyConfirmed render error: %v", err)
-		return err
+	subject, html, ok := n.custom(ctx, input.BarbershopID, confirmedVars(input), confirmedKinds(input)...)
+	if !ok {
+		var err error
+		subject = subjectAppointmentConfirmed
+		html, err = renderAppointmentConfirmed(input)
+		if err != nil {
+			log.Printf("[EMAIL] NotifyConfirmed render error: %v", err)
+			return err
+		}
 	}
 
 	ics := buildAppointmentICS(input)
-	err = n.send(ctx, input.ClientEmail, "Agendamento confirmado – Corteon", html, ics)
+	err := n.send(ctx, input.ClientEmail, subject, html, ics)
 	if err != nil {
 		log.Printf("[EMAIL] NotifyConfirmed send error to=%s: %v", input.ClientEmail, err)
 	}
@@ -89,13 +120,18 @@ func (n *EmailNotifier) NotifyConfirmed(ctx context.Context, input domain.Appoin
 func (n *EmailNotifier) NotifyCancelled(ctx context.Context, input domain.AppointmentCancelledInput) error {
 	log.Println("[EMAIL] NotifyCancelled to:", input.ClientEmail)
 
-	html, err := renderAppointmentCancelled(input)
-	if err != nil {
-		log.Printf("[EMAIL] NotifyCancelled render error: %v", err)
-		return err
+	subject, html, ok := n.custom(ctx, input.BarbershopID, cancelledVars(input), models.NotificationKindAppointmentCancelled)
+	if !ok {
+		var err error
+		subject = subjectAppointmentCancelled
+		html, err = renderAppointmentCancelled(input)
+		if err != nil {
+			log.Printf("[EMAIL] NotifyCancelled render error: %v", err)
+			return err
+		}
 	}
 
-	err = n.send(ctx, input.ClientEmail, "Agendamento cancelado – Corteon", html, "")
+	err := n.send(ctx, input.ClientEmail, subject, html, "")
 	if err != nil {
 		log.Printf("[EMAIL] NotifyCancelled send error to=%s: %v", input.ClientEmail, err)
 	}
@@ -107,20 +143,150 @@ func (n *EmailNotifier) NotifyCancelled(ctx context.Context, input domain.Appoin
 func (n *EmailNotifier) NotifyRescheduled(ctx context.Context, input domain.AppointmentRescheduledInput) error {
 	log.Println("[EMAIL] NotifyRescheduled to:", input.ClientEmail)
 
-	html, err := renderAppointmentRescheduled(input)
-	if err != nil {
-		log.Printf("[EMAIL] NotifyRescheduled render error: %v", err)
-		return err
+	subject, html, ok := n.custom(ctx, input.BarbershopID, rescheduledVars(input), models.NotificationKindAppointmentRescheduled)
+	if !ok {
+		var err error
+		subject = subjectAppointmentRescheduled
+		html, err = renderAppointmentRescheduled(input)
+		if err != nil {
+			log.Printf("[EMAIL] NotifyRescheduled render error: %v", err)
+			return err
+		}
 	}
 
 	ics := buildRescheduledICS(input)
-	err = n.send(ctx, input.ClientEmail, "Agendamento remarcado – Corteon", html, ics)
+	err := n.send(ctx, input.ClientEmail, subject, html, ics)
 	if err != nil {
 		log.Printf("[EMAIL] NotifyRescheduled send error to=%s: %v", input.ClientEmail, err)
 	}
 	return err
 }
 
+// ── Lembrete de agendamento ──────────────────────────────────────────────────
+
+func (n *EmailNotifier) NotifyReminder(ctx context.Context, input domain.AppointmentReminderInput) error {
+	log.Println("[EMAIL] NotifyReminder to:", input.ClientEmail)
+
+	subject, html, ok := n.custom(ctx, input.BarbershopID, reminderVars(input), models.NotificationKindAppointmentReminder)
+	if !ok {
+		var err error
+		subject = subjectAppointmentReminder
+		html, err = renderAppointmentReminder(input)
+		if err != nil {
+			log.Printf("[EMAIL] NotifyReminder render error: %v", err)
+			return err
+		}
+	}
+
+	err := n.send(ctx, input.ClientEmail, subject, html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifyReminder send error to=%s: %v", input.ClientEmail, err)
+	}
+	return err
+}
+
+func (n *EmailNotifier) NotifyWaitlistOffer(ctx context.Context, input domain.WaitlistOfferInput) error {
+	log.Println("[EMAIL] NotifyWaitlistOffer to:", input.ClientEmail)
+
+	html, err := renderWaitlistOffer(input)
+	if err != nil {
+		log.Printf("[EMAIL] NotifyWaitlistOffer render error: %v", err)
+		return err
+	}
+
+	err = n.send(ctx, input.ClientEmail, "Abriu um horário para você – Corteon", html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifyWaitlistOffer send error to=%s: %v", input.ClientEmail, err)
+	}
+	return err
+}
+
+func (n *EmailNotifier) NotifySubscriptionRenewal(ctx context.Context, input domain.SubscriptionRenewalInput) error {
+	if input.ClientEmail == "" {
+		return nil
+	}
+	log.Println("[EMAIL] NotifySubscriptionRenewal to:", input.ClientEmail)
+
+	html, err := renderSubscriptionRenewal(input)
+	if err != nil {
+		log.Printf("[EMAIL] NotifySubscriptionRenewal render error: %v", err)
+		return err
+	}
+
+	subject := "Assinatura renovada – Corteon"
+	if input.Failed {
+		subject = "Não conseguimos renovar sua assinatura – Corteon"
+	}
+	err = n.send(ctx, input.ClientEmail, subject, html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifySubscriptionRenewal send error to=%s: %v", input.ClientEmail, err)
+	}
+	return err
+}
+
+func (n *EmailNotifier) NotifyLowStock(ctx context.Context, input domain.LowStockInput) error {
+	if input.OwnerEmail == "" || len(input.Products) == 0 {
+		return nil
+	}
+	log.Println("[EMAIL] NotifyLowStock to:", input.OwnerEmail)
+
+	html, err := renderLowStock(input)
+	if err != nil {
+		log.Printf("[EMAIL] NotifyLowStock render error: %v", err)
+		return err
+	}
+
+	subject := "Estoque baixo – Corteon"
+	if len(input.Products) == 1 {
+		subject = "Estoque baixo: " + input.Products[0].Name + " – Corteon"
+	}
+	err = n.send(ctx, input.OwnerEmail, subject, html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifyLowStock send error to=%s: %v", input.OwnerEmail, err)
+	}
+	return err
+}
+
+func (n *EmailNotifier) NotifyLoginCode(ctx context.Context, input domain.LoginCodeInput) error {
+	if input.ClientEmail == "" {
+		return nil
+	}
+	log.Println("[EMAIL] NotifyLoginCode to:", input.ClientEmail)
+
+	html, err := renderLoginCode(input)
+	if err != nil {
+		log.Printf("[EMAIL] NotifyLoginCode render error: %v", err)
+		return err
+	}
+
+	subject := "Seu código de acesso: " + input.Code + " – Corteon"
+	err = n.send(ctx, input.ClientEmail, subject, html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifyLoginCode send error to=%s: %v", input.ClientEmail, err)
+	}
+	return err
+}
+
+// ── Campanhas ────────────────────────────────────────────────────────────────
+
+func (n *EmailNotifier) NotifyCampaign(ctx context.Context, input domain.CampaignMessageInput) error {
+	if input.ClientEmail == "" {
+		return nil
+	}
+
+	html, err := customMessageHTML(input.BarbershopName, input.Body, input.UnsubscribeURL)
+	if err != nil {
+		log.Printf("[EMAIL] NotifyCampaign render error: %v", err)
+		return err
+	}
+
+	err = n.send(ctx, input.ClientEmail, input.Subject, html, "")
+	if err != nil {
+		log.Printf("[EMAIL] NotifyCampaign send error to=%s: %v", input.ClientEmail, err)
+	}
+	return err
+}
+
 // ── Redefinição de senha ─────────────────────────────────────────────────────
 
 func (n *EmailNotifier) SendPasswordReset(ctx context.Context, to, resetLink string) error {
@@ -144,6 +310,29 @@ func (n *EmailNotifier) SendPasswordReset(ctx context.Context, to, resetLink str
 	return err
 }
 
+// ── Convite de equipe ────────────────────────────────────────────────────────
+
+func (n *EmailNotifier) SendStaffInvitation(ctx context.Context, to, barbershopName, inviteLink string) error {
+	page := fmt.Sprintf(`
+<html><body style="font-family:sans-serif;background:#f5f5f5;padding:40px 0">
+<div style="max-width:480px;margin:0 auto;background:#fff;border-radius:12px;padding:32px;box-shadow:0 2px 8px rgba(0,0,0,0.08)">
+  <h2 style="color:#C08A3E;margin-top:0;margin-bottom:8px">Convite para a equipe</h2>
+  <p style="color:#555;margin-bottom:16px">Você foi convidado para atender na <strong>%s</strong> pelo <strong>CorteOn</strong>.</p>
+  <p style="color:#555;margin-bottom:24px">Clique no botão abaixo para criar sua senha e acessar sua agenda. Este link é válido por <strong>7 dias</strong>.</p>
+  <a href="%s" style="display:inline-block;background:#C08A3E;color:#fff;padding:12px 28px;border-radius:8px;text-decoration:none;font-weight:bold;font-size:15px">
+    Aceitar convite
+  </a>
+  <p style="color:#999;font-size:12px;margin-top:28px">Se você não esperava este convite, ignore este e-mail.</p>
+</div>
+</body></html>`, html.EscapeString(barbershopName), inviteLink)
+
+	err := n.send(ctx, to, "Convite para a equipe – CorteOn", page, "")
+	if err != nil {
+		log.Printf("[EMAIL] SendStaffInvitation error to=%s: %v", to, err)
+	}
+	return err
+}
+
 // ── dispatcher central ───────────────────────────────────────────────────────
 
 func (n *EmailNotifier) send(ctx context.Context, to, subject, html, ics string) error {
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/integration/email"
+)
+
+// Send implementa email.Sender: mensagem livre (HTML, texto e anexos) pelo
+// mesmo transporte das notificações — Brevo quando configurado, senão SMTP.
+func (n *EmailNotifier) Send(ctx context.Context, msg email.Message) error {
+	if n.brevoAPIKey != "" {
+		return n.sendMessageViaBrevoAPI(ctx, msg)
+	}
+	from := n.fromName + " <" + n.fromAddress + ">"
+	return smtp.SendMail(n.smtpAddr, n.smtpAuth, n.fromAddress, []string{msg.To}, email.BuildMIME(from, msg))
+}
+
+type brevoMessageRequest struct {
+	Sender      brevoContact      `json:"sender"`
+	To          []brevoContact    `json:"to"`
+	Subject     string            `json:"subject"`
+	HTMLContent string            `json:"htmlContent,omitempty"`
+	TextContent string            `json:"textContent,omitempty"`
+	Attachment  []brevoAttachment `json:"attachment,omitempty"`
+}
+
+type brevoAttachment struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+func (n *EmailNotifier) sendMessageViaBrevoAPI(ctx context.Context, msg email.Message) error {
+	payload := brevoMessageRequest{
+		Sender:      brevoContact{Name: n.fromName, Email: n.fromAddress},
+		To:          []brevoContact{{Email: msg.To}},
+		Subject:     msg.Subject,
+		HTMLContent: msg.HTML,
+		TextContent: msg.Body,
+	}
+	for _, a := range msg.Attachments {
+		payload.Attachment = append(payload.Attachment, brevoAttachment{
+			Name:    a.Filename,
+			Content: base64.StdEncoding.EncodeToString(a.Content),
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("brevo marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://api.brevo.com/v3/smtp/email", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("brevo request: %w", err)
+	}
+	req.Header.Set("api-key", n.brevoAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("brevo http: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var errBody bytes.Buffer
+		errBody.ReadFrom(resp.Body)
+		return fmt.Errorf("brevo api status=%d body=%s", resp.StatusCode, errBody.String())
+	}
+
+	log.Printf("[EMAIL] Brevo API sent to=%s status=%d attachments=%d", msg.To, resp.StatusCode, len(msg.Attachments))
+	return nil
+}
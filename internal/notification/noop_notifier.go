@@ -6,7 +6,10 @@ import (
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 )
 
-// NoopNotifier implements both domain.Notifier and domain.AppointmentNotifier.
+// NoopNotifier implements domain.Notifier, domain.AppointmentNotifier,
+// domain.ReminderNotifier, domain.WaitlistOfferNotifier,
+// domain.SubscriptionRenewalNotifier, domain.LowStockNotifier and
+// domain.LoginCodeNotifier.
 // All methods are no-ops — use it when email is disabled.
 type NoopNotifier struct{}
 
@@ -32,6 +35,40 @@ func (n *NoopNotifier) NotifyRescheduled(_ context.Context, _ domain.Appointment
 	return nil
 }
 
+// --- domain.ReminderNotifier ---
+
+func (n *NoopNotifier) NotifyReminder(_ context.Context, _ domain.AppointmentReminderInput) error {
+	return nil
+}
+
+// --- domain.WaitlistOfferNotifier ---
+
+func (n *NoopNotifier) NotifyWaitlistOffer(_ context.Context, _ domain.WaitlistOfferInput) error {
+	return nil
+}
+
+// --- domain.SubscriptionRenewalNotifier ---
+
+func (n *NoopNotifier) NotifySubscriptionRenewal(_ context.Context, _ domain.SubscriptionRenewalInput) error {
+	return nil
+}
+
+// --- domain.LowStockNotifier ---
+
+func (n *NoopNotifier) NotifyLowStock(_ context.Context, _ domain.LowStockInput) error {
+	return nil
+}
+
+// --- domain.LoginCodeNotifier ---
+
+func (n *NoopNotifier) NotifyLoginCode(_ context.Context, _ domain.LoginCodeInput) error {
+	return nil
+}
+
 func (n *NoopNotifier) SendPasswordReset(_ context.Context, _, _ string) error {
 	return nil
 }
+
+func (n *NoopNotifier) SendStaffInvitation(_ context.Context, _, _, _ string) error {
+	return nil
+}
@@ -23,11 +23,31 @@ var appointmentCancelledRaw string
 //go:embed templates/appointment_rescheduled.html
 var appointmentRescheduledRaw string
 
+//go:embed templates/appointment_reminder.html
+var appointmentReminderRaw string
+
+//go:embed templates/waitlist_offer.html
+var waitlistOfferRaw string
+
+//go:embed templates/subscription_renewal.html
+var subscriptionRenewalRaw string
+
+//go:embed templates/low_stock.html
+var lowStockRaw string
+
+//go:embed templates/login_code.html
+var loginCodeRaw string
+
 var (
 	paymentConfirmedTmpl      = template.Must(template.New("payment_confirmed").Parse(paymentConfirmedRaw))
 	appointmentConfirmedTmpl  = template.Must(template.New("appointment_confirmed").Parse(appointmentConfirmedRaw))
 	appointmentCancelledTmpl  = template.Must(template.New("appointment_cancelled").Parse(appointmentCancelledRaw))
 	appointmentRescheduledTmpl = template.Must(template.New("appointment_rescheduled").Parse(appointmentRescheduledRaw))
+	appointmentReminderTmpl    = template.Must(template.New("appointment_reminder").Parse(appointmentReminderRaw))
+	waitlistOfferTmpl          = template.Must(template.New("waitlist_offer").Parse(waitlistOfferRaw))
+	subscriptionRenewalTmpl    = template.Must(template.New("subscription_renewal").Parse(subscriptionRenewalRaw))
+	lowStockTmpl               = template.Must(template.New("low_stock").Parse(lowStockRaw))
+	loginCodeTmpl              = template.Must(template.New("login_code").Parse(loginCodeRaw))
 )
 
 // ── payment_confirmed ────────────────────────────────────────────────────────
@@ -122,6 +142,118 @@ func renderAppointmentRescheduled(input domain.AppointmentRescheduledInput) (str
 	return execTemplate(appointmentRescheduledTmpl, data)
 }
 
+// ── appointment_reminder ─────────────────────────────────────────────────────
+
+type appointmentReminderData struct {
+	ClientName        string
+	ServiceName       string
+	AppointmentDate   string
+	BarbershopName    string
+	BarbershopPhone   string
+	BarbershopAddress string
+	TicketURL         string
+}
+
+func renderAppointmentReminder(input domain.AppointmentReminderInput) (string, error) {
+	loc := loadLocation(input.Timezone)
+	data := appointmentReminderData{
+		ClientName:        input.ClientName,
+		ServiceName:       input.ServiceName,
+		AppointmentDate:   input.StartTime.In(loc).Format("02/01/2006 às 15:04"),
+		BarbershopName:    input.BarbershopName,
+		BarbershopPhone:   input.BarbershopPhone,
+		BarbershopAddress: input.BarbershopAddress,
+		TicketURL:         input.TicketURL,
+	}
+	return execTemplate(appointmentReminderTmpl, data)
+}
+
+// ── waitlist_offer ───────────────────────────────────────────────────────────
+
+type waitlistOfferData struct {
+	ClientName      string
+	ServiceName     string
+	AppointmentDate string
+	BarbershopName  string
+	ExpiresAt       string
+	ClaimURL        string
+}
+
+func renderWaitlistOffer(input domain.WaitlistOfferInput) (string, error) {
+	loc := loadLocation(input.Timezone)
+	data := waitlistOfferData{
+		ClientName:      input.ClientName,
+		ServiceName:     input.ServiceName,
+		AppointmentDate: input.StartTime.In(loc).Format("02/01/2006 às 15:04"),
+		BarbershopName:  input.BarbershopName,
+		ExpiresAt:       input.ExpiresAt.In(loc).Format("15:04 de 02/01"),
+		ClaimURL:        input.ClaimURL,
+	}
+	return execTemplate(waitlistOfferTmpl, data)
+}
+
+// ── subscription_renewal ─────────────────────────────────────────────────────
+
+type subscriptionRenewalData struct {
+	ClientName     string
+	BarbershopName string
+	PlanName       string
+	Amount         string
+	CardLastFour   string
+	Failed         bool
+	ValidUntil     string
+	NextAttemptAt  string
+}
+
+func renderSubscriptionRenewal(input domain.SubscriptionRenewalInput) (string, error) {
+	loc := loadLocation(input.Timezone)
+	data := subscriptionRenewalData{
+		ClientName:     input.ClientName,
+		BarbershopName: input.BarbershopName,
+		PlanName:       input.PlanName,
+		Amount:         formatBRL(input.AmountCents),
+		CardLastFour:   input.CardLastFour,
+		Failed:         input.Failed,
+		ValidUntil:     input.ValidUntil.In(loc).Format("02/01/2006"),
+	}
+	if input.NextAttemptAt != nil {
+		data.NextAttemptAt = input.NextAttemptAt.In(loc).Format("02/01/2006")
+	}
+	return execTemplate(subscriptionRenewalTmpl, data)
+}
+
+// ── low_stock ────────────────────────────────────────────────────────────────
+
+type lowStockData struct {
+	BarbershopName string
+	Products       []domain.LowStockItem
+}
+
+func renderLowStock(input domain.LowStockInput) (string, error) {
+	return execTemplate(lowStockTmpl, lowStockData{
+		BarbershopName: input.BarbershopName,
+		Products:       input.Products,
+	})
+}
+
+// ── login_code ───────────────────────────────────────────────────────────────
+
+type loginCodeData struct {
+	ClientName       string
+	BarbershopName   string
+	Code             string
+	ExpiresInMinutes int
+}
+
+func renderLoginCode(input domain.LoginCodeInput) (string, error) {
+	return execTemplate(loginCodeTmpl, loginCodeData{
+		ClientName:       input.ClientName,
+		BarbershopName:   input.BarbershopName,
+		Code:             input.Code,
+		ExpiresInMinutes: input.ExpiresInMinutes,
+	})
+}
+
 // ── google calendar ──────────────────────────────────────────────────────────
 
 func buildGoogleCalendarURL(serviceName, barbershopName string, start, end time.Time) string {
@@ -155,3 +287,8 @@ func execTemplate(tmpl *template.Template, data any) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// formatBRL formata centavos como "R$ 49,90".
+func formatBRL(cents int64) string {
+	return fmt.Sprintf("R$ %d,%02d", cents/100, cents%100)
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	_ "embed"
+	"html/template"
+
+	domainReport "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+)
+
+//go:embed templates/report.html
+var reportRaw string
+
+var reportTmpl = template.Must(template.New("report").Parse(reportRaw))
+
+type reportData struct {
+	*domainReport.Document
+	Attachment string
+}
+
+// RenderReport monta o email do relatório agendado: destaques e seções do
+// documento, com a menção ao anexo.
+func RenderReport(doc *domainReport.Document, attachment string) (string, error) {
+	return execTemplate(reportTmpl, reportData{Document: doc, Attachment: attachment})
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
 )
 
@@ -301,6 +302,7 @@ type WhatsAppNotifier struct {
 	evolutionURL string
 	evolutionKey string
 	appURL       string
+	templates    domain.TemplateRepository
 }
 
 func NewWhatsAppNotifier(evolutionURL, evolutionKey, appURL string) *WhatsAppNotifier {
@@ -311,6 +313,27 @@ func NewWhatsAppNotifier(evolutionURL, evolutionKey, appURL string) *WhatsAppNot
 	}
 }
 
+// WithTemplates liga os textos personalizados das barbearias. Sem ele, vale
+// sempre o texto padrão.
+func (n *WhatsAppNotifier) WithTemplates(repo domain.TemplateRepository) *WhatsAppNotifier {
+	n.templates = repo
+	return n
+}
+
+// message devolve o texto personalizado da barbearia, se houver, ou o padrão.
+func (n *WhatsAppNotifier) message(ctx context.Context, barbershopID uint, vars map[string]string, fallback string, kinds ...string) string {
+	t := findTemplate(ctx, n.templates, barbershopID, models.NotificationChannelWhatsApp, kinds...)
+	if t == nil {
+		return fallback
+	}
+	_, text, err := renderCustom(t, vars)
+	if err != nil {
+		log.Printf("[WhatsApp] template %s render error barbershop=%d: %v", t.Kind, barbershopID, err)
+		return fallback
+	}
+	return text
+}
+
 func (n *WhatsAppNotifier) clientFor(instanceName string) *EvolutionClient {
 	return NewEvolutionClient(n.evolutionURL, n.evolutionKey)
 }
@@ -321,21 +344,31 @@ func instanceNameForBarbershop(barbershopID uint) string {
 	return fmt.Sprintf("bs%d", barbershopID)
 }
 
-func (n *WhatsAppNotifier) send(ctx context.Context, barbershopID uint, phone, msg string) {
+// sendErr envia a mensagem e devolve o erro — o chamador precisa saber se
+// ela saiu (outbox e lembretes registrados por canal tentam de novo).
+func (n *WhatsAppNotifier) sendErr(ctx context.Context, barbershopID uint, phone, msg string) error {
 	if phone == "" || n.evolutionURL == "" {
-		return
+		return nil
 	}
 	instance := instanceNameForBarbershop(barbershopID)
 	client := n.clientFor(instance)
 	if err := client.SendText(ctx, instance, phone, msg); err != nil {
 		log.Printf("[WhatsApp] send failed barbershop=%d phone=%s: %v", barbershopID, maskPhoneLog(phone), err)
+		return err
 	}
+	return nil
 }
 
 func (n *WhatsAppNotifier) NotifyConfirmed(ctx context.Context, in domain.AppointmentConfirmedInput) error {
 	if in.ClientPhone == "" || in.BarbershopID == 0 {
 		return nil
 	}
+	msg := n.message(ctx, in.BarbershopID, confirmedVars(in), confirmedMessage(in), confirmedKinds(in)...)
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, msg)
+}
+
+// confirmedMessage é o texto padrão da confirmação.
+func confirmedMessage(in domain.AppointmentConfirmedInput) string {
 	loc := timezone.Location(in.Timezone)
 	start := in.StartTime.In(loc)
 	end := in.EndTime.In(loc)
@@ -361,14 +394,19 @@ func (n *WhatsAppNotifier) NotifyConfirmed(ctx context.Context, in domain.Appoin
 	}
 	lines = append(lines, "", fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName))
 
-	n.send(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
-	return nil
+	return strings.Join(lines, "\n")
 }
 
 func (n *WhatsAppNotifier) NotifyCancelled(ctx context.Context, in domain.AppointmentCancelledInput) error {
 	if in.ClientPhone == "" || in.BarbershopID == 0 {
 		return nil
 	}
+	msg := n.message(ctx, in.BarbershopID, cancelledVars(in), cancelledMessage(in), models.NotificationKindAppointmentCancelled)
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, msg)
+}
+
+// cancelledMessage é o texto padrão do cancelamento.
+func cancelledMessage(in domain.AppointmentCancelledInput) string {
 	loc := timezone.Location(in.Timezone)
 	start := in.StartTime.In(loc)
 
@@ -381,14 +419,19 @@ func (n *WhatsAppNotifier) NotifyCancelled(ctx context.Context, in domain.Appoin
 		fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName),
 	}
 
-	n.send(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
-	return nil
+	return strings.Join(lines, "\n")
 }
 
 func (n *WhatsAppNotifier) NotifyRescheduled(ctx context.Context, in domain.AppointmentRescheduledInput) error {
 	if in.ClientPhone == "" || in.BarbershopID == 0 {
 		return nil
 	}
+	msg := n.message(ctx, in.BarbershopID, rescheduledVars(in), rescheduledMessage(in), models.NotificationKindAppointmentRescheduled)
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, msg)
+}
+
+// rescheduledMessage é o texto padrão da remarcação.
+func rescheduledMessage(in domain.AppointmentRescheduledInput) string {
 	loc := timezone.Location(in.Timezone)
 	oldStart := in.OldStartTime.In(loc)
 	newStart := in.NewStartTime.In(loc)
@@ -410,8 +453,142 @@ func (n *WhatsAppNotifier) NotifyRescheduled(ctx context.Context, in domain.Appo
 	}
 	lines = append(lines, "", fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName))
 
-	n.send(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
-	return nil
+	return strings.Join(lines, "\n")
+}
+
+func (n *WhatsAppNotifier) NotifyReminder(ctx context.Context, in domain.AppointmentReminderInput) error {
+	if in.ClientPhone == "" || in.BarbershopID == 0 {
+		return nil
+	}
+	msg := n.message(ctx, in.BarbershopID, reminderVars(in), reminderMessage(in), models.NotificationKindAppointmentReminder)
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, msg)
+}
+
+// reminderMessage é o texto padrão do lembrete.
+func reminderMessage(in domain.AppointmentReminderInput) string {
+	loc := timezone.Location(in.Timezone)
+	start := in.StartTime.In(loc)
+	end := in.EndTime.In(loc)
+
+	lines := []string{
+		fmt.Sprintf("⏰ *Lembrete do seu horário, %s!*", in.ClientName),
+		"",
+	}
+	if in.ServiceName != "" {
+		lines = append(lines, fmt.Sprintf("✂️ *%s*", in.ServiceName))
+	}
+	lines = append(lines,
+		fmt.Sprintf("📅 %s", formatDate(start)),
+		fmt.Sprintf("🕐 %s – %s", formatTime(start), formatTime(end)),
+	)
+	if in.BarbershopAddress != "" {
+		lines = append(lines, fmt.Sprintf("📍 %s", in.BarbershopAddress))
+	}
+	if in.TicketURL != "" {
+		lines = append(lines, "", "🔗 *Precisa cancelar ou remarcar?*", in.TicketURL)
+	}
+	if in.BarbershopPhone != "" {
+		lines = append(lines, "", fmt.Sprintf("📞 Dúvidas: %s", in.BarbershopPhone))
+	}
+	lines = append(lines, "", fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName))
+
+	return strings.Join(lines, "\n")
+}
+
+func (n *WhatsAppNotifier) NotifyWaitlistOffer(ctx context.Context, in domain.WaitlistOfferInput) error {
+	if in.ClientPhone == "" || in.BarbershopID == 0 {
+		return nil
+	}
+	loc := timezone.Location(in.Timezone)
+	start := in.StartTime.In(loc)
+	end := in.EndTime.In(loc)
+
+	lines := []string{
+		fmt.Sprintf("🎉 *Abriu um horário para você, %s!*", in.ClientName),
+		"",
+		"Um horário da sua lista de espera foi liberado:",
+	}
+	if in.ServiceName != "" {
+		lines = append(lines, fmt.Sprintf("✂️ *%s*", in.ServiceName))
+	}
+	lines = append(lines,
+		fmt.Sprintf("📅 %s", formatDate(start)),
+		fmt.Sprintf("🕐 %s – %s", formatTime(start), formatTime(end)),
+	)
+	if in.ClaimURL != "" {
+		lines = append(lines, "", "🔗 *Para garantir o horário:*", in.ClaimURL)
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("⏳ A oferta vale até %s. Depois o horário fica livre para outras pessoas.", formatTime(in.ExpiresAt.In(loc))),
+		"",
+		fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName),
+	)
+
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
+}
+
+func (n *WhatsAppNotifier) NotifySubscriptionRenewal(ctx context.Context, in domain.SubscriptionRenewalInput) error {
+	if in.ClientPhone == "" || in.BarbershopID == 0 {
+		return nil
+	}
+	loc := timezone.Location(in.Timezone)
+
+	card := "no cartão"
+	if in.CardLastFour != "" {
+		card = fmt.Sprintf("no cartão final %s", in.CardLastFour)
+	}
+
+	var lines []string
+	if in.Failed {
+		lines = []string{
+			fmt.Sprintf("⚠️ *%s, não conseguimos renovar sua assinatura.*", in.ClientName),
+			"",
+			fmt.Sprintf("A cobrança de R$ %d,%02d do plano *%s* %s foi recusada.", in.AmountCents/100, in.AmountCents%100, in.PlanName, card),
+		}
+		if in.NextAttemptAt != nil {
+			lines = append(lines, fmt.Sprintf("🔁 Vamos tentar de novo em %s.", formatDate(in.NextAttemptAt.In(loc))))
+		} else {
+			lines = append(lines, "Não haverá novas tentativas automáticas.")
+		}
+		lines = append(lines,
+			fmt.Sprintf("📅 Sem o pagamento, a assinatura expira em %s. Fale com a barbearia para atualizar o cartão.", formatDate(in.ValidUntil.In(loc))),
+		)
+	} else {
+		lines = []string{
+			fmt.Sprintf("✅ *Assinatura renovada, %s!*", in.ClientName),
+			"",
+			fmt.Sprintf("Cobramos R$ %d,%02d do plano *%s* %s.", in.AmountCents/100, in.AmountCents%100, in.PlanName, card),
+			fmt.Sprintf("📅 Seu plano segue ativo até %s.", formatDate(in.ValidUntil.In(loc))),
+		}
+	}
+	lines = append(lines, "", fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName))
+
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
+}
+
+func (n *WhatsAppNotifier) NotifyLoginCode(ctx context.Context, in domain.LoginCodeInput) error {
+	if in.ClientPhone == "" || in.BarbershopID == 0 {
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("🔐 *Seu código de acesso: %s*", in.Code),
+		"",
+		fmt.Sprintf("Use este código para entrar na sua área de cliente. Ele vale por %d minutos.", in.ExpiresInMinutes),
+		"Se não foi você que pediu, ignore esta mensagem.",
+		"",
+		fmt.Sprintf("_Mensagem automática · %s_", in.BarbershopName),
+	}
+
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, strings.Join(lines, "\n"))
+}
+
+func (n *WhatsAppNotifier) NotifyCampaign(ctx context.Context, in domain.CampaignMessageInput) error {
+	if in.ClientPhone == "" || in.BarbershopID == 0 {
+		return nil
+	}
+	return n.sendErr(ctx, in.BarbershopID, in.ClientPhone, campaignWhatsAppText(in.Body))
 }
 
 // ── Formatters ────────────────────────────────────────────────────────────────
@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"context"
+	"log"
+)
+
+type calendarPayload struct {
+	AppointmentID uint `json:"appointment_id"`
+}
+
+// Calendar grava no outbox a sincronização do agendamento com o Google
+// Calendar. Implementa domain/appointment.CalendarSync.
+type Calendar struct {
+	outbox *Outbox
+}
+
+func (o *Outbox) Calendar() *Calendar {
+	return &Calendar{outbox: o}
+}
+
+// AppointmentChanged não devolve erro (a interface é best-effort); dentro de
+// uma transação, uma falha na gravação aborta o commit.
+func (c *Calendar) AppointmentChanged(ctx context.Context, barbershopID, appointmentID uint) {
+	err := c.outbox.Enqueue(ctx, Message{
+		BarbershopID: barbershopID,
+		Topic:        TopicCalendarSync,
+		Reference:    appointmentReference(appointmentID),
+		Payload:      calendarPayload{AppointmentID: appointmentID},
+	})
+	if err != nil {
+		log.Printf("[OUTBOX] calendar enqueue failed appointment=%d: %v", appointmentID, err)
+	}
+}
@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"context"
+	"log"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+)
+
+// Notifier grava as notificações de agendamento no outbox, um evento por
+// canal ativo com destinatário. O WhatsApp só entra quando a barbearia tem
+// a instância conectada. Implementa domain/notification.AppointmentNotifier.
+type Notifier struct {
+	outbox   *Outbox
+	channels []string
+}
+
+// Notifier cria o notifier dos canais dados (ChannelEmail, ChannelWhatsApp).
+// Sem canais, as notificações são descartadas.
+func (o *Outbox) Notifier(channels ...string) *Notifier {
+	return &Notifier{outbox: o, channels: channels}
+}
+
+func (n *Notifier) NotifyConfirmed(ctx context.Context, in domain.AppointmentConfirmedInput) error {
+	return n.enqueue(ctx, in.BarbershopID, in.AppointmentID, TopicAppointmentConfirmed, in.ClientEmail, in.ClientPhone, in)
+}
+
+func (n *Notifier) NotifyCancelled(ctx context.Context, in domain.AppointmentCancelledInput) error {
+	return n.enqueue(ctx, in.BarbershopID, in.AppointmentID, TopicAppointmentCancelled, in.ClientEmail, in.ClientPhone, in)
+}
+
+func (n *Notifier) NotifyRescheduled(ctx context.Context, in domain.AppointmentRescheduledInput) error {
+	return n.enqueue(ctx, in.BarbershopID, in.AppointmentID, TopicAppointmentRescheduled, in.ClientEmail, in.ClientPhone, in)
+}
+
+func (n *Notifier) enqueue(
+	ctx context.Context,
+	barbershopID uint,
+	appointmentID uint,
+	topic string,
+	email string,
+	phone string,
+	payload any,
+) error {
+	var msgs []Message
+	for _, ch := range n.channels {
+		if (ch == ChannelEmail && email == "") || (ch == ChannelWhatsApp && phone == "") {
+			continue
+		}
+		if ch == ChannelWhatsApp && !n.outbox.whatsAppConnected(ctx, barbershopID) {
+			continue
+		}
+		msgs = append(msgs, Message{
+			BarbershopID: barbershopID,
+			Topic:        topic,
+			Channel:      ch,
+			Reference:    appointmentReference(appointmentID),
+			Payload:      payload,
+		})
+	}
+	return n.outbox.Enqueue(ctx, msgs...)
+}
+
+// whatsAppConnected diz se a barbearia tem a instância do WhatsApp
+// conectada. Sem ela o envio falharia em todas as tentativas.
+func (o *Outbox) whatsAppConnected(ctx context.Context, barbershopID uint) bool {
+	var connected bool
+	err := o.conn(ctx).
+		Raw(`SELECT EXISTS (
+			SELECT 1 FROM barbershop_whatsapp_instances
+			WHERE barbershop_id = ? AND barber_id IS NULL AND status = 'connected'
+		)`, barbershopID).
+		Scan(&connected).Error
+	if err != nil {
+		log.Printf("[OUTBOX] whatsapp instance lookup failed barbershop=%d: %v", barbershopID, err)
+		return false
+	}
+	return connected
+}
@@ -0,0 +1,138 @@
+// Package outbox grava os efeitos colaterais de uma mudança de estado
+// (notificações, sincronização com o Google Calendar, auditoria) na mesma
+// transação da mudança. O Worker entrega os eventos depois do commit, com
+// retentativas, então um crash ou deploy não perde mais nada pelo caminho.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	TopicAppointmentConfirmed   = "appointment_confirmed"
+	TopicAppointmentCancelled   = "appointment_cancelled"
+	TopicAppointmentRescheduled = "appointment_rescheduled"
+	TopicCalendarSync           = "calendar_sync"
+	TopicAudit                  = "audit"
+	TopicSlotFreed              = "slot_freed"
+)
+
+const (
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+)
+
+var (
+	ErrEventNotFound = apperr.ErrBusiness("outbox_event_not_found")
+	ErrEventNotDead  = apperr.ErrBusiness("outbox_event_not_dead")
+)
+
+// Message é um evento a gravar. Reference identifica a origem no histórico
+// de entregas do owner (ex.: "appointment:42").
+type Message struct {
+	BarbershopID uint
+	Topic        string
+	Channel      string
+	Reference    string
+	Payload      any
+}
+
+type Outbox struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+type txKey struct{}
+
+// ContextWithTx liga ctx à transação: o que for gravado no outbox com esse
+// contexto só existe se a transação for confirmada.
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// conn devolve a transação ligada ao contexto ou, sem ela, a conexão base.
+func (o *Outbox) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok && tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return o.db.WithContext(ctx)
+}
+
+// Enqueue grava os eventos como pendentes, prontos para a próxima rodada do
+// Worker.
+func (o *Outbox) Enqueue(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rows := make([]models.OutboxEvent, 0, len(msgs))
+	for _, m := range msgs {
+		payload, err := json.Marshal(m.Payload)
+		if err != nil {
+			return fmt.Errorf("outbox payload %s: %w", m.Topic, err)
+		}
+		rows = append(rows, models.OutboxEvent{
+			BarbershopID:  m.BarbershopID,
+			Topic:         m.Topic,
+			Channel:       m.Channel,
+			Reference:     m.Reference,
+			Payload:       string(payload),
+			Status:        models.OutboxStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	return o.conn(ctx).Create(&rows).Error
+}
+
+// Retry devolve à fila um evento que esgotou as tentativas, zerando a
+// contagem.
+func (o *Outbox) Retry(ctx context.Context, barbershopID, id uint) error {
+	var ev models.OutboxEvent
+	err := o.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&ev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if ev.Status != models.OutboxStatusDead {
+		return ErrEventNotDead
+	}
+
+	res := o.db.WithContext(ctx).Exec(`
+		UPDATE outbox_events
+		SET status = ?, attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE id = ? AND status = ?
+	`, models.OutboxStatusPending, ev.ID, models.OutboxStatusDead)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrEventNotDead
+	}
+	return nil
+}
+
+// appointmentReference monta a referência de um agendamento; sem ID, fica vazia.
+func appointmentReference(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("appointment:%d", id)
+}
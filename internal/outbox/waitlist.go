@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+)
+
+// Waitlist grava no outbox o horário liberado por um cancelamento; o Worker
+// faz as ofertas fora da requisição. Implementa domain/waitlist.SlotListener.
+type Waitlist struct {
+	outbox *Outbox
+}
+
+func (o *Outbox) Waitlist() *Waitlist {
+	return &Waitlist{outbox: o}
+}
+
+// SlotFreed devolve a falha na gravação: dentro da transação do
+// cancelamento, o chamador a propaga e o cancelamento é desfeito.
+func (w *Waitlist) SlotFreed(ctx context.Context, slot domain.FreedSlot) error {
+	err := w.outbox.Enqueue(ctx, Message{
+		BarbershopID: slot.BarbershopID,
+		Topic:        TopicSlotFreed,
+		Reference:    fmt.Sprintf("barber:%d", slot.BarberID),
+		Payload:      slot,
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue slot_freed barber=%d: %w", slot.BarberID, err)
+	}
+	return nil
+}
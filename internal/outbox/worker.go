@@ -0,0 +1,227 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	// MaxAttempts: depois da última falha o evento vira 'dead' e só volta à
+	// fila pelo reenvio do owner.
+	MaxAttempts = 8
+
+	baseBackoff     = 30 * time.Second
+	maxBackoff      = 6 * time.Hour
+	deliveryTimeout = 30 * time.Second
+	batchSize       = 50
+	maxBatches      = 20
+	maxErrorLen     = 500
+)
+
+// Handler entrega um evento. Erro agenda nova tentativa.
+type Handler func(ctx context.Context, ev *models.OutboxEvent) error
+
+// CalendarSyncer é a sincronização síncrona do agendamento
+// (integration/calendar.GoogleSync).
+type CalendarSyncer interface {
+	SyncAppointment(ctx context.Context, barbershopID, appointmentID uint) error
+}
+
+// SlotOfferer oferece o horário liberado à lista de espera
+// (usecase/waitlist.OfferFreedSlot).
+type SlotOfferer interface {
+	Execute(ctx context.Context, slot domainWaitlist.FreedSlot) (int, error)
+}
+
+// Worker entrega os eventos pendentes. Deve rodar sob o lock de job, em um
+// nó por vez.
+type Worker struct {
+	db       *gorm.DB
+	handlers map[string]Handler
+	now      func() time.Time
+}
+
+func NewWorker(db *gorm.DB) *Worker {
+	return &Worker{
+		db:       db,
+		handlers: map[string]Handler{},
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func handlerKey(topic, channel string) string {
+	return topic + "/" + channel
+}
+
+// Handle registra o destino de um tópico em um canal ("" para tópicos sem canal).
+func (w *Worker) Handle(topic, channel string, h Handler) {
+	w.handlers[handlerKey(topic, channel)] = h
+}
+
+// HandleAppointmentNotifier entrega as notificações de agendamento do canal
+// pelo notifier dado.
+func (w *Worker) HandleAppointmentNotifier(channel string, n domain.AppointmentNotifier) {
+	w.Handle(TopicAppointmentConfirmed, channel, func(ctx context.Context, ev *models.OutboxEvent) error {
+		var in domain.AppointmentConfirmedInput
+		if err := json.Unmarshal([]byte(ev.Payload), &in); err != nil {
+			return err
+		}
+		return n.NotifyConfirmed(ctx, in)
+	})
+	w.Handle(TopicAppointmentCancelled, channel, func(ctx context.Context, ev *models.OutboxEvent) error {
+		var in domain.AppointmentCancelledInput
+		if err := json.Unmarshal([]byte(ev.Payload), &in); err != nil {
+			return err
+		}
+		return n.NotifyCancelled(ctx, in)
+	})
+	w.Handle(TopicAppointmentRescheduled, channel, func(ctx context.Context, ev *models.OutboxEvent) error {
+		var in domain.AppointmentRescheduledInput
+		if err := json.Unmarshal([]byte(ev.Payload), &in); err != nil {
+			return err
+		}
+		return n.NotifyRescheduled(ctx, in)
+	})
+}
+
+// HandleCalendar entrega a sincronização de agendamentos com o calendário.
+func (w *Worker) HandleCalendar(s CalendarSyncer) {
+	w.Handle(TopicCalendarSync, "", func(ctx context.Context, ev *models.OutboxEvent) error {
+		var p calendarPayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		return s.SyncAppointment(ctx, ev.BarbershopID, p.AppointmentID)
+	})
+}
+
+// HandleWaitlist entrega os horários liberados à lista de espera. Uma falha
+// volta para a fila; quem já recebeu a oferta não é candidato de novo.
+func (w *Worker) HandleWaitlist(o SlotOfferer) {
+	w.Handle(TopicSlotFreed, "", func(ctx context.Context, ev *models.OutboxEvent) error {
+		var slot domainWaitlist.FreedSlot
+		if err := json.Unmarshal([]byte(ev.Payload), &slot); err != nil {
+			return err
+		}
+		_, err := o.Execute(ctx, slot)
+		return err
+	})
+}
+
+// Run entrega os eventos vencidos, do mais antigo ao mais novo, em lotes
+// até esvaziar a fila (no máximo maxBatches por rodada), e devolve quantos
+// foram entregues e quantos falharam.
+func (w *Worker) Run(ctx context.Context) (delivered int, failed int, err error) {
+	for batch := 0; batch < maxBatches; batch++ {
+		var due []models.OutboxEvent
+		err = w.db.WithContext(ctx).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, w.now()).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&due).Error
+		if err != nil {
+			return delivered, failed, err
+		}
+
+		for i := range due {
+			ev := &due[i]
+			deliverErr := w.deliver(ctx, ev)
+			settle(ev, deliverErr, w.now())
+
+			if err := w.save(ctx, ev); err != nil {
+				return delivered, failed, err
+			}
+			if deliverErr != nil {
+				failed++
+				log.Printf("[OUTBOX] delivery failed id=%d topic=%s channel=%s attempt=%d status=%s: %v",
+					ev.ID, ev.Topic, ev.Channel, ev.Attempts, ev.Status, deliverErr)
+				continue
+			}
+			delivered++
+		}
+
+		if len(due) < batchSize {
+			break
+		}
+	}
+
+	return delivered, failed, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, ev *models.OutboxEvent) error {
+	h, ok := w.handlers[handlerKey(ev.Topic, ev.Channel)]
+	if !ok {
+		return fmt.Errorf("no handler for %s/%s", ev.Topic, ev.Channel)
+	}
+
+	dctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	return h(dctx, ev)
+}
+
+// save grava o resultado só se o evento ainda estiver pendente (um reenvio
+// do owner não é sobrescrito).
+func (w *Worker) save(ctx context.Context, ev *models.OutboxEvent) error {
+	return w.db.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id = ? AND status = ?", ev.ID, models.OutboxStatusPending).
+		Updates(map[string]any{
+			"status":          ev.Status,
+			"attempts":        ev.Attempts,
+			"next_attempt_at": ev.NextAttemptAt,
+			"last_error":      ev.LastError,
+			"delivered_at":    ev.DeliveredAt,
+			"updated_at":      ev.UpdatedAt,
+		}).Error
+}
+
+// settle aplica o resultado de uma tentativa: entregue, nova tentativa com
+// backoff ou, esgotadas as tentativas, 'dead'.
+func settle(ev *models.OutboxEvent, err error, now time.Time) {
+	ev.Attempts++
+	ev.UpdatedAt = now
+
+	if err == nil {
+		ev.Status = models.OutboxStatusDelivered
+		ev.DeliveredAt = &now
+		ev.LastError = nil
+		return
+	}
+
+	msg := err.Error()
+	if r := []rune(msg); len(r) > maxErrorLen {
+		msg = string(r[:maxErrorLen])
+	}
+	ev.LastError = &msg
+
+	if ev.Attempts >= MaxAttempts {
+		ev.Status = models.OutboxStatusDead
+		return
+	}
+	ev.NextAttemptAt = now.Add(Backoff(ev.Attempts))
+}
+
+// Backoff é a espera antes da próxima tentativa: 30s, 1min, 2min, 4min...
+// dobrando a cada falha, até 6h.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := baseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{50, 6 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := Backoff(tc.attempts); got != tc.want {
+			t.Errorf("Backoff(%d): esperado %s, obtido %s", tc.attempts, tc.want, got)
+		}
+	}
+}
+
+func TestSettle(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("entrega com sucesso", func(t *testing.T) {
+		msg := "falha anterior"
+		ev := &models.OutboxEvent{Status: models.OutboxStatusPending, Attempts: 2, LastError: &msg}
+		settle(ev, nil, now)
+
+		if ev.Status != models.OutboxStatusDelivered || ev.Attempts != 3 {
+			t.Fatalf("esperado delivered na 3ª tentativa, obtido %s/%d", ev.Status, ev.Attempts)
+		}
+		if ev.DeliveredAt == nil || !ev.DeliveredAt.Equal(now) || ev.LastError != nil {
+			t.Errorf("delivered_at/last_error inesperados: %v %v", ev.DeliveredAt, ev.LastError)
+		}
+	})
+
+	t.Run("falha agenda nova tentativa com backoff", func(t *testing.T) {
+		ev := &models.OutboxEvent{Status: models.OutboxStatusPending, Attempts: 1}
+		settle(ev, errors.New("smtp indisponível"), now)
+
+		if ev.Status != models.OutboxStatusPending || ev.Attempts != 2 {
+			t.Fatalf("esperado pending na 2ª tentativa, obtido %s/%d", ev.Status, ev.Attempts)
+		}
+		if !ev.NextAttemptAt.Equal(now.Add(time.Minute)) {
+			t.Errorf("next_attempt_at: esperado %s, obtido %s", now.Add(time.Minute), ev.NextAttemptAt)
+		}
+		if ev.LastError == nil || *ev.LastError != "smtp indisponível" {
+			t.Errorf("last_error inesperado: %v", ev.LastError)
+		}
+	})
+
+	t.Run("última tentativa vira dead", func(t *testing.T) {
+		ev := &models.OutboxEvent{Status: models.OutboxStatusPending, Attempts: MaxAttempts - 1}
+		settle(ev, errors.New("timeout"), now)
+
+		if ev.Status != models.OutboxStatusDead || ev.Attempts != MaxAttempts {
+			t.Fatalf("esperado dead, obtido %s/%d", ev.Status, ev.Attempts)
+		}
+	})
+
+	t.Run("erro longo é truncado", func(t *testing.T) {
+		ev := &models.OutboxEvent{Status: models.OutboxStatusPending}
+		settle(ev, errors.New(strings.Repeat("é", maxErrorLen+10)), now)
+
+		if n := len([]rune(*ev.LastError)); n != maxErrorLen {
+			t.Errorf("esperado %d runas, obtido %d", maxErrorLen, n)
+		}
+	})
+}
+
+type fakeNotifier struct {
+	confirmed []domain.AppointmentConfirmedInput
+}
+
+func (f *fakeNotifier) NotifyConfirmed(_ context.Context, in domain.AppointmentConfirmedInput) error {
+	f.confirmed = append(f.confirmed, in)
+	return nil
+}
+
+func (f *fakeNotifier) NotifyCancelled(context.Context, domain.AppointmentCancelledInput) error {
+	return nil
+}
+
+func (f *fakeNotifier) NotifyRescheduled(context.Context, domain.AppointmentRescheduledInput) error {
+	return nil
+}
+
+func TestWorkerDeliver(t *testing.T) {
+	n := &fakeNotifier{}
+	w := NewWorker(nil)
+	w.HandleAppointmentNotifier(ChannelEmail, n)
+
+	ev := &models.OutboxEvent{
+		Topic:   TopicAppointmentConfirmed,
+		Channel: ChannelEmail,
+		Payload: `{"BarbershopID":1,"AppointmentID":42,"ClientEmail":"ana@example.com"}`,
+	}
+	if err := w.deliver(context.Background(), ev); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(n.confirmed) != 1 || n.confirmed[0].AppointmentID != 42 || n.confirmed[0].ClientEmail != "ana@example.com" {
+		t.Fatalf("payload não chegou ao notifier: %+v", n.confirmed)
+	}
+
+	ev.Channel = ChannelWhatsApp
+	if err := w.deliver(context.Background(), ev); err == nil {
+		t.Error("esperado erro para canal sem handler")
+	}
+}
+
+type fakeOfferer struct {
+	slots []domainWaitlist.FreedSlot
+	err   error
+}
+
+func (f *fakeOfferer) Execute(_ context.Context, slot domainWaitlist.FreedSlot) (int, error) {
+	f.slots = append(f.slots, slot)
+	return 1, f.err
+}
+
+func TestWorkerDeliverSlotFreed(t *testing.T) {
+	o := &fakeOfferer{}
+	w := NewWorker(nil)
+	w.HandleWaitlist(o)
+
+	start := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	payload, err := json.Marshal(domainWaitlist.FreedSlot{
+		BarbershopID: 1,
+		BarberID:     7,
+		StartTime:    start,
+		EndTime:      start.Add(30 * time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := &models.OutboxEvent{BarbershopID: 1, Topic: TopicSlotFreed, Payload: string(payload)}
+
+	if err := w.deliver(context.Background(), ev); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(o.slots) != 1 || o.slots[0].BarberID != 7 || !o.slots[0].StartTime.Equal(start) {
+		t.Fatalf("horário não chegou à lista de espera: %+v", o.slots)
+	}
+
+	// Falha na oferta volta para a fila em vez de ser engolida.
+	o.err = errors.New("banco indisponível")
+	if err := w.deliver(context.Background(), ev); err == nil {
+		t.Error("esperado erro para nova tentativa")
+	}
+}
@@ -0,0 +1,55 @@
+package clientportal
+
+import "time"
+
+// AppointmentDTO é um agendamento do cliente visto pelo portal. TicketToken
+// vem preenchido enquanto o ticket do agendamento vale, e dá acesso às
+// rotas públicas de cancelar e remarcar.
+type AppointmentDTO struct {
+	ID          uint      `json:"id"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Status      string    `json:"status"`
+	ServiceName string    `json:"service_name"`
+	BarberName  string    `json:"barber_name"`
+	TicketToken *string   `json:"ticket_token,omitempty"`
+}
+
+// SubscriptionDTO é a assinatura ativa do cliente.
+type SubscriptionDTO struct {
+	PlanID        uint      `json:"plan_id"`
+	PlanName      string    `json:"plan_name"`
+	CutsIncluded  int       `json:"cuts_included"`
+	CutsUsed      int       `json:"cuts_used"`
+	CutsRemaining int       `json:"cuts_remaining"`
+	ValidUntil    time.Time `json:"valid_until"`
+}
+
+// PackageDTO é um pacote pré-pago utilizável do cliente (ativo, não vencido
+// e com créditos).
+type PackageDTO struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	CreditsTotal int       `json:"credits_total"`
+	CreditsUsed  int       `json:"credits_used"`
+	CreditsLeft  int       `json:"credits_left"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type OrderItemDTO struct {
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	ReturnedQuantity int    `json:"returned_quantity"`
+	UnitPrice        int64  `json:"unit_price"`
+	LineTotal        int64  `json:"line_total"`
+}
+
+type OrderDTO struct {
+	ID             uint           `json:"id"`
+	Status         string         `json:"status"`
+	SubtotalAmount int64          `json:"subtotal_amount"`
+	DiscountAmount int64          `json:"discount_amount"`
+	TotalAmount    int64          `json:"total_amount"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Items          []OrderItemDTO `json:"items"`
+}
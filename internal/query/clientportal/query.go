@@ -0,0 +1,168 @@
+package clientportal
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
+)
+
+const (
+	ScopeUpcoming = "upcoming"
+	ScopePast     = "past"
+)
+
+// Query reúne as leituras do portal do cliente. Todas filtram pela
+// barbearia e pelo cliente da sessão.
+type Query struct {
+	db *gorm.DB
+}
+
+func NewQuery(db *gorm.DB) *Query {
+	return &Query{db: db}
+}
+
+// Appointments lista os agendamentos do cliente. ScopeUpcoming traz os
+// futuros ainda em aberto, do mais próximo ao mais distante; ScopePast traz
+// os demais, do mais recente ao mais antigo.
+func (q *Query) Appointments(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	scope string,
+	limit int,
+) ([]AppointmentDTO, error) {
+	tx := q.db.WithContext(ctx).
+		Table("appointments a").
+		Select(`
+			a.id, a.start_time, a.end_time, a.status,
+			COALESCE(bs.name, '') AS service_name,
+			COALESCE(u.name, '')  AS barber_name,
+			CASE WHEN t.expires_at > NOW() THEN t.token END AS ticket_token
+		`).
+		Joins("LEFT JOIN barbershop_services bs ON bs.id = a.barber_product_id").
+		Joins("LEFT JOIN users u ON u.id = a.barber_id").
+		Joins("LEFT JOIN appointment_tickets t ON t.appointment_id = a.id").
+		Where("a.barbershop_id = ? AND a.client_id = ?", barbershopID, clientID)
+
+	open := "a.status IN ('scheduled', 'awaiting_payment') AND a.start_time >= NOW()"
+	if scope == ScopePast {
+		tx = tx.Where("NOT (" + open + ")").Order("a.start_time DESC")
+	} else {
+		tx = tx.Where(open).Order("a.start_time ASC")
+	}
+
+	out := []AppointmentDTO{}
+	err := tx.Limit(limit).Scan(&out).Error
+	return out, err
+}
+
+// Subscription retorna a assinatura ativa do cliente, ou nil.
+func (q *Query) Subscription(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*SubscriptionDTO, error) {
+	var row SubscriptionDTO
+
+	err := q.db.WithContext(ctx).Raw(`
+		SELECT s.plan_id, p.name AS plan_name,
+		       p.cuts_included,
+		       s.cuts_used_in_period AS cuts_used,
+		       s.current_period_end AS valid_until
+		FROM subscriptions s
+		JOIN plans p ON p.id = s.plan_id
+		WHERE s.barbershop_id = ?
+		  AND s.client_id = ?
+		  AND `+shared.ActiveSubscriptionSQL+`
+		LIMIT 1
+	`, barbershopID, clientID).Scan(&row).Error
+	if err != nil || row.PlanID == 0 {
+		return nil, err
+	}
+
+	row.CutsRemaining = row.CutsIncluded - row.CutsUsed
+	if row.CutsRemaining < 0 {
+		row.CutsRemaining = 0
+	}
+	return &row, nil
+}
+
+// Packages lista os pacotes utilizáveis do cliente, do que vence primeiro
+// ao último.
+func (q *Query) Packages(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) ([]PackageDTO, error) {
+	out := []PackageDTO{}
+
+	err := q.db.WithContext(ctx).Raw(`
+		SELECT id, package_name AS name, credits_total, credits_used,
+		       credits_total - credits_used AS credits_left,
+		       expires_at
+		FROM client_packages
+		WHERE barbershop_id = ?
+		  AND client_id = ?
+		  AND status = 'active'
+		  AND expires_at > NOW()
+		  AND credits_used < credits_total
+		ORDER BY expires_at ASC
+	`, barbershopID, clientID).Scan(&out).Error
+	return out, err
+}
+
+// Orders lista os pedidos do cliente com os itens, do mais recente ao mais
+// antigo.
+func (q *Query) Orders(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	limit int,
+) ([]OrderDTO, error) {
+	orders := []OrderDTO{}
+
+	err := q.db.WithContext(ctx).
+		Table("orders").
+		Select("id, status, subtotal_amount, discount_amount, total_amount, created_at").
+		Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Scan(&orders).Error
+	if err != nil || len(orders) == 0 {
+		return orders, err
+	}
+
+	ids := make([]uint, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	var items []struct {
+		OrderID uint
+		OrderItemDTO
+	}
+	err = q.db.WithContext(ctx).
+		Table("order_items").
+		Select(`order_id, product_name_snapshot AS product_name, quantity,
+			returned_quantity, unit_price, line_total`).
+		Where("order_id IN ?", ids).
+		Order("id ASC").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byOrder := make(map[uint][]OrderItemDTO, len(orders))
+	for _, it := range items {
+		byOrder[it.OrderID] = append(byOrder[it.OrderID], it.OrderItemDTO)
+	}
+	for i := range orders {
+		orders[i].Items = byOrder[orders[i].ID]
+		if orders[i].Items == nil {
+			orders[i].Items = []OrderItemDTO{}
+		}
+	}
+	return orders, nil
+}
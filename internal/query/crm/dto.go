@@ -41,6 +41,68 @@ type SubscriptionDTO struct {
 	ValidUntil   time.Time `json:"valid_until"`
 }
 
+// PackageDTO is a usable prepaid package of the client (active, not expired,
+// with credits left).
+type PackageDTO struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	CreditsTotal int       `json:"credits_total"`
+	CreditsUsed  int       `json:"credits_used"`
+	CreditsLeft  int       `json:"credits_left"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// ComboUsageDTO counts the client's completed appointments booked as a combo.
+type ComboUsageDTO struct {
+	ComboID   uint   `json:"combo_id"`
+	Name      string `json:"name"`
+	Completed int    `json:"completed"`
+}
+
+// LoyaltyDTO is the client's loyalty points balance (nil when the program is
+// off and the client has no points left).
+type LoyaltyDTO struct {
+	Balance        int        `json:"balance"`
+	ExpiringPoints int        `json:"expiring_points"` // expiring within 30 days
+	NextExpiresAt  *time.Time `json:"next_expires_at,omitempty"`
+}
+
+// NoteDTO is a free-text note the staff wrote about the client.
+type NoteDTO struct {
+	ID         uint      `json:"id"`
+	Body       string    `json:"body"`
+	Pinned     bool      `json:"pinned"`
+	AuthorName string    `json:"author_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TagDTO is a barbershop-defined tag assigned to the client.
+type TagDTO struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// PreferencesDTO carries the client's structured preferences (nil when none
+// were recorded).
+type PreferencesDTO struct {
+	PreferredBarberID   *uint  `json:"preferred_barber_id,omitempty"`
+	PreferredBarberName string `json:"preferred_barber_name,omitempty"`
+	Allergies           string `json:"allergies"`
+	FavoriteServiceIDs  []uint `json:"favorite_service_ids"`
+	FavoriteProductIDs  []uint `json:"favorite_product_ids"`
+	Beverage            string `json:"beverage"`
+}
+
+// PhotoDTO is a before/after photo taken during an appointment.
+type PhotoDTO struct {
+	ID            uint      `json:"id"`
+	AppointmentID uint      `json:"appointment_id"`
+	Kind          string    `json:"kind"` // before|after
+	URL           string    `json:"url"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
 // FlagsDTO are pre-computed boolean signals for fast operational decisions.
 type FlagsDTO struct {
 	Premium   bool `json:"premium"`   // has active subscription
@@ -62,5 +124,12 @@ type ResponseDTO struct {
 	Metrics      MetricsDTO       `json:"metrics"`
 	Flags        FlagsDTO         `json:"flags"`
 	Subscription *SubscriptionDTO `json:"subscription,omitempty"`
+	Packages     []PackageDTO     `json:"packages"`
+	Combos       []ComboUsageDTO  `json:"combos"`
+	Loyalty      *LoyaltyDTO      `json:"loyalty,omitempty"`
+	Notes        []NoteDTO        `json:"notes"` // pinned first, latest 20
+	Tags         []TagDTO         `json:"tags"`
+	Preferences  *PreferencesDTO  `json:"preferences,omitempty"`
+	Photos       []PhotoDTO       `json:"photos"` // latest 12
 	Policy       PolicyDTO        `json:"policy"`
 }
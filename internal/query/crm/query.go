@@ -2,6 +2,7 @@ package crm
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -9,19 +10,27 @@ import (
 
 	domainMetrics "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
 	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
+	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 )
 
 var ErrClientNotFound = errors.New("client not found")
 
 // Query is the read-only CRM service for a single client.
 type Query struct {
-	db *gorm.DB
+	db    *gorm.DB
+	rules domainMetrics.ClassificationRulesRepository
 }
 
 func New(db *gorm.DB) *Query {
 	return &Query{db: db}
 }
 
+// WithRules classifica com as regras da barbearia; sem elas, valem as padrão.
+func (q *Query) WithRules(rules domainMetrics.ClassificationRulesRepository) *Query {
+	q.rules = rules
+	return q
+}
+
 // DB expõe o *gorm.DB para checagens pontuais no handler sem duplicar dependência.
 func (q *Query) DB() *gorm.DB { return q.db }
 
@@ -30,7 +39,7 @@ func (q *Query) DB() *gorm.DB { return q.db }
 // ----------------------------------------------------------------
 
 func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*ResponseDTO, error) {
-	// 1–3. Run all three independent queries in parallel.
+	// 1–3. Run all independent queries in parallel.
 	// None depends on the output of another during the fetch phase; post-processing
 	// (category resolution, flags, policy) happens after all results are collected.
 	var client struct {
@@ -62,11 +71,48 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 		CutsIncluded int       `gorm:"column:cuts_included"`
 		ValidUntil   time.Time `gorm:"column:valid_until"`
 	}
+	var pkgRows []struct {
+		ID           uint      `gorm:"column:id"`
+		Name         string    `gorm:"column:package_name"`
+		CreditsTotal int       `gorm:"column:credits_total"`
+		CreditsUsed  int       `gorm:"column:credits_used"`
+		ExpiresAt    time.Time `gorm:"column:expires_at"`
+	}
+	var comboRows []struct {
+		ComboID   uint   `gorm:"column:combo_id"`
+		Name      string `gorm:"column:name"`
+		Completed int    `gorm:"column:completed"`
+	}
+	var loyaltyRow struct {
+		ProgramActive  bool       `gorm:"column:program_active"`
+		Balance        int        `gorm:"column:balance"`
+		ExpiringPoints int        `gorm:"column:expiring_points"`
+		NextExpiresAt  *time.Time `gorm:"column:next_expires_at"`
+	}
+	var noteRows []NoteDTO
+	var tagRows []TagDTO
+	var prefRow struct {
+		ClientID            uint   `gorm:"column:client_id"`
+		PreferredBarberID   *uint  `gorm:"column:preferred_barber_id"`
+		PreferredBarberName string `gorm:"column:preferred_barber_name"`
+		Allergies           string `gorm:"column:allergies"`
+		FavoriteServiceIDs  string `gorm:"column:favorite_service_ids"`
+		FavoriteProductIDs  string `gorm:"column:favorite_product_ids"`
+		Beverage            string `gorm:"column:beverage"`
+	}
+	var photoRows []PhotoDTO
 	metricsFound := true
 
 	clientCh  := make(chan error, 1)
 	metricsCh := make(chan error, 1)
 	subCh     := make(chan error, 1)
+	pkgCh     := make(chan error, 1)
+	comboCh   := make(chan error, 1)
+	loyaltyCh := make(chan error, 1)
+	notesCh   := make(chan error, 1)
+	tagsCh    := make(chan error, 1)
+	prefsCh   := make(chan error, 1)
+	photosCh  := make(chan error, 1)
 
 	go func() {
 		clientCh <- q.db.WithContext(ctx).
@@ -103,12 +149,109 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 		`, barbershopID, clientID).Scan(&subRow).Error
 	}()
 
+	go func() {
+		pkgCh <- q.db.WithContext(ctx).Raw(`
+			SELECT id, package_name, credits_total, credits_used, expires_at
+			FROM client_packages
+			WHERE barbershop_id = ?
+			  AND client_id = ?
+			  AND status = 'active'
+			  AND expires_at > NOW()
+			  AND credits_used < credits_total
+			ORDER BY expires_at ASC
+		`, barbershopID, clientID).Scan(&pkgRows).Error
+	}()
+
+	go func() {
+		comboCh <- q.db.WithContext(ctx).Raw(`
+			SELECT a.combo_id, sc.name, COUNT(*) AS completed
+			FROM appointments a
+			JOIN service_combos sc ON sc.id = a.combo_id
+			WHERE a.barbershop_id = ?
+			  AND a.client_id = ?
+			  AND a.status = 'completed'
+			GROUP BY a.combo_id, sc.name
+			ORDER BY completed DESC
+		`, barbershopID, clientID).Scan(&comboRows).Error
+	}()
+
+	go func() {
+		loyaltyCh <- q.db.WithContext(ctx).Raw(`
+			SELECT
+				EXISTS (
+					SELECT 1 FROM loyalty_programs lp
+					WHERE lp.barbershop_id = ? AND lp.active
+				) AS program_active,
+				COALESCE(SUM(remaining_points), 0) AS balance,
+				COALESCE(SUM(remaining_points) FILTER (WHERE expires_at <= NOW() + INTERVAL '30 days'), 0) AS expiring_points,
+				MIN(expires_at) AS next_expires_at
+			FROM loyalty_entries
+			WHERE barbershop_id = ?
+			  AND client_id = ?
+			  AND kind = 'earn'
+			  AND remaining_points > 0
+			  AND (expires_at IS NULL OR expires_at > NOW())
+		`, barbershopID, barbershopID, clientID).Scan(&loyaltyRow).Error
+	}()
+
+	go func() {
+		notesCh <- q.db.WithContext(ctx).Raw(`
+			SELECT n.id, n.body, n.pinned, COALESCE(u.name, '') AS author_name, n.created_at
+			FROM client_notes n
+			LEFT JOIN users u ON u.id = n.author_user_id
+			WHERE n.barbershop_id = ?
+			  AND n.client_id = ?
+			ORDER BY n.pinned DESC, n.created_at DESC, n.id DESC
+			LIMIT 20
+		`, barbershopID, clientID).Scan(&noteRows).Error
+	}()
+
+	go func() {
+		tagsCh <- q.db.WithContext(ctx).Raw(`
+			SELECT t.id, t.name, t.color
+			FROM client_tag_assignments a
+			JOIN client_tags t ON t.id = a.tag_id
+			WHERE a.barbershop_id = ?
+			  AND a.client_id = ?
+			ORDER BY LOWER(t.name), t.id
+		`, barbershopID, clientID).Scan(&tagRows).Error
+	}()
+
+	go func() {
+		prefsCh <- q.db.WithContext(ctx).Raw(`
+			SELECT p.client_id, p.preferred_barber_id, COALESCE(u.name, '') AS preferred_barber_name,
+			       p.allergies, p.favorite_service_ids, p.favorite_product_ids, p.beverage
+			FROM client_preferences p
+			LEFT JOIN users u ON u.id = p.preferred_barber_id
+			WHERE p.barbershop_id = ?
+			  AND p.client_id = ?
+		`, barbershopID, clientID).Scan(&prefRow).Error
+	}()
+
+	go func() {
+		photosCh <- q.db.WithContext(ctx).Raw(`
+			SELECT id, appointment_id, kind, url, created_at
+			FROM client_photos
+			WHERE barbershop_id = ?
+			  AND client_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT 12
+		`, barbershopID, clientID).Scan(&photoRows).Error
+	}()
+
 	// Always drain all channels before returning any error.
 	// Channel receives happen-after the goroutine sends, guaranteeing memory
 	// visibility of client, m, subRow, and metricsFound without additional sync.
 	clientErr  := <-clientCh
 	metricsErr := <-metricsCh
 	subErr     := <-subCh
+	pkgErr     := <-pkgCh
+	comboErr   := <-comboCh
+	loyaltyErr := <-loyaltyCh
+	notesErr   := <-notesCh
+	tagsErr    := <-tagsCh
+	prefsErr   := <-prefsCh
+	photosErr  := <-photosCh
 
 	if clientErr != nil {
 		if errors.Is(clientErr, gorm.ErrRecordNotFound) {
@@ -121,12 +264,25 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 	// discarded subscription query errors (treating them as "no active subscription").
 	// A transient failure here must not abort the CRM request.
 	_ = subErr
+	// Pacotes e combos seguem a mesma regra: seções complementares do card.
+	_ = pkgErr
+	_ = comboErr
+	_ = loyaltyErr
+	// O perfil (anotações, etiquetas, preferências, fotos) também.
+	_ = notesErr
+	_ = tagsErr
+	_ = photosErr
 
 	// 4. Resolve category (apply classifier for auto, respect manual if not expired)
 	category := domainMetrics.CategoryNew
 	categorySource := "auto"
 
 	if metricsFound {
+		rules, err := ucMetrics.RulesFor(ctx, q.rules, barbershopID)
+		if err != nil {
+			return nil, err
+		}
+
 		dm := &domainMetrics.ClientMetrics{
 			TotalAppointments:           m.TotalAppointments,
 			CompletedAppointments:       m.CompletedAppointments,
@@ -135,6 +291,7 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 			NoShowAppointments:          m.NoShowAppointments,
 			RescheduledAppointments:     m.RescheduledAppointments,
 			LateRescheduledAppointments: m.LateRescheduledAppointments,
+			TotalSpent:                  m.TotalSpent,
 			LastCompletedAt:             m.LastCompletedAt,
 			ManualCategoryExpiresAt:     m.ManualCategoryExpiresAt,
 			CategorySource:              domainMetrics.CategorySource(m.CategorySource),
@@ -146,10 +303,10 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 				category = dm.Category
 				categorySource = "manual"
 			} else {
-				category = domainMetrics.Classify(dm)
+				category = rules.Classify(dm, time.Now().UTC())
 			}
 		} else {
-			category = domainMetrics.Classify(dm)
+			category = rules.Classify(dm, time.Now().UTC())
 		}
 	}
 
@@ -165,6 +322,51 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 		}
 	}
 
+	packages := make([]PackageDTO, 0, len(pkgRows))
+	for _, r := range pkgRows {
+		packages = append(packages, PackageDTO{
+			ID:           r.ID,
+			Name:         r.Name,
+			CreditsTotal: r.CreditsTotal,
+			CreditsUsed:  r.CreditsUsed,
+			CreditsLeft:  r.CreditsTotal - r.CreditsUsed,
+			ExpiresAt:    r.ExpiresAt,
+		})
+	}
+	combos := make([]ComboUsageDTO, 0, len(comboRows))
+	for _, r := range comboRows {
+		combos = append(combos, ComboUsageDTO{ComboID: r.ComboID, Name: r.Name, Completed: r.Completed})
+	}
+
+	// Fidelidade só aparece com o programa ativo ou com saldo a usar.
+	var loyalty *LoyaltyDTO
+	if loyaltyErr == nil && (loyaltyRow.ProgramActive || loyaltyRow.Balance > 0) {
+		loyalty = &LoyaltyDTO{
+			Balance:        loyaltyRow.Balance,
+			ExpiringPoints: loyaltyRow.ExpiringPoints,
+			NextExpiresAt:  loyaltyRow.NextExpiresAt,
+		}
+	}
+
+	notes := make([]NoteDTO, 0, len(noteRows))
+	notes = append(notes, noteRows...)
+	tags := make([]TagDTO, 0, len(tagRows))
+	tags = append(tags, tagRows...)
+	photos := make([]PhotoDTO, 0, len(photoRows))
+	photos = append(photos, photoRows...)
+
+	var prefs *PreferencesDTO
+	if prefsErr == nil && prefRow.ClientID != 0 {
+		prefs = &PreferencesDTO{
+			PreferredBarberID:   prefRow.PreferredBarberID,
+			PreferredBarberName: prefRow.PreferredBarberName,
+			Allergies:           prefRow.Allergies,
+			FavoriteServiceIDs:  decodeIDs(prefRow.FavoriteServiceIDs),
+			FavoriteProductIDs:  decodeIDs(prefRow.FavoriteProductIDs),
+			Beverage:            prefRow.Beverage,
+		}
+	}
+
 	// 6. Compute metrics DTO
 	var attendanceRate float64
 	if metricsFound && m.TotalAppointments > 0 {
@@ -213,10 +415,26 @@ func (q *Query) Execute(ctx context.Context, barbershopID, clientID uint) (*Resp
 		Metrics:      metricsDTO,
 		Flags:        flags,
 		Subscription: sub,
+		Packages:     packages,
+		Combos:       combos,
+		Loyalty:      loyalty,
+		Notes:        notes,
+		Tags:         tags,
+		Preferences:  prefs,
+		Photos:       photos,
 		Policy:       policy,
 	}, nil
 }
 
+// decodeIDs reads a JSONB id array; malformed content yields an empty list.
+func decodeIDs(raw string) []uint {
+	ids := []uint{}
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		return []uint{}
+	}
+	return ids
+}
+
 // resolvePolicy derives the operational booking policy for the client.
 // Payment upfront is only required when there is concrete no-show evidence,
 // not for inactivity or cancellations.
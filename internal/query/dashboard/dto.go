@@ -65,6 +65,21 @@ type ProductRankItem struct {
 	RevenueCents int64  `json:"revenue_cents"`
 }
 
+// CouponsDTO summarizes coupon redemptions in the period.
+type CouponsDTO struct {
+	Redemptions   int              `json:"redemptions"`
+	DiscountCents int64            `json:"discount_cents"`
+	TopCoupons    []CouponRankItem `json:"top_coupons"`
+}
+
+// CouponRankItem is a single entry in the coupon ranking.
+type CouponRankItem struct {
+	CouponID      uint   `json:"coupon_id"`
+	Code          string `json:"code"`
+	Redemptions   int    `json:"redemptions"`
+	DiscountCents int64  `json:"discount_cents"`
+}
+
 // ResponseDTO is the full dashboard payload for the period.
 type ResponseDTO struct {
 	Period   string `json:"period"`
@@ -77,4 +92,5 @@ type ResponseDTO struct {
 	Clients     ClientsDTO         `json:"clients"`
 	TopServices []ServiceRankItem  `json:"top_services"`
 	TopProducts []ProductRankItem  `json:"top_products"`
+	Coupons     CouponsDTO         `json:"coupons"`
 }
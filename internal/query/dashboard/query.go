@@ -8,6 +8,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
 )
 
@@ -18,6 +19,8 @@ var ErrInvalidPeriod = errors.New("invalid period, expected: day|week|month")
 type Input struct {
 	BarbershopID uint
 	Period       PeriodType // day|week|month; default = week
+	// Reference escolhe o período que contém essa data; zero = agora.
+	Reference time.Time
 }
 
 // Query is the read-only service for the dashboard.
@@ -59,7 +62,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	}
 
 	loc := timezone.Location(shop.Timezone)
-	startUTC, endUTC := periodRange(period, loc)
+	startUTC, endUTC := periodRange(period, loc, input.Reference)
 
 	dateFrom := startUTC.In(loc).Format("2006-01-02")
 	dateTo := endUTC.Add(-time.Second).In(loc).Format("2006-01-02")
@@ -72,6 +75,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		clients     ClientsDTO
 		topServices []ServiceRankItem
 		topProducts []ProductRankItem
+		coupons     CouponsDTO
 	)
 
 	g, gctx := errgroup.WithContext(ctx)
@@ -100,6 +104,11 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		topProducts, err = q.loadTopProducts(gctx, input.BarbershopID, startUTC, endUTC)
 		return err
 	})
+	g.Go(func() error {
+		var err error
+		coupons, err = q.loadCoupons(gctx, input.BarbershopID, startUTC, endUTC)
+		return err
+	})
 
 	if err := g.Wait(); err != nil {
 		return nil, err
@@ -115,6 +124,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		Clients:     clients,
 		TopServices: topServices,
 		TopProducts: topProducts,
+		Coupons:     coupons,
 	}, nil
 }
 
@@ -207,7 +217,7 @@ func (q *Query) loadRevenue(ctx context.Context, barbershopID uint, start, end t
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
 			COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS total,
-			COALESCE(SUM(CASE WHEN ac.subscription_covered THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents) ELSE 0 END), 0) AS subscription_part,
+			COALESCE(SUM(CASE WHEN ac.subscription_covered THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents) ELSE ac.subscription_covered_cents END), 0) AS subscription_part,
 			COUNT(*) AS closures_count
 		FROM appointment_closures ac
 		JOIN appointments a ON a.id = ac.appointment_id
@@ -263,11 +273,11 @@ func (q *Query) loadRevenue(ctx context.Context, barbershopID uint, start, end t
 	// Mensalidades de assinatura pagas no período.
 	var subscriptionPaymentRevenue int64
 	err = q.db.WithContext(ctx).Raw(`
-		SELECT COALESCE(SUM(p.amount), 0)
+		SELECT COALESCE(SUM(`+shared.NetPaymentAmountSQL+`), 0)
 		FROM payments p
 		WHERE p.barbershop_id = ?
 		  AND p.subscription_id IS NOT NULL
-		  AND p.status = 'paid'
+		  AND `+shared.RevenuePaymentSQL+`
 		  AND p.paid_at >= ?
 		  AND p.paid_at < ?
 	`, barbershopID, start, end).Scan(&subscriptionPaymentRevenue).Error
@@ -363,20 +373,47 @@ func (q *Query) loadTopServices(ctx context.Context, barbershopID uint, start, e
 		RevenueCents int64  `gorm:"column:revenue_cents"`
 	}
 
+	// Agendamento com vários serviços conta uma vez para cada serviço; a
+	// receita do fechamento é rateada pelo preço de cada serviço no booking.
 	var rows []row
 	err := q.db.WithContext(ctx).Raw(`
+		WITH closed AS (
+			SELECT
+				ac.appointment_id,
+				ac.service_id,
+				ac.service_name,
+				COALESCE(ac.final_amount_cents, ac.reference_amount_cents) AS amount,
+				(SELECT COUNT(*) FROM appointment_services s WHERE s.appointment_id = ac.appointment_id) AS lines,
+				(SELECT COALESCE(SUM(s.price_cents), 0) FROM appointment_services s WHERE s.appointment_id = ac.appointment_id) AS lines_total
+			FROM appointment_closures ac
+			JOIN appointments a ON a.id = ac.appointment_id
+			WHERE ac.barbershop_id = ?
+			  AND a.start_time >= ?
+			  AND a.start_time < ?
+		),
+		items AS (
+			SELECT c.service_id, c.service_name, c.amount AS revenue
+			FROM closed c
+			WHERE c.lines <= 1 AND c.service_id IS NOT NULL
+			UNION ALL
+			SELECT
+				s.service_id,
+				s.service_name,
+				CASE WHEN c.lines_total > 0
+				     THEN c.amount * s.price_cents / c.lines_total
+				     ELSE c.amount / c.lines
+				END AS revenue
+			FROM closed c
+			JOIN appointment_services s ON s.appointment_id = c.appointment_id
+			WHERE c.lines > 1 AND s.service_id IS NOT NULL
+		)
 		SELECT
-			ac.service_id,
-			ac.service_name,
-			COUNT(*)  AS count,
-			COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS revenue_cents
-		FROM appointment_closures ac
-		JOIN appointments a ON a.id = ac.appointment_id
-		WHERE ac.barbershop_id = ?
-		  AND ac.service_id IS NOT NULL
-		  AND a.start_time >= ?
-		  AND a.start_time < ?
-		GROUP BY ac.service_id, ac.service_name
+			service_id,
+			service_name,
+			COUNT(*) AS count,
+			COALESCE(SUM(revenue), 0) AS revenue_cents
+		FROM items
+		GROUP BY service_id, service_name
 		ORDER BY revenue_cents DESC
 		LIMIT 5
 	`, barbershopID, start, end).Scan(&rows).Error
@@ -453,8 +490,11 @@ func (q *Query) loadTopProducts(ctx context.Context, barbershopID uint, start, e
 // Period helpers
 // ----------------------------------------------------------------
 
-func periodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.Time) {
-	now := time.Now().In(loc)
+func periodRange(period PeriodType, loc *time.Location, ref time.Time) (startUTC, endUTC time.Time) {
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	now := ref.In(loc)
 	var localStart time.Time
 
 	switch period {
@@ -483,3 +523,51 @@ func periodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.T
 
 	return localStart.UTC(), localEnd.UTC()
 }
+
+// ----------------------------------------------------------------
+// Coupons
+// ----------------------------------------------------------------
+
+func (q *Query) loadCoupons(ctx context.Context, barbershopID uint, start, end time.Time) (CouponsDTO, error) {
+	type row struct {
+		CouponID      uint   `gorm:"column:coupon_id"`
+		Code          string `gorm:"column:code"`
+		Redemptions   int    `gorm:"column:redemptions"`
+		DiscountCents int64  `gorm:"column:discount_cents"`
+	}
+
+	var rows []row
+	err := q.db.WithContext(ctx).Raw(`
+		SELECT
+			r.coupon_id,
+			c.code,
+			COUNT(*)              AS redemptions,
+			SUM(r.discount_cents) AS discount_cents
+		FROM coupon_redemptions r
+		JOIN coupons c ON c.id = r.coupon_id
+		WHERE r.barbershop_id = ?
+		  AND r.created_at >= ?
+		  AND r.created_at < ?
+		  AND r.voided_at IS NULL
+		GROUP BY r.coupon_id, c.code
+		ORDER BY redemptions DESC, discount_cents DESC
+	`, barbershopID, start, end).Scan(&rows).Error
+	if err != nil {
+		return CouponsDTO{}, err
+	}
+
+	result := CouponsDTO{TopCoupons: []CouponRankItem{}}
+	for i, r := range rows {
+		result.Redemptions += r.Redemptions
+		result.DiscountCents += r.DiscountCents
+		if i < 5 {
+			result.TopCoupons = append(result.TopCoupons, CouponRankItem{
+				CouponID:      r.CouponID,
+				Code:          r.Code,
+				Redemptions:   r.Redemptions,
+				DiscountCents: r.DiscountCents,
+			})
+		}
+	}
+	return result, nil
+}
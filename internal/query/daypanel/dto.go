@@ -2,16 +2,31 @@ package daypanel
 
 import "time"
 
-// ClientDTO carries the client identity and behavioral classification.
+// ClientDTO carries the client identity, behavioral classification and the
+// profile bits the barber needs at the chair.
 type ClientDTO struct {
 	ID       uint   `json:"id"`
 	Name     string `json:"name"`
 	Phone    string `json:"phone"`
 	Email    string `json:"email,omitempty"`
 	Category string `json:"category"` // new|regular|trusted|at_risk
+
+	Tags         []TagDTO `json:"tags,omitempty"`
+	PinnedNotes  []string `json:"pinned_notes,omitempty"` // up to 3, latest first
+	Allergies    string   `json:"allergies,omitempty"`
+	Beverage     string   `json:"beverage,omitempty"`
+	LastPhotoURL string   `json:"last_photo_url,omitempty"` // latest "after" photo
+}
+
+// TagDTO is a barbershop-defined tag assigned to the client.
+type TagDTO struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
 }
 
-// ServiceDTO carries the service being performed.
+// ServiceDTO carries the service being performed. With several services in
+// one appointment, ID is the first one and name/duration/price cover them all.
 type ServiceDTO struct {
 	ID          uint   `json:"id"`
 	Name        string `json:"name"`
@@ -65,6 +80,7 @@ type FlagsDTO struct {
 	HasSuggestion   bool `json:"has_suggestion"`     // recommended product for this service
 	IsAtRisk        bool `json:"is_at_risk"`         // client has at_risk behavior
 	HasSubscription bool `json:"has_subscription"`   // client has active plan
+	HasAllergies    bool `json:"has_allergies"`      // preferences list allergies
 }
 
 // CardDTO is the complete operational card for a single appointment.
@@ -92,10 +92,12 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	var (
 		suggestionsByService  map[uint]suggestionRow
 		prePaidOrdersByClient map[uint]prePaidOrderRow
+		profilesByClient      map[uint]*clientProfile
 	)
 
-	sugCh   := make(chan error, 1)
-	orderCh := make(chan error, 1)
+	sugCh     := make(chan error, 1)
+	orderCh   := make(chan error, 1)
+	profileCh := make(chan error, 1)
 
 	go func() {
 		var err error
@@ -109,6 +111,12 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		orderCh <- err
 	}()
 
+	go func() {
+		var err error
+		profilesByClient, err = q.loadClientProfiles(ctx, input.BarbershopID, clientIDs)
+		profileCh <- err
+	}()
+
 	subscriptionsByClient, subsErr := q.loadSubscriptions(ctx, input.BarbershopID, clientIDs)
 
 	var coveredServicesByPlan map[uint]map[uint]bool
@@ -122,8 +130,9 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	// errors from loadSuggestions and loadPrePaidOrders.
 	// Channel receives happen-after the goroutine sends, guaranteeing memory
 	// visibility of suggestionsByService and prePaidOrdersByClient.
-	sugErr   := <-sugCh
-	orderErr := <-orderCh
+	sugErr     := <-sugCh
+	orderErr   := <-orderCh
+	profileErr := <-profileCh
 
 	if subsErr != nil {
 		return nil, subsErr
@@ -137,11 +146,14 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	if orderErr != nil {
 		return nil, orderErr
 	}
+	if profileErr != nil {
+		return nil, profileErr
+	}
 
 	// 6. Assemble cards
 	cards := make([]CardDTO, 0, len(rows))
 	for _, row := range rows {
-		card := assembleCard(row, subscriptionsByClient, coveredServicesByPlan, suggestionsByService, prePaidOrdersByClient)
+		card := assembleCard(row, subscriptionsByClient, coveredServicesByPlan, suggestionsByService, prePaidOrdersByClient, profilesByClient)
 		cards = append(cards, card)
 	}
 
@@ -200,9 +212,9 @@ func (q *Query) loadAppointmentRows(ctx context.Context, barbershopID, barberID
 			COALESCE(cm.category::text, 'new') AS client_category,
 
 			bs.id           AS service_id,
-			COALESCE(bs.name, '')  AS service_name,
-			COALESCE(bs.duration_min, 0) AS service_duration_min,
-			COALESCE(bs.price, 0) AS service_price_cents,
+			COALESCE(lines.names, bs.name, '')  AS service_name,
+			COALESCE(lines.duration_min, bs.duration_min, 0) AS service_duration_min,
+			COALESCE(lines.price_cents, bs.price, 0) AS service_price_cents,
 
 			p.id            AS payment_id,
 			COALESCE(p.status::text, 'none') AS payment_status,
@@ -219,6 +231,14 @@ func (q *Query) loadAppointmentRows(ctx context.Context, barbershopID, barberID
 			AND cm.barbershop_id = a.barbershop_id
 		LEFT JOIN barbershop_services bs
 			ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+			SELECT
+				string_agg(s.service_name, ' + ' ORDER BY s.position) AS names,
+				SUM(s.duration_min) AS duration_min,
+				SUM(s.price_cents)  AS price_cents
+			FROM appointment_services s
+			WHERE s.appointment_id = a.id
+		) lines ON true
 		LEFT JOIN payments p
 			ON p.appointment_id = a.id
 			AND p.barbershop_id = a.barbershop_id
@@ -449,6 +469,121 @@ func (q *Query) loadPrePaidOrders(ctx context.Context, barbershopID uint, client
 	return result, nil
 }
 
+// ----------------------------------------------------------------
+// clientProfile — tags, pinned notes, preferences and last photo
+// ----------------------------------------------------------------
+
+type clientProfile struct {
+	Tags         []TagDTO
+	PinnedNotes  []string
+	Allergies    string
+	Beverage     string
+	LastPhotoURL string
+}
+
+// maxPinnedNotes caps the pinned notes shown on each card.
+const maxPinnedNotes = 3
+
+func (q *Query) loadClientProfiles(ctx context.Context, barbershopID uint, clientIDs []int64) (map[uint]*clientProfile, error) {
+	result := make(map[uint]*clientProfile)
+	if len(clientIDs) == 0 {
+		return result, nil
+	}
+	profile := func(clientID uint) *clientProfile {
+		p, ok := result[clientID]
+		if !ok {
+			p = &clientProfile{}
+			result[clientID] = p
+		}
+		return p
+	}
+	db := q.db.WithContext(ctx)
+
+	var tags []struct {
+		ClientID uint   `gorm:"column:client_id"`
+		ID       uint   `gorm:"column:id"`
+		Name     string `gorm:"column:name"`
+		Color    string `gorm:"column:color"`
+	}
+	if err := db.Raw(`
+		SELECT a.client_id, t.id, t.name, t.color
+		FROM client_tag_assignments a
+		JOIN client_tags t ON t.id = a.tag_id
+		WHERE a.barbershop_id = ?
+		  AND a.client_id = ANY(`+pgIntArray(clientIDs)+`)
+		ORDER BY a.client_id, LOWER(t.name), t.id
+	`, barbershopID).Scan(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		p := profile(t.ClientID)
+		p.Tags = append(p.Tags, TagDTO{ID: t.ID, Name: t.Name, Color: t.Color})
+	}
+
+	var notes []struct {
+		ClientID uint   `gorm:"column:client_id"`
+		Body     string `gorm:"column:body"`
+	}
+	if err := db.Raw(`
+		SELECT client_id, body
+		FROM (
+			SELECT client_id, body,
+			       ROW_NUMBER() OVER (PARTITION BY client_id ORDER BY created_at DESC, id DESC) AS rn
+			FROM client_notes
+			WHERE barbershop_id = ?
+			  AND client_id = ANY(`+pgIntArray(clientIDs)+`)
+			  AND pinned
+		) n
+		WHERE rn <= ?
+		ORDER BY client_id, rn
+	`, barbershopID, maxPinnedNotes).Scan(&notes).Error; err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		p := profile(n.ClientID)
+		p.PinnedNotes = append(p.PinnedNotes, n.Body)
+	}
+
+	var prefs []struct {
+		ClientID  uint   `gorm:"column:client_id"`
+		Allergies string `gorm:"column:allergies"`
+		Beverage  string `gorm:"column:beverage"`
+	}
+	if err := db.Raw(`
+		SELECT client_id, allergies, beverage
+		FROM client_preferences
+		WHERE barbershop_id = ?
+		  AND client_id = ANY(`+pgIntArray(clientIDs)+`)
+	`, barbershopID).Scan(&prefs).Error; err != nil {
+		return nil, err
+	}
+	for _, pr := range prefs {
+		p := profile(pr.ClientID)
+		p.Allergies = pr.Allergies
+		p.Beverage = pr.Beverage
+	}
+
+	var photos []struct {
+		ClientID uint   `gorm:"column:client_id"`
+		URL      string `gorm:"column:url"`
+	}
+	if err := db.Raw(`
+		SELECT DISTINCT ON (client_id) client_id, url
+		FROM client_photos
+		WHERE barbershop_id = ?
+		  AND client_id = ANY(`+pgIntArray(clientIDs)+`)
+		  AND kind = 'after'
+		ORDER BY client_id, created_at DESC, id DESC
+	`, barbershopID).Scan(&photos).Error; err != nil {
+		return nil, err
+	}
+	for _, ph := range photos {
+		profile(ph.ClientID).LastPhotoURL = ph.URL
+	}
+
+	return result, nil
+}
+
 // ----------------------------------------------------------------
 // Card assembly
 // ----------------------------------------------------------------
@@ -459,6 +594,7 @@ func assembleCard(
 	coveredServices map[uint]map[uint]bool,
 	suggestions map[uint]suggestionRow,
 	prePaidOrders map[uint]prePaidOrderRow,
+	profiles map[uint]*clientProfile,
 ) CardDTO {
 	card := CardDTO{
 		AppointmentID: row.AppointmentID,
@@ -484,6 +620,13 @@ func assembleCard(
 			Email:    row.ClientEmail,
 			Category: row.ClientCategory,
 		}
+		if p, ok := profiles[*row.ClientID]; ok {
+			card.Client.Tags = p.Tags
+			card.Client.PinnedNotes = p.PinnedNotes
+			card.Client.Allergies = p.Allergies
+			card.Client.Beverage = p.Beverage
+			card.Client.LastPhotoURL = p.LastPhotoURL
+		}
 	}
 
 	// Service
@@ -547,6 +690,7 @@ func assembleCard(
 		HasSuggestion:   card.Suggestion != nil,
 		IsAtRisk:        row.ClientCategory == "at_risk",
 		HasSubscription: card.Subscription != nil,
+		HasAllergies:    card.Client.Allergies != "",
 	}
 
 	return card
@@ -10,9 +10,10 @@ const (
 
 // RealizedDTO is revenue already confirmed in the period.
 type RealizedDTO struct {
-	// Dinheiro recebido: serviços pagos avulsos + produtos + mensalidades de assinatura.
+	// Dinheiro recebido: serviços pagos avulsos + produtos + mensalidades de assinatura
+	// + pacotes pré-pagos vendidos.
 	TotalCents                      int64 `json:"total_cents"`
-	// Serviços pagos sem cobertura de assinatura (net).
+	// Serviços pagos sem cobertura de assinatura nem de pacote (net).
 	ServicesCents                   int64 `json:"services_cents"`
 	// Total de produtos pagos.
 	ProductsCents                   int64 `json:"products_cents"`
@@ -23,6 +24,11 @@ type RealizedDTO struct {
 	// Produção operacional coberta por assinatura — valor dos atendimentos cobertos.
 	// Informativo: NÃO representa dinheiro recebido no período, apenas produção realizada via plano.
 	SubscriptionsCents              int64 `json:"subscriptions_cents"`
+	// Pacotes pré-pagos vendidos no período (payments.client_package_id IS NOT NULL, pagos).
+	PackagePaymentRevenueCents      int64 `json:"package_payment_revenue_cents"`
+	// Produção coberta por crédito de pacote — informativa, como SubscriptionsCents:
+	// o dinheiro entrou na venda do pacote.
+	PackagesCents                   int64 `json:"packages_cents"`
 	ClosuresCount                   int   `json:"closures_count"`
 	PaidOrdersCount                 int   `json:"paid_orders_count"`
 }
@@ -75,4 +81,6 @@ type ResponseDTO struct {
 
 	TopServices []TopItemDTO `json:"top_services"`
 	TopProducts []TopItemDTO `json:"top_products"`
+	// Combos mais vendidos: atendimentos concluídos agendados como combo.
+	TopCombos []TopItemDTO `json:"top_combos"`
 }
@@ -7,6 +7,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
 )
 
@@ -17,6 +18,8 @@ var ErrInvalidPeriod = errors.New("invalid period, expected: week|month")
 type Input struct {
 	BarbershopID uint
 	Period       PeriodType // week|month; default = week
+	// Reference escolhe o período que contém essa data; zero = agora.
+	Reference time.Time
 }
 
 // Query is the read-only financial service.
@@ -57,8 +60,13 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	}
 
 	loc := timezone.Location(shop.Timezone)
-	startUTC, endUTC := periodRange(period, loc)
+	startUTC, endUTC := periodRange(period, loc, input.Reference)
 	now := time.Now().UTC()
+	// Período já fechado: o presumido vai só até o fim dele.
+	presumedUntil := now
+	if endUTC.Before(now) {
+		presumedUntil = endUTC
+	}
 
 	dateFrom := startUTC.In(loc).Format("2006-01-02")
 	dateTo := endUTC.Add(-time.Second).In(loc).Format("2006-01-02")
@@ -73,7 +81,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		return nil, err
 	}
 
-	presumed, err := q.loadPresumed(ctx, input.BarbershopID, startUTC, now)
+	presumed, err := q.loadPresumed(ctx, input.BarbershopID, startUTC, presumedUntil)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +101,11 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		return nil, err
 	}
 
+	topCombos, err := q.loadTopCombos(ctx, input.BarbershopID, startUTC, endUTC)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ResponseDTO{
 		Period:      string(period),
 		DateFrom:    dateFrom,
@@ -104,6 +117,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		Losses:      losses,
 		TopServices: topServices,
 		TopProducts: topProducts,
+		TopCombos:   topCombos,
 	}, nil
 }
 
@@ -116,6 +130,7 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 	var closureResult struct {
 		ServicesCents      int64 `gorm:"column:services_cents"`
 		SubscriptionsCents int64 `gorm:"column:subscriptions_cents"`
+		PackagesCents      int64 `gorm:"column:packages_cents"`
 		Count              int   `gorm:"column:count"`
 	}
 	err := q.db.WithContext(ctx).Raw(`
@@ -124,8 +139,9 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 			COALESCE(SUM(
 				CASE WHEN ac.subscription_covered
 				     THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents)
-				     ELSE 0 END
+				     ELSE ac.subscription_covered_cents END
 			), 0) AS subscriptions_cents,
+			COALESCE(SUM(ac.package_covered_cents), 0) AS packages_cents,
 			COUNT(*) AS count
 		FROM appointment_closures ac
 		JOIN appointments a ON a.id = ac.appointment_id
@@ -139,7 +155,8 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 
 	// Product revenue from paid orders — total.
 	// Closure-linked orders (in-person sales) may be 'pending' in legacy records;
-	// treat them as paid when they have a matching closure.
+	// treat them as paid when they have a matching closure. Paid orders cancelled
+	// later still count as received — the money returned goes to Losses.
 	var orderTotal struct {
 		ProductsCents int64 `gorm:"column:products_cents"`
 		Count         int   `gorm:"column:count"`
@@ -158,6 +175,11 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 		          SELECT 1 FROM appointment_closures ac
 		          WHERE ac.additional_order_id = o.id
 		      )
+		      OR EXISTS (
+		          SELECT 1 FROM order_returns r
+		          JOIN order_return_items ri ON ri.order_return_id = r.id
+		          WHERE r.order_id = o.id
+		      )
 		  )
 	`, barbershopID, start, end).Scan(&orderTotal).Error
 	if err != nil {
@@ -182,14 +204,15 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 	}
 
 	// Mensalidades de assinatura pagas no período.
-	// Filtra por paid_at (momento real do recebimento) e status='paid'.
+	// Filtra por paid_at (momento real do recebimento). Pagamentos estornados
+	// depois continuam contando como recebidos — a devolução entra em Losses.
 	var subscriptionPaymentRevenue int64
 	err = q.db.WithContext(ctx).Raw(`
 		SELECT COALESCE(SUM(p.amount), 0)
 		FROM payments p
 		WHERE p.barbershop_id = ?
 		  AND p.subscription_id IS NOT NULL
-		  AND p.status = 'paid'
+		  AND `+shared.RevenuePaymentSQL+`
 		  AND p.paid_at >= ?
 		  AND p.paid_at < ?
 	`, barbershopID, start, end).Scan(&subscriptionPaymentRevenue).Error
@@ -197,18 +220,35 @@ func (q *Query) loadRealized(ctx context.Context, barbershopID uint, start, end
 		return RealizedDTO{}, err
 	}
 
-	serviceNet := closureResult.ServicesCents - closureResult.SubscriptionsCents
+	// Pacotes pré-pagos vendidos no período — mesmo critério das mensalidades.
+	var packagePaymentRevenue int64
+	err = q.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(p.amount), 0)
+		FROM payments p
+		WHERE p.barbershop_id = ?
+		  AND p.client_package_id IS NOT NULL
+		  AND `+shared.RevenuePaymentSQL+`
+		  AND p.paid_at >= ?
+		  AND p.paid_at < ?
+	`, barbershopID, start, end).Scan(&packagePaymentRevenue).Error
+	if err != nil {
+		return RealizedDTO{}, err
+	}
+
+	serviceNet := closureResult.ServicesCents - closureResult.SubscriptionsCents - closureResult.PackagesCents
 
 	return RealizedDTO{
-		// Total = dinheiro efetivamente recebido: serviços avulsos + produtos + mensalidades.
-		// Produção coberta por assinatura (SubscriptionsCents) é informativa — não entra no total.
-		TotalCents:                      serviceNet + orderTotal.ProductsCents + subscriptionPaymentRevenue,
+		// Total = dinheiro efetivamente recebido: serviços avulsos + produtos + mensalidades + pacotes.
+		// Produção coberta por assinatura ou pacote é informativa — não entra no total.
+		TotalCents:                      serviceNet + orderTotal.ProductsCents + subscriptionPaymentRevenue + packagePaymentRevenue,
 		ServicesCents:                   serviceNet,
 		ProductsCents:                   orderTotal.ProductsCents,
 		ProductsSuggestionCents:         suggestionOrdersCents,
 		ProductsStandaloneCents:         orderTotal.ProductsCents - suggestionOrdersCents,
 		SubscriptionPaymentRevenueCents: subscriptionPaymentRevenue,
 		SubscriptionsCents:              closureResult.SubscriptionsCents,
+		PackagePaymentRevenueCents:      packagePaymentRevenue,
+		PackagesCents:                   closureResult.PackagesCents,
 		ClosuresCount:                   closureResult.Count,
 		PaidOrdersCount:                 orderTotal.Count,
 	}, nil
@@ -226,10 +266,14 @@ func (q *Query) loadExpectation(ctx context.Context, barbershopID uint, from, en
 	}
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
-			COALESCE(SUM(bs.price), 0) AS services_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS services_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status IN ('scheduled', 'awaiting_payment')
 		  AND a.start_time >= ?
@@ -246,7 +290,9 @@ func (q *Query) loadExpectation(ctx context.Context, barbershopID uint, from, en
 	err = q.db.WithContext(ctx).Raw(`
 		SELECT COALESCE(SUM(prod.price), 0) AS suggestions_cents
 		FROM appointments a
-		JOIN service_suggested_products ssp ON ssp.service_id = a.barber_product_id
+		JOIN service_suggested_products ssp
+			ON (ssp.service_id = a.barber_product_id
+			    OR ssp.service_id IN (SELECT s.service_id FROM appointment_services s WHERE s.appointment_id = a.id))
 			AND ssp.barbershop_id = a.barbershop_id
 			AND ssp.active = true
 		JOIN products prod ON prod.id = ssp.product_id
@@ -278,10 +324,14 @@ func (q *Query) loadPresumed(ctx context.Context, barbershopID uint, start, now
 	}
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
-			COALESCE(SUM(bs.price), 0) AS total_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS total_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		LEFT JOIN appointment_closures ac ON ac.appointment_id = a.id
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'scheduled'
@@ -300,7 +350,7 @@ func (q *Query) loadPresumed(ctx context.Context, barbershopID uint, start, now
 }
 
 // ----------------------------------------------------------------
-// Losses — no-show, cancelamentos, sugestões não vendidas
+// Losses — no-show, cancelamentos, sugestões não vendidas, estornos e chargebacks
 // ----------------------------------------------------------------
 
 func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end time.Time) (LossesDTO, error) {
@@ -318,10 +368,14 @@ func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end ti
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
 			'no_show' AS loss_type,
-			COALESCE(SUM(bs.price), 0) AS amount_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS amount_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'no_show'
 		  AND a.start_time >= ?
@@ -349,10 +403,14 @@ func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end ti
 	err = q.db.WithContext(ctx).Raw(`
 		SELECT
 			'cancellation' AS loss_type,
-			COALESCE(SUM(bs.price), 0) AS amount_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS amount_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'cancelled'
 		  AND a.start_time >= ?
@@ -399,10 +457,58 @@ func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end ti
 	}
 	suggNotSoldResult.LossType = "suggestion_not_sold"
 
-	breakdown := make([]LossItemDTO, 0, 3)
+	// Dinheiro devolvido ao cliente no período: estornos e chargebacks,
+	// pela data da devolução (não do agendamento).
+	var reversalRows []lossRow
+	err = q.db.WithContext(ctx).Raw(`
+		SELECT
+			pr.kind AS loss_type,
+			COALESCE(SUM(pr.amount), 0) AS amount_cents,
+			COUNT(DISTINCT pr.payment_id) AS count
+		FROM payment_refunds pr
+		WHERE pr.barbershop_id = ?
+		  AND pr.created_at >= ?
+		  AND pr.created_at < ?
+		GROUP BY pr.kind
+	`, barbershopID, start, end).Scan(&reversalRows).Error
+	if err != nil {
+		return LossesDTO{}, err
+	}
+
+	// Devoluções de produtos reembolsadas por fora (dinheiro, maquininha).
+	// Estornos pelo gateway já entram acima como "refund".
+	var productReturnResult lossRow
+	err = q.db.WithContext(ctx).Raw(`
+		SELECT
+			'product_return' AS loss_type,
+			COALESCE(SUM(r.amount_cents), 0) AS amount_cents,
+			COUNT(r.id) AS count
+		FROM order_returns r
+		WHERE r.barbershop_id = ?
+		  AND r.refund_status IN ('manual_pending', 'manual_refunded')
+		  AND r.created_at >= ?
+		  AND r.created_at < ?
+	`, barbershopID, start, end).Scan(&productReturnResult).Error
+	if err != nil {
+		return LossesDTO{}, err
+	}
+	productReturnResult.LossType = "product_return"
+
+	refundResult := lossRow{LossType: "refund"}
+	chargebackResult := lossRow{LossType: "chargeback"}
+	for _, r := range reversalRows {
+		switch r.LossType {
+		case "refund":
+			refundResult = r
+		case "chargeback":
+			chargebackResult = r
+		}
+	}
+
+	breakdown := make([]LossItemDTO, 0, 6)
 	total := int64(0)
 
-	for _, r := range []lossRow{noShowResult, cancelResult, suggNotSoldResult} {
+	for _, r := range []lossRow{noShowResult, cancelResult, suggNotSoldResult, refundResult, chargebackResult, productReturnResult} {
 		if r.Count > 0 || r.AmountCents > 0 {
 			breakdown = append(breakdown, LossItemDTO{
 				Type:        r.LossType,
@@ -455,7 +561,7 @@ func (q *Query) loadTopServices(ctx context.Context, barbershopID uint, start, e
 }
 
 // ----------------------------------------------------------------
-// Top products — by revenue from order items
+// Top products — by revenue from order items, net of returns
 // ----------------------------------------------------------------
 
 func (q *Query) loadTopProducts(ctx context.Context, barbershopID uint, start, end time.Time) ([]TopItemDTO, error) {
@@ -468,8 +574,8 @@ func (q *Query) loadTopProducts(ctx context.Context, barbershopID uint, start, e
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
 			oi.product_name_snapshot AS name,
-			SUM(oi.quantity) AS count,
-			SUM(oi.line_total) AS revenue_cents
+			SUM(oi.quantity - oi.returned_quantity) AS count,
+			SUM(oi.line_total - oi.unit_price * oi.returned_quantity) AS revenue_cents
 		FROM order_items oi
 		JOIN orders o ON o.id = oi.order_id
 		WHERE o.barbershop_id = ?
@@ -483,6 +589,43 @@ func (q *Query) loadTopProducts(ctx context.Context, barbershopID uint, start, e
 		      )
 		  )
 		GROUP BY oi.product_name_snapshot
+		HAVING SUM(oi.quantity - oi.returned_quantity) > 0
+		ORDER BY revenue_cents DESC
+		LIMIT 5
+	`, barbershopID, start, end).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	items := make([]TopItemDTO, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, TopItemDTO{Name: r.Name, Count: r.Count, RevenueCents: r.RevenueCents})
+	}
+	return items, nil
+}
+
+// ----------------------------------------------------------------
+// Top combos — atendimentos agendados como combo, by revenue from closures
+// ----------------------------------------------------------------
+
+func (q *Query) loadTopCombos(ctx context.Context, barbershopID uint, start, end time.Time) ([]TopItemDTO, error) {
+	type row struct {
+		Name         string `gorm:"column:name"`
+		Count        int    `gorm:"column:count"`
+		RevenueCents int64  `gorm:"column:revenue_cents"`
+	}
+	var rows []row
+	err := q.db.WithContext(ctx).Raw(`
+		SELECT
+			sc.name AS name,
+			COUNT(*) AS count,
+			COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS revenue_cents
+		FROM appointment_closures ac
+		JOIN appointments a ON a.id = ac.appointment_id
+		JOIN service_combos sc ON sc.id = a.combo_id
+		WHERE ac.barbershop_id = ?
+		  AND a.start_time >= ?
+		  AND a.start_time < ?
+		GROUP BY sc.id, sc.name
 		ORDER BY revenue_cents DESC
 		LIMIT 5
 	`, barbershopID, start, end).Scan(&rows).Error
@@ -500,8 +643,11 @@ func (q *Query) loadTopProducts(ctx context.Context, barbershopID uint, start, e
 // Period helpers
 // ----------------------------------------------------------------
 
-func periodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.Time) {
-	now := time.Now().In(loc)
+func periodRange(period PeriodType, loc *time.Location, ref time.Time) (startUTC, endUTC time.Time) {
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	now := ref.In(loc)
 	var localStart time.Time
 
 	switch period {
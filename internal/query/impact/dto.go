@@ -1,5 +1,7 @@
 package impact
 
+import "time"
+
 type PeriodType string
 
 const (
@@ -10,6 +12,8 @@ const (
 type Input struct {
 	BarbershopID uint
 	Period       PeriodType
+	// Reference escolhe o período que contém essa data; zero = agora.
+	Reference time.Time
 }
 
 // RevenueDTO — faturamento atual vs período anterior.
@@ -72,6 +76,16 @@ type IndirectDTO struct {
 	UpsellCapturedCents      int64   `json:"upsell_captured_cents"`
 }
 
+// CampaignsDTO — retorno das campanhas de reativação. Agendamento
+// atribuído = feito pelo cliente até 7 dias depois de receber a campanha.
+type CampaignsDTO struct {
+	MessagesSent          int     `json:"messages_sent"`
+	AttributedBookings    int     `json:"attributed_bookings"`
+	AttributedCompleted   int     `json:"attributed_completed"`
+	AttributedRevenue     int64   `json:"attributed_revenue_cents"`
+	ConversionRatePercent float64 `json:"conversion_rate_percent"`
+}
+
 // ROIDTO — valor gerado pelo sistema.
 type ROIDTO struct {
 	ValueGeneratedCents    int64  `json:"value_generated_cents"`
@@ -94,4 +108,5 @@ type ResponseDTO struct {
 	Usage     UsageDTO     `json:"usage"`
 	Indirect  IndirectDTO  `json:"indirect_gains"`
 	ROI       ROIDTO       `json:"roi"`
+	Campaigns CampaignsDTO `json:"campaigns"`
 }
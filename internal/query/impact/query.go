@@ -8,6 +8,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
 )
 
@@ -52,8 +53,8 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 	}
 
 	loc := timezone.Location(shop.Timezone)
-	start, end := periodRange(period, loc)
-	prevStart, prevEnd := prevPeriodRange(period, loc)
+	start, end := periodRange(period, loc, input.Reference)
+	prevStart, prevEnd := prevPeriodRange(period, loc, input.Reference)
 
 	dateFrom := start.In(loc).Format("2006-01-02")
 	dateTo := end.Add(-time.Second).In(loc).Format("2006-01-02")
@@ -88,6 +89,11 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		return nil, err
 	}
 
+	campaigns, err := q.loadCampaigns(ctx, input.BarbershopID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
 	roi, err := q.buildROI(ctx, input.BarbershopID, start, end, revenue, losses, indirect, usage, growth)
 	if err != nil {
 		return nil, err
@@ -105,6 +111,7 @@ func (q *Query) Execute(ctx context.Context, input Input) (*ResponseDTO, error)
 		Usage:     usage,
 		Indirect:  indirect,
 		ROI:       roi,
+		Campaigns: campaigns,
 	}, nil
 }
 
@@ -129,7 +136,7 @@ func (q *Query) loadRevenue(ctx context.Context, barbershopID uint, start, end,
 			SELECT
 				COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS services_cents,
 				COALESCE(SUM(CASE WHEN ac.subscription_covered
-				     THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents) ELSE 0 END), 0) AS subscription_part,
+				     THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents) ELSE ac.subscription_covered_cents END), 0) AS subscription_part,
 				COUNT(*) AS closures_count
 			FROM appointment_closures ac
 			JOIN appointments a ON a.id = ac.appointment_id
@@ -159,11 +166,11 @@ func (q *Query) loadRevenue(ctx context.Context, barbershopID uint, start, end,
 		// Mensalidades de assinatura pagas no período (filtradas por paid_at).
 		var subPaymentRevenue int64
 		err = q.db.WithContext(ctx).Raw(`
-			SELECT COALESCE(SUM(p.amount), 0)
+			SELECT COALESCE(SUM(`+shared.NetPaymentAmountSQL+`), 0)
 			FROM payments p
 			WHERE p.barbershop_id = ?
 			  AND p.subscription_id IS NOT NULL
-			  AND p.status = 'paid'
+			  AND `+shared.RevenuePaymentSQL+`
 			  AND p.paid_at >= ?
 			  AND p.paid_at < ?
 		`, barbershopID, pStart, pEnd).Scan(&subPaymentRevenue).Error
@@ -342,7 +349,7 @@ func (q *Query) loadTrendBuckets(
 			COUNT(a.id) AS count,
 			COALESCE(SUM(
 				CASE WHEN ac.id IS NOT NULL AND NOT ac.subscription_covered
-				     THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents)
+				     THEN COALESCE(ac.final_amount_cents, ac.reference_amount_cents) - ac.subscription_covered_cents
 				     ELSE 0 END
 			), 0) AS revenue_cents
 		FROM appointments a
@@ -404,11 +411,11 @@ func (q *Query) loadTrendBuckets(
 	err = q.db.WithContext(ctx).Raw(`
 		SELECT
 			EXTRACT(`+bucketExpr+` FROM p.paid_at AT TIME ZONE ?) AS bucket,
-			COALESCE(SUM(p.amount), 0) AS revenue_cents
+			COALESCE(SUM(`+shared.NetPaymentAmountSQL+`), 0) AS revenue_cents
 		FROM payments p
 		WHERE p.barbershop_id = ?
 		  AND p.subscription_id IS NOT NULL
-		  AND p.status = 'paid'
+		  AND `+shared.RevenuePaymentSQL+`
 		  AND p.paid_at >= ?
 		  AND p.paid_at < ?
 		GROUP BY bucket
@@ -562,10 +569,14 @@ func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end ti
 	}
 	err := q.db.WithContext(ctx).Raw(`
 		SELECT
-			COALESCE(SUM(bs.price), 0) AS amount_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS amount_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'no_show'
 		  AND a.start_time >= ?
@@ -581,10 +592,14 @@ func (q *Query) loadLosses(ctx context.Context, barbershopID uint, start, end ti
 	}
 	err = q.db.WithContext(ctx).Raw(`
 		SELECT
-			COALESCE(SUM(bs.price), 0) AS amount_cents,
+			COALESCE(SUM(COALESCE(lines.price_cents, bs.price)), 0) AS amount_cents,
 			COUNT(a.id) AS count
 		FROM appointments a
 		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		LEFT JOIN LATERAL (
+		  SELECT SUM(s.price_cents) AS price_cents
+		  FROM appointment_services s WHERE s.appointment_id = a.id
+		) lines ON true
 		WHERE a.barbershop_id = ?
 		  AND a.status = 'cancelled'
 		  AND a.start_time >= ?
@@ -767,6 +782,61 @@ func (q *Query) loadIndirect(ctx context.Context, barbershopID uint, start, end
 	}, nil
 }
 
+// ----------------------------------------------------------------
+// Campaigns
+// ----------------------------------------------------------------
+
+func (q *Query) loadCampaigns(ctx context.Context, barbershopID uint, start, end time.Time) (CampaignsDTO, error) {
+	var sent struct {
+		Count int `gorm:"column:count"`
+	}
+	err := q.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS count
+		FROM campaign_recipients
+		WHERE barbershop_id = ?
+		  AND status = 'sent'
+		  AND sent_at >= ?
+		  AND sent_at < ?
+	`, barbershopID, start, end).Scan(&sent).Error
+	if err != nil {
+		return CampaignsDTO{}, err
+	}
+
+	// Agendamentos feitos no período a partir de uma campanha.
+	var attributed struct {
+		Bookings     int   `gorm:"column:bookings"`
+		Completed    int   `gorm:"column:completed"`
+		RevenueCents int64 `gorm:"column:revenue_cents"`
+	}
+	err = q.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) AS bookings,
+			COUNT(*) FILTER (WHERE ca.appointment_status = 'completed') AS completed,
+			COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS revenue_cents
+		FROM campaign_attributions ca
+		LEFT JOIN appointment_closures ac ON ac.appointment_id = ca.appointment_id
+		WHERE ca.barbershop_id = ?
+		  AND ca.booked_at >= ?
+		  AND ca.booked_at < ?
+	`, barbershopID, start, end).Scan(&attributed).Error
+	if err != nil {
+		return CampaignsDTO{}, err
+	}
+
+	var conversion float64
+	if sent.Count > 0 {
+		conversion = float64(attributed.Bookings) / float64(sent.Count) * 100
+	}
+
+	return CampaignsDTO{
+		MessagesSent:          sent.Count,
+		AttributedBookings:    attributed.Bookings,
+		AttributedCompleted:   attributed.Completed,
+		AttributedRevenue:     attributed.RevenueCents,
+		ConversionRatePercent: conversion,
+	}, nil
+}
+
 // ----------------------------------------------------------------
 // ROI
 // ----------------------------------------------------------------
@@ -811,8 +881,11 @@ func (q *Query) buildROI(ctx context.Context, barbershopID uint, start, end time
 // Period helpers
 // ----------------------------------------------------------------
 
-func periodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.Time) {
-	now := time.Now().In(loc)
+func periodRange(period PeriodType, loc *time.Location, ref time.Time) (startUTC, endUTC time.Time) {
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	now := ref.In(loc)
 	var localStart time.Time
 
 	switch period {
@@ -837,8 +910,8 @@ func periodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.T
 	return localStart.UTC(), localEnd.UTC()
 }
 
-func prevPeriodRange(period PeriodType, loc *time.Location) (startUTC, endUTC time.Time) {
-	start, end := periodRange(period, loc)
+func prevPeriodRange(period PeriodType, loc *time.Location, ref time.Time) (startUTC, endUTC time.Time) {
+	start, end := periodRange(period, loc, ref)
 	dur := end.Sub(start)
 	return start.Add(-dur), start
 }
@@ -0,0 +1,22 @@
+package shared
+
+// RevenuePaymentSQL is the canonical WHERE condition for a payment whose
+// money reached the barbershop.  The snippet references the table alias "p"
+// so every query that includes it must alias the payments table as "p".
+//
+// Rule:
+//   - the payment was settled (status 'paid')
+//   - or it was settled and later returned, fully or partially
+//     ('partially_refunded', 'refunded', 'charged_back')
+//
+// A refund or chargeback never erases the original receipt; how much of it
+// stayed with the barbershop is given by NetPaymentAmountSQL.
+const RevenuePaymentSQL = `p.status IN ('paid', 'partially_refunded', 'refunded', 'charged_back')`
+
+// NetPaymentAmountSQL is the amount of a revenue-bearing payment that stayed
+// with the barbershop: the charged amount minus everything returned to the
+// client (refunds + chargeback).  Same alias rule as RevenuePaymentSQL.
+//
+// Reports that show refunds as a separate loss line (financial) sum the
+// gross p.amount instead, so the returned money is not subtracted twice.
+const NetPaymentAmountSQL = `(p.amount - p.refunded_amount)`
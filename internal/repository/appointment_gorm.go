@@ -14,6 +14,7 @@ import (
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/shared"
 )
 
 // workingHoursCache evita queries repetidas para o mesmo barber+weekday.
@@ -110,6 +111,29 @@ func (r *AppointmentGormRepository) GetProduct(
 	return &product, err
 }
 
+func (r *AppointmentGormRepository) GetCombo(
+	ctx context.Context,
+	barbershopID uint,
+	comboID uint,
+) (*models.ServiceCombo, error) {
+
+	var combo models.ServiceCombo
+
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ? AND barbershop_id = ?", comboID, barbershopID).
+		First(&combo).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &combo, nil
+}
+
 //
 // ======================================================
 // CLIENT
@@ -180,18 +204,22 @@ func isClientPhoneUniqueViolation(err error) bool {
 func (r *AppointmentGormRepository) CreateAppointment(
 	ctx context.Context,
 	ap *models.Appointment,
+	onCreated func(ctx context.Context, tx *gorm.DB) error,
 ) error {
 
-	err := r.db.WithContext(ctx).Create(ap).Error
-	if err == nil {
-		return nil
-	}
-
-	if isUniqueBarberSlotActiveViolation(err) {
-		return apperr.ErrBusiness("time_conflict")
-	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ap).Error; err != nil {
+			if isUniqueBarberSlotActiveViolation(err) {
+				return apperr.ErrBusiness("time_conflict")
+			}
+			return err
+		}
 
-	return err
+		if onCreated == nil {
+			return nil
+		}
+		return onCreated(ctx, tx)
+	})
 }
 
 // CreateAppointmentWithKey creates the appointment and persists the
@@ -228,6 +256,64 @@ func (r *AppointmentGormRepository) CreateAppointmentWithKey(
 	})
 }
 
+// CreateAppointmentIfFree serializa, via pg_advisory_xact_lock, as criações de
+// agendamento do mesmo barbeiro: a checagem de conflito e o INSERT acontecem
+// na mesma transação, então dois bookings simultâneos em horários que se
+// sobrepõem (não só no mesmo start_time, coberto pelo unique_barber_slot_active)
+// não conseguem passar os dois.
+func (r *AppointmentGormRepository) CreateAppointmentIfFree(
+	ctx context.Context,
+	ap *models.Appointment,
+	conflictStart time.Time,
+	conflictEnd time.Time,
+	idempotencyKey string,
+	onCreated func(ctx context.Context, tx *gorm.DB) error,
+) error {
+	if ap.BarbershopID == nil || ap.BarberID == nil {
+		return errors.New("appointment without barbershop or barber")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"SELECT pg_advisory_xact_lock(hashtext('appointment_barber'), ?::int)",
+			*ap.BarberID,
+		).Error; err != nil {
+			return err
+		}
+
+		txRepo := &AppointmentGormRepository{db: tx}
+		if err := txRepo.AssertNoTimeConflict(
+			ctx, *ap.BarbershopID, *ap.BarberID, conflictStart, conflictEnd,
+		); err != nil {
+			return err
+		}
+
+		if err := tx.Create(ap).Error; err != nil {
+			if isUniqueBarberSlotActiveViolation(err) {
+				return apperr.ErrBusiness("time_conflict")
+			}
+			return err
+		}
+
+		if idempotencyKey != "" {
+			if err := tx.Exec(
+				"INSERT INTO idempotency_keys (key) VALUES (?)",
+				idempotencyKey,
+			).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) || isPgUniqueViolation(err, "") {
+					return apperr.ErrBusiness("duplicate_request")
+				}
+				return err
+			}
+		}
+
+		if onCreated == nil {
+			return nil
+		}
+		return onCreated(ctx, tx)
+	})
+}
+
 func isUniqueBarberSlotActiveViolation(err error) bool {
 	return isPgUniqueViolation(err, "unique_barber_slot_active")
 }
@@ -249,6 +335,9 @@ func (r *AppointmentGormRepository) GetAppointmentForBarber(
 
 	err := r.db.WithContext(ctx).
 		Preload("BarberProduct").
+		Preload("Services", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("Client").
 		Preload("Barbershop").
 		Where(
@@ -274,6 +363,18 @@ func (r *AppointmentGormRepository) UpdateAppointment(
 	return r.db.WithContext(ctx).Omit(clause.Associations).Save(ap).Error
 }
 
+func (r *AppointmentGormRepository) ListAppointmentServices(
+	ctx context.Context,
+	appointmentID uint,
+) ([]models.AppointmentService, error) {
+	var lines []models.AppointmentService
+	err := r.db.WithContext(ctx).
+		Where("appointment_id = ?", appointmentID).
+		Order("position ASC").
+		Find(&lines).Error
+	return lines, err
+}
+
 func (r *AppointmentGormRepository) SaveAppointmentClosure(
 	ctx context.Context,
 	closure *models.AppointmentClosure,
@@ -317,6 +418,19 @@ func (r *AppointmentGormRepository) AssertNoTimeConflict(
 	barberID uint,
 	start time.Time,
 	end time.Time,
+) error {
+	return r.assertNoTimeConflictExcept(ctx, barbershopID, barberID, start, end, 0)
+}
+
+// assertNoTimeConflictExcept ignora o appointment exceptID (0 = nenhum), para
+// validar a mudança de horário de um agendamento existente.
+func (r *AppointmentGormRepository) assertNoTimeConflictExcept(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	start time.Time,
+	end time.Time,
+	exceptID uint,
 ) error {
 	var count int64
 
@@ -326,7 +440,7 @@ func (r *AppointmentGormRepository) AssertNoTimeConflict(
 	err := r.db.WithContext(ctx).
 		Model(&models.Appointment{}).
 		Where(
-			`barbershop_id = ? AND barber_id = ?
+			`barbershop_id = ? AND barber_id = ? AND id <> ?
 			 AND start_time < ? AND end_time > ?
 			 AND (
 			   status = 'scheduled'
@@ -342,6 +456,7 @@ func (r *AppointmentGormRepository) AssertNoTimeConflict(
 			 )`,
 			barbershopID,
 			barberID,
+			exceptID,
 			end,
 			start,
 		).
@@ -359,6 +474,107 @@ func (r *AppointmentGormRepository) AssertNoTimeConflict(
 	return nil
 }
 
+func (r *AppointmentGormRepository) GetBarberServiceOverride(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	serviceID uint,
+) (*models.BarberServiceOverride, error) {
+
+	var o models.BarberServiceOverride
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ? AND service_id = ?", barbershopID, barberID, serviceID).
+		First(&o).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+//
+// ======================================================
+// BARBERS
+// ======================================================
+//
+
+func (r *AppointmentGormRepository) ListActiveBarberIDs(
+	ctx context.Context,
+	barbershopID uint,
+) ([]uint, error) {
+
+	var ids []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("barbershop_id = ? AND active = true", barbershopID).
+		Order("id ASC").
+		Pluck("id", &ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *AppointmentGormRepository) GetLastAutoAssignedBarberID(
+	ctx context.Context,
+	barbershopID uint,
+) (uint, error) {
+
+	var ids []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Appointment{}).
+		Where("barbershop_id = ? AND auto_assigned = true AND barber_id IS NOT NULL", barbershopID).
+		Order("id DESC").
+		Limit(1).
+		Pluck("barber_id", &ids).
+		Error
+
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+func (r *AppointmentGormRepository) GetPreferredBarberID(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (uint, error) {
+
+	var ids []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Appointment{}).
+		Select("barber_id").
+		Where(
+			"barbershop_id = ? AND client_id = ? AND status = 'completed' AND barber_id IS NOT NULL",
+			barbershopID, clientID,
+		).
+		Group("barber_id").
+		Order("COUNT(*) DESC, MAX(start_time) DESC").
+		Limit(1).
+		Pluck("barber_id", &ids).
+		Error
+
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
 //
 // ======================================================
 // WORKING HOURS
@@ -555,6 +771,7 @@ func (r *AppointmentGormRepository) ListAppointmentsForReminder(
 	err := r.db.WithContext(ctx).
 		Preload("Client").
 		Preload("Barbershop").
+		Preload("BarberProduct").
 		Where(
 			"barbershop_id = ? AND start_time BETWEEN ? AND ? AND status = ?",
 			barbershopID,
@@ -568,6 +785,79 @@ func (r *AppointmentGormRepository) ListAppointmentsForReminder(
 	return apps, err
 }
 
+func (r *AppointmentGormRepository) ListReminderSettings(
+	ctx context.Context,
+) ([]domain.ReminderSettings, error) {
+
+	var rows []models.Barbershop
+
+	// Mesmo filtro de ListBarbershops: só barbearias ativas ou em trial válido.
+	err := r.db.WithContext(ctx).
+		Select("id, timezone, reminder_offsets_minutes").
+		Where(`
+			reminders_enabled = true
+			AND (
+				status = 'active'
+				OR (status = 'trial' AND (trial_ends_at IS NULL OR trial_ends_at > NOW()))
+			)
+		`).
+		Find(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make([]domain.ReminderSettings, 0, len(rows))
+
+	for _, row := range rows {
+		if len(row.ReminderOffsetsMinutes) == 0 {
+			continue
+		}
+		settings = append(settings, domain.ReminderSettings{
+			BarbershopID:   row.ID,
+			Timezone:       row.Timezone,
+			OffsetsMinutes: row.ReminderOffsetsMinutes,
+		})
+	}
+
+	return settings, nil
+}
+
+func (r *AppointmentGormRepository) ClaimReminder(
+	ctx context.Context,
+	barbershopID uint,
+	appointmentID uint,
+	offsetMinutes int,
+	channel string,
+) (bool, error) {
+
+	res := r.db.WithContext(ctx).Exec(`
+		INSERT INTO appointment_reminders (barbershop_id, appointment_id, offset_minutes, channel, sent_at)
+		VALUES (?, ?, ?, ?, now())
+		ON CONFLICT (appointment_id, offset_minutes, channel) DO NOTHING
+	`, barbershopID, appointmentID, offsetMinutes, channel)
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *AppointmentGormRepository) ReleaseReminder(
+	ctx context.Context,
+	appointmentID uint,
+	offsetMinutes int,
+	channel string,
+) error {
+
+	return r.db.WithContext(ctx).Exec(`
+		DELETE FROM appointment_reminders
+		WHERE appointment_id = ? AND offset_minutes = ? AND channel = ?
+	`, appointmentID, offsetMinutes, channel).Error
+}
+
 func (r *AppointmentGormRepository) GetAppointmentByID(
 	ctx context.Context,
 	barbershopID uint,
@@ -579,6 +869,9 @@ func (r *AppointmentGormRepository) GetAppointmentByID(
 	err := r.db.WithContext(ctx).
 		Preload("Client").
 		Preload("BarberProduct").
+		Preload("Services", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("Barbershop").
 		Where("id = ? AND barbershop_id = ?", appointmentID, barbershopID).
 		First(&ap).
@@ -657,10 +950,10 @@ func (r *AppointmentGormRepository) GetOperationalSummary(
 
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
-			(SELECT COALESCE(SUM(p.amount), 0)
+			(SELECT COALESCE(SUM(`+shared.NetPaymentAmountSQL+`), 0)
 			 FROM payments p
 			 WHERE p.barbershop_id = ?
-			   AND p.status = 'paid'
+			   AND `+shared.RevenuePaymentSQL+`
 			) AS total_received,
 
 			COUNT(*) FILTER (WHERE a.status = 'completed') AS count_completed,
@@ -819,13 +1112,13 @@ func (r *AppointmentGormRepository) ListAutoCompleteCandidates(
 				SELECT 1 FROM payments p
 				WHERE p.appointment_id = a.id
 				  AND p.barbershop_id = ?
-				  AND p.status = 'paid'
+				  AND `+shared.RevenuePaymentSQL+`
 			) AS has_paid_payment,
 			EXISTS(
 				SELECT 1 FROM payments p
 				WHERE p.appointment_id = a.id
 				  AND p.barbershop_id = ?
-				  AND p.status = 'paid'
+				  AND `+shared.RevenuePaymentSQL+`
 				  AND p.txid IS NOT NULL
 			) AS payment_is_pix
 		FROM appointments a
@@ -905,6 +1198,34 @@ func (r *AppointmentGormRepository) GetScheduleOverride(
 	return nil, err
 }
 
+// ======================================================
+// BUSY PERIODS (Google Calendar)
+// ======================================================
+
+func (r *AppointmentGormRepository) ListBusyPeriods(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	from time.Time,
+	to time.Time,
+) ([]domain.BusyPeriod, error) {
+	var rows []models.BarberBusyPeriod
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ? AND start_time < ? AND end_time > ?",
+			barbershopID, barberID, to, from).
+		Order("start_time ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.BusyPeriod, len(rows))
+	for i, row := range rows {
+		out[i] = domain.BusyPeriod{Start: row.StartTime, End: row.EndTime}
+	}
+	return out, nil
+}
+
 var _ domain.Repository = (*AppointmentGormRepository)(nil)
 var _ domain.BarbershopLister = (*AppointmentGormRepository)(nil)
 var _ domain.JobRepository = (*AppointmentGormRepository)(nil)
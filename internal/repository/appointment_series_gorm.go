@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+//
+// ======================================================
+// APPOINTMENT SERIES (domain.SeriesRepository)
+// ======================================================
+//
+
+func (r *AppointmentGormRepository) CreateSeries(
+	ctx context.Context,
+	series *models.AppointmentSeries,
+) error {
+	return r.db.WithContext(ctx).Create(series).Error
+}
+
+func (r *AppointmentGormRepository) GetSeries(
+	ctx context.Context,
+	barbershopID uint,
+	seriesID uint,
+) (*models.AppointmentSeries, error) {
+
+	var series models.AppointmentSeries
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", seriesID, barbershopID).
+		First(&series).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *AppointmentGormRepository) UpdateSeries(
+	ctx context.Context,
+	series *models.AppointmentSeries,
+) error {
+	return r.db.WithContext(ctx).Save(series).Error
+}
+
+func (r *AppointmentGormRepository) DeleteSeries(
+	ctx context.Context,
+	barbershopID uint,
+	seriesID uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", seriesID, barbershopID).
+		Delete(&models.AppointmentSeries{}).
+		Error
+}
+
+func (r *AppointmentGormRepository) ListSeriesAppointments(
+	ctx context.Context,
+	barbershopID uint,
+	seriesID uint,
+	from time.Time,
+) ([]*models.Appointment, error) {
+
+	var aps []*models.Appointment
+
+	err := r.db.WithContext(ctx).
+		Preload("BarberProduct").
+		Where(
+			`barbershop_id = ? AND series_id = ? AND start_time >= ?
+			 AND status IN ('scheduled', 'awaiting_payment')`,
+			barbershopID,
+			seriesID,
+			from,
+		).
+		Order("start_time ASC").
+		Find(&aps).
+		Error
+
+	return aps, err
+}
+
+func (r *AppointmentGormRepository) ReassignSeriesAppointments(
+	ctx context.Context,
+	barbershopID uint,
+	fromSeriesID uint,
+	toSeriesID uint,
+	from time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Appointment{}).
+		Where(
+			`barbershop_id = ? AND series_id = ? AND start_time >= ?
+			 AND status IN ('scheduled', 'awaiting_payment')`,
+			barbershopID,
+			fromSeriesID,
+			from,
+		).
+		Update("series_id", toSeriesID).
+		Error
+}
+
+// MoveAppointmentIfFree usa o mesmo pg_advisory_xact_lock de
+// CreateAppointmentIfFree: uma edição de série e um booking concorrente no
+// mesmo barbeiro não conseguem ocupar o mesmo intervalo.
+func (r *AppointmentGormRepository) MoveAppointmentIfFree(
+	ctx context.Context,
+	ap *models.Appointment,
+	conflictStart time.Time,
+	conflictEnd time.Time,
+	onMoved func(ctx context.Context, tx *gorm.DB) error,
+) error {
+	if ap.BarbershopID == nil || ap.BarberID == nil {
+		return errors.New("appointment without barbershop or barber")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"SELECT pg_advisory_xact_lock(hashtext('appointment_barber'), ?::int)",
+			*ap.BarberID,
+		).Error; err != nil {
+			return err
+		}
+
+		txRepo := &AppointmentGormRepository{db: tx}
+		if err := txRepo.assertNoTimeConflictExcept(
+			ctx, *ap.BarbershopID, *ap.BarberID, conflictStart, conflictEnd, ap.ID,
+		); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Appointment{}).
+			Where("id = ?", ap.ID).
+			Updates(map[string]any{
+				"start_time":        ap.StartTime,
+				"end_time":          ap.EndTime,
+				"barber_product_id": ap.BarberProductID,
+				"notes":             ap.Notes,
+			}).Error; err != nil {
+			if isUniqueBarberSlotActiveViolation(err) {
+				return apperr.ErrBusiness("time_conflict")
+			}
+			return err
+		}
+
+		// Serviços informados substituem as linhas do agendamento.
+		if len(ap.Services) > 0 {
+			if err := tx.Where("appointment_id = ?", ap.ID).
+				Delete(&models.AppointmentService{}).Error; err != nil {
+				return err
+			}
+			for i := range ap.Services {
+				ap.Services[i].ID = 0
+				ap.Services[i].AppointmentID = ap.ID
+			}
+			if err := tx.Create(&ap.Services).Error; err != nil {
+				return err
+			}
+		}
+
+		if onMoved == nil {
+			return nil
+		}
+		return onMoved(ctx, tx)
+	})
+}
@@ -0,0 +1,96 @@
+package repository
+
+// Testes de repositório para os overrides de serviço por barbeiro.
+//
+// O teste de ida e volta requer banco PostgreSQL real via DATABASE_URL —
+// skipped automaticamente sem ele. Helpers de setup em cancel_subscription_test.go.
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// TestUpsertBarberOverride_InsertsOfferedFalse: o INSERT leva offered = false
+// em vez de deixar o default do banco (true) valer.
+func TestUpsertBarberOverride_InsertsOfferedFalse(t *testing.T) {
+	db, insert := newDryRunDB(t)
+
+	err := NewServiceGormRepository(db).UpsertBarberOverride(context.Background(), &domain.BarberOverride{
+		BarbershopID: 1,
+		BarberID:     2,
+		ServiceID:    3,
+		Offered:      false,
+	})
+	if err != nil {
+		t.Fatalf("UpsertBarberOverride: %v", err)
+	}
+
+	got, ok := insert.column("offered")
+	if !ok {
+		t.Fatalf("coluna offered fora do INSERT: %s", insert.sql)
+	}
+	if got != false {
+		t.Errorf("offered gravado = %v, esperado false", got)
+	}
+}
+
+// TestUpsertBarberOverride_OfferedFalseRoundTrip: barbeiro que não faz o
+// serviço continua sem ele depois de salvo — inclusive ao sobrescrever um
+// override que estava ligado.
+func TestUpsertBarberOverride_OfferedFalseRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	outerErr := db.Transaction(func(tx *gorm.DB) error {
+		bs := seedBarbershop(t, tx)
+		bsID := bs.ID
+		barber := models.User{
+			BarbershopID: &bsID,
+			Name:         "Barbeiro Teste",
+			Email:        "barber-override-" + suffix() + "@test.local",
+			PasswordHash: "x",
+			Role:         models.UserRoleBarber,
+		}
+		if err := tx.Create(&barber).Error; err != nil {
+			t.Fatalf("seed barber: %v", err)
+		}
+		svc := models.BarbershopService{BarbershopID: bs.ID, Name: "Corte", DurationMin: 30, Price: 5000}
+		if err := tx.Create(&svc).Error; err != nil {
+			t.Fatalf("seed service: %v", err)
+		}
+
+		repo := NewServiceGormRepository(tx)
+		for _, offered := range []bool{false, true, false} {
+			err := repo.UpsertBarberOverride(ctx, &domain.BarberOverride{
+				BarbershopID: bs.ID,
+				BarberID:     barber.ID,
+				ServiceID:    svc.ID,
+				Offered:      offered,
+			})
+			if err != nil {
+				t.Errorf("UpsertBarberOverride(offered=%v): %v", offered, err)
+				return errors.New("rollback — falha no act")
+			}
+
+			got, err := repo.GetBarberOverride(ctx, bs.ID, barber.ID, svc.ID)
+			if err != nil || got == nil {
+				t.Fatalf("GetBarberOverride: override=%v err=%v", got, err)
+			}
+			if got.Offered != offered {
+				t.Errorf("offered lido = %v, esperado %v", got.Offered, offered)
+			}
+		}
+
+		return errors.New("rollback intencional")
+	})
+
+	if outerErr != nil && outerErr.Error() != "rollback intencional" {
+		t.Errorf("transação de teste falhou inesperadamente: %v", outerErr)
+	}
+}
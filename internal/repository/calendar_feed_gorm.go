@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/calendarfeed"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type CalendarFeedGormRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarFeedGormRepository(db *gorm.DB) *CalendarFeedGormRepository {
+	return &CalendarFeedGormRepository{db: db}
+}
+
+func (r *CalendarFeedGormRepository) GetByToken(
+	ctx context.Context,
+	token string,
+) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		First(&feed).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *CalendarFeedGormRepository) Get(
+	ctx context.Context,
+	barbershopID uint,
+	barberID *uint,
+) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+
+	q := r.db.WithContext(ctx).Where("barbershop_id = ?", barbershopID)
+	if barberID != nil {
+		q = q.Where("barber_id = ?", *barberID)
+	} else {
+		q = q.Where("barber_id IS NULL")
+	}
+
+	err := q.First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *CalendarFeedGormRepository) CreateIfMissing(
+	ctx context.Context,
+	feed *models.CalendarFeed,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(feed).
+		Error
+}
+
+func (r *CalendarFeedGormRepository) UpdateToken(
+	ctx context.Context,
+	feedID uint,
+	token string,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.CalendarFeed{}).
+		Where("id = ?", feedID).
+		Update("token", token).
+		Error
+}
+
+func (r *CalendarFeedGormRepository) GetBarbershopByID(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).
+		First(&shop, barbershopID).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *CalendarFeedGormRepository) GetBarberName(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+) (string, error) {
+	var names []string
+
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND barbershop_id = ?", barberID, barbershopID).
+		Limit(1).
+		Pluck("name", &names).
+		Error
+	if err != nil || len(names) == 0 {
+		return "", err
+	}
+	return names[0], nil
+}
+
+func (r *CalendarFeedGormRepository) ListEvents(
+	ctx context.Context,
+	barbershopID uint,
+	barberID *uint,
+	from time.Time,
+) ([]domain.Event, error) {
+	var events []domain.Event
+
+	q := r.db.WithContext(ctx).
+		Table("appointments a").
+		Select(`
+			a.id                        AS appointment_id,
+			COALESCE(u.name, '')        AS barber_name,
+			COALESCE(c.name, '')        AS client_name,
+			COALESCE(
+			  (SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+			   FROM appointment_services s WHERE s.appointment_id = a.id),
+			  bs.name,
+			  ''
+			)                           AS service_name,
+			COALESCE(a.notes, '')       AS notes,
+			a.status,
+			a.start_time,
+			a.end_time,
+			a.calendar_sequence         AS sequence,
+			a.updated_at`).
+		Joins("LEFT JOIN users u ON u.id = a.barber_id").
+		Joins("LEFT JOIN clients c ON c.id = a.client_id").
+		Joins("LEFT JOIN barbershop_services bs ON bs.id = a.barber_product_id").
+		Where("a.barbershop_id = ? AND a.end_time >= ?", barbershopID, from)
+
+	if barberID != nil {
+		q = q.Where("a.barber_id = ?", *barberID)
+	}
+
+	err := q.Order("a.start_time ASC, a.id ASC").
+		Scan(&events).
+		Error
+	return events, err
+}
@@ -0,0 +1,597 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type CampaignGormRepository struct {
+	db *gorm.DB
+}
+
+func NewCampaignGormRepository(db *gorm.DB) *CampaignGormRepository {
+	return &CampaignGormRepository{db: db}
+}
+
+// ======================================================
+// SEGMENTS
+// ======================================================
+
+func (r *CampaignGormRepository) ListSegments(
+	ctx context.Context,
+	barbershopID uint,
+) ([]models.ClientSegment, error) {
+	var list []models.ClientSegment
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("name ASC, id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *CampaignGormRepository) GetSegment(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*models.ClientSegment, error) {
+	var s models.ClientSegment
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *CampaignGormRepository) SaveSegment(
+	ctx context.Context,
+	s *models.ClientSegment,
+) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
+
+func (r *CampaignGormRepository) DeleteSegment(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Delete(&models.ClientSegment{}).
+		Error
+}
+
+func (r *CampaignGormRepository) SegmentInUse(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (bool, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Campaign{}).
+		Where("barbershop_id = ? AND segment_id = ?", barbershopID, id).
+		Where("status IN ?", []string{
+			models.CampaignStatusDraft,
+			models.CampaignStatusScheduled,
+			models.CampaignStatusSending,
+		}).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *CampaignGormRepository) MatchClients(
+	ctx context.Context,
+	barbershopID uint,
+	f domain.SegmentFilters,
+	limit int,
+	offset int,
+) ([]domain.SegmentClient, int64, error) {
+	var total int64
+	if err := r.segmentQuery(ctx, barbershopID, f).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	q := r.segmentQuery(ctx, barbershopID, f).Select(`c.id, c.name,
+		COALESCE(c.phone, '') AS phone,
+		COALESCE(c.email, '') AS email,
+		c.marketing_opt_out_at IS NOT NULL AS opted_out`).
+		Order("LOWER(c.name) ASC, c.id ASC")
+	if limit > 0 {
+		q = q.Limit(limit).Offset(offset)
+	}
+
+	var list []domain.SegmentClient
+	if err := q.Scan(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// segmentQuery monta o SELECT dos clientes que casam com os filtros. As
+// métricas vêm de client_metrics; cliente sem métricas é new, sem gasto e
+// sem faltas.
+func (r *CampaignGormRepository) segmentQuery(
+	ctx context.Context,
+	barbershopID uint,
+	f domain.SegmentFilters,
+) *gorm.DB {
+	q := r.db.WithContext(ctx).
+		Table("clients c").
+		Joins("LEFT JOIN client_metrics cm ON cm.client_id = c.id AND cm.barbershop_id = c.barbershop_id").
+		Where("c.barbershop_id = ? AND c.anonymized_at IS NULL AND c.merged_into_id IS NULL", barbershopID)
+
+	if len(f.Categories) > 0 {
+		q = q.Where("COALESCE(cm.category, 'new') IN ?", f.Categories)
+	}
+
+	if f.MinDaysSinceLastVisit != nil {
+		q = q.Where("cm.last_completed_at <= now() - make_interval(days => ?)", *f.MinDaysSinceLastVisit)
+	}
+	if f.MaxDaysSinceLastVisit != nil {
+		q = q.Where("cm.last_completed_at >= now() - make_interval(days => ?)", *f.MaxDaysSinceLastVisit)
+	}
+
+	if f.MinTotalSpent != nil {
+		q = q.Where("COALESCE(cm.total_spent, 0) >= ?", *f.MinTotalSpent)
+	}
+	if f.MaxTotalSpent != nil {
+		q = q.Where("COALESCE(cm.total_spent, 0) <= ?", *f.MaxTotalSpent)
+	}
+
+	if f.MinNoShows != nil {
+		q = q.Where("COALESCE(cm.no_show_appointments, 0) >= ?", *f.MinNoShows)
+	}
+	if f.MaxNoShows != nil {
+		q = q.Where("COALESCE(cm.no_show_appointments, 0) <= ?", *f.MaxNoShows)
+	}
+
+	if f.ActiveSubscription != nil {
+		exists := `EXISTS (
+			SELECT 1 FROM subscriptions s
+			WHERE s.client_id = c.id AND s.barbershop_id = c.barbershop_id AND s.status = 'active')`
+		if *f.ActiveSubscription {
+			q = q.Where(exists)
+		} else {
+			q = q.Where("NOT " + exists)
+		}
+	}
+
+	if len(f.ServiceIDs) > 0 {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM appointments a
+			JOIN appointment_services aps ON aps.appointment_id = a.id
+			WHERE a.client_id = c.id AND a.barbershop_id = c.barbershop_id
+			  AND a.status = 'completed' AND aps.service_id IN ?)`, f.ServiceIDs)
+	}
+
+	if len(f.ProductIDs) > 0 {
+		q = q.Where(`EXISTS (
+			SELECT 1 FROM orders o
+			JOIN order_items oi ON oi.order_id = o.id
+			WHERE o.client_id = c.id AND o.barbershop_id = c.barbershop_id
+			  AND o.status = 'paid' AND oi.product_id IN ?)`, f.ProductIDs)
+	}
+
+	return q
+}
+
+// ======================================================
+// CAMPAIGNS
+// ======================================================
+
+func (r *CampaignGormRepository) ListCampaigns(
+	ctx context.Context,
+	barbershopID uint,
+) ([]models.Campaign, error) {
+	var list []models.Campaign
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("created_at DESC, id DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *CampaignGormRepository) GetCampaign(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*models.Campaign, error) {
+	var c models.Campaign
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CampaignGormRepository) CreateCampaign(
+	ctx context.Context,
+	c *models.Campaign,
+) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *CampaignGormRepository) UpdateCampaign(
+	ctx context.Context,
+	c *models.Campaign,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.Campaign{}).
+		Where("id = ? AND barbershop_id = ?", c.ID, c.BarbershopID).
+		Where("status IN ?", []string{models.CampaignStatusDraft, models.CampaignStatusScheduled}).
+		Updates(map[string]any{
+			"segment_id":   c.SegmentID,
+			"name":         c.Name,
+			"channel":      c.Channel,
+			"subject":      c.Subject,
+			"body":         c.Body,
+			"status":       c.Status,
+			"scheduled_at": c.ScheduledAt,
+			"updated_at":   time.Now().UTC(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *CampaignGormRepository) DeleteCampaign(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ? AND status = ?", id, barbershopID, models.CampaignStatusDraft).
+		Delete(&models.Campaign{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *CampaignGormRepository) CancelCampaign(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+	at time.Time,
+) (bool, error) {
+	cancelled := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Campaign{}).
+			Where("id = ? AND barbershop_id = ?", id, barbershopID).
+			Where("status IN ?", []string{
+				models.CampaignStatusDraft,
+				models.CampaignStatusScheduled,
+				models.CampaignStatusSending,
+			}).
+			Updates(map[string]any{
+				"status":      models.CampaignStatusCancelled,
+				"finished_at": at,
+				"updated_at":  at,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		cancelled = true
+
+		return tx.Model(&models.CampaignRecipient{}).
+			Where("campaign_id = ? AND status = ?", id, models.CampaignRecipientPending).
+			Updates(map[string]any{
+				"status":     models.CampaignRecipientCancelled,
+				"updated_at": at,
+			}).Error
+	})
+	return cancelled, err
+}
+
+func (r *CampaignGormRepository) ListRecipients(
+	ctx context.Context,
+	barbershopID uint,
+	campaignID uint,
+	status string,
+	limit int,
+	offset int,
+) ([]models.CampaignRecipient, int64, error) {
+	base := func() *gorm.DB {
+		q := r.db.WithContext(ctx).
+			Model(&models.CampaignRecipient{}).
+			Where("campaign_id = ? AND barbershop_id = ?", campaignID, barbershopID)
+		if status != "" {
+			q = q.Where("status = ?", status)
+		}
+		return q
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []models.CampaignRecipient
+	err := base().Order("id ASC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+func (r *CampaignGormRepository) CampaignStats(
+	ctx context.Context,
+	barbershopID uint,
+	campaignID uint,
+) (*domain.Stats, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ? AND barbershop_id = ?", campaignID, barbershopID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.Stats{}
+	for _, row := range rows {
+		switch row.Status {
+		case models.CampaignRecipientPending:
+			stats.Pending = row.Count
+		case models.CampaignRecipientSent:
+			stats.Sent = row.Count
+		case models.CampaignRecipientFailed:
+			stats.Failed = row.Count
+		case models.CampaignRecipientSkipped:
+			stats.Skipped = row.Count
+		case models.CampaignRecipientCancelled:
+			stats.Cancelled = row.Count
+		}
+	}
+
+	var attributed struct {
+		Bookings     int
+		Completed    int
+		RevenueCents int64
+	}
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) AS bookings,
+			COUNT(*) FILTER (WHERE ca.appointment_status = 'completed') AS completed,
+			COALESCE(SUM(COALESCE(ac.final_amount_cents, ac.reference_amount_cents)), 0) AS revenue_cents
+		FROM campaign_attributions ca
+		LEFT JOIN appointment_closures ac ON ac.appointment_id = ca.appointment_id
+		WHERE ca.campaign_id = ? AND ca.barbershop_id = ?
+	`, campaignID, barbershopID).Scan(&attributed).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Bookings = attributed.Bookings
+	stats.Completed = attributed.Completed
+	stats.RevenueCents = attributed.RevenueCents
+
+	return stats, nil
+}
+
+// ======================================================
+// DISPATCH
+// ======================================================
+
+func (r *CampaignGormRepository) DueCampaigns(
+	ctx context.Context,
+	now time.Time,
+) ([]models.Campaign, error) {
+	var list []models.Campaign
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND scheduled_at <= ?)",
+			models.CampaignStatusSending, models.CampaignStatusScheduled, now).
+		Order("scheduled_at ASC, id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *CampaignGormRepository) StartCampaign(
+	ctx context.Context,
+	c *models.Campaign,
+	recipients []models.CampaignRecipient,
+	at time.Time,
+) (bool, error) {
+	started := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Campaign{}).
+			Where("id = ? AND status = ?", c.ID, models.CampaignStatusScheduled).
+			Updates(map[string]any{
+				"status":     models.CampaignStatusSending,
+				"started_at": at,
+				"updated_at": at,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		started = true
+
+		if len(recipients) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(recipients, 500).Error
+	})
+	if started {
+		c.Status = models.CampaignStatusSending
+		c.StartedAt = &at
+	}
+	return started, err
+}
+
+func (r *CampaignGormRepository) PendingRecipients(
+	ctx context.Context,
+	campaignID uint,
+	limit int,
+) ([]domain.PendingRecipient, error) {
+	var list []domain.PendingRecipient
+
+	err := r.db.WithContext(ctx).
+		Table("campaign_recipients cr").
+		Select(`cr.*, c.name AS client_name,
+			c.marketing_opt_out_at IS NOT NULL AS opted_out,
+			c.anonymized_at IS NOT NULL AS anonymized`).
+		Joins("JOIN clients c ON c.id = cr.client_id").
+		Where("cr.campaign_id = ? AND cr.status = ?", campaignID, models.CampaignRecipientPending).
+		Order("cr.id ASC").
+		Limit(limit).
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *CampaignGormRepository) UpdateRecipient(
+	ctx context.Context,
+	rec *models.CampaignRecipient,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("id = ? AND status = ?", rec.ID, models.CampaignRecipientPending).
+		Updates(map[string]any{
+			"status":     rec.Status,
+			"error":      rec.Error,
+			"sent_at":    rec.SentAt,
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+func (r *CampaignGormRepository) FinishCampaign(
+	ctx context.Context,
+	id uint,
+	at time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Campaign{}).
+		Where("id = ? AND status = ?", id, models.CampaignStatusSending).
+		Updates(map[string]any{
+			"status":      models.CampaignStatusCompleted,
+			"finished_at": at,
+			"updated_at":  at,
+		}).Error
+}
+
+func (r *CampaignGormRepository) GetBarbershop(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).First(&shop, barbershopID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *CampaignGormRepository) WhatsAppConnected(
+	ctx context.Context,
+	barbershopID uint,
+) (bool, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.BarbershopWhatsAppInstance{}).
+		Where("instance_name = ? AND status = ?", models.InstanceNameFor(barbershopID, nil), "connected").
+		Count(&n).Error
+	return n > 0, err
+}
+
+// ======================================================
+// OPT-OUT
+// ======================================================
+
+func (r *CampaignGormRepository) FindRecipientByToken(
+	ctx context.Context,
+	token string,
+) (*models.CampaignRecipient, error) {
+	var rec models.CampaignRecipient
+
+	err := r.db.WithContext(ctx).
+		Where("unsubscribe_token = ?", token).
+		First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *CampaignGormRepository) SetClientOptOut(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	at *time.Time,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.Client{}).
+		Where("id = ? AND barbershop_id = ?", clientID, barbershopID).
+		Update("marketing_opt_out_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *CampaignGormRepository) OptOutByPhone(
+	ctx context.Context,
+	barbershopID uint,
+	phone string,
+	at time.Time,
+) (bool, error) {
+	// Últimos 8 dígitos, como o bot: o WhatsApp entrega DDI+DDD+número e o
+	// cadastro pode ter qualquer formatação.
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) < 8 {
+		return false, nil
+	}
+	suffix := digits[len(digits)-8:]
+
+	res := r.db.WithContext(ctx).
+		Model(&models.Client{}).
+		Where("barbershop_id = ? AND anonymized_at IS NULL", barbershopID).
+		Where("REGEXP_REPLACE(phone, '[^0-9]', '', 'g') LIKE ?", "%"+suffix).
+		Where("marketing_opt_out_at IS NULL").
+		Update("marketing_opt_out_at", at)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Já descadastrado também conta como casado.
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Client{}).
+		Where("barbershop_id = ? AND anonymized_at IS NULL", barbershopID).
+		Where("REGEXP_REPLACE(phone, '[^0-9]', '', 'g') LIKE ?", "%"+suffix).
+		Count(&n).Error
+	return n > 0, err
+}
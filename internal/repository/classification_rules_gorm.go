@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ClassificationRulesGormRepository struct {
+	db *gorm.DB
+}
+
+func NewClassificationRulesGormRepository(db *gorm.DB) *ClassificationRulesGormRepository {
+	return &ClassificationRulesGormRepository{db: db}
+}
+
+func (r *ClassificationRulesGormRepository) GetRules(
+	ctx context.Context,
+	barbershopID uint,
+) (*domain.RulesConfig, error) {
+	var row models.ClientClassificationRules
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rulesToDomain(&row)
+}
+
+func (r *ClassificationRulesGormRepository) SaveRules(
+	ctx context.Context,
+	barbershopID uint,
+	rules domain.ClassificationRules,
+) error {
+	row := models.ClientClassificationRules{
+		BarbershopID:      barbershopID,
+		ReclassifyPending: true,
+	}
+	if rules != nil {
+		raw, err := json.Marshal(rules)
+		if err != nil {
+			return err
+		}
+		s := string(raw)
+		row.Rules = &s
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "barbershop_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"rules",
+				"reclassify_pending",
+				"updated_at",
+			}),
+		}).
+		Create(&row).
+		Error
+}
+
+func (r *ClassificationRulesGormRepository) PendingReclassification(
+	ctx context.Context,
+) ([]domain.RulesConfig, error) {
+	var rows []models.ClientClassificationRules
+
+	if err := r.db.WithContext(ctx).
+		Where("reclassify_pending").
+		Order("updated_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.RulesConfig, 0, len(rows))
+	for i := range rows {
+		cfg, err := rulesToDomain(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *cfg)
+	}
+	return out, nil
+}
+
+func (r *ClassificationRulesGormRepository) MarkReclassified(
+	ctx context.Context,
+	cfg domain.RulesConfig,
+	at time.Time,
+) error {
+	// Regras salvas durante a reclassificação mantêm a marca para a próxima rodada.
+	return r.db.WithContext(ctx).
+		Model(&models.ClientClassificationRules{}).
+		Where("barbershop_id = ? AND updated_at <= ?", cfg.BarbershopID, cfg.UpdatedAt).
+		UpdateColumns(map[string]any{
+			"reclassify_pending": false,
+			"reclassified_at":    at,
+		}).
+		Error
+}
+
+func (r *ClassificationRulesGormRepository) UpdateCategory(
+	ctx context.Context,
+	m *domain.ClientMetrics,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClientMetrics{}).
+		Where("client_id = ? AND barbershop_id = ?", m.ClientID, m.BarbershopID).
+		UpdateColumns(map[string]any{
+			"category":                   string(m.Category),
+			"category_source":            string(m.CategorySource),
+			"manual_category_expires_at": m.ManualCategoryExpiresAt,
+			"updated_at":                 time.Now().UTC(),
+		}).
+		Error
+}
+
+func rulesToDomain(row *models.ClientClassificationRules) (*domain.RulesConfig, error) {
+	cfg := &domain.RulesConfig{
+		BarbershopID:      row.BarbershopID,
+		ReclassifyPending: row.ReclassifyPending,
+		ReclassifiedAt:    row.ReclassifiedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+	if row.Rules != nil {
+		if err := json.Unmarshal([]byte(*row.Rules), &cfg.Rules); err != nil {
+			return nil, err
+		}
+		if cfg.Rules == nil {
+			cfg.Rules = domain.ClassificationRules{}
+		}
+	}
+	return cfg, nil
+}
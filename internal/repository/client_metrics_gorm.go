@@ -84,8 +84,9 @@ func mapToDomain(m *infraModels.ClientMetrics) *domain.ClientMetrics {
 		LastLateCanceledAt:    m.LastLateCanceledAt,
 		LastLateRescheduledAt: m.LastLateRescheduledAt,
 
-		Category:       domain.ClientCategory(m.Category),
-		CategorySource: domain.CategorySource(m.CategorySource),
+		Category:                domain.ClientCategory(m.Category),
+		CategorySource:          domain.CategorySource(m.CategorySource),
+		ManualCategoryExpiresAt: m.ManualCategoryExpiresAt,
 
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
@@ -115,8 +116,9 @@ func mapToModel(m *domain.ClientMetrics) *infraModels.ClientMetrics {
 		LastLateCanceledAt:    m.LastLateCanceledAt,
 		LastLateRescheduledAt: m.LastLateRescheduledAt,
 
-		Category:       infraModels.ClientCategory(m.Category),
-		CategorySource: infraModels.CategorySourceType(m.CategorySource),
+		Category:                infraModels.ClientCategory(m.Category),
+		CategorySource:          infraModels.CategorySourceType(m.CategorySource),
+		ManualCategoryExpiresAt: m.ManualCategoryExpiresAt,
 	}
 }
 
@@ -140,3 +142,37 @@ func (r *ClientMetricsGormRepository) FindByBarbershop(
 
 	return result, nil
 }
+
+// Find retorna nil quando o cliente ainda não tem métricas. Com lock, como
+// GetOrCreate, para quem vai regravar dentro da transação.
+func (r *ClientMetricsGormRepository) Find(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*domain.ClientMetrics, error) {
+
+	var m infraModels.ClientMetrics
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("client_id = ? AND barbershop_id = ?", clientID, barbershopID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToDomain(&m), nil
+}
+
+func (r *ClientMetricsGormRepository) Delete(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("client_id = ? AND barbershop_id = ?", clientID, barbershopID).
+		Delete(&infraModels.ClientMetrics{}).Error
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ClientPortalGormRepository struct {
+	db *gorm.DB
+}
+
+func NewClientPortalGormRepository(db *gorm.DB) *ClientPortalGormRepository {
+	return &ClientPortalGormRepository{db: db}
+}
+
+var _ domain.Repository = (*ClientPortalGormRepository)(nil)
+
+// ======================================================
+// CLIENTS
+// ======================================================
+
+func (r *ClientPortalGormRepository) findClient(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (*models.Client, error) {
+	var c models.Client
+
+	err := r.db.WithContext(ctx).
+		Where(query, args...).
+		Where("anonymized_at IS NULL AND merged_into_id IS NULL").
+		Order("id ASC").
+		First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ClientPortalGormRepository) FindClientByPhone(
+	ctx context.Context,
+	barbershopID uint,
+	phone string,
+) (*models.Client, error) {
+	return r.findClient(ctx, "barbershop_id = ? AND phone = ?", barbershopID, phone)
+}
+
+func (r *ClientPortalGormRepository) FindClientByEmail(
+	ctx context.Context,
+	barbershopID uint,
+	email string,
+) (*models.Client, error) {
+	return r.findClient(ctx, "barbershop_id = ? AND LOWER(email) = ?", barbershopID, email)
+}
+
+func (r *ClientPortalGormRepository) GetClient(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*models.Client, error) {
+	return r.findClient(ctx, "barbershop_id = ? AND id = ?", barbershopID, clientID)
+}
+
+func (r *ClientPortalGormRepository) UpdateClientContact(
+	ctx context.Context,
+	c *models.Client,
+) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.Client{}).
+		Where("id = ? AND barbershop_id = ?", c.ID, c.BarbershopID).
+		Updates(map[string]any{
+			"name":       c.Name,
+			"phone":      c.Phone,
+			"email":      c.Email,
+			"updated_at": time.Now().UTC(),
+		}).Error
+	if isPgUniqueViolation(err, "uq_clients_barbershop_phone") {
+		return domain.ErrPhoneTaken
+	}
+	return err
+}
+
+// ======================================================
+// LOGIN CODES
+// ======================================================
+
+func (r *ClientPortalGormRepository) CreateCode(
+	ctx context.Context,
+	c *models.ClientLoginCode,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&models.ClientLoginCode{}).
+			Where("client_id = ? AND used_at IS NULL", c.ClientID).
+			Update("expires_at", c.CreatedAt).Error; err != nil {
+			return err
+		}
+		return tx.Create(c).Error
+	})
+}
+
+func (r *ClientPortalGormRepository) LatestCode(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*models.ClientLoginCode, error) {
+	var c models.ClientLoginCode
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND client_id = ? AND used_at IS NULL", barbershopID, clientID).
+		Order("created_at DESC, id DESC").
+		First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ClientPortalGormRepository) IncrementAttempts(
+	ctx context.Context,
+	codeID uint,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClientLoginCode{}).
+		Where("id = ?", codeID).
+		Update("attempts", gorm.Expr("attempts + 1")).
+		Error
+}
+
+func (r *ClientPortalGormRepository) UseCode(
+	ctx context.Context,
+	codeID uint,
+	now time.Time,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.ClientLoginCode{}).
+		Where("id = ? AND used_at IS NULL", codeID).
+		Update("used_at", now)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ======================================================
+// SESSIONS
+// ======================================================
+
+func (r *ClientPortalGormRepository) CreateSession(
+	ctx context.Context,
+	s *models.ClientSession,
+) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *ClientPortalGormRepository) FindSession(
+	ctx context.Context,
+	slug string,
+	tokenHash string,
+	now time.Time,
+) (*models.ClientSession, error) {
+	var s models.ClientSession
+
+	err := r.db.WithContext(ctx).
+		Table("client_sessions cs").
+		Select("cs.*").
+		Joins("JOIN barbershops b ON b.id = cs.barbershop_id").
+		Joins("JOIN clients c ON c.id = cs.client_id").
+		Where("b.slug = ? AND cs.token_hash = ?", slug, tokenHash).
+		Where("cs.revoked_at IS NULL AND cs.expires_at > ?", now).
+		Where("c.anonymized_at IS NULL").
+		Take(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ClientPortalGormRepository) TouchSession(
+	ctx context.Context,
+	sessionID uint,
+	now time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClientSession{}).
+		Where("id = ?", sessionID).
+		Update("last_seen_at", now).
+		Error
+}
+
+func (r *ClientPortalGormRepository) RevokeSession(
+	ctx context.Context,
+	sessionID uint,
+	now time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClientSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).
+		Error
+}
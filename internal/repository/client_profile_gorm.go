@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ClientProfileGormRepository struct {
+	db *gorm.DB
+}
+
+func NewClientProfileGormRepository(db *gorm.DB) *ClientProfileGormRepository {
+	return &ClientProfileGormRepository{db: db}
+}
+
+func (r *ClientProfileGormRepository) ClientExists(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (bool, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Client{}).
+		Where("id = ? AND barbershop_id = ?", clientID, barbershopID).
+		Where("anonymized_at IS NULL AND merged_into_id IS NULL").
+		Count(&n).Error
+	return n > 0, err
+}
+
+// ======================================================
+// NOTES
+// ======================================================
+
+func (r *ClientProfileGormRepository) ListNotes(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	limit int,
+) ([]domain.NoteView, error) {
+	var list []domain.NoteView
+
+	err := r.db.WithContext(ctx).
+		Table("client_notes n").
+		Select("n.*, COALESCE(u.name, '') AS author_name").
+		Joins("LEFT JOIN users u ON u.id = n.author_user_id").
+		Where("n.barbershop_id = ? AND n.client_id = ?", barbershopID, clientID).
+		Order("n.pinned DESC, n.created_at DESC, n.id DESC").
+		Limit(limit).
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *ClientProfileGormRepository) GetNote(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	id uint,
+) (*models.ClientNote, error) {
+	var n models.ClientNote
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ? AND client_id = ?", id, barbershopID, clientID).
+		First(&n).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *ClientProfileGormRepository) SaveNote(
+	ctx context.Context,
+	n *models.ClientNote,
+) error {
+	return r.db.WithContext(ctx).Save(n).Error
+}
+
+func (r *ClientProfileGormRepository) DeleteNote(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Delete(&models.ClientNote{}).
+		Error
+}
+
+// ======================================================
+// TAGS
+// ======================================================
+
+func (r *ClientProfileGormRepository) ListTags(
+	ctx context.Context,
+	barbershopID uint,
+) ([]domain.TagView, error) {
+	var list []domain.TagView
+
+	err := r.db.WithContext(ctx).
+		Table("client_tags t").
+		Select(`t.*, (
+			SELECT COUNT(*) FROM client_tag_assignments a
+			JOIN clients c ON c.id = a.client_id
+			WHERE a.tag_id = t.id AND c.anonymized_at IS NULL AND c.merged_into_id IS NULL
+		) AS clients`).
+		Where("t.barbershop_id = ?", barbershopID).
+		Order("LOWER(t.name) ASC, t.id ASC").
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *ClientProfileGormRepository) GetTag(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*models.ClientTag, error) {
+	var t models.ClientTag
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *ClientProfileGormRepository) TagNameTaken(
+	ctx context.Context,
+	barbershopID uint,
+	name string,
+	exceptID uint,
+) (bool, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ClientTag{}).
+		Where("barbershop_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", barbershopID, name, exceptID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *ClientProfileGormRepository) CountTags(
+	ctx context.Context,
+	barbershopID uint,
+) (int64, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ClientTag{}).
+		Where("barbershop_id = ?", barbershopID).
+		Count(&n).Error
+	return n, err
+}
+
+func (r *ClientProfileGormRepository) SaveTag(
+	ctx context.Context,
+	t *models.ClientTag,
+) error {
+	return r.db.WithContext(ctx).Save(t).Error
+}
+
+func (r *ClientProfileGormRepository) DeleteTag(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) error {
+	// as atribuições saem pelo ON DELETE CASCADE
+	return r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Delete(&models.ClientTag{}).
+		Error
+}
+
+func (r *ClientProfileGormRepository) ClientTags(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) ([]models.ClientTag, error) {
+	var list []models.ClientTag
+
+	err := r.db.WithContext(ctx).
+		Table("client_tags t").
+		Select("t.*").
+		Joins("JOIN client_tag_assignments a ON a.tag_id = t.id").
+		Where("a.barbershop_id = ? AND a.client_id = ?", barbershopID, clientID).
+		Order("LOWER(t.name) ASC, t.id ASC").
+		Scan(&list).Error
+	return list, err
+}
+
+func (r *ClientProfileGormRepository) SetClientTags(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	tagIDs []uint,
+) (bool, error) {
+	ok := true
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(tagIDs) > 0 {
+			var n int64
+			if err := tx.Model(&models.ClientTag{}).
+				Where("barbershop_id = ? AND id IN ?", barbershopID, tagIDs).
+				Count(&n).Error; err != nil {
+				return err
+			}
+			if int(n) != len(tagIDs) {
+				ok = false
+				return nil
+			}
+		}
+
+		del := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID)
+		if len(tagIDs) > 0 {
+			del = del.Where("tag_id NOT IN ?", tagIDs)
+		}
+		if err := del.Delete(&models.ClientTagAssignment{}).Error; err != nil {
+			return err
+		}
+
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		rows := make([]models.ClientTagAssignment, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			rows = append(rows, models.ClientTagAssignment{
+				BarbershopID: barbershopID,
+				ClientID:     clientID,
+				TagID:        id,
+			})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+	return ok, err
+}
+
+// ======================================================
+// PREFERENCES
+// ======================================================
+
+func (r *ClientProfileGormRepository) GetPreferences(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*domain.Preferences, error) {
+	var m models.ClientPreferences
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &domain.Preferences{
+		PreferredBarberID:  m.PreferredBarberID,
+		Allergies:          m.Allergies,
+		FavoriteServiceIDs: []uint{},
+		FavoriteProductIDs: []uint{},
+		Beverage:           m.Beverage,
+	}
+	if err := json.Unmarshal([]byte(m.FavoriteServiceIDs), &p.FavoriteServiceIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(m.FavoriteProductIDs), &p.FavoriteProductIDs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *ClientProfileGormRepository) SavePreferences(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	userID uint,
+	p domain.Preferences,
+) error {
+	services, err := json.Marshal(p.FavoriteServiceIDs)
+	if err != nil {
+		return err
+	}
+	products, err := json.Marshal(p.FavoriteProductIDs)
+	if err != nil {
+		return err
+	}
+
+	m := models.ClientPreferences{
+		BarbershopID:       barbershopID,
+		ClientID:           clientID,
+		PreferredBarberID:  p.PreferredBarberID,
+		Allergies:          p.Allergies,
+		FavoriteServiceIDs: string(services),
+		FavoriteProductIDs: string(products),
+		Beverage:           p.Beverage,
+		UpdatedByUserID:    &userID,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "client_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"preferred_barber_id",
+				"allergies",
+				"favorite_service_ids",
+				"favorite_product_ids",
+				"beverage",
+				"updated_by_user_id",
+				"updated_at",
+			}),
+		}).
+		Create(&m).Error
+}
+
+func (r *ClientProfileGormRepository) PreferenceRefsValid(
+	ctx context.Context,
+	barbershopID uint,
+	p domain.Preferences,
+) (bool, error) {
+	db := r.db.WithContext(ctx)
+
+	if p.PreferredBarberID != nil {
+		var n int64
+		if err := db.Model(&models.User{}).
+			Where("id = ? AND barbershop_id = ? AND active", *p.PreferredBarberID, barbershopID).
+			Count(&n).Error; err != nil || n == 0 {
+			return false, err
+		}
+	}
+	if len(p.FavoriteServiceIDs) > 0 {
+		var n int64
+		if err := db.Model(&models.BarbershopService{}).
+			Where("barbershop_id = ? AND id IN ?", barbershopID, p.FavoriteServiceIDs).
+			Count(&n).Error; err != nil || int(n) != len(p.FavoriteServiceIDs) {
+			return false, err
+		}
+	}
+	if len(p.FavoriteProductIDs) > 0 {
+		var n int64
+		if err := db.Model(&models.Product{}).
+			Where("barbershop_id = ? AND id IN ?", barbershopID, p.FavoriteProductIDs).
+			Count(&n).Error; err != nil || int(n) != len(p.FavoriteProductIDs) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ======================================================
+// PHOTOS
+// ======================================================
+
+func (r *ClientProfileGormRepository) ListPhotos(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	appointmentID uint,
+	limit int,
+) ([]models.ClientPhoto, error) {
+	var list []models.ClientPhoto
+
+	q := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID)
+	if appointmentID != 0 {
+		q = q.Where("appointment_id = ?", appointmentID)
+	}
+	err := q.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *ClientProfileGormRepository) AppointmentOfClient(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	appointmentID uint,
+) (bool, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Appointment{}).
+		Where("id = ? AND barbershop_id = ? AND client_id = ?", appointmentID, barbershopID, clientID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *ClientProfileGormRepository) CountAppointmentPhotos(
+	ctx context.Context,
+	barbershopID uint,
+	appointmentID uint,
+) (int64, error) {
+	var n int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.ClientPhoto{}).
+		Where("barbershop_id = ? AND appointment_id = ?", barbershopID, appointmentID).
+		Count(&n).Error
+	return n, err
+}
+
+func (r *ClientProfileGormRepository) GetPhoto(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	id uint,
+) (*models.ClientPhoto, error) {
+	var p models.ClientPhoto
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ? AND client_id = ?", id, barbershopID, clientID).
+		First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ClientProfileGormRepository) SavePhoto(
+	ctx context.Context,
+	p *models.ClientPhoto,
+) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *ClientProfileGormRepository) DeletePhoto(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Delete(&models.ClientPhoto{}).
+		Error
+}
@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type CouponGormRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponGormRepository(db *gorm.DB) *CouponGormRepository {
+	return &CouponGormRepository{db: db}
+}
+
+// WithTx devolve o repositório vinculado a uma transação existente.
+func (r *CouponGormRepository) WithTx(tx *gorm.DB) domain.Repository {
+	return &CouponGormRepository{db: tx}
+}
+
+// couponTargetTables: tabela de cada escopo restringível.
+var couponTargetTables = map[string]string{
+	models.CouponAppliesToServices: "barbershop_services",
+	models.CouponAppliesToProducts: "products",
+	models.CouponAppliesToPlans:    "plans",
+}
+
+func (r *CouponGormRepository) CountTargets(
+	ctx context.Context,
+	barbershopID uint,
+	appliesTo string,
+	ids []uint,
+) (int64, error) {
+	table, ok := couponTargetTables[appliesTo]
+	if !ok {
+		return 0, fmt.Errorf("coupon scope %q has no targets", appliesTo)
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table(table).
+		Where("barbershop_id = ? AND id IN ?", barbershopID, ids).
+		Count(&count).Error
+	return count, err
+}
+
+// ======================================================
+// COUPONS
+// ======================================================
+
+func (r *CouponGormRepository) Create(
+	ctx context.Context,
+	c *models.Coupon,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(c).Error; err != nil {
+			return err
+		}
+		return insertCouponTargets(tx, c.ID, c.TargetIDs)
+	})
+	if isPgUniqueViolation(err, "uq_coupons_barbershop_code") {
+		return domain.ErrCodeTaken
+	}
+	return err
+}
+
+func (r *CouponGormRepository) Update(
+	ctx context.Context,
+	c *models.Coupon,
+) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Coupon{}).
+			Where("id = ? AND barbershop_id = ?", c.ID, c.BarbershopID).
+			Updates(map[string]any{
+				"code":                c.Code,
+				"description":         c.Description,
+				"discount_type":       c.DiscountType,
+				"discount_value":      c.DiscountValue,
+				"applies_to":          c.AppliesTo,
+				"starts_at":           c.StartsAt,
+				"ends_at":             c.EndsAt,
+				"max_uses":            c.MaxUses,
+				"max_uses_per_client": c.MaxUsesPerClient,
+				"min_order_cents":     c.MinOrderCents,
+				"first_visit_only":    c.FirstVisitOnly,
+				"active":              c.Active,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM coupon_targets WHERE coupon_id = ?`, c.ID).Error; err != nil {
+			return err
+		}
+		return insertCouponTargets(tx, c.ID, c.TargetIDs)
+	})
+	if isPgUniqueViolation(err, "uq_coupons_barbershop_code") {
+		return domain.ErrCodeTaken
+	}
+	return err
+}
+
+func insertCouponTargets(tx *gorm.DB, couponID uint, targetIDs []uint) error {
+	for _, targetID := range targetIDs {
+		if err := tx.Exec(
+			`INSERT INTO coupon_targets (coupon_id, target_id) VALUES (?, ?)`,
+			couponID, targetID,
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CouponGormRepository) Get(
+	ctx context.Context,
+	barbershopID uint,
+	couponID uint,
+) (*models.Coupon, error) {
+	return r.first(ctx, r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", couponID, barbershopID))
+}
+
+func (r *CouponGormRepository) GetByCode(
+	ctx context.Context,
+	barbershopID uint,
+	code string,
+) (*models.Coupon, error) {
+	return r.first(ctx, r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND UPPER(code) = ?", barbershopID, strings.ToUpper(code)))
+}
+
+func (r *CouponGormRepository) first(ctx context.Context, q *gorm.DB) (*models.Coupon, error) {
+	var c models.Coupon
+
+	err := q.First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCouponTargets(ctx, []*models.Coupon{&c}); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CouponGormRepository) List(
+	ctx context.Context,
+	barbershopID uint,
+) ([]models.Coupon, error) {
+	var coupons []models.Coupon
+
+	if err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("active DESC, created_at DESC").
+		Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*models.Coupon, 0, len(coupons))
+	for i := range coupons {
+		ptrs = append(ptrs, &coupons[i])
+	}
+	if err := r.loadCouponTargets(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (r *CouponGormRepository) loadCouponTargets(
+	ctx context.Context,
+	coupons []*models.Coupon,
+) error {
+	if len(coupons) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint]*models.Coupon, len(coupons))
+	ids := make([]uint, 0, len(coupons))
+	for _, c := range coupons {
+		c.TargetIDs = []uint{}
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+
+	var rows []struct {
+		CouponID uint
+		TargetID uint
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT coupon_id, target_id
+		FROM coupon_targets
+		WHERE coupon_id IN ?
+		ORDER BY target_id
+	`, ids).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if c := byID[row.CouponID]; c != nil {
+			c.TargetIDs = append(c.TargetIDs, row.TargetID)
+		}
+	}
+	return nil
+}
+
+func (r *CouponGormRepository) HasCompletedVisit(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM appointments
+			WHERE barbershop_id = ? AND client_id = ? AND status = 'completed'
+		)
+	`, barbershopID, clientID).Scan(&exists).Error
+	return exists, err
+}
+
+// ======================================================
+// REDEMPTIONS
+// ======================================================
+
+// Redeem: o UPDATE condicional de uses_count é o controle do limite total e
+// trava a linha do cupom até o fim da transação, então a contagem por
+// cliente que vem depois não corre com outro resgate do mesmo código.
+func (r *CouponGormRepository) Redeem(
+	ctx context.Context,
+	redemption *models.CouponRedemption,
+	perClientLimit *int,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE coupons
+			SET uses_count = uses_count + 1
+			WHERE id = ? AND (max_uses IS NULL OR uses_count < max_uses)
+		`, redemption.CouponID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrExhausted
+		}
+
+		if perClientLimit != nil && redemption.ClientID != nil {
+			var used int64
+			if err := tx.Model(&models.CouponRedemption{}).
+				Where("coupon_id = ? AND client_id = ? AND voided_at IS NULL", redemption.CouponID, *redemption.ClientID).
+				Count(&used).Error; err != nil {
+				return err
+			}
+			if used >= int64(*perClientLimit) {
+				return domain.ErrClientLimit
+			}
+		}
+
+		return tx.Create(redemption).Error
+	})
+}
+
+func (r *CouponGormRepository) AttachAppointment(
+	ctx context.Context,
+	redemptionID uint,
+	appointmentID uint,
+	discountCents int64,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.CouponRedemption{}).
+		Where("id = ?", redemptionID).
+		Updates(map[string]any{
+			"appointment_id": appointmentID,
+			"discount_cents": discountCents,
+		}).Error
+}
+
+func (r *CouponGormRepository) Release(
+	ctx context.Context,
+	redemptionID uint,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redemption models.CouponRedemption
+		err := tx.Where("id = ?", redemptionID).First(&redemption).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&redemption).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE coupons SET uses_count = uses_count - 1
+			WHERE id = ? AND uses_count > 0
+		`, redemption.CouponID).Error
+	})
+}
+
+func (r *CouponGormRepository) VoidForAppointment(
+	ctx context.Context,
+	appointmentID uint,
+) error {
+	return voidCouponRedemptions(ctx, r.db, "appointment_id", appointmentID)
+}
+
+func (r *CouponGormRepository) VoidForOrder(
+	ctx context.Context,
+	orderID uint,
+) error {
+	return voidCouponRedemptions(ctx, r.db, "order_id", orderID)
+}
+
+// voidCouponRedemptions marca os usos ativos do agendamento ou pedido
+// (column) como anulados e devolve cada um ao seu cupom, num único
+// comando. db deve ser a transação da mudança que anulou a compra.
+func voidCouponRedemptions(ctx context.Context, db *gorm.DB, column string, id uint) error {
+	return db.WithContext(ctx).Exec(`
+		WITH voided AS (
+			UPDATE coupon_redemptions
+			SET voided_at = now()
+			WHERE `+column+` = ? AND voided_at IS NULL
+			RETURNING coupon_id
+		)
+		UPDATE coupons c
+		SET uses_count = GREATEST(c.uses_count - v.n, 0)
+		FROM (SELECT coupon_id, COUNT(*) AS n FROM voided GROUP BY coupon_id) v
+		WHERE c.id = v.coupon_id
+	`, id).Error
+}
+
+func (r *CouponGormRepository) ListRedemptions(
+	ctx context.Context,
+	barbershopID uint,
+	couponID uint,
+) ([]models.CouponRedemption, error) {
+	var redemptions []models.CouponRedemption
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND coupon_id = ?", barbershopID, couponID).
+		Order("created_at DESC, id DESC").
+		Find(&redemptions).Error
+	return redemptions, err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunInsert guarda o SQL e os argumentos do último INSERT montado.
+type dryRunInsert struct {
+	sql  string
+	vars []any
+}
+
+// column retorna o valor gravado na coluna e se ela está no INSERT.
+func (c *dryRunInsert) column(name string) (any, bool) {
+	open := strings.Index(c.sql, "(")
+	closeIdx := strings.Index(c.sql, ")")
+	if open < 0 || closeIdx < open {
+		return nil, false
+	}
+	for i, col := range strings.Split(c.sql[open+1:closeIdx], ",") {
+		if strings.Trim(strings.TrimSpace(col), `"`) == name && i < len(c.vars) {
+			return c.vars[i], true
+		}
+	}
+	return nil, false
+}
+
+// newDryRunDB abre o dialeto Postgres em DryRun: nada chega ao banco e o
+// INSERT montado pelo repositório fica em dryRunInsert. Roda sem
+// DATABASE_URL — cobre o SQL que o GORM gera a partir das tags do model.
+func newDryRunDB(t *testing.T) (*gorm.DB, *dryRunInsert) {
+	t.Helper()
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=dryrun dbname=dryrun sslmode=disable"}),
+		&gorm.Config{
+			DryRun:                 true,
+			DisableAutomaticPing:   true,
+			SkipDefaultTransaction: true,
+			Logger:                 logger.Default.LogMode(logger.Silent),
+		},
+	)
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	captured := &dryRunInsert{}
+	if err := db.Callback().Create().After("gorm:create").Register("test:capture_insert", func(tx *gorm.DB) {
+		captured.sql = tx.Statement.SQL.String()
+		captured.vars = tx.Statement.Vars
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return db, captured
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	domainProduct "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type InventoryGormRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryGormRepository(db *gorm.DB) *InventoryGormRepository {
+	return &InventoryGormRepository{db: db}
+}
+
+// WithTx amarra o razão à transação do chamador (fechamento do atendimento),
+// para que a baixa de estoque e o pedido sejam gravados juntos.
+func (r *InventoryGormRepository) WithTx(tx *gorm.DB) *InventoryGormRepository {
+	return &InventoryGormRepository{db: tx}
+}
+
+func (r *InventoryGormRepository) Record(
+	ctx context.Context,
+	movements []*models.InventoryMovement,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, m := range movements {
+			if err := applyInventoryMovement(ctx, tx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *InventoryGormRepository) Count(
+	ctx context.Context,
+	barbershopID uint,
+	lines []domain.CountLine,
+	userID *uint,
+	note string,
+) ([]models.InventoryMovement, error) {
+	var out []models.InventoryMovement
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, line := range lines {
+			// Trava a linha: vendas concorrentes esperam a contagem terminar,
+			// e a diferença é calculada sobre o saldo real.
+			var product models.Product
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "stock").
+				Where("id = ? AND barbershop_id = ?", line.ProductID, barbershopID).
+				First(&product).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainProduct.ErrProductNotFound
+			}
+			if err != nil {
+				return err
+			}
+
+			diff := line.Counted - product.Stock
+			if diff == 0 {
+				continue
+			}
+
+			m := &models.InventoryMovement{
+				BarbershopID: barbershopID,
+				ProductID:    line.ProductID,
+				Type:         domain.MovementAdjustment,
+				Quantity:     diff,
+				UserID:       userID,
+				Note:         note,
+			}
+			if err := applyInventoryMovement(ctx, tx, m); err != nil {
+				return err
+			}
+			out = append(out, *m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *InventoryGormRepository) ListByProduct(
+	ctx context.Context,
+	barbershopID uint,
+	productID uint,
+	beforeID uint,
+	limit int,
+) ([]models.InventoryMovement, error) {
+	q := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND product_id = ?", barbershopID, productID)
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+
+	var list []models.InventoryMovement
+	if err := q.Order("id DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *InventoryGormRepository) ListLowStockPending(
+	ctx context.Context,
+	limit int,
+) ([]domain.LowStockProduct, error) {
+	var rows []domain.LowStockProduct
+
+	err := r.db.WithContext(ctx).
+		Table("products").
+		Select("id AS product_id, barbershop_id, name, stock, low_stock_threshold AS threshold").
+		Where("active = TRUE").
+		Where("low_stock_threshold IS NOT NULL AND stock <= low_stock_threshold").
+		Where("low_stock_notified_at IS NULL").
+		Order("barbershop_id ASC, stock ASC, id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *InventoryGormRepository) MarkLowStockNotified(
+	ctx context.Context,
+	productIDs []uint,
+	at time.Time,
+) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id IN ?", productIDs).
+		UpdateColumn("low_stock_notified_at", at).Error
+}
+
+func (r *InventoryGormRepository) GetAlertRecipients(
+	ctx context.Context,
+	barbershopID uint,
+) (string, []string, error) {
+	var shop models.Barbershop
+	if err := r.db.WithContext(ctx).
+		Select("id", "name").
+		First(&shop, barbershopID).Error; err != nil {
+		return "", nil, err
+	}
+
+	var emails []string
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("barbershop_id = ? AND role = ? AND active = TRUE", barbershopID, models.UserRoleOwner).
+		Order("id ASC").
+		Pluck("email", &emails).Error
+	if err != nil {
+		return "", nil, err
+	}
+
+	return shop.Name, emails, nil
+}
+
+// applyInventoryMovement soma m.Quantity ao estoque do produto e grava o
+// movimento com o saldo resultante. O UPDATE condicional garante que o saldo
+// nunca fica negativo, mesmo com vendas concorrentes. Quando o estoque volta
+// acima do limite de alerta, o aviso de estoque baixo é rearmado.
+//
+// Deve rodar dentro da transação do chamador: é o único caminho que altera
+// products.stock depois do cadastro.
+func applyInventoryMovement(ctx context.Context, db *gorm.DB, m *models.InventoryMovement) error {
+	if m.Quantity == 0 {
+		return errors.New("invalid_quantity")
+	}
+
+	var row struct{ Stock int }
+	result := db.WithContext(ctx).Raw(`
+		UPDATE products
+		SET stock = stock + ?,
+		    low_stock_notified_at = CASE
+		      WHEN low_stock_threshold IS NOT NULL AND stock + ? > low_stock_threshold THEN NULL
+		      ELSE low_stock_notified_at
+		    END
+		WHERE id = ? AND barbershop_id = ? AND stock + ? >= 0
+		RETURNING stock
+	`, m.Quantity, m.Quantity, m.ProductID, m.BarbershopID, m.Quantity).Scan(&row)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := db.WithContext(ctx).
+			Model(&models.Product{}).
+			Where("id = ? AND barbershop_id = ?", m.ProductID, m.BarbershopID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return domainProduct.ErrProductNotFound
+		}
+		return domainProduct.ErrInsufficientStock
+	}
+
+	m.StockAfter = row.Stock
+	return db.WithContext(ctx).Create(m).Error
+}
+
+var _ domain.Repository = (*InventoryGormRepository)(nil)
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type LoyaltyGormRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyGormRepository(db *gorm.DB) *LoyaltyGormRepository {
+	return &LoyaltyGormRepository{db: db}
+}
+
+// WithTx devolve o repositório vinculado a uma transação existente.
+func (r *LoyaltyGormRepository) WithTx(tx *gorm.DB) domain.Repository {
+	return &LoyaltyGormRepository{db: tx}
+}
+
+// ======================================================
+// PROGRAM
+// ======================================================
+
+func (r *LoyaltyGormRepository) GetProgram(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.LoyaltyProgram, error) {
+	var p models.LoyaltyProgram
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *LoyaltyGormRepository) SaveProgram(
+	ctx context.Context,
+	p *models.LoyaltyProgram,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "barbershop_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"active",
+				"points_per_real",
+				"expiry_months",
+				"updated_at",
+			}),
+		}).
+		Create(p).
+		Error
+}
+
+// ======================================================
+// REWARDS
+// ======================================================
+
+func (r *LoyaltyGormRepository) ServiceExists(
+	ctx context.Context,
+	barbershopID uint,
+	serviceID uint,
+) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.BarbershopService{}).
+		Where("id = ? AND barbershop_id = ?", serviceID, barbershopID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *LoyaltyGormRepository) CreateReward(
+	ctx context.Context,
+	reward *models.LoyaltyReward,
+) error {
+	return r.db.WithContext(ctx).Create(reward).Error
+}
+
+func (r *LoyaltyGormRepository) UpdateReward(
+	ctx context.Context,
+	reward *models.LoyaltyReward,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LoyaltyReward{}).
+		Where("id = ? AND barbershop_id = ?", reward.ID, reward.BarbershopID).
+		Updates(map[string]any{
+			"name":           reward.Name,
+			"kind":           reward.Kind,
+			"points_cost":    reward.PointsCost,
+			"service_id":     reward.ServiceID,
+			"discount_cents": reward.DiscountCents,
+			"active":         reward.Active,
+		}).Error
+}
+
+func (r *LoyaltyGormRepository) GetReward(
+	ctx context.Context,
+	barbershopID uint,
+	rewardID uint,
+) (*models.LoyaltyReward, error) {
+	var reward models.LoyaltyReward
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", rewardID, barbershopID).
+		First(&reward).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+func (r *LoyaltyGormRepository) ListRewards(
+	ctx context.Context,
+	barbershopID uint,
+	onlyActive bool,
+) ([]models.LoyaltyReward, error) {
+	var rewards []models.LoyaltyReward
+
+	q := r.db.WithContext(ctx).Where("barbershop_id = ?", barbershopID)
+	if onlyActive {
+		q = q.Where("active = true")
+	}
+	err := q.Order("active DESC, points_cost ASC, id ASC").Find(&rewards).Error
+	return rewards, err
+}
+
+// ======================================================
+// LEDGER
+// ======================================================
+
+// Earn: os índices únicos parciais de earn por atendimento e por pedido
+// tornam o crédito idempotente.
+func (r *LoyaltyGormRepository) Earn(
+	ctx context.Context,
+	e *models.LoyaltyEntry,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(e).Error
+}
+
+// FindEarn trava o crédito até o fim da transação, serializando reversões
+// concorrentes do mesmo atendimento ou pedido.
+func (r *LoyaltyGormRepository) FindEarn(
+	ctx context.Context,
+	appointmentID *uint,
+	orderID *uint,
+) (*models.LoyaltyEntry, int, error) {
+	source, id := "appointment_id", appointmentID
+	if id == nil {
+		source, id = "order_id", orderID
+	}
+	if id == nil {
+		return nil, 0, nil
+	}
+
+	var earn models.LoyaltyEntry
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(source+" = ? AND kind = ?", *id, models.LoyaltyEntryEarn).
+		First(&earn).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var reversed int
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(-SUM(points), 0)
+		FROM loyalty_entries
+		WHERE `+source+` = ? AND kind = ?
+	`, *id, models.LoyaltyEntryReverse).Scan(&reversed).Error; err != nil {
+		return nil, 0, err
+	}
+	return &earn, reversed, nil
+}
+
+// Debit: os lotes ficam travados (FOR UPDATE) até o fim da transação, então
+// duas trocas simultâneas do mesmo cliente não gastam o mesmo saldo.
+func (r *LoyaltyGormRepository) Debit(
+	ctx context.Context,
+	e *models.LoyaltyEntry,
+	now time.Time,
+	fromLotID *uint,
+	partial bool,
+) error {
+	want := -e.Points
+	if want <= 0 {
+		e.Points = 0
+		return nil
+	}
+
+	var preferred uint
+	if fromLotID != nil {
+		preferred = *fromLotID
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var lots []models.LoyaltyEntry
+		if err := tx.Raw(`
+			SELECT *
+			FROM loyalty_entries
+			WHERE barbershop_id = ?
+			  AND client_id = ?
+			  AND kind = ?
+			  AND remaining_points > 0
+			  AND (expires_at IS NULL OR expires_at > ?)
+			ORDER BY (id = ?) DESC, expires_at ASC NULLS LAST, id ASC
+			FOR UPDATE
+		`, e.BarbershopID, e.ClientID, models.LoyaltyEntryEarn, now, preferred).
+			Scan(&lots).Error; err != nil {
+			return err
+		}
+
+		available := 0
+		for _, lot := range lots {
+			available += lot.RemainingPoints
+		}
+		if available < want {
+			if !partial {
+				return domain.ErrInsufficientPoints
+			}
+			want = available
+		}
+		if want == 0 {
+			e.Points = 0
+			return nil
+		}
+
+		left := want
+		for _, lot := range lots {
+			if left == 0 {
+				break
+			}
+			take := lot.RemainingPoints
+			if take > left {
+				take = left
+			}
+			if err := tx.Exec(`
+				UPDATE loyalty_entries
+				SET remaining_points = remaining_points - ?
+				WHERE id = ?
+			`, take, lot.ID).Error; err != nil {
+				return err
+			}
+			left -= take
+		}
+
+		e.Points = -want
+		e.RemainingPoints = 0
+		return tx.Create(e).Error
+	})
+}
+
+func (r *LoyaltyGormRepository) GetAccount(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	now time.Time,
+	horizon time.Time,
+) (*domain.Account, error) {
+	var row struct {
+		Balance        int
+		ExpiringPoints int
+		NextExpiresAt  *time.Time
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COALESCE(SUM(remaining_points), 0)                                 AS balance,
+			COALESCE(SUM(remaining_points) FILTER (WHERE expires_at <= ?), 0) AS expiring_points,
+			MIN(expires_at)                                                    AS next_expires_at
+		FROM loyalty_entries
+		WHERE barbershop_id = ?
+		  AND client_id = ?
+		  AND kind = ?
+		  AND remaining_points > 0
+		  AND (expires_at IS NULL OR expires_at > ?)
+	`, horizon, barbershopID, clientID, models.LoyaltyEntryEarn, now).
+		Scan(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return &domain.Account{
+		Balance:        row.Balance,
+		ExpiringPoints: row.ExpiringPoints,
+		NextExpiresAt:  row.NextExpiresAt,
+	}, nil
+}
+
+func (r *LoyaltyGormRepository) ListEntries(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	limit int,
+) ([]models.LoyaltyEntry, error) {
+	var entries []models.LoyaltyEntry
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// ExpireLots: SKIP LOCKED deixa para a próxima rodada os lotes que uma troca
+// está consumindo no momento.
+func (r *LoyaltyGormRepository) ExpireLots(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		WITH due AS (
+			SELECT id, barbershop_id, client_id, remaining_points
+			FROM loyalty_entries
+			WHERE kind = ?
+			  AND remaining_points > 0
+			  AND expires_at <= ?
+			FOR UPDATE SKIP LOCKED
+		), zeroed AS (
+			UPDATE loyalty_entries e
+			SET remaining_points = 0
+			FROM due
+			WHERE e.id = due.id
+		)
+		INSERT INTO loyalty_entries (barbershop_id, client_id, kind, points, created_at)
+		SELECT barbershop_id, client_id, ?, -remaining_points, ?
+		FROM due
+	`, models.LoyaltyEntryEarn, now, models.LoyaltyEntryExpire, now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+// Testes de repositório para a configuração do programa de fidelidade.
+//
+// O teste de ida e volta requer banco PostgreSQL real via DATABASE_URL —
+// skipped automaticamente sem ele. Helpers de setup em cancel_subscription_test.go.
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// TestSaveProgram_InsertsZeroExpiry: o INSERT leva expiry_months = 0 (pontos
+// não expiram) em vez de deixar o default do banco (12) valer.
+func TestSaveProgram_InsertsZeroExpiry(t *testing.T) {
+	db, insert := newDryRunDB(t)
+
+	err := NewLoyaltyGormRepository(db).SaveProgram(context.Background(), &models.LoyaltyProgram{
+		BarbershopID:  1,
+		Active:        true,
+		PointsPerReal: 1,
+		ExpiryMonths:  0,
+	})
+	if err != nil {
+		t.Fatalf("SaveProgram: %v", err)
+	}
+
+	got, ok := insert.column("expiry_months")
+	if !ok {
+		t.Fatalf("coluna expiry_months fora do INSERT: %s", insert.sql)
+	}
+	if got != 0 {
+		t.Errorf("expiry_months gravado = %v, esperado 0", got)
+	}
+}
+
+// TestSaveProgram_ZeroExpiryRoundTrip: desligar a expiração grava 0 — na
+// criação e ao reconfigurar um programa que expirava em 12 meses.
+func TestSaveProgram_ZeroExpiryRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	outerErr := db.Transaction(func(tx *gorm.DB) error {
+		bs := seedBarbershop(t, tx)
+		repo := NewLoyaltyGormRepository(tx)
+
+		for _, months := range []int{0, 12, 0} {
+			err := repo.SaveProgram(ctx, &models.LoyaltyProgram{
+				BarbershopID:  bs.ID,
+				Active:        true,
+				PointsPerReal: 1,
+				ExpiryMonths:  months,
+			})
+			if err != nil {
+				t.Errorf("SaveProgram(expiry=%d): %v", months, err)
+				return errors.New("rollback — falha no act")
+			}
+
+			got, err := repo.GetProgram(ctx, bs.ID)
+			if err != nil || got == nil {
+				t.Fatalf("GetProgram: program=%v err=%v", got, err)
+			}
+			if got.ExpiryMonths != months {
+				t.Errorf("expiry_months lido = %d, esperado %d", got.ExpiryMonths, months)
+			}
+		}
+
+		return errors.New("rollback intencional")
+	})
+
+	if outerErr != nil && outerErr.Error() != "rollback intencional" {
+		t.Errorf("transação de teste falhou inesperadamente: %v", outerErr)
+	}
+}
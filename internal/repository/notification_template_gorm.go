@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type NotificationTemplateGormRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationTemplateGormRepository(db *gorm.DB) *NotificationTemplateGormRepository {
+	return &NotificationTemplateGormRepository{db: db}
+}
+
+func (r *NotificationTemplateGormRepository) Find(
+	ctx context.Context,
+	barbershopID uint,
+	kind string,
+	channel string,
+) (*models.NotificationTemplate, error) {
+	var t models.NotificationTemplate
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND kind = ? AND channel = ?", barbershopID, kind, channel).
+		First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *NotificationTemplateGormRepository) List(
+	ctx context.Context,
+	barbershopID uint,
+) ([]models.NotificationTemplate, error) {
+	var list []models.NotificationTemplate
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("kind ASC, channel ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *NotificationTemplateGormRepository) Save(
+	ctx context.Context,
+	t *models.NotificationTemplate,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "barbershop_id"}, {Name: "kind"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"subject",
+				"body",
+				"updated_at",
+			}),
+		}).
+		Create(t).
+		Error
+}
+
+func (r *NotificationTemplateGormRepository) Delete(
+	ctx context.Context,
+	barbershopID uint,
+	kind string,
+	channel string,
+) error {
+	return r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND kind = ? AND channel = ?", barbershopID, kind, channel).
+		Delete(&models.NotificationTemplate{}).
+		Error
+}
+
+func (r *NotificationTemplateGormRepository) GetBarbershop(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).First(&shop, barbershopID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
 	"github.com/BruksfildServices01/barber-scheduler/internal/dto"
@@ -48,6 +49,7 @@ func (r *OrderGormRepository) Create(
 		SubtotalAmount: o.SubtotalAmount,
 		DiscountAmount: o.DiscountAmount,
 		TotalAmount:    o.TotalAmount,
+		CouponID:       o.CouponID,
 	}
 
 	if err := db.Create(orderModel).Error; err != nil {
@@ -130,7 +132,8 @@ func (r *OrderGormRepository) ListAdminByBarbershop(
 	barbershopID uint,
 	params ListOrdersAdminParams,
 ) ([]dto.OrderListItemDTO, int, error) {
-	// Only show paid orders (includes closure-linked orders from legacy pending data).
+	// Only show paid orders (includes closure-linked orders from legacy pending data
+	// and paid orders cancelled later — their returns always carry items).
 	baseWhere := `
 		orders.barbershop_id = ?
 		AND (
@@ -139,6 +142,11 @@ func (r *OrderGormRepository) ListAdminByBarbershop(
 		        SELECT 1 FROM appointment_closures ac
 		        WHERE ac.additional_order_id = orders.id
 		    )
+		    OR EXISTS (
+		        SELECT 1 FROM order_returns r
+		        JOIN order_return_items ri ON ri.order_return_id = r.id
+		        WHERE r.order_id = orders.id
+		    )
 		)
 	`
 
@@ -187,6 +195,11 @@ func (r *OrderGormRepository) ListAdminByBarbershop(
 		  AND (
 		      o.status = 'paid'
 		      OR ac.id IS NOT NULL
+		      OR EXISTS (
+		          SELECT 1 FROM order_returns r
+		          JOIN order_return_items ri ON ri.order_return_id = r.id
+		          WHERE r.order_id = o.id
+		      )
 		  )
 		GROUP BY o.id, c.name
 		ORDER BY `+orderBy+`
@@ -306,6 +319,7 @@ func (r *OrderGormRepository) GetRichByID(
 			Quantity:            it.Quantity,
 			UnitPrice:           it.UnitPrice,
 			LineTotal:           it.LineTotal,
+			ReturnedQuantity:    it.ReturnedQuantity,
 		})
 	}
 
@@ -328,6 +342,189 @@ func (r *OrderGormRepository) GetRichByID(
 	}, nil
 }
 
+// GetForUpdate carrega o pedido com os itens travando a linha do pedido:
+// cancelamentos e devoluções simultâneas do mesmo pedido ficam em fila.
+func (r *OrderGormRepository) GetForUpdate(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*domain.Order, error) {
+	var m models.Order
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&m).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", m.ID).
+		Order("id ASC").
+		Find(&m.Items).Error; err != nil {
+		return nil, err
+	}
+
+	return mapOrderToDomain(&m), nil
+}
+
+func (r *OrderGormRepository) UpdateStatus(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+	status domain.OrderStatus,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Update("status", models.OrderStatus(status)).
+		Error
+}
+
+// HasPendingPayment indica cobrança em aberto para o pedido (sozinho ou junto
+// de um agendamento) — o webhook ainda pode confirmá-la.
+func (r *OrderGormRepository) HasPendingPayment(
+	ctx context.Context,
+	barbershopID uint,
+	orderID uint,
+) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Payment{}).
+		Where("barbershop_id = ? AND (order_id = ? OR bundled_order_id = ?) AND status = 'pending'",
+			barbershopID, orderID, orderID).
+		Count(&count).
+		Error
+	return count > 0, err
+}
+
+// FindRefundablePayment retorna o pagamento que cobriu o pedido e ainda tem
+// saldo a estornar, ou nil quando o pedido foi pago por fora (fechamento).
+func (r *OrderGormRepository) FindRefundablePayment(
+	ctx context.Context,
+	barbershopID uint,
+	orderID uint,
+) (*models.Payment, error) {
+	var p models.Payment
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND (order_id = ? OR bundled_order_id = ?)", barbershopID, orderID, orderID).
+		Where("status IN ('paid', 'partially_refunded')").
+		Order("id DESC").
+		First(&p).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SumReturnedAmount soma o que já foi devolvido ao cliente nas devoluções
+// anteriores do pedido.
+func (r *OrderGormRepository) SumReturnedAmount(
+	ctx context.Context,
+	orderID uint,
+) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&models.OrderReturn{}).
+		Where("order_id = ?", orderID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&total).
+		Error
+	return total, err
+}
+
+// CreateReturn grava a devolução com os itens.
+func (r *OrderGormRepository) CreateReturn(
+	ctx context.Context,
+	ret *models.OrderReturn,
+) error {
+	return r.db.WithContext(ctx).Create(ret).Error
+}
+
+// AddReturnedQuantity soma unidades devolvidas à linha do pedido, sem
+// ultrapassar a quantidade vendida.
+func (r *OrderGormRepository) AddReturnedQuantity(
+	ctx context.Context,
+	orderItemID uint,
+	quantity int,
+) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Where("id = ? AND returned_quantity + ? <= quantity", orderItemID, quantity).
+		Update("returned_quantity", gorm.Expr("returned_quantity + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrReturnExceedsQuantity
+	}
+	return nil
+}
+
+func (r *OrderGormRepository) UpdateReturnRefund(
+	ctx context.Context,
+	returnID uint,
+	status string,
+	refundedAt *time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderReturn{}).
+		Where("id = ?", returnID).
+		Updates(map[string]any{
+			"refund_status": status,
+			"refunded_at":   refundedAt,
+		}).
+		Error
+}
+
+func (r *OrderGormRepository) ListReturns(
+	ctx context.Context,
+	barbershopID uint,
+	orderID uint,
+) ([]models.OrderReturn, error) {
+	var rows []models.OrderReturn
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("barbershop_id = ? AND order_id = ?", barbershopID, orderID).
+		Order("id ASC").
+		Find(&rows).
+		Error
+	return rows, err
+}
+
+func (r *OrderGormRepository) GetReturn(
+	ctx context.Context,
+	barbershopID uint,
+	orderID uint,
+	returnID uint,
+) (*models.OrderReturn, error) {
+	var ret models.OrderReturn
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("id = ? AND barbershop_id = ? AND order_id = ?", returnID, barbershopID, orderID).
+		First(&ret).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
 func mapOrderToDomain(m *models.Order) *domain.Order {
 	items := make([]domain.OrderItem, 0, len(m.Items))
 
@@ -340,6 +537,7 @@ func mapOrderToDomain(m *models.Order) *domain.Order {
 			Quantity:            i.Quantity,
 			UnitPrice:           i.UnitPrice,
 			LineTotal:           i.LineTotal,
+			ReturnedQuantity:    i.ReturnedQuantity,
 		})
 	}
 
@@ -352,6 +550,7 @@ func mapOrderToDomain(m *models.Order) *domain.Order {
 		SubtotalAmount: m.SubtotalAmount,
 		DiscountAmount: m.DiscountAmount,
 		TotalAmount:    m.TotalAmount,
+		CouponID:       m.CouponID,
 		Items:          items,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
@@ -8,8 +8,10 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	domainInventory "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 )
 
 type PaymentGormRepository struct {
@@ -369,6 +371,30 @@ func (r *PaymentGormTxRepository) GetByTxIDForUpdate(
 	return &p, nil
 }
 
+func (r *PaymentGormTxRepository) GetByIDForUpdate(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*models.Payment, error) {
+
+	var p models.Payment
+
+	err := r.tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&p).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
 func (r *PaymentGormTxRepository) GetAppointmentForUpdate(
 	ctx context.Context,
 	barbershopID uint,
@@ -439,6 +465,20 @@ func (r *PaymentGormTxRepository) ListExpiredPendingForUpdate(
 	return payments, err
 }
 
+func (r *PaymentGormTxRepository) VoidAppointmentCouponsTx(
+	ctx context.Context,
+	appointmentID uint,
+) error {
+	return voidCouponRedemptions(ctx, r.tx, "appointment_id", appointmentID)
+}
+
+func (r *PaymentGormTxRepository) VoidOrderCouponsTx(
+	ctx context.Context,
+	orderID uint,
+) error {
+	return voidCouponRedemptions(ctx, r.tx, "order_id", orderID)
+}
+
 func (r *PaymentGormTxRepository) Create(
 	ctx context.Context,
 	p *models.Payment,
@@ -600,7 +640,7 @@ func (r *PaymentGormTxRepository) ActivateSubscriptionTx(
 	id uint,
 	periodStart, periodEnd time.Time,
 ) error {
-	return r.tx.WithContext(ctx).
+	if err := r.tx.WithContext(ctx).
 		Model(&models.Subscription{}).
 		Where("id = ? AND status = ?", id, "pending_payment").
 		Updates(map[string]any{
@@ -609,7 +649,79 @@ func (r *PaymentGormTxRepository) ActivateSubscriptionTx(
 			"current_period_end":      periodEnd,
 			"cuts_used_in_period":     0,
 			"cuts_reserved_in_period": 0,
-		}).Error
+		}).Error; err != nil {
+		return err
+	}
+	return r.tx.WithContext(ctx).Exec(expireSupersededPastDueSQL, id).Error
+}
+
+// RenewSubscriptionTx aplica a renovação paga. Ainda no período, o próximo
+// fica agendado em renewed_period_end e a virada acontece no job de
+// expiração; em carência (ou com o período já encerrado), a assinatura volta
+// a ativa com um período novo a partir do pagamento.
+func (r *PaymentGormTxRepository) RenewSubscriptionTx(
+	ctx context.Context,
+	sub *models.Subscription,
+	durationDays int,
+	paidAt time.Time,
+) error {
+	updates := map[string]any{
+		"renewal_attempts": 0,
+		"next_renewal_at":  nil,
+		"grace_until":      nil,
+	}
+	if sub.Status == "active" && sub.CurrentPeriodEnd.After(paidAt) {
+		updates["renewed_period_end"] = sub.CurrentPeriodEnd.AddDate(0, 0, durationDays)
+	} else {
+		updates["status"] = "active"
+		updates["current_period_start"] = paidAt
+		updates["current_period_end"] = paidAt.AddDate(0, 0, durationDays)
+		updates["cuts_used_in_period"] = 0
+		updates["renewed_period_end"] = nil
+	}
+	return r.tx.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("id = ? AND status IN ?", sub.ID, []string{"active", "past_due"}).
+		Updates(updates).Error
+}
+
+func (r *PaymentGormTxRepository) GetClientPackageForUpdate(
+	ctx context.Context,
+	id uint,
+) (*models.ClientPackage, error) {
+	var cp models.ClientPackage
+	err := r.tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&cp).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// ActivateClientPackageTx ativa a compra com validade a partir de purchasedAt.
+func (r *PaymentGormTxRepository) ActivateClientPackageTx(
+	ctx context.Context,
+	id uint,
+	purchasedAt time.Time,
+) error {
+	return r.tx.WithContext(ctx).Exec(`
+		UPDATE client_packages
+		SET status       = 'active',
+		    purchased_at = ?,
+		    expires_at   = ?::timestamptz + make_interval(days => validity_days)
+		WHERE id = ?
+		  AND status = 'pending_payment'
+	`, purchasedAt, purchasedAt, id).Error
+}
+
+func (r *PaymentGormTxRepository) Context(ctx context.Context) context.Context {
+	return outbox.ContextWithTx(ctx, r.tx)
 }
 
 func (r *PaymentGormTxRepository) Commit() error {
@@ -677,6 +789,29 @@ func (r *PaymentGormTxRepository) UpdatePaymentTx(
 		Error
 }
 
+func (r *PaymentGormTxRepository) ApplyRefundTx(
+	ctx context.Context,
+	p *models.Payment,
+	refund *models.PaymentRefund,
+) error {
+
+	err := r.tx.WithContext(ctx).
+		Model(&models.Payment{}).
+		Where("id = ? AND barbershop_id = ?", p.ID, p.BarbershopID).
+		Updates(map[string]any{
+			"status":          p.Status,
+			"refunded_amount": p.RefundedAmount,
+			"refunded_at":     p.RefundedAt,
+			"updated_at":      time.Now().UTC(),
+		}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.tx.WithContext(ctx).Create(refund).Error
+}
+
 func (r *PaymentGormTxRepository) ListOrderItems(
 	ctx context.Context,
 	barbershopID uint,
@@ -710,25 +845,19 @@ func (r *PaymentGormTxRepository) DecreaseProductStock(
 	barbershopID uint,
 	productID uint,
 	quantity int,
+	orderID uint,
 ) error {
 	if quantity <= 0 || productID == 0 {
 		return nil
 	}
 
-	result := r.tx.WithContext(ctx).
-		Model(&models.Product{}).
-		Where("id = ? AND barbershop_id = ? AND stock >= ?", productID, barbershopID, quantity).
-		Update("stock", gorm.Expr("stock - ?", quantity))
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return errors.New("insufficient_stock")
-	}
-
-	return nil
+	return applyInventoryMovement(ctx, r.tx, &models.InventoryMovement{
+		BarbershopID: barbershopID,
+		ProductID:    productID,
+		Type:         domainInventory.MovementSale,
+		Quantity:     -quantity,
+		OrderID:      &orderID,
+	})
 }
 
 func (r *PaymentGormRepository) BeginTx(
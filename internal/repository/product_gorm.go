@@ -7,6 +7,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
@@ -31,7 +32,25 @@ func (r *ProductGormRepository) Create(
 ) error {
 	model := mapProductToModel(p)
 
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+	// O estoque inicial entra pelo razão, como qualquer outra alteração.
+	model.Stock = 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		if p.Stock == 0 {
+			return nil
+		}
+		return applyInventoryMovement(ctx, tx, &models.InventoryMovement{
+			BarbershopID: model.BarbershopID,
+			ProductID:    model.ID,
+			Type:         inventory.MovementAdjustment,
+			Quantity:     p.Stock,
+			Note:         "Estoque inicial",
+		})
+	})
+	if err != nil {
 		return err
 	}
 
@@ -39,6 +58,8 @@ func (r *ProductGormRepository) Create(
 	return nil
 }
 
+// Update não altera o estoque: mudanças de saldo passam pelo razão
+// (InventoryGormRepository). Trocar o limite de alerta rearma o aviso.
 func (r *ProductGormRepository) Update(
 	ctx context.Context,
 	p *domain.Product,
@@ -53,9 +74,14 @@ func (r *ProductGormRepository) Update(
 			"description":    model.Description,
 			"category":       model.Category,
 			"price":          model.Price,
-			"stock":          model.Stock,
 			"active":         model.Active,
 			"online_visible": model.OnlineVisible,
+
+			"low_stock_threshold": model.LowStockThreshold,
+			"low_stock_notified_at": gorm.Expr(
+				"CASE WHEN low_stock_threshold IS DISTINCT FROM ? THEN NULL ELSE low_stock_notified_at END",
+				model.LowStockThreshold,
+			),
 		}).
 		Error
 }
@@ -71,6 +97,27 @@ func (r *ProductGormRepository) Delete(
 		Error
 }
 
+// RestoreStock devolve ao estoque unidades de um pedido cancelado ou
+// devolvido, registrando o movimento "return" no razão.
+func (r *ProductGormRepository) RestoreStock(
+	ctx context.Context,
+	barbershopID uint,
+	productID uint,
+	quantity int,
+	orderID uint,
+	userID *uint,
+) error {
+	return applyInventoryMovement(ctx, r.db, &models.InventoryMovement{
+		BarbershopID: barbershopID,
+		ProductID:    productID,
+		Type:         inventory.MovementReturn,
+		Quantity:     quantity,
+		OrderID:      &orderID,
+		UserID:       userID,
+		Note:         "Devolução do pedido",
+	})
+}
+
 func (r *ProductGormRepository) GetByID(
 	ctx context.Context,
 	barbershopID uint,
@@ -164,39 +211,6 @@ func (r *ProductGormRepository) ListPublicProducts(
 	return result, nil
 }
 
-// DecreaseStock decrementa estoque de forma atômica e segura.
-// Requer que o repo esteja com a TX correta (use r.WithTx(tx) no usecase).
-func (r *ProductGormRepository) DecreaseStock(
-	ctx context.Context,
-	barbershopID uint,
-	productID uint,
-	quantity int,
-) error {
-	if quantity <= 0 {
-		return errors.New("invalid_quantity")
-	}
-
-	result := r.db.WithContext(ctx).
-		Model(&models.Product{}).
-		Where(
-			"id = ? AND barbershop_id = ? AND stock >= ?",
-			productID,
-			barbershopID,
-			quantity,
-		).
-		Update("stock", gorm.Expr("stock - ?", quantity))
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return domain.ErrInsufficientStock
-	}
-
-	return nil
-}
-
 func mapProductToDomain(m *models.Product) *domain.Product {
 	imageURL := ""
 	if m.ImageURL != nil {
@@ -213,6 +227,8 @@ func mapProductToDomain(m *models.Product) *domain.Product {
 		Stock:         m.Stock,
 		Active:        m.Active,
 		OnlineVisible: m.OnlineVisible,
+
+		LowStockThreshold: m.LowStockThreshold,
 	}
 }
 
@@ -227,6 +243,8 @@ func mapProductToModel(p *domain.Product) *models.Product {
 		Stock:         p.Stock,
 		Active:        p.Active,
 		OnlineVisible: p.OnlineVisible,
+
+		LowStockThreshold: p.LowStockThreshold,
 	}
 }
 
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ReportGormRepository struct {
+	db *gorm.DB
+}
+
+func NewReportGormRepository(db *gorm.DB) *ReportGormRepository {
+	return &ReportGormRepository{db: db}
+}
+
+// ======================================================
+// SUBSCRIPTIONS
+// ======================================================
+
+func (r *ReportGormRepository) ListSubscriptions(
+	ctx context.Context,
+	barbershopID uint,
+) ([]models.ReportSubscription, error) {
+	var list []models.ReportSubscription
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *ReportGormRepository) GetSubscription(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (*models.ReportSubscription, error) {
+	var s models.ReportSubscription
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ReportGormRepository) SaveSubscription(
+	ctx context.Context,
+	s *models.ReportSubscription,
+) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
+
+func (r *ReportGormRepository) DeleteSubscription(
+	ctx context.Context,
+	barbershopID uint,
+	id uint,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", id, barbershopID).
+		Delete(&models.ReportSubscription{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *ReportGormRepository) DueSubscriptions(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]models.ReportSubscription, error) {
+	var list []models.ReportSubscription
+
+	err := r.db.WithContext(ctx).
+		Where("active AND next_run_at <= ?", now).
+		Order("next_run_at ASC, id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *ReportGormRepository) AdvanceSubscription(
+	ctx context.Context,
+	id uint,
+	nextRunAt time.Time,
+	lastSentAt *time.Time,
+) error {
+	updates := map[string]any{
+		"next_run_at": nextRunAt,
+		"updated_at":  time.Now().UTC(),
+	}
+	if lastSentAt != nil {
+		updates["last_sent_at"] = *lastSentAt
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&models.ReportSubscription{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ======================================================
+// DELIVERIES
+// ======================================================
+
+func (r *ReportGormRepository) RecordDelivery(
+	ctx context.Context,
+	d *models.ReportDelivery,
+) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *ReportGormRepository) ListDeliveries(
+	ctx context.Context,
+	barbershopID uint,
+	subscriptionID *uint,
+	limit int,
+	offset int,
+) ([]models.ReportDelivery, int64, error) {
+	base := func() *gorm.DB {
+		q := r.db.WithContext(ctx).
+			Model(&models.ReportDelivery{}).
+			Where("barbershop_id = ?", barbershopID)
+		if subscriptionID != nil {
+			q = q.Where("subscription_id = ?", *subscriptionID)
+		}
+		return q
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []models.ReportDelivery
+	err := base().Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+func (r *ReportGormRepository) GetBarbershop(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).First(&shop, barbershopID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
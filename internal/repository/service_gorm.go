@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
@@ -203,6 +204,123 @@ func mapServiceToDomain(m *models.BarbershopService) *domain.Service {
 	}
 }
 
+// ======================================================
+// PER-BARBER CATALOG
+// ======================================================
+
+func (r *ServiceGormRepository) BarberExists(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND barbershop_id = ?", barberID, barbershopID).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
+
+func (r *ServiceGormRepository) GetBarberOverride(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	serviceID uint,
+) (*domain.BarberOverride, error) {
+	var m models.BarberServiceOverride
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ? AND service_id = ?", barbershopID, barberID, serviceID).
+		First(&m).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mapBarberOverrideToDomain(&m), nil
+}
+
+func (r *ServiceGormRepository) ListBarberOverrides(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+) ([]*domain.BarberOverride, error) {
+	var rows []models.BarberServiceOverride
+
+	if err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ?", barbershopID, barberID).
+		Find(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.BarberOverride, 0, len(rows))
+	for i := range rows {
+		result = append(result, mapBarberOverrideToDomain(&rows[i]))
+	}
+
+	return result, nil
+}
+
+func (r *ServiceGormRepository) UpsertBarberOverride(
+	ctx context.Context,
+	o *domain.BarberOverride,
+) error {
+	row := models.BarberServiceOverride{
+		BarbershopID: o.BarbershopID,
+		BarberID:     o.BarberID,
+		ServiceID:    o.ServiceID,
+		Offered:      o.Offered,
+		Price:        o.Price,
+		DurationMin:  o.DurationMin,
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "barber_id"},
+				{Name: "service_id"},
+			},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"offered",
+				"price",
+				"duration_min",
+				"updated_at",
+			}),
+		}).
+		Create(&row).
+		Error
+}
+
+func (r *ServiceGormRepository) DeleteBarberOverride(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	serviceID uint,
+) error {
+	return r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ? AND service_id = ?", barbershopID, barberID, serviceID).
+		Delete(&models.BarberServiceOverride{}).
+		Error
+}
+
+func mapBarberOverrideToDomain(m *models.BarberServiceOverride) *domain.BarberOverride {
+	return &domain.BarberOverride{
+		BarbershopID: m.BarbershopID,
+		BarberID:     m.BarberID,
+		ServiceID:    m.ServiceID,
+		Offered:      m.Offered,
+		Price:        m.Price,
+		DurationMin:  m.DurationMin,
+	}
+}
+
 func mapServiceToModel(s *domain.Service) *models.BarbershopService {
 	return &models.BarbershopService{
 		ID:           s.ID,
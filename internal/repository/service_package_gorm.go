@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ServicePackageGormRepository struct {
+	db *gorm.DB
+}
+
+func NewServicePackageGormRepository(db *gorm.DB) *ServicePackageGormRepository {
+	return &ServicePackageGormRepository{db: db}
+}
+
+// WithTx devolve o repositório vinculado a uma transação existente.
+func (r *ServicePackageGormRepository) WithTx(tx *gorm.DB) domain.Repository {
+	return &ServicePackageGormRepository{db: tx}
+}
+
+func (r *ServicePackageGormRepository) CountServices(
+	ctx context.Context,
+	barbershopID uint,
+	serviceIDs []uint,
+) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.BarbershopService{}).
+		Where("barbershop_id = ? AND id IN ?", barbershopID, serviceIDs).
+		Count(&count).Error
+	return count, err
+}
+
+// ======================================================
+// PACKAGES
+// ======================================================
+
+func (r *ServicePackageGormRepository) CreatePackage(
+	ctx context.Context,
+	pkg *models.ServicePackage,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pkg).Error; err != nil {
+			return err
+		}
+		return insertPackageServices(tx, pkg.ID, pkg.ServiceIDs)
+	})
+}
+
+func (r *ServicePackageGormRepository) UpdatePackage(
+	ctx context.Context,
+	pkg *models.ServicePackage,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ServicePackage{}).
+			Where("id = ? AND barbershop_id = ?", pkg.ID, pkg.BarbershopID).
+			Updates(map[string]any{
+				"name":          pkg.Name,
+				"price_cents":   pkg.PriceCents,
+				"credits":       pkg.Credits,
+				"validity_days": pkg.ValidityDays,
+				"active":        pkg.Active,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM service_package_services WHERE package_id = ?`, pkg.ID).Error; err != nil {
+			return err
+		}
+		return insertPackageServices(tx, pkg.ID, pkg.ServiceIDs)
+	})
+}
+
+func insertPackageServices(tx *gorm.DB, packageID uint, serviceIDs []uint) error {
+	for _, serviceID := range serviceIDs {
+		if err := tx.Exec(
+			`INSERT INTO service_package_services (package_id, service_id) VALUES (?, ?)`,
+			packageID, serviceID,
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ServicePackageGormRepository) GetPackage(
+	ctx context.Context,
+	barbershopID uint,
+	packageID uint,
+) (*models.ServicePackage, error) {
+	var pkg models.ServicePackage
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", packageID, barbershopID).
+		First(&pkg).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPackageServices(ctx, []*models.ServicePackage{&pkg}); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (r *ServicePackageGormRepository) ListPackages(
+	ctx context.Context,
+	barbershopID uint,
+	onlyActive bool,
+) ([]models.ServicePackage, error) {
+	var pkgs []models.ServicePackage
+
+	q := r.db.WithContext(ctx).Where("barbershop_id = ?", barbershopID)
+	if onlyActive {
+		q = q.Where("active = true")
+	}
+	if err := q.Order("name ASC").Find(&pkgs).Error; err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*models.ServicePackage, 0, len(pkgs))
+	for i := range pkgs {
+		ptrs = append(ptrs, &pkgs[i])
+	}
+	if err := r.loadPackageServices(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+func (r *ServicePackageGormRepository) loadPackageServices(
+	ctx context.Context,
+	pkgs []*models.ServicePackage,
+) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint]*models.ServicePackage, len(pkgs))
+	ids := make([]uint, 0, len(pkgs))
+	for _, p := range pkgs {
+		p.ServiceIDs = []uint{}
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+
+	var rows []struct {
+		PackageID uint
+		ServiceID uint
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT package_id, service_id
+		FROM service_package_services
+		WHERE package_id IN ?
+		ORDER BY service_id
+	`, ids).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if p := byID[row.PackageID]; p != nil {
+			p.ServiceIDs = append(p.ServiceIDs, row.ServiceID)
+		}
+	}
+	return nil
+}
+
+// ======================================================
+// CLIENT PACKAGES
+// ======================================================
+
+func (r *ServicePackageGormRepository) FindOrCreateClient(
+	ctx context.Context,
+	barbershopID uint,
+	name string,
+	phone string,
+) (*models.Client, error) {
+	phone = strings.Join(strings.Fields(phone), "")
+
+	var client models.Client
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND phone = ?", barbershopID, phone).
+		First(&client).Error
+	if err == nil {
+		return &client, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	client = models.Client{
+		BarbershopID: &barbershopID,
+		Name:         name,
+		Phone:        phone,
+	}
+	if err := r.db.WithContext(ctx).Create(&client).Error; err != nil {
+		if !strings.Contains(strings.ToLower(err.Error()), "uq_clients_barbershop_phone") {
+			return nil, err
+		}
+		// Corrida: outra request criou o cliente primeiro — usa o existente.
+		if err := r.db.WithContext(ctx).
+			Where("barbershop_id = ? AND phone = ?", barbershopID, phone).
+			First(&client).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &client, nil
+}
+
+func (r *ServicePackageGormRepository) CreateClientPackage(
+	ctx context.Context,
+	cp *models.ClientPackage,
+) error {
+	return r.db.WithContext(ctx).Create(cp).Error
+}
+
+func (r *ServicePackageGormRepository) GetClientPackage(
+	ctx context.Context,
+	barbershopID uint,
+	clientPackageID uint,
+) (*models.ClientPackage, error) {
+	var cp models.ClientPackage
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", clientPackageID, barbershopID).
+		First(&cp).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (r *ServicePackageGormRepository) ActivateClientPackage(
+	ctx context.Context,
+	clientPackageID uint,
+	purchasedAt time.Time,
+) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE client_packages
+		SET status       = 'active',
+		    purchased_at = ?,
+		    expires_at   = ?::timestamptz + make_interval(days => validity_days)
+		WHERE id = ?
+		  AND status = 'pending_payment'
+	`, purchasedAt, purchasedAt, clientPackageID).Error
+}
+
+func (r *ServicePackageGormRepository) ListClientPackages(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) ([]models.ClientPackage, error) {
+	var list []models.ClientPackage
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND client_id = ? AND status <> ?",
+			barbershopID, clientID, models.ClientPackagePendingPayment).
+		Order("created_at DESC").
+		Find(&list).Error
+
+	return list, err
+}
+
+func (r *ServicePackageGormRepository) FindUsableForUpdate(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	serviceID uint,
+	now time.Time,
+) (*models.ClientPackage, error) {
+	var cp models.ClientPackage
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(`barbershop_id = ? AND client_id = ? AND status = ?
+		       AND expires_at > ? AND credits_used < credits_total
+		       AND EXISTS (
+		           SELECT 1 FROM service_package_services sps
+		           WHERE sps.package_id = client_packages.package_id
+		             AND sps.service_id = ?
+		       )`,
+			barbershopID, clientID, models.ClientPackageActive, now, serviceID).
+		Order("expires_at ASC, id ASC").
+		First(&cp).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (r *ServicePackageGormRepository) ConsumeCredit(
+	ctx context.Context,
+	clientPackageID uint,
+) error {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE client_packages
+		SET credits_used = credits_used + 1
+		WHERE id = ?
+		  AND credits_used < credits_total
+	`, clientPackageID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrNoCreditsLeft
+	}
+	return nil
+}
+
+func (r *ServicePackageGormRepository) ExpireClientPackages(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.ClientPackage{}).
+		Where("status = ? AND expires_at <= ?", models.ClientPackageActive, now).
+		Update("status", models.ClientPackageExpired)
+	return res.RowsAffected, res.Error
+}
+
+// ======================================================
+// COMBOS
+// ======================================================
+
+func (r *ServicePackageGormRepository) CreateCombo(
+	ctx context.Context,
+	combo *models.ServiceCombo,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := combo.Items
+		combo.Items = nil
+		if err := tx.Create(combo).Error; err != nil {
+			combo.Items = items
+			return err
+		}
+		combo.Items = items
+		return insertComboItems(tx, combo)
+	})
+}
+
+func (r *ServicePackageGormRepository) UpdateCombo(
+	ctx context.Context,
+	combo *models.ServiceCombo,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ServiceCombo{}).
+			Where("id = ? AND barbershop_id = ?", combo.ID, combo.BarbershopID).
+			Updates(map[string]any{
+				"name":             combo.Name,
+				"price_cents":      combo.PriceCents,
+				"discount_percent": combo.DiscountPercent,
+				"active":           combo.Active,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("combo_id = ?", combo.ID).Delete(&models.ServiceComboItem{}).Error; err != nil {
+			return err
+		}
+		return insertComboItems(tx, combo)
+	})
+}
+
+func insertComboItems(tx *gorm.DB, combo *models.ServiceCombo) error {
+	for i := range combo.Items {
+		combo.Items[i].ComboID = combo.ID
+		combo.Items[i].Position = i
+	}
+	if len(combo.Items) == 0 {
+		return nil
+	}
+	return tx.Create(&combo.Items).Error
+}
+
+func (r *ServicePackageGormRepository) GetCombo(
+	ctx context.Context,
+	barbershopID uint,
+	comboID uint,
+) (*models.ServiceCombo, error) {
+	var combo models.ServiceCombo
+
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ? AND barbershop_id = ?", comboID, barbershopID).
+		First(&combo).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &combo, nil
+}
+
+func (r *ServicePackageGormRepository) ListCombos(
+	ctx context.Context,
+	barbershopID uint,
+	onlyActive bool,
+) ([]models.ServiceCombo, error) {
+	var combos []models.ServiceCombo
+
+	q := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("barbershop_id = ?", barbershopID)
+	if onlyActive {
+		q = q.Where("active = true")
+	}
+	err := q.Order("name ASC").Find(&combos).Error
+	return combos, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+// Testes de repositório para o cartão guardado da renovação automática.
+//
+// Requerem banco PostgreSQL real via DATABASE_URL — skipped automaticamente sem ele.
+// Helpers de setup em cancel_subscription_test.go.
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
+)
+
+// TestSaveCard_PayerEncrypted valida que e-mail e CPF do pagador não ficam
+// em texto puro em subscription_cards e voltam decifrados na leitura.
+func TestSaveCard_PayerEncrypted(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	cipher, err := crypt.NewCipher("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	outerErr := db.Transaction(func(tx *gorm.DB) error {
+		bs := seedBarbershop(t, tx)
+		cl := seedClient(t, tx, bs.ID)
+		plan := seedPlan(t, tx, bs.ID)
+		sub := seedSubscription(t, tx, bs.ID, cl.ID, plan.ID, 0)
+
+		repo := NewSubscriptionRenewalGormRepository(tx, cipher)
+		card := &models.SubscriptionCard{
+			BarbershopID:   bs.ID,
+			SubscriptionID: sub.ID,
+			Provider:       "pagbank",
+			CardRef:        "CARD_1",
+			LastFour:       "4242",
+			PayerEmail:     "pagador@example.com",
+			PayerCPF:       "123.456.789-09",
+		}
+		if err := repo.SaveCard(ctx, card); err != nil {
+			t.Errorf("SaveCard: %v", err)
+			return errors.New("rollback — falha no act")
+		}
+		if card.PayerCPF != "123.456.789-09" {
+			t.Errorf("SaveCard não deve alterar o cartão do chamador, CPF = %q", card.PayerCPF)
+		}
+
+		var stored models.SubscriptionCard
+		if err := tx.First(&stored, card.ID).Error; err != nil {
+			t.Fatalf("não encontrou subscription_card: %v", err)
+		}
+		if stored.PayerCPF == card.PayerCPF || stored.PayerEmail == card.PayerEmail {
+			t.Errorf("dados do pagador gravados em texto puro: email=%q cpf=%q", stored.PayerEmail, stored.PayerCPF)
+		}
+
+		got, err := repo.GetCard(ctx, sub.ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetCard: card=%v err=%v", got, err)
+		}
+		if got.PayerEmail != card.PayerEmail || got.PayerCPF != card.PayerCPF {
+			t.Errorf("GetCard esperado email=%q cpf=%q, obtido email=%q cpf=%q",
+				card.PayerEmail, card.PayerCPF, got.PayerEmail, got.PayerCPF)
+		}
+
+		return errors.New("rollback intencional")
+	})
+
+	if outerErr != nil && outerErr.Error() != "rollback intencional" {
+		t.Errorf("transação de teste falhou inesperadamente: %v", outerErr)
+	}
+}
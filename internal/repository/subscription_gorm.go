@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -289,14 +290,15 @@ func (r *SubscriptionGormRepository) CancelSubscription(
 	barbershopID, clientID uint,
 ) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. Localiza e bloqueia a assinatura ativa com FOR UPDATE.
+		// 1. Localiza e bloqueia a assinatura ativa (ou em carência) com FOR UPDATE.
 		// SELECT ... FOR UPDATE adquire lock de linha antes das alterações,
 		// impedindo que duas transações simultâneas cancelem a mesma assinatura.
 		var sub models.Subscription
 		if err := tx.
 			Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("barbershop_id = ? AND client_id = ? AND status = ?",
-				barbershopID, clientID, "active").
+			Where("barbershop_id = ? AND client_id = ? AND status IN ?",
+				barbershopID, clientID, []string{"active", string(domain.StatusPastDue)}).
+			Order("status ASC").
 			First(&sub).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return domain.ErrActiveSubscriptionNotFound
@@ -379,6 +381,11 @@ func (r *SubscriptionGormRepository) GetActiveSubscription(
 		CurrentPeriodEnd:     model.CurrentPeriodEnd,
 		CutsUsedInPeriod:     model.CutsUsedInPeriod,
 		CutsReservedInPeriod: model.CutsReservedInPeriod,
+		AutoRenew:            model.AutoRenew,
+		RenewalAttempts:      model.RenewalAttempts,
+		NextRenewalAt:        model.NextRenewalAt,
+		RenewedPeriodEnd:     model.RenewedPeriodEnd,
+		GraceUntil:           model.GraceUntil,
 		Plan:                 planPtr,
 	}, nil
 }
@@ -636,6 +643,11 @@ func (r *SubscriptionGormRepository) GetSubscriptionByID(
 		CurrentPeriodEnd:     model.CurrentPeriodEnd,
 		CutsUsedInPeriod:     model.CutsUsedInPeriod,
 		CutsReservedInPeriod: model.CutsReservedInPeriod,
+		AutoRenew:            model.AutoRenew,
+		RenewalAttempts:      model.RenewalAttempts,
+		NextRenewalAt:        model.NextRenewalAt,
+		RenewedPeriodEnd:     model.RenewedPeriodEnd,
+		GraceUntil:           model.GraceUntil,
 		Plan:                 plan,
 	}, nil
 }
@@ -675,9 +687,22 @@ func (r *SubscriptionGormRepository) ActivateSubscriptionByID(
 		}
 		return domain.ErrActiveSubscriptionNotFound
 	}
-	return nil
+	return r.db.WithContext(ctx).Exec(expireSupersededPastDueSQL, id).Error
 }
 
+// expireSupersededPastDueSQL expira a assinatura em carência do cliente
+// quando uma nova compra é ativada: a antiga não deve mais ser cobrada. A
+// limpeza das reservas fica com ExpireSubscriptions.
+const expireSupersededPastDueSQL = `
+	UPDATE subscriptions old
+	SET status = 'expired', cuts_reserved_in_period = 0, auto_renew = false
+	FROM subscriptions cur
+	WHERE cur.id = ?
+	  AND old.barbershop_id = cur.barbershop_id
+	  AND old.client_id = cur.client_id
+	  AND old.id <> cur.id
+	  AND old.status = 'past_due'`
+
 func isPendingSubscriptionUniqueViolation(err error) bool {
 	if err == nil {
 		return false
@@ -903,11 +928,40 @@ func (r *SubscriptionGormRepository) ExpireSubscriptions(ctx context.Context) (i
 	var total int64
 
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. Expira subscriptions ativas cujo período terminou.
+		// 0. Renovação já paga: vira o período em vez de expirar.
+		if err := tx.Exec(
+			`UPDATE subscriptions
+			 SET current_period_start = current_period_end,
+			     current_period_end   = renewed_period_end,
+			     cuts_used_in_period  = 0,
+			     renewed_period_end   = NULL
+			 WHERE status = 'active'
+			   AND current_period_end < NOW()
+			   AND renewed_period_end IS NOT NULL`,
+		).Error; err != nil {
+			return err
+		}
+
+		// 0.1. Renovação automática ainda não paga: entra em carência enquanto
+		// as retentativas continuam.
+		if err := tx.Exec(
+			`UPDATE subscriptions
+			 SET status = 'past_due', grace_until = current_period_end + ?::interval
+			 WHERE status = 'active'
+			   AND current_period_end < NOW()
+			   AND auto_renew = true`,
+			fmt.Sprintf("%d seconds", int64(domain.GracePeriod.Seconds())),
+		).Error; err != nil {
+			return err
+		}
+
+		// 1. Expira subscriptions ativas cujo período terminou e as em carência
+		// cuja carência acabou sem pagamento.
 		r1 := tx.Exec(
 			`UPDATE subscriptions
 			 SET status = 'expired', cuts_reserved_in_period = 0
-			 WHERE status = 'active' AND current_period_end < NOW()`,
+			 WHERE (status = 'active' AND current_period_end < NOW())
+			    OR (status = 'past_due' AND grace_until < NOW())`,
 		)
 		if r1.Error != nil {
 			return r1.Error
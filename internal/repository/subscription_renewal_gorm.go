@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
+)
+
+// SubscriptionRenewalGormRepository implementa domain.RenewalRepository.
+// E-mail e CPF do pagador do cartão são gravados criptografados com cipher;
+// nil (dev sem PAYMENT_CREDENTIALS_ENCRYPTION_KEY) grava em texto puro.
+type SubscriptionRenewalGormRepository struct {
+	db     *gorm.DB
+	cipher *crypt.Cipher
+}
+
+func NewSubscriptionRenewalGormRepository(db *gorm.DB, cipher *crypt.Cipher) *SubscriptionRenewalGormRepository {
+	return &SubscriptionRenewalGormRepository{db: db, cipher: cipher}
+}
+
+func (r *SubscriptionRenewalGormRepository) SaveCard(
+	ctx context.Context,
+	card *models.SubscriptionCard,
+) error {
+	// Grava uma cópia: o chamador continua com os dados do pagador em claro.
+	row := *card
+	var err error
+	if row.PayerEmail, err = r.encrypt(card.PayerEmail); err != nil {
+		return err
+	}
+	if row.PayerCPF, err = r.encrypt(card.PayerCPF); err != nil {
+		return err
+	}
+
+	err = r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "subscription_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"provider", "customer_ref", "card_ref", "brand",
+				"last_four", "payer_email", "payer_cpf", "updated_at",
+			}),
+		}).
+		Create(&row).Error
+	if err != nil {
+		return err
+	}
+	card.ID = row.ID
+	card.CreatedAt = row.CreatedAt
+	card.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *SubscriptionRenewalGormRepository) GetCard(
+	ctx context.Context,
+	subscriptionID uint,
+) (*models.SubscriptionCard, error) {
+	var card models.SubscriptionCard
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		First(&card).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	card.PayerEmail = r.decrypt(card.PayerEmail)
+	card.PayerCPF = r.decrypt(card.PayerCPF)
+	return &card, nil
+}
+
+// encrypt cifra um dado do pagador. Sem cipher ou valor vazio, devolve o
+// valor como veio.
+func (r *SubscriptionRenewalGormRepository) encrypt(value string) (string, error) {
+	if r.cipher == nil || value == "" {
+		return value, nil
+	}
+	enc, err := r.cipher.Encrypt([]byte(value))
+	if err != nil {
+		return "", fmt.Errorf("encrypt subscription card payer: %w", err)
+	}
+	return enc, nil
+}
+
+// decrypt é o inverso de encrypt. Valor que não decifra é linha gravada em
+// texto puro antes da migration 043 — devolvido como está e cifrado na
+// próxima gravação do cartão.
+func (r *SubscriptionRenewalGormRepository) decrypt(value string) string {
+	if r.cipher == nil || value == "" {
+		return value
+	}
+	plain, err := r.cipher.Decrypt(value)
+	if err != nil {
+		return value
+	}
+	return string(plain)
+}
+
+// SetAutoRenew liga/desliga a renovação. Religar zera as tentativas para que
+// o job volte a cobrar.
+func (r *SubscriptionRenewalGormRepository) SetAutoRenew(
+	ctx context.Context,
+	subscriptionID uint,
+	autoRenew bool,
+) error {
+	updates := map[string]any{"auto_renew": autoRenew}
+	if autoRenew {
+		updates["renewal_attempts"] = 0
+		updates["next_renewal_at"] = nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("id = ?", subscriptionID).
+		Updates(updates).Error
+}
+
+func (r *SubscriptionRenewalGormRepository) FindRenewableSubscriptionID(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("barbershop_id = ? AND client_id = ? AND status IN ?",
+			barbershopID, clientID, []string{string(domain.StatusActive), string(domain.StatusPastDue)}).
+		Order("status ASC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// renewalRow é a linha de ListDueRenewals/GetRenewal: assinatura, cartão e os
+// dados de contato para o aviso.
+type renewalRow struct {
+	models.Subscription
+	CardID          uint
+	CardProvider    string
+	CardCustomerRef string
+	CardRef         string
+	CardBrand       string
+	CardLastFour    string
+	CardPayerEmail  string
+	CardPayerCPF    string `gorm:"column:card_payer_cpf"`
+	ClientName      string
+	ClientPhone     string
+	ClientEmail     string
+	BarbershopName  string
+	Timezone        string
+}
+
+const renewalSelect = `
+	SELECT s.*,
+	       c.id           AS card_id,
+	       c.provider     AS card_provider,
+	       c.customer_ref AS card_customer_ref,
+	       c.card_ref     AS card_ref,
+	       c.brand        AS card_brand,
+	       c.last_four    AS card_last_four,
+	       c.payer_email  AS card_payer_email,
+	       c.payer_cpf    AS card_payer_cpf,
+	       cl.name                 AS client_name,
+	       COALESCE(cl.phone, '')  AS client_phone,
+	       COALESCE(cl.email, '')  AS client_email,
+	       b.name                  AS barbershop_name,
+	       b.timezone              AS timezone
+	FROM subscriptions s
+	JOIN subscription_cards c ON c.subscription_id = s.id
+	JOIN clients cl           ON cl.id = s.client_id
+	JOIN barbershops b        ON b.id = s.barbershop_id`
+
+func (r *SubscriptionRenewalGormRepository) ListDueRenewals(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]domain.Renewal, error) {
+	var rows []renewalRow
+	err := r.db.WithContext(ctx).Raw(renewalSelect+`
+		WHERE s.auto_renew = true
+		  AND s.renewed_period_end IS NULL
+		  AND s.renewal_attempts < ?
+		  AND (
+		        (s.status = 'active'   AND s.current_period_end <= ?)
+		     OR (s.status = 'past_due' AND s.grace_until > ?)
+		  )
+		  AND (s.next_renewal_at IS NULL OR s.next_renewal_at <= ?)
+		  AND NOT EXISTS (
+		        SELECT 1 FROM payments p
+		        WHERE p.subscription_id = s.id
+		          AND p.subscription_renewal = true
+		          AND p.status = 'pending'
+		  )
+		ORDER BY s.current_period_end ASC
+		LIMIT ?`,
+		domain.MaxRenewalAttempts(), now.Add(domain.RenewalLeadTime), now, now, limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Renewal, 0, len(rows))
+	for i := range rows {
+		renewal, err := r.toRenewal(ctx, &rows[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *renewal)
+	}
+	return out, nil
+}
+
+func (r *SubscriptionRenewalGormRepository) GetRenewal(
+	ctx context.Context,
+	subscriptionID uint,
+) (*domain.Renewal, error) {
+	var rows []renewalRow
+	if err := r.db.WithContext(ctx).
+		Raw(renewalSelect+` WHERE s.id = ?`, subscriptionID).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return r.toRenewal(ctx, &rows[0])
+}
+
+func (r *SubscriptionRenewalGormRepository) FailRenewalPayment(
+	ctx context.Context,
+	paymentID uint,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.Payment{}).
+		Where("id = ? AND subscription_renewal = true AND status = ?", paymentID, "pending").
+		Update("status", "expired")
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *SubscriptionRenewalGormRepository) RecordRenewalFailure(
+	ctx context.Context,
+	subscriptionID uint,
+	attempts int,
+	nextAttemptAt *time.Time,
+) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("id = ?", subscriptionID).
+		Updates(map[string]any{
+			"renewal_attempts": attempts,
+			"next_renewal_at":  nextAttemptAt,
+		}).Error
+}
+
+func (r *SubscriptionRenewalGormRepository) toRenewal(
+	ctx context.Context,
+	row *renewalRow,
+) (*domain.Renewal, error) {
+	m := row.Subscription
+
+	plan, err := NewSubscriptionGormRepository(r.db).GetPlanByID(ctx, m.BarbershopID, m.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Renewal{
+		Subscription: domain.Subscription{
+			ID:                   m.ID,
+			BarbershopID:         m.BarbershopID,
+			ClientID:             m.ClientID,
+			PlanID:               m.PlanID,
+			Status:               domain.Status(m.Status),
+			CurrentPeriodStart:   m.CurrentPeriodStart,
+			CurrentPeriodEnd:     m.CurrentPeriodEnd,
+			CutsUsedInPeriod:     m.CutsUsedInPeriod,
+			CutsReservedInPeriod: m.CutsReservedInPeriod,
+			AutoRenew:            m.AutoRenew,
+			RenewalAttempts:      m.RenewalAttempts,
+			NextRenewalAt:        m.NextRenewalAt,
+			RenewedPeriodEnd:     m.RenewedPeriodEnd,
+			GraceUntil:           m.GraceUntil,
+			Plan:                 plan,
+		},
+		Card: models.SubscriptionCard{
+			ID:             row.CardID,
+			BarbershopID:   m.BarbershopID,
+			SubscriptionID: m.ID,
+			Provider:       row.CardProvider,
+			CustomerRef:    row.CardCustomerRef,
+			CardRef:        row.CardRef,
+			Brand:          row.CardBrand,
+			LastFour:       row.CardLastFour,
+			PayerEmail:     r.decrypt(row.CardPayerEmail),
+			PayerCPF:       r.decrypt(row.CardPayerCPF),
+		},
+		ClientName:     row.ClientName,
+		ClientPhone:    row.ClientPhone,
+		ClientEmail:    row.ClientEmail,
+		BarbershopName: row.BarbershopName,
+		Timezone:       row.Timezone,
+	}, nil
+}
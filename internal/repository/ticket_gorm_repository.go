@@ -19,6 +19,11 @@ func NewTicketGormRepository(db *gorm.DB) *TicketGormRepository {
 	return &TicketGormRepository{db: db}
 }
 
+// WithTx grava os tickets na transação do chamador.
+func (r *TicketGormRepository) WithTx(tx *gorm.DB) domainTicket.Repository {
+	return &TicketGormRepository{db: tx}
+}
+
 func (r *TicketGormRepository) Upsert(ctx context.Context, ticket *models.AppointmentTicket) error {
 	return r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type WaitlistGormRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistGormRepository(db *gorm.DB) *WaitlistGormRepository {
+	return &WaitlistGormRepository{db: db}
+}
+
+func (r *WaitlistGormRepository) GetBarbershopByID(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).
+		First(&shop, barbershopID).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *WaitlistGormRepository) GetService(
+	ctx context.Context,
+	barbershopID uint,
+	serviceID uint,
+) (*models.BarbershopService, error) {
+	var svc models.BarbershopService
+
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND barbershop_id = ?", serviceID, barbershopID).
+		First(&svc).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+func (r *WaitlistGormRepository) GetBarberServiceOverride(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	serviceID uint,
+) (*models.BarberServiceOverride, error) {
+	var o models.BarberServiceOverride
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND barber_id = ? AND service_id = ?", barbershopID, barberID, serviceID).
+		First(&o).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *WaitlistGormRepository) ActiveBarberExists(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND barbershop_id = ? AND active = true", barberID, barbershopID).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
+
+func (r *WaitlistGormRepository) HasOpenEntry(
+	ctx context.Context,
+	barbershopID uint,
+	serviceID uint,
+	phone string,
+	email string,
+) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&models.WaitlistEntry{}).
+		Where("barbershop_id = ? AND service_id = ?", barbershopID, serviceID).
+		Where("status IN ?", []string{models.WaitlistStatusWaiting, models.WaitlistStatusOffered}).
+		Where("date_to >= CURRENT_DATE").
+		Where("(? <> '' AND client_phone = ?) OR (? <> '' AND LOWER(client_email) = LOWER(?))",
+			phone, phone, email, email).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
+
+func (r *WaitlistGormRepository) Create(
+	ctx context.Context,
+	entry *models.WaitlistEntry,
+) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *WaitlistGormRepository) GetByToken(
+	ctx context.Context,
+	token string,
+) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		First(&entry).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *WaitlistGormRepository) ListOfferCandidates(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+	day time.Time,
+	now time.Time,
+	limit int,
+) ([]*models.WaitlistEntry, error) {
+	var entries []*models.WaitlistEntry
+
+	date := day.Format("2006-01-02")
+
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Where("barber_id IS NULL OR barber_id = ?", barberID).
+		Where("date_from <= ?::date AND date_to >= ?::date", date, date).
+		Where("status = ? OR (status = ? AND offer_expires_at <= ?)",
+			models.WaitlistStatusWaiting, models.WaitlistStatusOffered, now).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&entries).
+		Error
+
+	return entries, err
+}
+
+func (r *WaitlistGormRepository) SetOffer(
+	ctx context.Context,
+	entry *models.WaitlistEntry,
+	now time.Time,
+) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&models.WaitlistEntry{}).
+		Where("id = ?", entry.ID).
+		Where("status = ? OR (status = ? AND offer_expires_at <= ?)",
+			models.WaitlistStatusWaiting, models.WaitlistStatusOffered, now).
+		Updates(map[string]any{
+			"status":             models.WaitlistStatusOffered,
+			"offered_start_time": entry.OfferedStartTime,
+			"offered_barber_id":  entry.OfferedBarberID,
+			"offer_expires_at":   entry.OfferExpiresAt,
+		})
+
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *WaitlistGormRepository) Update(
+	ctx context.Context,
+	entry *models.WaitlistEntry,
+) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
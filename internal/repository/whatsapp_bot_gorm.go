@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/whatsappbot"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type WhatsAppBotGormRepository struct {
+	db *gorm.DB
+}
+
+func NewWhatsAppBotGormRepository(db *gorm.DB) *WhatsAppBotGormRepository {
+	return &WhatsAppBotGormRepository{db: db}
+}
+
+var _ domain.Repository = (*WhatsAppBotGormRepository)(nil)
+
+// ======================================================
+// CONVERSATIONS
+// ======================================================
+
+// LockConversation usa pg_advisory_xact_lock em vez de FOR UPDATE: na
+// primeira mensagem ainda não há linha para travar. O lock é liberado no
+// commit/rollback da transação.
+func (r *WhatsAppBotGormRepository) LockConversation(
+	ctx context.Context,
+	instanceName string,
+	phone string,
+	fn func(ctx context.Context, repo domain.Repository) error,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"SELECT pg_advisory_xact_lock(hashtext('whatsapp_conversation'), hashtext(? || ':' || ?))",
+			instanceName, phone,
+		).Error; err != nil {
+			return err
+		}
+		return fn(ctx, &WhatsAppBotGormRepository{db: tx})
+	})
+}
+
+func (r *WhatsAppBotGormRepository) GetConversation(
+	ctx context.Context,
+	instanceName string,
+	phone string,
+) (*models.WhatsAppConversation, error) {
+	var c models.WhatsAppConversation
+
+	err := r.db.WithContext(ctx).
+		Where("instance_name = ? AND phone = ?", instanceName, phone).
+		First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *WhatsAppBotGormRepository) SaveConversation(
+	ctx context.Context,
+	c *models.WhatsAppConversation,
+) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "instance_name"}, {Name: "phone"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"barbershop_id",
+				"state",
+				"data",
+				"expires_at",
+				"updated_at",
+			}),
+		}).
+		Create(c).
+		Error
+}
+
+func (r *WhatsAppBotGormRepository) DeleteConversation(
+	ctx context.Context,
+	instanceName string,
+	phone string,
+) error {
+	return r.db.WithContext(ctx).
+		Where("instance_name = ? AND phone = ?", instanceName, phone).
+		Delete(&models.WhatsAppConversation{}).
+		Error
+}
+
+// ======================================================
+// READS
+// ======================================================
+
+func (r *WhatsAppBotGormRepository) GetBarbershop(
+	ctx context.Context,
+	barbershopID uint,
+) (*models.Barbershop, error) {
+	var shop models.Barbershop
+
+	err := r.db.WithContext(ctx).First(&shop, barbershopID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+func (r *WhatsAppBotGormRepository) FindClient(
+	ctx context.Context,
+	barbershopID uint,
+	phone string,
+) (*models.Client, error) {
+	// Últimos 8 dígitos: o WhatsApp entrega DDI+DDD+número e o cadastro pode
+	// ter qualquer formatação.
+	suffix := phone
+	if len(suffix) > 8 {
+		suffix = suffix[len(suffix)-8:]
+	}
+
+	var c models.Client
+	err := r.db.WithContext(ctx).
+		Where("barbershop_id = ? AND anonymized_at IS NULL", barbershopID).
+		Where("REGEXP_REPLACE(phone, '[^0-9]', '', 'g') LIKE ?", "%"+suffix).
+		Order("id ASC").
+		First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *WhatsAppBotGormRepository) ListUpcoming(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) ([]domain.UpcomingAppointment, error) {
+	var rows []struct {
+		ID              uint
+		BarberID        uint
+		BarberProductID uint
+		ServiceName     string
+		StartTime       time.Time
+		TicketToken     string
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			a.id,
+			a.barber_id,
+			a.barber_product_id,
+			COALESCE(
+				(SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+				 FROM appointment_services s WHERE s.appointment_id = a.id),
+				bs.name
+			) AS service_name,
+			a.start_time,
+			t.token AS ticket_token
+		FROM appointments a
+		JOIN appointment_tickets t
+			ON t.appointment_id = a.id AND t.expires_at > NOW()
+		LEFT JOIN barbershop_services bs
+			ON bs.id = a.barber_product_id
+		WHERE a.barbershop_id = ?
+		  AND a.client_id = ?
+		  AND a.status IN ('scheduled', 'awaiting_payment')
+		  AND a.start_time >= NOW()
+		ORDER BY a.start_time ASC
+		LIMIT 9
+	`, barbershopID, clientID).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var lines []struct {
+		AppointmentID uint
+		ServiceID     uint
+	}
+	err = r.db.WithContext(ctx).
+		Table("appointment_services").
+		Select("appointment_id, service_id").
+		Where("appointment_id IN ? AND service_id IS NOT NULL", ids).
+		Order("appointment_id, position").
+		Scan(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[uint][]uint, len(rows))
+	for _, l := range lines {
+		services[l.AppointmentID] = append(services[l.AppointmentID], l.ServiceID)
+	}
+
+	out := make([]domain.UpcomingAppointment, len(rows))
+	for i, row := range rows {
+		serviceIDs := services[row.ID]
+		if len(serviceIDs) == 0 && row.BarberProductID != 0 {
+			serviceIDs = []uint{row.BarberProductID}
+		}
+		out[i] = domain.UpcomingAppointment{
+			ID:          row.ID,
+			BarberID:    row.BarberID,
+			ServiceIDs:  serviceIDs,
+			ServiceName: row.ServiceName,
+			StartTime:   row.StartTime,
+			TicketToken: row.TicketToken,
+		}
+	}
+	return out, nil
+}
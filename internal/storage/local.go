@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage writes images under a directory on disk, for development
+// without R2 credentials. The directory must be served at publicURL.
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalStorage(dir, publicURL string) *LocalStorage {
+	return &LocalStorage{
+		dir:       dir,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Upload resizes and converts the image like R2Service.Upload and writes it
+// to dir/objectKey.
+func (s *LocalStorage) Upload(_ context.Context, kind AssetKind, objectKey string, raw []byte) (string, error) {
+	path, err := s.path(objectKey)
+	if err != nil {
+		return "", err
+	}
+
+	maxW, maxH := dimensionsFor(kind)
+	jpegBytes, err := convertToJPEG(raw, maxW, maxH)
+	if err != nil {
+		return "", fmt.Errorf("image conversion: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("local upload: %w", err)
+	}
+	if err := os.WriteFile(path, jpegBytes, 0o644); err != nil {
+		return "", fmt.Errorf("local upload: %w", err)
+	}
+
+	return s.publicURL + "/" + objectKey, nil
+}
+
+// Delete removes the file; a missing file is not an error.
+func (s *LocalStorage) Delete(_ context.Context, objectKey string) error {
+	path, err := s.path(objectKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// KeyFromURL extracts the object key from a full public URL.
+func (s *LocalStorage) KeyFromURL(url string) string {
+	return strings.TrimPrefix(url, s.publicURL+"/")
+}
+
+// path resolves objectKey inside dir, refusing keys that escape it.
+func (s *LocalStorage) path(objectKey string) (string, error) {
+	clean := filepath.Clean("/" + objectKey)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid object key %q", objectKey)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
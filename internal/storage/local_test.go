@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorageUploadAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "http://localhost:8080/uploads/")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2000, 1000))); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	url, err := s.Upload(ctx, KindClientPhoto, "1/clients/7/a.jpg", buf.Bytes())
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if url != "http://localhost:8080/uploads/1/clients/7/a.jpg" {
+		t.Errorf("url inesperada: %s", url)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "1/clients/7/a.jpg"))
+	if err != nil {
+		t.Fatalf("arquivo não gravado: %v", err)
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	f.Close()
+	if err != nil || cfg.Width != clientPhotoW || cfg.Height != clientPhotoW/2 {
+		t.Errorf("imagem deveria caber em %dx%d: %+v %v", clientPhotoW, clientPhotoH, cfg, err)
+	}
+
+	key := s.KeyFromURL(url)
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, key)); !os.IsNotExist(err) {
+		t.Errorf("arquivo deveria ter sido removido")
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("apagar de novo não deveria falhar: %v", err)
+	}
+}
+
+func TestLocalStorageKeepsKeysInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(filepath.Join(dir, "uploads"), "/uploads")
+
+	path, err := s.path("../../etc/passwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "uploads", "etc", "passwd") {
+		t.Errorf("chave saiu do diretório: %s", path)
+	}
+	if _, err := s.path(".."); err == nil {
+		t.Errorf("chave vazia deveria ser recusada")
+	}
+}
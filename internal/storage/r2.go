@@ -28,6 +28,8 @@ const (
 	productImageH = 400
 	profilePhotoW = 1200
 	profilePhotoH = 630
+	clientPhotoW  = 1200
+	clientPhotoH  = 1200
 )
 
 type AssetKind string
@@ -36,8 +38,17 @@ const (
 	KindServiceImage AssetKind = "services"
 	KindProductImage AssetKind = "products"
 	KindProfilePhoto AssetKind = "profile"
+	KindClientPhoto  AssetKind = "clients"
 )
 
+// Storage stores uploaded images and returns their public URL. R2Service is
+// the production backend; LocalStorage serves the same keys from disk in dev.
+type Storage interface {
+	Upload(ctx context.Context, kind AssetKind, objectKey string, raw []byte) (string, error)
+	Delete(ctx context.Context, objectKey string) error
+	KeyFromURL(url string) string
+}
+
 type R2Service struct {
 	client    *s3.Client
 	bucket    string
@@ -109,6 +120,8 @@ func dimensionsFor(kind AssetKind) (int, int) {
 		return productImageW, productImageH
 	case KindProfilePhoto:
 		return profilePhotoW, profilePhotoH
+	case KindClientPhoto:
+		return clientPhotoW, clientPhotoH
 	default:
 		return 800, 600
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
 )
 
 const adjustmentWindowDays = 7
@@ -32,14 +33,22 @@ type AdjustClosureInput struct {
 }
 
 type AdjustClosure struct {
-	db    *gorm.DB
-	audit *audit.Dispatcher
+	db      *gorm.DB
+	audit   *audit.Dispatcher
+	loyalty *ucLoyalty.Ledger
 }
 
 func NewAdjustClosure(db *gorm.DB, audit *audit.Dispatcher) *AdjustClosure {
 	return &AdjustClosure{db: db, audit: audit}
 }
 
+// WithLoyalty estorna os pontos de fidelidade quando o ajuste reduz o valor
+// cobrado no fechamento.
+func (uc *AdjustClosure) WithLoyalty(l *ucLoyalty.Ledger) *AdjustClosure {
+	uc.loyalty = l
+	return uc
+}
+
 func (uc *AdjustClosure) Execute(
 	ctx context.Context,
 	input AdjustClosureInput,
@@ -56,6 +65,7 @@ func (uc *AdjustClosure) Execute(
 	}
 
 	var adjustment *models.ClosureAdjustment
+	var loyaltyReversed int
 
 	err := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Load the closure for this appointment
@@ -88,7 +98,25 @@ func (uc *AdjustClosure) Execute(
 			AdjustedAt:            now,
 		}
 
-		return tx.Create(adjustment).Error
+		if err := tx.Create(adjustment).Error; err != nil {
+			return err
+		}
+
+		if uc.loyalty != nil && input.DeltaFinalAmountCents != nil {
+			charged := loyaltyChargeCents(
+				input.DeltaFinalAmountCents,
+				closure.ReferenceAmountCents,
+				closure.SubscriptionCovered,
+				closure.SubscriptionCoveredCents,
+				closure.PackageCoveredCents,
+			)
+			reversed, err := uc.loyalty.WithTx(tx).Reverse(ctx, &input.AppointmentID, nil, charged)
+			if err != nil {
+				return err
+			}
+			loyaltyReversed = reversed
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -110,6 +138,9 @@ func (uc *AdjustClosure) Execute(
 	if input.DeltaOperationalNote != nil {
 		metadata["delta_operational_note"] = *input.DeltaOperationalNote
 	}
+	if loyaltyReversed > 0 {
+		metadata["loyalty_points_reversed"] = loyaltyReversed
+	}
 
 	barberID := input.BarberID
 	uc.audit.Dispatch(audit.Event{
@@ -0,0 +1,173 @@
+package appointment
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// maxAppointmentServices limita quantos serviços cabem num agendamento.
+const maxAppointmentServices = 5
+
+// lineCoverage é a cobertura da assinatura de um serviço do agendamento.
+type lineCoverage struct {
+	Status   models.AppointmentCoverageStatus
+	Reserved bool
+}
+
+// bookingProductIDs devolve os serviços pedidos, em ordem. ProductIDs vazio
+// mantém o booking de um serviço só (ProductID).
+func bookingProductIDs(productID uint, productIDs []uint) []uint {
+	if len(productIDs) == 0 {
+		return []uint{productID}
+	}
+	return productIDs
+}
+
+// bookingServices resolve os serviços pedidos: os itens do combo quando
+// comboID != 0, senão ProductIDs/ProductID. Retorna combo_not_found para
+// combo inexistente ou inativo.
+func bookingServices(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	productID uint,
+	productIDs []uint,
+	comboID uint,
+) ([]*models.BarbershopService, *models.ServiceCombo, error) {
+	ids := bookingProductIDs(productID, productIDs)
+
+	var combo *models.ServiceCombo
+	if comboID != 0 {
+		c, err := repo.GetCombo(ctx, barbershopID, comboID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c == nil || !c.Active || len(c.Items) == 0 {
+			return nil, nil, apperr.ErrBusiness("combo_not_found")
+		}
+		combo = c
+		ids = c.ServiceIDs()
+	}
+
+	products, err := loadProducts(ctx, repo, barbershopID, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return products, combo, nil
+}
+
+// loadProducts busca os serviços do agendamento na ordem pedida.
+// Retorna too_many_services, duplicate_service ou product_not_found.
+func loadProducts(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	productIDs []uint,
+) ([]*models.BarbershopService, error) {
+	if len(productIDs) == 0 {
+		return nil, apperr.ErrBusiness("product_not_found")
+	}
+	if len(productIDs) > maxAppointmentServices {
+		return nil, apperr.ErrBusiness("too_many_services")
+	}
+
+	seen := make(map[uint]bool, len(productIDs))
+	products := make([]*models.BarbershopService, 0, len(productIDs))
+	for _, id := range productIDs {
+		if seen[id] {
+			return nil, apperr.ErrBusiness("duplicate_service")
+		}
+		seen[id] = true
+
+		product, err := repo.GetProduct(ctx, barbershopID, id)
+		if err != nil || product == nil {
+			return nil, apperr.ErrBusiness("product_not_found")
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// appointmentCoverage resume a cobertura das linhas no agendamento: coberto
+// só quando todos os serviços são cobertos; senão, o status do primeiro
+// serviço não coberto. reserved indica se alguma linha reservou corte.
+func appointmentCoverage(lines []lineCoverage) (status models.AppointmentCoverageStatus, reserved bool) {
+	status = models.CoverageStatusNone
+	allCovered := len(lines) > 0
+	for _, line := range lines {
+		if line.Status != models.CoverageStatusCovered {
+			allCovered = false
+			if status == models.CoverageStatusNone {
+				status = line.Status
+			}
+		}
+		if line.Reserved {
+			reserved = true
+		}
+	}
+	if allCovered {
+		status = models.CoverageStatusCovered
+	}
+	return status, reserved
+}
+
+// appointmentServiceLines monta as linhas do agendamento com o snapshot dos
+// serviços nos valores do barbeiro escolhido.
+func appointmentServiceLines(
+	barbershopID uint,
+	services []*models.BarbershopService,
+	coverage []lineCoverage,
+) []models.AppointmentService {
+	lines := make([]models.AppointmentService, 0, len(services))
+	for i, svc := range services {
+		serviceID := svc.ID
+		line := models.AppointmentService{
+			BarbershopID:   barbershopID,
+			ServiceID:      &serviceID,
+			Position:       i,
+			ServiceName:    svc.Name,
+			PriceCents:     svc.Price,
+			DurationMin:    svc.DurationMin,
+			CoverageStatus: models.CoverageStatusNone,
+		}
+		if i < len(coverage) {
+			line.CoverageStatus = coverage[i].Status
+			line.ReservedCut = coverage[i].Reserved
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// applyComboPrice aplica o preço do combo às linhas: o total do combo é
+// rateado proporcionalmente ao preço de cada serviço no barbeiro, e o
+// arredondamento fica na última linha.
+func applyComboPrice(lines []models.AppointmentService, combo *models.ServiceCombo) {
+	if combo == nil || len(lines) == 0 {
+		return
+	}
+
+	var full int64
+	for _, line := range lines {
+		full += line.PriceCents
+	}
+	total := combo.BundlePrice(full)
+
+	remaining := total
+	for i := range lines {
+		if i == len(lines)-1 {
+			lines[i].PriceCents = remaining
+			break
+		}
+
+		share := total / int64(len(lines))
+		if full > 0 {
+			share = total * lines[i].PriceCents / full
+		}
+		lines[i].PriceCents = share
+		remaining -= share
+	}
+}
@@ -0,0 +1,291 @@
+package appointment
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// ValidateBookingBarber confere se o barbeiro escolhido pelo cliente atende
+// na barbearia (usuário ativo). barberID 0 — "qualquer barbeiro" — é sempre válido.
+func ValidateBookingBarber(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID, barberID uint,
+) error {
+	if barberID == 0 {
+		return nil
+	}
+
+	ids, err := repo.ListActiveBarberIDs(ctx, barbershopID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == barberID {
+			return nil
+		}
+	}
+	return apperr.ErrBusiness("barber_not_found")
+}
+
+// assertWithinWorkingHours valida [start, end) contra o expediente efetivo do
+// barbeiro (working hours + schedule override), incluindo o almoço e os
+// horários ocupados do Google Calendar.
+func assertWithinWorkingHours(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID, barberID uint,
+	start, end time.Time,
+	loc *time.Location,
+) error {
+	startLocal := start.In(loc)
+	endLocal := end.In(loc)
+
+	ewh, err := resolveWorkingHours(ctx, repo, barbershopID, barberID, startLocal)
+	if err != nil {
+		return err
+	}
+	if ewh == nil {
+		return apperr.ErrBusiness("outside_working_hours")
+	}
+
+	workStart := parseHM(ewh.StartTime, startLocal, loc)
+	workEnd := parseHM(ewh.EndTime, startLocal, loc)
+
+	if startLocal.Before(workStart) || endLocal.After(workEnd) {
+		return apperr.ErrBusiness("outside_working_hours")
+	}
+
+	if ewh.blocked(startLocal, endLocal, startLocal, loc) {
+		return apperr.ErrBusiness("outside_working_hours")
+	}
+
+	return nil
+}
+
+// serviceForBarber devolve o serviço com o preço e a duração do barbeiro
+// (catálogo por barbeiro). Retorna service_not_offered quando ele não faz o serviço.
+func serviceForBarber(
+	ctx context.Context,
+	repo domain.Repository,
+	product *models.BarbershopService,
+	barbershopID, barberID uint,
+) (*models.BarbershopService, error) {
+	override, err := repo.GetBarberServiceOverride(ctx, barbershopID, barberID, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil && !override.Offered {
+		return nil, apperr.ErrBusiness("service_not_offered")
+	}
+	return override.ApplyTo(product), nil
+}
+
+// servicesForBarber aplica serviceForBarber a cada serviço do agendamento
+// (na ordem) e devolve a duração total. Basta um serviço que o barbeiro não
+// faz para retornar service_not_offered.
+func servicesForBarber(
+	ctx context.Context,
+	repo domain.Repository,
+	products []*models.BarbershopService,
+	barbershopID, barberID uint,
+) ([]*models.BarbershopService, time.Duration, error) {
+	services := make([]*models.BarbershopService, 0, len(products))
+	var total time.Duration
+	for _, product := range products {
+		svc, err := serviceForBarber(ctx, repo, product, barbershopID, barberID)
+		if err != nil {
+			return nil, 0, err
+		}
+		services = append(services, svc)
+		total += time.Duration(svc.DurationMin) * time.Minute
+	}
+	return services, total, nil
+}
+
+// barberCandidate é um barbeiro livre para o horário, com os serviços nos
+// valores dele (a soma das durações define o fim do agendamento).
+type barberCandidate struct {
+	BarberID uint
+	Services []*models.BarbershopService
+	End      time.Time
+}
+
+// rankAvailableBarbers devolve os barbeiros ativos que fazem os serviços,
+// atendem no horário e estão livres, na ordem de preferência da estratégia de
+// atribuição da barbearia (Barbershop.BarberAssignmentStrategy). O primeiro é
+// o escolhido; os demais servem de fallback caso outro agendamento
+// concorrente ocupe o horário antes do INSERT.
+//
+// Sem nenhum barbeiro livre retorna time_conflict quando algum barbeiro
+// trabalha no horário (todos ocupados), outside_working_hours quando ninguém
+// trabalha e service_not_offered quando nenhum barbeiro faz todos os serviços.
+func rankAvailableBarbers(
+	ctx context.Context,
+	repo domain.Repository,
+	shop *models.Barbershop,
+	products []*models.BarbershopService,
+	clientID uint,
+	start time.Time,
+	loc *time.Location,
+) ([]barberCandidate, error) {
+	barberIDs, err := repo.ListActiveBarberIDs(ctx, shop.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	anyOffered, anyWorking := false, false
+	free := make(map[uint]barberCandidate, len(barberIDs))
+	freeIDs := make([]uint, 0, len(barberIDs))
+
+	for _, barberID := range barberIDs {
+		services, duration, err := servicesForBarber(ctx, repo, products, shop.ID, barberID)
+		if apperr.IsBusiness(err, "service_not_offered") {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		anyOffered = true
+
+		end := start.Add(duration)
+
+		err = assertWithinWorkingHours(ctx, repo, shop.ID, barberID, start, end, loc)
+		if apperr.IsBusiness(err, "outside_working_hours") {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		anyWorking = true
+
+		conflictStart, conflictEnd := applyTolerance(start, end, shop.ScheduleToleranceMinutes)
+		err = repo.AssertNoTimeConflict(ctx, shop.ID, barberID, conflictStart, conflictEnd)
+		if apperr.IsBusiness(err, "time_conflict") {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		free[barberID] = barberCandidate{BarberID: barberID, Services: services, End: end}
+		freeIDs = append(freeIDs, barberID)
+	}
+
+	switch {
+	case !anyOffered:
+		return nil, apperr.ErrBusiness("service_not_offered")
+	case !anyWorking:
+		return nil, apperr.ErrBusiness("outside_working_hours")
+	case len(freeIDs) == 0:
+		return nil, apperr.ErrBusiness("time_conflict")
+	}
+
+	var ordered []uint
+	switch shop.BarberAssignmentStrategy {
+	case models.BarberAssignmentRoundRobin:
+		ordered, err = orderRoundRobin(ctx, repo, shop.ID, freeIDs)
+	case models.BarberAssignmentPreferred:
+		ordered, err = orderPreferred(ctx, repo, shop.ID, clientID, freeIDs, start, loc)
+	default:
+		ordered, err = orderLeastLoaded(ctx, repo, shop.ID, freeIDs, start, loc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]barberCandidate, 0, len(ordered))
+	for _, barberID := range ordered {
+		candidates = append(candidates, free[barberID])
+	}
+	return candidates, nil
+}
+
+// orderLeastLoaded ordena pelo número de agendamentos ativos no dia
+// (menos ocupado primeiro); empates mantêm a ordem de cadastro.
+func orderLeastLoaded(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	barberIDs []uint,
+	start time.Time,
+	loc *time.Location,
+) ([]uint, error) {
+	startLocal := start.In(loc)
+	dayStart := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	load := make(map[uint]int, len(barberIDs))
+	for _, barberID := range barberIDs {
+		aps, err := repo.ListAppointmentsForDay(ctx, barbershopID, barberID, dayStart.UTC(), dayEnd.UTC())
+		if err != nil {
+			return nil, err
+		}
+		load[barberID] = len(aps)
+	}
+
+	ordered := append([]uint(nil), barberIDs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i]] < load[ordered[j]]
+	})
+	return ordered, nil
+}
+
+// orderRoundRobin começa pelo barbeiro seguinte (em ordem de cadastro) ao que
+// recebeu a última atribuição automática da barbearia.
+func orderRoundRobin(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	barberIDs []uint,
+) ([]uint, error) {
+	lastID, err := repo.GetLastAutoAssignedBarberID(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := 0
+	for i, barberID := range barberIDs {
+		if barberID > lastID {
+			next = i
+			break
+		}
+	}
+
+	return append(append([]uint(nil), barberIDs[next:]...), barberIDs[:next]...), nil
+}
+
+// orderPreferred coloca na frente o barbeiro que mais atendeu o cliente; os
+// demais (ou todos, sem histórico) seguem a ordem de menor carga.
+func orderPreferred(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID, clientID uint,
+	barberIDs []uint,
+	start time.Time,
+	loc *time.Location,
+) ([]uint, error) {
+	ordered, err := orderLeastLoaded(ctx, repo, barbershopID, barberIDs, start, loc)
+	if err != nil || clientID == 0 {
+		return ordered, err
+	}
+
+	preferredID, err := repo.GetPreferredBarberID(ctx, barbershopID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, barberID := range ordered {
+		if barberID == preferredID {
+			copy(ordered[1:i+1], ordered[:i])
+			ordered[0] = preferredID
+			break
+		}
+	}
+	return ordered, nil
+}
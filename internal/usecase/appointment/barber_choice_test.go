@@ -0,0 +1,133 @@
+package appointment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+func TestRankAvailableBarbers(t *testing.T) {
+	ctx := context.Background()
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	start := time.Date(2030, 6, 10, 10, 0, 0, 0, loc)
+
+	// Barbeiro 1 tem dois atendimentos no dia, 2 e 3 nenhum.
+	busyDay := map[uint][]models.Appointment{
+		1: {{ID: 10}, {ID: 11}},
+		2: {},
+		3: {},
+	}
+
+	newRepo := func(strategy string) *mockRepo {
+		shop := defaultShop()
+		shop.BarberAssignmentStrategy = strategy
+		return &mockRepo{
+			shop:                 shop,
+			workingHours:         defaultWorkingHours(),
+			barberIDs:            []uint{1, 2, 3},
+			appointmentsByBarber: busyDay,
+		}
+	}
+
+	t.Run("least_loaded: menos ocupado primeiro, empate em ordem de cadastro", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentLeastLoaded)
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 0, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{2, 3, 1})
+	})
+
+	t.Run("round_robin: começa pelo seguinte ao último atribuído", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentRoundRobin)
+		repo.lastAutoBarberID = 2
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 0, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{3, 1, 2})
+	})
+
+	t.Run("round_robin: volta ao início depois do último barbeiro", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentRoundRobin)
+		repo.lastAutoBarberID = 3
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 0, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{1, 2, 3})
+	})
+
+	t.Run("preferred: barbeiro habitual do cliente na frente", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentPreferred)
+		repo.preferredBarberID = 1
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 7, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{1, 2, 3})
+	})
+
+	t.Run("preferred: sem histórico cai no menos ocupado", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentPreferred)
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 7, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{2, 3, 1})
+	})
+
+	t.Run("catálogo por barbeiro: pula quem não faz o serviço e usa a duração própria", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentLeastLoaded)
+		duration := 90
+		repo.overrideByBarber = map[uint]*models.BarberServiceOverride{
+			2: {Offered: false},
+			3: {Offered: true, DurationMin: &duration},
+		}
+
+		got, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 0, start, loc)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		assertBarberOrder(t, got, []uint{3, 1})
+		if !got[0].End.Equal(start.Add(90 * time.Minute)) {
+			t.Errorf("fim = %v, esperado duração de 90 min", got[0].End)
+		}
+	})
+
+	t.Run("catálogo por barbeiro: ninguém faz o serviço retorna service_not_offered", func(t *testing.T) {
+		repo := newRepo(models.BarberAssignmentLeastLoaded)
+		repo.overrideByBarber = map[uint]*models.BarberServiceOverride{
+			1: {Offered: false}, 2: {Offered: false}, 3: {Offered: false},
+		}
+
+		_, err := rankAvailableBarbers(ctx, repo, repo.shop, []*models.BarbershopService{defaultProduct()}, 0, start, loc)
+		if !apperr.IsBusiness(err, "service_not_offered") {
+			t.Fatalf("esperado service_not_offered, obtido: %v", err)
+		}
+	})
+}
+
+func assertBarberOrder(t *testing.T, candidates []barberCandidate, want []uint) {
+	t.Helper()
+	got := make([]uint, 0, len(candidates))
+	for _, c := range candidates {
+		got = append(got, c.BarberID)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ordem = %v, esperado %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ordem = %v, esperado %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package appointment
+
+import (
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// chargeableLines são os serviços cobrados no agendamento: os não cobertos
+// pela assinatura, no preço do barbeiro (ou rateados pelo combo).
+func chargeableLines(lines []models.AppointmentService) []couponDomain.Line {
+	out := make([]couponDomain.Line, 0, len(lines))
+	for _, line := range lines {
+		if line.CoverageStatus == models.CoverageStatusCovered || line.ServiceID == nil {
+			continue
+		}
+		out = append(out, couponDomain.Line{ItemID: *line.ServiceID, AmountCents: line.PriceCents})
+	}
+	return out
+}
+
+// bookingDiscount calcula o desconto do cupom sobre os serviços cobrados.
+func bookingDiscount(c *models.Coupon, lines []models.AppointmentService) (discount int64, charge int64, err error) {
+	purchase := couponDomain.Purchase{
+		Kind:  models.CouponAppliesToServices,
+		Lines: chargeableLines(lines),
+	}
+	discount, err = couponDomain.Discount(c, purchase)
+	return discount, purchase.Total(), err
+}
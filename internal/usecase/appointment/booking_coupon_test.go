@@ -0,0 +1,36 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+func TestBookingDiscount_IgnoresCoveredLines(t *testing.T) {
+	corte, barba := uint(1), uint(2)
+	lines := []models.AppointmentService{
+		{ServiceID: &corte, PriceCents: 5000, CoverageStatus: models.CoverageStatusCovered},
+		{ServiceID: &barba, PriceCents: 3000, CoverageStatus: models.CoverageStatusNotCoveredService},
+	}
+
+	coupon := &models.Coupon{
+		DiscountType:  models.CouponDiscountPercent,
+		DiscountValue: 50,
+		AppliesTo:     models.CouponAppliesToServices,
+	}
+	discount, charge, err := bookingDiscount(coupon, lines)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if charge != 3000 || discount != 1500 {
+		t.Errorf("esperado cobrança 3000 e desconto 1500, obtido %d e %d", charge, discount)
+	}
+
+	// Cupom só do corte: o corte já está coberto pela assinatura.
+	coupon.TargetIDs = []uint{corte}
+	if _, _, err := bookingDiscount(coupon, lines); !errors.Is(err, couponDomain.ErrNotApplicable) {
+		t.Errorf("esperado ErrNotApplicable, obtido %v", err)
+	}
+}
@@ -9,8 +9,10 @@ import (
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 )
@@ -22,6 +24,9 @@ type CancelAppointment struct {
 	audit            *audit.Dispatcher
 	metrics          *ucMetrics.UpdateClientMetrics
 	releaseUC        *ucSubscription.ReleaseSubscriptionCut
+	waitlist         domainWaitlist.SlotListener
+	calendar         domain.CalendarSync
+	coupons          txableCouponRepo
 }
 
 func NewCancelAppointment(
@@ -42,6 +47,24 @@ func NewCancelAppointment(
 	}
 }
 
+// WithWaitlist oferece o horário liberado à lista de espera após o cancelamento.
+func (uc *CancelAppointment) WithWaitlist(l domainWaitlist.SlotListener) *CancelAppointment {
+	uc.waitlist = l
+	return uc
+}
+
+// WithCoupons devolve ao cupom o uso do agendamento cancelado.
+func (uc *CancelAppointment) WithCoupons(r txableCouponRepo) *CancelAppointment {
+	uc.coupons = r
+	return uc
+}
+
+// WithCalendarSync remove o evento espelhado no Google Calendar do barbeiro.
+func (uc *CancelAppointment) WithCalendarSync(c domain.CalendarSync) *CancelAppointment {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *CancelAppointment) Execute(
 	ctx context.Context,
 	barbershopID uint,
@@ -75,13 +98,48 @@ func (uc *CancelAppointment) Execute(
 			return err
 		}
 
-		// Liberar reserva de assinatura dentro da mesma transação.
-		// Em caso de falha (ex.: período expirado), loga e segue — o cancelamento
-		// não deve ser bloqueado por falha no release.
+		// Liberar reserva de assinatura dentro da mesma transação (um corte
+		// por serviço coberto). Em caso de falha (ex.: período expirado), loga
+		// e segue — o cancelamento não deve ser bloqueado por falha no release.
 		if ap.ReservedSubscriptionCut && ap.ClientID != nil && ap.BarbershopID != nil && uc.releaseUC != nil {
 			txSubRepo := uc.subscriptionRepo.WithTx(tx)
-			if err := uc.releaseUC.Execute(ctx, *ap.BarbershopID, *ap.ClientID, txSubRepo); err != nil {
-				log.Printf("[CancelAppointment] release subscription cut failed for client %d: %v", *ap.ClientID, err)
+			for i := 0; i < ap.ReservedCuts(); i++ {
+				if err := uc.releaseUC.Execute(ctx, *ap.BarbershopID, *ap.ClientID, txSubRepo); err != nil {
+					log.Printf("[CancelAppointment] release subscription cut failed for client %d: %v", *ap.ClientID, err)
+					break
+				}
+			}
+		}
+
+		if uc.coupons != nil {
+			if err := uc.coupons.WithTx(tx).VoidForAppointment(ctx, ap.ID); err != nil {
+				return err
+			}
+		}
+
+		// Auditoria, agenda e lista de espera entram na mesma transação pelo
+		// outbox.
+		octx := outbox.ContextWithTx(ctx, tx)
+		if err := uc.audit.DispatchContext(octx, audit.Event{
+			BarbershopID: barbershopID,
+			UserID:       &barberID,
+			Action:       "appointment_cancelled",
+			Entity:       "appointment",
+			EntityID:     &ap.ID,
+		}); err != nil {
+			return err
+		}
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(octx, barbershopID, ap.ID)
+		}
+		if uc.waitlist != nil && ap.BarberID != nil {
+			if err := uc.waitlist.SlotFreed(octx, domainWaitlist.FreedSlot{
+				BarbershopID: barbershopID,
+				BarberID:     *ap.BarberID,
+				StartTime:    ap.StartTime,
+				EndTime:      ap.EndTime,
+			}); err != nil {
+				return err
 			}
 		}
 
@@ -92,14 +150,6 @@ func (uc *CancelAppointment) Execute(
 		return nil, err
 	}
 
-	uc.audit.Dispatch(audit.Event{
-		BarbershopID: barbershopID,
-		UserID:       &barberID,
-		Action:       "appointment_cancelled",
-		Entity:       "appointment",
-		EntityID:     &ap.ID,
-	})
-
 	if ap.ClientID != nil {
 		_ = uc.metrics.Execute(ctx, ucMetrics.UpdateClientMetricsInput{
 			BarbershopID: barbershopID,
@@ -0,0 +1,84 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeCouponVoider registra os agendamentos cujo uso de cupom foi anulado
+// e se a chamada veio com a transação do cancelamento.
+type fakeCouponVoider struct {
+	couponDomain.Repository
+	voided []uint
+	withTx bool
+	err    error
+}
+
+func (f *fakeCouponVoider) WithTx(tx *gorm.DB) couponDomain.Repository {
+	f.withTx = tx != nil
+	return f
+}
+
+func (f *fakeCouponVoider) VoidForAppointment(_ context.Context, appointmentID uint) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.voided = append(f.voided, appointmentID)
+	return nil
+}
+
+func newCancelWithCoupons(t *testing.T, ap *models.Appointment, coupons *fakeCouponVoider) *CancelAppointment {
+	t.Helper()
+	repo := &mockCompleteAppointmentRepo{appointment: ap}
+	return NewCancelAppointment(newTestCompleteDB(t), repo, nil, newTestCompleteDispatcher(t), nil, nil).
+		WithCoupons(coupons)
+}
+
+func TestCancelAppointment_VoidsCouponInTransaction(t *testing.T) {
+	shopID, barberID := uint(1), uint(2)
+	ap := &models.Appointment{
+		ID:           10,
+		BarbershopID: &shopID,
+		BarberID:     &barberID,
+		Status:       models.AppointmentStatusScheduled,
+		StartTime:    time.Now().Add(48 * time.Hour),
+		EndTime:      time.Now().Add(49 * time.Hour),
+	}
+	coupons := &fakeCouponVoider{}
+
+	got, err := newCancelWithCoupons(t, ap, coupons).Execute(context.Background(), shopID, barberID, ap.ID)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got.Status != models.AppointmentStatusCancelled {
+		t.Errorf("esperado cancelled, obtido %s", got.Status)
+	}
+	if len(coupons.voided) != 1 || coupons.voided[0] != ap.ID {
+		t.Errorf("esperado cupom do agendamento %d anulado, obtido %v", ap.ID, coupons.voided)
+	}
+	if !coupons.withTx {
+		t.Error("cupom deveria ser anulado na transação do cancelamento")
+	}
+}
+
+func TestCancelAppointment_CouponVoidFailureAbortsCancel(t *testing.T) {
+	shopID, barberID := uint(1), uint(2)
+	ap := &models.Appointment{
+		ID:           11,
+		BarbershopID: &shopID,
+		BarberID:     &barberID,
+		Status:       models.AppointmentStatusScheduled,
+	}
+	coupons := &fakeCouponVoider{err: errors.New("lock timeout")}
+
+	if _, err := newCancelWithCoupons(t, ap, coupons).Execute(context.Background(), shopID, barberID, ap.ID); err == nil {
+		t.Fatal("esperado erro: cancelamento e devolução do cupom são atômicos")
+	}
+}
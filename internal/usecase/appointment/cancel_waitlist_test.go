@@ -0,0 +1,71 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeSlotListener registra os horários liberados ou falha como o outbox
+// quando a gravação do evento não entra.
+type fakeSlotListener struct {
+	freed []domainWaitlist.FreedSlot
+	err   error
+}
+
+func (f *fakeSlotListener) SlotFreed(_ context.Context, slot domainWaitlist.FreedSlot) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.freed = append(f.freed, slot)
+	return nil
+}
+
+func newCancelWithWaitlist(t *testing.T, ap *models.Appointment, listener *fakeSlotListener) *CancelAppointment {
+	t.Helper()
+	repo := &mockCompleteAppointmentRepo{appointment: ap}
+	return NewCancelAppointment(newTestCompleteDB(t), repo, nil, newTestCompleteDispatcher(t), nil, nil).
+		WithWaitlist(listener)
+}
+
+func TestCancelAppointment_FreesSlotForWaitlist(t *testing.T) {
+	shopID, barberID := uint(1), uint(2)
+	start := time.Now().Add(48 * time.Hour)
+	ap := &models.Appointment{
+		ID:           20,
+		BarbershopID: &shopID,
+		BarberID:     &barberID,
+		Status:       models.AppointmentStatusScheduled,
+		StartTime:    start,
+		EndTime:      start.Add(time.Hour),
+	}
+	listener := &fakeSlotListener{}
+
+	if _, err := newCancelWithWaitlist(t, ap, listener).Execute(context.Background(), shopID, barberID, ap.ID); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(listener.freed) != 1 || listener.freed[0].BarberID != barberID || !listener.freed[0].StartTime.Equal(start) {
+		t.Errorf("esperado horário liberado do barbeiro %d às %s, obtido %+v", barberID, start, listener.freed)
+	}
+}
+
+func TestCancelAppointment_SlotFreedFailureAbortsCancel(t *testing.T) {
+	shopID, barberID := uint(1), uint(2)
+	ap := &models.Appointment{
+		ID:           21,
+		BarbershopID: &shopID,
+		BarberID:     &barberID,
+		Status:       models.AppointmentStatusScheduled,
+		StartTime:    time.Now().Add(48 * time.Hour),
+		EndTime:      time.Now().Add(49 * time.Hour),
+	}
+	listener := &fakeSlotListener{err: errors.New("outbox indisponível")}
+
+	if _, err := newCancelWithWaitlist(t, ap, listener).Execute(context.Background(), shopID, barberID, ap.ID); err == nil {
+		t.Fatal("esperado erro: cancelamento e aviso à lista de espera são atômicos")
+	}
+}
@@ -0,0 +1,30 @@
+package appointment
+
+import "testing"
+
+func TestLoyaltyChargeCents(t *testing.T) {
+	final := int64(6000)
+
+	cases := []struct {
+		name                string
+		final               *int64
+		subscriptionCovered bool
+		subscriptionCents   int64
+		packageCents        int64
+		want                int64
+	}{
+		{"sem valor final usa a referência", nil, false, 0, 0, 8000},
+		{"valor final informado", &final, false, 0, 0, 6000},
+		{"desconta o coberto pela assinatura e pelo pacote", &final, false, 2000, 1500, 2500},
+		{"coberto pela assinatura não pontua", &final, true, 0, 0, 0},
+		{"cobertura maior que o valor", &final, false, 5000, 3000, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := loyaltyChargeCents(tc.final, 8000, tc.subscriptionCovered, tc.subscriptionCents, tc.packageCents)
+			if got != tc.want {
+				t.Errorf("esperado %d, obtido %d", tc.want, got)
+			}
+		})
+	}
+}
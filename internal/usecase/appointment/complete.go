@@ -2,20 +2,27 @@ package appointment
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	inventoryDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
 	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
 	productDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
+	domainLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	domainPackage "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
 	domainSubscription "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 )
@@ -34,16 +41,32 @@ type txableSubscriptionRepo interface {
 	WithTx(tx *gorm.DB) *infraRepo.SubscriptionGormRepository
 }
 
+// txablePackageRepo vincula o repositório de pacotes à transação do
+// fechamento, para o débito de crédito ser revertido junto.
+type txablePackageRepo interface {
+	domainPackage.Repository
+	WithTx(tx *gorm.DB) domainPackage.Repository
+}
+
+// txableCouponRepo vincula o repositório de cupons à transação do
+// cancelamento, para o uso do cupom ser devolvido junto.
+type txableCouponRepo interface {
+	WithTx(tx *gorm.DB) couponDomain.Repository
+}
+
 type CompleteAppointment struct {
 	db               *gorm.DB
 	repo             txableRepository
 	paymentRepo      domainPayment.Repository
 	orderRepo        *infraRepo.OrderGormRepository
 	productRepo      *infraRepo.ProductGormRepository
+	inventoryRepo    *infraRepo.InventoryGormRepository
 	subscriptionRepo txableSubscriptionRepo
 	audit            *audit.Dispatcher
 	metrics          *ucMetrics.UpdateClientMetrics
 	consumeCutUC     *ucSubscription.ConsumeCut
+	packageRepo      txablePackageRepo
+	loyalty          *ucLoyalty.Ledger
 }
 
 func NewCompleteAppointment(
@@ -52,6 +75,7 @@ func NewCompleteAppointment(
 	paymentRepo domainPayment.Repository,
 	orderRepo *infraRepo.OrderGormRepository,
 	productRepo *infraRepo.ProductGormRepository,
+	inventoryRepo *infraRepo.InventoryGormRepository,
 	subscriptionRepo txableSubscriptionRepo,
 	audit *audit.Dispatcher,
 	metrics *ucMetrics.UpdateClientMetrics,
@@ -63,6 +87,7 @@ func NewCompleteAppointment(
 		paymentRepo:      paymentRepo,
 		orderRepo:        orderRepo,
 		productRepo:      productRepo,
+		inventoryRepo:    inventoryRepo,
 		subscriptionRepo: subscriptionRepo,
 		audit:            audit,
 		metrics:          metrics,
@@ -70,6 +95,20 @@ func NewCompleteAppointment(
 	}
 }
 
+// WithPackages debita crédito de pacote pré-pago do cliente nos serviços
+// que a assinatura não cobriu.
+func (uc *CompleteAppointment) WithPackages(repo txablePackageRepo) *CompleteAppointment {
+	uc.packageRepo = repo
+	return uc
+}
+
+// WithLoyalty credita pontos de fidelidade pelo valor pago e permite trocar
+// uma recompensa no fechamento.
+func (uc *CompleteAppointment) WithLoyalty(ledger *ucLoyalty.Ledger) *CompleteAppointment {
+	uc.loyalty = ledger
+	return uc
+}
+
 // ClosureItemInput is a product sold during the appointment (venda adicional).
 type ClosureItemInput struct {
 	ProductID uint
@@ -90,7 +129,7 @@ type CompleteAppointmentInput struct {
 	// Venda adicional de produtos durante o atendimento.
 	AdditionalItems []ClosureItemInput
 
-	// Forma de pagamento real: "cash" | "card" | "pix" | "subscription".
+	// Forma de pagamento real: "cash" | "card" | "pix" | "subscription" | "package".
 	PaymentMethod string
 
 	// O item previsto (suggestion) foi removido/não utilizado.
@@ -98,6 +137,9 @@ type CompleteAppointmentInput struct {
 
 	OperationalNote       string
 	ConfirmNormalCharging bool
+
+	// Recompensa de fidelidade trocada neste atendimento (opcional).
+	LoyaltyRewardID *uint
 }
 
 func (uc *CompleteAppointment) Execute(
@@ -114,6 +156,8 @@ func (uc *CompleteAppointment) Execute(
 		closure          *models.AppointmentClosure
 		consumeCutResult *ucSubscription.ConsumeCutResult
 		referenceAmount  int64
+		loyaltyReward    *models.LoyaltyReward
+		loyaltyDiscount  int64
 	)
 
 	err := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -137,6 +181,14 @@ func (uc *CompleteAppointment) Execute(
 		// significa que recebeu o pagamento por outro meio (dinheiro, cartão, etc.).
 		// Não bloqueia — o método de pagamento selecionado no modal é registrado normalmente.
 
+		// Vários serviços: a troca de serviço realizado só vale para agendamento
+		// de um serviço; o valor final (FinalAmountCents) cobre os ajustes.
+		multiService := len(ap.Services) > 1
+		if multiService && input.ActualServiceID != nil &&
+			(ap.BarberProductID == nil || *input.ActualServiceID != *ap.BarberProductID) {
+			return apperr.ErrBusiness("actual_service_requires_single_service")
+		}
+
 		// Resolves the actual service: use ActualServiceID if provided, else the scheduled one.
 		actualServiceID := ap.BarberProductID
 		actualServiceName := ""
@@ -159,6 +211,38 @@ func (uc *CompleteAppointment) Execute(
 			referenceAmount = svc.Price
 		}
 
+		// Catálogo por barbeiro: o valor de referência é o preço do barbeiro
+		// que atendeu, quando ele tem preço próprio para o serviço.
+		if ap.BarberID != nil && actualServiceID != nil {
+			override, err := txRepo.GetBarberServiceOverride(ctx, barbershopID, *ap.BarberID, *actualServiceID)
+			if err != nil {
+				return err
+			}
+			if override != nil && override.Price != nil {
+				referenceAmount = *override.Price
+			}
+		}
+
+		// Vários serviços: o valor de referência é a soma do snapshot das linhas
+		// (preço do barbeiro no booking).
+		if multiService {
+			referenceAmount = 0
+			for _, line := range ap.Services {
+				referenceAmount += line.PriceCents
+			}
+			actualServiceName = serviceLinesName(ap.Services)
+		}
+
+		// Cupom do booking: sem valor final informado, o atendimento fecha
+		// com o desconto aplicado.
+		if input.FinalAmountCents == nil && ap.DiscountCents > 0 {
+			final := referenceAmount - ap.DiscountCents
+			if final < 0 {
+				final = 0
+			}
+			input.FinalAmountCents = &final
+		}
+
 		// Consume subscription cut only when a cut was explicitly reserved at
 		// booking time. Appointments created without subscription coverage
 		// (ReservedSubscriptionCut = false) complete under normal charging
@@ -166,7 +250,16 @@ func (uc *CompleteAppointment) Execute(
 		//
 		// O repo é vinculado ao tx para que o consumo/liberação seja revertido
 		// junto com o restante da transação em caso de falha.
-		if ap.ReservedSubscriptionCut && ap.ClientID != nil && actualServiceID != nil && uc.consumeCutUC != nil {
+		var linesCoveredCents int64
+		var linesCovered []bool
+		if multiService {
+			if ap.ReservedSubscriptionCut && ap.ClientID != nil && uc.consumeCutUC != nil {
+				consumeCutResult, linesCoveredCents, linesCovered, err = uc.consumeServiceLines(ctx, tx, ap, barbershopID)
+				if err != nil {
+					return err
+				}
+			}
+		} else if ap.ReservedSubscriptionCut && ap.ClientID != nil && actualServiceID != nil && uc.consumeCutUC != nil {
 			txSubRepo := uc.subscriptionRepo.WithTx(tx)
 
 			// Detecta se o barbeiro trocou o serviço realizado em relação ao agendado.
@@ -239,6 +332,25 @@ func (uc *CompleteAppointment) Execute(
 
 		now := time.Now().UTC()
 
+		// Pacote pré-pago: cada serviço que a assinatura não cobriu consome um
+		// crédito de pacote do cliente.
+		var pkgUsage packageUsage
+		if uc.packageRepo != nil && ap.ClientID != nil {
+			pending := packagePendingLines(ap, multiService, actualServiceID, referenceAmount, consumeCutResult, linesCovered)
+			if len(pending) > 0 {
+				paid, err := appointmentPaidOnline(ctx, tx, barbershopID, ap.ID)
+				if err != nil {
+					return err
+				}
+				if !paid {
+					pkgUsage, err = uc.consumePackageCredits(ctx, tx, barbershopID, *ap.ClientID, pending, now)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+
 		// Captura o status antes de domain.Complete modificá-lo.
 		wasAwaitingPayment := ap.Status == models.AppointmentStatus(domain.StatusAwaitingPayment)
 
@@ -285,12 +397,74 @@ func (uc *CompleteAppointment) Execute(
 			}
 		}
 
+		// Vários serviços: coberto só quando todos os serviços foram cobertos;
+		// senão, registra a parte coberta para o financeiro.
+		var subscriptionCoveredCents int64
+		if multiService && consumeCutResult != nil {
+			linesAllCovered := allLinesCovered(linesCovered)
+			subscriptionCovered = linesAllCovered
+			if !linesAllCovered {
+				subscriptionCoveredCents = linesCoveredCents
+			}
+		}
+
+		// O pacote cobriu tudo o que a assinatura não cobriu: nada a cobrar.
+		paymentMethod := input.PaymentMethod
+		if pkgUsage.AllCovered {
+			requiresNormalCharging = false
+			if paymentMethod == "" {
+				paymentMethod = "package"
+			}
+		}
+
 		if requiresNormalCharging && !input.ConfirmNormalCharging {
 			return apperr.ErrBusiness("normal_charging_confirmation_required")
 		}
 
+		// Fidelidade: a recompensa abate o que o cliente pagaria e os pontos
+		// saem do que ele efetivamente pagou.
+		var txLoyalty *ucLoyalty.Ledger
+		if uc.loyalty != nil && ap.ClientID != nil {
+			txLoyalty = uc.loyalty.WithTx(tx)
+		}
+		charged := loyaltyChargeCents(input.FinalAmountCents, referenceAmount, subscriptionCovered, subscriptionCoveredCents, pkgUsage.CoveredCents)
+		if input.LoyaltyRewardID != nil {
+			if txLoyalty == nil {
+				return domainLoyalty.ErrRequiresClient
+			}
+			pending := packagePendingLines(ap, multiService, actualServiceID, referenceAmount, consumeCutResult, linesCovered)
+			lines := make([]domainLoyalty.RewardLine, 0, len(pending))
+			for _, l := range pending {
+				lines = append(lines, domainLoyalty.RewardLine{ServiceID: l.ServiceID, AmountCents: l.PriceCents})
+			}
+
+			loyaltyReward, loyaltyDiscount, err = txLoyalty.Redeem(ctx, ucLoyalty.RedeemInput{
+				BarbershopID:  barbershopID,
+				ClientID:      *ap.ClientID,
+				AppointmentID: ap.ID,
+				RewardID:      *input.LoyaltyRewardID,
+				Lines:         lines,
+				ChargeCents:   charged,
+			})
+			if err != nil {
+				return err
+			}
+
+			final := referenceAmount
+			if input.FinalAmountCents != nil {
+				final = *input.FinalAmountCents
+			}
+			final -= loyaltyDiscount
+			if final < 0 {
+				final = 0
+			}
+			input.FinalAmountCents = &final
+			charged -= loyaltyDiscount
+		}
+
 		// Venda adicional — cria Order dentro da mesma transação.
 		var additionalOrderID *uint
+		var additionalOrderTotal int64
 		if len(input.AdditionalItems) > 0 {
 			txOrderRepo := uc.orderRepo.WithTx(tx)
 			txProductRepo := uc.productRepo.WithTx(tx)
@@ -321,13 +495,25 @@ func (uc *CompleteAppointment) Execute(
 				return err
 			}
 
+			// Baixa no estoque pelo razão, vinculada ao pedido e ao atendimento.
+			movements := make([]*models.InventoryMovement, 0, len(input.AdditionalItems))
 			for _, item := range input.AdditionalItems {
-				if err := txProductRepo.DecreaseStock(ctx, barbershopID, item.ProductID, item.Quantity); err != nil {
-					return err
-				}
+				movements = append(movements, &models.InventoryMovement{
+					BarbershopID:  barbershopID,
+					ProductID:     item.ProductID,
+					Type:          inventoryDomain.MovementClosureAddon,
+					Quantity:      -item.Quantity,
+					OrderID:       &order.ID,
+					AppointmentID: &ap.ID,
+					UserID:        &barberID,
+				})
+			}
+			if err := uc.inventoryRepo.WithTx(tx).Record(ctx, movements); err != nil {
+				return err
 			}
 
 			additionalOrderID = &order.ID
+			additionalOrderTotal = order.TotalAmount
 		}
 
 		closure = &models.AppointmentClosure{
@@ -335,6 +521,9 @@ func (uc *CompleteAppointment) Execute(
 			BarbershopID:              barbershopID,
 			ServiceID:                 ap.BarberProductID,
 			ServiceName:               func() string {
+				if multiService {
+					return serviceLinesName(ap.Services)
+				}
 				if ap.BarberProduct != nil {
 					return ap.BarberProduct.Name
 				}
@@ -345,20 +534,45 @@ func (uc *CompleteAppointment) Execute(
 			SubscriptionConsumeStatus: subscriptionConsumeStatus,
 			SubscriptionPlanID:        subscriptionPlanID,
 			SubscriptionCovered:       subscriptionCovered,
+			SubscriptionCoveredCents:  subscriptionCoveredCents,
 			RequiresNormalCharging:    requiresNormalCharging,
 			ConfirmNormalCharging:     input.ConfirmNormalCharging,
 			OperationalNote:           input.OperationalNote,
 			ActualServiceID:           actualServiceID,
 			ActualServiceName:         actualServiceName,
-			PaymentMethod:             input.PaymentMethod,
+			PaymentMethod:             paymentMethod,
 			AdditionalOrderID:         additionalOrderID,
 			SuggestionRemoved:         input.SuggestionRemoved,
+			ClientPackageID:           pkgUsage.ClientPackageID,
+			PackageCreditsUsed:        pkgUsage.Credits,
+			PackageCoveredCents:       pkgUsage.CoveredCents,
 		}
 
 		if err := txRepo.SaveAppointmentClosure(ctx, closure); err != nil {
 			return err
 		}
 
+		if txLoyalty != nil {
+			if err := txLoyalty.Earn(ctx, ucLoyalty.EarnInput{
+				BarbershopID:  barbershopID,
+				ClientID:      *ap.ClientID,
+				AppointmentID: &ap.ID,
+				AmountCents:   charged,
+			}); err != nil {
+				return err
+			}
+			if additionalOrderID != nil {
+				if err := txLoyalty.Earn(ctx, ucLoyalty.EarnInput{
+					BarbershopID: barbershopID,
+					ClientID:     *ap.ClientID,
+					OrderID:      additionalOrderID,
+					AmountCents:  additionalOrderTotal,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
 		return nil
 	})
 
@@ -399,6 +613,15 @@ func (uc *CompleteAppointment) Execute(
 	if closure != nil && closure.AdditionalOrderID != nil {
 		metadata["additional_order_id"] = *closure.AdditionalOrderID
 	}
+	if closure != nil && closure.ClientPackageID != nil {
+		metadata["client_package_id"] = *closure.ClientPackageID
+		metadata["package_credits_used"] = closure.PackageCreditsUsed
+	}
+	if loyaltyReward != nil {
+		metadata["loyalty_reward_id"] = loyaltyReward.ID
+		metadata["loyalty_points_spent"] = loyaltyReward.PointsCost
+		metadata["loyalty_discount_cents"] = loyaltyDiscount
+	}
 
 	uc.audit.Dispatch(audit.Event{
 		BarbershopID: barbershopID,
@@ -432,3 +655,206 @@ func (uc *CompleteAppointment) Execute(
 
 	return ap, closure, consumeCutResult, nil
 }
+
+// consumeServiceLines consome um corte por serviço reservado no booking.
+// Retorna o resultado agregado (consumed quando todos os reservados foram
+// consumidos; senão, o primeiro status de falha), o valor dos serviços
+// cobertos e quais serviços (por posição) ficaram cobertos.
+func (uc *CompleteAppointment) consumeServiceLines(
+	ctx context.Context,
+	tx *gorm.DB,
+	ap *models.Appointment,
+	barbershopID uint,
+) (*ucSubscription.ConsumeCutResult, int64, []bool, error) {
+	txSubRepo := uc.subscriptionRepo.WithTx(tx)
+
+	var (
+		aggregated   *ucSubscription.ConsumeCutResult
+		coveredCents int64
+	)
+	covered := make([]bool, len(ap.Services))
+
+	for i, line := range ap.Services {
+		if !line.ReservedCut {
+			continue
+		}
+
+		if line.ServiceID == nil {
+			// Serviço removido do catálogo depois do booking: devolve a reserva.
+			if err := txSubRepo.ReleaseSubscriptionCut(ctx, barbershopID, *ap.ClientID); err != nil {
+				log.Printf("[CompleteAppointment] release cut of removed service failed client=%d: %v",
+					*ap.ClientID, err)
+			}
+			continue
+		}
+
+		result, err := uc.consumeCutUC.Execute(ctx, barbershopID, *ap.ClientID, *line.ServiceID, true, txSubRepo)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		if result.Status == ucSubscription.ConsumeCutStatusConsumed {
+			coveredCents += line.PriceCents
+			covered[i] = true
+			if aggregated == nil {
+				aggregated = result
+			}
+			continue
+		}
+
+		if aggregated == nil || aggregated.Status == ucSubscription.ConsumeCutStatusConsumed {
+			aggregated = result
+		}
+	}
+
+	return aggregated, coveredCents, covered, nil
+}
+
+// allLinesCovered indica se todos os serviços foram cobertos.
+func allLinesCovered(covered []bool) bool {
+	if len(covered) == 0 {
+		return false
+	}
+	for _, c := range covered {
+		if !c {
+			return false
+		}
+	}
+	return true
+}
+
+// packageLine é um serviço do atendimento que pode consumir crédito de pacote.
+type packageLine struct {
+	ServiceID  uint
+	PriceCents int64
+}
+
+// packageUsage resume o consumo de pacote no fechamento. ClientPackageID é o
+// primeiro pacote debitado; AllCovered indica que o pacote cobriu todos os
+// serviços pendentes.
+type packageUsage struct {
+	ClientPackageID *uint
+	Credits         int
+	CoveredCents    int64
+	AllCovered      bool
+}
+
+// packagePendingLines devolve os serviços que a assinatura não cobriu.
+func packagePendingLines(
+	ap *models.Appointment,
+	multiService bool,
+	actualServiceID *uint,
+	referenceAmount int64,
+	consumeCutResult *ucSubscription.ConsumeCutResult,
+	linesCovered []bool,
+) []packageLine {
+	if !multiService {
+		if actualServiceID == nil {
+			return nil
+		}
+		if consumeCutResult != nil && consumeCutResult.Status == ucSubscription.ConsumeCutStatusConsumed {
+			return nil
+		}
+		return []packageLine{{ServiceID: *actualServiceID, PriceCents: referenceAmount}}
+	}
+
+	lines := make([]packageLine, 0, len(ap.Services))
+	for i, line := range ap.Services {
+		if i < len(linesCovered) && linesCovered[i] {
+			continue
+		}
+		if line.ServiceID == nil {
+			continue
+		}
+		lines = append(lines, packageLine{ServiceID: *line.ServiceID, PriceCents: line.PriceCents})
+	}
+	return lines
+}
+
+// appointmentPaidOnline indica se o agendamento já foi pago pelo fluxo de
+// pagamento (PIX/cartão) — nesse caso não se debita crédito de pacote.
+func appointmentPaidOnline(ctx context.Context, tx *gorm.DB, barbershopID, appointmentID uint) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&models.Payment{}).
+		Where("barbershop_id = ? AND appointment_id = ? AND status = ?", barbershopID, appointmentID, "paid").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// consumePackageCredits debita um crédito por serviço, sempre do pacote que
+// vence primeiro entre os que cobrem o serviço.
+func (uc *CompleteAppointment) consumePackageCredits(
+	ctx context.Context,
+	tx *gorm.DB,
+	barbershopID, clientID uint,
+	lines []packageLine,
+	now time.Time,
+) (packageUsage, error) {
+	txPkgRepo := uc.packageRepo.WithTx(tx)
+	usage := packageUsage{AllCovered: true}
+
+	for _, line := range lines {
+		cp, err := txPkgRepo.FindUsableForUpdate(ctx, barbershopID, clientID, line.ServiceID, now)
+		if err != nil {
+			return packageUsage{}, err
+		}
+		if cp == nil {
+			usage.AllCovered = false
+			continue
+		}
+
+		if err := txPkgRepo.ConsumeCredit(ctx, cp.ID); err != nil {
+			if errors.Is(err, domainPackage.ErrNoCreditsLeft) {
+				usage.AllCovered = false
+				continue
+			}
+			return packageUsage{}, err
+		}
+
+		if usage.ClientPackageID == nil {
+			id := cp.ID
+			usage.ClientPackageID = &id
+		}
+		usage.Credits++
+		usage.CoveredCents += line.PriceCents
+	}
+
+	if usage.Credits == 0 {
+		usage.AllCovered = false
+	}
+	return usage, nil
+}
+
+// loyaltyChargeCents é o que o cliente paga pelo atendimento: o valor do
+// fechamento menos a parte coberta por assinatura e pacote (a mesma conta
+// da receita de serviços do financeiro).
+func loyaltyChargeCents(
+	final *int64,
+	reference int64,
+	subscriptionCovered bool,
+	subscriptionCoveredCents int64,
+	packageCoveredCents int64,
+) int64 {
+	if subscriptionCovered {
+		return 0
+	}
+	amount := reference
+	if final != nil {
+		amount = *final
+	}
+	amount -= subscriptionCoveredCents + packageCoveredCents
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
+
+// serviceLinesName junta os nomes dos serviços do agendamento ("Corte + Barba").
+func serviceLinesName(lines []models.AppointmentService) string {
+	names := make([]string, 0, len(lines))
+	for _, line := range lines {
+		names = append(names, line.ServiceName)
+	}
+	return strings.Join(names, " + ")
+}
@@ -105,6 +105,9 @@ func (r *mockCompleteAppointmentRepo) GetAppointmentForBarber(_ context.Context,
 func (r *mockCompleteAppointmentRepo) UpdateAppointment(_ context.Context, _ *models.Appointment) error {
 	return nil
 }
+func (r *mockCompleteAppointmentRepo) ListAppointmentServices(_ context.Context, _ uint) ([]models.AppointmentService, error) {
+	return nil, nil
+}
 func (r *mockCompleteAppointmentRepo) SaveAppointmentClosure(_ context.Context, c *models.AppointmentClosure) error {
 	r.savedClosure = c
 	return nil
@@ -114,18 +117,36 @@ func (r *mockCompleteAppointmentRepo) SaveAppointmentClosure(_ context.Context,
 func (r *mockCompleteAppointmentRepo) GetBarbershopByID(_ context.Context, _ uint) (*models.Barbershop, error) {
 	return &models.Barbershop{ID: 1, Timezone: "America/Sao_Paulo"}, nil
 }
+func (r *mockCompleteAppointmentRepo) GetCombo(_ context.Context, _, _ uint) (*models.ServiceCombo, error) {
+	return nil, nil
+}
 func (r *mockCompleteAppointmentRepo) GetProduct(_ context.Context, _, _ uint) (*models.BarbershopService, error) {
 	return nil, nil
 }
+func (r *mockCompleteAppointmentRepo) GetBarberServiceOverride(_ context.Context, _, _, _ uint) (*models.BarberServiceOverride, error) {
+	return nil, nil
+}
+func (r *mockCompleteAppointmentRepo) ListActiveBarberIDs(_ context.Context, _ uint) ([]uint, error) {
+	return nil, nil
+}
+func (r *mockCompleteAppointmentRepo) GetLastAutoAssignedBarberID(_ context.Context, _ uint) (uint, error) {
+	return 0, nil
+}
+func (r *mockCompleteAppointmentRepo) GetPreferredBarberID(_ context.Context, _, _ uint) (uint, error) {
+	return 0, nil
+}
 func (r *mockCompleteAppointmentRepo) GetOrCreateClient(_ context.Context, _ uint, _, _, _ string) (*models.Client, error) {
 	return nil, nil
 }
-func (r *mockCompleteAppointmentRepo) CreateAppointment(_ context.Context, _ *models.Appointment) error {
+func (r *mockCompleteAppointmentRepo) CreateAppointment(_ context.Context, _ *models.Appointment, _ func(context.Context, *gorm.DB) error) error {
 	return nil
 }
 func (r *mockCompleteAppointmentRepo) CreateAppointmentWithKey(_ context.Context, _ *models.Appointment, _ string) error {
 	return nil
 }
+func (r *mockCompleteAppointmentRepo) CreateAppointmentIfFree(_ context.Context, _ *models.Appointment, _, _ time.Time, _ string, _ func(context.Context, *gorm.DB) error) error {
+	return nil
+}
 func (r *mockCompleteAppointmentRepo) AssertNoTimeConflict(_ context.Context, _, _ uint, _, _ time.Time) error {
 	return nil
 }
@@ -141,6 +162,9 @@ func (r *mockCompleteAppointmentRepo) GetWorkingHours(_ context.Context, _, _ ui
 func (r *mockCompleteAppointmentRepo) GetScheduleOverride(_ context.Context, _, _ uint, _ string, _, _, _ int) (*models.ScheduleOverride, error) {
 	return nil, nil
 }
+func (r *mockCompleteAppointmentRepo) ListBusyPeriods(_ context.Context, _, _ uint, _, _ time.Time) ([]domain.BusyPeriod, error) {
+	return nil, nil
+}
 func (r *mockCompleteAppointmentRepo) ListAppointmentsForDay(_ context.Context, _, _ uint, _, _ time.Time) ([]models.Appointment, error) {
 	return nil, nil
 }
@@ -269,6 +293,7 @@ func buildCompleteUC(t *testing.T, apptRepo *mockCompleteAppointmentRepo) *Compl
 		nil, // paymentRepo — não usado quando status != awaiting_payment
 		nil, // orderRepo — não usado quando sem additional_items
 		nil, // productRepo — não usado quando sem additional_items
+		nil, // inventoryRepo — não usado quando sem additional_items
 		subRepo,
 		newTestCompleteDispatcher(t),
 		metricsUC,
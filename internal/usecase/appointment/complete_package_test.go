@@ -0,0 +1,113 @@
+package appointment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domainPackage "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// mockPackageRepo cobre só o fluxo de débito do fechamento; os demais
+// métodos do repositório ficam no Repository embutido (nil).
+type mockPackageRepo struct {
+	domainPackage.Repository
+
+	// usable: serviceID → pacote que cobre o serviço.
+	usable   map[uint]*models.ClientPackage
+	consumed []uint
+}
+
+func (r *mockPackageRepo) WithTx(_ *gorm.DB) domainPackage.Repository { return r }
+
+func (r *mockPackageRepo) FindUsableForUpdate(_ context.Context, _, _, serviceID uint, _ time.Time) (*models.ClientPackage, error) {
+	return r.usable[serviceID], nil
+}
+
+func (r *mockPackageRepo) ConsumeCredit(_ context.Context, clientPackageID uint) error {
+	for _, cp := range r.usable {
+		if cp.ID != clientPackageID {
+			continue
+		}
+		if cp.CreditsLeft() <= 0 {
+			return domainPackage.ErrNoCreditsLeft
+		}
+		cp.CreditsUsed++
+	}
+	r.consumed = append(r.consumed, clientPackageID)
+	return nil
+}
+
+func TestCompleteAppointment_PackageCredits(t *testing.T) {
+	ctx := context.Background()
+	const (
+		svcOriginal = uint(10)
+		svcPackage  = uint(20)
+	)
+
+	input := CompleteAppointmentInput{
+		BarbershopID:    1,
+		BarberID:        1,
+		AppointmentID:   100,
+		ActualServiceID: svcPtr(svcPackage),
+	}
+
+	t.Run("serviço fora da assinatura coberto por pacote dispensa confirmação", func(t *testing.T) {
+		apptRepo := &mockCompleteAppointmentRepo{appointment: baseAppointmentWithActual(svcOriginal, svcPackage)}
+		pkgRepo := &mockPackageRepo{usable: map[uint]*models.ClientPackage{
+			svcPackage: {ID: 7, CreditsTotal: 3},
+		}}
+		uc := buildCompleteUC(t, apptRepo).WithPackages(pkgRepo)
+
+		_, closure, _, err := uc.Execute(ctx, input)
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if len(pkgRepo.consumed) != 1 || pkgRepo.consumed[0] != 7 {
+			t.Fatalf("créditos debitados = %v, esperado [7]", pkgRepo.consumed)
+		}
+		if closure.ClientPackageID == nil || *closure.ClientPackageID != 7 {
+			t.Errorf("client_package_id = %v, esperado 7", closure.ClientPackageID)
+		}
+		if closure.PackageCreditsUsed != 1 {
+			t.Errorf("package_credits_used = %d, esperado 1", closure.PackageCreditsUsed)
+		}
+		if closure.PackageCoveredCents != 5000 {
+			t.Errorf("package_covered_cents = %d, esperado 5000", closure.PackageCoveredCents)
+		}
+		if closure.PaymentMethod != "package" {
+			t.Errorf("payment_method = %q, esperado package", closure.PaymentMethod)
+		}
+	})
+
+	t.Run("pacote sem saldo mantém a exigência de confirmação", func(t *testing.T) {
+		apptRepo := &mockCompleteAppointmentRepo{appointment: baseAppointmentWithActual(svcOriginal, svcPackage)}
+		pkgRepo := &mockPackageRepo{usable: map[uint]*models.ClientPackage{
+			svcPackage: {ID: 7, CreditsTotal: 3, CreditsUsed: 3},
+		}}
+		uc := buildCompleteUC(t, apptRepo).WithPackages(pkgRepo)
+
+		_, _, _, err := uc.Execute(ctx, input)
+		if !apperr.IsBusiness(err, "normal_charging_confirmation_required") {
+			t.Errorf("esperado normal_charging_confirmation_required, obtido: %v", err)
+		}
+	})
+
+	t.Run("sem pacote que cubra o serviço mantém a exigência de confirmação", func(t *testing.T) {
+		apptRepo := &mockCompleteAppointmentRepo{appointment: baseAppointmentWithActual(svcOriginal, svcPackage)}
+		pkgRepo := &mockPackageRepo{usable: map[uint]*models.ClientPackage{}}
+		uc := buildCompleteUC(t, apptRepo).WithPackages(pkgRepo)
+
+		_, _, _, err := uc.Execute(ctx, input)
+		if !apperr.IsBusiness(err, "normal_charging_confirmation_required") {
+			t.Errorf("esperado normal_charging_confirmation_required, obtido: %v", err)
+		}
+		if len(pkgRepo.consumed) != 0 {
+			t.Errorf("nenhum crédito deveria ser debitado, obtido %v", pkgRepo.consumed)
+		}
+	})
+}
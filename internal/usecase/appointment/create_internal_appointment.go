@@ -4,13 +4,17 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gorm"
+
 	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 )
 
 type CreateInternalAppointment struct {
 	appointmentRepo domainAppointment.Repository
+	calendar        domainAppointment.CalendarSync
 }
 
 func NewCreateInternalAppointment(
@@ -21,6 +25,12 @@ func NewCreateInternalAppointment(
 	}
 }
 
+// WithCalendarSync espelha o agendamento criado no Google Calendar do barbeiro.
+func (uc *CreateInternalAppointment) WithCalendarSync(c domainAppointment.CalendarSync) *CreateInternalAppointment {
+	uc.calendar = c
+	return uc
+}
+
 type CreateInternalAppointmentInput struct {
 	BarbershopID uint
 	BarberID     uint
@@ -99,7 +109,35 @@ func (uc *CreateInternalAppointment) Execute(
 		Notes: input.Notes,
 	}
 
-	if err := uc.appointmentRepo.CreateAppointment(ctx, appointment); err != nil {
+	// Linha do serviço com o preço do barbeiro; a duração é a do intervalo
+	// informado. Serviço inexistente segue sem linha, como antes.
+	product, err := uc.appointmentRepo.GetProduct(ctx, barbershopID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product != nil {
+		override, err := uc.appointmentRepo.GetBarberServiceOverride(ctx, barbershopID, barberID, productID)
+		if err != nil {
+			return nil, err
+		}
+		svc := override.ApplyTo(product)
+		svc.DurationMin = int(input.EndTime.Sub(input.StartTime) / time.Minute)
+		if svc.DurationMin < 1 {
+			svc.DurationMin = 1
+		}
+		appointment.Services = appointmentServiceLines(barbershopID, []*models.BarbershopService{svc}, nil)
+	}
+
+	// A sincronização de agenda entra na transação do INSERT pelo outbox.
+	var onCreated func(ctx context.Context, tx *gorm.DB) error
+	if uc.calendar != nil {
+		onCreated = func(ctx context.Context, tx *gorm.DB) error {
+			uc.calendar.AppointmentChanged(outbox.ContextWithTx(ctx, tx), barbershopID, appointment.ID)
+			return nil
+		}
+	}
+
+	if err := uc.appointmentRepo.CreateAppointment(ctx, appointment, onCreated); err != nil {
 		return nil, err
 	}
 
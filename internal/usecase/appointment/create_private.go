@@ -2,15 +2,21 @@ package appointment
 
 import (
 	"context"
+	"log"
+	"strings"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/paymentconfig"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/idempotency"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+	ucCoupon "github.com/BruksfildServices01/barber-scheduler/internal/usecase/coupon"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 	paymentconfig "github.com/BruksfildServices01/barber-scheduler/internal/usecase/paymentconfig"
 	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
@@ -18,18 +24,36 @@ import (
 
 type CreatePrivateAppointmentInput struct {
 	BarbershopID uint
-	BarberID     uint
+	BarberID     uint // 0 = qualquer barbeiro disponível
 
 	ClientName  string
 	ClientPhone string
 	ClientEmail string
 
 	ProductID uint
+	// ProductIDs são os serviços do agendamento, em ordem (ex.: corte + barba).
+	// Vazio = só ProductID.
+	ProductIDs []uint
+	// ComboID agenda os serviços de um combo, com o preço do combo.
+	// Substitui ProductID/ProductIDs.
+	ComboID uint
 
 	Date           string
 	Time           string
 	Notes          string
 	IdempotencyKey string
+
+	// SeriesID vincula o agendamento a uma série recorrente (CreateSeries).
+	SeriesID *uint
+
+	// CouponCode aplica um cupom sobre os serviços cobrados (não cobertos
+	// pela assinatura).
+	CouponCode string
+
+	// OnCreated roda na transação que cria o agendamento (ap.ID já
+	// preenchido), para o chamador gravar o que depende dele — ticket,
+	// confirmação no outbox — com a mesma garantia de commit.
+	OnCreated func(ctx context.Context, tx *gorm.DB, ap *models.Appointment) error
 }
 
 type CreatePrivateAppointment struct {
@@ -41,6 +65,8 @@ type CreatePrivateAppointment struct {
 	getSubscriptionUC *ucSubscription.GetActiveSubscription
 	reserveCutUC      *ucSubscription.ReserveSubscriptionCut
 	idempotency       idempotency.Store
+	calendar          domain.CalendarSync
+	coupons           *ucCoupon.Applier
 }
 
 func NewCreatePrivateAppointment(
@@ -65,6 +91,18 @@ func NewCreatePrivateAppointment(
 	}
 }
 
+// WithCoupons habilita CouponCode no booking.
+func (uc *CreatePrivateAppointment) WithCoupons(a *ucCoupon.Applier) *CreatePrivateAppointment {
+	uc.coupons = a
+	return uc
+}
+
+// WithCalendarSync espelha o agendamento criado no Google Calendar do barbeiro.
+func (uc *CreatePrivateAppointment) WithCalendarSync(c domain.CalendarSync) *CreatePrivateAppointment {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *CreatePrivateAppointment) Execute(
 	ctx context.Context,
 	in CreatePrivateAppointmentInput,
@@ -115,46 +153,33 @@ func (uc *CreatePrivateAppointment) Execute(
 	}
 
 	// --------------------------------------------------
-	// 4) Produto
+	// 4) Serviços (o primeiro é o principal)
 	// --------------------------------------------------
-	product, err := uc.repo.GetProduct(ctx, in.BarbershopID, in.ProductID)
-	if err != nil || product == nil {
-		return nil, apperr.ErrBusiness("product_not_found")
+	products, combo, err := bookingServices(ctx, uc.repo, in.BarbershopID, in.ProductID, in.ProductIDs, in.ComboID)
+	if err != nil {
+		return nil, err
 	}
-
-	end := start.Add(time.Duration(product.DurationMin) * time.Minute)
+	product := products[0]
 
 	// --------------------------------------------------
 	// 5) Horário de trabalho (timezone-safe) + schedule override
 	// --------------------------------------------------
-	// resolveWorkingHours aplica as mesmas regras que GetAvailability usa,
+	// A duração é a soma dos serviços no catálogo do barbeiro (servicesForBarber).
+	// assertWithinWorkingHours aplica as mesmas regras que GetAvailability usa,
 	// garantindo que criação e disponibilidade validem o mesmo expediente efetivo.
-	startLocal := start.In(loc)
-	endLocal := end.In(loc)
-
-	ewh, err := resolveWorkingHours(ctx, uc.repo, in.BarbershopID, in.BarberID, startLocal)
-	if err != nil {
-		return nil, err
-	}
-
-	if ewh == nil {
-		return nil, apperr.ErrBusiness("outside_working_hours")
-	}
-
-	workStart := parseHM(ewh.StartTime, startLocal, loc)
-	workEnd := parseHM(ewh.EndTime, startLocal, loc)
-
-	if startLocal.Before(workStart) || endLocal.After(workEnd) {
-		return nil, apperr.ErrBusiness("outside_working_hours")
-	}
-
-	if ewh.LunchStart != "" && ewh.LunchEnd != "" {
-		lunchStart := parseHM(ewh.LunchStart, startLocal, loc)
-		lunchEnd := parseHM(ewh.LunchEnd, startLocal, loc)
+	// BarberID 0 ("qualquer barbeiro") é validado por barbeiro no passo 7.
+	var chosen barberCandidate
+	if in.BarberID != 0 {
+		services, duration, err := servicesForBarber(ctx, uc.repo, products, in.BarbershopID, in.BarberID)
+		if err != nil {
+			return nil, err
+		}
+		end := start.Add(duration)
 
-		if startLocal.Before(lunchEnd) && endLocal.After(lunchStart) {
-			return nil, apperr.ErrBusiness("outside_working_hours")
+		if err := assertWithinWorkingHours(ctx, uc.repo, in.BarbershopID, in.BarberID, start, end, loc); err != nil {
+			return nil, err
 		}
+		chosen = barberCandidate{BarberID: in.BarberID, Services: services, End: end}
 	}
 
 	// --------------------------------------------------
@@ -171,20 +196,46 @@ func (uc *CreatePrivateAppointment) Execute(
 		return nil, err
 	}
 
+	// Cupom: status, janela e regras do cliente valem antes de reservar
+	// cortes da assinatura.
+	var coupon *models.Coupon
+	if code := strings.TrimSpace(in.CouponCode); code != "" {
+		if uc.coupons == nil {
+			return nil, apperr.ErrBusiness("coupon_not_found")
+		}
+		coupon, err = uc.coupons.Prepare(ctx, in.BarbershopID, code, &client.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// --------------------------------------------------
 	// 7) Conflito de horário (com tolerância configurada)
 	// --------------------------------------------------
 	// A tolerância permite sobreposição de até T minutos em cada extremidade,
 	// espelhando a mesma lógica usada em get_availability.go.
-	conflictStart, conflictEnd := applyTolerance(start, end, shop.ScheduleToleranceMinutes)
-	if err := uc.repo.AssertNoTimeConflict(
-		ctx,
-		in.BarbershopID,
-		in.BarberID,
-		conflictStart,
-		conflictEnd,
-	); err != nil {
-		return nil, err
+	// Aqui é só uma checagem antecipada: a definitiva acontece no INSERT
+	// (CreateAppointmentIfFree), sob lock do barbeiro.
+	//
+	// BarberID 0 = "qualquer barbeiro disponível" (booking público): os
+	// barbeiros livres são ordenados pela estratégia de atribuição da barbearia.
+	candidates := []barberCandidate{chosen}
+	if in.BarberID == 0 {
+		candidates, err = rankAvailableBarbers(ctx, uc.repo, shop, products, client.ID, start, loc)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		conflictStart, conflictEnd := applyTolerance(start, chosen.End, shop.ScheduleToleranceMinutes)
+		if err := uc.repo.AssertNoTimeConflict(
+			ctx,
+			in.BarbershopID,
+			in.BarberID,
+			conflictStart,
+			conflictEnd,
+		); err != nil {
+			return nil, err
+		}
 	}
 
 	// --------------------------------------------------
@@ -200,11 +251,15 @@ func (uc *CreatePrivateAppointment) Execute(
 	}
 
 	// --------------------------------------------------
-	// 9) Assinatura ativa + cobertura do serviço
+	// 9) Assinatura ativa + cobertura de cada serviço
 	// --------------------------------------------------
-	coverageStatus := models.CoverageStatusNone
+	// Cada serviço coberto reserva um corte: corte + barba num plano que cobre
+	// os dois consome dois cortes; se só o corte é coberto, a barba é cobrada.
+	coverage := make([]lineCoverage, len(products))
+	for i := range coverage {
+		coverage[i].Status = models.CoverageStatusNone
+	}
 	var subscriptionID *uint
-	reservedCut := false
 
 	if uc.getSubscriptionUC != nil {
 		sub, err := uc.getSubscriptionUC.Execute(ctx, in.BarbershopID, client.ID)
@@ -213,37 +268,42 @@ func (uc *CreatePrivateAppointment) Execute(
 		}
 
 		if sub != nil {
-			serviceAllowed := false
-			if sub.Plan != nil {
-				for _, allowedServiceID := range sub.Plan.ServiceIDs {
-					if allowedServiceID == product.ID {
-						serviceAllowed = true
-						break
+			reservedNow := 0
+
+			for i, p := range products {
+				serviceAllowed := false
+				if sub.Plan != nil {
+					for _, allowedServiceID := range sub.Plan.ServiceIDs {
+						if allowedServiceID == p.ID {
+							serviceAllowed = true
+							break
+						}
 					}
 				}
-			}
 
-			switch {
-			case !serviceAllowed:
-				coverageStatus = models.CoverageStatusNotCoveredService
-
-			case sub.Plan != nil && sub.Plan.CutsIncluded > 0 &&
-				sub.CutsUsedInPeriod+sub.CutsReservedInPeriod >= sub.Plan.CutsIncluded:
-				coverageStatus = models.CoverageStatusNotCoveredExhausted
-
-			default:
-				// Tenta reservar; falha de concorrência → exhausted
-				if uc.reserveCutUC != nil {
-					if err := uc.reserveCutUC.Execute(ctx, in.BarbershopID, client.ID); err == nil {
-						coverageStatus = models.CoverageStatusCovered
-						subID := sub.ID
-						subscriptionID = &subID
-						reservedCut = true
+				switch {
+				case !serviceAllowed:
+					coverage[i].Status = models.CoverageStatusNotCoveredService
+
+				case sub.Plan != nil && sub.Plan.CutsIncluded > 0 &&
+					sub.CutsUsedInPeriod+sub.CutsReservedInPeriod+reservedNow >= sub.Plan.CutsIncluded:
+					coverage[i].Status = models.CoverageStatusNotCoveredExhausted
+
+				default:
+					// Tenta reservar; falha de concorrência → exhausted
+					if uc.reserveCutUC != nil {
+						if err := uc.reserveCutUC.Execute(ctx, in.BarbershopID, client.ID); err == nil {
+							coverage[i] = lineCoverage{Status: models.CoverageStatusCovered, Reserved: true}
+							reservedNow++
+						} else {
+							coverage[i].Status = models.CoverageStatusNotCoveredExhausted
+						}
 					} else {
-						coverageStatus = models.CoverageStatusNotCoveredExhausted
+						coverage[i].Status = models.CoverageStatusCovered
 					}
-				} else {
-					coverageStatus = models.CoverageStatusCovered
+				}
+
+				if coverage[i].Status == models.CoverageStatusCovered && subscriptionID == nil {
 					subID := sub.ID
 					subscriptionID = &subID
 				}
@@ -251,6 +311,8 @@ func (uc *CreatePrivateAppointment) Execute(
 		}
 	}
 
+	coverageStatus, reservedCut := appointmentCoverage(coverage)
+
 	// --------------------------------------------------
 	// 10) Regra final de cobrança
 	// --------------------------------------------------
@@ -290,17 +352,36 @@ func (uc *CreatePrivateAppointment) Execute(
 	}
 
 	// --------------------------------------------------
-	// 12) Limpar awaiting_payment expirado/orfão no slot
-	// Garante que a DB constraint não conflite com a lógica
-	// de AssertNoTimeConflict na janela entre o job e o INSERT.
+	// 12) Cupom: reserva o uso antes do INSERT
 	// --------------------------------------------------
-	_ = uc.repo.CancelExpiredAwaitingPaymentAtSlot(ctx, in.BarbershopID, in.BarberID, start)
+	// O resgate é atômico (limite total e por cliente); se o agendamento não
+	// for criado, o uso é devolvido.
+	var redemption *models.CouponRedemption
+	if coupon != nil {
+		preview := appointmentServiceLines(in.BarbershopID, candidates[0].Services, coverage)
+		applyComboPrice(preview, combo)
+		if _, _, err := bookingDiscount(coupon, preview); err != nil {
+			return nil, err
+		}
+
+		redemption = &models.CouponRedemption{ClientID: &client.ID}
+		if err := uc.coupons.Redeem(ctx, coupon, redemption); err != nil {
+			return nil, err
+		}
+	}
+	releaseCoupon := func() {
+		if redemption == nil {
+			return
+		}
+		if err := uc.coupons.Release(ctx, redemption.ID); err != nil {
+			log.Printf("[CreatePrivateAppointment] failed to release coupon redemption %d: %v", redemption.ID, err)
+		}
+	}
 
 	// --------------------------------------------------
 	// 13) Criar Appointment
 	// --------------------------------------------------
 	barbershopID := in.BarbershopID
-	barberID := in.BarberID
 	clientID := client.ID
 	productID := product.ID
 
@@ -308,11 +389,9 @@ func (uc *CreatePrivateAppointment) Execute(
 
 	ap := &models.Appointment{
 		BarbershopID:            &barbershopID,
-		BarberID:                &barberID,
 		ClientID:                &clientID,
 		BarberProductID:         &productID,
 		StartTime:               start,
-		EndTime:                 end,
 		Status:                  status,
 		CreatedBy:               models.CreatedByClient,
 		PaymentIntent:           models.PaymentIntentPayLater,
@@ -320,17 +399,69 @@ func (uc *CreatePrivateAppointment) Execute(
 		SubscriptionID:          subscriptionID,
 		CoverageStatus:          coverageStatus,
 		ReservedSubscriptionCut: reservedCut,
+		AutoAssigned:            in.BarberID == 0,
+		SeriesID:                in.SeriesID,
+	}
+	if combo != nil {
+		comboID := combo.ID
+		ap.ComboID = &comboID
+	}
+	if coupon != nil {
+		couponID := coupon.ID
+		ap.CouponID = &couponID
 	}
 
 	// --------------------------------------------------
-	// 13) Criar appointment + persistir chave de idempotência atomicamente
+	// 14) Criar appointment + persistir chave de idempotência atomicamente
 	// --------------------------------------------------
-	if err := uc.repo.CreateAppointmentWithKey(ctx, ap, idempotencyStorageKey); err != nil {
-		return nil, err
+	// Com "qualquer barbeiro", se um agendamento concorrente ocupar o horário
+	// do candidato entre a checagem e o INSERT, tenta o próximo da lista.
+	for i, candidate := range candidates {
+		barberID := candidate.BarberID
+		ap.BarberID = &barberID
+		ap.EndTime = candidate.End
+		ap.Services = appointmentServiceLines(in.BarbershopID, candidate.Services, coverage)
+		applyComboPrice(ap.Services, combo)
+		conflictStart, conflictEnd := applyTolerance(start, candidate.End, shop.ScheduleToleranceMinutes)
+
+		// O desconto depende dos preços do barbeiro; se ele cobre toda a
+		// cobrança, não há o que pagar antes.
+		if coupon != nil {
+			discount, charge, err := bookingDiscount(coupon, ap.Services)
+			if err != nil {
+				releaseCoupon()
+				return nil, err
+			}
+			ap.DiscountCents = discount
+			ap.Status = status
+			if discount >= charge {
+				ap.Status = models.AppointmentStatus(domain.StatusScheduled)
+			}
+		}
+
+		// Limpa awaiting_payment expirado/órfão no slot, para que a DB
+		// constraint não conflite com a lógica de AssertNoTimeConflict na
+		// janela entre o job e o INSERT.
+		_ = uc.repo.CancelExpiredAwaitingPaymentAtSlot(ctx, in.BarbershopID, barberID, start)
+
+		err = uc.repo.CreateAppointmentIfFree(ctx, ap, conflictStart, conflictEnd, idempotencyStorageKey, uc.onCreated(in, ap))
+		if err == nil {
+			break
+		}
+		if !apperr.IsBusiness(err, "time_conflict") || i == len(candidates)-1 {
+			releaseCoupon()
+			return nil, err
+		}
+	}
+
+	if redemption != nil {
+		if err := uc.coupons.AttachAppointment(ctx, redemption.ID, ap.ID, ap.DiscountCents); err != nil {
+			log.Printf("[CreatePrivateAppointment] failed to attach coupon redemption %d to appointment %d: %v", redemption.ID, ap.ID, err)
+		}
 	}
 
 	// --------------------------------------------------
-	// 14) Métricas
+	// 15) Métricas
 	// --------------------------------------------------
 	_ = uc.metrics.Execute(ctx, ucMetrics.UpdateClientMetricsInput{
 		BarbershopID: in.BarbershopID,
@@ -342,3 +473,20 @@ func (uc *CreatePrivateAppointment) Execute(
 
 	return ap, nil
 }
+
+// onCreated grava a sincronização de agenda, e o que o chamador pedir em
+// in.OnCreated, na transação do INSERT: nada disso existe sem o agendamento.
+func (uc *CreatePrivateAppointment) onCreated(in CreatePrivateAppointmentInput, ap *models.Appointment) func(ctx context.Context, tx *gorm.DB) error {
+	if uc.calendar == nil && in.OnCreated == nil {
+		return nil
+	}
+	return func(ctx context.Context, tx *gorm.DB) error {
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(outbox.ContextWithTx(ctx, tx), in.BarbershopID, ap.ID)
+		}
+		if in.OnCreated != nil {
+			return in.OnCreated(ctx, tx, ap)
+		}
+		return nil
+	}
+}
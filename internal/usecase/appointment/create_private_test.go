@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	domainMetrics "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
 	domainPaymentConfig "github.com/BruksfildServices01/barber-scheduler/internal/domain/paymentconfig"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
@@ -259,6 +260,25 @@ func TestCreatePrivateAppointment(t *testing.T) {
 		}
 	})
 
+	t.Run("horário ocupado no Google Calendar retorna outside_working_hours", func(t *testing.T) {
+		start, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+hr, loc)
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			product:      defaultProduct(),
+			workingHours: defaultWorkingHours(),
+			client:       zeroClient(),
+			busy: []domain.BusyPeriod{
+				{Start: start.Add(-30 * time.Minute).UTC(), End: start.Add(30 * time.Minute).UTC()},
+			},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		_, err := uc.Execute(ctx, defaultInput(date, hr))
+		if !apperr.IsBusiness(err, "outside_working_hours") {
+			t.Errorf("esperado outside_working_hours com evento pessoal, obtido: %v", err)
+		}
+	})
+
 	t.Run("conflito de horário retorna time_conflict", func(t *testing.T) {
 		repo := &mockRepo{
 			shop:         defaultShop(),
@@ -401,4 +421,327 @@ func TestCreatePrivateAppointment(t *testing.T) {
 			t.Errorf("almoço herdado deve rejeitar 12:00, obtido: %v", err)
 		}
 	})
+
+	t.Run("qualquer barbeiro: atribui o primeiro barbeiro livre", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:             defaultShop(),
+			product:          defaultProduct(),
+			workingHours:     defaultWorkingHours(),
+			client:           zeroClient(),
+			barberIDs:        []uint{1, 2},
+			conflictByBarber: map[uint]error{1: apperr.ErrBusiness("time_conflict")},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.BarberID = 0
+		ap, err := uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if ap.BarberID == nil || *ap.BarberID != 2 {
+			t.Errorf("esperado barbeiro 2, obtido %v", ap.BarberID)
+		}
+	})
+
+	t.Run("qualquer barbeiro: horário tomado no INSERT passa para o próximo barbeiro", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:                   defaultShop(),
+			product:                defaultProduct(),
+			workingHours:           defaultWorkingHours(),
+			client:                 zeroClient(),
+			barberIDs:              []uint{1, 2},
+			insertConflictByBarber: map[uint]error{1: apperr.ErrBusiness("time_conflict")},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.BarberID = 0
+		ap, err := uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if ap.BarberID == nil || *ap.BarberID != 2 {
+			t.Errorf("esperado barbeiro 2, obtido %v", ap.BarberID)
+		}
+		if !ap.AutoAssigned {
+			t.Error("agendamento deveria estar marcado como auto_assigned")
+		}
+	})
+
+	t.Run("barbeiro escolhido: horário tomado no INSERT retorna time_conflict", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:                   defaultShop(),
+			product:                defaultProduct(),
+			workingHours:           defaultWorkingHours(),
+			client:                 zeroClient(),
+			barberIDs:              []uint{1, 2},
+			insertConflictByBarber: map[uint]error{1: apperr.ErrBusiness("time_conflict")},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		_, err := uc.Execute(ctx, defaultInput(date, hr))
+		if !apperr.IsBusiness(err, "time_conflict") {
+			t.Errorf("esperado time_conflict, obtido: %v", err)
+		}
+	})
+
+	t.Run("catálogo por barbeiro: duração própria define o fim", func(t *testing.T) {
+		duration := 45
+		repo := &mockRepo{
+			shop:             defaultShop(),
+			product:          defaultProduct(),
+			workingHours:     defaultWorkingHours(),
+			client:           zeroClient(),
+			overrideByBarber: map[uint]*models.BarberServiceOverride{1: {Offered: true, DurationMin: &duration}},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		ap, err := uc.Execute(ctx, defaultInput(date, hr))
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if got := ap.EndTime.Sub(ap.StartTime); got != 45*time.Minute {
+			t.Errorf("duração = %v, esperado 45m", got)
+		}
+	})
+
+	t.Run("catálogo por barbeiro: barbeiro que não faz o serviço retorna service_not_offered", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:             defaultShop(),
+			product:          defaultProduct(),
+			workingHours:     defaultWorkingHours(),
+			client:           zeroClient(),
+			overrideByBarber: map[uint]*models.BarberServiceOverride{1: {Offered: false}},
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		_, err := uc.Execute(ctx, defaultInput(date, hr))
+		if !apperr.IsBusiness(err, "service_not_offered") {
+			t.Errorf("esperado service_not_offered, obtido: %v", err)
+		}
+	})
+
+	t.Run("qualquer barbeiro: todos ocupados retorna time_conflict", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			product:      defaultProduct(),
+			workingHours: defaultWorkingHours(),
+			client:       zeroClient(),
+			barberIDs:    []uint{1, 2},
+			conflictErr:  apperr.ErrBusiness("time_conflict"),
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.BarberID = 0
+		_, err := uc.Execute(ctx, in)
+		if !apperr.IsBusiness(err, "time_conflict") {
+			t.Errorf("esperado time_conflict, obtido: %v", err)
+		}
+	})
+}
+
+func TestCreatePrivateAppointment_MultipleServices(t *testing.T) {
+	ctx := context.Background()
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	date, hr := futureDate(loc)
+
+	products := map[uint]*models.BarbershopService{
+		1: {ID: 1, Name: "Corte", Price: 5000, DurationMin: 60},
+		2: {ID: 2, Name: "Barba", Price: 3000, DurationMin: 30},
+	}
+
+	t.Run("soma as durações e grava uma linha por serviço", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			productsByID: products,
+			workingHours: defaultWorkingHours(),
+			client:       zeroClient(),
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ProductIDs = []uint{1, 2}
+		ap, err := uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if got := ap.EndTime.Sub(ap.StartTime); got != 90*time.Minute {
+			t.Errorf("duração = %v, esperado 90m", got)
+		}
+		if len(ap.Services) != 2 {
+			t.Fatalf("linhas = %d, esperado 2", len(ap.Services))
+		}
+		if ap.Services[0].ServiceName != "Corte" || ap.Services[0].Position != 0 || ap.Services[0].PriceCents != 5000 {
+			t.Errorf("primeira linha inesperada: %+v", ap.Services[0])
+		}
+		if ap.Services[1].ServiceName != "Barba" || ap.Services[1].Position != 1 || ap.Services[1].PriceCents != 3000 {
+			t.Errorf("segunda linha inesperada: %+v", ap.Services[1])
+		}
+		if ap.BarberProductID == nil || *ap.BarberProductID != 1 {
+			t.Errorf("serviço principal deveria ser o primeiro da lista")
+		}
+	})
+
+	t.Run("serviço repetido retorna duplicate_service", func(t *testing.T) {
+		repo := &mockRepo{shop: defaultShop(), productsByID: products, workingHours: defaultWorkingHours()}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ProductIDs = []uint{1, 1}
+		_, err := uc.Execute(ctx, in)
+		if !apperr.IsBusiness(err, "duplicate_service") {
+			t.Errorf("esperado duplicate_service, obtido: %v", err)
+		}
+	})
+
+	t.Run("mais de cinco serviços retorna too_many_services", func(t *testing.T) {
+		repo := &mockRepo{shop: defaultShop(), productsByID: products, workingHours: defaultWorkingHours()}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ProductIDs = []uint{1, 2, 3, 4, 5, 6}
+		_, err := uc.Execute(ctx, in)
+		if !apperr.IsBusiness(err, "too_many_services") {
+			t.Errorf("esperado too_many_services, obtido: %v", err)
+		}
+	})
+
+	t.Run("serviço inexistente na lista retorna product_not_found", func(t *testing.T) {
+		repo := &mockRepo{shop: defaultShop(), productsByID: products, workingHours: defaultWorkingHours()}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ProductIDs = []uint{1, 9}
+		_, err := uc.Execute(ctx, in)
+		if !apperr.IsBusiness(err, "product_not_found") {
+			t.Errorf("esperado product_not_found, obtido: %v", err)
+		}
+	})
+}
+
+func TestCreatePrivateAppointment_Combo(t *testing.T) {
+	ctx := context.Background()
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	date, hr := futureDate(loc)
+
+	products := map[uint]*models.BarbershopService{
+		1: {ID: 1, Name: "Corte", Price: 5000, DurationMin: 60},
+		2: {ID: 2, Name: "Barba", Price: 3000, DurationMin: 30},
+	}
+	combo := &models.ServiceCombo{
+		ID:              7,
+		Name:            "Corte + Barba",
+		DiscountPercent: 15,
+		Active:          true,
+		Items:           []models.ServiceComboItem{{ServiceID: 1, Position: 0}, {ServiceID: 2, Position: 1}},
+	}
+
+	t.Run("agenda os serviços do combo com o preço do combo", func(t *testing.T) {
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			productsByID: products,
+			combos:       map[uint]*models.ServiceCombo{7: combo},
+			workingHours: defaultWorkingHours(),
+			client:       zeroClient(),
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ProductID = 0
+		in.ComboID = 7
+		ap, err := uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if ap.ComboID == nil || *ap.ComboID != 7 {
+			t.Errorf("combo_id não gravado: %v", ap.ComboID)
+		}
+		if len(ap.Services) != 2 {
+			t.Fatalf("linhas = %d, esperado 2", len(ap.Services))
+		}
+		// 8000 com 15% off = 6800, rateado 5000:3000.
+		if ap.Services[0].PriceCents != 4250 || ap.Services[1].PriceCents != 2550 {
+			t.Errorf("preços = %d + %d, esperado 4250 + 2550",
+				ap.Services[0].PriceCents, ap.Services[1].PriceCents)
+		}
+		if got := ap.EndTime.Sub(ap.StartTime); got != 90*time.Minute {
+			t.Errorf("duração = %v, esperado 90m", got)
+		}
+	})
+
+	t.Run("combo inativo retorna combo_not_found", func(t *testing.T) {
+		inactive := *combo
+		inactive.Active = false
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			productsByID: products,
+			combos:       map[uint]*models.ServiceCombo{7: &inactive},
+			workingHours: defaultWorkingHours(),
+		}
+		uc := buildCreateUC(repo, nil, false)
+
+		in := defaultInput(date, hr)
+		in.ComboID = 7
+		_, err := uc.Execute(ctx, in)
+		if !apperr.IsBusiness(err, "combo_not_found") {
+			t.Errorf("esperado combo_not_found, obtido: %v", err)
+		}
+	})
+}
+
+func TestApplyComboPrice(t *testing.T) {
+	lines := func() []models.AppointmentService {
+		return []models.AppointmentService{{PriceCents: 5000}, {PriceCents: 3000}, {PriceCents: 2000}}
+	}
+	fixed := int64(7001)
+
+	tests := []struct {
+		name  string
+		combo *models.ServiceCombo
+		want  []int64
+	}{
+		{"desconto rateado pelo preço", &models.ServiceCombo{DiscountPercent: 10}, []int64{4500, 2700, 1800}},
+		{"preço fixo com arredondamento na última linha", &models.ServiceCombo{PriceCents: &fixed}, []int64{3500, 2100, 1401}},
+		{"sem combo mantém os preços", nil, []int64{5000, 3000, 2000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lines()
+			applyComboPrice(got, tt.combo)
+			for i := range got {
+				if got[i].PriceCents != tt.want[i] {
+					t.Errorf("linha %d = %d, esperado %d", i, got[i].PriceCents, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Serviços sem preço: o preço fixo é dividido igualmente.
+	free := []models.AppointmentService{{}, {}}
+	applyComboPrice(free, &models.ServiceCombo{PriceCents: &fixed})
+	if free[0].PriceCents != 3500 || free[1].PriceCents != 3501 {
+		t.Errorf("divisão igual = %d + %d, esperado 3500 + 3501", free[0].PriceCents, free[1].PriceCents)
+	}
+}
+
+func TestAppointmentCoverage(t *testing.T) {
+	covered := lineCoverage{Status: models.CoverageStatusCovered, Reserved: true}
+	notCovered := lineCoverage{Status: models.CoverageStatusNotCoveredService}
+
+	status, reserved := appointmentCoverage([]lineCoverage{covered, covered})
+	if status != models.CoverageStatusCovered || !reserved {
+		t.Errorf("todas cobertas: status=%s reserved=%v", status, reserved)
+	}
+
+	status, reserved = appointmentCoverage([]lineCoverage{covered, notCovered})
+	if status != models.CoverageStatusNotCoveredService || !reserved {
+		t.Errorf("cobertura parcial: status=%s reserved=%v", status, reserved)
+	}
+
+	status, reserved = appointmentCoverage(nil)
+	if status != models.CoverageStatusNone || reserved {
+		t.Errorf("sem linhas: status=%s reserved=%v", status, reserved)
+	}
 }
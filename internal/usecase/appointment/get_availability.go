@@ -2,6 +2,7 @@ package appointment
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
@@ -23,12 +24,12 @@ func (uc *GetAvailability) Execute(
 	in domain.AvailabilityInput,
 ) ([]domain.TimeSlot, error) {
 
-	// 1 & 2) Paralelo: barbearia e produto não têm dependência entre si.
+	// 1 & 2) Paralelo: barbearia e serviços não têm dependência entre si.
 	// O receive no canal acontece-after o send da goroutine, garantindo
-	// visibilidade de memória de shop e product sem sincronização adicional.
+	// visibilidade de memória de shop e products sem sincronização adicional.
 	var (
-		shop    *models.Barbershop
-		product *models.BarbershopService
+		shop     *models.Barbershop
+		products []*models.BarbershopService
 	)
 	shopCh    := make(chan error, 1)
 	productCh := make(chan error, 1)
@@ -40,7 +41,7 @@ func (uc *GetAvailability) Execute(
 	}()
 	go func() {
 		var err error
-		product, err = uc.repo.GetProduct(ctx, in.BarbershopID, in.ProductID)
+		products, _, err = bookingServices(ctx, uc.repo, in.BarbershopID, in.ProductID, in.ProductIDs, in.ComboID)
 		productCh <- err
 	}()
 
@@ -55,17 +56,70 @@ func (uc *GetAvailability) Execute(
 	if shop == nil {
 		return nil, apperr.ErrBusiness("barbershop_not_found")
 	}
-	if productErr != nil || product == nil {
-		return nil, apperr.ErrBusiness("product_not_found")
+	if productErr != nil {
+		return nil, productErr
 	}
 
 	loc := timezone.Location(shop.Timezone)
 	dateLocal := in.Date.In(loc)
 
+	if in.BarberID != 0 {
+		return uc.slotsForBarber(ctx, shop, products, in.BarberID, dateLocal, loc)
+	}
+
+	// BarberID 0 = "qualquer barbeiro disponível": união dos slots livres de
+	// todos os barbeiros ativos. A criação escolhe o barbeiro (rankAvailableBarbers).
+	barberIDs, err := uc.repo.ListActiveBarberIDs(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	slots := make([]domain.TimeSlot, 0)
+	for _, barberID := range barberIDs {
+		barberSlots, err := uc.slotsForBarber(ctx, shop, products, barberID, dateLocal, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range barberSlots {
+			if seen[slot.Start] {
+				continue
+			}
+			seen[slot.Start] = true
+			slots = append(slots, slot)
+		}
+	}
+
+	// "HH:MM" ordena lexicograficamente na ordem do dia.
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start < slots[j].Start })
+
+	return slots, nil
+}
+
+// slotsForBarber calcula os slots livres de um barbeiro no dia. O slot tem a
+// duração somada dos serviços do agendamento.
+func (uc *GetAvailability) slotsForBarber(
+	ctx context.Context,
+	shop *models.Barbershop,
+	products []*models.BarbershopService,
+	barberID uint,
+	dateLocal time.Time,
+	loc *time.Location,
+) ([]domain.TimeSlot, error) {
+	// Catálogo por barbeiro: duração própria; quem não faz algum dos serviços
+	// não tem slots.
+	_, slotDuration, err := servicesForBarber(ctx, uc.repo, products, shop.ID, barberID)
+	if apperr.IsBusiness(err, "service_not_offered") {
+		return []domain.TimeSlot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	// 3) Expediente efetivo do dia: working hours + schedule override (se existir).
 	// resolveWorkingHours aplica as mesmas regras usadas em CreatePrivateAppointment,
 	// garantindo que disponibilidade e criação validem exatamente o mesmo expediente.
-	ewh, err := resolveWorkingHours(ctx, uc.repo, in.BarbershopID, in.BarberID, dateLocal)
+	ewh, err := resolveWorkingHours(ctx, uc.repo, shop.ID, barberID, dateLocal)
 	if err != nil {
 		return nil, err
 	}
@@ -76,18 +130,11 @@ func (uc *GetAvailability) Execute(
 	dayStart := parseHM(ewh.StartTime, dateLocal, loc)
 	dayEnd := parseHM(ewh.EndTime, dateLocal, loc)
 
-	hasLunch := ewh.LunchStart != "" && ewh.LunchEnd != ""
-	var lunchStart, lunchEnd time.Time
-	if hasLunch {
-		lunchStart = parseHM(ewh.LunchStart, dateLocal, loc)
-		lunchEnd = parseHM(ewh.LunchEnd, dateLocal, loc)
-	}
-
 	// 4) Buscar appointments no range do dia
 	appointments, err := uc.repo.ListAppointmentsForDay(
 		ctx,
-		in.BarbershopID,
-		in.BarberID,
+		shop.ID,
+		barberID,
 		dayStart,
 		dayEnd,
 	)
@@ -103,7 +150,6 @@ func (uc *GetAvailability) Execute(
 	earliest := time.Now().In(loc).Add(time.Duration(minAdvance) * time.Minute)
 
 	// 6) Slots
-	slotDuration := time.Duration(product.DurationMin) * time.Minute
 	slots := make([]domain.TimeSlot, 0)
 
 	apIdx := 0
@@ -117,7 +163,8 @@ func (uc *GetAvailability) Execute(
 			continue
 		}
 
-		if hasLunch && slotStart.Before(lunchEnd) && slotEnd.After(lunchStart) {
+		// Almoço e eventos pessoais do barbeiro no Google Calendar.
+		if ewh.blocked(slotStart, slotEnd, dateLocal, loc) {
 			continue
 		}
 
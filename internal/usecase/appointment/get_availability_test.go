@@ -114,6 +114,29 @@ func TestGetAvailability(t *testing.T) {
 		}
 	})
 
+	t.Run("evento pessoal do Google Calendar bloqueia slots sobrepostos", func(t *testing.T) {
+		// Evento das 10:00 às 11:30 → slots 10:00 e 11:00 ficam indisponíveis
+		busyStart := time.Date(2030, 1, 7, 10, 0, 0, 0, loc)
+		busy := []domain.BusyPeriod{{Start: busyStart.UTC(), End: busyStart.Add(90 * time.Minute).UTC()}}
+
+		repo := &mockRepo{shop: shop, product: product60min, workingHours: wh9to18, busy: busy}
+		uc := NewGetAvailability(repo)
+
+		slots, err := uc.Execute(ctx, input(baseDate, 1))
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+
+		if len(slots) != 7 {
+			t.Errorf("esperado 7 slots, obtido %d: %v", len(slots), slots)
+		}
+		for _, s := range slots {
+			if s.Start == "10:00" || s.Start == "11:00" {
+				t.Errorf("slot %s não deveria estar disponível (evento pessoal)", s.Start)
+			}
+		}
+	})
+
 	t.Run("tolerância não bloqueia slots adjacentes dentro do limite", func(t *testing.T) {
 		shopWithTol := &models.Barbershop{
 			ID:                       1,
@@ -382,4 +405,71 @@ func TestGetAvailability(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("qualquer barbeiro: união dos slots livres de todos os barbeiros", func(t *testing.T) {
+		// Barbeiro 1 ocupado das 10:00 às 11:00; barbeiro 2 ocupado das 11:00 às 12:00.
+		ap10 := time.Date(2030, 1, 7, 10, 0, 0, 0, loc)
+		ap11 := time.Date(2030, 1, 7, 11, 0, 0, 0, loc)
+		repo := &mockRepo{
+			shop:         shop,
+			product:      product60min,
+			workingHours: wh9to18,
+			barberIDs:    []uint{1, 2},
+			appointmentsByBarber: map[uint][]models.Appointment{
+				1: {{StartTime: ap10, EndTime: ap10.Add(time.Hour), Status: models.AppointmentStatusScheduled}},
+				2: {{StartTime: ap11, EndTime: ap11.Add(time.Hour), Status: models.AppointmentStatusScheduled}},
+			},
+		}
+		uc := NewGetAvailability(repo)
+
+		in := input(baseDate, 1)
+		in.BarberID = 0
+		slots, err := uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+
+		// Cada horário tem ao menos um barbeiro livre → 9 slots, sem duplicatas e em ordem.
+		if len(slots) != 9 {
+			t.Fatalf("esperado 9 slots, obtido %d: %+v", len(slots), slots)
+		}
+		for i := 1; i < len(slots); i++ {
+			if slots[i-1].Start >= slots[i].Start {
+				t.Fatalf("slots fora de ordem ou duplicados: %+v", slots)
+			}
+		}
+	})
+
+	t.Run("catálogo por barbeiro: duração própria e barbeiro que não faz o serviço", func(t *testing.T) {
+		duration := 120
+		repo := &mockRepo{
+			shop:         shop,
+			product:      product60min,
+			workingHours: wh9to18,
+			overrideByBarber: map[uint]*models.BarberServiceOverride{
+				1: {Offered: true, DurationMin: &duration},
+				2: {Offered: false},
+			},
+		}
+		uc := NewGetAvailability(repo)
+
+		slots, err := uc.Execute(ctx, input(baseDate, 1))
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		// 09:00–18:00 com serviço de 2h → 4 slots: 09, 11, 13, 15.
+		if len(slots) != 4 || slots[0].End != "11:00" || slots[3].Start != "15:00" {
+			t.Fatalf("slots com duração de 2h incorretos: %+v", slots)
+		}
+
+		in := input(baseDate, 1)
+		in.BarberID = 2
+		slots, err = uc.Execute(ctx, in)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if len(slots) != 0 {
+			t.Errorf("barbeiro que não faz o serviço não deve ter slots, obtido %d", len(slots))
+		}
+	})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
 )
@@ -21,6 +22,7 @@ type MarkAppointmentNoShow struct {
 	audit            *audit.Dispatcher
 	metrics          *ucMetrics.UpdateClientMetrics
 	releaseUC        *ucSubscription.ReleaseSubscriptionCut
+	calendar         domain.CalendarSync
 }
 
 func NewMarkAppointmentNoShow(
@@ -41,6 +43,12 @@ func NewMarkAppointmentNoShow(
 	}
 }
 
+// WithCalendarSync marca o evento espelhado no Google Calendar como falta.
+func (uc *MarkAppointmentNoShow) WithCalendarSync(c domain.CalendarSync) *MarkAppointmentNoShow {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *MarkAppointmentNoShow) Execute(
 	ctx context.Context,
 	barbershopID uint,
@@ -76,14 +84,33 @@ func (uc *MarkAppointmentNoShow) Execute(
 			return err
 		}
 
-		// Liberar reserva de assinatura dentro da mesma transação.
+		// Liberar reserva de assinatura dentro da mesma transação (um corte
+		// por serviço coberto).
 		if ap.ReservedSubscriptionCut && ap.ClientID != nil && ap.BarbershopID != nil && uc.releaseUC != nil {
 			txSubRepo := uc.subscriptionRepo.WithTx(tx)
-			if err := uc.releaseUC.Execute(ctx, *ap.BarbershopID, *ap.ClientID, txSubRepo); err != nil {
-				log.Printf("[MarkNoShow] release subscription cut failed for client %d: %v", *ap.ClientID, err)
+			for i := 0; i < ap.ReservedCuts(); i++ {
+				if err := uc.releaseUC.Execute(ctx, *ap.BarbershopID, *ap.ClientID, txSubRepo); err != nil {
+					log.Printf("[MarkNoShow] release subscription cut failed for client %d: %v", *ap.ClientID, err)
+					break
+				}
 			}
 		}
 
+		// Auditoria e agenda entram na mesma transação pelo outbox.
+		octx := outbox.ContextWithTx(ctx, tx)
+		if err := uc.audit.DispatchContext(octx, audit.Event{
+			BarbershopID: barbershopID,
+			UserID:       &barberID,
+			Action:       "appointment_no_show",
+			Entity:       "appointment",
+			EntityID:     &apID,
+		}); err != nil {
+			return err
+		}
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(octx, barbershopID, apID)
+		}
+
 		return nil
 	})
 
@@ -100,13 +127,5 @@ func (uc *MarkAppointmentNoShow) Execute(
 		})
 	}
 
-	uc.audit.Dispatch(audit.Event{
-		BarbershopID: barbershopID,
-		UserID:       &barberID,
-		Action:       "appointment_no_show",
-		Entity:       "appointment",
-		EntityID:     &apID,
-	})
-
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gorm"
+
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
@@ -26,21 +28,69 @@ type mockRepo struct {
 	conflictErr      error
 	createErr        error
 	cancelExpiredErr error
+
+	// Multi-barbeiro: quando preenchidos, sobrepõem appointments/conflictErr
+	// para o barbeiro correspondente.
+	barberIDs            []uint
+	appointmentsByBarber map[uint][]models.Appointment
+	conflictByBarber     map[uint]error
+
+	// Atribuição automática.
+	lastAutoBarberID  uint
+	preferredBarberID uint
+	// insertConflictByBarber simula outro booking ocupando o horário entre a
+	// checagem e o INSERT (CreateAppointmentIfFree).
+	insertConflictByBarber map[uint]error
+
+	// Catálogo por barbeiro.
+	overrideByBarber map[uint]*models.BarberServiceOverride
+
+	// Vários serviços: quando preenchido, GetProduct busca pelo ID.
+	productsByID map[uint]*models.BarbershopService
+
+	// Combos por ID.
+	combos map[uint]*models.ServiceCombo
+
+	// Horários ocupados do Google Calendar.
+	busy []domain.BusyPeriod
 }
 
 func (r *mockRepo) GetBarbershopByID(_ context.Context, _ uint) (*models.Barbershop, error) {
 	return r.shop, r.shopErr
 }
 
-func (r *mockRepo) GetProduct(_ context.Context, _, _ uint) (*models.BarbershopService, error) {
+func (r *mockRepo) GetProduct(_ context.Context, _, productID uint) (*models.BarbershopService, error) {
+	if r.productsByID != nil {
+		return r.productsByID[productID], r.productErr
+	}
 	return r.product, r.productErr
 }
 
+func (r *mockRepo) GetCombo(_ context.Context, _, comboID uint) (*models.ServiceCombo, error) {
+	return r.combos[comboID], nil
+}
+
+func (r *mockRepo) GetBarberServiceOverride(_ context.Context, _, barberID, _ uint) (*models.BarberServiceOverride, error) {
+	return r.overrideByBarber[barberID], nil
+}
+
+func (r *mockRepo) ListActiveBarberIDs(_ context.Context, _ uint) ([]uint, error) {
+	return r.barberIDs, nil
+}
+
+func (r *mockRepo) GetLastAutoAssignedBarberID(_ context.Context, _ uint) (uint, error) {
+	return r.lastAutoBarberID, nil
+}
+
+func (r *mockRepo) GetPreferredBarberID(_ context.Context, _, _ uint) (uint, error) {
+	return r.preferredBarberID, nil
+}
+
 func (r *mockRepo) GetOrCreateClient(_ context.Context, _ uint, _, _, _ string) (*models.Client, error) {
 	return r.client, r.clientErr
 }
 
-func (r *mockRepo) CreateAppointment(_ context.Context, _ *models.Appointment) error {
+func (r *mockRepo) CreateAppointment(_ context.Context, _ *models.Appointment, _ func(context.Context, *gorm.DB) error) error {
 	return r.createErr
 }
 
@@ -52,7 +102,26 @@ func (r *mockRepo) CreateAppointmentWithKey(_ context.Context, ap *models.Appoin
 	return nil
 }
 
-func (r *mockRepo) AssertNoTimeConflict(_ context.Context, _, _ uint, _, _ time.Time) error {
+func (r *mockRepo) CreateAppointmentIfFree(ctx context.Context, ap *models.Appointment, _, _ time.Time, _ string, onCreated func(context.Context, *gorm.DB) error) error {
+	if ap.BarberID != nil {
+		if err, ok := r.insertConflictByBarber[*ap.BarberID]; ok {
+			return err
+		}
+	}
+	if r.createErr != nil {
+		return r.createErr
+	}
+	ap.ID = 1
+	if onCreated != nil {
+		return onCreated(ctx, nil)
+	}
+	return nil
+}
+
+func (r *mockRepo) AssertNoTimeConflict(_ context.Context, _, barberID uint, _, _ time.Time) error {
+	if err, ok := r.conflictByBarber[barberID]; ok {
+		return err
+	}
 	return r.conflictErr
 }
 
@@ -68,6 +137,10 @@ func (r *mockRepo) UpdateAppointment(_ context.Context, _ *models.Appointment) e
 	return nil
 }
 
+func (r *mockRepo) ListAppointmentServices(_ context.Context, _ uint) ([]models.AppointmentService, error) {
+	return nil, nil
+}
+
 func (r *mockRepo) SaveAppointmentClosure(_ context.Context, _ *models.AppointmentClosure) error {
 	return nil
 }
@@ -84,7 +157,20 @@ func (r *mockRepo) GetScheduleOverride(_ context.Context, _, _ uint, _ string, _
 	return r.override, r.overrideErr
 }
 
-func (r *mockRepo) ListAppointmentsForDay(_ context.Context, _, _ uint, _, _ time.Time) ([]models.Appointment, error) {
+func (r *mockRepo) ListBusyPeriods(_ context.Context, _, _ uint, from, to time.Time) ([]domain.BusyPeriod, error) {
+	out := make([]domain.BusyPeriod, 0, len(r.busy))
+	for _, b := range r.busy {
+		if b.Start.Before(to) && b.End.After(from) {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *mockRepo) ListAppointmentsForDay(_ context.Context, _, barberID uint, _, _ time.Time) ([]models.Appointment, error) {
+	if aps, ok := r.appointmentsByBarber[barberID]; ok {
+		return aps, r.appointmentsErr
+	}
 	return r.appointments, r.appointmentsErr
 }
 
@@ -0,0 +1,354 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+const (
+	// maxSeriesOccurrences limita quantas ocorrências uma série cria de uma vez
+	// (um ano de atendimentos semanais).
+	maxSeriesOccurrences = 52
+
+	maxSeriesIntervalWeeks = 12
+)
+
+// Resultado de cada ocorrência no relatório da série.
+const (
+	SeriesOccurrenceCreated   = "created"
+	SeriesOccurrenceUpdated   = "updated"
+	SeriesOccurrenceCancelled = "cancelled"
+	SeriesOccurrenceFailed    = "failed"
+)
+
+// SeriesOccurrence é a linha do relatório por ocorrência. Error traz o código
+// de negócio quando a ocorrência não pôde ser criada/alterada (time_conflict,
+// day_closed, outside_working_hours, too_soon...).
+type SeriesOccurrence struct {
+	Date           string `json:"date"` // YYYY-MM-DD (horário da barbearia)
+	Time           string `json:"time"` // HH:MM
+	Status         string `json:"status"`
+	AppointmentID  *uint  `json:"appointment_id,omitempty"`
+	CoverageStatus string `json:"coverage_status,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// SeriesResult é a série e o relatório das ocorrências afetadas.
+// Series é nil quando nenhuma ocorrência pôde ser criada.
+type SeriesResult struct {
+	Series      *models.AppointmentSeries `json:"series"`
+	Occurrences []SeriesOccurrence        `json:"occurrences"`
+}
+
+// appointmentCreator é o fluxo normal de criação (CreatePrivateAppointment).
+type appointmentCreator interface {
+	Execute(ctx context.Context, in CreatePrivateAppointmentInput) (*models.Appointment, error)
+}
+
+// ======================================================
+// CREATE
+// ======================================================
+
+type CreateSeriesInput struct {
+	BarbershopID uint
+	BarberID     uint
+	UserID       uint // quem criou a série
+
+	ClientName  string
+	ClientPhone string
+	ClientEmail string
+
+	ProductID uint
+
+	StartDate     string // YYYY-MM-DD — define também o dia da semana
+	Time          string // HH:MM
+	IntervalWeeks int    // 0 = semanal
+	UntilDate     string // YYYY-MM-DD, exclusivo com Count
+	Count         int    // número de ocorrências, exclusivo com UntilDate
+	Notes         string
+}
+
+// CreateSeries cria uma série recorrente e todas as suas ocorrências.
+// Cada ocorrência passa pelo CreatePrivateAppointment, então expediente,
+// exceções de agenda, conflito e cota da assinatura (ReserveSubscriptionCut)
+// são validados um a um; as que falham entram no relatório e a série segue.
+type CreateSeries struct {
+	repo       domain.Repository
+	seriesRepo domain.SeriesRepository
+	create     appointmentCreator
+	audit      *audit.Dispatcher
+}
+
+func NewCreateSeries(
+	repo domain.Repository,
+	seriesRepo domain.SeriesRepository,
+	create appointmentCreator,
+	audit *audit.Dispatcher,
+) *CreateSeries {
+	return &CreateSeries{
+		repo:       repo,
+		seriesRepo: seriesRepo,
+		create:     create,
+		audit:      audit,
+	}
+}
+
+func (uc *CreateSeries) Execute(ctx context.Context, in CreateSeriesInput) (*SeriesResult, error) {
+	startDate, err := time.Parse("2006-01-02", in.StartDate)
+	if err != nil {
+		return nil, apperr.ErrBusiness("invalid_date_or_time")
+	}
+	if _, err := time.Parse("15:04", in.Time); err != nil {
+		return nil, apperr.ErrBusiness("invalid_date_or_time")
+	}
+
+	interval := in.IntervalWeeks
+	if interval == 0 {
+		interval = 1
+	}
+	if interval < 1 || interval > maxSeriesIntervalWeeks {
+		return nil, apperr.ErrBusiness("invalid_series_interval")
+	}
+
+	var untilDate *time.Time
+	switch {
+	case in.UntilDate == "" && in.Count <= 0:
+		return nil, apperr.ErrBusiness("series_end_required")
+	case in.UntilDate != "" && in.Count > 0:
+		return nil, apperr.ErrBusiness("invalid_series_end")
+	case in.UntilDate != "":
+		d, err := time.Parse("2006-01-02", in.UntilDate)
+		if err != nil || d.Before(startDate) {
+			return nil, apperr.ErrBusiness("invalid_series_end")
+		}
+		untilDate = &d
+	}
+	if in.Count > maxSeriesOccurrences {
+		return nil, apperr.ErrBusiness("series_too_long")
+	}
+
+	dates := expandSeriesDates(startDate, interval, untilDate, in.Count, maxSeriesOccurrences+1)
+	if len(dates) > maxSeriesOccurrences {
+		return nil, apperr.ErrBusiness("series_too_long")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("barbershop_not_found")
+	}
+
+	// Serviço inválido ou que o barbeiro não faz falharia em todas as
+	// ocorrências: recusa a série inteira.
+	product, err := uc.repo.GetProduct(ctx, in.BarbershopID, in.ProductID)
+	if err != nil || product == nil {
+		return nil, apperr.ErrBusiness("product_not_found")
+	}
+	if _, err := serviceForBarber(ctx, uc.repo, product, in.BarbershopID, in.BarberID); err != nil {
+		return nil, err
+	}
+
+	client, err := uc.repo.GetOrCreateClient(ctx, in.BarbershopID, in.ClientName, in.ClientPhone, in.ClientEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.AppointmentSeries{
+		BarbershopID:  in.BarbershopID,
+		BarberID:      in.BarberID,
+		ClientID:      client.ID,
+		ServiceID:     product.ID,
+		IntervalWeeks: interval,
+		StartDate:     startDate,
+		TimeOfDay:     in.Time,
+		UntilDate:     untilDate,
+		Notes:         in.Notes,
+		Status:        models.AppointmentSeriesStatusActive,
+	}
+	if in.Count > 0 {
+		count := in.Count
+		series.OccurrenceCount = &count
+	}
+	if in.UserID != 0 {
+		userID := in.UserID
+		series.CreatedByUserID = &userID
+	}
+
+	if err := uc.seriesRepo.CreateSeries(ctx, series); err != nil {
+		return nil, err
+	}
+
+	loc := timezone.Location(shop.Timezone)
+	result := &SeriesResult{Occurrences: make([]SeriesOccurrence, 0, len(dates))}
+	created := 0
+
+	for _, day := range dates {
+		occ := SeriesOccurrence{Date: day.Format("2006-01-02"), Time: in.Time}
+
+		ap, err := uc.create.Execute(ctx, CreatePrivateAppointmentInput{
+			BarbershopID: in.BarbershopID,
+			BarberID:     in.BarberID,
+			ClientName:   in.ClientName,
+			ClientPhone:  in.ClientPhone,
+			ClientEmail:  in.ClientEmail,
+			ProductID:    product.ID,
+			Date:         occ.Date,
+			Time:         in.Time,
+			Notes:        in.Notes,
+			SeriesID:     &series.ID,
+		})
+		if err != nil {
+			code, ok := occurrenceErrorCode(ctx, uc.repo, in.BarbershopID, in.BarberID, day, loc, err)
+			if !ok {
+				return nil, err
+			}
+			occ.Status = SeriesOccurrenceFailed
+			occ.Error = code
+		} else {
+			created++
+			apID := ap.ID
+			occ.Status = SeriesOccurrenceCreated
+			occ.AppointmentID = &apID
+			occ.CoverageStatus = string(ap.CoverageStatus)
+		}
+
+		result.Occurrences = append(result.Occurrences, occ)
+	}
+
+	if created == 0 {
+		// Série sem nenhuma ocorrência não tem o que gerenciar.
+		if err := uc.seriesRepo.DeleteSeries(ctx, in.BarbershopID, series.ID); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	result.Series = series
+
+	if uc.audit != nil {
+		uc.audit.Dispatch(audit.Event{
+			BarbershopID: in.BarbershopID,
+			UserID:       series.CreatedByUserID,
+			Action:       "appointment_series_created",
+			Entity:       "appointment_series",
+			EntityID:     &series.ID,
+			Metadata: map[string]any{
+				"barber_id":   in.BarberID,
+				"occurrences": len(dates),
+				"created":     created,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// ======================================================
+// GET
+// ======================================================
+
+// GetSeries retorna a série com as ocorrências ativas a partir de agora.
+type GetSeries struct {
+	seriesRepo domain.SeriesRepository
+}
+
+func NewGetSeries(seriesRepo domain.SeriesRepository) *GetSeries {
+	return &GetSeries{seriesRepo: seriesRepo}
+}
+
+// Execute: barberID != 0 restringe às séries do barbeiro (role "barber").
+func (uc *GetSeries) Execute(
+	ctx context.Context,
+	barbershopID, barberID, seriesID uint,
+) (*models.AppointmentSeries, []*models.Appointment, error) {
+	series, err := loadSeries(ctx, uc.seriesRepo, barbershopID, barberID, seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aps, err := uc.seriesRepo.ListSeriesAppointments(ctx, barbershopID, series.ID, time.Now().UTC())
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, aps, nil
+}
+
+// ======================================================
+// HELPERS
+// ======================================================
+
+// loadSeries busca a série; barberID != 0 esconde as séries de outros barbeiros.
+func loadSeries(
+	ctx context.Context,
+	seriesRepo domain.SeriesRepository,
+	barbershopID, barberID, seriesID uint,
+) (*models.AppointmentSeries, error) {
+	series, err := seriesRepo.GetSeries(ctx, barbershopID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil || (barberID != 0 && series.BarberID != barberID) {
+		return nil, apperr.ErrBusiness("series_not_found")
+	}
+	return series, nil
+}
+
+// expandSeriesDates gera as datas da série (dias em UTC 00:00), parando em
+// until (inclusive), em count ocorrências ou em limit, o que vier primeiro.
+func expandSeriesDates(start time.Time, intervalWeeks int, until *time.Time, count, limit int) []time.Time {
+	var dates []time.Time
+	for d := start; len(dates) < limit; d = d.AddDate(0, 0, 7*intervalWeeks) {
+		if until != nil && d.After(*until) {
+			break
+		}
+		if count > 0 && len(dates) >= count {
+			break
+		}
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// occurrenceErrorCode traduz o erro de uma ocorrência para o relatório.
+// outside_working_hours vira day_closed quando há exceção de agenda fechando
+// o dia. Retorna false para erros de infraestrutura.
+func occurrenceErrorCode(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID, barberID uint,
+	day time.Time,
+	loc *time.Location,
+	err error,
+) (string, bool) {
+	var be apperr.BusinessError
+	if !errors.As(err, &be) {
+		return "", false
+	}
+	if be.Code != "outside_working_hours" {
+		return be.Code, true
+	}
+
+	dayLocal := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	override, oErr := repo.GetScheduleOverride(
+		ctx,
+		barbershopID,
+		barberID,
+		dayLocal.Format("2006-01-02"),
+		int(dayLocal.Weekday()),
+		int(dayLocal.Month()),
+		dayLocal.Year(),
+	)
+	if oErr == nil && override != nil && override.Closed {
+		return "day_closed", true
+	}
+	return be.Code, true
+}
@@ -0,0 +1,211 @@
+package appointment
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+	ucSubscription "github.com/BruksfildServices01/barber-scheduler/internal/usecase/subscription"
+)
+
+// CancelSeries cancela as ocorrências futuras de uma série e a encerra.
+// Ocorrências passadas ficam como estão. Cada cancelamento libera a reserva
+// da assinatura como CancelAppointment, mas não conta como cancelamento nas
+// métricas do cliente: encerrar a série é decisão da barbearia com o cliente,
+// não desistência de um atendimento.
+type CancelSeries struct {
+	db               *gorm.DB
+	repo             txableRepository
+	seriesRepo       domain.SeriesRepository
+	subscriptionRepo txableSubscriptionRepo
+	audit            *audit.Dispatcher
+	releaseUC        *ucSubscription.ReleaseSubscriptionCut
+	waitlist         domainWaitlist.SlotListener
+	calendar         domain.CalendarSync
+	coupons          txableCouponRepo
+}
+
+func NewCancelSeries(
+	db *gorm.DB,
+	repo txableRepository,
+	seriesRepo domain.SeriesRepository,
+	subscriptionRepo txableSubscriptionRepo,
+	audit *audit.Dispatcher,
+	releaseUC *ucSubscription.ReleaseSubscriptionCut,
+) *CancelSeries {
+	return &CancelSeries{
+		db:               db,
+		repo:             repo,
+		seriesRepo:       seriesRepo,
+		subscriptionRepo: subscriptionRepo,
+		audit:            audit,
+		releaseUC:        releaseUC,
+	}
+}
+
+// WithWaitlist oferece os horários liberados à lista de espera.
+func (uc *CancelSeries) WithWaitlist(l domainWaitlist.SlotListener) *CancelSeries {
+	uc.waitlist = l
+	return uc
+}
+
+// WithCoupons devolve ao cupom os usos das ocorrências canceladas.
+func (uc *CancelSeries) WithCoupons(r txableCouponRepo) *CancelSeries {
+	uc.coupons = r
+	return uc
+}
+
+// WithCalendarSync remove do Google Calendar os eventos das ocorrências canceladas.
+func (uc *CancelSeries) WithCalendarSync(c domain.CalendarSync) *CancelSeries {
+	uc.calendar = c
+	return uc
+}
+
+// Execute: barberID != 0 restringe às séries do barbeiro (role "barber").
+func (uc *CancelSeries) Execute(
+	ctx context.Context,
+	barbershopID, barberID, userID, seriesID uint,
+) (*SeriesResult, error) {
+	series, err := loadSeries(ctx, uc.seriesRepo, barbershopID, barberID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status != models.AppointmentSeriesStatusActive {
+		return nil, apperr.ErrBusiness("series_cancelled")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("barbershop_not_found")
+	}
+	loc := timezone.Location(shop.Timezone)
+
+	now := time.Now().UTC()
+	upcoming, err := uc.seriesRepo.ListSeriesAppointments(ctx, barbershopID, series.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SeriesResult{Series: series, Occurrences: make([]SeriesOccurrence, 0, len(upcoming))}
+	var freed []*models.Appointment
+
+	for _, ap := range upcoming {
+		startLocal := ap.StartTime.In(loc)
+		apID := ap.ID
+		occ := SeriesOccurrence{
+			Date:          startLocal.Format("2006-01-02"),
+			Time:          startLocal.Format("15:04"),
+			AppointmentID: &apID,
+		}
+
+		cancelled, err := uc.cancelOccurrence(ctx, barbershopID, ap.ID, now)
+		if err != nil {
+			if !apperr.IsBusiness(err, "invalid_state") {
+				return nil, err
+			}
+			// Mudou de estado entre a listagem e o cancelamento.
+			occ.Status = SeriesOccurrenceFailed
+			occ.Error = "invalid_state"
+		} else {
+			occ.Status = SeriesOccurrenceCancelled
+			freed = append(freed, cancelled)
+		}
+
+		result.Occurrences = append(result.Occurrences, occ)
+	}
+
+	series.Status = models.AppointmentSeriesStatusCancelled
+	if err := uc.seriesRepo.UpdateSeries(ctx, series); err != nil {
+		return nil, err
+	}
+
+	if uc.audit != nil {
+		uc.audit.Dispatch(audit.Event{
+			BarbershopID: barbershopID,
+			UserID:       &userID,
+			Action:       "appointment_series_cancelled",
+			Entity:       "appointment_series",
+			EntityID:     &series.ID,
+			Metadata: map[string]any{
+				"cancelled": len(freed),
+			},
+		})
+	}
+
+	return result, nil
+}
+
+func (uc *CancelSeries) cancelOccurrence(
+	ctx context.Context,
+	barbershopID, appointmentID uint,
+	now time.Time,
+) (*models.Appointment, error) {
+	var ap *models.Appointment
+
+	err := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := uc.repo.WithTx(tx)
+
+		loaded, err := txRepo.GetAppointmentByID(ctx, barbershopID, appointmentID)
+		if err != nil {
+			return err
+		}
+		if loaded == nil {
+			return apperr.ErrBusiness("invalid_state")
+		}
+		ap = loaded
+
+		if err := domain.Cancel(ap, now); err != nil {
+			return err
+		}
+		if err := txRepo.UpdateAppointment(ctx, ap); err != nil {
+			return err
+		}
+
+		if ap.ReservedSubscriptionCut && ap.ClientID != nil && uc.releaseUC != nil {
+			txSubRepo := uc.subscriptionRepo.WithTx(tx)
+			for i := 0; i < ap.ReservedCuts(); i++ {
+				if err := uc.releaseUC.Execute(ctx, barbershopID, *ap.ClientID, txSubRepo); err != nil {
+					log.Printf("[CancelSeries] release subscription cut failed for client %d: %v", *ap.ClientID, err)
+					break
+				}
+			}
+		}
+
+		if uc.coupons != nil {
+			if err := uc.coupons.WithTx(tx).VoidForAppointment(ctx, ap.ID); err != nil {
+				return err
+			}
+		}
+
+		// Agenda e lista de espera entram na mesma transação pelo outbox.
+		octx := outbox.ContextWithTx(ctx, tx)
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(octx, barbershopID, ap.ID)
+		}
+		if uc.waitlist != nil && ap.BarberID != nil {
+			if err := uc.waitlist.SlotFreed(octx, domainWaitlist.FreedSlot{
+				BarbershopID: barbershopID,
+				BarberID:     *ap.BarberID,
+				StartTime:    ap.StartTime,
+				EndTime:      ap.EndTime,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return ap, err
+}
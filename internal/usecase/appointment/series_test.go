@@ -0,0 +1,427 @@
+package appointment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// ── Mocks ────────────────────────────────────────────────────────────────────
+
+// mockSeriesRepo implementa domain.SeriesRepository em memória.
+type mockSeriesRepo struct {
+	series       map[uint]*models.AppointmentSeries
+	nextID       uint
+	deleted      []uint
+	appointments []*models.Appointment
+
+	// moveErrByAppointment simula conflito ao mover a ocorrência.
+	moveErrByAppointment map[uint]error
+}
+
+func newMockSeriesRepo() *mockSeriesRepo {
+	return &mockSeriesRepo{series: map[uint]*models.AppointmentSeries{}, nextID: 10}
+}
+
+func (r *mockSeriesRepo) CreateSeries(_ context.Context, s *models.AppointmentSeries) error {
+	r.nextID++
+	s.ID = r.nextID
+	cp := *s
+	r.series[s.ID] = &cp
+	return nil
+}
+
+func (r *mockSeriesRepo) GetSeries(_ context.Context, _, id uint) (*models.AppointmentSeries, error) {
+	s, ok := r.series[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *mockSeriesRepo) UpdateSeries(_ context.Context, s *models.AppointmentSeries) error {
+	cp := *s
+	r.series[s.ID] = &cp
+	return nil
+}
+
+func (r *mockSeriesRepo) DeleteSeries(_ context.Context, _, id uint) error {
+	delete(r.series, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *mockSeriesRepo) ListSeriesAppointments(_ context.Context, _, seriesID uint, from time.Time) ([]*models.Appointment, error) {
+	var out []*models.Appointment
+	for _, ap := range r.appointments {
+		if ap.SeriesID != nil && *ap.SeriesID == seriesID && !ap.StartTime.Before(from) {
+			cp := *ap
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *mockSeriesRepo) ReassignSeriesAppointments(_ context.Context, _, fromID, toID uint, from time.Time) error {
+	for _, ap := range r.appointments {
+		if ap.SeriesID != nil && *ap.SeriesID == fromID && !ap.StartTime.Before(from) {
+			id := toID
+			ap.SeriesID = &id
+		}
+	}
+	return nil
+}
+
+func (r *mockSeriesRepo) MoveAppointmentIfFree(ctx context.Context, ap *models.Appointment, _, _ time.Time, onMoved func(context.Context, *gorm.DB) error) error {
+	if err, ok := r.moveErrByAppointment[ap.ID]; ok {
+		return err
+	}
+	for i, stored := range r.appointments {
+		if stored.ID == ap.ID {
+			cp := *ap
+			cp.SeriesID = stored.SeriesID
+			r.appointments[i] = &cp
+		}
+	}
+	if onMoved != nil {
+		return onMoved(ctx, nil)
+	}
+	return nil
+}
+
+// fakeCreator simula CreatePrivateAppointment: falha nas datas configuradas.
+type fakeCreator struct {
+	errByDate map[string]error
+	inputs    []CreatePrivateAppointmentInput
+}
+
+func (f *fakeCreator) Execute(_ context.Context, in CreatePrivateAppointmentInput) (*models.Appointment, error) {
+	f.inputs = append(f.inputs, in)
+	if err, ok := f.errByDate[in.Date]; ok {
+		return nil, err
+	}
+	return &models.Appointment{
+		ID:             uint(len(f.inputs)),
+		SeriesID:       in.SeriesID,
+		CoverageStatus: models.CoverageStatusNone,
+	}, nil
+}
+
+func seriesInput() CreateSeriesInput {
+	return CreateSeriesInput{
+		BarbershopID: 1,
+		BarberID:     1,
+		UserID:       1,
+		ClientName:   "Cliente Fixo",
+		ClientPhone:  "11999999999",
+		ProductID:    1,
+		StartDate:    "2030-06-10",
+		Time:         "10:00",
+		Count:        4,
+	}
+}
+
+// ── Testes ───────────────────────────────────────────────────────────────────
+
+func TestExpandSeriesDates(t *testing.T) {
+	start := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("por número de ocorrências", func(t *testing.T) {
+		dates := expandSeriesDates(start, 1, nil, 3, 53)
+		if len(dates) != 3 {
+			t.Fatalf("esperado 3 datas, obtido %d", len(dates))
+		}
+		if got := dates[2].Format("2006-01-02"); got != "2030-06-24" {
+			t.Errorf("terceira data esperada 2030-06-24, obtida %s", got)
+		}
+	})
+
+	t.Run("a cada 2 semanas até a data final (inclusive)", func(t *testing.T) {
+		until := time.Date(2030, 7, 8, 0, 0, 0, 0, time.UTC)
+		dates := expandSeriesDates(start, 2, &until, 0, 53)
+		if len(dates) != 3 {
+			t.Fatalf("esperado 3 datas, obtido %d", len(dates))
+		}
+		if got := dates[2].Format("2006-01-02"); got != "2030-07-08" {
+			t.Errorf("última data esperada 2030-07-08, obtida %s", got)
+		}
+	})
+}
+
+func TestCreateSeries(t *testing.T) {
+	ctx := context.Background()
+
+	newRepo := func() *mockRepo {
+		return &mockRepo{
+			shop:         defaultShop(),
+			product:      defaultProduct(),
+			workingHours: defaultWorkingHours(),
+			client:       zeroClient(),
+		}
+	}
+
+	t.Run("relata conflitos e dias fechados por ocorrência", func(t *testing.T) {
+		repo := newRepo()
+		repo.override = &models.ScheduleOverride{Closed: true}
+		seriesRepo := newMockSeriesRepo()
+		creator := &fakeCreator{errByDate: map[string]error{
+			"2030-06-17": apperr.ErrBusiness("outside_working_hours"),
+			"2030-06-24": apperr.ErrBusiness("time_conflict"),
+		}}
+		uc := NewCreateSeries(repo, seriesRepo, creator, nil)
+
+		result, err := uc.Execute(ctx, seriesInput())
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if result.Series == nil {
+			t.Fatal("série deveria ter sido criada")
+		}
+		if len(result.Occurrences) != 4 {
+			t.Fatalf("esperado 4 ocorrências, obtido %d", len(result.Occurrences))
+		}
+
+		want := []struct{ status, code string }{
+			{SeriesOccurrenceCreated, ""},
+			{SeriesOccurrenceFailed, "day_closed"},
+			{SeriesOccurrenceFailed, "time_conflict"},
+			{SeriesOccurrenceCreated, ""},
+		}
+		for i, w := range want {
+			occ := result.Occurrences[i]
+			if occ.Status != w.status || occ.Error != w.code {
+				t.Errorf("ocorrência %d: esperado %s/%q, obtido %s/%q", i, w.status, w.code, occ.Status, occ.Error)
+			}
+		}
+
+		for _, in := range creator.inputs {
+			if in.SeriesID == nil || *in.SeriesID != result.Series.ID {
+				t.Errorf("ocorrência %s sem series_id da série", in.Date)
+			}
+		}
+	})
+
+	t.Run("fora do expediente sem exceção continua outside_working_hours", func(t *testing.T) {
+		repo := newRepo()
+		creator := &fakeCreator{errByDate: map[string]error{
+			"2030-06-10": apperr.ErrBusiness("outside_working_hours"),
+		}}
+		uc := NewCreateSeries(repo, newMockSeriesRepo(), creator, nil)
+
+		result, err := uc.Execute(ctx, seriesInput())
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if got := result.Occurrences[0].Error; got != "outside_working_hours" {
+			t.Errorf("esperado outside_working_hours, obtido %q", got)
+		}
+	})
+
+	t.Run("sem nenhuma ocorrência a série é descartada", func(t *testing.T) {
+		seriesRepo := newMockSeriesRepo()
+		conflict := apperr.ErrBusiness("time_conflict")
+		creator := &fakeCreator{errByDate: map[string]error{
+			"2030-06-10": conflict, "2030-06-17": conflict,
+			"2030-06-24": conflict, "2030-07-01": conflict,
+		}}
+		uc := NewCreateSeries(newRepo(), seriesRepo, creator, nil)
+
+		result, err := uc.Execute(ctx, seriesInput())
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if result.Series != nil {
+			t.Error("série não deveria ser retornada")
+		}
+		if len(seriesRepo.deleted) != 1 || len(seriesRepo.series) != 0 {
+			t.Errorf("série deveria ter sido removida (deleted=%v)", seriesRepo.deleted)
+		}
+	})
+
+	t.Run("validações do padrão", func(t *testing.T) {
+		cases := []struct {
+			name string
+			edit func(*CreateSeriesInput)
+			code string
+		}{
+			{"sem fim", func(in *CreateSeriesInput) { in.Count = 0 }, "series_end_required"},
+			{"data final e contagem", func(in *CreateSeriesInput) { in.UntilDate = "2030-08-01" }, "invalid_series_end"},
+			{"data final antes do início", func(in *CreateSeriesInput) { in.Count = 0; in.UntilDate = "2030-06-01" }, "invalid_series_end"},
+			{"intervalo grande demais", func(in *CreateSeriesInput) { in.IntervalWeeks = 13 }, "invalid_series_interval"},
+			{"ocorrências demais", func(in *CreateSeriesInput) { in.Count = 0; in.UntilDate = "2032-01-01" }, "series_too_long"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				in := seriesInput()
+				tc.edit(&in)
+				uc := NewCreateSeries(newRepo(), newMockSeriesRepo(), &fakeCreator{}, nil)
+				if _, err := uc.Execute(ctx, in); !apperr.IsBusiness(err, tc.code) {
+					t.Errorf("esperado %s, obtido %v", tc.code, err)
+				}
+			})
+		}
+	})
+}
+
+func TestUpdateSeries(t *testing.T) {
+	ctx := context.Background()
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+
+	// Série semanal com 4 ocorrências às 10:00 a partir de 2030-06-10.
+	setup := func() (*mockRepo, *mockSeriesRepo, uint) {
+		repo := &mockRepo{
+			shop:         defaultShop(),
+			product:      defaultProduct(),
+			workingHours: defaultWorkingHours(),
+		}
+		seriesRepo := newMockSeriesRepo()
+		series := &models.AppointmentSeries{
+			BarbershopID:  1,
+			BarberID:      1,
+			ClientID:      5,
+			ServiceID:     1,
+			IntervalWeeks: 1,
+			StartDate:     time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC),
+			TimeOfDay:     "10:00",
+			Status:        models.AppointmentSeriesStatusActive,
+		}
+		_ = seriesRepo.CreateSeries(ctx, series)
+
+		shopID, barberID, productID := uint(1), uint(1), uint(1)
+		for i := 0; i < 4; i++ {
+			start := time.Date(2030, 6, 10+7*i, 10, 0, 0, 0, loc)
+			seriesID := series.ID
+			seriesRepo.appointments = append(seriesRepo.appointments, &models.Appointment{
+				ID:              uint(100 + i),
+				BarbershopID:    &shopID,
+				BarberID:        &barberID,
+				BarberProductID: &productID,
+				StartTime:       start,
+				EndTime:         start.Add(time.Hour),
+				Status:          models.AppointmentStatusScheduled,
+				SeriesID:        &seriesID,
+			})
+		}
+		return repo, seriesRepo, series.ID
+	}
+
+	t.Run("this and following divide a série e relata conflitos", func(t *testing.T) {
+		repo, seriesRepo, seriesID := setup()
+		seriesRepo.moveErrByAppointment = map[uint]error{
+			103: apperr.ErrBusiness("time_conflict"),
+		}
+		uc := NewUpdateSeries(repo, seriesRepo, nil)
+
+		result, err := uc.Execute(ctx, UpdateSeriesInput{
+			BarbershopID:  1,
+			UserID:        1,
+			SeriesID:      seriesID,
+			Scope:         SeriesScopeFollowing,
+			AppointmentID: 102,
+			Time:          "14:00",
+		})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+
+		if result.Series.ID == seriesID {
+			t.Fatal("esperado uma nova série a partir da ocorrência 102")
+		}
+		if result.Series.TimeOfDay != "14:00" {
+			t.Errorf("novo horário esperado 14:00, obtido %s", result.Series.TimeOfDay)
+		}
+
+		original := seriesRepo.series[seriesID]
+		if original.UntilDate == nil || original.UntilDate.Format("2006-01-02") != "2030-06-23" {
+			t.Errorf("série original deveria terminar em 2030-06-23, obtido %v", original.UntilDate)
+		}
+
+		if len(result.Occurrences) != 2 {
+			t.Fatalf("esperado 2 ocorrências afetadas, obtido %d", len(result.Occurrences))
+		}
+		if occ := result.Occurrences[0]; occ.Status != SeriesOccurrenceUpdated || occ.Time != "14:00" {
+			t.Errorf("ocorrência 102: esperado updated às 14:00, obtido %+v", occ)
+		}
+		if occ := result.Occurrences[1]; occ.Status != SeriesOccurrenceFailed || occ.Error != "time_conflict" {
+			t.Errorf("ocorrência 103: esperado time_conflict, obtido %+v", occ)
+		}
+
+		for _, ap := range seriesRepo.appointments {
+			inNew := *ap.SeriesID == result.Series.ID
+			if (ap.ID >= 102) != inNew {
+				t.Errorf("ocorrência %d na série errada (%d)", ap.ID, *ap.SeriesID)
+			}
+		}
+	})
+
+	t.Run("this altera só a ocorrência e mantém a série", func(t *testing.T) {
+		repo, seriesRepo, seriesID := setup()
+		uc := NewUpdateSeries(repo, seriesRepo, nil)
+
+		notes := "trazer referência"
+		result, err := uc.Execute(ctx, UpdateSeriesInput{
+			BarbershopID:  1,
+			UserID:        1,
+			SeriesID:      seriesID,
+			Scope:         SeriesScopeThis,
+			AppointmentID: 101,
+			Notes:         &notes,
+		})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if result.Series.ID != seriesID || len(result.Occurrences) != 1 {
+			t.Fatalf("esperado 1 ocorrência da mesma série, obtido %+v", result)
+		}
+		if seriesRepo.series[seriesID].Notes != "" {
+			t.Error("observação da série não deveria mudar com escopo this")
+		}
+		if seriesRepo.appointments[1].Notes != notes || seriesRepo.appointments[2].Notes != "" {
+			t.Error("somente a ocorrência 101 deveria ter a observação")
+		}
+	})
+
+	t.Run("horário fora do expediente não altera a ocorrência", func(t *testing.T) {
+		repo, seriesRepo, seriesID := setup()
+		uc := NewUpdateSeries(repo, seriesRepo, nil)
+
+		result, err := uc.Execute(ctx, UpdateSeriesInput{
+			BarbershopID:  1,
+			SeriesID:      seriesID,
+			Scope:         SeriesScopeThis,
+			AppointmentID: 100,
+			Time:          "20:00",
+		})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if occ := result.Occurrences[0]; occ.Error != "outside_working_hours" {
+			t.Errorf("esperado outside_working_hours, obtido %+v", occ)
+		}
+		if seriesRepo.appointments[0].StartTime.In(loc).Hour() != 10 {
+			t.Error("ocorrência não deveria ter sido movida")
+		}
+	})
+
+	t.Run("barbeiro não acessa série de outro barbeiro", func(t *testing.T) {
+		repo, seriesRepo, seriesID := setup()
+		uc := NewUpdateSeries(repo, seriesRepo, nil)
+
+		_, err := uc.Execute(ctx, UpdateSeriesInput{
+			BarbershopID: 1,
+			BarberID:     2,
+			SeriesID:     seriesID,
+			Scope:        SeriesScopeAll,
+			Time:         "11:00",
+		})
+		if !apperr.IsBusiness(err, "series_not_found") {
+			t.Errorf("esperado series_not_found, obtido %v", err)
+		}
+	})
+}
@@ -0,0 +1,390 @@
+package appointment
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+// Escopo de uma edição de série.
+const (
+	SeriesScopeThis      = "this"      // só a ocorrência informada
+	SeriesScopeFollowing = "following" // a ocorrência informada e as seguintes
+	SeriesScopeAll       = "all"       // todas as ocorrências futuras
+)
+
+type UpdateSeriesInput struct {
+	BarbershopID uint
+	BarberID     uint // != 0 restringe às séries do barbeiro (role "barber")
+	UserID       uint
+	SeriesID     uint
+
+	Scope         string
+	AppointmentID uint // ocorrência de referência (this / following)
+
+	Date      string  // YYYY-MM-DD — só com escopo "this"
+	Time      string  // HH:MM
+	ProductID uint    // 0 = mantém o serviço
+	Notes     *string // nil = mantém a observação
+}
+
+// UpdateSeries altera horário, serviço ou observação das ocorrências de uma
+// série. As ocorrências são alteradas no lugar (não são canceladas e
+// recriadas), então a cobertura da assinatura e as métricas do cliente não
+// mudam. Cada ocorrência é validada contra expediente, exceções e conflito;
+// as que não cabem no novo horário ficam como estavam e entram no relatório.
+//
+// "following" a partir de uma ocorrência que não é a primeira divide a série:
+// a original termina na véspera e as seguintes passam para uma série nova.
+type UpdateSeries struct {
+	repo       domain.Repository
+	seriesRepo domain.SeriesRepository
+	audit      *audit.Dispatcher
+	calendar   domain.CalendarSync
+}
+
+func NewUpdateSeries(
+	repo domain.Repository,
+	seriesRepo domain.SeriesRepository,
+	audit *audit.Dispatcher,
+) *UpdateSeries {
+	return &UpdateSeries{
+		repo:       repo,
+		seriesRepo: seriesRepo,
+		audit:      audit,
+	}
+}
+
+// WithCalendarSync atualiza no Google Calendar os eventos das ocorrências alteradas.
+func (uc *UpdateSeries) WithCalendarSync(c domain.CalendarSync) *UpdateSeries {
+	uc.calendar = c
+	return uc
+}
+
+func (uc *UpdateSeries) Execute(ctx context.Context, in UpdateSeriesInput) (*SeriesResult, error) {
+	switch in.Scope {
+	case SeriesScopeThis, SeriesScopeFollowing, SeriesScopeAll:
+	default:
+		return nil, apperr.ErrBusiness("invalid_series_scope")
+	}
+	if in.Date != "" && in.Scope != SeriesScopeThis {
+		return nil, apperr.ErrBusiness("invalid_series_scope")
+	}
+	if in.Date == "" && in.Time == "" && in.ProductID == 0 && in.Notes == nil {
+		return nil, apperr.ErrBusiness("nothing_to_update")
+	}
+	if in.Date != "" {
+		if _, err := time.Parse("2006-01-02", in.Date); err != nil {
+			return nil, apperr.ErrBusiness("invalid_date_or_time")
+		}
+	}
+	if in.Time != "" {
+		if _, err := time.Parse("15:04", in.Time); err != nil {
+			return nil, apperr.ErrBusiness("invalid_date_or_time")
+		}
+	}
+	if in.Scope != SeriesScopeAll && in.AppointmentID == 0 {
+		return nil, apperr.ErrBusiness("series_appointment_required")
+	}
+
+	series, err := loadSeries(ctx, uc.seriesRepo, in.BarbershopID, in.BarberID, in.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status != models.AppointmentSeriesStatusActive {
+		return nil, apperr.ErrBusiness("series_cancelled")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("barbershop_not_found")
+	}
+	loc := timezone.Location(shop.Timezone)
+	now := time.Now().UTC()
+
+	// --------------------------------------------------
+	// Ocorrências afetadas
+	// --------------------------------------------------
+	var affected []*models.Appointment
+	if in.Scope == SeriesScopeAll {
+		affected, err = uc.seriesRepo.ListSeriesAppointments(ctx, in.BarbershopID, series.ID, now)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		all, err := uc.seriesRepo.ListSeriesAppointments(ctx, in.BarbershopID, series.ID, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		for i, ap := range all {
+			if ap.ID != in.AppointmentID {
+				continue
+			}
+			if in.Scope == SeriesScopeThis {
+				affected = all[i : i+1]
+			} else {
+				affected = all[i:]
+			}
+			break
+		}
+		if len(affected) == 0 {
+			return nil, apperr.ErrBusiness("appointment_not_found")
+		}
+	}
+
+	// Serviço novo: inválido ou fora do catálogo do barbeiro recusa a edição.
+	var newProduct *models.BarbershopService
+	if in.ProductID != 0 {
+		newProduct, err = uc.repo.GetProduct(ctx, in.BarbershopID, in.ProductID)
+		if err != nil || newProduct == nil {
+			return nil, apperr.ErrBusiness("product_not_found")
+		}
+		if _, err := serviceForBarber(ctx, uc.repo, newProduct, in.BarbershopID, series.BarberID); err != nil {
+			return nil, err
+		}
+	}
+
+	// --------------------------------------------------
+	// Série: divide ("following") e grava o novo padrão
+	// --------------------------------------------------
+	target := series
+	if in.Scope != SeriesScopeThis {
+		if in.Scope == SeriesScopeFollowing {
+			target, err = uc.splitAt(ctx, series, affected, loc, in.UserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if in.Time != "" {
+			target.TimeOfDay = in.Time
+		}
+		if newProduct != nil {
+			target.ServiceID = newProduct.ID
+		}
+		if in.Notes != nil {
+			target.Notes = *in.Notes
+		}
+		if err := uc.seriesRepo.UpdateSeries(ctx, target); err != nil {
+			return nil, err
+		}
+	}
+
+	// --------------------------------------------------
+	// Ocorrências
+	// --------------------------------------------------
+	minAdvance := shop.MinAdvanceMinutes
+	if minAdvance <= 0 {
+		minAdvance = 120
+	}
+
+	services := map[uint]*models.BarbershopService{}
+	result := &SeriesResult{Series: target, Occurrences: make([]SeriesOccurrence, 0, len(affected))}
+	updated := 0
+
+	for _, ap := range affected {
+		startLocal := ap.StartTime.In(loc)
+		date := startLocal.Format("2006-01-02")
+		if in.Date != "" {
+			date = in.Date
+		}
+		hm := startLocal.Format("15:04")
+		if in.Time != "" {
+			hm = in.Time
+		}
+
+		occ := SeriesOccurrence{Date: date, Time: hm}
+		apID := ap.ID
+		occ.AppointmentID = &apID
+
+		product := newProduct
+		if product == nil && ap.BarberProductID != nil {
+			product, err = uc.repo.GetProduct(ctx, in.BarbershopID, *ap.BarberProductID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err := uc.updateOccurrence(ctx, shop, loc, ap, product, services, date, hm, in.Notes, now, minAdvance)
+		if err != nil {
+			day, _ := time.Parse("2006-01-02", date)
+			code, ok := occurrenceErrorCode(ctx, uc.repo, in.BarbershopID, series.BarberID, day, loc, err)
+			if !ok {
+				return nil, err
+			}
+			occ.Status = SeriesOccurrenceFailed
+			occ.Error = code
+		} else {
+			updated++
+			occ.Status = SeriesOccurrenceUpdated
+		}
+
+		result.Occurrences = append(result.Occurrences, occ)
+	}
+
+	if uc.audit != nil {
+		userID := in.UserID
+		uc.audit.Dispatch(audit.Event{
+			BarbershopID: in.BarbershopID,
+			UserID:       &userID,
+			Action:       "appointment_series_updated",
+			Entity:       "appointment_series",
+			EntityID:     &target.ID,
+			Metadata: map[string]any{
+				"scope":       in.Scope,
+				"occurrences": len(affected),
+				"updated":     updated,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// updateOccurrence aplica a edição em uma ocorrência. Horário só é validado
+// quando muda: trocar apenas a observação não esbarra na antecedência mínima.
+func (uc *UpdateSeries) updateOccurrence(
+	ctx context.Context,
+	shop *models.Barbershop,
+	loc *time.Location,
+	ap *models.Appointment,
+	product *models.BarbershopService,
+	services map[uint]*models.BarbershopService,
+	date, hm string,
+	notes *string,
+	now time.Time,
+	minAdvance int,
+) error {
+	if product == nil {
+		return apperr.ErrBusiness("product_not_found")
+	}
+	if ap.BarberID == nil {
+		return apperr.ErrBusiness("appointment_not_found")
+	}
+	barberID := *ap.BarberID
+
+	svc, cached := services[product.ID]
+	if !cached {
+		var err error
+		svc, err = serviceForBarber(ctx, uc.repo, product, shop.ID, barberID)
+		if err != nil {
+			return err
+		}
+		services[product.ID] = svc
+	}
+
+	start, err := time.ParseInLocation("2006-01-02 15:04", date+" "+hm, loc)
+	if err != nil {
+		return apperr.ErrBusiness("invalid_date_or_time")
+	}
+	end := start.Add(time.Duration(svc.DurationMin) * time.Minute)
+
+	if !start.Equal(ap.StartTime) || !end.Equal(ap.EndTime) {
+		if start.Before(now.Add(time.Duration(minAdvance) * time.Minute)) {
+			return apperr.ErrBusiness("too_soon")
+		}
+		if err := assertWithinWorkingHours(ctx, uc.repo, shop.ID, barberID, start, end, loc); err != nil {
+			return err
+		}
+	}
+
+	moved := *ap
+	moved.StartTime = start
+	moved.EndTime = end
+	productID := product.ID
+	moved.BarberProductID = &productID
+	moved.Services = nil
+	if ap.BarberProductID == nil || *ap.BarberProductID != productID {
+		// Troca de serviço: a linha do agendamento acompanha, com a mesma
+		// cobertura decidida no booking.
+		moved.Services = appointmentServiceLines(shop.ID, []*models.BarbershopService{svc}, []lineCoverage{{
+			Status:   ap.CoverageStatus,
+			Reserved: ap.ReservedSubscriptionCut,
+		}})
+	}
+	if notes != nil {
+		moved.Notes = *notes
+	}
+
+	// A sincronização de agenda entra na transação da mudança pelo outbox.
+	var onMoved func(ctx context.Context, tx *gorm.DB) error
+	if uc.calendar != nil {
+		onMoved = func(ctx context.Context, tx *gorm.DB) error {
+			uc.calendar.AppointmentChanged(outbox.ContextWithTx(ctx, tx), shop.ID, ap.ID)
+			return nil
+		}
+	}
+
+	conflictStart, conflictEnd := applyTolerance(start, end, shop.ScheduleToleranceMinutes)
+	if err := uc.seriesRepo.MoveAppointmentIfFree(ctx, &moved, conflictStart, conflictEnd, onMoved); err != nil {
+		return err
+	}
+
+	*ap = moved
+	return nil
+}
+
+// splitAt divide a série na primeira ocorrência de affected. Se ela já é o
+// início da série, não há o que dividir e a própria série é retornada.
+func (uc *UpdateSeries) splitAt(
+	ctx context.Context,
+	series *models.AppointmentSeries,
+	affected []*models.Appointment,
+	loc *time.Location,
+	userID uint,
+) (*models.AppointmentSeries, error) {
+	first := affected[0].StartTime.In(loc)
+	firstDay := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	if !firstDay.After(series.StartDate) {
+		return series, nil
+	}
+
+	last := affected[len(affected)-1].StartTime.In(loc)
+	lastDay := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC)
+
+	next := *series
+	next.ID = 0
+	next.StartDate = firstDay
+	next.OccurrenceCount = nil
+	next.UntilDate = series.UntilDate
+	if next.UntilDate == nil {
+		next.UntilDate = &lastDay
+	}
+	if userID != 0 {
+		next.CreatedByUserID = &userID
+	}
+	next.CreatedAt = time.Time{}
+	next.UpdatedAt = time.Time{}
+
+	if err := uc.seriesRepo.CreateSeries(ctx, &next); err != nil {
+		return nil, err
+	}
+	if err := uc.seriesRepo.ReassignSeriesAppointments(
+		ctx, series.BarbershopID, series.ID, next.ID, affected[0].StartTime,
+	); err != nil {
+		return nil, err
+	}
+
+	// A série original passa a terminar na véspera da divisão.
+	until := firstDay.AddDate(0, 0, -1)
+	series.UntilDate = &until
+	series.OccurrenceCount = nil
+	if err := uc.seriesRepo.UpdateSeries(ctx, series); err != nil {
+		return nil, err
+	}
+
+	for _, ap := range affected {
+		ap.SeriesID = &next.ID
+	}
+	return &next, nil
+}
@@ -14,6 +14,26 @@ type effectiveWorkingHours struct {
 	EndTime    string // "HH:MM"
 	LunchStart string // "" quando não há almoço
 	LunchEnd   string // "" quando não há almoço
+	// Busy são os eventos pessoais do barbeiro no Google Calendar que caem no
+	// dia; bloqueiam a agenda como o almoço.
+	Busy []domain.BusyPeriod
+}
+
+// blocked indica se [start, end) cai no almoço ou num horário ocupado.
+func (ewh *effectiveWorkingHours) blocked(start, end time.Time, refDay time.Time, loc *time.Location) bool {
+	if ewh.LunchStart != "" && ewh.LunchEnd != "" {
+		lunchStart := parseHM(ewh.LunchStart, refDay, loc)
+		lunchEnd := parseHM(ewh.LunchEnd, refDay, loc)
+		if start.Before(lunchEnd) && end.After(lunchStart) {
+			return true
+		}
+	}
+	for _, busy := range ewh.Busy {
+		if start.Before(busy.End) && end.After(busy.Start) {
+			return true
+		}
+	}
+	return false
 }
 
 // resolveWorkingHours retorna o expediente efetivo do dia combinando working hours
@@ -32,6 +52,7 @@ type effectiveWorkingHours struct {
 //     LunchStart/LunchEnd do working hours original. Isso garante que o almoço
 //     configurado não desapareça quando apenas o expediente é alterado via override.
 //  5. Sem override → usa working hours original integralmente.
+//  6. Em dia com expediente, carrega os horários ocupados do Google Calendar.
 //
 // Disponibilidade e criação de agendamento devem chamar esta função para garantir
 // que validam exatamente o mesmo expediente efetivo.
@@ -62,6 +83,8 @@ func resolveWorkingHours(
 		return nil, err
 	}
 
+	var ewh *effectiveWorkingHours
+
 	if override != nil {
 		// Override fechado → dia sem expediente, independente do working hours.
 		if override.Closed {
@@ -77,24 +100,35 @@ func resolveWorkingHours(
 				lunchStart = wh.LunchStart
 				lunchEnd = wh.LunchEnd
 			}
-			return &effectiveWorkingHours{
+			ewh = &effectiveWorkingHours{
 				StartTime:  override.StartTime,
 				EndTime:    override.EndTime,
 				LunchStart: lunchStart,
 				LunchEnd:   lunchEnd,
-			}, nil
+			}
 		}
 	}
 
-	// Sem override aplicável: usa working hours padrão.
-	if wh == nil || !wh.Active || wh.StartTime == "" || wh.EndTime == "" {
-		return nil, nil // dia sem expediente configurado
+	if ewh == nil {
+		// Sem override aplicável: usa working hours padrão.
+		if wh == nil || !wh.Active || wh.StartTime == "" || wh.EndTime == "" {
+			return nil, nil // dia sem expediente configurado
+		}
+
+		ewh = &effectiveWorkingHours{
+			StartTime:  wh.StartTime,
+			EndTime:    wh.EndTime,
+			LunchStart: wh.LunchStart,
+			LunchEnd:   wh.LunchEnd,
+		}
+	}
+
+	dayStart := time.Date(dateLocal.Year(), dateLocal.Month(), dateLocal.Day(), 0, 0, 0, 0, dateLocal.Location())
+	busy, err := repo.ListBusyPeriods(ctx, barbershopID, barberID, dayStart.UTC(), dayStart.AddDate(0, 0, 1).UTC())
+	if err != nil {
+		return nil, err
 	}
+	ewh.Busy = busy
 
-	return &effectiveWorkingHours{
-		StartTime:  wh.StartTime,
-		EndTime:    wh.EndTime,
-		LunchStart: wh.LunchStart,
-		LunchEnd:   wh.LunchEnd,
-	}, nil
+	return ewh, nil
 }
@@ -0,0 +1,226 @@
+package calendarfeed
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/calendarfeed"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type mockFeedRepo struct {
+	feeds  []*models.CalendarFeed
+	events []domain.Event
+	from   time.Time
+}
+
+func sameBarber(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (m *mockFeedRepo) GetByToken(_ context.Context, token string) (*models.CalendarFeed, error) {
+	for _, f := range m.feeds {
+		if f.Token == token {
+			cp := *f
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockFeedRepo) Get(_ context.Context, barbershopID uint, barberID *uint) (*models.CalendarFeed, error) {
+	for _, f := range m.feeds {
+		if f.BarbershopID == barbershopID && sameBarber(f.BarberID, barberID) {
+			cp := *f
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockFeedRepo) CreateIfMissing(ctx context.Context, feed *models.CalendarFeed) error {
+	if existing, _ := m.Get(ctx, feed.BarbershopID, feed.BarberID); existing != nil {
+		return nil
+	}
+	feed.ID = uint(len(m.feeds) + 1)
+	m.feeds = append(m.feeds, feed)
+	return nil
+}
+
+func (m *mockFeedRepo) UpdateToken(_ context.Context, feedID uint, token string) error {
+	for _, f := range m.feeds {
+		if f.ID == feedID {
+			f.Token = token
+		}
+	}
+	return nil
+}
+
+func (m *mockFeedRepo) GetBarbershopByID(_ context.Context, barbershopID uint) (*models.Barbershop, error) {
+	return &models.Barbershop{ID: barbershopID, Name: "Barbearia Centro"}, nil
+}
+
+func (m *mockFeedRepo) GetBarberName(_ context.Context, _, _ uint) (string, error) {
+	return "Carlos", nil
+}
+
+func (m *mockFeedRepo) ListEvents(_ context.Context, _ uint, barberID *uint, from time.Time) ([]domain.Event, error) {
+	m.from = from
+	return m.events, nil
+}
+
+func TestGetFeed_CreatesTokenOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockFeedRepo{}
+	uc := NewGetFeed(repo)
+	barberID := uint(7)
+
+	first, err := uc.Execute(ctx, 1, &barberID)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if len(first.Token) != 64 {
+		t.Errorf("token com tamanho inesperado: %q", first.Token)
+	}
+
+	again, err := uc.Execute(ctx, 1, &barberID)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if again.Token != first.Token {
+		t.Error("segunda chamada deveria devolver o mesmo token")
+	}
+
+	shop, err := uc.Execute(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if shop.Token == first.Token || shop.BarberID != nil {
+		t.Errorf("feed da barbearia deveria ser outro: %+v", shop)
+	}
+}
+
+func TestRotateFeedToken_InvalidatesOldURL(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockFeedRepo{}
+	barberID := uint(7)
+
+	old, _ := NewGetFeed(repo).Execute(ctx, 1, &barberID)
+	rotated, err := NewRotateFeedToken(repo).Execute(ctx, 1, &barberID)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+	if rotated.Token == old.Token {
+		t.Fatal("rotação deveria trocar o token")
+	}
+
+	_, err = NewRenderFeed(repo).Execute(ctx, old.Token, time.Now())
+	if !apperr.IsBusiness(err, "calendar_feed_not_found") {
+		t.Errorf("token antigo deveria dar calendar_feed_not_found, obtido: %v", err)
+	}
+	if _, err := NewRenderFeed(repo).Execute(ctx, rotated.Token, time.Now()); err != nil {
+		t.Errorf("token novo deveria funcionar: %v", err)
+	}
+}
+
+func TestRenderFeed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2030, 1, 8, 13, 0, 0, 0, time.UTC)
+	barberID := uint(7)
+
+	newRepo := func() *mockFeedRepo {
+		return &mockFeedRepo{
+			feeds: []*models.CalendarFeed{
+				{ID: 1, BarbershopID: 1, BarberID: &barberID, Token: "barber-token"},
+				{ID: 2, BarbershopID: 1, Token: "shop-token"},
+			},
+			events: []domain.Event{
+				{
+					AppointmentID: 10, BarberName: "Carlos", ClientName: "João", ServiceName: "Corte",
+					Status: models.AppointmentStatusScheduled, StartTime: start, EndTime: start.Add(time.Hour),
+					Sequence: 1, UpdatedAt: now,
+				},
+				{
+					AppointmentID: 11, BarberName: "Carlos", ClientName: "Ana", ServiceName: "Barba",
+					Status: models.AppointmentStatusCancelled, StartTime: start.Add(2 * time.Hour), EndTime: start.Add(3 * time.Hour),
+					Sequence: 2, UpdatedAt: now,
+				},
+			},
+		}
+	}
+
+	t.Run("eventos com UID estável, SEQUENCE e STATUS", func(t *testing.T) {
+		repo := newRepo()
+		feed, err := NewRenderFeed(repo).Execute(ctx, "barber-token", now)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		body := string(feed.Body)
+
+		for _, want := range []string{
+			"X-WR-CALNAME:Carlos · Barbearia Centro\r\n",
+			"UID:appointment-10@corteon\r\n",
+			"SEQUENCE:1\r\n",
+			"STATUS:CONFIRMED\r\n",
+			"UID:appointment-11@corteon\r\n",
+			"SEQUENCE:2\r\n",
+			"STATUS:CANCELLED\r\n",
+			"DTSTART:20300108T130000Z\r\n",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("feed sem %q:\n%s", want, body)
+			}
+		}
+		if strings.Contains(body, "(Carlos)") {
+			t.Error("feed do barbeiro não deveria repetir o nome no título")
+		}
+		if !repo.from.Equal(now.Add(-feedLookback)) {
+			t.Errorf("janela começa em %v, esperado %v", repo.from, now.Add(-feedLookback))
+		}
+	})
+
+	t.Run("feed da barbearia leva o barbeiro no título", func(t *testing.T) {
+		feed, err := NewRenderFeed(newRepo()).Execute(ctx, "shop-token", now)
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if !strings.Contains(string(feed.Body), "SUMMARY:✂️ Corte — João (Carlos)") {
+			t.Errorf("título sem o barbeiro:\n%s", feed.Body)
+		}
+	})
+
+	t.Run("ETag só muda quando os agendamentos mudam", func(t *testing.T) {
+		repo := newRepo()
+		uc := NewRenderFeed(repo)
+
+		a, _ := uc.Execute(ctx, "barber-token", now)
+		b, _ := uc.Execute(ctx, "barber-token", now.Add(time.Minute))
+		if a.ETag != b.ETag {
+			t.Errorf("ETag mudou sem mudança nos dados: %s != %s", a.ETag, b.ETag)
+		}
+
+		repo.events[0].StartTime = start.Add(30 * time.Minute)
+		repo.events[0].Sequence++
+		c, _ := uc.Execute(ctx, "barber-token", now)
+		if c.ETag == a.ETag {
+			t.Error("ETag deveria mudar após reagendamento")
+		}
+		if !strings.Contains(string(c.Body), "SEQUENCE:2\r\nSTATUS:CONFIRMED") {
+			t.Errorf("SEQUENCE do reagendado deveria subir:\n%s", c.Body)
+		}
+	})
+
+	t.Run("token desconhecido retorna calendar_feed_not_found", func(t *testing.T) {
+		_, err := NewRenderFeed(newRepo()).Execute(ctx, "nope", now)
+		if !apperr.IsBusiness(err, "calendar_feed_not_found") {
+			t.Errorf("esperado calendar_feed_not_found, obtido: %v", err)
+		}
+	})
+}
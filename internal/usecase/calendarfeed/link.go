@@ -0,0 +1,95 @@
+package calendarfeed
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/calendarfeed"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// GetFeed devolve o feed do barbeiro (ou da barbearia, com barberID nil),
+// criando o token na primeira vez.
+type GetFeed struct {
+	repo domain.Repository
+}
+
+func NewGetFeed(repo domain.Repository) *GetFeed {
+	return &GetFeed{repo: repo}
+}
+
+func (uc *GetFeed) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	barberID *uint,
+) (*models.CalendarFeed, error) {
+	feed, err := uc.repo.Get(ctx, barbershopID, barberID)
+	if err != nil || feed != nil {
+		return feed, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.CreateIfMissing(ctx, &models.CalendarFeed{
+		BarbershopID: barbershopID,
+		BarberID:     barberID,
+		Token:        token,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Relê: um request concorrente pode ter criado o feed primeiro.
+	feed, err = uc.repo.Get(ctx, barbershopID, barberID)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, apperr.ErrBusiness("calendar_feed_not_found")
+	}
+	return feed, nil
+}
+
+// RotateFeedToken troca o token do feed. A URL antiga para de funcionar na
+// hora; quem assinou precisa assinar de novo.
+type RotateFeedToken struct {
+	repo domain.Repository
+	get  *GetFeed
+}
+
+func NewRotateFeedToken(repo domain.Repository) *RotateFeedToken {
+	return &RotateFeedToken{repo: repo, get: NewGetFeed(repo)}
+}
+
+func (uc *RotateFeedToken) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	barberID *uint,
+) (*models.CalendarFeed, error) {
+	feed, err := uc.get.Execute(ctx, barbershopID, barberID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.UpdateToken(ctx, feed.ID, token); err != nil {
+		return nil, err
+	}
+
+	feed.Token = token
+	return feed, nil
+}
+
+func newFeedToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,129 @@
+package calendarfeed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/calendarfeed"
+	"github.com/BruksfildServices01/barber-scheduler/internal/integration/calendar"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// feedLookback: o feed inclui a última semana para que cancelamentos e
+// remarcações recentes cheguem a quem assinou.
+const feedLookback = 7 * 24 * time.Hour
+
+// Feed é o VCALENDAR pronto para servir.
+type Feed struct {
+	Body []byte
+	// ETag forte: hash do corpo, que só muda quando os agendamentos mudam.
+	ETag string
+}
+
+// RenderFeed monta o .ics do feed identificado pelo token.
+type RenderFeed struct {
+	repo domain.Repository
+}
+
+func NewRenderFeed(repo domain.Repository) *RenderFeed {
+	return &RenderFeed{repo: repo}
+}
+
+func (uc *RenderFeed) Execute(ctx context.Context, token string, now time.Time) (*Feed, error) {
+	if token == "" {
+		return nil, apperr.ErrBusiness("calendar_feed_not_found")
+	}
+
+	feed, err := uc.repo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, apperr.ErrBusiness("calendar_feed_not_found")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, feed.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("calendar_feed_not_found")
+	}
+
+	name := shop.Name
+	if feed.BarberID != nil {
+		barberName, err := uc.repo.GetBarberName(ctx, feed.BarbershopID, *feed.BarberID)
+		if err != nil {
+			return nil, err
+		}
+		if barberName != "" {
+			name = barberName + " · " + shop.Name
+		}
+	}
+
+	events, err := uc.repo.ListEvents(ctx, feed.BarbershopID, feed.BarberID, now.Add(-feedLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	vevents := make([]calendar.FeedEvent, 0, len(events))
+	for _, ev := range events {
+		vevents = append(vevents, toFeedEvent(ev, feed.BarberID == nil))
+	}
+
+	body := calendar.BuildFeed(name, vevents)
+	sum := sha256.Sum256(body)
+
+	return &Feed{
+		Body: body,
+		ETag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}, nil
+}
+
+// toFeedEvent segue o título do Google Calendar ("✂️ serviço — cliente").
+// No feed da barbearia o barbeiro entra no título.
+func toFeedEvent(ev domain.Event, withBarber bool) calendar.FeedEvent {
+	service := ev.ServiceName
+	if service == "" {
+		service = "Atendimento"
+	}
+	client := ev.ClientName
+	if client == "" {
+		client = "Cliente"
+	}
+
+	summary := fmt.Sprintf("✂️ %s — %s", service, client)
+	if withBarber && ev.BarberName != "" {
+		summary += " (" + ev.BarberName + ")"
+	}
+
+	status := calendar.FeedStatusConfirmed
+	switch ev.Status {
+	case models.AppointmentStatusCancelled:
+		status = calendar.FeedStatusCancelled
+	case models.AppointmentStatusAwaitingPayment:
+		status = calendar.FeedStatusTentative
+	case models.AppointmentStatusNoShow:
+		summary = "Não compareceu · " + summary
+	}
+
+	description := ""
+	if ev.Notes != "" {
+		description = "Observação: " + ev.Notes
+	}
+
+	return calendar.FeedEvent{
+		UID:         fmt.Sprintf("appointment-%d@corteon", ev.AppointmentID),
+		Summary:     summary,
+		Description: description,
+		Start:       ev.StartTime,
+		End:         ev.EndTime,
+		Stamp:       ev.UpdatedAt,
+		Sequence:    ev.Sequence,
+		Status:      status,
+	}
+}
@@ -0,0 +1,369 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+var testNow = time.Date(2026, 5, 4, 14, 0, 0, 0, time.UTC)
+
+// fakeRepo guarda campanhas e destinatários em memória. clients é a base
+// que o segmento 1 devolve; optedOut e anonymized simulam mudanças no
+// cliente depois que a campanha começou.
+type fakeRepo struct {
+	domain.Repository
+	campaigns  map[uint]*models.Campaign
+	recipients []*models.CampaignRecipient
+	clients    []domain.SegmentClient
+	optedOut   map[uint]bool
+	anonymized map[uint]bool
+	connected  bool
+}
+
+func newFakeRepo(clients ...domain.SegmentClient) *fakeRepo {
+	return &fakeRepo{
+		campaigns:  map[uint]*models.Campaign{},
+		clients:    clients,
+		optedOut:   map[uint]bool{},
+		anonymized: map[uint]bool{},
+		connected:  true,
+	}
+}
+
+func (r *fakeRepo) addCampaign(c models.Campaign) *models.Campaign {
+	c.ID = uint(len(r.campaigns) + 1)
+	r.campaigns[c.ID] = &c
+	return &c
+}
+
+func (r *fakeRepo) GetSegment(_ context.Context, bid, id uint) (*models.ClientSegment, error) {
+	if id != 1 {
+		return nil, nil
+	}
+	return &models.ClientSegment{ID: 1, BarbershopID: bid, Name: "Sumidos", Filters: `{"min_days_since_last_visit":60}`}, nil
+}
+
+func (r *fakeRepo) MatchClients(context.Context, uint, domain.SegmentFilters, int, int) ([]domain.SegmentClient, int64, error) {
+	return r.clients, int64(len(r.clients)), nil
+}
+
+func (r *fakeRepo) GetCampaign(_ context.Context, _ uint, id uint) (*models.Campaign, error) {
+	c, ok := r.campaigns[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (r *fakeRepo) UpdateCampaign(_ context.Context, c *models.Campaign) (bool, error) {
+	cp := *c
+	r.campaigns[c.ID] = &cp
+	return true, nil
+}
+
+func (r *fakeRepo) CancelCampaign(_ context.Context, _ uint, id uint, at time.Time) (bool, error) {
+	r.campaigns[id].Status = models.CampaignStatusCancelled
+	r.campaigns[id].FinishedAt = &at
+	return true, nil
+}
+
+func (r *fakeRepo) DueCampaigns(_ context.Context, now time.Time) ([]models.Campaign, error) {
+	var out []models.Campaign
+	for id := uint(1); id <= uint(len(r.campaigns)); id++ {
+		c := r.campaigns[id]
+		if c.Status == models.CampaignStatusSending ||
+			(c.Status == models.CampaignStatusScheduled && !c.ScheduledAt.After(now)) {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) StartCampaign(_ context.Context, c *models.Campaign, recipients []models.CampaignRecipient, at time.Time) (bool, error) {
+	for i := range recipients {
+		rec := recipients[i]
+		rec.ID = uint(len(r.recipients) + 1)
+		r.recipients = append(r.recipients, &rec)
+	}
+	r.campaigns[c.ID].Status = models.CampaignStatusSending
+	r.campaigns[c.ID].StartedAt = &at
+	return true, nil
+}
+
+func (r *fakeRepo) PendingRecipients(_ context.Context, campaignID uint, limit int) ([]domain.PendingRecipient, error) {
+	var out []domain.PendingRecipient
+	for _, rec := range r.recipients {
+		if rec.CampaignID != campaignID || rec.Status != models.CampaignRecipientPending {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, domain.PendingRecipient{
+			CampaignRecipient: *rec,
+			ClientName:        "Cliente " + rec.Destination,
+			OptedOut:          r.optedOut[rec.ClientID],
+			Anonymized:        r.anonymized[rec.ClientID],
+		})
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) UpdateRecipient(_ context.Context, rec *models.CampaignRecipient) error {
+	cp := *rec
+	r.recipients[rec.ID-1] = &cp
+	return nil
+}
+
+func (r *fakeRepo) FinishCampaign(_ context.Context, id uint, at time.Time) error {
+	r.campaigns[id].Status = models.CampaignStatusCompleted
+	r.campaigns[id].FinishedAt = &at
+	return nil
+}
+
+func (r *fakeRepo) GetBarbershop(_ context.Context, id uint) (*models.Barbershop, error) {
+	return &models.Barbershop{ID: id, Name: "Barbearia Teste", Slug: "teste"}, nil
+}
+
+func (r *fakeRepo) WhatsAppConnected(context.Context, uint) (bool, error) {
+	return r.connected, nil
+}
+
+func (r *fakeRepo) byStatus(status string) int {
+	n := 0
+	for _, rec := range r.recipients {
+		if rec.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// fakeSender registra as mensagens e falha para os destinos em fail.
+type fakeSender struct {
+	sent []domainNotification.CampaignMessageInput
+	fail map[string]bool
+}
+
+func (s *fakeSender) NotifyCampaign(_ context.Context, in domainNotification.CampaignMessageInput) error {
+	if s.fail[in.ClientEmail+in.ClientPhone] {
+		return errors.New(strings.Repeat("x", 300))
+	}
+	s.sent = append(s.sent, in)
+	return nil
+}
+
+func newDispatch(repo *fakeRepo, senders Senders) (*DispatchCampaigns, *int) {
+	uc := NewDispatchCampaigns(repo, senders, "https://app.test")
+	uc.now = func() time.Time { return testNow }
+	sleeps := 0
+	uc.sleep = func(context.Context, time.Duration) { sleeps++ }
+	return uc, &sleeps
+}
+
+func segmentID() *uint {
+	id := uint(1)
+	return &id
+}
+
+func TestDispatchStartsAndSendsScheduledCampaign(t *testing.T) {
+	repo := newFakeRepo(
+		domain.SegmentClient{ID: 1, Name: "Ana", Email: "ana@example.com"},
+		domain.SegmentClient{ID: 2, Name: "Bia", Email: "bia@example.com", OptedOut: true},
+		domain.SegmentClient{ID: 3, Name: "Caio", Phone: "11999990000"},
+		domain.SegmentClient{ID: 4, Name: "Davi", Email: "davi@example.com"},
+	)
+	at := testNow.Add(-time.Minute)
+	c := repo.addCampaign(models.Campaign{
+		BarbershopID: 1, SegmentID: segmentID(), Channel: models.NotificationChannelEmail,
+		Subject: "Saudades, {{client_name}}", Body: "Volte: {{booking_link}}",
+		Status: models.CampaignStatusScheduled, ScheduledAt: &at,
+	})
+	sender := &fakeSender{fail: map[string]bool{"davi@example.com": true}}
+	uc, _ := newDispatch(repo, Senders{models.NotificationChannelEmail: sender})
+
+	res, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Started != 1 || res.Sent != 1 || res.Failed != 1 || res.Finished != 1 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	if len(repo.recipients) != 4 {
+		t.Fatalf("esperado 4 destinatários gravados, obtido %d", len(repo.recipients))
+	}
+	if got := repo.byStatus(models.CampaignRecipientSkipped); got != 2 {
+		t.Errorf("descadastrado e sem email devem ser pulados, obtido %d", got)
+	}
+	if reason := repo.recipients[1].Error; reason == nil || *reason != skipOptedOut {
+		t.Errorf("motivo do pulo = %v", reason)
+	}
+	if reason := repo.recipients[3].Error; reason == nil || len(*reason) != maxErrorLength {
+		t.Errorf("erro do envio deve ser truncado em %d", maxErrorLength)
+	}
+	if repo.campaigns[c.ID].Status != models.CampaignStatusCompleted {
+		t.Errorf("campanha deveria estar concluída, está %s", repo.campaigns[c.ID].Status)
+	}
+
+	msg := sender.sent[0]
+	if msg.ClientEmail != "ana@example.com" || msg.Subject != "Saudades, Cliente ana@example.com" {
+		t.Errorf("mensagem inesperada: %+v", msg)
+	}
+	if msg.Body != "Volte: https://app.test/teste" {
+		t.Errorf("link de agendamento não renderizado: %q", msg.Body)
+	}
+	if !strings.HasPrefix(msg.UnsubscribeURL, "https://app.test/descadastrar/") ||
+		strings.TrimPrefix(msg.UnsubscribeURL, "https://app.test/descadastrar/") != repo.recipients[0].UnsubscribeToken {
+		t.Errorf("link de descadastro inesperado: %q", msg.UnsubscribeURL)
+	}
+}
+
+func TestDispatchThrottlesWhatsAppPerBarbershop(t *testing.T) {
+	repo := newFakeRepo()
+	for i := uint(1); i <= 15; i++ {
+		repo.clients = append(repo.clients, domain.SegmentClient{ID: i, Phone: fmt.Sprintf("119999900%02d", i)})
+	}
+	at := testNow.Add(-time.Minute)
+	for range 2 {
+		repo.addCampaign(models.Campaign{
+			BarbershopID: 1, SegmentID: segmentID(), Channel: models.NotificationChannelWhatsApp,
+			Body: "Oi {{client_name}}", Status: models.CampaignStatusScheduled, ScheduledAt: &at,
+		})
+	}
+	sender := &fakeSender{}
+	uc, sleeps := newDispatch(repo, Senders{models.NotificationChannelWhatsApp: sender})
+
+	res, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sent != whatsAppPerRun {
+		t.Errorf("esperado %d envios na rodada, obtido %d", whatsAppPerRun, res.Sent)
+	}
+	if *sleeps != whatsAppPerRun-1 {
+		t.Errorf("esperado intervalo entre as mensagens (%d), obtido %d", whatsAppPerRun-1, *sleeps)
+	}
+	if repo.campaigns[1].Status != models.CampaignStatusCompleted {
+		t.Errorf("primeira campanha deveria ter terminado")
+	}
+	if repo.campaigns[2].Status != models.CampaignStatusSending {
+		t.Errorf("segunda campanha deveria continuar em envio, está %s", repo.campaigns[2].Status)
+	}
+
+	// a rodada seguinte envia o restante e conclui
+	res, err = uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sent != 10 || res.Finished != 1 || repo.campaigns[2].Status != models.CampaignStatusCompleted {
+		t.Errorf("segunda rodada inesperada: %+v", res)
+	}
+}
+
+func TestDispatchSkipsClientsThatOptedOutAfterStart(t *testing.T) {
+	repo := newFakeRepo(
+		domain.SegmentClient{ID: 1, Phone: "11999990001"},
+		domain.SegmentClient{ID: 2, Phone: "11999990002"},
+		domain.SegmentClient{ID: 3, Phone: "11999990003"},
+	)
+	c := repo.addCampaign(models.Campaign{
+		BarbershopID: 1, SegmentID: segmentID(), Channel: models.NotificationChannelWhatsApp,
+		Body: "Oi", Status: models.CampaignStatusScheduled, ScheduledAt: &testNow,
+	})
+	uc, _ := newDispatch(repo, Senders{models.NotificationChannelWhatsApp: &fakeSender{}})
+	if _, err := uc.start(context.Background(), repo.campaigns[c.ID]); err != nil {
+		t.Fatal(err)
+	}
+
+	repo.optedOut[1] = true
+	repo.anonymized[2] = true
+
+	res, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sent != 1 || res.Skipped != 2 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	if reason := repo.recipients[1].Error; reason == nil || *reason != skipAnonymized {
+		t.Errorf("motivo do pulo = %v", reason)
+	}
+}
+
+func TestDispatchCancelsCampaignWithoutSegment(t *testing.T) {
+	repo := newFakeRepo()
+	missing := uint(9)
+	c := repo.addCampaign(models.Campaign{
+		BarbershopID: 1, SegmentID: &missing, Channel: models.NotificationChannelEmail,
+		Status: models.CampaignStatusScheduled, ScheduledAt: &testNow,
+	})
+	uc, _ := newDispatch(repo, Senders{models.NotificationChannelEmail: &fakeSender{}})
+
+	res, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Started != 0 || repo.campaigns[c.ID].Status != models.CampaignStatusCancelled {
+		t.Errorf("campanha sem segmento deveria ser cancelada: %+v, %s", res, repo.campaigns[c.ID].Status)
+	}
+}
+
+func TestScheduleCampaign(t *testing.T) {
+	later := testNow.Add(48 * time.Hour)
+	tooFar := testNow.Add(maxScheduleAhead + time.Hour)
+	past := testNow.Add(-time.Hour)
+
+	cases := []struct {
+		name      string
+		campaign  models.Campaign
+		senders   Senders
+		connected bool
+		at        *time.Time
+		wantErr   error
+		wantAt    time.Time
+	}{
+		{"agora", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, nil, nil, testNow},
+		{"no passado vira agora", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, &past, nil, testNow},
+		{"data futura", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, &later, nil, later},
+		{"longe demais", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, &tooFar, ErrInvalidSchedule, time.Time{}},
+		{"sem segmento", models.Campaign{Channel: models.NotificationChannelEmail}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, nil, ErrSegmentRequired, time.Time{}},
+		{"email desabilitado", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail}, Senders{}, true, nil, ErrChannelUnavailable, time.Time{}},
+		{"whatsapp desconectado", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelWhatsApp}, Senders{models.NotificationChannelWhatsApp: &fakeSender{}}, false, nil, ErrWhatsAppNotConnected, time.Time{}},
+		{"já concluída", models.Campaign{SegmentID: segmentID(), Channel: models.NotificationChannelEmail, Status: models.CampaignStatusCompleted}, Senders{models.NotificationChannelEmail: &fakeSender{}}, true, nil, ErrCampaignNotEditable, time.Time{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.connected = tc.connected
+			tc.campaign.BarbershopID = 1
+			if tc.campaign.Status == "" {
+				tc.campaign.Status = models.CampaignStatusDraft
+			}
+			c := repo.addCampaign(tc.campaign)
+
+			uc := NewScheduleCampaign(repo, tc.senders)
+			uc.now = func() time.Time { return testNow }
+
+			out, err := uc.Execute(context.Background(), ScheduleCampaignInput{BarbershopID: 1, ID: c.ID, At: tc.at})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("esperado %v, obtido %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if out.Status != models.CampaignStatusScheduled || !out.ScheduledAt.Equal(tc.wantAt) {
+				t.Errorf("agendamento inesperado: %s %v", out.Status, out.ScheduledAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package campaign
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+)
+
+// maxScheduleAhead limita até quando uma campanha pode ser agendada.
+const maxScheduleAhead = 90 * 24 * time.Hour
+
+// Senders são os canais de envio configurados, por canal; canal sem
+// notifier não aceita campanhas.
+type Senders map[string]domainNotification.CampaignNotifier
+
+type CampaignInput struct {
+	BarbershopID uint
+	ID           uint // só na alteração
+	Name         string
+	SegmentID    *uint
+	Channel      string
+	Subject      string // só email
+	Body         string
+}
+
+// validate confere nome, texto e segmento, e devolve o nome limpo.
+func (in CampaignInput) validate(ctx context.Context, repo domain.Repository) (string, error) {
+	if in.BarbershopID == 0 {
+		return "", ErrInvalidBarbershop
+	}
+	name, err := validName(in.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := notification.ValidateCampaign(in.Channel, in.Subject, in.Body); err != nil {
+		return "", err
+	}
+	if in.SegmentID != nil {
+		s, err := repo.GetSegment(ctx, in.BarbershopID, *in.SegmentID)
+		if err != nil {
+			return "", err
+		}
+		if s == nil {
+			return "", ErrSegmentNotFound
+		}
+	}
+	return name, nil
+}
+
+type ListCampaigns struct {
+	repo domain.Repository
+}
+
+func NewListCampaigns(repo domain.Repository) *ListCampaigns {
+	return &ListCampaigns{repo: repo}
+}
+
+func (uc *ListCampaigns) Execute(ctx context.Context, barbershopID uint) ([]models.Campaign, error) {
+	return uc.repo.ListCampaigns(ctx, barbershopID)
+}
+
+// CampaignView é a campanha com o resumo de entrega e de retorno.
+type CampaignView struct {
+	models.Campaign
+	Stats domain.Stats `json:"stats"`
+}
+
+type GetCampaign struct {
+	repo domain.Repository
+}
+
+func NewGetCampaign(repo domain.Repository) *GetCampaign {
+	return &GetCampaign{repo: repo}
+}
+
+func (uc *GetCampaign) Execute(ctx context.Context, barbershopID, id uint) (*CampaignView, error) {
+	c, err := uc.repo.GetCampaign(ctx, barbershopID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	stats, err := uc.repo.CampaignStats(ctx, barbershopID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignView{Campaign: *c, Stats: *stats}, nil
+}
+
+// CreateCampaign cria a campanha como rascunho.
+type CreateCampaign struct {
+	repo domain.Repository
+}
+
+func NewCreateCampaign(repo domain.Repository) *CreateCampaign {
+	return &CreateCampaign{repo: repo}
+}
+
+func (uc *CreateCampaign) Execute(ctx context.Context, in CampaignInput) (*models.Campaign, error) {
+	name, err := in.validate(ctx, uc.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.Campaign{
+		BarbershopID: in.BarbershopID,
+		SegmentID:    in.SegmentID,
+		Name:         name,
+		Channel:      in.Channel,
+		Subject:      in.Subject,
+		Body:         in.Body,
+		Status:       models.CampaignStatusDraft,
+	}
+	if err := uc.repo.CreateCampaign(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateCampaign altera a campanha enquanto ela não começou; uma campanha
+// agendada continua agendada.
+type UpdateCampaign struct {
+	repo domain.Repository
+}
+
+func NewUpdateCampaign(repo domain.Repository) *UpdateCampaign {
+	return &UpdateCampaign{repo: repo}
+}
+
+func (uc *UpdateCampaign) Execute(ctx context.Context, in CampaignInput) (*models.Campaign, error) {
+	c, err := uc.repo.GetCampaign(ctx, in.BarbershopID, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCampaignNotFound
+	}
+	if c.Status != models.CampaignStatusDraft && c.Status != models.CampaignStatusScheduled {
+		return nil, ErrCampaignNotEditable
+	}
+	if c.Status == models.CampaignStatusScheduled && in.SegmentID == nil {
+		return nil, ErrSegmentRequired
+	}
+
+	name, err := in.validate(ctx, uc.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Name = name
+	c.SegmentID = in.SegmentID
+	c.Channel = in.Channel
+	c.Subject = in.Subject
+	c.Body = in.Body
+
+	ok, err := uc.repo.UpdateCampaign(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCampaignNotEditable
+	}
+	return c, nil
+}
+
+// DeleteCampaign apaga um rascunho. Campanhas agendadas são canceladas, e
+// as já enviadas ficam para o histórico e a atribuição.
+type DeleteCampaign struct {
+	repo domain.Repository
+}
+
+func NewDeleteCampaign(repo domain.Repository) *DeleteCampaign {
+	return &DeleteCampaign{repo: repo}
+}
+
+func (uc *DeleteCampaign) Execute(ctx context.Context, barbershopID, id uint) error {
+	c, err := uc.repo.GetCampaign(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return ErrCampaignNotFound
+	}
+
+	ok, err := uc.repo.DeleteCampaign(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCampaignNotDeletable
+	}
+	return nil
+}
+
+type ScheduleCampaignInput struct {
+	BarbershopID uint
+	ID           uint
+	// At nil ou no passado = envio no próximo ciclo do job.
+	At *time.Time
+}
+
+// ScheduleCampaign agenda o envio. Exige segmento e canal disponível: o
+// email habilitado ou a instância do WhatsApp conectada.
+type ScheduleCampaign struct {
+	repo    domain.Repository
+	senders Senders
+	now     func() time.Time
+}
+
+func NewScheduleCampaign(repo domain.Repository, senders Senders) *ScheduleCampaign {
+	return &ScheduleCampaign{repo: repo, senders: senders, now: time.Now}
+}
+
+func (uc *ScheduleCampaign) Execute(ctx context.Context, in ScheduleCampaignInput) (*models.Campaign, error) {
+	c, err := uc.repo.GetCampaign(ctx, in.BarbershopID, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCampaignNotFound
+	}
+	if c.Status != models.CampaignStatusDraft && c.Status != models.CampaignStatusScheduled {
+		return nil, ErrCampaignNotEditable
+	}
+	if c.SegmentID == nil {
+		return nil, ErrSegmentRequired
+	}
+
+	now := uc.now().UTC()
+	at := now
+	if in.At != nil && in.At.After(now) {
+		at = in.At.UTC()
+	}
+	if at.Sub(now) > maxScheduleAhead {
+		return nil, ErrInvalidSchedule
+	}
+
+	if uc.senders[c.Channel] == nil {
+		return nil, ErrChannelUnavailable
+	}
+	if c.Channel == models.NotificationChannelWhatsApp {
+		connected, err := uc.repo.WhatsAppConnected(ctx, c.BarbershopID)
+		if err != nil {
+			return nil, err
+		}
+		if !connected {
+			return nil, ErrWhatsAppNotConnected
+		}
+	}
+
+	c.Status = models.CampaignStatusScheduled
+	c.ScheduledAt = &at
+
+	ok, err := uc.repo.UpdateCampaign(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCampaignNotEditable
+	}
+	return c, nil
+}
+
+// CancelCampaign encerra a campanha; quem ainda não recebeu não recebe
+// mais.
+type CancelCampaign struct {
+	repo domain.Repository
+}
+
+func NewCancelCampaign(repo domain.Repository) *CancelCampaign {
+	return &CancelCampaign{repo: repo}
+}
+
+func (uc *CancelCampaign) Execute(ctx context.Context, barbershopID, id uint) error {
+	c, err := uc.repo.GetCampaign(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return ErrCampaignNotFound
+	}
+
+	ok, err := uc.repo.CancelCampaign(ctx, barbershopID, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCampaignFinished
+	}
+	return nil
+}
+
+type ListRecipientsInput struct {
+	BarbershopID uint
+	CampaignID   uint
+	Status       string // vazio = todos
+	Limit        int
+	Offset       int
+}
+
+type RecipientPage struct {
+	Total      int64                      `json:"total"`
+	Recipients []models.CampaignRecipient `json:"recipients"`
+}
+
+// ListRecipients lista os destinatários com o status de entrega de cada um.
+type ListRecipients struct {
+	repo domain.Repository
+}
+
+func NewListRecipients(repo domain.Repository) *ListRecipients {
+	return &ListRecipients{repo: repo}
+}
+
+func (uc *ListRecipients) Execute(ctx context.Context, in ListRecipientsInput) (*RecipientPage, error) {
+	switch in.Status {
+	case "", models.CampaignRecipientPending, models.CampaignRecipientSent, models.CampaignRecipientFailed,
+		models.CampaignRecipientSkipped, models.CampaignRecipientCancelled:
+	default:
+		return nil, ErrInvalidRecipientState
+	}
+
+	c, err := uc.repo.GetCampaign(ctx, in.BarbershopID, in.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	list, total, err := uc.repo.ListRecipients(ctx, in.BarbershopID, in.CampaignID, in.Status, in.Limit, in.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []models.CampaignRecipient{}
+	}
+	return &RecipientPage{Total: total, Recipients: list}, nil
+}
+
+type PreviewCampaignInput struct {
+	BarbershopID uint
+	Channel      string
+	Subject      string
+	Body         string
+}
+
+// PreviewCampaign renderiza a mensagem com os dados da barbearia e um
+// cliente de exemplo, sem salvar.
+type PreviewCampaign struct {
+	repo   domain.Repository
+	appURL string
+}
+
+func NewPreviewCampaign(repo domain.Repository, appURL string) *PreviewCampaign {
+	return &PreviewCampaign{repo: repo, appURL: appURL}
+}
+
+func (uc *PreviewCampaign) Execute(ctx context.Context, in PreviewCampaignInput) (*notification.RenderedTemplate, error) {
+	shop, err := uc.repo.GetBarbershop(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, ErrBarbershopNotFound
+	}
+
+	return notification.PreviewCampaign(in.Channel, in.Subject, in.Body, notification.TemplateSample{
+		BarbershopName:    shop.Name,
+		BarbershopPhone:   shop.Phone,
+		BarbershopAddress: shop.Address,
+		Timezone:          shop.Timezone,
+		AppURL:            uc.appURL,
+		BarbershopSlug:    shop.Slug,
+	})
+}
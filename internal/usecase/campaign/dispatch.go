@@ -0,0 +1,306 @@
+package campaign
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+)
+
+const (
+	// Envios por barbearia e canal a cada rodada do job (1 minuto). No
+	// WhatsApp o limite é da instância da barbearia: rajadas fazem o número
+	// ser bloqueado como spam.
+	whatsAppPerRun = 20
+	emailPerRun    = 100
+	// whatsAppGap espaça as mensagens da mesma instância.
+	whatsAppGap = 2 * time.Second
+)
+
+// Motivos gravados em destinatários pulados.
+const (
+	skipOptedOut      = "opted_out"
+	skipNoDestination = "no_destination"
+	skipAnonymized    = "anonymized"
+)
+
+const maxErrorLength = 255
+
+// DispatchResult resume uma rodada do job.
+type DispatchResult struct {
+	Started  int
+	Sent     int
+	Failed   int
+	Skipped  int
+	Finished int
+}
+
+// DispatchCampaigns é a rodada do job de campanhas: começa as agendadas que
+// venceram (gravando os destinatários do segmento naquele momento), envia
+// os pendentes dentro do limite de cada barbearia e canal e conclui as que
+// não têm mais pendentes.
+type DispatchCampaigns struct {
+	repo    domain.Repository
+	senders Senders
+	appURL  string
+	now     func() time.Time
+	sleep   func(context.Context, time.Duration)
+}
+
+func NewDispatchCampaigns(repo domain.Repository, senders Senders, appURL string) *DispatchCampaigns {
+	return &DispatchCampaigns{
+		repo:    repo,
+		senders: senders,
+		appURL:  appURL,
+		now:     time.Now,
+		sleep:   sleepCtx,
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+func perRun(channel string) int {
+	if channel == models.NotificationChannelWhatsApp {
+		return whatsAppPerRun
+	}
+	return emailPerRun
+}
+
+func (uc *DispatchCampaigns) Execute(ctx context.Context) (DispatchResult, error) {
+	var res DispatchResult
+
+	due, err := uc.repo.DueCampaigns(ctx, uc.now().UTC())
+	if err != nil {
+		return res, err
+	}
+
+	used := map[string]int{}
+	shops := map[uint]*models.Barbershop{}
+
+	for i := range due {
+		c := &due[i]
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		if c.Status == models.CampaignStatusScheduled {
+			started, err := uc.start(ctx, c)
+			if err != nil {
+				return res, err
+			}
+			if !started {
+				continue
+			}
+			res.Started++
+		}
+
+		key := fmt.Sprintf("%d/%s", c.BarbershopID, c.Channel)
+		budget := perRun(c.Channel) - used[key]
+		if budget <= 0 {
+			continue
+		}
+
+		pending, err := uc.repo.PendingRecipients(ctx, c.ID, budget)
+		if err != nil {
+			return res, err
+		}
+
+		shop, ok := shops[c.BarbershopID]
+		if !ok {
+			if shop, err = uc.repo.GetBarbershop(ctx, c.BarbershopID); err != nil {
+				return res, err
+			}
+			shops[c.BarbershopID] = shop
+		}
+		if shop == nil {
+			continue
+		}
+
+		for j := range pending {
+			p := &pending[j]
+
+			if skip := skipReason(p); skip != "" {
+				if err := uc.finish(ctx, &p.CampaignRecipient, models.CampaignRecipientSkipped, skip); err != nil {
+					return res, err
+				}
+				res.Skipped++
+				continue
+			}
+
+			if c.Channel == models.NotificationChannelWhatsApp && used[key] > 0 {
+				uc.sleep(ctx, whatsAppGap)
+			}
+			if err := ctx.Err(); err != nil {
+				return res, err
+			}
+			used[key]++
+
+			if sendErr := uc.send(ctx, c, shop, p); sendErr != nil {
+				log.Printf("[Campaign] send failed campaign=%d recipient=%d: %v", c.ID, p.ID, sendErr)
+				if err := uc.finish(ctx, &p.CampaignRecipient, models.CampaignRecipientFailed, sendErr.Error()); err != nil {
+					return res, err
+				}
+				res.Failed++
+				continue
+			}
+			if err := uc.finish(ctx, &p.CampaignRecipient, models.CampaignRecipientSent, ""); err != nil {
+				return res, err
+			}
+			res.Sent++
+		}
+
+		if len(pending) < budget {
+			if err := uc.repo.FinishCampaign(ctx, c.ID, uc.now().UTC()); err != nil {
+				return res, err
+			}
+			res.Finished++
+		}
+	}
+
+	return res, nil
+}
+
+// start grava os destinatários: os clientes do segmento agora, com o
+// contato do canal. Descadastrados e sem contato entram como pulados, para
+// o dono ver por que não receberam. Sem segmento, a campanha é cancelada.
+func (uc *DispatchCampaigns) start(ctx context.Context, c *models.Campaign) (bool, error) {
+	now := uc.now().UTC()
+
+	var segment *models.ClientSegment
+	if c.SegmentID != nil {
+		s, err := uc.repo.GetSegment(ctx, c.BarbershopID, *c.SegmentID)
+		if err != nil {
+			return false, err
+		}
+		segment = s
+	}
+	if segment == nil {
+		log.Printf("[Campaign] campaign=%d without segment, cancelling", c.ID)
+		_, err := uc.repo.CancelCampaign(ctx, c.BarbershopID, c.ID, now)
+		return false, err
+	}
+
+	filters, err := decodeFilters(segment.Filters)
+	if err != nil {
+		return false, err
+	}
+	clients, _, err := uc.repo.MatchClients(ctx, c.BarbershopID, filters, 0, 0)
+	if err != nil {
+		return false, err
+	}
+
+	recipients := make([]models.CampaignRecipient, 0, len(clients))
+	for _, cl := range clients {
+		token, err := newUnsubscribeToken()
+		if err != nil {
+			return false, err
+		}
+
+		r := models.CampaignRecipient{
+			CampaignID:       c.ID,
+			BarbershopID:     c.BarbershopID,
+			ClientID:         cl.ID,
+			Status:           models.CampaignRecipientPending,
+			UnsubscribeToken: token,
+		}
+		if c.Channel == models.NotificationChannelEmail {
+			r.Destination = cl.Email
+		} else {
+			r.Destination = cl.Phone
+		}
+
+		switch {
+		case cl.OptedOut:
+			r.Status, r.Error = models.CampaignRecipientSkipped, strPtr(skipOptedOut)
+		case r.Destination == "":
+			r.Status, r.Error = models.CampaignRecipientSkipped, strPtr(skipNoDestination)
+		}
+		recipients = append(recipients, r)
+	}
+
+	return uc.repo.StartCampaign(ctx, c, recipients, now)
+}
+
+// skipReason pula quem se descadastrou ou foi anonimizado depois que a
+// campanha começou.
+func skipReason(p *domain.PendingRecipient) string {
+	switch {
+	case p.Anonymized:
+		return skipAnonymized
+	case p.OptedOut:
+		return skipOptedOut
+	}
+	return ""
+}
+
+func (uc *DispatchCampaigns) send(
+	ctx context.Context,
+	c *models.Campaign,
+	shop *models.Barbershop,
+	p *domain.PendingRecipient,
+) error {
+	sender := uc.senders[c.Channel]
+	if sender == nil {
+		return ErrChannelUnavailable
+	}
+
+	vars := notification.CampaignVars(p.ClientName, shop.Name, shop.Phone, shop.Address, uc.appURL+"/"+shop.Slug)
+	subject, body, err := notification.RenderCampaign(c.Subject, c.Body, vars)
+	if err != nil {
+		return err
+	}
+
+	in := domainNotification.CampaignMessageInput{
+		BarbershopID:   c.BarbershopID,
+		BarbershopName: shop.Name,
+		Subject:        subject,
+		Body:           body,
+		UnsubscribeURL: uc.appURL + "/descadastrar/" + p.UnsubscribeToken,
+	}
+	if c.Channel == models.NotificationChannelEmail {
+		in.ClientEmail = p.Destination
+	} else {
+		in.ClientPhone = p.Destination
+	}
+	return sender.NotifyCampaign(ctx, in)
+}
+
+func (uc *DispatchCampaigns) finish(ctx context.Context, r *models.CampaignRecipient, status, reason string) error {
+	r.Status = status
+	r.Error = nil
+	if reason != "" {
+		if r := []rune(reason); len(r) > maxErrorLength {
+			reason = string(r[:maxErrorLength])
+		}
+		r.Error = &reason
+	}
+	if status == models.CampaignRecipientSent {
+		now := uc.now().UTC()
+		r.SentAt = &now
+	}
+	return uc.repo.UpdateRecipient(ctx, r)
+}
+
+func newUnsubscribeToken() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,25 @@
+package campaign
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop  = errors.New("invalid_barbershop")
+	ErrBarbershopNotFound = errors.New("barbershop_not_found")
+	ErrInvalidName        = errors.New("invalid_name")
+
+	ErrSegmentNotFound = errors.New("segment_not_found")
+	ErrSegmentInUse    = errors.New("segment_in_use")
+
+	ErrCampaignNotFound      = errors.New("campaign_not_found")
+	ErrCampaignNotEditable   = errors.New("campaign_not_editable")
+	ErrCampaignNotDeletable  = errors.New("campaign_not_deletable")
+	ErrCampaignFinished      = errors.New("campaign_already_finished")
+	ErrSegmentRequired       = errors.New("campaign_segment_required")
+	ErrInvalidSchedule       = errors.New("invalid_schedule")
+	ErrChannelUnavailable    = errors.New("campaign_channel_unavailable")
+	ErrWhatsAppNotConnected  = errors.New("whatsapp_not_connected")
+	ErrInvalidRecipientState = errors.New("invalid_recipient_status")
+
+	ErrUnsubscribeNotFound = errors.New("unsubscribe_token_not_found")
+	ErrClientNotFound      = errors.New("client_not_found")
+)
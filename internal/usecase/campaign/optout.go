@@ -0,0 +1,102 @@
+package campaign
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// UnsubscribeView é o que a página de descadastro mostra ao cliente.
+type UnsubscribeView struct {
+	BarbershopName string `json:"barbershop_name"`
+}
+
+// Unsubscribe atende o link de descadastro do email: View identifica a
+// barbearia e Execute registra o pedido. Avisos de agendamento continuam.
+type Unsubscribe struct {
+	repo domain.Repository
+}
+
+func NewUnsubscribe(repo domain.Repository) *Unsubscribe {
+	return &Unsubscribe{repo: repo}
+}
+
+func (uc *Unsubscribe) View(ctx context.Context, token string) (*UnsubscribeView, error) {
+	_, view, err := uc.find(ctx, token)
+	return view, err
+}
+
+func (uc *Unsubscribe) Execute(ctx context.Context, token string) (*UnsubscribeView, error) {
+	r, view, err := uc.find(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if _, err := uc.repo.SetClientOptOut(ctx, r.BarbershopID, r.ClientID, &now); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (uc *Unsubscribe) find(ctx context.Context, token string) (*models.CampaignRecipient, *UnsubscribeView, error) {
+	r, err := uc.repo.FindRecipientByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r == nil {
+		return nil, nil, ErrUnsubscribeNotFound
+	}
+
+	shop, err := uc.repo.GetBarbershop(ctx, r.BarbershopID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if shop == nil {
+		return nil, nil, ErrUnsubscribeNotFound
+	}
+	return r, &UnsubscribeView{BarbershopName: shop.Name}, nil
+}
+
+// SetMarketingOptOut é o ajuste manual pelo painel: marca ou desfaz o
+// pedido do cliente para não receber campanhas.
+type SetMarketingOptOut struct {
+	repo domain.Repository
+}
+
+func NewSetMarketingOptOut(repo domain.Repository) *SetMarketingOptOut {
+	return &SetMarketingOptOut{repo: repo}
+}
+
+func (uc *SetMarketingOptOut) Execute(ctx context.Context, barbershopID, clientID uint, optOut bool) error {
+	var at *time.Time
+	if optOut {
+		now := time.Now().UTC()
+		at = &now
+	}
+
+	ok, err := uc.repo.SetClientOptOut(ctx, barbershopID, clientID, at)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrClientNotFound
+	}
+	return nil
+}
+
+// OptOutByPhone descadastra pelo número do WhatsApp (resposta PARAR ao
+// bot); false quando nenhum cliente da barbearia tem o número.
+type OptOutByPhone struct {
+	repo domain.Repository
+}
+
+func NewOptOutByPhone(repo domain.Repository) *OptOutByPhone {
+	return &OptOutByPhone{repo: repo}
+}
+
+func (uc *OptOutByPhone) Execute(ctx context.Context, barbershopID uint, phone string) (bool, error) {
+	return uc.repo.OptOutByPhone(ctx, barbershopID, phone, time.Now().UTC())
+}
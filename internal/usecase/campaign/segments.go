@@ -0,0 +1,245 @@
+package campaign
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/campaign"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	maxNameLength = 100
+	// previewClients é quantos clientes a prévia do segmento lista.
+	previewClients = 20
+)
+
+// SegmentView é o segmento salvo com os filtros decodificados.
+type SegmentView struct {
+	ID        uint                  `json:"id"`
+	Name      string                `json:"name"`
+	Filters   domain.SegmentFilters `json:"filters"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func segmentView(s *models.ClientSegment) (*SegmentView, error) {
+	filters, err := decodeFilters(s.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return &SegmentView{
+		ID:        s.ID,
+		Name:      s.Name,
+		Filters:   filters,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}, nil
+}
+
+func decodeFilters(raw string) (domain.SegmentFilters, error) {
+	var f domain.SegmentFilters
+	if raw == "" {
+		return f, nil
+	}
+	err := json.Unmarshal([]byte(raw), &f)
+	return f, err
+}
+
+func validName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+type ListSegments struct {
+	repo domain.Repository
+}
+
+func NewListSegments(repo domain.Repository) *ListSegments {
+	return &ListSegments{repo: repo}
+}
+
+func (uc *ListSegments) Execute(ctx context.Context, barbershopID uint) ([]SegmentView, error) {
+	list, err := uc.repo.ListSegments(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SegmentView, 0, len(list))
+	for i := range list {
+		v, err := segmentView(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+type SaveSegmentInput struct {
+	BarbershopID uint
+	ID           uint // zero cria
+	Name         string
+	Filters      domain.SegmentFilters
+}
+
+// SaveSegment cria ou altera um segmento. Campanhas já iniciadas não
+// mudam: os destinatários foram gravados quando começaram.
+type SaveSegment struct {
+	repo domain.Repository
+}
+
+func NewSaveSegment(repo domain.Repository) *SaveSegment {
+	return &SaveSegment{repo: repo}
+}
+
+func (uc *SaveSegment) Execute(ctx context.Context, in SaveSegmentInput) (*SegmentView, error) {
+	if in.BarbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	name, err := validName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.Filters.Validate(); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(in.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &models.ClientSegment{BarbershopID: in.BarbershopID}
+	if in.ID != 0 {
+		s, err = uc.repo.GetSegment(ctx, in.BarbershopID, in.ID)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, ErrSegmentNotFound
+		}
+	}
+	s.Name = name
+	s.Filters = string(raw)
+
+	if err := uc.repo.SaveSegment(ctx, s); err != nil {
+		return nil, err
+	}
+	return segmentView(s)
+}
+
+// DeleteSegment apaga o segmento se nenhuma campanha em aberto o usa; as
+// encerradas ficam sem segmento.
+type DeleteSegment struct {
+	repo domain.Repository
+}
+
+func NewDeleteSegment(repo domain.Repository) *DeleteSegment {
+	return &DeleteSegment{repo: repo}
+}
+
+func (uc *DeleteSegment) Execute(ctx context.Context, barbershopID, id uint) error {
+	s, err := uc.repo.GetSegment(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return ErrSegmentNotFound
+	}
+
+	inUse, err := uc.repo.SegmentInUse(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrSegmentInUse
+	}
+
+	return uc.repo.DeleteSegment(ctx, barbershopID, id)
+}
+
+// SegmentPreview conta os clientes do segmento e quantos podem receber
+// em cada canal (com contato e sem descadastro).
+type SegmentPreview struct {
+	Total     int64                  `json:"total"`
+	Reachable ChannelCounts          `json:"reachable"`
+	OptedOut  int                    `json:"opted_out"`
+	Clients   []domain.SegmentClient `json:"clients"`
+}
+
+type ChannelCounts struct {
+	Email    int `json:"email"`
+	WhatsApp int `json:"whatsapp"`
+}
+
+// PreviewSegment aplica filtros (salvos ou não) e devolve as contagens e
+// os primeiros clientes por nome.
+type PreviewSegment struct {
+	repo domain.Repository
+}
+
+func NewPreviewSegment(repo domain.Repository) *PreviewSegment {
+	return &PreviewSegment{repo: repo}
+}
+
+func (uc *PreviewSegment) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	f domain.SegmentFilters,
+) (*SegmentPreview, error) {
+	if barbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	all, total, err := uc.repo.MatchClients(ctx, barbershopID, f, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &SegmentPreview{Total: total, Clients: all}
+	for _, c := range all {
+		if c.OptedOut {
+			p.OptedOut++
+			continue
+		}
+		if c.Email != "" {
+			p.Reachable.Email++
+		}
+		if c.Phone != "" {
+			p.Reachable.WhatsApp++
+		}
+	}
+	if len(p.Clients) > previewClients {
+		p.Clients = p.Clients[:previewClients]
+	}
+	if p.Clients == nil {
+		p.Clients = []domain.SegmentClient{}
+	}
+
+	return p, nil
+}
+
+// ExecuteSaved é a prévia de um segmento salvo.
+func (uc *PreviewSegment) ExecuteSaved(ctx context.Context, barbershopID, id uint) (*SegmentPreview, error) {
+	s, err := uc.repo.GetSegment(ctx, barbershopID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrSegmentNotFound
+	}
+	f, err := decodeFilters(s.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return uc.Execute(ctx, barbershopID, f)
+}
@@ -20,6 +20,11 @@ var (
 type CheckoutCartInput struct {
 	CartKey      string
 	BarbershopID uint
+
+	// Opcionais: cupom sobre os produtos e o cliente já identificado (exigido
+	// por cupons com limite por cliente ou de primeira visita).
+	CouponCode string
+	ClientID   *uint
 }
 
 type CheckoutCart struct {
@@ -81,8 +86,9 @@ func (uc *CheckoutCart) Execute(
 
 	orderInput := ucOrder.CreateOrderInput{
 		BarbershopID: input.BarbershopID,
-		ClientID:     nil,
+		ClientID:     input.ClientID,
 		Items:        items,
+		CouponCode:   strings.TrimSpace(input.CouponCode),
 	}
 
 	// Transactional path: CreateOrder and cart Clear run in the same DB
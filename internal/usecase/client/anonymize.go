@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +11,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/storage"
 )
 
 var (
@@ -22,15 +24,24 @@ var (
 // AnonymizeClient remove dados pessoais de um cliente em transação atômica.
 // O registro do cliente é mantido para preservar histórico financeiro e operacional.
 // Após anonimização: name="Cliente removido", phone=NULL, email=NULL.
+// Anotações, preferências, etiquetas e fotos do perfil são apagadas.
 type AnonymizeClient struct {
 	db    *gorm.DB
 	audit *audit.Dispatcher
+	store storage.Storage
 }
 
 func NewAnonymizeClient(db *gorm.DB, auditDispatcher *audit.Dispatcher) *AnonymizeClient {
 	return &AnonymizeClient{db: db, audit: auditDispatcher}
 }
 
+// WithStorage apaga também os arquivos das fotos do cliente. Sem storage
+// só os registros saem.
+func (uc *AnonymizeClient) WithStorage(store storage.Storage) *AnonymizeClient {
+	uc.store = store
+	return uc
+}
+
 func (uc *AnonymizeClient) Execute(
 	ctx context.Context,
 	barbershopID uint,
@@ -42,7 +53,9 @@ func (uc *AnonymizeClient) Execute(
 		reason = "lgpd_request"
 	}
 
-	return uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var photoURLs []string
+
+	err := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		// 1. Buscar cliente validando ownership por barbershop_id
 		var client models.Client
@@ -104,7 +117,35 @@ func (uc *AnonymizeClient) Execute(
 			return err
 		}
 
-		// 8. Anonimizar o cliente — sobrescrever PII, manter ID para integridade referencial
+		// 8. Encerrar o acesso ao portal — sessões e códigos de login
+		if err := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+			Delete(&models.ClientSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+			Delete(&models.ClientLoginCode{}).Error; err != nil {
+			return err
+		}
+
+		// 9. Apagar o perfil — anotações, preferências, etiquetas e fotos
+		if err := tx.Model(&models.ClientPhoto{}).
+			Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+			Pluck("url", &photoURLs).Error; err != nil {
+			return err
+		}
+		for _, m := range []any{
+			&models.ClientNote{},
+			&models.ClientPreferences{},
+			&models.ClientTagAssignment{},
+			&models.ClientPhoto{},
+		} {
+			if err := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, clientID).
+				Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		// 10. Anonimizar o cliente — sobrescrever PII, manter ID para integridade referencial
 		now := time.Now().UTC()
 		if err := tx.Model(&client).Updates(map[string]any{
 			"name":              "Cliente removido",
@@ -118,6 +159,21 @@ func (uc *AnonymizeClient) Execute(
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Arquivos só depois do commit: falha aqui deixa órfão no storage, mas
+	// nenhuma foto continua ligada ao cliente.
+	if uc.store != nil {
+		for _, url := range photoURLs {
+			key := uc.store.KeyFromURL(url)
+			if err := uc.store.Delete(ctx, key); err != nil {
+				log.Printf("[AnonymizeClient] failed to delete photo %s: %v", key, err)
+			}
+		}
+	}
+	return nil
 }
 
 // DispatchAudit deve ser chamado APÓS a transação confirmar com sucesso.
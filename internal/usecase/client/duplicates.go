@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	domainClient "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+)
+
+// Motivos de uma sugestão de duplicado e o peso de cada um no score.
+const (
+	DuplicateByPhone = "phone"
+	DuplicateByEmail = "email"
+	DuplicateByName  = "name"
+)
+
+var duplicateWeights = map[string]int{
+	DuplicateByPhone: 60,
+	DuplicateByEmail: 50,
+	DuplicateByName:  30,
+}
+
+type DuplicateClient struct {
+	ID                uint       `json:"id"`
+	Name              string     `json:"name"`
+	Phone             string     `json:"phone,omitempty"`
+	Email             string     `json:"email,omitempty"`
+	TotalAppointments int        `json:"total_appointments"`
+	LastAppointmentAt *time.Time `json:"last_appointment_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// DuplicatePair é uma sugestão de junção. SuggestedTargetID é o cadastro
+// com mais histórico (ou o mais antigo), que costuma ser o que fica.
+type DuplicatePair struct {
+	Clients           [2]DuplicateClient `json:"clients"`
+	Reasons           []string           `json:"reasons"`
+	Score             int                `json:"score"`
+	SuggestedTargetID uint               `json:"suggested_target_id"`
+}
+
+// FindDuplicates sugere pares de cadastros que parecem ser a mesma pessoa:
+// mesmo telefone (E.164), mesmo email normalizado ou nomes parecidos.
+// Só sugere — a junção é sempre decisão do dono (MergeClients).
+type FindDuplicates struct {
+	db *gorm.DB
+}
+
+func NewFindDuplicates(db *gorm.DB) *FindDuplicates {
+	return &FindDuplicates{db: db}
+}
+
+type duplicateRow struct {
+	DuplicateClient
+	phoneKey string
+	emailKey string
+	nameKey  string
+}
+
+func (uc *FindDuplicates) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	limit int,
+) ([]DuplicatePair, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var rows []DuplicateClient
+	if err := uc.db.WithContext(ctx).
+		Table("clients c").
+		Select(`c.id, c.name, COALESCE(c.phone, '') AS phone, COALESCE(c.email, '') AS email, c.created_at,
+			COALESCE(cm.total_appointments, 0) AS total_appointments, cm.last_appointment_at`).
+		Joins("LEFT JOIN client_metrics cm ON cm.client_id = c.id AND cm.barbershop_id = c.barbershop_id").
+		Where("c.barbershop_id = ? AND c.anonymized_at IS NULL AND c.merged_into_id IS NULL", barbershopID).
+		Order("c.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return suggestDuplicates(rows, limit), nil
+}
+
+// suggestDuplicates agrupa por telefone e email normalizados e compara
+// nomes só dentro do mesmo primeiro nome, para não comparar todos com todos.
+func suggestDuplicates(list []DuplicateClient, limit int) []DuplicatePair {
+	rows := make([]duplicateRow, len(list))
+	byPhone := map[string][]int{}
+	byEmail := map[string][]int{}
+	byFirstName := map[string][]int{}
+
+	for i, c := range list {
+		r := duplicateRow{
+			DuplicateClient: c,
+			phoneKey:        domainClient.NormalizePhone(c.Phone),
+			emailKey:        domainClient.NormalizeEmail(c.Email),
+			nameKey:         domainClient.NormalizeName(c.Name),
+		}
+		rows[i] = r
+		if r.phoneKey != "" {
+			byPhone[r.phoneKey] = append(byPhone[r.phoneKey], i)
+		}
+		if r.emailKey != "" {
+			byEmail[r.emailKey] = append(byEmail[r.emailKey], i)
+		}
+		if first, _, _ := strings.Cut(r.nameKey, " "); first != "" {
+			byFirstName[first] = append(byFirstName[first], i)
+		}
+	}
+
+	reasons := map[[2]int][]string{}
+	addGroups := func(groups map[string][]int, reason string, match func(a, b duplicateRow) bool) {
+		for _, idx := range groups {
+			for x := 0; x < len(idx); x++ {
+				for y := x + 1; y < len(idx); y++ {
+					if match == nil || match(rows[idx[x]], rows[idx[y]]) {
+						key := [2]int{idx[x], idx[y]}
+						reasons[key] = append(reasons[key], reason)
+					}
+				}
+			}
+		}
+	}
+	addGroups(byPhone, DuplicateByPhone, nil)
+	addGroups(byEmail, DuplicateByEmail, nil)
+	addGroups(byFirstName, DuplicateByName, func(a, b duplicateRow) bool {
+		return domainClient.SimilarNames(a.nameKey, b.nameKey)
+	})
+
+	pairs := make([]DuplicatePair, 0, len(reasons))
+	for key, rs := range reasons {
+		a, b := rows[key[0]].DuplicateClient, rows[key[1]].DuplicateClient
+		score := 0
+		for _, r := range rs {
+			score += duplicateWeights[r]
+		}
+		pairs = append(pairs, DuplicatePair{
+			Clients:           [2]DuplicateClient{a, b},
+			Reasons:           rs,
+			Score:             min(score, 100),
+			SuggestedTargetID: suggestedTarget(a, b),
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		return pairs[i].Clients[1].ID > pairs[j].Clients[1].ID
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs
+}
+
+func suggestedTarget(a, b DuplicateClient) uint {
+	if b.TotalAppointments > a.TotalAppointments {
+		return b.ID
+	}
+	return a.ID
+}
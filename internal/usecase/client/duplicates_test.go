@@ -0,0 +1,55 @@
+package client
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggestDuplicates(t *testing.T) {
+	list := []DuplicateClient{
+		{ID: 1, Name: "João da Silva", Phone: "(11) 99999-0000", Email: "joao.silva@gmail.com"},
+		{ID: 2, Name: "joao da silva", Phone: "+55 11 99999-0000", TotalAppointments: 4},
+		{ID: 3, Name: "Maria Souza", Email: "JoaoSilva+corte@gmail.com"},
+		{ID: 4, Name: "Pedro Santos", Phone: "11 98888-7777"},
+		{ID: 5, Name: "Pedro Santos Lima"},
+	}
+
+	pairs := suggestDuplicates(list, 50)
+
+	find := func(a, b uint) *DuplicatePair {
+		for i := range pairs {
+			if pairs[i].Clients[0].ID == a && pairs[i].Clients[1].ID == b {
+				return &pairs[i]
+			}
+		}
+		return nil
+	}
+
+	p := find(1, 2)
+	if p == nil {
+		t.Fatalf("1 e 2 deveriam ser sugeridos, obtido %+v", pairs)
+	}
+	if !slices.Contains(p.Reasons, DuplicateByPhone) || !slices.Contains(p.Reasons, DuplicateByName) {
+		t.Errorf("motivos esperados phone e name, obtido %v", p.Reasons)
+	}
+	if p.SuggestedTargetID != 2 {
+		t.Errorf("cadastro com mais histórico deveria ser o sugerido, obtido %d", p.SuggestedTargetID)
+	}
+
+	if p := find(1, 3); p == nil || !slices.Equal(p.Reasons, []string{DuplicateByEmail}) {
+		t.Errorf("1 e 3 deveriam casar só pelo email, obtido %+v", p)
+	}
+	if p := find(4, 5); p == nil || !slices.Equal(p.Reasons, []string{DuplicateByName}) {
+		t.Errorf("4 e 5 deveriam casar pelo nome, obtido %+v", p)
+	}
+	if find(2, 3) != nil {
+		t.Errorf("2 e 3 não têm nada em comum")
+	}
+
+	if pairs[0].Clients[0].ID != 1 || pairs[0].Clients[1].ID != 2 {
+		t.Errorf("par com mais motivos deveria vir primeiro, obtido %+v", pairs[0])
+	}
+	if got := suggestDuplicates(list, 1); len(got) != 1 {
+		t.Errorf("limite não respeitado: %d pares", len(got))
+	}
+}
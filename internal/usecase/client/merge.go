@@ -0,0 +1,529 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainMetrics "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
+)
+
+// mergedTables são as tabelas cujos registros passam para o cadastro
+// mantido. Pagamentos, fechamentos e tickets seguem o agendamento ou o
+// pedido, que carregam o client_id.
+var mergedTables = []string{
+	"appointments",
+	"orders",
+	"subscriptions",
+	"appointment_series",
+	"client_packages",
+	"loyalty_entries",
+	"coupon_redemptions",
+	"campaign_recipients",
+	"client_notes",
+	"client_photos",
+	"client_tag_assignments",
+	"client_preferences",
+}
+
+// mergeConflicts filtra as linhas do source que colidiriam com um índice
+// único do target; essas ficam no source e voltam com ele no desfazer.
+var mergeConflicts = map[string]string{
+	// UNIQUE(campaign_id, client_id)
+	"campaign_recipients": "campaign_id NOT IN (SELECT campaign_id FROM campaign_recipients WHERE client_id = ?)",
+	// UNIQUE(client_id, tag_id)
+	"client_tag_assignments": "tag_id NOT IN (SELECT tag_id FROM client_tag_assignments WHERE client_id = ?)",
+	// uma linha por cliente: vale a preferência do target
+	"client_preferences": "NOT EXISTS (SELECT 1 FROM client_preferences WHERE client_id = ?)",
+}
+
+// clientContact é o contato de um cadastro em um momento da junção.
+type clientContact struct {
+	Phone             string     `json:"phone"`
+	Email             string     `json:"email"`
+	MarketingOptOutAt *time.Time `json:"marketing_opt_out_at,omitempty"`
+}
+
+// mergeSnapshot é o conteúdo de client_merges.snapshot: o bastante para
+// desfazer a junção sem apagar o que aconteceu depois dela.
+type mergeSnapshot struct {
+	TargetBefore  clientContact                `json:"target_before"`
+	TargetAfter   clientContact                `json:"target_after"`
+	Source        clientContact                `json:"source"`
+	TargetMetrics *domainMetrics.ClientMetrics `json:"target_metrics,omitempty"`
+	SourceMetrics *domainMetrics.ClientMetrics `json:"source_metrics,omitempty"`
+	Moved         map[string][]uint            `json:"moved"`
+}
+
+// MergeClients junta um cadastro duplicado (source) no cadastro mantido
+// (target): os registros do source passam para o target, as métricas são
+// somadas e a categoria recalculada. O source não é apagado — fica marcado
+// com merged_into_id, sem contato, fora das listas — e a junção pode ser
+// desfeita (Undo).
+type MergeClients struct {
+	db      *gorm.DB
+	audit   *audit.Dispatcher
+	metrics *infraRepo.ClientMetricsGormRepository
+	rules   domainMetrics.ClassificationRulesRepository
+	now     func() time.Time
+}
+
+func NewMergeClients(
+	db *gorm.DB,
+	auditDispatcher *audit.Dispatcher,
+	metrics *infraRepo.ClientMetricsGormRepository,
+) *MergeClients {
+	return &MergeClients{
+		db:      db,
+		audit:   auditDispatcher,
+		metrics: metrics,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithRules recalcula a categoria com as regras da barbearia; sem elas,
+// valem as padrão.
+func (uc *MergeClients) WithRules(rules domainMetrics.ClassificationRulesRepository) *MergeClients {
+	uc.rules = rules
+	return uc
+}
+
+// Execute junta sourceID em targetID e devolve o registro da junção.
+func (uc *MergeClients) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	targetID uint,
+	sourceID uint,
+	userID uint,
+) (*models.ClientMerge, error) {
+	if targetID == sourceID {
+		return nil, apperr.ErrBusiness("same_client")
+	}
+
+	rules, err := ucMetrics.RulesFor(ctx, uc.rules, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	var merge models.ClientMerge
+
+	err = uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := outbox.ContextWithTx(ctx, tx)
+		now := uc.now()
+
+		// 1. Travar os dois cadastros (em ordem de id, para não haver
+		// deadlock entre junções cruzadas) e validar
+		clients, err := lockClients(tx, barbershopID, targetID, sourceID)
+		if err != nil {
+			return err
+		}
+		target, source := clients[targetID], clients[sourceID]
+		for _, c := range []*models.Client{target, source} {
+			if c.AnonymizedAt != nil {
+				return apperr.ErrBusiness("client_anonymized")
+			}
+			if c.MergedIntoID != nil {
+				return apperr.ErrBusiness("client_already_merged")
+			}
+		}
+
+		// 2. Assinaturas: a barbearia admite uma em aberto por cliente
+		targetSubs, err := openSubscriptions(tx, barbershopID, targetID)
+		if err != nil {
+			return err
+		}
+		sourceSubs, err := openSubscriptions(tx, barbershopID, sourceID)
+		if err != nil {
+			return err
+		}
+		if targetSubs > 0 && sourceSubs > 0 {
+			return apperr.ErrBusiness("both_have_subscriptions")
+		}
+
+		snap := mergeSnapshot{
+			TargetBefore: contactOf(target),
+			Source:       contactOf(source),
+			Moved:        map[string][]uint{},
+		}
+
+		// 3. Mover os registros, guardando os ids para o desfazer
+		for _, table := range mergedTables {
+			q := tx.Table(table).Where("client_id = ?", sourceID)
+			if cond, ok := mergeConflicts[table]; ok {
+				q = q.Where(cond, targetID)
+			}
+			var ids []uint
+			if err := q.Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Table(table).
+				Where("id IN ?", ids).
+				Update("client_id", targetID).Error; err != nil {
+				return err
+			}
+			snap.Moved[table] = ids
+		}
+
+		// 4. Encerrar o acesso do source ao portal
+		if err := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, sourceID).
+			Delete(&models.ClientSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("barbershop_id = ? AND client_id = ?", barbershopID, sourceID).
+			Delete(&models.ClientLoginCode{}).Error; err != nil {
+			return err
+		}
+
+		// 5. Contato: o target herda o que não tem; o source perde o seu,
+		// para que o agendamento público encontre o target. O telefone é
+		// único por barbearia, então o source é limpo antes.
+		if err := tx.Model(source).Updates(map[string]any{
+			"phone":          nil,
+			"email":          nil,
+			"merged_into_id": targetID,
+			"merged_at":      now,
+		}).Error; err != nil {
+			return err
+		}
+		after := snap.TargetBefore
+		if after.Phone == "" {
+			after.Phone = snap.Source.Phone
+		}
+		if after.Email == "" {
+			after.Email = snap.Source.Email
+		}
+		if after.MarketingOptOutAt == nil {
+			after.MarketingOptOutAt = snap.Source.MarketingOptOutAt
+		}
+		if !sameContact(after, snap.TargetBefore) {
+			if err := tx.Model(target).Updates(map[string]any{
+				"phone":                nullIfEmpty(after.Phone),
+				"email":                nullIfEmpty(after.Email),
+				"marketing_opt_out_at": after.MarketingOptOutAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		snap.TargetAfter = after
+
+		// 6. Métricas somadas e categoria recalculada
+		metricsRepo := uc.metrics.WithTx(tx)
+		if snap.TargetMetrics, err = metricsRepo.Find(ctx, barbershopID, targetID); err != nil {
+			return err
+		}
+		if snap.SourceMetrics, err = metricsRepo.Find(ctx, barbershopID, sourceID); err != nil {
+			return err
+		}
+		if snap.SourceMetrics != nil {
+			base := snap.TargetMetrics
+			if base == nil {
+				base = &domainMetrics.ClientMetrics{ClientID: targetID, BarbershopID: barbershopID}
+			}
+			merged := domainMetrics.MergeClientMetrics(base, snap.SourceMetrics)
+			merged.RecalculateCategory(rules, now)
+			if err := metricsRepo.Save(ctx, merged); err != nil {
+				return err
+			}
+			if err := metricsRepo.Delete(ctx, barbershopID, sourceID); err != nil {
+				return err
+			}
+		}
+
+		// 7. Registro reversível
+		raw, err := json.Marshal(snap)
+		if err != nil {
+			return err
+		}
+		uid := userID
+		merge = models.ClientMerge{
+			BarbershopID:   barbershopID,
+			TargetClientID: targetID,
+			SourceClientID: sourceID,
+			MergedByUserID: &uid,
+			Snapshot:       string(raw),
+			CreatedAt:      now,
+		}
+		if err := tx.Create(&merge).Error; err != nil {
+			return err
+		}
+
+		if uc.audit != nil {
+			tid := targetID
+			if err := uc.audit.DispatchContext(ctx, audit.Event{
+				BarbershopID: barbershopID,
+				UserID:       &uid,
+				Action:       "client_merged",
+				Entity:       "client",
+				EntityID:     &tid,
+				// Só ids e contagens (LGPD); o estado anterior fica no
+				// snapshot de client_merges.
+				Metadata: map[string]any{
+					"merge_id":         merge.ID,
+					"source_client_id": sourceID,
+					"moved":            movedCounts(snap.Moved),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &merge, nil
+}
+
+// Undo desfaz uma junção: os registros movidos voltam ao source, as
+// métricas e o contato dos dois voltam ao que eram. O que foi criado
+// depois da junção fica com o cadastro mantido, e o contato do target só
+// é restaurado se não mudou desde então.
+func (uc *MergeClients) Undo(
+	ctx context.Context,
+	barbershopID uint,
+	mergeID uint,
+	userID uint,
+) (*models.ClientMerge, error) {
+	var merge models.ClientMerge
+
+	err := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := outbox.ContextWithTx(ctx, tx)
+		now := uc.now()
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND barbershop_id = ?", mergeID, barbershopID).
+			First(&merge).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperr.ErrBusiness("merge_not_found")
+			}
+			return err
+		}
+		if merge.UndoneAt != nil {
+			return apperr.ErrBusiness("merge_already_undone")
+		}
+
+		var snap mergeSnapshot
+		if err := json.Unmarshal([]byte(merge.Snapshot), &snap); err != nil {
+			return err
+		}
+
+		clients, err := lockClients(tx, barbershopID, merge.TargetClientID, merge.SourceClientID)
+		if err != nil {
+			return err
+		}
+		target, source := clients[merge.TargetClientID], clients[merge.SourceClientID]
+		if target.AnonymizedAt != nil || source.AnonymizedAt != nil {
+			return apperr.ErrBusiness("client_anonymized")
+		}
+		if source.MergedIntoID == nil || *source.MergedIntoID != target.ID {
+			return apperr.ErrBusiness("merge_not_reversible")
+		}
+
+		// 1. Registros de volta ao source (só os que ainda estão no target)
+		for table, ids := range snap.Moved {
+			if err := tx.Table(table).
+				Where("id IN ? AND client_id = ?", ids, target.ID).
+				Update("client_id", source.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// 2. Contato: primeiro o target solta o que herdou, depois o source
+		// recebe o seu de volta (telefone é único por barbearia)
+		if sameContact(contactOf(target), snap.TargetAfter) {
+			if err := tx.Model(target).Updates(map[string]any{
+				"phone":                nullIfEmpty(snap.TargetBefore.Phone),
+				"email":                nullIfEmpty(snap.TargetBefore.Email),
+				"marketing_opt_out_at": snap.TargetBefore.MarketingOptOutAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		phone := snap.Source.Phone
+		if phone != "" {
+			var taken int64
+			if err := tx.Model(&models.Client{}).
+				Where("barbershop_id = ? AND phone = ? AND id <> ?", barbershopID, phone, source.ID).
+				Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				phone = ""
+			}
+		}
+		if err := tx.Model(source).Updates(map[string]any{
+			"phone":                nullIfEmpty(phone),
+			"email":                nullIfEmpty(snap.Source.Email),
+			"marketing_opt_out_at": snap.Source.MarketingOptOutAt,
+			"merged_into_id":       nil,
+			"merged_at":            nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 3. Métricas: tira do target o que veio do source e devolve ao
+		// source as que ele tinha
+		if snap.SourceMetrics != nil {
+			rules, err := ucMetrics.RulesFor(ctx, uc.rules, barbershopID)
+			if err != nil {
+				return err
+			}
+			metricsRepo := uc.metrics.WithTx(tx)
+
+			current, err := metricsRepo.Find(ctx, barbershopID, target.ID)
+			if err != nil {
+				return err
+			}
+			if current != nil {
+				before := snap.TargetMetrics
+				if before == nil {
+					before = &domainMetrics.ClientMetrics{ClientID: target.ID, BarbershopID: barbershopID}
+				}
+				back := domainMetrics.UnmergeClientMetrics(current, before, snap.SourceMetrics)
+				back.RecalculateCategory(rules, now)
+				if err := metricsRepo.Save(ctx, back); err != nil {
+					return err
+				}
+			}
+
+			restored := *snap.SourceMetrics
+			restored.RecalculateCategory(rules, now)
+			if err := metricsRepo.Save(ctx, &restored); err != nil {
+				return err
+			}
+		}
+
+		// 4. Fechar o registro
+		uid := userID
+		merge.UndoneAt = &now
+		merge.UndoneByUserID = &uid
+		if err := tx.Model(&merge).Updates(map[string]any{
+			"undone_at":         now,
+			"undone_by_user_id": uid,
+		}).Error; err != nil {
+			return err
+		}
+
+		if uc.audit != nil {
+			tid := target.ID
+			if err := uc.audit.DispatchContext(ctx, audit.Event{
+				BarbershopID: barbershopID,
+				UserID:       &uid,
+				Action:       "client_merge_undone",
+				Entity:       "client",
+				EntityID:     &tid,
+				Metadata: map[string]any{
+					"merge_id":         merge.ID,
+					"source_client_id": source.ID,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &merge, nil
+}
+
+// List devolve as junções da barbearia, da mais recente para a mais antiga.
+func (uc *MergeClients) List(
+	ctx context.Context,
+	barbershopID uint,
+	limit int,
+) ([]models.ClientMerge, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var list []models.ClientMerge
+	if err := uc.db.WithContext(ctx).
+		Where("barbershop_id = ?", barbershopID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func lockClients(tx *gorm.DB, barbershopID uint, ids ...uint) (map[uint]*models.Client, error) {
+	var list []models.Client
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("barbershop_id = ? AND id IN ?", barbershopID, ids).
+		Order("id ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	if len(list) != len(ids) {
+		return nil, apperr.ErrBusiness("client_not_found")
+	}
+
+	byID := make(map[uint]*models.Client, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	return byID, nil
+}
+
+func contactOf(c *models.Client) clientContact {
+	cc := clientContact{Phone: c.Phone, Email: c.Email}
+	if c.MarketingOptOutAt != nil {
+		at := c.MarketingOptOutAt.UTC().Truncate(time.Microsecond)
+		cc.MarketingOptOutAt = &at
+	}
+	return cc
+}
+
+func sameContact(a, b clientContact) bool {
+	if a.Phone != b.Phone || a.Email != b.Email {
+		return false
+	}
+	if a.MarketingOptOutAt == nil || b.MarketingOptOutAt == nil {
+		return a.MarketingOptOutAt == b.MarketingOptOutAt
+	}
+	return a.MarketingOptOutAt.Equal(*b.MarketingOptOutAt)
+}
+
+func openSubscriptions(tx *gorm.DB, barbershopID, clientID uint) (int64, error) {
+	var n int64
+	err := tx.Model(&models.Subscription{}).
+		Where("barbershop_id = ? AND client_id = ? AND status IN ('active','pending_payment','past_due')",
+			barbershopID, clientID).
+		Count(&n).Error
+	return n, err
+}
+
+func movedCounts(moved map[string][]uint) map[string]int {
+	counts := make(map[string]int, len(moved))
+	for table, ids := range moved {
+		counts[table] = len(ids)
+	}
+	return counts
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,353 @@
+package clientportal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeRepo conhece um único cliente (id 7, telefone 11999990000, email
+// ana@example.com) e guarda códigos e sessões em memória.
+type fakeRepo struct {
+	domain.Repository
+	client   *models.Client
+	codes    []*models.ClientLoginCode
+	sessions []*models.ClientSession
+	touched  int
+}
+
+func newFakeRepo() *fakeRepo {
+	bid := uint(1)
+	return &fakeRepo{client: &models.Client{
+		ID: 7, BarbershopID: &bid, Name: "Ana", Phone: "11999990000", Email: "ana@example.com",
+	}}
+}
+
+func (r *fakeRepo) FindClientByPhone(_ context.Context, _ uint, phone string) (*models.Client, error) {
+	if phone != r.client.Phone {
+		return nil, nil
+	}
+	return r.client, nil
+}
+
+func (r *fakeRepo) FindClientByEmail(_ context.Context, _ uint, email string) (*models.Client, error) {
+	if email != r.client.Email {
+		return nil, nil
+	}
+	return r.client, nil
+}
+
+func (r *fakeRepo) GetClient(_ context.Context, _ uint, id uint) (*models.Client, error) {
+	if id != r.client.ID {
+		return nil, nil
+	}
+	c := *r.client
+	return &c, nil
+}
+
+func (r *fakeRepo) UpdateClientContact(_ context.Context, c *models.Client) error {
+	if c.Phone == "11888880000" {
+		return domain.ErrPhoneTaken
+	}
+	r.client = c
+	return nil
+}
+
+func (r *fakeRepo) CreateCode(_ context.Context, c *models.ClientLoginCode) error {
+	for _, old := range r.codes {
+		if old.UsedAt == nil {
+			old.ExpiresAt = c.CreatedAt
+		}
+	}
+	c.ID = uint(len(r.codes) + 1)
+	r.codes = append(r.codes, c)
+	return nil
+}
+
+func (r *fakeRepo) LatestCode(context.Context, uint, uint) (*models.ClientLoginCode, error) {
+	for i := len(r.codes) - 1; i >= 0; i-- {
+		if r.codes[i].UsedAt == nil {
+			return r.codes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepo) IncrementAttempts(_ context.Context, id uint) error {
+	r.codes[id-1].Attempts++
+	return nil
+}
+
+func (r *fakeRepo) UseCode(_ context.Context, id uint, now time.Time) (bool, error) {
+	c := r.codes[id-1]
+	if c.UsedAt != nil {
+		return false, nil
+	}
+	c.UsedAt = &now
+	return true, nil
+}
+
+func (r *fakeRepo) CreateSession(_ context.Context, s *models.ClientSession) error {
+	s.ID = uint(len(r.sessions) + 1)
+	r.sessions = append(r.sessions, s)
+	return nil
+}
+
+func (r *fakeRepo) FindSession(_ context.Context, slug, hash string, now time.Time) (*models.ClientSession, error) {
+	for _, s := range r.sessions {
+		if slug == "barbearia" && s.TokenHash == hash && s.RevokedAt == nil && now.Before(s.ExpiresAt) {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepo) TouchSession(_ context.Context, id uint, now time.Time) error {
+	r.touched++
+	r.sessions[id-1].LastSeenAt = &now
+	return nil
+}
+
+func (r *fakeRepo) RevokeSession(_ context.Context, id uint, now time.Time) error {
+	r.sessions[id-1].RevokedAt = &now
+	return nil
+}
+
+type fakeNotifier struct {
+	sent []domainNotification.LoginCodeInput
+}
+
+func (n *fakeNotifier) NotifyLoginCode(_ context.Context, in domainNotification.LoginCodeInput) error {
+	n.sent = append(n.sent, in)
+	return nil
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func setup() (*fakeRepo, *fakeNotifier, *RequestCode, *VerifyCode, *clock) {
+	repo := newFakeRepo()
+	wa := &fakeNotifier{}
+	clk := &clock{t: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+
+	request := NewRequestCode(repo, nil, wa)
+	request.now = clk.now
+	verify := NewVerifyCode(repo)
+	verify.now = clk.now
+	return repo, wa, request, verify, clk
+}
+
+func TestRequestCodeTargets(t *testing.T) {
+	_, _, request, _, _ := setup()
+	ctx := context.Background()
+
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1}); !errors.Is(err, domain.ErrLoginTargetRequired) {
+		t.Fatalf("sem contato: esperado ErrLoginTargetRequired, obtido %v", err)
+	}
+	both := RequestCodeInput{BarbershopID: 1, Phone: "11999990000", Email: "ana@example.com"}
+	if err := request.Execute(ctx, both); !errors.Is(err, domain.ErrLoginTargetRequired) {
+		t.Fatalf("dois contatos: esperado ErrLoginTargetRequired, obtido %v", err)
+	}
+	// O email está desativado nesta barbearia (notifier nil).
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1, Email: "ana@example.com"}); !errors.Is(err, domain.ErrChannelUnavailable) {
+		t.Fatalf("canal indisponível: esperado ErrChannelUnavailable, obtido %v", err)
+	}
+}
+
+func TestRequestCodeUnknownClientIsSilent(t *testing.T) {
+	repo, wa, request, _, _ := setup()
+
+	err := request.Execute(context.Background(), RequestCodeInput{BarbershopID: 1, Phone: "11000000000"})
+	if err != nil {
+		t.Fatalf("esperado nil, obtido %v", err)
+	}
+	if len(repo.codes) != 0 || len(wa.sent) != 0 {
+		t.Fatalf("não deveria gerar nem enviar código: codes=%d sent=%d", len(repo.codes), len(wa.sent))
+	}
+}
+
+func TestRequestCodeThrottlesResend(t *testing.T) {
+	repo, wa, request, _, clk := setup()
+	ctx := context.Background()
+	in := RequestCodeInput{BarbershopID: 1, BarbershopName: "Barbearia", Phone: "11 99999 0000"}
+
+	if err := request.Execute(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+	clk.t = clk.t.Add(30 * time.Second)
+	if err := request.Execute(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+	if len(wa.sent) != 1 {
+		t.Fatalf("reenvio antes do intervalo: esperado 1 envio, obtido %d", len(wa.sent))
+	}
+
+	clk.t = clk.t.Add(ResendInterval)
+	if err := request.Execute(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+	if len(wa.sent) != 2 || len(repo.codes) != 2 {
+		t.Fatalf("esperado 2 envios e 2 códigos, obtido %d e %d", len(wa.sent), len(repo.codes))
+	}
+	if wa.sent[0].ClientPhone != "11999990000" || len(wa.sent[0].Code) != 6 {
+		t.Fatalf("envio inesperado: %+v", wa.sent[0])
+	}
+	if repo.codes[0].CodeHash == wa.sent[0].Code {
+		t.Fatal("o código não pode ser guardado em claro")
+	}
+}
+
+func TestVerifyCodeOpensSession(t *testing.T) {
+	repo, wa, request, verify, _ := setup()
+	ctx := context.Background()
+
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1, Phone: "11999990000"}); err != nil {
+		t.Fatal(err)
+	}
+	code := wa.sent[0].Code
+
+	out, err := verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: code})
+	if err != nil {
+		t.Fatalf("esperado sucesso, obtido %v", err)
+	}
+	if out.Token == "" || out.Client.ID != 7 {
+		t.Fatalf("saída inesperada: %+v", out)
+	}
+	if len(repo.sessions) != 1 || repo.sessions[0].TokenHash != domain.HashSecret(out.Token) {
+		t.Fatal("a sessão deve guardar o hash do token")
+	}
+
+	// Uso único.
+	_, err = verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: code})
+	if !errors.Is(err, domain.ErrInvalidCode) {
+		t.Fatalf("código reutilizado: esperado ErrInvalidCode, obtido %v", err)
+	}
+}
+
+func TestVerifyCodeExpired(t *testing.T) {
+	_, wa, request, verify, clk := setup()
+	ctx := context.Background()
+
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1, Phone: "11999990000"}); err != nil {
+		t.Fatal(err)
+	}
+	clk.t = clk.t.Add(CodeTTL)
+
+	_, err := verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: wa.sent[0].Code})
+	if !errors.Is(err, domain.ErrInvalidCode) {
+		t.Fatalf("esperado ErrInvalidCode, obtido %v", err)
+	}
+}
+
+func TestVerifyCodeAttemptsLimit(t *testing.T) {
+	_, wa, request, verify, _ := setup()
+	ctx := context.Background()
+
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1, Phone: "11999990000"}); err != nil {
+		t.Fatal(err)
+	}
+	wrong := "000000"
+	if wa.sent[0].Code == wrong {
+		wrong = "111111"
+	}
+
+	for i := 1; i <= MaxCodeAttempts; i++ {
+		_, err := verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: wrong})
+		want := domain.ErrInvalidCode
+		if i == MaxCodeAttempts {
+			want = domain.ErrTooManyAttempts
+		}
+		if !errors.Is(err, want) {
+			t.Fatalf("tentativa %d: esperado %v, obtido %v", i, want, err)
+		}
+	}
+
+	// Nem o código certo vale depois do limite.
+	_, err := verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: wa.sent[0].Code})
+	if !errors.Is(err, domain.ErrTooManyAttempts) {
+		t.Fatalf("esperado ErrTooManyAttempts, obtido %v", err)
+	}
+}
+
+func TestSessionsAuthenticateAndLogout(t *testing.T) {
+	repo, wa, request, verify, clk := setup()
+	ctx := context.Background()
+
+	if err := request.Execute(ctx, RequestCodeInput{BarbershopID: 1, Phone: "11999990000"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := verify.Execute(ctx, VerifyCodeInput{BarbershopID: 1, Phone: "11999990000", Code: wa.sent[0].Code})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := NewSessions(repo)
+	sessions.now = clk.now
+
+	if s, _ := sessions.Authenticate(ctx, "outra", out.Token); s != nil {
+		t.Fatal("token não vale em outra barbearia")
+	}
+	if s, _ := sessions.Authenticate(ctx, "barbearia", out.Token); s == nil || s.ClientID != 7 {
+		t.Fatal("esperado sessão do cliente 7")
+	}
+	if repo.touched != 0 {
+		t.Fatal("last_seen_at recém-gravado não deve ser atualizado")
+	}
+
+	clk.t = clk.t.Add(2 * time.Hour)
+	if s, _ := sessions.Authenticate(ctx, "barbearia", out.Token); s == nil {
+		t.Fatal("esperado sessão ativa")
+	}
+	if repo.touched != 1 {
+		t.Fatalf("esperado 1 atualização de last_seen_at, obtido %d", repo.touched)
+	}
+
+	if err := sessions.Logout(ctx, repo.sessions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := sessions.Authenticate(ctx, "barbearia", out.Token); s != nil {
+		t.Fatal("sessão revogada não deve autenticar")
+	}
+}
+
+func TestUpdateContact(t *testing.T) {
+	repo := newFakeRepo()
+	uc := NewUpdateContact(repo)
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	cases := []struct {
+		name  string
+		input UpdateContactInput
+		want  error
+	}{
+		{"nome vazio", UpdateContactInput{Name: str("  ")}, ErrInvalidName},
+		{"telefone curto", UpdateContactInput{Phone: str("123")}, ErrInvalidPhone},
+		{"email inválido", UpdateContactInput{Email: str("ana@")}, ErrInvalidEmail},
+		{"telefone de outro cliente", UpdateContactInput{Phone: str("11888880000")}, domain.ErrPhoneTaken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.input.BarbershopID, tc.input.ClientID = 1, 7
+			if _, err := uc.Execute(ctx, tc.input); !errors.Is(err, tc.want) {
+				t.Fatalf("esperado %v, obtido %v", tc.want, err)
+			}
+		})
+	}
+
+	c, err := uc.Execute(ctx, UpdateContactInput{
+		BarbershopID: 1, ClientID: 7, Phone: str("11 97777 0000"), Email: str(" Ana.Nova@Example.com "),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Ana" || c.Phone != "11977770000" || c.Email != "ana.nova@example.com" {
+		t.Fatalf("contato inesperado: %+v", c)
+	}
+}
@@ -0,0 +1,9 @@
+package clientportal
+
+import "errors"
+
+var (
+	ErrInvalidName  = errors.New("invalid_name")
+	ErrInvalidPhone = errors.New("invalid_phone")
+	ErrInvalidEmail = errors.New("invalid_email")
+)
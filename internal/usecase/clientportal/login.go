@@ -0,0 +1,272 @@
+package clientportal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	// CodeTTL é a validade do código de acesso.
+	CodeTTL = 10 * time.Minute
+	// ResendInterval é o intervalo mínimo entre dois códigos para o mesmo
+	// cliente.
+	ResendInterval = time.Minute
+	// MaxCodeAttempts é o número de tentativas erradas que invalida o código.
+	MaxCodeAttempts = 5
+	// SessionTTL é a validade da sessão aberta pelo login.
+	SessionTTL = 30 * 24 * time.Hour
+)
+
+// ======================================================
+// REQUEST CODE
+// ======================================================
+
+// RequestCodeInput: informe só um entre Phone (código pelo WhatsApp) e Email
+// (código por email).
+type RequestCodeInput struct {
+	BarbershopID   uint
+	BarbershopName string
+	Phone          string
+	Email          string
+}
+
+// RequestCode gera e envia o código de acesso ao portal. Contato sem
+// cadastro e pedido repetido dentro de ResendInterval retornam nil sem
+// enviar nada: a resposta é a mesma haja ou não cliente.
+type RequestCode struct {
+	repo     domain.Repository
+	email    domainNotification.LoginCodeNotifier
+	whatsapp domainNotification.LoginCodeNotifier
+	now      func() time.Time
+}
+
+func NewRequestCode(
+	repo domain.Repository,
+	email domainNotification.LoginCodeNotifier,
+	whatsapp domainNotification.LoginCodeNotifier,
+) *RequestCode {
+	return &RequestCode{
+		repo:     repo,
+		email:    email,
+		whatsapp: whatsapp,
+		now:      time.Now,
+	}
+}
+
+func (uc *RequestCode) Execute(ctx context.Context, input RequestCodeInput) error {
+	phone := domain.NormalizePhone(input.Phone)
+	email := domain.NormalizeEmail(input.Email)
+	if (phone == "") == (email == "") {
+		return domain.ErrLoginTargetRequired
+	}
+
+	channel := models.ClientLoginChannelWhatsApp
+	notifier := uc.whatsapp
+	if email != "" {
+		channel = models.ClientLoginChannelEmail
+		notifier = uc.email
+	}
+	if notifier == nil {
+		return domain.ErrChannelUnavailable
+	}
+
+	var (
+		client *models.Client
+		err    error
+	)
+	if phone != "" {
+		client, err = uc.repo.FindClientByPhone(ctx, input.BarbershopID, phone)
+	} else {
+		client, err = uc.repo.FindClientByEmail(ctx, input.BarbershopID, email)
+	}
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		return nil
+	}
+
+	now := uc.now().UTC()
+
+	last, err := uc.repo.LatestCode(ctx, input.BarbershopID, client.ID)
+	if err != nil {
+		return err
+	}
+	if last != nil && now.Sub(last.CreatedAt) < ResendInterval {
+		return nil
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.CreateCode(ctx, &models.ClientLoginCode{
+		BarbershopID: input.BarbershopID,
+		ClientID:     client.ID,
+		Channel:      channel,
+		CodeHash:     domain.HashSecret(code),
+		ExpiresAt:    now.Add(CodeTTL),
+		CreatedAt:    now,
+	}); err != nil {
+		return err
+	}
+
+	// O código já está gravado: falha de entrega só é logada, e o cliente
+	// pode pedir outro depois de ResendInterval.
+	err = notifier.NotifyLoginCode(ctx, domainNotification.LoginCodeInput{
+		BarbershopID:     input.BarbershopID,
+		BarbershopName:   input.BarbershopName,
+		ClientName:       client.Name,
+		ClientEmail:      email,
+		ClientPhone:      phone,
+		Code:             code,
+		ExpiresInMinutes: int(CodeTTL / time.Minute),
+	})
+	if err != nil {
+		log.Printf("[CLIENT_PORTAL] failed to send login code client=%d channel=%s: %v", client.ID, channel, err)
+	}
+	return nil
+}
+
+// ======================================================
+// VERIFY CODE
+// ======================================================
+
+type VerifyCodeInput struct {
+	BarbershopID uint
+	Phone        string
+	Email        string
+	Code         string
+}
+
+type SessionOutput struct {
+	Token     string         `json:"token"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	Client    *models.Client `json:"client"`
+}
+
+// VerifyCode troca o código de acesso por uma sessão do portal. O código é
+// de uso único e cai depois de MaxCodeAttempts tentativas erradas.
+type VerifyCode struct {
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewVerifyCode(repo domain.Repository) *VerifyCode {
+	return &VerifyCode{repo: repo, now: time.Now}
+}
+
+func (uc *VerifyCode) Execute(ctx context.Context, input VerifyCodeInput) (*SessionOutput, error) {
+	phone := domain.NormalizePhone(input.Phone)
+	email := domain.NormalizeEmail(input.Email)
+	if (phone == "") == (email == "") {
+		return nil, domain.ErrLoginTargetRequired
+	}
+
+	code := strings.TrimSpace(input.Code)
+	if code == "" {
+		return nil, domain.ErrInvalidCode
+	}
+
+	var (
+		client *models.Client
+		err    error
+	)
+	if phone != "" {
+		client, err = uc.repo.FindClientByPhone(ctx, input.BarbershopID, phone)
+	} else {
+		client, err = uc.repo.FindClientByEmail(ctx, input.BarbershopID, email)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, domain.ErrInvalidCode
+	}
+
+	now := uc.now().UTC()
+
+	last, err := uc.repo.LatestCode(ctx, input.BarbershopID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil || !now.Before(last.ExpiresAt) {
+		return nil, domain.ErrInvalidCode
+	}
+	if last.Attempts >= MaxCodeAttempts {
+		return nil, domain.ErrTooManyAttempts
+	}
+
+	if domain.HashSecret(code) != last.CodeHash {
+		attempts := last.Attempts + 1
+		if err := uc.repo.IncrementAttempts(ctx, last.ID); err != nil {
+			return nil, err
+		}
+		if attempts >= MaxCodeAttempts {
+			return nil, domain.ErrTooManyAttempts
+		}
+		return nil, domain.ErrInvalidCode
+	}
+
+	used, err := uc.repo.UseCode(ctx, last.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, domain.ErrInvalidCode
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.ClientSession{
+		BarbershopID: input.BarbershopID,
+		ClientID:     client.ID,
+		TokenHash:    domain.HashSecret(token),
+		ExpiresAt:    now.Add(SessionTTL),
+		LastSeenAt:   &now,
+		CreatedAt:    now,
+	}
+	if err := uc.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &SessionOutput{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt,
+		Client:    client,
+	}, nil
+}
+
+// ======================================================
+// HELPERS
+// ======================================================
+
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
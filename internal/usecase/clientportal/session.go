@@ -0,0 +1,57 @@
+package clientportal
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// touchInterval limita a atualização de last_seen_at a uma escrita por
+// hora por sessão.
+const touchInterval = time.Hour
+
+// Sessions resolve e encerra as sessões do portal.
+type Sessions struct {
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewSessions(repo domain.Repository) *Sessions {
+	return &Sessions{repo: repo, now: time.Now}
+}
+
+// Authenticate retorna a sessão ativa do token na barbearia do slug, ou nil
+// quando o token não vale (inexistente, vencido, revogado ou de outra
+// barbearia).
+func (uc *Sessions) Authenticate(
+	ctx context.Context,
+	slug string,
+	token string,
+) (*models.ClientSession, error) {
+	if token == "" || slug == "" {
+		return nil, nil
+	}
+
+	now := uc.now().UTC()
+
+	session, err := uc.repo.FindSession(ctx, slug, domain.HashSecret(token), now)
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	if session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) >= touchInterval {
+		if err := uc.repo.TouchSession(ctx, session.ID, now); err != nil {
+			log.Printf("[CLIENT_PORTAL] failed to touch session %d: %v", session.ID, err)
+		}
+	}
+
+	return session, nil
+}
+
+// Logout revoga a sessão; o token deixa de valer na hora.
+func (uc *Sessions) Logout(ctx context.Context, sessionID uint) error {
+	return uc.repo.RevokeSession(ctx, sessionID, uc.now().UTC())
+}
@@ -0,0 +1,74 @@
+package clientportal
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/clientportal"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// UpdateContactInput: campos nil são mantidos. Email vazio remove o email;
+// o telefone não pode ser removido, pois é o contato de login pelo WhatsApp.
+type UpdateContactInput struct {
+	BarbershopID uint
+	ClientID     uint
+	Name         *string
+	Phone        *string
+	Email        *string
+}
+
+// UpdateContact é o cliente atualizando os próprios dados de contato pelo
+// portal.
+type UpdateContact struct {
+	repo domain.Repository
+}
+
+func NewUpdateContact(repo domain.Repository) *UpdateContact {
+	return &UpdateContact{repo: repo}
+}
+
+func (uc *UpdateContact) Execute(ctx context.Context, input UpdateContactInput) (*models.Client, error) {
+	client, err := uc.repo.GetClient(ctx, input.BarbershopID, input.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, domain.ErrSessionInvalid
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" || len(name) > 100 {
+			return nil, ErrInvalidName
+		}
+		client.Name = name
+	}
+
+	if input.Phone != nil {
+		phone := domain.NormalizePhone(*input.Phone)
+		if len(phone) < 8 || len(phone) > 20 {
+			return nil, ErrInvalidPhone
+		}
+		client.Phone = phone
+	}
+
+	if input.Email != nil {
+		email := domain.NormalizeEmail(*input.Email)
+		if email != "" {
+			if len(email) > 100 {
+				return nil, ErrInvalidEmail
+			}
+			if _, err := mail.ParseAddress(email); err != nil {
+				return nil, ErrInvalidEmail
+			}
+		}
+		client.Email = email
+	}
+
+	if err := uc.repo.UpdateClientContact(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
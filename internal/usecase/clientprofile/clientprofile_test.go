@@ -0,0 +1,210 @@
+package clientprofile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/storage"
+)
+
+// fakeRepo guarda o perfil em memória para o cliente 1, com o agendamento
+// 10 e as etiquetas 1 e 2.
+type fakeRepo struct {
+	domain.ProfileRepository
+	notes      map[uint]*models.ClientNote
+	photos     []*models.ClientPhoto
+	clientTags []uint
+	failPhoto  bool
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{notes: map[uint]*models.ClientNote{}}
+}
+
+func (r *fakeRepo) ClientExists(_ context.Context, _, clientID uint) (bool, error) {
+	return clientID == 1, nil
+}
+
+func (r *fakeRepo) GetNote(_ context.Context, _, clientID, id uint) (*models.ClientNote, error) {
+	n, ok := r.notes[id]
+	if !ok || n.ClientID != clientID {
+		return nil, nil
+	}
+	return n, nil
+}
+
+func (r *fakeRepo) SaveNote(_ context.Context, n *models.ClientNote) error {
+	if n.ID == 0 {
+		n.ID = uint(len(r.notes) + 1)
+	}
+	r.notes[n.ID] = n
+	return nil
+}
+
+func (r *fakeRepo) DeleteNote(_ context.Context, _, id uint) error {
+	delete(r.notes, id)
+	return nil
+}
+
+func (r *fakeRepo) SetClientTags(_ context.Context, _, _ uint, ids []uint) (bool, error) {
+	for _, id := range ids {
+		if id != 1 && id != 2 {
+			return false, nil
+		}
+	}
+	r.clientTags = ids
+	return true, nil
+}
+
+func (r *fakeRepo) ClientTags(context.Context, uint, uint) ([]models.ClientTag, error) {
+	out := []models.ClientTag{}
+	for _, id := range r.clientTags {
+		out = append(out, models.ClientTag{ID: id})
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) AppointmentOfClient(_ context.Context, _, clientID, apptID uint) (bool, error) {
+	return clientID == 1 && apptID == 10, nil
+}
+
+func (r *fakeRepo) CountAppointmentPhotos(context.Context, uint, uint) (int64, error) {
+	return int64(len(r.photos)), nil
+}
+
+func (r *fakeRepo) SavePhoto(_ context.Context, p *models.ClientPhoto) error {
+	if r.failPhoto {
+		return errors.New("db down")
+	}
+	p.ID = uint(len(r.photos) + 1)
+	r.photos = append(r.photos, p)
+	return nil
+}
+
+// fakeStore registra os objetos enviados e apagados.
+type fakeStore struct {
+	objects map[string]bool
+}
+
+func (s *fakeStore) Upload(_ context.Context, _ storage.AssetKind, key string, _ []byte) (string, error) {
+	s.objects[key] = true
+	return "https://cdn.test/" + key, nil
+}
+
+func (s *fakeStore) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *fakeStore) KeyFromURL(url string) string { return url[len("https://cdn.test/"):] }
+
+func TestSaveClientNotePermissions(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	save := NewSaveClientNote(repo)
+
+	n, err := save.Execute(ctx, SaveClientNoteInput{BarbershopID: 1, ClientID: 1, UserID: 7, Body: " prefere máquina 2 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Body != "prefere máquina 2" || n.AuthorUserID == nil || *n.AuthorUserID != 7 {
+		t.Fatalf("anotação inesperada: %+v", n)
+	}
+
+	other := SaveClientNoteInput{BarbershopID: 1, ClientID: 1, ID: n.ID, UserID: 8, Body: "outra"}
+	if _, err := save.Execute(ctx, other); !errors.Is(err, ErrNoteForbidden) {
+		t.Errorf("outro barbeiro não deveria editar, obtido %v", err)
+	}
+	other.IsOwner = true
+	other.Pinned = true
+	if n, err := save.Execute(ctx, other); err != nil || !n.Pinned || *n.AuthorUserID != 7 {
+		t.Errorf("dono deveria editar mantendo o autor: %+v %v", n, err)
+	}
+
+	del := NewDeleteClientNote(repo)
+	if err := del.Execute(ctx, 1, 1, n.ID, 8, false); !errors.Is(err, ErrNoteForbidden) {
+		t.Errorf("outro barbeiro não deveria apagar, obtido %v", err)
+	}
+	if err := del.Execute(ctx, 1, 1, n.ID, 7, false); err != nil {
+		t.Errorf("autor deveria apagar: %v", err)
+	}
+	if err := del.Execute(ctx, 1, 1, n.ID, 7, false); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("anotação apagada deveria sumir, obtido %v", err)
+	}
+
+	if _, err := save.Execute(ctx, SaveClientNoteInput{BarbershopID: 1, ClientID: 2, UserID: 7, Body: "x"}); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("cliente de fora deveria falhar, obtido %v", err)
+	}
+}
+
+func TestSetClientTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewSetClientTags(repo)
+
+	tags, err := uc.Execute(ctx, 1, 1, []uint{2, 1, 2})
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("obtido %+v %v", tags, err)
+	}
+	if _, err := uc.Execute(ctx, 1, 1, []uint{1, 3}); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("etiqueta de fora deveria falhar, obtido %v", err)
+	}
+	if len(repo.clientTags) != 2 {
+		t.Errorf("falha não deveria mudar as etiquetas: %v", repo.clientTags)
+	}
+	if tags, err := uc.Execute(ctx, 1, 1, nil); err != nil || len(tags) != 0 {
+		t.Errorf("lista vazia deveria tirar todas: %+v %v", tags, err)
+	}
+}
+
+func TestAddClientPhoto(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	store := &fakeStore{objects: map[string]bool{}}
+	uc := NewAddClientPhoto(repo, store)
+
+	in := AddClientPhotoInput{BarbershopID: 1, ClientID: 1, AppointmentID: 10, UserID: 7, Kind: models.ClientPhotoAfter}
+	p, err := uc.Execute(ctx, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Kind != models.ClientPhotoAfter || len(store.objects) != 1 {
+		t.Errorf("foto inesperada: %+v %v", p, store.objects)
+	}
+
+	bad := in
+	bad.Kind = "side"
+	if _, err := uc.Execute(ctx, bad); !errors.Is(err, ErrInvalidPhotoKind) {
+		t.Errorf("tipo inválido deveria falhar, obtido %v", err)
+	}
+	bad = in
+	bad.AppointmentID = 11
+	if _, err := uc.Execute(ctx, bad); !errors.Is(err, ErrAppointmentNotFound) {
+		t.Errorf("agendamento de outro cliente deveria falhar, obtido %v", err)
+	}
+
+	repo.failPhoto = true
+	if _, err := uc.Execute(ctx, in); err == nil {
+		t.Fatal("falha no banco deveria voltar")
+	}
+	if len(store.objects) != 1 {
+		t.Errorf("arquivo órfão deveria ser apagado: %v", store.objects)
+	}
+
+	repo.failPhoto = false
+	for len(repo.photos) < domain.MaxPhotosPerVisit {
+		if _, err := uc.Execute(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := uc.Execute(ctx, in); !errors.Is(err, ErrTooManyPhotos) {
+		t.Errorf("limite por atendimento deveria valer, obtido %v", err)
+	}
+
+	if _, err := NewAddClientPhoto(repo, nil).Execute(ctx, in); !errors.Is(err, ErrStorageUnavailable) {
+		t.Errorf("sem storage deveria falhar, obtido %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package clientprofile
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop = errors.New("invalid_barbershop")
+	ErrClientNotFound    = errors.New("client_not_found")
+
+	ErrNoteNotFound  = errors.New("note_not_found")
+	ErrNoteForbidden = errors.New("note_forbidden")
+
+	ErrTagNotFound  = errors.New("tag_not_found")
+	ErrTagNameTaken = errors.New("tag_name_taken")
+	ErrTooManyTags  = errors.New("too_many_tags")
+	ErrInvalidTags  = errors.New("invalid_tags")
+
+	ErrInvalidPreferenceRefs = errors.New("invalid_preference_refs")
+
+	ErrAppointmentNotFound = errors.New("appointment_not_found")
+	ErrPhotoNotFound       = errors.New("photo_not_found")
+	ErrTooManyPhotos       = errors.New("too_many_photos")
+	ErrInvalidPhotoKind    = errors.New("invalid_photo_kind")
+	ErrStorageUnavailable  = errors.New("storage_unavailable")
+)
@@ -0,0 +1,147 @@
+package clientprofile
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// maxNotesListed é quantas anotações a listagem devolve.
+const maxNotesListed = 100
+
+type ListClientNotes struct {
+	repo domain.ProfileRepository
+}
+
+func NewListClientNotes(repo domain.ProfileRepository) *ListClientNotes {
+	return &ListClientNotes{repo: repo}
+}
+
+func (uc *ListClientNotes) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) ([]domain.NoteView, error) {
+	if err := clientExists(ctx, uc.repo, barbershopID, clientID); err != nil {
+		return nil, err
+	}
+	list, err := uc.repo.ListNotes(ctx, barbershopID, clientID, maxNotesListed)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []domain.NoteView{}
+	}
+	return list, nil
+}
+
+type SaveClientNoteInput struct {
+	BarbershopID uint
+	ClientID     uint
+	ID           uint // zero cria
+	UserID       uint
+	IsOwner      bool
+	Body         string
+	Pinned       bool
+}
+
+// SaveClientNote cria ou edita uma anotação. Só quem escreveu ou o dono
+// podem editar.
+type SaveClientNote struct {
+	repo domain.ProfileRepository
+}
+
+func NewSaveClientNote(repo domain.ProfileRepository) *SaveClientNote {
+	return &SaveClientNote{repo: repo}
+}
+
+func (uc *SaveClientNote) Execute(
+	ctx context.Context,
+	in SaveClientNoteInput,
+) (*models.ClientNote, error) {
+	body, err := domain.NormalizeNote(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := clientExists(ctx, uc.repo, in.BarbershopID, in.ClientID); err != nil {
+		return nil, err
+	}
+
+	n := &models.ClientNote{
+		BarbershopID: in.BarbershopID,
+		ClientID:     in.ClientID,
+		AuthorUserID: &in.UserID,
+	}
+	if in.ID != 0 {
+		n, err = uc.repo.GetNote(ctx, in.BarbershopID, in.ClientID, in.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil {
+			return nil, ErrNoteNotFound
+		}
+		if !canEditNote(n, in.UserID, in.IsOwner) {
+			return nil, ErrNoteForbidden
+		}
+	}
+
+	n.Body = body
+	n.Pinned = in.Pinned
+	if err := uc.repo.SaveNote(ctx, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+type DeleteClientNote struct {
+	repo domain.ProfileRepository
+}
+
+func NewDeleteClientNote(repo domain.ProfileRepository) *DeleteClientNote {
+	return &DeleteClientNote{repo: repo}
+}
+
+func (uc *DeleteClientNote) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	noteID uint,
+	userID uint,
+	isOwner bool,
+) error {
+	n, err := uc.repo.GetNote(ctx, barbershopID, clientID, noteID)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return ErrNoteNotFound
+	}
+	if !canEditNote(n, userID, isOwner) {
+		return ErrNoteForbidden
+	}
+	return uc.repo.DeleteNote(ctx, barbershopID, n.ID)
+}
+
+func canEditNote(n *models.ClientNote, userID uint, isOwner bool) bool {
+	return isOwner || (n.AuthorUserID != nil && *n.AuthorUserID == userID)
+}
+
+func clientExists(
+	ctx context.Context,
+	repo domain.ProfileRepository,
+	barbershopID uint,
+	clientID uint,
+) error {
+	if barbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+	ok, err := repo.ClientExists(ctx, barbershopID, clientID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrClientNotFound
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package clientprofile
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/storage"
+)
+
+// maxPhotosListed é quantas fotos a listagem devolve.
+const maxPhotosListed = 100
+
+type ListClientPhotos struct {
+	repo domain.ProfileRepository
+}
+
+func NewListClientPhotos(repo domain.ProfileRepository) *ListClientPhotos {
+	return &ListClientPhotos{repo: repo}
+}
+
+// Execute lista as fotos do cliente; appointmentID diferente de zero
+// filtra por atendimento.
+func (uc *ListClientPhotos) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	appointmentID uint,
+) ([]models.ClientPhoto, error) {
+	if err := clientExists(ctx, uc.repo, barbershopID, clientID); err != nil {
+		return nil, err
+	}
+	list, err := uc.repo.ListPhotos(ctx, barbershopID, clientID, appointmentID, maxPhotosListed)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []models.ClientPhoto{}
+	}
+	return list, nil
+}
+
+type AddClientPhotoInput struct {
+	BarbershopID  uint
+	ClientID      uint
+	AppointmentID uint
+	UserID        uint
+	Kind          string
+	Raw           []byte
+}
+
+// AddClientPhoto envia a foto de antes/depois para o storage e registra no
+// atendimento. Se o registro falhar, o arquivo enviado é apagado.
+type AddClientPhoto struct {
+	repo  domain.ProfileRepository
+	store storage.Storage
+}
+
+func NewAddClientPhoto(repo domain.ProfileRepository, store storage.Storage) *AddClientPhoto {
+	return &AddClientPhoto{repo: repo, store: store}
+}
+
+func (uc *AddClientPhoto) Execute(
+	ctx context.Context,
+	in AddClientPhotoInput,
+) (*models.ClientPhoto, error) {
+	if uc.store == nil {
+		return nil, ErrStorageUnavailable
+	}
+	if in.Kind != models.ClientPhotoBefore && in.Kind != models.ClientPhotoAfter {
+		return nil, ErrInvalidPhotoKind
+	}
+	if err := clientExists(ctx, uc.repo, in.BarbershopID, in.ClientID); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.repo.AppointmentOfClient(ctx, in.BarbershopID, in.ClientID, in.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAppointmentNotFound
+	}
+
+	n, err := uc.repo.CountAppointmentPhotos(ctx, in.BarbershopID, in.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= domain.MaxPhotosPerVisit {
+		return nil, ErrTooManyPhotos
+	}
+
+	key := fmt.Sprintf("%d/clients/%d/%s.jpg", in.BarbershopID, in.ClientID, uuid.NewString())
+	url, err := uc.store.Upload(ctx, storage.KindClientPhoto, key, in.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &models.ClientPhoto{
+		BarbershopID:     in.BarbershopID,
+		ClientID:         in.ClientID,
+		AppointmentID:    in.AppointmentID,
+		Kind:             in.Kind,
+		URL:              url,
+		UploadedByUserID: &in.UserID,
+	}
+	if err := uc.repo.SavePhoto(ctx, p); err != nil {
+		if derr := uc.store.Delete(ctx, key); derr != nil {
+			log.Printf("[ClientPhoto] failed to delete orphan %s: %v", key, derr)
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// DeleteClientPhoto apaga o registro e depois o arquivo. Falha ao apagar o
+// arquivo só vai para o log: a foto já some do perfil.
+type DeleteClientPhoto struct {
+	repo  domain.ProfileRepository
+	store storage.Storage
+}
+
+func NewDeleteClientPhoto(repo domain.ProfileRepository, store storage.Storage) *DeleteClientPhoto {
+	return &DeleteClientPhoto{repo: repo, store: store}
+}
+
+func (uc *DeleteClientPhoto) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	photoID uint,
+) error {
+	p, err := uc.repo.GetPhoto(ctx, barbershopID, clientID, photoID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrPhotoNotFound
+	}
+	if err := uc.repo.DeletePhoto(ctx, barbershopID, p.ID); err != nil {
+		return err
+	}
+
+	if uc.store != nil {
+		if key := uc.store.KeyFromURL(p.URL); key != "" {
+			if err := uc.store.Delete(ctx, key); err != nil {
+				log.Printf("[ClientPhoto] failed to delete %s: %v", key, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package clientprofile
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+)
+
+type GetClientPreferences struct {
+	repo domain.ProfileRepository
+}
+
+func NewGetClientPreferences(repo domain.ProfileRepository) *GetClientPreferences {
+	return &GetClientPreferences{repo: repo}
+}
+
+// Execute devolve preferências vazias quando o cliente ainda não tem.
+func (uc *GetClientPreferences) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+) (*domain.Preferences, error) {
+	if err := clientExists(ctx, uc.repo, barbershopID, clientID); err != nil {
+		return nil, err
+	}
+	p, err := uc.repo.GetPreferences(ctx, barbershopID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &domain.Preferences{
+			FavoriteServiceIDs: []uint{},
+			FavoriteProductIDs: []uint{},
+		}
+	}
+	return p, nil
+}
+
+// SaveClientPreferences substitui as preferências do cliente. O barbeiro
+// precisa estar ativo e os serviços e produtos precisam ser da barbearia.
+type SaveClientPreferences struct {
+	repo domain.ProfileRepository
+}
+
+func NewSaveClientPreferences(repo domain.ProfileRepository) *SaveClientPreferences {
+	return &SaveClientPreferences{repo: repo}
+}
+
+func (uc *SaveClientPreferences) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	userID uint,
+	p domain.Preferences,
+) (*domain.Preferences, error) {
+	if err := p.Normalize(); err != nil {
+		return nil, err
+	}
+	if err := clientExists(ctx, uc.repo, barbershopID, clientID); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.repo.PreferenceRefsValid(ctx, barbershopID, p)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidPreferenceRefs
+	}
+
+	if err := uc.repo.SavePreferences(ctx, barbershopID, clientID, userID, p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,155 @@
+package clientprofile
+
+import (
+	"context"
+	"slices"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/client"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ListTags struct {
+	repo domain.ProfileRepository
+}
+
+func NewListTags(repo domain.ProfileRepository) *ListTags {
+	return &ListTags{repo: repo}
+}
+
+func (uc *ListTags) Execute(ctx context.Context, barbershopID uint) ([]domain.TagView, error) {
+	list, err := uc.repo.ListTags(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []domain.TagView{}
+	}
+	return list, nil
+}
+
+type SaveTagInput struct {
+	BarbershopID uint
+	ID           uint // zero cria
+	Name         string
+	Color        string
+}
+
+// SaveTag cria ou renomeia uma etiqueta. O nome é único na barbearia sem
+// diferenciar maiúsculas.
+type SaveTag struct {
+	repo domain.ProfileRepository
+}
+
+func NewSaveTag(repo domain.ProfileRepository) *SaveTag {
+	return &SaveTag{repo: repo}
+}
+
+func (uc *SaveTag) Execute(ctx context.Context, in SaveTagInput) (*models.ClientTag, error) {
+	if in.BarbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	name, color, err := domain.NormalizeTag(in.Name, in.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.ClientTag{BarbershopID: in.BarbershopID}
+	if in.ID != 0 {
+		t, err = uc.repo.GetTag(ctx, in.BarbershopID, in.ID)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, ErrTagNotFound
+		}
+	} else {
+		n, err := uc.repo.CountTags(ctx, in.BarbershopID)
+		if err != nil {
+			return nil, err
+		}
+		if n >= domain.MaxTagsPerBarbershop {
+			return nil, ErrTooManyTags
+		}
+	}
+
+	taken, err := uc.repo.TagNameTaken(ctx, in.BarbershopID, name, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrTagNameTaken
+	}
+
+	t.Name = name
+	t.Color = color
+	if err := uc.repo.SaveTag(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+type DeleteTag struct {
+	repo domain.ProfileRepository
+}
+
+func NewDeleteTag(repo domain.ProfileRepository) *DeleteTag {
+	return &DeleteTag{repo: repo}
+}
+
+func (uc *DeleteTag) Execute(ctx context.Context, barbershopID, tagID uint) error {
+	t, err := uc.repo.GetTag(ctx, barbershopID, tagID)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return ErrTagNotFound
+	}
+	return uc.repo.DeleteTag(ctx, barbershopID, t.ID)
+}
+
+// SetClientTags troca todas as etiquetas do cliente. Lista vazia tira
+// todas.
+type SetClientTags struct {
+	repo domain.ProfileRepository
+}
+
+func NewSetClientTags(repo domain.ProfileRepository) *SetClientTags {
+	return &SetClientTags{repo: repo}
+}
+
+func (uc *SetClientTags) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	tagIDs []uint,
+) ([]models.ClientTag, error) {
+	ids := make([]uint, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if id == 0 {
+			return nil, ErrInvalidTags
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if err := clientExists(ctx, uc.repo, barbershopID, clientID); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.repo.SetClientTags(ctx, barbershopID, clientID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTags
+	}
+
+	list, err := uc.repo.ClientTags(ctx, barbershopID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []models.ClientTag{}
+	}
+	return list, nil
+}
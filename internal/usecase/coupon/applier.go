@@ -0,0 +1,115 @@
+package coupon
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Applier aplica cupons nos fluxos de compra (checkout, carrinho, booking e
+// planos): Prepare/Quote validam e calculam o desconto; Redeem consome o uso.
+type Applier struct {
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewApplier(repo domain.Repository) *Applier {
+	return &Applier{repo: repo, now: time.Now}
+}
+
+// Prepare busca o cupom pelo código e valida o que não depende dos itens:
+// status, janela, usos restantes e as regras do cliente.
+func (a *Applier) Prepare(
+	ctx context.Context,
+	barbershopID uint,
+	code string,
+	clientID *uint,
+) (*models.Coupon, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	c, err := a.repo.GetByCode(ctx, barbershopID, code)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := domain.CheckAvailable(c, a.now()); err != nil {
+		return nil, err
+	}
+
+	if domain.NeedsClient(c) && clientID == nil {
+		return nil, domain.ErrRequiresClient
+	}
+	if c.FirstVisitOnly {
+		visited, err := a.repo.HasCompletedVisit(ctx, barbershopID, *clientID)
+		if err != nil {
+			return nil, err
+		}
+		if visited {
+			return nil, domain.ErrFirstVisitOnly
+		}
+	}
+
+	return c, nil
+}
+
+// Quote é o cupom validado com o desconto que ele dá na compra.
+type Quote struct {
+	Coupon        *models.Coupon
+	DiscountCents int64
+}
+
+func (a *Applier) Quote(
+	ctx context.Context,
+	barbershopID uint,
+	code string,
+	clientID *uint,
+	purchase domain.Purchase,
+) (*Quote, error) {
+	c, err := a.Prepare(ctx, barbershopID, code, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := domain.Discount(c, purchase)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{Coupon: c, DiscountCents: discount}, nil
+}
+
+// Redeem consome um uso do cupom. r traz o cliente, o vínculo (agendamento,
+// pedido ou assinatura) e o desconto; limite total e por cliente são
+// conferidos atomicamente pelo repositório.
+func (a *Applier) Redeem(
+	ctx context.Context,
+	c *models.Coupon,
+	r *models.CouponRedemption,
+) error {
+	r.BarbershopID = c.BarbershopID
+	r.CouponID = c.ID
+	return a.repo.Redeem(ctx, r, domain.PerClientLimit(c))
+}
+
+// AttachAppointment vincula o uso reservado antes do booking ao agendamento.
+func (a *Applier) AttachAppointment(
+	ctx context.Context,
+	redemptionID uint,
+	appointmentID uint,
+	discountCents int64,
+) error {
+	return a.repo.AttachAppointment(ctx, redemptionID, appointmentID, discountCents)
+}
+
+// Release devolve um uso reservado quando a compra não se concretiza.
+func (a *Applier) Release(ctx context.Context, redemptionID uint) error {
+	return a.repo.Release(ctx, redemptionID)
+}
@@ -0,0 +1,188 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeRepo guarda um cupom só; existem os alvos 1 a 5 e o cliente 7 já foi
+// atendido.
+type fakeRepo struct {
+	domain.Repository
+	coupon *models.Coupon
+}
+
+func (fakeRepo) CountTargets(_ context.Context, _ uint, _ string, ids []uint) (int64, error) {
+	var n int64
+	for _, id := range ids {
+		if id >= 1 && id <= 5 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r fakeRepo) GetByCode(_ context.Context, _ uint, code string) (*models.Coupon, error) {
+	if r.coupon == nil || r.coupon.Code != code {
+		return nil, nil
+	}
+	return r.coupon, nil
+}
+
+func (fakeRepo) HasCompletedVisit(_ context.Context, _ uint, clientID uint) (bool, error) {
+	return clientID == 7, nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestValidateCoupon(t *testing.T) {
+	ctx := context.Background()
+	valid := CouponInput{
+		BarbershopID:  1,
+		Code:          " bemvindo10 ",
+		DiscountType:  models.CouponDiscountPercent,
+		DiscountValue: 10,
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	cases := []struct {
+		name    string
+		mutate  func(in *CouponInput)
+		wantErr error
+	}{
+		{"válido", func(in *CouponInput) {}, nil},
+		{"código curto", func(in *CouponInput) { in.Code = "ab" }, ErrInvalidCode},
+		{"código com espaço", func(in *CouponInput) { in.Code = "BEM VINDO" }, ErrInvalidCode},
+		{"tipo inválido", func(in *CouponInput) { in.DiscountType = "free" }, ErrInvalidDiscountType},
+		{"percentual acima de 100", func(in *CouponInput) { in.DiscountValue = 101 }, ErrInvalidDiscountValue},
+		{"fixo zero", func(in *CouponInput) { in.DiscountType = models.CouponDiscountFixed; in.DiscountValue = 0 }, ErrInvalidDiscountValue},
+		{"janela invertida", func(in *CouponInput) { in.StartsAt = &end; in.EndsAt = &start }, ErrInvalidWindow},
+		{"limite zero", func(in *CouponInput) { in.MaxUsesPerClient = intPtr(0) }, ErrInvalidMaxUses},
+		{"mínimo negativo", func(in *CouponInput) { in.MinOrderCents = -1 }, ErrInvalidMinOrder},
+		{"escopo inválido", func(in *CouponInput) { in.AppliesTo = "gift_cards" }, ErrInvalidAppliesTo},
+		{"alvos sem escopo", func(in *CouponInput) { in.TargetIDs = []uint{1} }, ErrInvalidTargetIDs},
+		{"alvo repetido", func(in *CouponInput) {
+			in.AppliesTo = models.CouponAppliesToServices
+			in.TargetIDs = []uint{1, 1}
+		}, ErrInvalidTargetIDs},
+		{"alvo de outra barbearia", func(in *CouponInput) {
+			in.AppliesTo = models.CouponAppliesToProducts
+			in.TargetIDs = []uint{1, 99}
+		}, ErrInvalidTargetIDs},
+		{"alvos do escopo", func(in *CouponInput) {
+			in.AppliesTo = models.CouponAppliesToPlans
+			in.TargetIDs = []uint{2, 3}
+		}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := valid
+			tc.mutate(&in)
+
+			err := validateCoupon(ctx, fakeRepo{}, &in)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("esperado %v, obtido %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	in := valid
+	if err := validateCoupon(ctx, fakeRepo{}, &in); err != nil || in.Code != "BEMVINDO10" || in.AppliesTo != models.CouponAppliesToAll {
+		t.Errorf("normalização inesperada: %+v, %v", in, err)
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	lines := []domain.Line{{ItemID: 1, AmountCents: 5000}, {ItemID: 2, AmountCents: 3000}}
+	services := domain.Purchase{Kind: models.CouponAppliesToServices, Lines: lines}
+
+	cases := []struct {
+		name    string
+		coupon  models.Coupon
+		want    int64
+		wantErr error
+	}{
+		{"percentual sobre tudo", models.Coupon{DiscountType: models.CouponDiscountPercent, DiscountValue: 10, AppliesTo: models.CouponAppliesToAll}, 800, nil},
+		{"fixo limitado ao elegível", models.Coupon{DiscountType: models.CouponDiscountFixed, DiscountValue: 9000, AppliesTo: models.CouponAppliesToAll}, 8000, nil},
+		{"só o serviço alvo", models.Coupon{DiscountType: models.CouponDiscountPercent, DiscountValue: 50, AppliesTo: models.CouponAppliesToServices, TargetIDs: []uint{2}}, 1500, nil},
+		{"alvo fora da compra", models.Coupon{DiscountType: models.CouponDiscountPercent, DiscountValue: 50, AppliesTo: models.CouponAppliesToServices, TargetIDs: []uint{9}}, 0, domain.ErrNotApplicable},
+		{"escopo de produtos", models.Coupon{DiscountType: models.CouponDiscountPercent, DiscountValue: 10, AppliesTo: models.CouponAppliesToProducts}, 0, domain.ErrNotApplicable},
+		{"mínimo sobre o total", models.Coupon{DiscountType: models.CouponDiscountFixed, DiscountValue: 1000, AppliesTo: models.CouponAppliesToServices, TargetIDs: []uint{2}, MinOrderCents: 8000}, 1000, nil},
+		{"abaixo do mínimo", models.Coupon{DiscountType: models.CouponDiscountFixed, DiscountValue: 1000, AppliesTo: models.CouponAppliesToAll, MinOrderCents: 8001}, 0, domain.ErrMinOrder},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := domain.Discount(&tc.coupon, services)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("esperado erro %v, obtido %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("esperado %d, obtido %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplierPrepare(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+	newClient, oldClient := uint(8), uint(7)
+
+	cases := []struct {
+		name     string
+		mutate   func(c *models.Coupon)
+		clientID *uint
+		wantErr  error
+	}{
+		{"válido", func(c *models.Coupon) {}, nil, nil},
+		{"inativo", func(c *models.Coupon) { c.Active = false }, nil, domain.ErrInactive},
+		{"ainda não começou", func(c *models.Coupon) { c.StartsAt = &after }, nil, domain.ErrNotStarted},
+		{"expirado", func(c *models.Coupon) { c.EndsAt = &before }, nil, domain.ErrExpired},
+		{"esgotado", func(c *models.Coupon) { c.MaxUses = intPtr(3); c.UsesCount = 3 }, nil, domain.ErrExhausted},
+		{"limite por cliente sem cliente", func(c *models.Coupon) { c.MaxUsesPerClient = intPtr(1) }, nil, domain.ErrRequiresClient},
+		{"primeira visita de cliente novo", func(c *models.Coupon) { c.FirstVisitOnly = true }, &newClient, nil},
+		{"primeira visita de cliente antigo", func(c *models.Coupon) { c.FirstVisitOnly = true }, &oldClient, domain.ErrFirstVisitOnly},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &models.Coupon{ID: 1, BarbershopID: 1, Code: "BEMVINDO", Active: true}
+			tc.mutate(c)
+
+			a := NewApplier(fakeRepo{coupon: c})
+			a.now = func() time.Time { return now }
+
+			_, err := a.Prepare(ctx, 1, " BEMVINDO ", tc.clientID)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("esperado %v, obtido %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	a := NewApplier(fakeRepo{})
+	if _, err := a.Prepare(ctx, 1, "NAOEXISTE", nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("esperado ErrNotFound, obtido %v", err)
+	}
+}
+
+func TestPerClientLimit(t *testing.T) {
+	if got := domain.PerClientLimit(&models.Coupon{}); got != nil {
+		t.Errorf("sem limite: esperado nil, obtido %d", *got)
+	}
+	if got := domain.PerClientLimit(&models.Coupon{FirstVisitOnly: true}); got == nil || *got != 1 {
+		t.Errorf("primeira visita: esperado 1, obtido %v", got)
+	}
+	if got := domain.PerClientLimit(&models.Coupon{FirstVisitOnly: true, MaxUsesPerClient: intPtr(2)}); got == nil || *got != 2 {
+		t.Errorf("limite explícito: esperado 2, obtido %v", got)
+	}
+}
@@ -0,0 +1,233 @@
+package coupon
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// Códigos digitáveis pelo cliente: letras, números, hífen e sublinhado.
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,40}$`)
+
+const maxCouponDescriptionLength = 255
+
+type CouponInput struct {
+	BarbershopID     uint
+	Code             string
+	Description      string
+	DiscountType     string
+	DiscountValue    int64
+	AppliesTo        string // vazio = all
+	TargetIDs        []uint // vazio = todos do escopo
+	StartsAt         *time.Time
+	EndsAt           *time.Time
+	MaxUses          *int
+	MaxUsesPerClient *int
+	MinOrderCents    int64
+	FirstVisitOnly   bool
+}
+
+// validateCoupon normaliza o código (maiúsculas) e o escopo.
+func validateCoupon(ctx context.Context, repo domain.Repository, in *CouponInput) error {
+	if in.BarbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+
+	in.Code = strings.ToUpper(strings.TrimSpace(in.Code))
+	if !couponCodePattern.MatchString(in.Code) {
+		return ErrInvalidCode
+	}
+	in.Description = strings.TrimSpace(in.Description)
+	if len([]rune(in.Description)) > maxCouponDescriptionLength {
+		return ErrInvalidDescription
+	}
+
+	switch in.DiscountType {
+	case models.CouponDiscountPercent:
+		if in.DiscountValue <= 0 || in.DiscountValue > 100 {
+			return ErrInvalidDiscountValue
+		}
+	case models.CouponDiscountFixed:
+		if in.DiscountValue <= 0 {
+			return ErrInvalidDiscountValue
+		}
+	default:
+		return ErrInvalidDiscountType
+	}
+
+	if in.StartsAt != nil && in.EndsAt != nil && !in.EndsAt.After(*in.StartsAt) {
+		return ErrInvalidWindow
+	}
+	if (in.MaxUses != nil && *in.MaxUses <= 0) || (in.MaxUsesPerClient != nil && *in.MaxUsesPerClient <= 0) {
+		return ErrInvalidMaxUses
+	}
+	if in.MinOrderCents < 0 {
+		return ErrInvalidMinOrder
+	}
+
+	if in.AppliesTo == "" {
+		in.AppliesTo = models.CouponAppliesToAll
+	}
+	switch in.AppliesTo {
+	case models.CouponAppliesToAll:
+		if len(in.TargetIDs) > 0 {
+			return ErrInvalidTargetIDs
+		}
+		return nil
+	case models.CouponAppliesToServices, models.CouponAppliesToProducts, models.CouponAppliesToPlans:
+	default:
+		return ErrInvalidAppliesTo
+	}
+
+	return validateTargetIDs(ctx, repo, in.BarbershopID, in.AppliesTo, in.TargetIDs)
+}
+
+// validateTargetIDs exige alvos não repetidos, todos da barbearia.
+func validateTargetIDs(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	appliesTo string,
+	targetIDs []uint,
+) error {
+	if len(targetIDs) == 0 {
+		return nil
+	}
+
+	seen := make(map[uint]bool, len(targetIDs))
+	for _, id := range targetIDs {
+		if id == 0 || seen[id] {
+			return ErrInvalidTargetIDs
+		}
+		seen[id] = true
+	}
+
+	count, err := repo.CountTargets(ctx, barbershopID, appliesTo, targetIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(targetIDs)) {
+		return ErrInvalidTargetIDs
+	}
+	return nil
+}
+
+func applyCouponInput(c *models.Coupon, in CouponInput) {
+	c.Code = in.Code
+	c.Description = in.Description
+	c.DiscountType = in.DiscountType
+	c.DiscountValue = in.DiscountValue
+	c.AppliesTo = in.AppliesTo
+	c.TargetIDs = in.TargetIDs
+	if c.TargetIDs == nil {
+		c.TargetIDs = []uint{}
+	}
+	c.StartsAt = in.StartsAt
+	c.EndsAt = in.EndsAt
+	c.MaxUses = in.MaxUses
+	c.MaxUsesPerClient = in.MaxUsesPerClient
+	c.MinOrderCents = in.MinOrderCents
+	c.FirstVisitOnly = in.FirstVisitOnly
+}
+
+type CreateCoupon struct {
+	repo domain.Repository
+}
+
+func NewCreateCoupon(repo domain.Repository) *CreateCoupon {
+	return &CreateCoupon{repo: repo}
+}
+
+func (uc *CreateCoupon) Execute(ctx context.Context, in CouponInput) (*models.Coupon, error) {
+	if err := validateCoupon(ctx, uc.repo, &in); err != nil {
+		return nil, err
+	}
+
+	c := &models.Coupon{
+		BarbershopID: in.BarbershopID,
+		Active:       true,
+	}
+	applyCouponInput(c, in)
+
+	if err := uc.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateCoupon altera as regras do cupom; os resgates já feitos ficam com o
+// desconto da época. Um max_uses abaixo dos usos já feitos é recusado.
+type UpdateCoupon struct {
+	repo domain.Repository
+}
+
+func NewUpdateCoupon(repo domain.Repository) *UpdateCoupon {
+	return &UpdateCoupon{repo: repo}
+}
+
+func (uc *UpdateCoupon) Execute(
+	ctx context.Context,
+	couponID uint,
+	active *bool, // nil = mantém
+	in CouponInput,
+) (*models.Coupon, error) {
+	c, err := uc.repo.Get(ctx, in.BarbershopID, couponID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCouponNotFound
+	}
+
+	if err := validateCoupon(ctx, uc.repo, &in); err != nil {
+		return nil, err
+	}
+	if in.MaxUses != nil && *in.MaxUses < c.UsesCount {
+		return nil, ErrInvalidMaxUses
+	}
+
+	applyCouponInput(c, in)
+	if active != nil {
+		c.Active = *active
+	}
+
+	if err := uc.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type ListCoupons struct {
+	repo domain.Repository
+}
+
+func NewListCoupons(repo domain.Repository) *ListCoupons {
+	return &ListCoupons{repo: repo}
+}
+
+func (uc *ListCoupons) Execute(ctx context.Context, barbershopID uint) ([]models.Coupon, error) {
+	return uc.repo.List(ctx, barbershopID)
+}
+
+type ListRedemptions struct {
+	repo domain.Repository
+}
+
+func NewListRedemptions(repo domain.Repository) *ListRedemptions {
+	return &ListRedemptions{repo: repo}
+}
+
+func (uc *ListRedemptions) Execute(ctx context.Context, barbershopID uint, couponID uint) ([]models.CouponRedemption, error) {
+	c, err := uc.repo.Get(ctx, barbershopID, couponID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCouponNotFound
+	}
+	return uc.repo.ListRedemptions(ctx, barbershopID, couponID)
+}
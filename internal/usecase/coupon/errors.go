@@ -0,0 +1,17 @@
+package coupon
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop    = errors.New("invalid_barbershop")
+	ErrInvalidCode          = errors.New("invalid_coupon_code")
+	ErrInvalidDescription   = errors.New("invalid_description")
+	ErrInvalidDiscountType  = errors.New("invalid_discount_type")
+	ErrInvalidDiscountValue = errors.New("invalid_discount_value")
+	ErrInvalidAppliesTo     = errors.New("invalid_applies_to")
+	ErrInvalidTargetIDs     = errors.New("invalid_target_ids")
+	ErrInvalidWindow        = errors.New("invalid_coupon_window")
+	ErrInvalidMaxUses       = errors.New("invalid_max_uses")
+	ErrInvalidMinOrder      = errors.New("invalid_min_order")
+	ErrCouponNotFound       = errors.New("coupon_not_found")
+)
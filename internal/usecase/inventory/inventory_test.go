@@ -0,0 +1,333 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainProduct "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// mockInventoryRepo guarda o estoque em memória e aplica cada lote por
+// inteiro ou nada, como a transação do repositório GORM.
+type mockInventoryRepo struct {
+	stock     map[uint]int
+	movements []models.InventoryMovement
+
+	lowStock   []domain.LowStockProduct
+	recipients map[uint][]string
+	notified   []uint
+}
+
+func newMockInventoryRepo(stock map[uint]int) *mockInventoryRepo {
+	return &mockInventoryRepo{stock: stock, recipients: map[uint][]string{}}
+}
+
+func (m *mockInventoryRepo) Record(_ context.Context, movements []*models.InventoryMovement) error {
+	next := make(map[uint]int, len(m.stock))
+	for id, qty := range m.stock {
+		next[id] = qty
+	}
+	for _, mv := range movements {
+		current, ok := next[mv.ProductID]
+		if !ok {
+			return domainProduct.ErrProductNotFound
+		}
+		if current+mv.Quantity < 0 {
+			return domainProduct.ErrInsufficientStock
+		}
+		next[mv.ProductID] = current + mv.Quantity
+		mv.StockAfter = next[mv.ProductID]
+	}
+
+	m.stock = next
+	for _, mv := range movements {
+		mv.ID = uint(len(m.movements) + 1)
+		m.movements = append(m.movements, *mv)
+	}
+	return nil
+}
+
+func (m *mockInventoryRepo) Count(
+	ctx context.Context,
+	barbershopID uint,
+	lines []domain.CountLine,
+	userID *uint,
+	note string,
+) ([]models.InventoryMovement, error) {
+	var movements []*models.InventoryMovement
+	for _, line := range lines {
+		current, ok := m.stock[line.ProductID]
+		if !ok {
+			return nil, domainProduct.ErrProductNotFound
+		}
+		if diff := line.Counted - current; diff != 0 {
+			movements = append(movements, &models.InventoryMovement{
+				BarbershopID: barbershopID,
+				ProductID:    line.ProductID,
+				Type:         domain.MovementAdjustment,
+				Quantity:     diff,
+				UserID:       userID,
+				Note:         note,
+			})
+		}
+	}
+	if err := m.Record(ctx, movements); err != nil {
+		return nil, err
+	}
+
+	out := make([]models.InventoryMovement, 0, len(movements))
+	for _, mv := range movements {
+		out = append(out, *mv)
+	}
+	return out, nil
+}
+
+func (m *mockInventoryRepo) ListByProduct(_ context.Context, _, productID, _ uint, limit int) ([]models.InventoryMovement, error) {
+	var out []models.InventoryMovement
+	for i := len(m.movements) - 1; i >= 0 && len(out) < limit; i-- {
+		if m.movements[i].ProductID == productID {
+			out = append(out, m.movements[i])
+		}
+	}
+	return out, nil
+}
+
+func (m *mockInventoryRepo) ListLowStockPending(_ context.Context, _ int) ([]domain.LowStockProduct, error) {
+	return m.lowStock, nil
+}
+
+func (m *mockInventoryRepo) MarkLowStockNotified(_ context.Context, ids []uint, _ time.Time) error {
+	m.notified = append(m.notified, ids...)
+	return nil
+}
+
+func (m *mockInventoryRepo) GetAlertRecipients(_ context.Context, barbershopID uint) (string, []string, error) {
+	return "Barbearia Teste", m.recipients[barbershopID], nil
+}
+
+// ledgerBalance soma os movimentos do produto: deve bater com o estoque.
+func (m *mockInventoryRepo) ledgerBalance(productID uint) int {
+	total := 0
+	for _, mv := range m.movements {
+		if mv.ProductID == productID {
+			total += mv.Quantity
+		}
+	}
+	return total
+}
+
+func TestRecordStockEntries(t *testing.T) {
+	ctx := context.Background()
+	cost := int64(1250)
+
+	t.Run("compra entra com custo e perda sai do estoque", func(t *testing.T) {
+		repo := newMockInventoryRepo(map[uint]int{1: 0, 2: 0})
+		uc := NewRecordStockEntries(repo)
+
+		_, err := uc.Execute(ctx, RecordStockEntriesInput{
+			BarbershopID: 7,
+			UserID:       3,
+			Type:         domain.MovementPurchase,
+			Note:         "  NF 123  ",
+			Items: []EntryItem{
+				{ProductID: 1, Quantity: 10, UnitCostCents: &cost},
+				{ProductID: 2, Quantity: 4},
+			},
+		})
+		if err != nil {
+			t.Fatalf("compra: inesperado erro: %v", err)
+		}
+
+		movements, err := uc.Execute(ctx, RecordStockEntriesInput{
+			BarbershopID: 7,
+			UserID:       3,
+			Type:         domain.MovementLoss,
+			Items:        []EntryItem{{ProductID: 1, Quantity: 3}},
+		})
+		if err != nil {
+			t.Fatalf("perda: inesperado erro: %v", err)
+		}
+
+		if movements[0].Quantity != -3 || movements[0].StockAfter != 7 {
+			t.Errorf("perda inesperada: quantity=%d stock_after=%d", movements[0].Quantity, movements[0].StockAfter)
+		}
+		if repo.stock[1] != 7 || repo.ledgerBalance(1) != 7 {
+			t.Errorf("estoque=%d razão=%d, esperado 7", repo.stock[1], repo.ledgerBalance(1))
+		}
+		first := repo.movements[0]
+		if first.Note != "NF 123" || first.UnitCostCents == nil || *first.UnitCostCents != cost {
+			t.Errorf("compra gravada sem nota/custo: %+v", first)
+		}
+		if first.UserID == nil || *first.UserID != 3 {
+			t.Errorf("compra sem o usuário que lançou: %+v", first)
+		}
+	})
+
+	t.Run("perda maior que o estoque não grava nada", func(t *testing.T) {
+		repo := newMockInventoryRepo(map[uint]int{1: 5, 2: 1})
+		uc := NewRecordStockEntries(repo)
+
+		_, err := uc.Execute(ctx, RecordStockEntriesInput{
+			BarbershopID: 7,
+			Type:         domain.MovementLoss,
+			Items: []EntryItem{
+				{ProductID: 1, Quantity: 2},
+				{ProductID: 2, Quantity: 3},
+			},
+		})
+		if !errors.Is(err, domainProduct.ErrInsufficientStock) {
+			t.Fatalf("esperado ErrInsufficientStock, obtido: %v", err)
+		}
+		if len(repo.movements) != 0 || repo.stock[1] != 5 {
+			t.Errorf("lote parcial gravado: movimentos=%d estoque=%d", len(repo.movements), repo.stock[1])
+		}
+	})
+
+	t.Run("validações", func(t *testing.T) {
+		negative := int64(-1)
+		cases := []struct {
+			name  string
+			input RecordStockEntriesInput
+			code  string
+		}{
+			{"tipo de sistema", RecordStockEntriesInput{Type: domain.MovementSale, Items: []EntryItem{{ProductID: 1, Quantity: 1}}}, "invalid_movement_type"},
+			{"sem itens", RecordStockEntriesInput{Type: domain.MovementPurchase}, "empty_items"},
+			{"quantidade zero", RecordStockEntriesInput{Type: domain.MovementPurchase, Items: []EntryItem{{ProductID: 1}}}, "invalid_quantity"},
+			{"quantidade negativa", RecordStockEntriesInput{Type: domain.MovementReturn, Items: []EntryItem{{ProductID: 1, Quantity: -2}}}, "invalid_quantity"},
+			{"custo fora da compra", RecordStockEntriesInput{Type: domain.MovementLoss, Items: []EntryItem{{ProductID: 1, Quantity: 1, UnitCostCents: &cost}}}, "invalid_unit_cost"},
+			{"custo negativo", RecordStockEntriesInput{Type: domain.MovementPurchase, Items: []EntryItem{{ProductID: 1, Quantity: 1, UnitCostCents: &negative}}}, "invalid_unit_cost"},
+		}
+
+		for _, tc := range cases {
+			repo := newMockInventoryRepo(map[uint]int{1: 10})
+			_, err := NewRecordStockEntries(repo).Execute(ctx, tc.input)
+			if !apperr.IsBusiness(err, tc.code) {
+				t.Errorf("%s: esperado %s, obtido %v", tc.name, tc.code, err)
+			}
+		}
+	})
+}
+
+func TestRecordStockCount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("só as diferenças viram ajuste", func(t *testing.T) {
+		repo := newMockInventoryRepo(map[uint]int{1: 10, 2: 4, 3: 0})
+		uc := NewRecordStockCount(repo)
+
+		adjustments, err := uc.Execute(ctx, RecordStockCountInput{
+			BarbershopID: 7,
+			UserID:       3,
+			Lines: []domain.CountLine{
+				{ProductID: 1, Counted: 8},
+				{ProductID: 2, Counted: 4},
+				{ProductID: 3, Counted: 2},
+			},
+		})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+
+		if len(adjustments) != 2 {
+			t.Fatalf("esperado 2 ajustes, obtido %d", len(adjustments))
+		}
+		if adjustments[0].Quantity != -2 || adjustments[1].Quantity != 2 {
+			t.Errorf("ajustes inesperados: %+v", adjustments)
+		}
+		if adjustments[0].Note != "Contagem de estoque" {
+			t.Errorf("nota padrão = %q", adjustments[0].Note)
+		}
+		if repo.stock[1] != 8 || repo.stock[3] != 2 {
+			t.Errorf("estoque após contagem: %+v", repo.stock)
+		}
+	})
+
+	t.Run("produto repetido é recusado", func(t *testing.T) {
+		repo := newMockInventoryRepo(map[uint]int{1: 10})
+		_, err := NewRecordStockCount(repo).Execute(ctx, RecordStockCountInput{
+			BarbershopID: 7,
+			Lines: []domain.CountLine{
+				{ProductID: 1, Counted: 8},
+				{ProductID: 1, Counted: 9},
+			},
+		})
+		if !apperr.IsBusiness(err, "duplicate_product") {
+			t.Errorf("esperado duplicate_product, obtido %v", err)
+		}
+	})
+
+	t.Run("contagem negativa é recusada", func(t *testing.T) {
+		repo := newMockInventoryRepo(map[uint]int{1: 10})
+		_, err := NewRecordStockCount(repo).Execute(ctx, RecordStockCountInput{
+			BarbershopID: 7,
+			Lines:        []domain.CountLine{{ProductID: 1, Counted: -1}},
+		})
+		if !apperr.IsBusiness(err, "invalid_quantity") {
+			t.Errorf("esperado invalid_quantity, obtido %v", err)
+		}
+	})
+}
+
+type mockLowStockNotifier struct {
+	sent []domainNotification.LowStockInput
+	fail map[string]bool
+}
+
+func (m *mockLowStockNotifier) NotifyLowStock(_ context.Context, input domainNotification.LowStockInput) error {
+	if m.fail[input.OwnerEmail] {
+		return errors.New("smtp down")
+	}
+	m.sent = append(m.sent, input)
+	return nil
+}
+
+func TestNotifyLowStock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+
+	repo := newMockInventoryRepo(nil)
+	repo.lowStock = []domain.LowStockProduct{
+		{ProductID: 1, BarbershopID: 7, Name: "Pomada", Stock: 2, Threshold: 3},
+		{ProductID: 2, BarbershopID: 7, Name: "Óleo", Stock: 0, Threshold: 1},
+		{ProductID: 3, BarbershopID: 8, Name: "Gel", Stock: 1, Threshold: 5},
+		{ProductID: 4, BarbershopID: 9, Name: "Shampoo", Stock: 0, Threshold: 0},
+	}
+	repo.recipients[7] = []string{"dono@a.com", "socio@a.com"}
+	repo.recipients[8] = []string{"dono@b.com"}
+	// Barbearia 9 sem dono ativo: marca para não reprocessar sempre.
+
+	notifier := &mockLowStockNotifier{fail: map[string]bool{"dono@b.com": true}}
+
+	n, err := NewNotifyLowStock(repo, notifier).Execute(ctx, now)
+	if err != nil {
+		t.Fatalf("inesperado erro: %v", err)
+	}
+
+	// Um e-mail por dono, com todos os produtos da barbearia.
+	if len(notifier.sent) != 2 {
+		t.Fatalf("esperado 2 e-mails, obtido %d", len(notifier.sent))
+	}
+	if len(notifier.sent[0].Products) != 2 || notifier.sent[0].BarbershopName != "Barbearia Teste" {
+		t.Errorf("e-mail inesperado: %+v", notifier.sent[0])
+	}
+
+	// Envio falhou para a barbearia 8: fica pendente para a próxima execução.
+	if n != 3 {
+		t.Errorf("avisados = %d, esperado 3", n)
+	}
+	want := map[uint]bool{1: true, 2: true, 4: true}
+	if len(repo.notified) != len(want) {
+		t.Fatalf("marcados = %v", repo.notified)
+	}
+	for _, id := range repo.notified {
+		if !want[id] {
+			t.Errorf("produto %d marcado indevidamente", id)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package inventory
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	defaultMovementsLimit = 50
+	maxMovementsLimit     = 200
+)
+
+// ListStockMovements lista o histórico de estoque de um produto, do mais
+// recente para o mais antigo, paginado por cursor (before_id).
+type ListStockMovements struct {
+	repo domain.Repository
+}
+
+func NewListStockMovements(repo domain.Repository) *ListStockMovements {
+	return &ListStockMovements{repo: repo}
+}
+
+func (uc *ListStockMovements) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	productID uint,
+	beforeID uint,
+	limit int,
+) ([]models.InventoryMovement, error) {
+	if limit <= 0 {
+		limit = defaultMovementsLimit
+	}
+	if limit > maxMovementsLimit {
+		limit = maxMovementsLimit
+	}
+
+	return uc.repo.ListByProduct(ctx, barbershopID, productID, beforeID, limit)
+}
@@ -0,0 +1,104 @@
+package inventory
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+)
+
+// lowStockBatchSize limita os produtos avisados por execução do job.
+const lowStockBatchSize = 500
+
+// NotifyLowStock envia ao dono um e-mail por barbearia com os produtos que
+// chegaram ao limite de estoque baixo. Cada produto é avisado uma vez; o
+// aviso rearma quando o estoque volta a ficar acima do limite.
+type NotifyLowStock struct {
+	repo   domain.Repository
+	notify domainNotification.LowStockNotifier
+}
+
+func NewNotifyLowStock(
+	repo domain.Repository,
+	notify domainNotification.LowStockNotifier,
+) *NotifyLowStock {
+	return &NotifyLowStock{repo: repo, notify: notify}
+}
+
+// Execute retorna quantos produtos foram avisados.
+func (uc *NotifyLowStock) Execute(ctx context.Context, now time.Time) (int, error) {
+	products, err := uc.repo.ListLowStockPending(ctx, lowStockBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// A lista vem ordenada por barbearia.
+	notified := 0
+	for start := 0; start < len(products); {
+		end := start
+		for end < len(products) && products[end].BarbershopID == products[start].BarbershopID {
+			end++
+		}
+
+		n, err := uc.notifyBarbershop(ctx, products[start:end], now)
+		if err != nil {
+			log.Printf("[NotifyLowStock] barbershop=%d error=%v", products[start].BarbershopID, err)
+		}
+		notified += n
+		start = end
+	}
+
+	return notified, nil
+}
+
+func (uc *NotifyLowStock) notifyBarbershop(
+	ctx context.Context,
+	products []domain.LowStockProduct,
+	now time.Time,
+) (int, error) {
+	barbershopID := products[0].BarbershopID
+
+	shopName, emails, err := uc.repo.GetAlertRecipients(ctx, barbershopID)
+	if err != nil {
+		return 0, err
+	}
+
+	items := make([]domainNotification.LowStockItem, 0, len(products))
+	ids := make([]uint, 0, len(products))
+	for _, p := range products {
+		items = append(items, domainNotification.LowStockItem{
+			Name:      p.Name,
+			Stock:     p.Stock,
+			Threshold: p.Threshold,
+		})
+		ids = append(ids, p.ProductID)
+	}
+
+	sent := false
+	for _, email := range emails {
+		err := uc.notify.NotifyLowStock(ctx, domainNotification.LowStockInput{
+			BarbershopID:   barbershopID,
+			BarbershopName: shopName,
+			OwnerEmail:     email,
+			Products:       items,
+		})
+		if err != nil {
+			log.Printf("[NotifyLowStock] barbershop=%d send error=%v", barbershopID, err)
+			continue
+		}
+		sent = true
+	}
+
+	// Sem nenhum envio bem-sucedido, tenta de novo na próxima execução.
+	// Barbearia sem dono ativo é marcada para não ser reprocessada sempre.
+	if !sent && len(emails) > 0 {
+		return 0, nil
+	}
+
+	if err := uc.repo.MarkLowStockNotified(ctx, ids, now); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,69 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type RecordStockCountInput struct {
+	BarbershopID uint
+	UserID       uint
+	Note         string
+	Lines        []domain.CountLine
+}
+
+// RecordStockCount concilia o estoque com uma contagem física: cada diferença
+// vira um ajuste no razão. Produtos que bateram com o sistema não geram
+// movimento.
+type RecordStockCount struct {
+	repo domain.Repository
+}
+
+func NewRecordStockCount(repo domain.Repository) *RecordStockCount {
+	return &RecordStockCount{repo: repo}
+}
+
+func (uc *RecordStockCount) Execute(
+	ctx context.Context,
+	input RecordStockCountInput,
+) ([]models.InventoryMovement, error) {
+	if err := validateCountLines(input.Lines); err != nil {
+		return nil, err
+	}
+
+	note, err := normalizeNote(input.Note)
+	if err != nil {
+		return nil, err
+	}
+	if note == "" {
+		note = "Contagem de estoque"
+	}
+
+	userID := input.UserID
+	return uc.repo.Count(ctx, input.BarbershopID, input.Lines, &userID, note)
+}
+
+func validateCountLines(lines []domain.CountLine) error {
+	if err := validateItemCount(len(lines)); err != nil {
+		return err
+	}
+
+	seen := make(map[uint]bool, len(lines))
+	for _, line := range lines {
+		if line.ProductID == 0 {
+			return apperr.ErrBusiness("invalid_product_id")
+		}
+		if line.Counted < 0 {
+			return apperr.ErrBusiness("invalid_quantity")
+		}
+		// O mesmo produto duas vezes na contagem é ambíguo.
+		if seen[line.ProductID] {
+			return apperr.ErrBusiness("duplicate_product")
+		}
+		seen[line.ProductID] = true
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// maxItemsPerEntry limita os produtos de um lançamento (nota de compra, contagem).
+const maxItemsPerEntry = 200
+
+// EntryItem é um produto do lançamento. Quantity é sempre positiva; o tipo
+// define se entra ou sai do estoque.
+type EntryItem struct {
+	ProductID     uint
+	Quantity      int
+	UnitCostCents *int64
+}
+
+type RecordStockEntriesInput struct {
+	BarbershopID uint
+	UserID       uint
+	Type         string // purchase | loss | return
+	Note         string
+	Items        []EntryItem
+}
+
+// RecordStockEntries lança uma entrada de compra do fornecedor, uma perda
+// (quebra, vencimento) ou uma devolução manual. Todos os itens são aplicados
+// juntos: se algum falhar (ex.: perda maior que o estoque), nada é gravado.
+type RecordStockEntries struct {
+	repo domain.Repository
+}
+
+func NewRecordStockEntries(repo domain.Repository) *RecordStockEntries {
+	return &RecordStockEntries{repo: repo}
+}
+
+func (uc *RecordStockEntries) Execute(
+	ctx context.Context,
+	input RecordStockEntriesInput,
+) ([]*models.InventoryMovement, error) {
+	movements, err := buildEntryMovements(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Record(ctx, movements); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// buildEntryMovements valida o lançamento e monta os movimentos com a
+// quantidade assinada.
+func buildEntryMovements(input RecordStockEntriesInput) ([]*models.InventoryMovement, error) {
+	sign := 1
+	switch input.Type {
+	case domain.MovementPurchase, domain.MovementReturn:
+	case domain.MovementLoss:
+		sign = -1
+	default:
+		return nil, apperr.ErrBusiness("invalid_movement_type")
+	}
+
+	if err := validateItemCount(len(input.Items)); err != nil {
+		return nil, err
+	}
+
+	note, err := normalizeNote(input.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := input.UserID
+	movements := make([]*models.InventoryMovement, 0, len(input.Items))
+	for _, item := range input.Items {
+		if item.ProductID == 0 {
+			return nil, apperr.ErrBusiness("invalid_product_id")
+		}
+		if item.Quantity <= 0 {
+			return nil, apperr.ErrBusiness("invalid_quantity")
+		}
+		if item.UnitCostCents != nil {
+			// Custo só faz sentido na compra.
+			if input.Type != domain.MovementPurchase || *item.UnitCostCents < 0 {
+				return nil, apperr.ErrBusiness("invalid_unit_cost")
+			}
+		}
+
+		movements = append(movements, &models.InventoryMovement{
+			BarbershopID:  input.BarbershopID,
+			ProductID:     item.ProductID,
+			Type:          input.Type,
+			Quantity:      sign * item.Quantity,
+			UnitCostCents: item.UnitCostCents,
+			UserID:        &userID,
+			Note:          note,
+		})
+	}
+
+	return movements, nil
+}
+
+func validateItemCount(n int) error {
+	if n == 0 {
+		return apperr.ErrBusiness("empty_items")
+	}
+	if n > maxItemsPerEntry {
+		return apperr.ErrBusiness("too_many_items")
+	}
+	return nil
+}
+
+func normalizeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > 255 {
+		return "", apperr.ErrBusiness("note_too_long")
+	}
+	return note, nil
+}
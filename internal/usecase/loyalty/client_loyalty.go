@@ -0,0 +1,56 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	// expiringHorizon: janela do aviso de pontos prestes a vencer.
+	expiringHorizon = 30 * 24 * time.Hour
+
+	clientEntriesLimit = 50
+)
+
+type ClientLoyalty struct {
+	Balance        int                   `json:"balance"`
+	ExpiringPoints int                   `json:"expiring_points"`
+	NextExpiresAt  *time.Time            `json:"next_expires_at,omitempty"`
+	Entries        []models.LoyaltyEntry `json:"entries"`
+}
+
+// GetClientLoyalty devolve o saldo do cliente e as últimas movimentações.
+type GetClientLoyalty struct {
+	repo domain.Repository
+}
+
+func NewGetClientLoyalty(repo domain.Repository) *GetClientLoyalty {
+	return &GetClientLoyalty{repo: repo}
+}
+
+func (uc *GetClientLoyalty) Execute(ctx context.Context, barbershopID, clientID uint) (*ClientLoyalty, error) {
+	now := time.Now().UTC()
+
+	account, err := uc.repo.GetAccount(ctx, barbershopID, clientID, now, now.Add(expiringHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.ListEntries(ctx, barbershopID, clientID, clientEntriesLimit)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.LoyaltyEntry{}
+	}
+
+	return &ClientLoyalty{
+		Balance:        account.Balance,
+		ExpiringPoints: account.ExpiringPoints,
+		NextExpiresAt:  account.NextExpiresAt,
+		Entries:        entries,
+	}, nil
+}
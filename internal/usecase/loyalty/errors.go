@@ -0,0 +1,15 @@
+package loyalty
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop     = errors.New("invalid_barbershop")
+	ErrInvalidPointsPerReal  = errors.New("invalid_points_per_real")
+	ErrInvalidExpiryMonths   = errors.New("invalid_expiry_months")
+	ErrInvalidRewardName     = errors.New("invalid_reward_name")
+	ErrInvalidRewardKind     = errors.New("invalid_reward_kind")
+	ErrInvalidPointsCost     = errors.New("invalid_points_cost")
+	ErrInvalidRewardService  = errors.New("invalid_reward_service")
+	ErrInvalidRewardDiscount = errors.New("invalid_reward_discount")
+	ErrRewardNotFound        = errors.New("loyalty_reward_not_found")
+)
@@ -0,0 +1,181 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// TxRepository vincula o extrato à transação do fluxo que pontua (fechamento,
+// ajuste, devolução), para os pontos serem revertidos junto em caso de falha.
+type TxRepository interface {
+	domain.Repository
+	WithTx(tx *gorm.DB) domain.Repository
+}
+
+// Ledger movimenta os pontos: crédito pelo valor gasto, troca por
+// recompensa e reversão quando o valor cai.
+type Ledger struct {
+	base TxRepository
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewLedger(repo TxRepository) *Ledger {
+	return &Ledger{base: repo, repo: repo, now: time.Now}
+}
+
+// WithTx devolve o Ledger vinculado a uma transação existente.
+func (l *Ledger) WithTx(tx *gorm.DB) *Ledger {
+	return &Ledger{base: l.base, repo: l.base.WithTx(tx), now: l.now}
+}
+
+// EarnInput identifica a origem do crédito: um atendimento ou um pedido.
+type EarnInput struct {
+	BarbershopID  uint
+	ClientID      uint
+	AppointmentID *uint
+	OrderID       *uint
+	AmountCents   int64
+}
+
+// Earn credita os pontos do valor gasto. Sem programa ativo ou com valor
+// abaixo de um real, não faz nada; a mesma origem só pontua uma vez.
+func (l *Ledger) Earn(ctx context.Context, in EarnInput) error {
+	program, err := l.repo.GetProgram(ctx, in.BarbershopID)
+	if err != nil {
+		return err
+	}
+	if program == nil || !program.Active {
+		return nil
+	}
+
+	points := domain.PointsFor(program, in.AmountCents)
+	if points == 0 {
+		return nil
+	}
+
+	now := l.now().UTC()
+	return l.repo.Earn(ctx, &models.LoyaltyEntry{
+		BarbershopID:    in.BarbershopID,
+		ClientID:        in.ClientID,
+		Kind:            models.LoyaltyEntryEarn,
+		Points:          points,
+		RemainingPoints: points,
+		AmountCents:     in.AmountCents,
+		AppointmentID:   in.AppointmentID,
+		OrderID:         in.OrderID,
+		ExpiresAt:       domain.ExpiresAt(program, now),
+		CreatedAt:       now,
+	})
+}
+
+// Reverse estorna os pontos de um atendimento ou pedido cujo valor caiu
+// para newAmountCents (ajuste de fechamento, cancelamento, devolução).
+// Vale mesmo com o programa desligado. Pontos já trocados não são cobrados
+// de volta: a reversão para no saldo do cliente. Retorna os pontos
+// revertidos.
+func (l *Ledger) Reverse(
+	ctx context.Context,
+	appointmentID *uint,
+	orderID *uint,
+	newAmountCents int64,
+) (int, error) {
+	earn, reversed, err := l.repo.FindEarn(ctx, appointmentID, orderID)
+	if err != nil || earn == nil {
+		return 0, err
+	}
+
+	points := domain.ReversalFor(earn, reversed, newAmountCents)
+	if points == 0 {
+		return 0, nil
+	}
+
+	now := l.now().UTC()
+	entry := &models.LoyaltyEntry{
+		BarbershopID:  earn.BarbershopID,
+		ClientID:      earn.ClientID,
+		Kind:          models.LoyaltyEntryReverse,
+		Points:        -points,
+		AppointmentID: earn.AppointmentID,
+		OrderID:       earn.OrderID,
+		CreatedAt:     now,
+	}
+	if err := l.repo.Debit(ctx, entry, now, &earn.ID, true); err != nil {
+		return 0, err
+	}
+	return -entry.Points, nil
+}
+
+// RedeemInput é a troca de uma recompensa no fechamento do atendimento.
+// ChargeCents é o que o cliente ainda pagaria; Lines são os serviços que
+// ele vai pagar.
+type RedeemInput struct {
+	BarbershopID  uint
+	ClientID      uint
+	AppointmentID uint
+	RewardID      uint
+	Lines         []domain.RewardLine
+	ChargeCents   int64
+}
+
+// Redeem debita o custo da recompensa e devolve o desconto concedido.
+func (l *Ledger) Redeem(ctx context.Context, in RedeemInput) (*models.LoyaltyReward, int64, error) {
+	program, err := l.repo.GetProgram(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if program == nil || !program.Active {
+		return nil, 0, domain.ErrProgramInactive
+	}
+
+	reward, err := l.repo.GetReward(ctx, in.BarbershopID, in.RewardID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if reward == nil {
+		return nil, 0, domain.ErrRewardNotFound
+	}
+	if !reward.Active {
+		return nil, 0, domain.ErrRewardInactive
+	}
+
+	discount, err := domain.RewardDiscount(reward, in.Lines, in.ChargeCents)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := l.now().UTC()
+	appointmentID := in.AppointmentID
+	entry := &models.LoyaltyEntry{
+		BarbershopID:  in.BarbershopID,
+		ClientID:      in.ClientID,
+		Kind:          models.LoyaltyEntryRedeem,
+		Points:        -reward.PointsCost,
+		AmountCents:   discount,
+		AppointmentID: &appointmentID,
+		RewardID:      &reward.ID,
+		CreatedAt:     now,
+	}
+	if err := l.repo.Debit(ctx, entry, now, nil, false); err != nil {
+		return nil, 0, err
+	}
+	return reward, discount, nil
+}
+
+// ExpirePoints zera os lotes de pontos vencidos.
+type ExpirePoints struct {
+	repo domain.Repository
+}
+
+func NewExpirePoints(repo domain.Repository) *ExpirePoints {
+	return &ExpirePoints{repo: repo}
+}
+
+func (uc *ExpirePoints) Execute(ctx context.Context) (int64, error) {
+	return uc.repo.ExpireLots(ctx, time.Now().UTC())
+}
@@ -0,0 +1,321 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeRepo guarda o programa, uma recompensa, o crédito do atendimento e o
+// saldo do cliente; o serviço 3 é o único que existe na barbearia.
+type fakeRepo struct {
+	domain.Repository
+	program  *models.LoyaltyProgram
+	reward   *models.LoyaltyReward
+	earn     *models.LoyaltyEntry
+	reversed int
+	balance  int
+	earned   []*models.LoyaltyEntry
+	debits   []*models.LoyaltyEntry
+}
+
+func (r *fakeRepo) WithTx(*gorm.DB) domain.Repository { return r }
+
+func (r *fakeRepo) GetProgram(context.Context, uint) (*models.LoyaltyProgram, error) {
+	return r.program, nil
+}
+
+func (r *fakeRepo) GetReward(_ context.Context, _ uint, id uint) (*models.LoyaltyReward, error) {
+	if r.reward == nil || r.reward.ID != id {
+		return nil, nil
+	}
+	return r.reward, nil
+}
+
+func (r *fakeRepo) SaveProgram(_ context.Context, p *models.LoyaltyProgram) error {
+	r.program = p
+	return nil
+}
+
+func (*fakeRepo) ServiceExists(_ context.Context, _ uint, serviceID uint) (bool, error) {
+	return serviceID == 3, nil
+}
+
+func (r *fakeRepo) Earn(_ context.Context, e *models.LoyaltyEntry) error {
+	r.earned = append(r.earned, e)
+	r.balance += e.Points
+	return nil
+}
+
+func (r *fakeRepo) FindEarn(context.Context, *uint, *uint) (*models.LoyaltyEntry, int, error) {
+	return r.earn, r.reversed, nil
+}
+
+func (r *fakeRepo) Debit(_ context.Context, e *models.LoyaltyEntry, _ time.Time, _ *uint, partial bool) error {
+	want := -e.Points
+	if want > r.balance {
+		if !partial {
+			return domain.ErrInsufficientPoints
+		}
+		e.Points = -r.balance
+	}
+	if e.Points == 0 {
+		return nil
+	}
+	r.balance += e.Points
+	r.debits = append(r.debits, e)
+	return nil
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func activeProgram() *models.LoyaltyProgram {
+	return &models.LoyaltyProgram{BarbershopID: 1, Active: true, PointsPerReal: 2, ExpiryMonths: 12}
+}
+
+func TestPointsFor(t *testing.T) {
+	p := &models.LoyaltyProgram{PointsPerReal: 1}
+
+	cases := []struct {
+		amount int64
+		want   int
+	}{
+		{4590, 45},
+		{99, 0},
+		{0, 0},
+		{-500, 0},
+	}
+	for _, tc := range cases {
+		if got := domain.PointsFor(p, tc.amount); got != tc.want {
+			t.Errorf("PointsFor(%d) = %d, want %d", tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if got := domain.ExpiresAt(&models.LoyaltyProgram{ExpiryMonths: 0}, now); got != nil {
+		t.Fatalf("ExpiryMonths=0 deveria não expirar, got %v", got)
+	}
+	got := domain.ExpiresAt(&models.LoyaltyProgram{ExpiryMonths: 6}, now)
+	if got == nil || !got.Equal(now.AddDate(0, 6, 0)) {
+		t.Fatalf("ExpiresAt = %v, want %v", got, now.AddDate(0, 6, 0))
+	}
+}
+
+func TestReversalFor(t *testing.T) {
+	earn := &models.LoyaltyEntry{Points: 100, AmountCents: 10000}
+
+	cases := []struct {
+		name     string
+		reversed int
+		amount   int64
+		want     int
+	}{
+		{"metade do valor", 0, 5000, 50},
+		{"valor zerado", 0, 0, 100},
+		{"valor maior não pontua", 0, 15000, 0},
+		{"segunda redução desconta a anterior", 50, 2500, 25},
+		{"mesmo valor após reversão", 50, 5000, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.ReversalFor(earn, tc.reversed, tc.amount); got != tc.want {
+				t.Fatalf("ReversalFor = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRewardDiscount(t *testing.T) {
+	lines := []domain.RewardLine{
+		{ServiceID: 3, AmountCents: 4000},
+		{ServiceID: 4, AmountCents: 2500},
+	}
+
+	cases := []struct {
+		name    string
+		reward  models.LoyaltyReward
+		charge  int64
+		want    int64
+		wantErr error
+	}{
+		{"serviço específico", models.LoyaltyReward{Kind: models.LoyaltyRewardFreeService, ServiceID: uintPtr(4)}, 6500, 2500, nil},
+		{"qualquer serviço cobre o primeiro", models.LoyaltyReward{Kind: models.LoyaltyRewardFreeService}, 6500, 4000, nil},
+		{"serviço fora do atendimento", models.LoyaltyReward{Kind: models.LoyaltyRewardFreeService, ServiceID: uintPtr(9)}, 6500, 0, domain.ErrRewardNotApplicable},
+		{"desconto limitado à cobrança", models.LoyaltyReward{Kind: models.LoyaltyRewardDiscount, DiscountCents: 5000}, 3000, 3000, nil},
+		{"nada a cobrar", models.LoyaltyReward{Kind: models.LoyaltyRewardDiscount, DiscountCents: 5000}, 0, 0, domain.ErrRewardNotApplicable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := domain.RewardDiscount(&tc.reward, lines, tc.charge)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("discount = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateProgramValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      ProgramInput
+		wantErr error
+	}{
+		{"válido", ProgramInput{BarbershopID: 1, PointsPerReal: 1, ExpiryMonths: 12}, nil},
+		{"sem expiração", ProgramInput{BarbershopID: 1, PointsPerReal: 1}, nil},
+		{"zero pontos por real", ProgramInput{BarbershopID: 1, ExpiryMonths: 12}, ErrInvalidPointsPerReal},
+		{"pontos demais por real", ProgramInput{BarbershopID: 1, PointsPerReal: 101}, ErrInvalidPointsPerReal},
+		{"validade negativa", ProgramInput{BarbershopID: 1, PointsPerReal: 1, ExpiryMonths: -1}, ErrInvalidExpiryMonths},
+		{"validade longa demais", ProgramInput{BarbershopID: 1, PointsPerReal: 1, ExpiryMonths: 61}, ErrInvalidExpiryMonths},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewUpdateProgram(&fakeRepo{})
+			if _, err := uc.Execute(context.Background(), tc.in); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReward(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      RewardInput
+		wantErr error
+	}{
+		{"serviço grátis qualquer", RewardInput{BarbershopID: 1, Name: "Corte grátis", Kind: models.LoyaltyRewardFreeService, PointsCost: 100}, nil},
+		{"serviço grátis específico", RewardInput{BarbershopID: 1, Name: "Barba", Kind: models.LoyaltyRewardFreeService, PointsCost: 50, ServiceID: uintPtr(3)}, nil},
+		{"serviço de outra barbearia", RewardInput{BarbershopID: 1, Name: "Barba", Kind: models.LoyaltyRewardFreeService, PointsCost: 50, ServiceID: uintPtr(8)}, ErrInvalidRewardService},
+		{"serviço grátis com desconto", RewardInput{BarbershopID: 1, Name: "Barba", Kind: models.LoyaltyRewardFreeService, PointsCost: 50, DiscountCents: 100}, ErrInvalidRewardDiscount},
+		{"desconto", RewardInput{BarbershopID: 1, Name: "R$10", Kind: models.LoyaltyRewardDiscount, PointsCost: 80, DiscountCents: 1000}, nil},
+		{"desconto sem valor", RewardInput{BarbershopID: 1, Name: "R$10", Kind: models.LoyaltyRewardDiscount, PointsCost: 80}, ErrInvalidRewardDiscount},
+		{"desconto com serviço", RewardInput{BarbershopID: 1, Name: "R$10", Kind: models.LoyaltyRewardDiscount, PointsCost: 80, DiscountCents: 1000, ServiceID: uintPtr(3)}, ErrInvalidRewardService},
+		{"nome em branco", RewardInput{BarbershopID: 1, Name: "   ", Kind: models.LoyaltyRewardDiscount, PointsCost: 80, DiscountCents: 1000}, ErrInvalidRewardName},
+		{"custo zero", RewardInput{BarbershopID: 1, Name: "R$10", Kind: models.LoyaltyRewardDiscount, DiscountCents: 1000}, ErrInvalidPointsCost},
+		{"tipo desconhecido", RewardInput{BarbershopID: 1, Name: "Brinde", Kind: "gift", PointsCost: 10}, ErrInvalidRewardKind},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := tc.in
+			if err := validateReward(context.Background(), &fakeRepo{}, &in); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLedgerEarn(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("programa desligado não pontua", func(t *testing.T) {
+		repo := &fakeRepo{program: &models.LoyaltyProgram{PointsPerReal: 1}}
+		l := NewLedger(repo)
+		if err := l.Earn(context.Background(), EarnInput{BarbershopID: 1, ClientID: 7, AmountCents: 5000}); err != nil {
+			t.Fatal(err)
+		}
+		if len(repo.earned) != 0 {
+			t.Fatalf("não deveria creditar, got %d lançamentos", len(repo.earned))
+		}
+	})
+
+	t.Run("credita lote com validade", func(t *testing.T) {
+		repo := &fakeRepo{program: activeProgram()}
+		l := NewLedger(repo)
+		l.now = func() time.Time { return now }
+
+		err := l.Earn(context.Background(), EarnInput{
+			BarbershopID:  1,
+			ClientID:      7,
+			AppointmentID: uintPtr(10),
+			AmountCents:   4590,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repo.earned) != 1 {
+			t.Fatalf("esperava 1 crédito, got %d", len(repo.earned))
+		}
+		e := repo.earned[0]
+		if e.Points != 90 || e.RemainingPoints != 90 {
+			t.Fatalf("pontos = %d/%d, want 90/90", e.Points, e.RemainingPoints)
+		}
+		if e.ExpiresAt == nil || !e.ExpiresAt.Equal(now.AddDate(0, 12, 0)) {
+			t.Fatalf("expires_at = %v", e.ExpiresAt)
+		}
+	})
+}
+
+func TestLedgerReverse(t *testing.T) {
+	t.Run("para no saldo do cliente", func(t *testing.T) {
+		repo := &fakeRepo{
+			earn:    &models.LoyaltyEntry{ID: 5, BarbershopID: 1, ClientID: 7, Points: 100, AmountCents: 10000, AppointmentID: uintPtr(10)},
+			balance: 30,
+		}
+		l := NewLedger(repo)
+
+		got, err := l.Reverse(context.Background(), uintPtr(10), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 30 || repo.balance != 0 {
+			t.Fatalf("revertidos = %d, saldo = %d; want 30, 0", got, repo.balance)
+		}
+	})
+
+	t.Run("sem crédito não faz nada", func(t *testing.T) {
+		repo := &fakeRepo{balance: 30}
+		got, err := NewLedger(repo).Reverse(context.Background(), nil, uintPtr(3), 0)
+		if err != nil || got != 0 || len(repo.debits) != 0 {
+			t.Fatalf("got %d, err %v, debits %d", got, err, len(repo.debits))
+		}
+	})
+}
+
+func TestLedgerRedeem(t *testing.T) {
+	reward := &models.LoyaltyReward{ID: 2, BarbershopID: 1, Kind: models.LoyaltyRewardDiscount, PointsCost: 80, DiscountCents: 1500, Active: true}
+	in := RedeemInput{BarbershopID: 1, ClientID: 7, AppointmentID: 10, RewardID: 2, ChargeCents: 5000}
+
+	cases := []struct {
+		name     string
+		program  *models.LoyaltyProgram
+		reward   *models.LoyaltyReward
+		balance  int
+		want     int64
+		wantErr  error
+		wantLeft int
+	}{
+		{"troca", activeProgram(), reward, 100, 1500, nil, 20},
+		{"saldo insuficiente", activeProgram(), reward, 50, 0, domain.ErrInsufficientPoints, 50},
+		{"programa desligado", nil, reward, 100, 0, domain.ErrProgramInactive, 100},
+		{"recompensa inexistente", activeProgram(), nil, 100, 0, domain.ErrRewardNotFound, 100},
+		{"recompensa inativa", activeProgram(), &models.LoyaltyReward{ID: 2, Kind: models.LoyaltyRewardDiscount, PointsCost: 80, DiscountCents: 1500}, 100, 0, domain.ErrRewardInactive, 100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeRepo{program: tc.program, reward: tc.reward, balance: tc.balance}
+
+			_, discount, err := NewLedger(repo).Redeem(context.Background(), in)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if discount != tc.want {
+				t.Fatalf("discount = %d, want %d", discount, tc.want)
+			}
+			if repo.balance != tc.wantLeft {
+				t.Fatalf("saldo = %d, want %d", repo.balance, tc.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package loyalty
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const (
+	maxPointsPerReal = 100
+	maxExpiryMonths  = 60
+)
+
+// defaultProgram é o programa de uma barbearia que nunca o configurou:
+// desligado, 1 ponto por real e validade de 12 meses.
+func defaultProgram(barbershopID uint) *models.LoyaltyProgram {
+	return &models.LoyaltyProgram{
+		BarbershopID:  barbershopID,
+		Active:        false,
+		PointsPerReal: 1,
+		ExpiryMonths:  12,
+	}
+}
+
+type GetProgram struct {
+	repo domain.Repository
+}
+
+func NewGetProgram(repo domain.Repository) *GetProgram {
+	return &GetProgram{repo: repo}
+}
+
+func (uc *GetProgram) Execute(ctx context.Context, barbershopID uint) (*models.LoyaltyProgram, error) {
+	p, err := uc.repo.GetProgram(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return defaultProgram(barbershopID), nil
+	}
+	return p, nil
+}
+
+type ProgramInput struct {
+	BarbershopID  uint
+	Active        bool
+	PointsPerReal int
+	ExpiryMonths  int // 0 = pontos não expiram
+}
+
+// UpdateProgram liga, desliga ou reconfigura o programa. A nova validade só
+// vale para os pontos creditados daqui em diante; desligar o programa para
+// de pontuar e de trocar, mas mantém o saldo.
+type UpdateProgram struct {
+	repo domain.Repository
+}
+
+func NewUpdateProgram(repo domain.Repository) *UpdateProgram {
+	return &UpdateProgram{repo: repo}
+}
+
+func (uc *UpdateProgram) Execute(ctx context.Context, in ProgramInput) (*models.LoyaltyProgram, error) {
+	if in.BarbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	if in.PointsPerReal <= 0 || in.PointsPerReal > maxPointsPerReal {
+		return nil, ErrInvalidPointsPerReal
+	}
+	if in.ExpiryMonths < 0 || in.ExpiryMonths > maxExpiryMonths {
+		return nil, ErrInvalidExpiryMonths
+	}
+
+	p := &models.LoyaltyProgram{
+		BarbershopID:  in.BarbershopID,
+		Active:        in.Active,
+		PointsPerReal: in.PointsPerReal,
+		ExpiryMonths:  in.ExpiryMonths,
+	}
+	if err := uc.repo.SaveProgram(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,145 @@
+package loyalty
+
+import (
+	"context"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/loyalty"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+const maxRewardNameLength = 100
+
+type RewardInput struct {
+	BarbershopID  uint
+	Name          string
+	Kind          string
+	PointsCost    int
+	ServiceID     *uint // free_service: nil = qualquer serviço
+	DiscountCents int64 // discount
+}
+
+func validateReward(ctx context.Context, repo domain.Repository, in *RewardInput) error {
+	if in.BarbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || len([]rune(in.Name)) > maxRewardNameLength {
+		return ErrInvalidRewardName
+	}
+	if in.PointsCost <= 0 {
+		return ErrInvalidPointsCost
+	}
+
+	switch in.Kind {
+	case models.LoyaltyRewardFreeService:
+		if in.DiscountCents != 0 {
+			return ErrInvalidRewardDiscount
+		}
+		if in.ServiceID == nil {
+			return nil
+		}
+		ok, err := repo.ServiceExists(ctx, in.BarbershopID, *in.ServiceID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidRewardService
+		}
+	case models.LoyaltyRewardDiscount:
+		if in.ServiceID != nil {
+			return ErrInvalidRewardService
+		}
+		if in.DiscountCents <= 0 {
+			return ErrInvalidRewardDiscount
+		}
+	default:
+		return ErrInvalidRewardKind
+	}
+	return nil
+}
+
+func applyRewardInput(r *models.LoyaltyReward, in RewardInput) {
+	r.Name = in.Name
+	r.Kind = in.Kind
+	r.PointsCost = in.PointsCost
+	r.ServiceID = in.ServiceID
+	r.DiscountCents = in.DiscountCents
+}
+
+type CreateReward struct {
+	repo domain.Repository
+}
+
+func NewCreateReward(repo domain.Repository) *CreateReward {
+	return &CreateReward{repo: repo}
+}
+
+func (uc *CreateReward) Execute(ctx context.Context, in RewardInput) (*models.LoyaltyReward, error) {
+	if err := validateReward(ctx, uc.repo, &in); err != nil {
+		return nil, err
+	}
+
+	r := &models.LoyaltyReward{
+		BarbershopID: in.BarbershopID,
+		Active:       true,
+	}
+	applyRewardInput(r, in)
+
+	if err := uc.repo.CreateReward(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateReward altera a recompensa; trocas já feitas ficam com o custo e o
+// desconto da época.
+type UpdateReward struct {
+	repo domain.Repository
+}
+
+func NewUpdateReward(repo domain.Repository) *UpdateReward {
+	return &UpdateReward{repo: repo}
+}
+
+func (uc *UpdateReward) Execute(
+	ctx context.Context,
+	rewardID uint,
+	active *bool, // nil = mantém
+	in RewardInput,
+) (*models.LoyaltyReward, error) {
+	r, err := uc.repo.GetReward(ctx, in.BarbershopID, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ErrRewardNotFound
+	}
+
+	if err := validateReward(ctx, uc.repo, &in); err != nil {
+		return nil, err
+	}
+
+	applyRewardInput(r, in)
+	if active != nil {
+		r.Active = *active
+	}
+
+	if err := uc.repo.UpdateReward(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type ListRewards struct {
+	repo domain.Repository
+}
+
+func NewListRewards(repo domain.Repository) *ListRewards {
+	return &ListRewards{repo: repo}
+}
+
+func (uc *ListRewards) Execute(ctx context.Context, barbershopID uint, onlyActive bool) ([]models.LoyaltyReward, error) {
+	return uc.repo.ListRewards(ctx, barbershopID, onlyActive)
+}
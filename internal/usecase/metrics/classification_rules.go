@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+)
+
+var ErrInvalidBarbershop = errors.New("invalid_barbershop")
+
+// RulesFor devolve as regras de classificação da barbearia, ou as padrão
+// quando ela nunca as configurou (ou não há repositório).
+func RulesFor(
+	ctx context.Context,
+	repo domain.ClassificationRulesRepository,
+	barbershopID uint,
+) (domain.ClassificationRules, error) {
+	if repo == nil {
+		return domain.DefaultClassificationRules(), nil
+	}
+
+	cfg, err := repo.GetRules(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil || cfg.Rules == nil {
+		return domain.DefaultClassificationRules(), nil
+	}
+	return cfg.Rules, nil
+}
+
+// RulesView é a configuração de classificação exibida ao owner.
+type RulesView struct {
+	Custom            bool                       `json:"custom"`
+	Rules             domain.ClassificationRules `json:"rules"`
+	Defaults          domain.ClassificationRules `json:"defaults"`
+	Categories        []domain.ClientCategory    `json:"categories"`
+	ReclassifyPending bool                       `json:"reclassify_pending"`
+	ReclassifiedAt    *time.Time                 `json:"reclassified_at,omitempty"`
+}
+
+type GetClassificationRules struct {
+	repo domain.ClassificationRulesRepository
+}
+
+func NewGetClassificationRules(repo domain.ClassificationRulesRepository) *GetClassificationRules {
+	return &GetClassificationRules{repo: repo}
+}
+
+func (uc *GetClassificationRules) Execute(ctx context.Context, barbershopID uint) (*RulesView, error) {
+	cfg, err := uc.repo.GetRules(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &RulesView{
+		Rules:    domain.DefaultClassificationRules(),
+		Defaults: domain.DefaultClassificationRules(),
+	}
+	if cfg != nil {
+		view.ReclassifyPending = cfg.ReclassifyPending
+		view.ReclassifiedAt = cfg.ReclassifiedAt
+		if cfg.Rules != nil {
+			view.Custom = true
+			view.Rules = cfg.Rules
+		}
+	}
+	view.Categories = view.Rules.Categories()
+
+	return view, nil
+}
+
+type UpdateClassificationRulesInput struct {
+	BarbershopID uint
+	// Rules nil volta às regras padrão.
+	Rules domain.ClassificationRules
+}
+
+// UpdateClassificationRules valida e grava as regras. A categoria dos
+// clientes é recalculada pelo job de reclassificação, não aqui.
+type UpdateClassificationRules struct {
+	repo domain.ClassificationRulesRepository
+}
+
+func NewUpdateClassificationRules(repo domain.ClassificationRulesRepository) *UpdateClassificationRules {
+	return &UpdateClassificationRules{repo: repo}
+}
+
+func (uc *UpdateClassificationRules) Execute(ctx context.Context, in UpdateClassificationRulesInput) error {
+	if in.BarbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+	if err := in.Rules.Validate(); err != nil {
+		return err
+	}
+	return uc.repo.SaveRules(ctx, in.BarbershopID, in.Rules)
+}
+
+// CategoryMove conta os clientes que passariam de From para To.
+type CategoryMove struct {
+	From    domain.ClientCategory `json:"from"`
+	To      domain.ClientCategory `json:"to"`
+	Clients int                   `json:"clients"`
+}
+
+// SimulationResult compara a classificação atual com a das regras propostas.
+// Clientes com categoria manual em vigor não mudam e entram em Manual.
+type SimulationResult struct {
+	TotalClients int                           `json:"total_clients"`
+	Changed      int                           `json:"changed"`
+	Manual       int                           `json:"manual"`
+	Before       map[domain.ClientCategory]int `json:"before"`
+	After        map[domain.ClientCategory]int `json:"after"`
+	Moves        []CategoryMove                `json:"moves"`
+}
+
+type SimulateClassification struct {
+	metrics domain.ClientMetricsRepository
+	rules   domain.ClassificationRulesRepository
+}
+
+func NewSimulateClassification(
+	metrics domain.ClientMetricsRepository,
+	rules domain.ClassificationRulesRepository,
+) *SimulateClassification {
+	return &SimulateClassification{metrics: metrics, rules: rules}
+}
+
+func (uc *SimulateClassification) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	proposed domain.ClassificationRules,
+) (*SimulationResult, error) {
+	if barbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	if err := proposed.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := RulesFor(ctx, uc.rules, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := uc.metrics.FindByBarbershop(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulate(list, current, proposed, time.Now().UTC()), nil
+}
+
+func simulate(
+	list []*domain.ClientMetrics,
+	current domain.ClassificationRules,
+	proposed domain.ClassificationRules,
+	now time.Time,
+) *SimulationResult {
+	res := &SimulationResult{
+		TotalClients: len(list),
+		Before:       map[domain.ClientCategory]int{},
+		After:        map[domain.ClientCategory]int{},
+		Moves:        []CategoryMove{},
+	}
+	moves := map[[2]domain.ClientCategory]int{}
+
+	for _, m := range list {
+		if manualInEffect(m, now) {
+			res.Manual++
+			res.Before[m.Category]++
+			res.After[m.Category]++
+			continue
+		}
+
+		before := current.Classify(m, now)
+		after := proposed.Classify(m, now)
+		res.Before[before]++
+		res.After[after]++
+
+		if before != after {
+			res.Changed++
+			moves[[2]domain.ClientCategory{before, after}]++
+		}
+	}
+
+	for k, n := range moves {
+		res.Moves = append(res.Moves, CategoryMove{From: k[0], To: k[1], Clients: n})
+	}
+	sort.Slice(res.Moves, func(i, j int) bool {
+		a, b := res.Moves[i], res.Moves[j]
+		if a.Clients != b.Clients {
+			return a.Clients > b.Clients
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+
+	return res
+}
+
+func manualInEffect(m *domain.ClientMetrics, now time.Time) bool {
+	return m.CategorySource == domain.CategorySourceManual &&
+		(m.ManualCategoryExpiresAt == nil || now.Before(*m.ManualCategoryExpiresAt))
+}
+
+// ReclassifyClients recalcula a categoria de todos os clientes das
+// barbearias cujas regras mudaram desde a última rodada.
+type ReclassifyClients struct {
+	metrics domain.ClientMetricsRepository
+	rules   domain.ClassificationRulesRepository
+}
+
+func NewReclassifyClients(
+	metrics domain.ClientMetricsRepository,
+	rules domain.ClassificationRulesRepository,
+) *ReclassifyClients {
+	return &ReclassifyClients{metrics: metrics, rules: rules}
+}
+
+// Execute retorna quantos clientes mudaram de categoria.
+func (uc *ReclassifyClients) Execute(ctx context.Context) (int, error) {
+	pending, err := uc.rules.PendingReclassification(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, cfg := range pending {
+		rules := cfg.Rules
+		if rules == nil {
+			rules = domain.DefaultClassificationRules()
+		}
+
+		list, err := uc.metrics.FindByBarbershop(ctx, cfg.BarbershopID)
+		if err != nil {
+			return changed, err
+		}
+
+		now := time.Now().UTC()
+		for _, m := range list {
+			category, source := m.Category, m.CategorySource
+			m.RecalculateCategory(rules, now)
+			if m.Category == category && m.CategorySource == source {
+				continue
+			}
+			if err := uc.rules.UpdateCategory(ctx, m); err != nil {
+				return changed, err
+			}
+			changed++
+		}
+
+		if err := uc.rules.MarkReclassified(ctx, cfg, now); err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestSimulate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	old := now.AddDate(0, 0, -100)
+	future := now.AddDate(0, 0, 7)
+
+	list := []*domain.ClientMetrics{
+		{ClientID: 1},
+		{ClientID: 2, TotalAppointments: 5, CompletedAppointments: 5, LastCompletedAt: &recent},
+		{ClientID: 3, TotalAppointments: 3, CompletedAppointments: 3, LastCompletedAt: &old},
+		{ClientID: 4, TotalAppointments: 4, CompletedAppointments: 4, LastCompletedAt: &old},
+		{ClientID: 5, TotalAppointments: 3, CompletedAppointments: 3, LastCompletedAt: &old,
+			Category: domain.CategoryTrusted, CategorySource: domain.CategorySourceManual, ManualCategoryExpiresAt: &future},
+	}
+
+	// Só muda a inatividade: at_risk depois de 90 dias vira dormant.
+	proposed := domain.DefaultClassificationRules()
+	proposed[2] = domain.ClassificationRule{Category: "dormant", InactiveDays: intPtr(90)}
+
+	res := simulate(list, domain.DefaultClassificationRules(), proposed, now)
+
+	if res.TotalClients != 5 || res.Changed != 2 || res.Manual != 1 {
+		t.Fatalf("totais inesperados: %+v", res)
+	}
+	if res.Before[domain.CategoryAtRisk] != 2 || res.After["dormant"] != 2 || res.After[domain.CategoryAtRisk] != 0 {
+		t.Errorf("contagens inesperadas: antes %v, depois %v", res.Before, res.After)
+	}
+	if res.Before[domain.CategoryTrusted] != 2 || res.After[domain.CategoryTrusted] != 2 {
+		t.Errorf("trusted (inclui o manual) não deveria mudar: antes %v, depois %v", res.Before, res.After)
+	}
+	if len(res.Moves) != 1 || res.Moves[0] != (CategoryMove{From: domain.CategoryAtRisk, To: "dormant", Clients: 2}) {
+		t.Errorf("movimentos inesperados: %+v", res.Moves)
+	}
+}
+
+type fakeRulesRepo struct {
+	domain.ClassificationRulesRepository
+	pending []domain.RulesConfig
+	updated []domain.ClientMetrics
+	marked  []uint
+}
+
+func (f *fakeRulesRepo) PendingReclassification(context.Context) ([]domain.RulesConfig, error) {
+	return f.pending, nil
+}
+
+func (f *fakeRulesRepo) UpdateCategory(_ context.Context, m *domain.ClientMetrics) error {
+	f.updated = append(f.updated, *m)
+	return nil
+}
+
+func (f *fakeRulesRepo) MarkReclassified(_ context.Context, cfg domain.RulesConfig, _ time.Time) error {
+	f.marked = append(f.marked, cfg.BarbershopID)
+	return nil
+}
+
+type fakeMetricsRepo struct {
+	domain.ClientMetricsRepository
+	list []*domain.ClientMetrics
+}
+
+func (f *fakeMetricsRepo) FindByBarbershop(context.Context, uint) ([]*domain.ClientMetrics, error) {
+	return f.list, nil
+}
+
+func TestReclassifyClients(t *testing.T) {
+	recent := time.Now().UTC().AddDate(0, 0, -5)
+	expired := time.Now().UTC().AddDate(0, 0, -1)
+
+	metrics := &fakeMetricsRepo{list: []*domain.ClientMetrics{
+		{ClientID: 1, TotalAppointments: 2, CompletedAppointments: 2, LastCompletedAt: &recent,
+			Category: domain.CategoryRegular, CategorySource: domain.CategorySourceAuto},
+		{ClientID: 2, TotalAppointments: 1, CompletedAppointments: 1, LastCompletedAt: &recent,
+			Category: domain.CategoryRegular, CategorySource: domain.CategorySourceAuto},
+		{ClientID: 3, TotalAppointments: 1, CompletedAppointments: 1, LastCompletedAt: &recent,
+			Category: domain.CategoryAtRisk, CategorySource: domain.CategorySourceManual, ManualCategoryExpiresAt: &expired},
+	}}
+	rules := &fakeRulesRepo{pending: []domain.RulesConfig{{
+		BarbershopID: 7,
+		Rules:        domain.ClassificationRules{{Category: "vip", MinCompleted: intPtr(2)}},
+	}}}
+
+	n, err := NewReclassifyClients(metrics, rules).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	// Cliente 1 vira vip; o 2 não muda; o 3 perde o override vencido.
+	if n != 2 || len(rules.updated) != 2 {
+		t.Fatalf("esperado 2 clientes alterados, obtido %d (%+v)", n, rules.updated)
+	}
+	if rules.updated[0].ClientID != 1 || rules.updated[0].Category != "vip" {
+		t.Errorf("cliente 1 inesperado: %+v", rules.updated[0])
+	}
+	if rules.updated[1].ClientID != 3 || rules.updated[1].Category != domain.CategoryRegular ||
+		rules.updated[1].CategorySource != domain.CategorySourceAuto {
+		t.Errorf("cliente 3 inesperado: %+v", rules.updated[1])
+	}
+	if len(rules.marked) != 1 || rules.marked[0] != 7 {
+		t.Errorf("barbearia não marcada como reclassificada: %v", rules.marked)
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 type GetClientCategory struct {
-	repo domainMetrics.ClientMetricsRepository
+	repo  domainMetrics.ClientMetricsRepository
+	rules domainMetrics.ClassificationRulesRepository
 }
 
 func NewGetClientCategory(
@@ -19,6 +20,12 @@ func NewGetClientCategory(
 	}
 }
 
+// WithRules classifica com as regras da barbearia; sem elas, valem as padrão.
+func (uc *GetClientCategory) WithRules(rules domainMetrics.ClassificationRulesRepository) *GetClientCategory {
+	uc.rules = rules
+	return uc
+}
+
 func (uc *GetClientCategory) Execute(
 	ctx context.Context,
 	barbershopID uint,
@@ -38,5 +45,9 @@ func (uc *GetClientCategory) Execute(
 	}
 
 	// 2) fallback para classificação comportamental
-	return domainMetrics.Classify(m), nil
+	rules, err := RulesFor(ctx, uc.rules, barbershopID)
+	if err != nil {
+		return "", err
+	}
+	return rules.Classify(m, time.Now().UTC()), nil
 }
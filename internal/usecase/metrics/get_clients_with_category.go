@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"time"
 
 	domainMetrics "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
 )
@@ -12,7 +13,8 @@ type ClientWithCategory struct {
 }
 
 type GetClientsWithCategory struct {
-	repo domainMetrics.ClientMetricsRepository
+	repo  domainMetrics.ClientMetricsRepository
+	rules domainMetrics.ClassificationRulesRepository
 }
 
 func NewGetClientsWithCategory(
@@ -23,6 +25,12 @@ func NewGetClientsWithCategory(
 	}
 }
 
+// WithRules classifica com as regras da barbearia; sem elas, valem as padrão.
+func (uc *GetClientsWithCategory) WithRules(rules domainMetrics.ClassificationRulesRepository) *GetClientsWithCategory {
+	uc.rules = rules
+	return uc
+}
+
 func (uc *GetClientsWithCategory) Execute(
 	ctx context.Context,
 	barbershopID uint,
@@ -33,10 +41,16 @@ func (uc *GetClientsWithCategory) Execute(
 		return nil, err
 	}
 
+	rules, err := RulesFor(ctx, uc.rules, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+
 	out := make([]ClientWithCategory, 0, len(metricsList))
 
 	for _, m := range metricsList {
-		category := domainMetrics.Classify(m)
+		category := rules.Classify(m, now)
 
 		// 1) override manual
 		if m.CategorySource == domainMetrics.CategorySourceManual && m.Category != "" {
@@ -3,13 +3,15 @@ package metrics
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/metrics"
 )
 
 type SetClientCategory struct {
-	repo domain.ClientMetricsRepository
+	repo  domain.ClientMetricsRepository
+	rules domain.ClassificationRulesRepository
 }
 
 func NewSetClientCategory(
@@ -18,6 +20,12 @@ func NewSetClientCategory(
 	return &SetClientCategory{repo: repo}
 }
 
+// WithRules aceita também as categorias próprias das regras da barbearia.
+func (uc *SetClientCategory) WithRules(rules domain.ClassificationRulesRepository) *SetClientCategory {
+	uc.rules = rules
+	return uc
+}
+
 type SetClientCategoryInput struct {
 	BarbershopID uint
 	ClientID     uint
@@ -40,7 +48,13 @@ func (uc *SetClientCategory) Execute(
 		domain.CategoryTrusted,
 		domain.CategoryAtRisk:
 	default:
-		return errors.New("invalid_category")
+		rules, err := RulesFor(ctx, uc.rules, input.BarbershopID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(rules.Categories(), input.Category) {
+			return errors.New("invalid_category")
+		}
 	}
 
 	m, err := uc.repo.GetOrCreate(ctx, input.BarbershopID, input.ClientID)
@@ -11,8 +11,9 @@ import (
 )
 
 type UpdateClientMetrics struct {
-	repo *infraRepo.ClientMetricsGormRepository
-	db   *gorm.DB
+	repo  *infraRepo.ClientMetricsGormRepository
+	db    *gorm.DB
+	rules domain.ClassificationRulesRepository
 }
 
 func NewUpdateClientMetrics(
@@ -22,6 +23,12 @@ func NewUpdateClientMetrics(
 	return &UpdateClientMetrics{repo: repo, db: db}
 }
 
+// WithRules reclassifica com as regras da barbearia; sem elas, valem as padrão.
+func (uc *UpdateClientMetrics) WithRules(rules domain.ClassificationRulesRepository) *UpdateClientMetrics {
+	uc.rules = rules
+	return uc
+}
+
 type UpdateClientMetricsInput struct {
 	BarbershopID uint
 	ClientID     uint
@@ -58,6 +65,11 @@ func (uc *UpdateClientMetrics) Execute(
 		occurredAt = time.Now().UTC()
 	}
 
+	rules, err := RulesFor(ctx, uc.rules, in.BarbershopID)
+	if err != nil {
+		return err
+	}
+
 	return uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		txRepo := uc.repo.WithTx(tx)
 
@@ -86,6 +98,12 @@ func (uc *UpdateClientMetrics) Execute(
 			return nil
 		}
 
+		// A criação não muda a categoria: ela só é recalculada quando o
+		// atendimento tem desfecho (conclusão, cancelamento, falta, remarcação).
+		if in.EventType != EventAppointmentCreated {
+			m.RecalculateCategory(rules, time.Now().UTC())
+		}
+
 		return txRepo.Save(ctx, m)
 	})
 }
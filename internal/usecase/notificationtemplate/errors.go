@@ -0,0 +1,8 @@
+package notificationtemplate
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop  = errors.New("invalid_barbershop")
+	ErrBarbershopNotFound = errors.New("barbershop_not_found")
+)
@@ -0,0 +1,159 @@
+package notificationtemplate
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+)
+
+var channels = []string{models.NotificationChannelEmail, models.NotificationChannelWhatsApp}
+
+// TemplateView é uma mensagem personalizável em um canal. Sem Custom, vale
+// o texto padrão e Subject/Body vêm vazios.
+type TemplateView struct {
+	Kind         string     `json:"kind"`
+	Channel      string     `json:"channel"`
+	Custom       bool       `json:"custom"`
+	Subject      string     `json:"subject,omitempty"`
+	Body         string     `json:"body,omitempty"`
+	Placeholders []string   `json:"placeholders"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// ListTemplates lista todas as mensagens personalizáveis, em todos os
+// canais, com o texto personalizado quando houver.
+type ListTemplates struct {
+	repo domain.TemplateRepository
+}
+
+func NewListTemplates(repo domain.TemplateRepository) *ListTemplates {
+	return &ListTemplates{repo: repo}
+}
+
+func (uc *ListTemplates) Execute(ctx context.Context, barbershopID uint) ([]TemplateView, error) {
+	saved, err := uc.repo.List(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*models.NotificationTemplate, len(saved))
+	for i := range saved {
+		byKey[saved[i].Kind+"/"+saved[i].Channel] = &saved[i]
+	}
+
+	var views []TemplateView
+	for _, kind := range notification.TemplateKinds() {
+		for _, ch := range channels {
+			v := TemplateView{
+				Kind:         kind,
+				Channel:      ch,
+				Placeholders: notification.TemplatePlaceholders(kind),
+			}
+			if t, ok := byKey[kind+"/"+ch]; ok {
+				v.Custom = true
+				v.Subject = t.Subject
+				v.Body = t.Body
+				v.UpdatedAt = &t.UpdatedAt
+			}
+			views = append(views, v)
+		}
+	}
+	return views, nil
+}
+
+type SaveInput struct {
+	BarbershopID uint
+	Kind         string
+	Channel      string
+	Subject      string // só email
+	Body         string
+}
+
+// SaveTemplate grava o texto personalizado de uma mensagem. Templates que
+// não renderizariam são recusados aqui, nunca na hora do envio.
+type SaveTemplate struct {
+	repo domain.TemplateRepository
+}
+
+func NewSaveTemplate(repo domain.TemplateRepository) *SaveTemplate {
+	return &SaveTemplate{repo: repo}
+}
+
+func (uc *SaveTemplate) Execute(ctx context.Context, in SaveInput) (*models.NotificationTemplate, error) {
+	if in.BarbershopID == 0 {
+		return nil, ErrInvalidBarbershop
+	}
+	if err := notification.ValidateTemplate(in.Kind, in.Channel, in.Subject, in.Body); err != nil {
+		return nil, err
+	}
+
+	t := &models.NotificationTemplate{
+		BarbershopID: in.BarbershopID,
+		Kind:         in.Kind,
+		Channel:      in.Channel,
+		Subject:      in.Subject,
+		Body:         in.Body,
+	}
+	if err := uc.repo.Save(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ResetTemplate apaga o texto personalizado: a mensagem volta ao padrão.
+type ResetTemplate struct {
+	repo domain.TemplateRepository
+}
+
+func NewResetTemplate(repo domain.TemplateRepository) *ResetTemplate {
+	return &ResetTemplate{repo: repo}
+}
+
+func (uc *ResetTemplate) Execute(ctx context.Context, barbershopID uint, kind, channel string) error {
+	if barbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+	if err := notification.CheckKindChannel(kind, channel); err != nil {
+		return err
+	}
+	return uc.repo.Delete(ctx, barbershopID, kind, channel)
+}
+
+type PreviewInput struct {
+	BarbershopID uint
+	Kind         string
+	Channel      string
+	Subject      string
+	Body         string // vazio = prévia do texto padrão
+}
+
+// PreviewTemplate renderiza a mensagem com os dados da barbearia e um
+// cliente de exemplo, sem salvar.
+type PreviewTemplate struct {
+	repo   domain.TemplateRepository
+	appURL string
+}
+
+func NewPreviewTemplate(repo domain.TemplateRepository, appURL string) *PreviewTemplate {
+	return &PreviewTemplate{repo: repo, appURL: appURL}
+}
+
+func (uc *PreviewTemplate) Execute(ctx context.Context, in PreviewInput) (*notification.RenderedTemplate, error) {
+	shop, err := uc.repo.GetBarbershop(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, ErrBarbershopNotFound
+	}
+
+	return notification.PreviewTemplate(in.Kind, in.Channel, in.Subject, in.Body, notification.TemplateSample{
+		BarbershopName:    shop.Name,
+		BarbershopPhone:   shop.Phone,
+		BarbershopAddress: shop.Address,
+		Timezone:          shop.Timezone,
+		AppURL:            uc.appURL,
+	})
+}
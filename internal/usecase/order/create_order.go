@@ -6,15 +6,19 @@ import (
 
 	"gorm.io/gorm"
 
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
 	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
 	productDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+	ucCoupon "github.com/BruksfildServices01/barber-scheduler/internal/usecase/coupon"
 )
 
 type CreateOrder struct {
 	db                *gorm.DB
 	orderRepository   *infraRepo.OrderGormRepository
 	productRepository *infraRepo.ProductGormRepository
+	couponRepository  *infraRepo.CouponGormRepository
 }
 
 func NewCreateOrder(
@@ -37,9 +41,16 @@ func (uc *CreateOrder) WithTx(tx *gorm.DB) *CreateOrder {
 		db:                tx,
 		orderRepository:   uc.orderRepository,
 		productRepository: uc.productRepository,
+		couponRepository:  uc.couponRepository,
 	}
 }
 
+// WithCoupons habilita CouponCode no pedido.
+func (uc *CreateOrder) WithCoupons(couponRepo *infraRepo.CouponGormRepository) *CreateOrder {
+	uc.couponRepository = couponRepo
+	return uc
+}
+
 type CreateOrderItemInput struct {
 	ProductID uint `json:"product_id"`
 	Quantity  int  `json:"quantity"`
@@ -49,6 +60,10 @@ type CreateOrderInput struct {
 	BarbershopID uint                   `json:"barbershop_id"`
 	ClientID     *uint                  `json:"client_id,omitempty"`
 	Items        []CreateOrderItemInput `json:"items"`
+
+	// CouponCode aplica um cupom sobre os produtos; o uso é resgatado na
+	// mesma transação do pedido.
+	CouponCode string `json:"coupon_code,omitempty"`
 }
 
 func (uc *CreateOrder) Execute(
@@ -101,6 +116,14 @@ func (uc *CreateOrder) Execute(
 			}
 		}
 
+		var quote *ucCoupon.Quote
+		if input.CouponCode != "" {
+			var err error
+			if quote, err = uc.quoteCoupon(ctx, tx, order, input); err != nil {
+				return err
+			}
+		}
+
 		if err := order.Validate(); err != nil {
 			return err
 		}
@@ -109,6 +132,17 @@ func (uc *CreateOrder) Execute(
 			return err
 		}
 
+		if quote != nil {
+			applier := ucCoupon.NewApplier(uc.couponRepository.WithTx(tx))
+			if err := applier.Redeem(ctx, quote.Coupon, &models.CouponRedemption{
+				ClientID:      input.ClientID,
+				OrderID:       &order.ID,
+				DiscountCents: quote.DiscountCents,
+			}); err != nil {
+				return err
+			}
+		}
+
 		createdOrder = order
 		return nil
 	})
@@ -119,3 +153,35 @@ func (uc *CreateOrder) Execute(
 
 	return createdOrder, nil
 }
+
+// quoteCoupon valida o cupom sobre os produtos do pedido e aplica o desconto.
+func (uc *CreateOrder) quoteCoupon(
+	ctx context.Context,
+	tx *gorm.DB,
+	order *orderDomain.Order,
+	input CreateOrderInput,
+) (*ucCoupon.Quote, error) {
+	if uc.couponRepository == nil {
+		return nil, couponDomain.ErrNotFound
+	}
+
+	lines := make([]couponDomain.Line, 0, len(order.Items))
+	for _, it := range order.Items {
+		lines = append(lines, couponDomain.Line{ItemID: it.ProductID, AmountCents: it.LineTotal})
+	}
+
+	applier := ucCoupon.NewApplier(uc.couponRepository.WithTx(tx))
+	quote, err := applier.Quote(ctx, input.BarbershopID, input.CouponCode, input.ClientID, couponDomain.Purchase{
+		Kind:  models.CouponAppliesToProducts,
+		Lines: lines,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// O pedido não pode sair zerado: não há o que cobrar no provider.
+	if err := order.ApplyDiscount(quote.Coupon.ID, quote.DiscountCents); err != nil {
+		return nil, couponDomain.ErrNotApplicable
+	}
+	return quote, nil
+}
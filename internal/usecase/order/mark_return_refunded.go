@@ -0,0 +1,68 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+)
+
+// MarkReturnRefunded confirma que o dono devolveu por fora (dinheiro,
+// maquininha) o valor de uma devolução em reembolso manual.
+type MarkReturnRefunded struct {
+	orderRepo *infraRepo.OrderGormRepository
+	audit     *audit.Dispatcher
+}
+
+func NewMarkReturnRefunded(
+	orderRepo *infraRepo.OrderGormRepository,
+	audit *audit.Dispatcher,
+) *MarkReturnRefunded {
+	return &MarkReturnRefunded{
+		orderRepo: orderRepo,
+		audit:     audit,
+	}
+}
+
+func (uc *MarkReturnRefunded) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	orderID uint,
+	returnID uint,
+	userID *uint,
+) (*models.OrderReturn, error) {
+	ret, err := uc.orderRepo.GetReturn(ctx, barbershopID, orderID, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, apperr.ErrBusiness("order_return_not_found")
+	}
+	if ret.RefundStatus != models.OrderRefundManualPending {
+		return nil, apperr.ErrBusiness("refund_not_pending")
+	}
+
+	now := time.Now().UTC()
+	if err := uc.orderRepo.UpdateReturnRefund(ctx, ret.ID, models.OrderRefundManualRefunded, &now); err != nil {
+		return nil, err
+	}
+	ret.RefundStatus = models.OrderRefundManualRefunded
+	ret.RefundedAt = &now
+
+	uc.audit.Dispatch(audit.Event{
+		BarbershopID: barbershopID,
+		UserID:       userID,
+		Action:       "order_return_refunded",
+		Entity:       "order",
+		EntityID:     &ret.OrderID,
+		Metadata: map[string]any{
+			"order_return_id": ret.ID,
+			"amount_cents":    ret.AmountCents,
+		},
+	})
+
+	return ret, nil
+}
@@ -0,0 +1,386 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
+	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
+	infraRepo "github.com/BruksfildServices01/barber-scheduler/internal/repository"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
+	ucPayment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/payment"
+)
+
+const maxReturnReasonLength = 255
+
+// paymentRefunder estorna o pagamento no provider (satisfeito por
+// payment.RefundPayment).
+type paymentRefunder interface {
+	Execute(ctx context.Context, input ucPayment.RefundPaymentInput, gatewayOverride ...domainPayment.TransparentGateway) (*models.Payment, error)
+}
+
+// gatewayResolver resolve o gateway do provider que cobrou o pedido
+// (satisfeito por payment.ProviderRegistry).
+type gatewayResolver interface {
+	GatewayForProvider(ctx context.Context, barbershopID uint, providerName string) (domainPayment.TransparentGateway, error)
+}
+
+// txableCouponRepo vincula o repositório de cupons à transação da
+// devolução (satisfeito por repository.CouponGormRepository).
+type txableCouponRepo interface {
+	WithTx(tx *gorm.DB) couponDomain.Repository
+}
+
+// ReturnOrder cancela um pedido ou devolve parte dos itens: o estoque volta
+// pelo razão e o valor é estornado no pagamento vinculado. Sem pagamento
+// estornável (pago no fechamento, provider fora do ar), a devolução fica
+// marcada para reembolso manual.
+type ReturnOrder struct {
+	db          *gorm.DB
+	orderRepo   *infraRepo.OrderGormRepository
+	productRepo *infraRepo.ProductGormRepository
+	refunds     paymentRefunder
+	gateways    gatewayResolver
+	audit       *audit.Dispatcher
+	loyalty     *ucLoyalty.Ledger
+	coupons     txableCouponRepo
+}
+
+func NewReturnOrder(
+	db *gorm.DB,
+	orderRepo *infraRepo.OrderGormRepository,
+	productRepo *infraRepo.ProductGormRepository,
+	refunds paymentRefunder,
+	gateways gatewayResolver,
+	audit *audit.Dispatcher,
+) *ReturnOrder {
+	return &ReturnOrder{
+		db:          db,
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		refunds:     refunds,
+		gateways:    gateways,
+		audit:       audit,
+	}
+}
+
+// WithLoyalty estorna os pontos de fidelidade do valor devolvido.
+func (uc *ReturnOrder) WithLoyalty(l *ucLoyalty.Ledger) *ReturnOrder {
+	uc.loyalty = l
+	return uc
+}
+
+// WithCoupons devolve ao cupom o uso do pedido cancelado.
+func (uc *ReturnOrder) WithCoupons(r txableCouponRepo) *ReturnOrder {
+	uc.coupons = r
+	return uc
+}
+
+type ReturnOrderItemInput struct {
+	ProductID uint
+	Quantity  int
+}
+
+type ReturnOrderInput struct {
+	BarbershopID uint
+	OrderID      uint
+	UserID       *uint
+	Reason       string
+
+	// Cancel devolve tudo o que resta do pedido; senão, devolve Items.
+	Cancel bool
+	Items  []ReturnOrderItemInput
+}
+
+func (uc *ReturnOrder) Execute(
+	ctx context.Context,
+	input ReturnOrderInput,
+) (*models.OrderReturn, error) {
+	reason, err := normalizeReturnReason(input.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ret             *models.OrderReturn
+		payment         *models.Payment
+		loyaltyReversed int
+	)
+
+	// A auditoria entra na mesma transação pelo outbox.
+	err = uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		octx := outbox.ContextWithTx(ctx, tx)
+		orderRepoTx := uc.orderRepo.WithTx(tx)
+		productRepoTx := uc.productRepo.WithTx(tx)
+
+		order, err := orderRepoTx.GetForUpdate(ctx, input.BarbershopID, input.OrderID)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return apperr.ErrBusiness("order_not_found")
+		}
+
+		switch order.Status {
+		case orderDomain.OrderStatusCancelled:
+			return apperr.ErrBusiness("order_already_cancelled")
+
+		case orderDomain.OrderStatusPending:
+			// Pedido não pago: o estoque ainda não saiu, só cancela.
+			if !input.Cancel {
+				return apperr.ErrBusiness("order_not_paid")
+			}
+			pending, err := orderRepoTx.HasPendingPayment(ctx, input.BarbershopID, order.ID)
+			if err != nil {
+				return err
+			}
+			if pending {
+				return apperr.ErrBusiness("order_payment_in_progress")
+			}
+
+			ret = newOrderReturn(order, input, reason, nil, 0)
+			if err := orderRepoTx.CreateReturn(ctx, ret); err != nil {
+				return err
+			}
+			if err := orderRepoTx.UpdateStatus(ctx, input.BarbershopID, order.ID, orderDomain.OrderStatusCancelled); err != nil {
+				return err
+			}
+			if err := uc.voidCoupons(ctx, tx, order.ID); err != nil {
+				return err
+			}
+			return uc.dispatchAudit(octx, input, ret, 0)
+		}
+
+		lines, err := planReturnLines(order, input)
+		if err != nil {
+			return err
+		}
+
+		alreadyReturned, err := orderRepoTx.SumReturnedAmount(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		amount := order.RefundAmount(lines, alreadyReturned)
+
+		payment, err = orderRepoTx.FindRefundablePayment(ctx, input.BarbershopID, order.ID)
+		if err != nil {
+			return err
+		}
+
+		ret = newOrderReturn(order, input, reason, lines, amount)
+		if payment != nil {
+			ret.PaymentID = &payment.ID
+		}
+		if err := orderRepoTx.CreateReturn(ctx, ret); err != nil {
+			return err
+		}
+
+		for _, l := range lines {
+			if err := orderRepoTx.AddReturnedQuantity(ctx, l.OrderItemID, l.Quantity); err != nil {
+				return err
+			}
+			markReturned(order, l)
+
+			if err := productRepoTx.RestoreStock(ctx, input.BarbershopID, l.ProductID, l.Quantity, order.ID, input.UserID); err != nil {
+				return err
+			}
+		}
+
+		if uc.loyalty != nil && amount > 0 {
+			orderID := order.ID
+			kept := order.TotalAmount - alreadyReturned - amount
+			loyaltyReversed, err = uc.loyalty.WithTx(tx).Reverse(ctx, nil, &orderID, kept)
+			if err != nil {
+				return err
+			}
+		}
+
+		if order.FullyReturned() {
+			if err := orderRepoTx.UpdateStatus(ctx, input.BarbershopID, order.ID, orderDomain.OrderStatusCancelled); err != nil {
+				return err
+			}
+			if err := uc.voidCoupons(ctx, tx, order.ID); err != nil {
+				return err
+			}
+		}
+		return uc.dispatchAudit(octx, input, ret, loyaltyReversed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ret.RefundStatus == models.OrderRefundManualPending && payment != nil {
+		uc.refund(ctx, ret, payment)
+	}
+
+	return ret, nil
+}
+
+// voidCoupons devolve ao cupom o uso do pedido que ficou cancelado.
+// Devolução parcial mantém o uso: a compra continua valendo.
+func (uc *ReturnOrder) voidCoupons(ctx context.Context, tx *gorm.DB, orderID uint) error {
+	if uc.coupons == nil {
+		return nil
+	}
+	return uc.coupons.WithTx(tx).VoidForOrder(ctx, orderID)
+}
+
+// dispatchAudit registra a devolução no outbox (ctx ligado à transação).
+// O estorno no provider acontece depois do commit e é auditado à parte
+// (payment_refunded), então refund_status é o da devolução recém-criada.
+func (uc *ReturnOrder) dispatchAudit(
+	ctx context.Context,
+	input ReturnOrderInput,
+	ret *models.OrderReturn,
+	loyaltyReversed int,
+) error {
+	action := "order_items_returned"
+	if ret.Kind == models.OrderReturnKindCancellation {
+		action = "order_cancelled"
+	}
+	metadata := map[string]any{
+		"order_return_id": ret.ID,
+		"reason":          ret.Reason,
+		"amount_cents":    ret.AmountCents,
+		"refund_status":   ret.RefundStatus,
+		"payment_id":      ret.PaymentID,
+		"items":           len(ret.Items),
+	}
+	if loyaltyReversed > 0 {
+		metadata["loyalty_points_reversed"] = loyaltyReversed
+	}
+	return uc.audit.DispatchContext(ctx, audit.Event{
+		BarbershopID: input.BarbershopID,
+		UserID:       input.UserID,
+		Action:       action,
+		Entity:       "order",
+		EntityID:     &ret.OrderID,
+		Metadata:     metadata,
+	})
+}
+
+// refund estorna o valor no provider que cobrou o pedido. Qualquer falha
+// deixa a devolução em reembolso manual — o estoque já voltou e o dono
+// resolve o dinheiro por fora.
+func (uc *ReturnOrder) refund(ctx context.Context, ret *models.OrderReturn, payment *models.Payment) {
+	var gw domainPayment.TransparentGateway
+	if payment.Provider != nil && *payment.Provider != "" && uc.gateways != nil {
+		resolved, err := uc.gateways.GatewayForProvider(ctx, payment.BarbershopID, *payment.Provider)
+		if err != nil {
+			log.Printf("[ORDER_RETURN] gateway error barbershop=%d payment=%d: %v", payment.BarbershopID, payment.ID, err)
+			return
+		}
+		gw = resolved
+	}
+
+	_, err := uc.refunds.Execute(ctx, ucPayment.RefundPaymentInput{
+		BarbershopID: payment.BarbershopID,
+		PaymentID:    payment.ID,
+		AmountCents:  ret.AmountCents,
+		Reason:       fmt.Sprintf("Pedido #%d: %s", ret.OrderID, ret.Reason),
+	}, gw)
+	if err != nil {
+		log.Printf("[ORDER_RETURN] refund failed order=%d payment=%d: %v", ret.OrderID, payment.ID, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	if err := uc.orderRepo.UpdateReturnRefund(ctx, ret.ID, models.OrderRefundRefunded, &now); err != nil {
+		// O estorno já saiu no provider; a devolução só fica com o status antigo.
+		log.Printf("[ORDER_RETURN] failed to mark return=%d refunded: %v", ret.ID, err)
+		return
+	}
+	ret.RefundStatus = models.OrderRefundRefunded
+	ret.RefundedAt = &now
+}
+
+func normalizeReturnReason(raw string) (string, error) {
+	reason := strings.TrimSpace(raw)
+	if reason == "" {
+		return "", apperr.ErrBusiness("return_reason_required")
+	}
+	if utf8.RuneCountInString(reason) > maxReturnReasonLength {
+		return "", apperr.ErrBusiness("return_reason_too_long")
+	}
+	return reason, nil
+}
+
+// planReturnLines escolhe as linhas devolvidas: tudo o que resta no
+// cancelamento, ou as quantidades pedidas por produto na devolução parcial.
+func planReturnLines(order *orderDomain.Order, input ReturnOrderInput) ([]orderDomain.ReturnLine, error) {
+	if input.Cancel {
+		lines := order.PlanCancellation()
+		if len(lines) == 0 {
+			return nil, orderDomain.ErrNothingToReturn
+		}
+		return lines, nil
+	}
+
+	requested := make(map[uint]int, len(input.Items))
+	for _, it := range input.Items {
+		if it.Quantity <= 0 {
+			return nil, orderDomain.ErrInvalidQuantity
+		}
+		requested[it.ProductID] += it.Quantity
+	}
+	return order.PlanReturn(requested)
+}
+
+func newOrderReturn(
+	order *orderDomain.Order,
+	input ReturnOrderInput,
+	reason string,
+	lines []orderDomain.ReturnLine,
+	amount int64,
+) *models.OrderReturn {
+	kind := models.OrderReturnKindReturn
+	if input.Cancel {
+		kind = models.OrderReturnKindCancellation
+	}
+
+	refundStatus := models.OrderRefundNone
+	if amount > 0 {
+		refundStatus = models.OrderRefundManualPending
+	}
+
+	items := make([]models.OrderReturnItem, 0, len(lines))
+	for _, l := range lines {
+		items = append(items, models.OrderReturnItem{
+			OrderItemID: l.OrderItemID,
+			ProductID:   l.ProductID,
+			Quantity:    l.Quantity,
+			AmountCents: l.AmountCents,
+		})
+	}
+
+	return &models.OrderReturn{
+		BarbershopID: order.BarbershopID,
+		OrderID:      order.ID,
+		UserID:       input.UserID,
+		Kind:         kind,
+		Reason:       reason,
+		AmountCents:  amount,
+		RefundStatus: refundStatus,
+		Items:        items,
+	}
+}
+
+func markReturned(order *orderDomain.Order, l orderDomain.ReturnLine) {
+	for i := range order.Items {
+		if order.Items[i].ID == l.OrderItemID {
+			order.Items[i].ReturnedQuantity += l.Quantity
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package order
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// pedido: 2x pomada (R$ 30) em duas linhas + 1x shampoo (R$ 50), desconto R$ 10.
+func returnTestOrder() *orderDomain.Order {
+	return &orderDomain.Order{
+		ID:             9,
+		BarbershopID:   1,
+		Status:         orderDomain.OrderStatusPaid,
+		SubtotalAmount: 11000,
+		DiscountAmount: 1000,
+		TotalAmount:    10000,
+		Items: []orderDomain.OrderItem{
+			{ID: 1, ProductID: 10, Quantity: 1, UnitPrice: 3000, LineTotal: 3000},
+			{ID: 2, ProductID: 20, Quantity: 1, UnitPrice: 5000, LineTotal: 5000},
+			{ID: 3, ProductID: 10, Quantity: 1, UnitPrice: 3000, LineTotal: 3000},
+		},
+	}
+}
+
+func TestPlanReturnLines(t *testing.T) {
+	t.Run("cancelamento devolve o que resta", func(t *testing.T) {
+		order := returnTestOrder()
+		order.Items[0].ReturnedQuantity = 1
+
+		lines, err := planReturnLines(order, ReturnOrderInput{Cancel: true})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if len(lines) != 2 || lines[0].OrderItemID != 2 || lines[1].OrderItemID != 3 {
+			t.Errorf("linhas inesperadas: %+v", lines)
+		}
+	})
+
+	t.Run("devolução distribui a quantidade entre linhas do mesmo produto", func(t *testing.T) {
+		lines, err := planReturnLines(returnTestOrder(), ReturnOrderInput{
+			Items: []ReturnOrderItemInput{{ProductID: 10, Quantity: 1}, {ProductID: 10, Quantity: 1}},
+		})
+		if err != nil {
+			t.Fatalf("inesperado erro: %v", err)
+		}
+		if len(lines) != 2 || lines[0].OrderItemID != 1 || lines[1].OrderItemID != 3 {
+			t.Errorf("linhas inesperadas: %+v", lines)
+		}
+	})
+
+	cases := []struct {
+		name  string
+		items []ReturnOrderItemInput
+		want  error
+	}{
+		{"sem itens", nil, orderDomain.ErrNothingToReturn},
+		{"quantidade zero", []ReturnOrderItemInput{{ProductID: 10}}, orderDomain.ErrInvalidQuantity},
+		{"produto fora do pedido", []ReturnOrderItemInput{{ProductID: 99, Quantity: 1}}, orderDomain.ErrProductNotInOrder},
+		{"acima do vendido", []ReturnOrderItemInput{{ProductID: 20, Quantity: 2}}, orderDomain.ErrReturnExceedsQuantity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := planReturnLines(returnTestOrder(), ReturnOrderInput{Items: tc.items})
+			if !errors.Is(err, tc.want) {
+				t.Errorf("esperado %v, obtido %v", tc.want, err)
+			}
+		})
+	}
+
+	t.Run("cancelamento de pedido já devolvido", func(t *testing.T) {
+		order := returnTestOrder()
+		for i := range order.Items {
+			order.Items[i].ReturnedQuantity = order.Items[i].Quantity
+		}
+		if _, err := planReturnLines(order, ReturnOrderInput{Cancel: true}); !errors.Is(err, orderDomain.ErrNothingToReturn) {
+			t.Errorf("esperado ErrNothingToReturn, obtido %v", err)
+		}
+	})
+}
+
+func TestRefundAmount_CappedByOrderTotal(t *testing.T) {
+	order := returnTestOrder()
+
+	first, _ := planReturnLines(order, ReturnOrderInput{Items: []ReturnOrderItemInput{{ProductID: 20, Quantity: 1}}})
+	if got := order.RefundAmount(first, 0); got != 5000 {
+		t.Errorf("devolução parcial: esperado 5000, obtido %d", got)
+	}
+
+	// O restante do pedido vale R$ 60 em itens, mas só R$ 50 foram pagos além
+	// do que já voltou: o desconto sai da última devolução.
+	order.Items[1].ReturnedQuantity = 1
+	rest := order.PlanCancellation()
+	if got := order.RefundAmount(rest, 5000); got != 5000 {
+		t.Errorf("cancelamento após devolução: esperado 5000, obtido %d", got)
+	}
+}
+
+func TestNewOrderReturn_RefundStatus(t *testing.T) {
+	order := returnTestOrder()
+	lines := order.PlanCancellation()
+
+	paid := newOrderReturn(order, ReturnOrderInput{Cancel: true}, "Desistência", lines, 10000)
+	if paid.Kind != models.OrderReturnKindCancellation || paid.RefundStatus != models.OrderRefundManualPending {
+		t.Errorf("devolução com valor deveria aguardar reembolso: %+v", paid)
+	}
+	if len(paid.Items) != 3 {
+		t.Errorf("esperado 3 itens, obtido %d", len(paid.Items))
+	}
+
+	free := newOrderReturn(order, ReturnOrderInput{}, "Brinde", lines, 0)
+	if free.Kind != models.OrderReturnKindReturn || free.RefundStatus != models.OrderRefundNone {
+		t.Errorf("devolução sem valor não tem reembolso: %+v", free)
+	}
+}
+
+func TestNormalizeReturnReason(t *testing.T) {
+	if got, err := normalizeReturnReason("  Produto com defeito "); err != nil || got != "Produto com defeito" {
+		t.Errorf("motivo inesperado: %q, %v", got, err)
+	}
+	if _, err := normalizeReturnReason("   "); !apperr.IsBusiness(err, "return_reason_required") {
+		t.Errorf("esperado return_reason_required, obtido %v", err)
+	}
+	if _, err := normalizeReturnReason(strings.Repeat("á", 256)); !apperr.IsBusiness(err, "return_reason_too_long") {
+		t.Errorf("esperado return_reason_too_long, obtido %v", err)
+	}
+}
@@ -92,7 +92,38 @@ func (uc *CreatePaymentForAppointment) Execute(
 		return nil, domain.ErrInvalidAmount()
 	}
 
+	// Catálogo por barbeiro: cobra o preço do barbeiro do agendamento.
+	if appointment.BarberID != nil {
+		override, err := uc.appointmentRepo.GetBarberServiceOverride(ctx, barbershopID, *appointment.BarberID, product.ID)
+		if err != nil {
+			return nil, err
+		}
+		product = override.ApplyTo(product)
+	}
+
 	amountCents := product.Price
+
+	// Vários serviços: cobra a soma dos serviços não cobertos pela assinatura
+	// (snapshot do booking, já no preço do barbeiro).
+	lines := appointment.Services
+	if len(lines) == 0 {
+		lines, err = uc.appointmentRepo.ListAppointmentServices(ctx, appointment.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(lines) > 1 {
+		amountCents = 0
+		for _, line := range lines {
+			if line.CoverageStatus != models.CoverageStatusCovered {
+				amountCents += line.PriceCents
+			}
+		}
+	}
+
+	// Cupom aplicado no booking.
+	amountCents -= appointment.DiscountCents
+
 	if amountCents < 100 {
 		return nil, domain.ErrInvalidAmount()
 	}
@@ -16,6 +16,7 @@ import (
 	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
 )
 
 const mpPayPrefix = "mp_pay:"
@@ -32,6 +33,7 @@ type CreateTransparentPayment struct {
 	apptNotifier domainNotification.AppointmentNotifier
 	ticketRepo   domainTicket.Repository
 	appURL       string
+	loyalty      *ucLoyalty.Ledger
 }
 
 func NewCreateTransparentPayment(
@@ -56,6 +58,13 @@ func NewCreateTransparentPayment(
 	}
 }
 
+// WithLoyalty credita os pontos de fidelidade do pedido pago junto com o
+// agendamento quando o cartão é aprovado na hora.
+func (uc *CreateTransparentPayment) WithLoyalty(l *ucLoyalty.Ledger) *CreateTransparentPayment {
+	uc.loyalty = l
+	return uc
+}
+
 // TransparentPaymentInput agrupa os dados enviados pelo frontend.
 type TransparentPaymentInput struct {
 	BarbershopID    uint
@@ -264,6 +273,7 @@ func (uc *CreateTransparentPayment) Execute(
 	// ==================================================
 	// 7) Se aprovado imediatamente (cartão), marcar como pago
 	// ==================================================
+	var paidOrder *models.Order
 	if result.Status == "approved" {
 		now := time.Now().UTC()
 		payment.Status = models.PaymentStatus(domain.StatusPaid)
@@ -301,7 +311,7 @@ func (uc *CreateTransparentPayment) Execute(
 					return nil, nil, fmt.Errorf("failed to list order items: %w", err)
 				}
 				for _, it := range items {
-					if err := tx.DecreaseProductStock(ctx, input.BarbershopID, it.ProductID, it.Quantity); err != nil {
+					if err := tx.DecreaseProductStock(ctx, input.BarbershopID, it.ProductID, it.Quantity, order.ID); err != nil {
 						return nil, nil, fmt.Errorf("failed to decrease stock: %w", err)
 					}
 				}
@@ -309,6 +319,7 @@ func (uc *CreateTransparentPayment) Execute(
 				if err := tx.UpdateOrderTx(ctx, order); err != nil {
 					return nil, nil, fmt.Errorf("failed to update order: %w", err)
 				}
+				paidOrder = order
 			}
 		}
 	} else {
@@ -317,11 +328,10 @@ func (uc *CreateTransparentPayment) Execute(
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, nil, fmt.Errorf("commit failed: %w", err)
-	}
+	// Auditoria e confirmação ao cliente entram na mesma transação pelo outbox.
+	octx := tx.Context(ctx)
 
-	uc.audit.Dispatch(audit.Event{
+	if err := uc.audit.DispatchContext(octx, audit.Event{
 		BarbershopID: input.BarbershopID,
 		Action:       "payment_transparent_created",
 		Entity:       "payment",
@@ -331,13 +341,21 @@ func (uc *CreateTransparentPayment) Execute(
 			"payment_method_id": input.PaymentMethodID,
 			"status":            result.Status,
 		},
-	})
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to enqueue audit: %w", err)
+	}
 
 	// Send confirmation email only when payment is immediately approved (card).
 	if result.Status == "approved" && payment.AppointmentID != nil &&
 		uc.apptNotifier != nil && uc.db != nil {
-		sendAppointmentConfirmedNotification(ctx, uc.db, uc.apptNotifier, uc.ticketRepo, uc.appURL, *payment.AppointmentID)
+		sendAppointmentConfirmedNotification(octx, uc.db, uc.apptNotifier, uc.ticketRepo, uc.appURL, *payment.AppointmentID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit failed: %w", err)
 	}
 
+	earnOrderPoints(ctx, uc.loyalty, paidOrder)
+
 	return payment, result, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
 	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
 
@@ -15,6 +16,8 @@ type ExpirePayments struct {
 	paymentRepo     domainPayment.Repository
 	appointmentRepo domainAppointment.Repository
 	audit           *audit.Dispatcher
+	waitlist        domainWaitlist.SlotListener
+	calendar        domainAppointment.CalendarSync
 }
 
 func NewExpirePayments(
@@ -29,6 +32,20 @@ func NewExpirePayments(
 	}
 }
 
+// WithWaitlist oferece à lista de espera os horários liberados por
+// agendamentos cancelados por falta de pagamento.
+func (uc *ExpirePayments) WithWaitlist(l domainWaitlist.SlotListener) *ExpirePayments {
+	uc.waitlist = l
+	return uc
+}
+
+// WithCalendarSync remove do Google Calendar os eventos dos agendamentos
+// cancelados por falta de pagamento.
+func (uc *ExpirePayments) WithCalendarSync(c domainAppointment.CalendarSync) *ExpirePayments {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *ExpirePayments) Execute(
 	ctx context.Context,
 	now time.Time,
@@ -52,6 +69,9 @@ func (uc *ExpirePayments) Execute(
 	}
 	defer tx.Rollback()
 
+	// Auditoria e agenda entram na mesma transação pelo outbox.
+	octx := tx.Context(ctx)
+
 	payments, err := tx.ListExpiredPendingForUpdate(ctx, barbershopID, now)
 	if err != nil {
 		return fmt.Errorf("failed to lock expired payments: %w", err)
@@ -61,6 +81,10 @@ func (uc *ExpirePayments) Execute(
 		return tx.Commit()
 	}
 
+	// Horários liberados: as ofertas saem pelo outbox, depois do commit.
+	var freed []domainWaitlist.FreedSlot
+	var cancelledIDs []uint
+
 	for _, p := range payments {
 		currentStatus := domainPayment.Status(p.Status)
 
@@ -85,12 +109,26 @@ func (uc *ExpirePayments) Execute(
 					if err := tx.UpdateAppointmentTx(ctx, ap); err != nil {
 						return fmt.Errorf("failed to update appointment: %w", err)
 					}
-					uc.audit.Dispatch(audit.Event{
+					if err := tx.VoidAppointmentCouponsTx(ctx, ap.ID); err != nil {
+						return fmt.Errorf("failed to void appointment coupons: %w", err)
+					}
+					if err := uc.audit.DispatchContext(octx, audit.Event{
 						BarbershopID: p.BarbershopID,
 						Action:       "appointment_cancelled_by_payment_expiration",
 						Entity:       "appointment",
 						EntityID:     &ap.ID,
-					})
+					}); err != nil {
+						return fmt.Errorf("failed to enqueue audit: %w", err)
+					}
+					cancelledIDs = append(cancelledIDs, ap.ID)
+					if ap.BarberID != nil {
+						freed = append(freed, domainWaitlist.FreedSlot{
+							BarbershopID: barbershopID,
+							BarberID:     *ap.BarberID,
+							StartTime:    ap.StartTime,
+							EndTime:      ap.EndTime,
+						})
+					}
 				}
 			}
 		}
@@ -107,22 +145,43 @@ func (uc *ExpirePayments) Execute(
 				if err := tx.UpdateOrderTx(ctx, order); err != nil {
 					return fmt.Errorf("failed to update order: %w", err)
 				}
+				if err := tx.VoidOrderCouponsTx(ctx, order.ID); err != nil {
+					return fmt.Errorf("failed to void order coupons: %w", err)
+				}
 
-				uc.audit.Dispatch(audit.Event{
+				if err := uc.audit.DispatchContext(octx, audit.Event{
 					BarbershopID: p.BarbershopID,
 					Action:       "order_cancelled_by_payment_expiration",
 					Entity:       "order",
 					EntityID:     &order.ID,
-				})
+				}); err != nil {
+					return fmt.Errorf("failed to enqueue audit: %w", err)
+				}
 			}
 		}
 
-		uc.audit.Dispatch(audit.Event{
+		if err := uc.audit.DispatchContext(octx, audit.Event{
 			BarbershopID: p.BarbershopID,
 			Action:       "payment_expired",
 			Entity:       "payment",
 			EntityID:     &p.ID,
-		})
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
+	}
+
+	if uc.calendar != nil {
+		for _, id := range cancelledIDs {
+			uc.calendar.AppointmentChanged(octx, barbershopID, id)
+		}
+	}
+
+	if uc.waitlist != nil {
+		for _, slot := range freed {
+			if err := uc.waitlist.SlotFreed(octx, slot); err != nil {
+				return fmt.Errorf("failed to enqueue slot_freed: %w", err)
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
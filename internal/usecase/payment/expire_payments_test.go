@@ -0,0 +1,109 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// TestExpirePayments_VoidsCoupons: o agendamento e o pedido cancelados por
+// falta de pagamento devolvem o uso do cupom na mesma transação.
+func TestExpirePayments_VoidsCoupons(t *testing.T) {
+	apptID, orderID := uint(50), uint(60)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	txRepo := &mockTxRepo{
+		expired: []*models.Payment{
+			{ID: 1, BarbershopID: 1, Status: "pending", AppointmentID: &apptID},
+			{ID: 2, BarbershopID: 1, Status: "pending", OrderID: &orderID},
+		},
+		appointment: &models.Appointment{
+			ID:        apptID,
+			Status:    models.AppointmentStatusAwaitingPayment,
+			StartTime: now.Add(48 * time.Hour),
+			EndTime:   now.Add(49 * time.Hour),
+		},
+		order: &models.Order{ID: orderID, Status: models.OrderStatusPending},
+	}
+	uc := NewExpirePayments(&mockPaymentRepo{txRepo: txRepo}, nil, newTestDispatcher(t))
+
+	if err := uc.Execute(context.Background(), now, 1); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(txRepo.voidedAppointments) != 1 || txRepo.voidedAppointments[0] != apptID {
+		t.Errorf("esperado cupom do agendamento %d anulado, obtido %v", apptID, txRepo.voidedAppointments)
+	}
+	if len(txRepo.voidedOrders) != 1 || txRepo.voidedOrders[0] != orderID {
+		t.Errorf("esperado cupom do pedido %d anulado, obtido %v", orderID, txRepo.voidedOrders)
+	}
+	if txRepo.committedCount != 1 {
+		t.Errorf("esperado 1 commit, obtido %d", txRepo.committedCount)
+	}
+}
+
+// TestExpirePayments_PaidAppointmentKeepsCoupon: agendamento que já saiu de
+// awaiting_payment não é cancelado e mantém o cupom.
+func TestExpirePayments_PaidAppointmentKeepsCoupon(t *testing.T) {
+	apptID := uint(51)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	txRepo := &mockTxRepo{
+		expired: []*models.Payment{
+			{ID: 3, BarbershopID: 1, Status: "pending", AppointmentID: &apptID},
+		},
+		appointment: &models.Appointment{ID: apptID, Status: models.AppointmentStatusScheduled},
+	}
+	uc := NewExpirePayments(&mockPaymentRepo{txRepo: txRepo}, nil, newTestDispatcher(t))
+
+	if err := uc.Execute(context.Background(), now, 1); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(txRepo.voidedAppointments) != 0 {
+		t.Errorf("cupom não deveria ser anulado, obtido %v", txRepo.voidedAppointments)
+	}
+}
+
+type failingSlotListener struct{ calls int }
+
+func (f *failingSlotListener) SlotFreed(context.Context, domainWaitlist.FreedSlot) error {
+	f.calls++
+	return errors.New("outbox indisponível")
+}
+
+// TestExpirePayments_SlotFreedFailureAbortsCommit: sem o evento da lista de
+// espera gravado, o ciclo não confirma a expiração — o próximo tenta de novo.
+func TestExpirePayments_SlotFreedFailureAbortsCommit(t *testing.T) {
+	apptID, barberID := uint(52), uint(7)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	txRepo := &mockTxRepo{
+		expired: []*models.Payment{
+			{ID: 4, BarbershopID: 1, Status: "pending", AppointmentID: &apptID},
+		},
+		appointment: &models.Appointment{
+			ID:        apptID,
+			BarberID:  &barberID,
+			Status:    models.AppointmentStatusAwaitingPayment,
+			StartTime: now.Add(48 * time.Hour),
+			EndTime:   now.Add(49 * time.Hour),
+		},
+	}
+	listener := &failingSlotListener{}
+	uc := NewExpirePayments(&mockPaymentRepo{txRepo: txRepo}, nil, newTestDispatcher(t)).
+		WithWaitlist(listener)
+
+	if err := uc.Execute(context.Background(), now, 1); err == nil {
+		t.Fatal("esperado erro quando o horário liberado não entra no outbox")
+	}
+	if listener.calls != 1 {
+		t.Errorf("esperado 1 aviso à lista de espera, obtido %d", listener.calls)
+	}
+	if txRepo.committedCount != 0 {
+		t.Errorf("esperado nenhum commit, obtido %d", txRepo.committedCount)
+	}
+}
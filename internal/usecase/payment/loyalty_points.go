@@ -0,0 +1,29 @@
+package payment
+
+import (
+	"context"
+	"log"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
+)
+
+// earnOrderPoints credita os pontos de fidelidade de um pedido recém-pago.
+// Roda depois do commit e é best-effort: o crédito é idempotente por pedido,
+// e uma falha aqui não desfaz a confirmação do pagamento.
+func earnOrderPoints(ctx context.Context, ledger *ucLoyalty.Ledger, order *models.Order) {
+	if ledger == nil || order == nil || order.ClientID == nil {
+		return
+	}
+
+	orderID := order.ID
+	err := ledger.Earn(ctx, ucLoyalty.EarnInput{
+		BarbershopID: order.BarbershopID,
+		ClientID:     *order.ClientID,
+		OrderID:      &orderID,
+		AmountCents:  order.TotalAmount,
+	})
+	if err != nil {
+		log.Printf("[LOYALTY] failed to earn points for order %d: %v", order.ID, err)
+	}
+}
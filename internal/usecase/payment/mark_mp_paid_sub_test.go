@@ -131,7 +131,10 @@ func (r *mockPaymentRepo) GetByTxID(_ context.Context, _ uint, _ string) (*model
 	return nil, nil
 }
 func (r *mockPaymentRepo) ListExpiredPending(_ context.Context, _ uint, _ time.Time) ([]*models.Payment, error) {
-	return nil, nil
+	if r.txRepo == nil {
+		return nil, nil
+	}
+	return r.txRepo.expired, nil
 }
 func (r *mockPaymentRepo) ListForBarbershop(_ context.Context, _ uint, _ domainPayment.PaymentListFilter) ([]models.Payment, error) {
 	return nil, nil
@@ -155,18 +158,31 @@ type mockTxRepo struct {
 	sub  *models.Subscription
 	plan *models.Plan
 
+	// Pacote pré-pago retornado por GetClientPackageForUpdate
+	clientPackage *models.ClientPackage
+
 	// Appointment e order retornados para os caminhos de agendamento/pedido
 	appointment *models.Appointment
 	order       *models.Order
 
+	// Pagamentos vencidos retornados para ExpirePayments
+	expired []*models.Payment
+
 	// Registro de chamadas
 	markedAsPaid        bool
 	activatedSubID      uint
 	activatedPeriodStart time.Time
 	activatedPeriodEnd   time.Time
+	renewedSubID         uint
+	renewedPaidAt        time.Time
+	activatedPackageID   uint
+	packagePurchasedAt   time.Time
 	committedCount      int
 	rolledBackCount     int
 	registeredEvent     bool
+	refunds             []*models.PaymentRefund
+	voidedAppointments  []uint
+	voidedOrders        []uint
 
 	// Controles configuráveis por teste
 	hasProcessedEvent bool
@@ -179,6 +195,9 @@ func (r *mockTxRepo) GetByTxIDForUpdate(_ context.Context, _ uint, _ string) (*m
 func (r *mockTxRepo) GetByAppointmentIDForUpdate(_ context.Context, _, _ uint) (*models.Payment, error) {
 	return r.payment, nil
 }
+func (r *mockTxRepo) GetByIDForUpdate(_ context.Context, _, _ uint) (*models.Payment, error) {
+	return r.payment, nil
+}
 func (r *mockTxRepo) GetAppointmentForUpdate(_ context.Context, _, _ uint) (*models.Appointment, error) {
 	return r.appointment, nil
 }
@@ -186,7 +205,19 @@ func (r *mockTxRepo) GetOrderForUpdate(_ context.Context, _, _ uint) (*models.Or
 	return r.order, nil
 }
 func (r *mockTxRepo) ListExpiredPendingForUpdate(_ context.Context, _ uint, _ time.Time) ([]*models.Payment, error) {
-	return nil, nil
+	return r.expired, nil
+}
+func (r *mockTxRepo) VoidAppointmentCouponsTx(_ context.Context, appointmentID uint) error {
+	r.mu.Lock()
+	r.voidedAppointments = append(r.voidedAppointments, appointmentID)
+	r.mu.Unlock()
+	return nil
+}
+func (r *mockTxRepo) VoidOrderCouponsTx(_ context.Context, orderID uint) error {
+	r.mu.Lock()
+	r.voidedOrders = append(r.voidedOrders, orderID)
+	r.mu.Unlock()
+	return nil
 }
 func (r *mockTxRepo) Create(_ context.Context, _ *models.Payment) error { return nil }
 func (r *mockTxRepo) MarkAsPaid(_ context.Context, _ uint, _ *models.Payment) error {
@@ -199,6 +230,12 @@ func (r *mockTxRepo) MarkAsExpired(_ context.Context, _ uint, _ *models.Payment)
 func (r *mockTxRepo) UpdatePaymentTx(_ context.Context, _ uint, _ *models.Payment) error {
 	return nil
 }
+func (r *mockTxRepo) ApplyRefundTx(_ context.Context, _ *models.Payment, refund *models.PaymentRefund) error {
+	r.mu.Lock()
+	r.refunds = append(r.refunds, refund)
+	r.mu.Unlock()
+	return nil
+}
 func (r *mockTxRepo) UpdateAppointmentTx(_ context.Context, _ *models.Appointment) error {
 	return nil
 }
@@ -218,7 +255,7 @@ func (r *mockTxRepo) GetByOrderID(_ context.Context, _, _ uint) (*models.Payment
 func (r *mockTxRepo) ListOrderItems(_ context.Context, _, _ uint) ([]models.OrderItem, error) {
 	return nil, nil
 }
-func (r *mockTxRepo) DecreaseProductStock(_ context.Context, _, _ uint, _ int) error { return nil }
+func (r *mockTxRepo) DecreaseProductStock(_ context.Context, _, _ uint, _ int, _ uint) error { return nil }
 func (r *mockTxRepo) GetSubscriptionForUpdate(_ context.Context, _ uint) (*models.Subscription, error) {
 	return r.sub, nil
 }
@@ -233,6 +270,24 @@ func (r *mockTxRepo) ActivateSubscriptionTx(_ context.Context, id uint, start, e
 	r.mu.Unlock()
 	return nil
 }
+func (r *mockTxRepo) RenewSubscriptionTx(_ context.Context, sub *models.Subscription, _ int, paidAt time.Time) error {
+	r.mu.Lock()
+	r.renewedSubID = sub.ID
+	r.renewedPaidAt = paidAt
+	r.mu.Unlock()
+	return nil
+}
+func (r *mockTxRepo) GetClientPackageForUpdate(_ context.Context, _ uint) (*models.ClientPackage, error) {
+	return r.clientPackage, nil
+}
+func (r *mockTxRepo) ActivateClientPackageTx(_ context.Context, id uint, purchasedAt time.Time) error {
+	r.mu.Lock()
+	r.activatedPackageID = id
+	r.packagePurchasedAt = purchasedAt
+	r.mu.Unlock()
+	return nil
+}
+func (r *mockTxRepo) Context(ctx context.Context) context.Context { return ctx }
 func (r *mockTxRepo) Commit() error {
 	r.mu.Lock()
 	r.committedCount++
@@ -644,3 +699,128 @@ var _ domainTicket.Repository = (*noopTicketRepo)(nil)
 
 // errDeadCodeElimination evita "declared and not used" em imports.
 var _ = errors.New
+
+// TestMarkMPPaid_ClientPackage_ActivatesOnConfirmation: payment de pacote
+// pré-pago ativa a compra pending_payment na mesma tx, sem tocar assinatura.
+func TestMarkMPPaid_ClientPackage_ActivatesOnConfirmation(t *testing.T) {
+	const (
+		paymentID = uint(3)
+		cpID      = uint(40)
+		txid      = "pkg_pending:40:1234567890"
+	)
+
+	pmt := &models.Payment{
+		ID:              paymentID,
+		BarbershopID:    1,
+		ClientPackageID: uint64p(cpID),
+		Status:          "pending",
+		TxID:            strp(txid),
+	}
+	cp := &models.ClientPackage{ID: cpID, Status: models.ClientPackagePendingPayment}
+
+	txRepo := &mockTxRepo{payment: pmt, clientPackage: cp}
+	repo := &mockPaymentRepo{payment: pmt, txRepo: txRepo}
+
+	uc := newUC(t, repo, &noopIdemStore{})
+
+	before := time.Now().UTC().Truncate(time.Second)
+	if err := uc.Execute(context.Background(), "3", "QRC_PKG"); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	if !txRepo.markedAsPaid {
+		t.Error("esperado: MarkAsPaid chamado")
+	}
+	if txRepo.activatedPackageID != cpID {
+		t.Errorf("ActivateClientPackageTx id = %d, want %d", txRepo.activatedPackageID, cpID)
+	}
+	if txRepo.packagePurchasedAt.Before(before) {
+		t.Errorf("purchased_at antes da confirmação: %v", txRepo.packagePurchasedAt)
+	}
+	if txRepo.activatedSubID != 0 {
+		t.Errorf("pacote: ActivateSubscriptionTx não deve ser chamado, got subID=%d", txRepo.activatedSubID)
+	}
+}
+
+// TestMarkMPPaid_ClientPackage_AlreadyActive_NoReactivation: compra que já
+// saiu de pending_payment não é reativada (validade não recomeça).
+func TestMarkMPPaid_ClientPackage_AlreadyActive_NoReactivation(t *testing.T) {
+	pmt := &models.Payment{
+		ID:              4,
+		BarbershopID:    1,
+		ClientPackageID: uint64p(41),
+		Status:          "pending",
+		TxID:            strp("pkg_pending:41:1234567890"),
+	}
+	cp := &models.ClientPackage{ID: 41, Status: models.ClientPackageActive}
+
+	txRepo := &mockTxRepo{payment: pmt, clientPackage: cp}
+	repo := &mockPaymentRepo{payment: pmt, txRepo: txRepo}
+
+	uc := newUC(t, repo, &noopIdemStore{})
+	if err := uc.Execute(context.Background(), "4", "QRC_PKG2"); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+
+	if txRepo.activatedPackageID != 0 {
+		t.Errorf("ActivateClientPackageTx não deve ser chamado para pacote já active, got id=%d", txRepo.activatedPackageID)
+	}
+}
+
+type recordingRenewalListener struct{ renewed []uint }
+
+func (l *recordingRenewalListener) SubscriptionRenewed(_ context.Context, subscriptionID uint) {
+	l.renewed = append(l.renewed, subscriptionID)
+}
+
+// TestMarkMPPaid_SubscriptionRenewal verifica que a cobrança de renovação
+// (SubscriptionRenewal) renova a assinatura ativa ou em carência e avisa o
+// listener, e que um pagamento comum de assinatura já ativa não renova nada.
+func TestMarkMPPaid_SubscriptionRenewal(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    string
+		renewal   bool
+		wantRenew bool
+	}{
+		{"ativa", "active", true, true},
+		{"em carência", "past_due", true, true},
+		{"expirada", "expired", true, false},
+		{"compra comum", "active", false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmt := pendingPayment(5, 50, "sub_renewal:50:111")
+			pmt.SubscriptionRenewal = tc.renewal
+			sub := pendingSubscription(50, 8)
+			sub.Status = tc.status
+
+			txRepo := &mockTxRepo{payment: pmt, sub: sub, plan: planWith(8, 30)}
+			repo := &mockPaymentRepo{payment: pmt, txRepo: txRepo}
+			listener := &recordingRenewalListener{}
+			uc := newUC(t, repo, &noopIdemStore{}).WithRenewalListener(listener)
+
+			if err := uc.Execute(context.Background(), "5", "CHAR_RENEW"); err != nil {
+				t.Fatalf("Execute error: %v", err)
+			}
+			if !txRepo.markedAsPaid {
+				t.Error("payment deve ser marcado como paid")
+			}
+			if txRepo.activatedSubID != 0 {
+				t.Errorf("renovação não deve passar por ActivateSubscriptionTx, got %d", txRepo.activatedSubID)
+			}
+
+			renewed := txRepo.renewedSubID == 50
+			if renewed != tc.wantRenew {
+				t.Errorf("RenewSubscriptionTx chamado = %v, want %v", renewed, tc.wantRenew)
+			}
+			if tc.wantRenew && (len(listener.renewed) != 1 || listener.renewed[0] != 50) {
+				t.Errorf("listener = %v, want [50]", listener.renewed)
+			}
+			if !tc.wantRenew && len(listener.renewed) != 0 {
+				t.Errorf("listener não deve ser chamado, got %v", listener.renewed)
+			}
+		})
+	}
+}
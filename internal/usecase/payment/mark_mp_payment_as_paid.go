@@ -13,9 +13,11 @@ import (
 	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 	domainOrder "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domainSubscription "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
 	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
 	"github.com/BruksfildServices01/barber-scheduler/internal/idempotency"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucLoyalty "github.com/BruksfildServices01/barber-scheduler/internal/usecase/loyalty"
 )
 
 const mpPaidEvent = "mp_paid"
@@ -32,6 +34,8 @@ type MarkMPPaymentAsPaid struct {
 	apptNotifier domainNotification.AppointmentNotifier
 	ticketRepo   domainTicket.Repository
 	appURL       string
+	renewals     domainSubscription.RenewalListener
+	loyalty      *ucLoyalty.Ledger
 }
 
 func NewMarkMPPaymentAsPaid(
@@ -56,6 +60,19 @@ func NewMarkMPPaymentAsPaid(
 	}
 }
 
+// WithRenewalListener avisa o cliente quando uma cobrança de renovação de
+// assinatura é confirmada.
+func (uc *MarkMPPaymentAsPaid) WithRenewalListener(l domainSubscription.RenewalListener) *MarkMPPaymentAsPaid {
+	uc.renewals = l
+	return uc
+}
+
+// WithLoyalty credita os pontos de fidelidade dos pedidos confirmados.
+func (uc *MarkMPPaymentAsPaid) WithLoyalty(l *ucLoyalty.Ledger) *MarkMPPaymentAsPaid {
+	uc.loyalty = l
+	return uc
+}
+
 // Execute processa a confirmação de um pagamento MP.
 // externalReference é o campo external_reference da preferência = nosso payment ID.
 // mpPaymentID é o ID do pagamento gerado pelo Mercado Pago (para idempotência).
@@ -156,7 +173,10 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 
 	var ap *models.Appointment
 	var order *models.Order
+	var paidOrder *models.Order
 	var activatedSubID *uint
+	var renewedSubID *uint
+	var activatedPackageID *uint
 
 	// Subscription: ativa quando o pagamento cobre uma assinatura pending_payment
 	if payment.SubscriptionID != nil {
@@ -177,6 +197,33 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 				}
 				activatedSubID = &sub.ID
 			}
+		} else if sub != nil && payment.SubscriptionRenewal {
+			// Renovação automática: assinatura ativa ou em carência.
+			plan, err := tx.GetPlanByID(ctx, sub.PlanID)
+			if err != nil {
+				return fmt.Errorf("failed to load plan: %w", err)
+			}
+			if plan != nil && (sub.Status == "active" || sub.Status == "past_due") {
+				if err := tx.RenewSubscriptionTx(ctx, sub, plan.DurationDays, now); err != nil {
+					return fmt.Errorf("failed to renew subscription: %w", err)
+				}
+				renewedSubID = &sub.ID
+			}
+		}
+	}
+
+	// Pacote pré-pago: ativa a compra pending_payment; a validade conta a
+	// partir da confirmação do pagamento.
+	if payment.ClientPackageID != nil {
+		cp, err := tx.GetClientPackageForUpdate(ctx, *payment.ClientPackageID)
+		if err != nil {
+			return fmt.Errorf("failed to lock client package: %w", err)
+		}
+		if cp != nil && cp.Status == models.ClientPackagePendingPayment {
+			if err := tx.ActivateClientPackageTx(ctx, cp.ID, now); err != nil {
+				return fmt.Errorf("failed to activate client package: %w", err)
+			}
+			activatedPackageID = &cp.ID
 		}
 	}
 
@@ -209,7 +256,7 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 				return fmt.Errorf("failed to list order items: %w", err)
 			}
 			for _, it := range items {
-				if err := tx.DecreaseProductStock(ctx, barbershopID, it.ProductID, it.Quantity); err != nil {
+				if err := tx.DecreaseProductStock(ctx, barbershopID, it.ProductID, it.Quantity, order.ID); err != nil {
 					return fmt.Errorf("failed to decrease stock: %w", err)
 				}
 			}
@@ -217,18 +264,14 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 			if err := tx.UpdateOrderTx(ctx, order); err != nil {
 				return fmt.Errorf("failed to update order: %w", err)
 			}
+			paidOrder = order
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit failed: %w", err)
-	}
+	// Auditoria e confirmação ao cliente entram na mesma transação pelo outbox.
+	octx := tx.Context(ctx)
 
-	if err := uc.idem.Save(ctx, idemKey); err != nil {
-		return fmt.Errorf("failed to persist idempotency key: %w", err)
-	}
-
-	uc.audit.Dispatch(audit.Event{
+	if err := uc.audit.DispatchContext(octx, audit.Event{
 		BarbershopID: barbershopID,
 		Action:       "payment_mp_confirmed",
 		Entity:       "payment",
@@ -236,28 +279,34 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 		Metadata: map[string]any{
 			"mp_payment_id": mpPaymentID,
 		},
-	})
+	}); err != nil {
+		return fmt.Errorf("failed to enqueue audit: %w", err)
+	}
 
 	if ap != nil {
-		uc.audit.Dispatch(audit.Event{
+		if err := uc.audit.DispatchContext(octx, audit.Event{
 			BarbershopID: barbershopID,
 			Action:       "appointment_payment_confirmed",
 			Entity:       "appointment",
 			EntityID:     &ap.ID,
-		})
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
 	}
 
 	if order != nil {
-		uc.audit.Dispatch(audit.Event{
+		if err := uc.audit.DispatchContext(octx, audit.Event{
 			BarbershopID: barbershopID,
 			Action:       "order_payment_confirmed",
 			Entity:       "order",
 			EntityID:     &order.ID,
-		})
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
 	}
 
 	if activatedSubID != nil {
-		uc.audit.Dispatch(audit.Event{
+		if err := uc.audit.DispatchContext(octx, audit.Event{
 			BarbershopID: barbershopID,
 			Action:       "subscription_activated",
 			Entity:       "subscription",
@@ -265,12 +314,56 @@ func (uc *MarkMPPaymentAsPaid) Execute(
 			Metadata: map[string]any{
 				"via": "mp_webhook",
 			},
-		})
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
+	}
+
+	if renewedSubID != nil {
+		if err := uc.audit.DispatchContext(octx, audit.Event{
+			BarbershopID: barbershopID,
+			Action:       "subscription_renewed",
+			Entity:       "subscription",
+			EntityID:     renewedSubID,
+			Metadata: map[string]any{
+				"payment_id": payment.ID,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
+	}
+
+	if activatedPackageID != nil {
+		if err := uc.audit.DispatchContext(octx, audit.Event{
+			BarbershopID: barbershopID,
+			Action:       "client_package_activated",
+			Entity:       "client_package",
+			EntityID:     activatedPackageID,
+			Metadata: map[string]any{
+				"via": "mp_webhook",
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue audit: %w", err)
+		}
 	}
 
 	// Send appointment confirmation email after payment is confirmed.
 	if ap != nil && uc.apptNotifier != nil && uc.db != nil {
-		sendAppointmentConfirmedNotification(ctx, uc.db, uc.apptNotifier, uc.ticketRepo, uc.appURL, ap.ID)
+		sendAppointmentConfirmedNotification(octx, uc.db, uc.apptNotifier, uc.ticketRepo, uc.appURL, ap.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	if err := uc.idem.Save(ctx, idemKey); err != nil {
+		return fmt.Errorf("failed to persist idempotency key: %w", err)
+	}
+
+	earnOrderPoints(ctx, uc.loyalty, paidOrder)
+
+	if renewedSubID != nil && uc.renewals != nil {
+		uc.renewals.SubscriptionRenewed(ctx, *renewedSubID)
 	}
 
 	return nil
@@ -12,22 +12,25 @@ import (
 )
 
 type appointmentNotifyRow struct {
-	BarbershopID    uint      `gorm:"column:barbershop_id"`
-	ClientName      string    `gorm:"column:client_name"`
-	ClientEmail     string    `gorm:"column:client_email"`
-	ClientPhone     string    `gorm:"column:client_phone"`
-	BarbershopName  string    `gorm:"column:barbershop_name"`
-	BarbershopPhone string    `gorm:"column:barbershop_phone"`
-	BarbershopSlug  string    `gorm:"column:barbershop_slug"`
-	ServiceName     string    `gorm:"column:service_name"`
-	Timezone        string    `gorm:"column:timezone"`
-	StartTime       time.Time `gorm:"column:start_time"`
-	EndTime         time.Time `gorm:"column:end_time"`
+	BarbershopID      uint      `gorm:"column:barbershop_id"`
+	ClientName        string    `gorm:"column:client_name"`
+	ClientEmail       string    `gorm:"column:client_email"`
+	ClientPhone       string    `gorm:"column:client_phone"`
+	BarbershopName    string    `gorm:"column:barbershop_name"`
+	BarbershopPhone   string    `gorm:"column:barbershop_phone"`
+	BarbershopSlug    string    `gorm:"column:barbershop_slug"`
+	BarbershopAddress string    `gorm:"column:barbershop_address"`
+	ServiceName       string    `gorm:"column:service_name"`
+	Timezone          string    `gorm:"column:timezone"`
+	StartTime         time.Time `gorm:"column:start_time"`
+	EndTime           time.Time `gorm:"column:end_time"`
 }
 
 // sendAppointmentConfirmedNotification dispara a notificação de confirmação.
 // Suporta email e WhatsApp — o notifier ativo decide o canal.
 // Nunca propaga erros: falhas de notificação não devem afetar o fluxo de pagamento.
+// Com ctx ligado à transação do pagamento (TxRepository.Context), a
+// notificação entra no outbox no mesmo commit.
 func sendAppointmentConfirmedNotification(
 	ctx context.Context,
 	db *gorm.DB,
@@ -46,7 +49,12 @@ func sendAppointmentConfirmedNotification(
 			b.name  AS barbershop_name,
 			b.phone AS barbershop_phone,
 			b.slug  AS barbershop_slug,
-			bs.name AS service_name,
+			b.address AS barbershop_address,
+			COALESCE(
+				(SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+				 FROM appointment_services s WHERE s.appointment_id = a.id),
+				bs.name
+			)       AS service_name,
 			b.timezone,
 			a.start_time,
 			a.end_time
@@ -73,17 +81,20 @@ func sendAppointmentConfirmedNotification(
 	}
 
 	_ = apptNotifier.NotifyConfirmed(ctx, domainNotification.AppointmentConfirmedInput{
-		BarbershopID:    row.BarbershopID,
-		ClientName:      row.ClientName,
-		ClientEmail:     row.ClientEmail,
-		ClientPhone:     row.ClientPhone,
-		BarbershopName:  row.BarbershopName,
-		BarbershopPhone: row.BarbershopPhone,
-		BarbershopSlug:  row.BarbershopSlug,
-		ServiceName:     row.ServiceName,
-		StartTime:       row.StartTime,
-		EndTime:         row.EndTime,
-		Timezone:        row.Timezone,
-		TicketURL:       ticketURL,
+		BarbershopID:      row.BarbershopID,
+		AppointmentID:     appointmentID,
+		ClientName:        row.ClientName,
+		ClientEmail:       row.ClientEmail,
+		ClientPhone:       row.ClientPhone,
+		BarbershopName:    row.BarbershopName,
+		BarbershopPhone:   row.BarbershopPhone,
+		BarbershopSlug:    row.BarbershopSlug,
+		BarbershopAddress: row.BarbershopAddress,
+		ServiceName:       row.ServiceName,
+		StartTime:         row.StartTime,
+		EndTime:           row.EndTime,
+		Timezone:          row.Timezone,
+		TicketURL:         ticketURL,
+		Paid:              true,
 	})
 }
@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// refunder é implementado pelos gateways que suportam estorno
+// (mp.Gateway, mp.MockGateway, pagbank.Gateway).
+type refunder interface {
+	RefundPayment(ctx context.Context, input domain.RefundInput) (*domain.RefundResult, error)
+}
+
+// RefundPayment devolve ao cliente, total ou parcialmente, um pagamento já
+// recebido — estorno pelo provider que processou a cobrança.
+type RefundPayment struct {
+	repo    domain.Repository
+	gateway domain.TransparentGateway
+	audit   *audit.Dispatcher
+}
+
+func NewRefundPayment(
+	repo domain.Repository,
+	gateway domain.TransparentGateway,
+	audit *audit.Dispatcher,
+) *RefundPayment {
+	return &RefundPayment{
+		repo:    repo,
+		gateway: gateway,
+		audit:   audit,
+	}
+}
+
+type RefundPaymentInput struct {
+	BarbershopID uint
+	PaymentID    uint
+	AmountCents  int64 // 0 = estorno total do saldo restante
+	Reason       string
+}
+
+// Execute estorna o pagamento no provider e registra a devolução.
+//
+// O payment fica travado (FOR UPDATE) durante a chamada ao provider: dois
+// pedidos simultâneos não conseguem estornar o mesmo saldo, e o webhook de
+// estorno que o provider dispara em seguida encontra refunded_amount já
+// atualizado — não registra a devolução de novo.
+func (uc *RefundPayment) Execute(
+	ctx context.Context,
+	input RefundPaymentInput,
+	gatewayOverride ...domain.TransparentGateway,
+) (*models.Payment, error) {
+	gateway := uc.gateway
+	if len(gatewayOverride) > 0 && gatewayOverride[0] != nil {
+		gateway = gatewayOverride[0]
+	}
+
+	gw, ok := gateway.(refunder)
+	if !ok {
+		return nil, apperr.ErrBusiness("refund_not_supported")
+	}
+
+	if input.AmountCents < 0 {
+		return nil, domain.ErrInvalidRefundAmount()
+	}
+
+	tx, err := uc.repo.BeginTx(ctx, input.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	payment, err := tx.GetByIDForUpdate(ctx, input.BarbershopID, input.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, apperr.ErrBusiness("payment_not_found")
+	}
+
+	if !domain.Status(payment.Status).IsRefundable() {
+		return nil, domain.ErrPaymentNotRefundable()
+	}
+
+	amount := input.AmountCents
+	if amount == 0 {
+		amount = payment.Amount - payment.RefundedAmount
+	}
+	if amount <= 0 || amount > payment.Amount-payment.RefundedAmount {
+		return nil, domain.ErrInvalidRefundAmount()
+	}
+
+	providerPaymentID := refundProviderPaymentID(payment)
+	if providerPaymentID == "" {
+		// Pagamento sem cobrança no provider (ex.: preferência nunca paga via API).
+		return nil, apperr.ErrBusiness("refund_not_supported")
+	}
+
+	result, err := gw.RefundPayment(ctx, domain.RefundInput{
+		ProviderPaymentID: providerPaymentID,
+		AmountCents:       amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("provider refund: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if err := domain.ApplyRefund(payment, amount, now); err != nil {
+		return nil, err
+	}
+
+	refund := &models.PaymentRefund{
+		BarbershopID: payment.BarbershopID,
+		PaymentID:    payment.ID,
+		Kind:         models.PaymentRefundKindRefund,
+		Amount:       amount,
+		Reason:       strings.TrimSpace(input.Reason),
+	}
+	if result.ProviderRefundID != "" {
+		refund.ProviderRefundID = &result.ProviderRefundID
+	}
+
+	if err := tx.ApplyRefundTx(ctx, payment, refund); err != nil {
+		// O dinheiro já saiu no provider — o webhook de estorno reconcilia depois.
+		return nil, fmt.Errorf("failed to persist refund: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	uc.audit.Dispatch(audit.Event{
+		BarbershopID: payment.BarbershopID,
+		Action:       "payment_refunded",
+		Entity:       "payment",
+		EntityID:     &payment.ID,
+		Metadata: map[string]any{
+			"amount_cents":       amount,
+			"refunded_total":     payment.RefundedAmount,
+			"status":             string(payment.Status),
+			"provider_refund_id": result.ProviderRefundID,
+			"reason":             refund.Reason,
+		},
+	})
+
+	return payment, nil
+}
+
+// refundProviderPaymentID retorna o ID da cobrança no provider.
+// Payments antigos não têm provider_payment_id — o TxID "mp_pay:<id>" carrega o ID do MP.
+func refundProviderPaymentID(p *models.Payment) string {
+	if p.ProviderPaymentID != nil && *p.ProviderPaymentID != "" {
+		return strings.TrimPrefix(*p.ProviderPaymentID, mpPayPrefix)
+	}
+	if p.TxID != nil && strings.HasPrefix(*p.TxID, mpPayPrefix) {
+		return strings.TrimPrefix(*p.TxID, mpPayPrefix)
+	}
+	return ""
+}
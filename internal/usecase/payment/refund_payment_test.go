@@ -0,0 +1,157 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// fakeRefundGateway implementa TransparentGateway + refunder registrando as chamadas.
+type fakeRefundGateway struct {
+	calls []domainPayment.RefundInput
+}
+
+func (g *fakeRefundGateway) CreatePayment(_ domainPayment.TransparentPaymentInput) (*domainPayment.TransparentPaymentResult, error) {
+	return nil, nil
+}
+
+func (g *fakeRefundGateway) RefundPayment(_ context.Context, input domainPayment.RefundInput) (*domainPayment.RefundResult, error) {
+	g.calls = append(g.calls, input)
+	return &domainPayment.RefundResult{ProviderRefundID: "rf-1", AmountCents: input.AmountCents}, nil
+}
+
+func newPaidPayment(amount int64) *models.Payment {
+	providerID := "123456"
+	return &models.Payment{
+		ID:                10,
+		BarbershopID:      1,
+		Amount:            amount,
+		Status:            models.PaymentStatus(domainPayment.StatusPaid),
+		ProviderPaymentID: &providerID,
+	}
+}
+
+func TestRefundPayment_Parcial_DeixaPartiallyRefunded(t *testing.T) {
+	p := newPaidPayment(5000)
+	txRepo := &mockTxRepo{payment: p}
+	gw := &fakeRefundGateway{}
+	uc := NewRefundPayment(&mockPaymentRepo{payment: p, txRepo: txRepo}, gw, newTestDispatcher(t))
+
+	got, err := uc.Execute(context.Background(), RefundPaymentInput{BarbershopID: 1, PaymentID: 10, AmountCents: 2000})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if got.Status != models.PaymentStatus(domainPayment.StatusPartiallyRefunded) {
+		t.Errorf("status = %s, want partially_refunded", got.Status)
+	}
+	if got.RefundedAmount != 2000 {
+		t.Errorf("refunded_amount = %d, want 2000", got.RefundedAmount)
+	}
+	if len(gw.calls) != 1 || gw.calls[0].ProviderPaymentID != "123456" || gw.calls[0].AmountCents != 2000 {
+		t.Errorf("gateway calls = %+v", gw.calls)
+	}
+	if len(txRepo.refunds) != 1 || txRepo.refunds[0].Kind != models.PaymentRefundKindRefund {
+		t.Errorf("refunds = %+v", txRepo.refunds)
+	}
+	if txRepo.committedCount != 1 {
+		t.Errorf("commit count = %d, want 1", txRepo.committedCount)
+	}
+}
+
+func TestRefundPayment_SemValor_EstornaSaldoRestante(t *testing.T) {
+	p := newPaidPayment(5000)
+	p.Status = models.PaymentStatus(domainPayment.StatusPartiallyRefunded)
+	p.RefundedAmount = 1500
+	txRepo := &mockTxRepo{payment: p}
+	gw := &fakeRefundGateway{}
+	uc := NewRefundPayment(&mockPaymentRepo{payment: p, txRepo: txRepo}, gw, newTestDispatcher(t))
+
+	got, err := uc.Execute(context.Background(), RefundPaymentInput{BarbershopID: 1, PaymentID: 10})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if got.Status != models.PaymentStatus(domainPayment.StatusRefunded) {
+		t.Errorf("status = %s, want refunded", got.Status)
+	}
+	if got.RefundedAmount != 5000 {
+		t.Errorf("refunded_amount = %d, want 5000", got.RefundedAmount)
+	}
+	if gw.calls[0].AmountCents != 3500 {
+		t.Errorf("gateway amount = %d, want 3500", gw.calls[0].AmountCents)
+	}
+}
+
+func TestRefundPayment_ValorMaiorQueSaldo_Rejeita(t *testing.T) {
+	p := newPaidPayment(5000)
+	txRepo := &mockTxRepo{payment: p}
+	gw := &fakeRefundGateway{}
+	uc := NewRefundPayment(&mockPaymentRepo{payment: p, txRepo: txRepo}, gw, newTestDispatcher(t))
+
+	_, err := uc.Execute(context.Background(), RefundPaymentInput{BarbershopID: 1, PaymentID: 10, AmountCents: 5001})
+	if !apperr.IsBusiness(err, "invalid_refund_amount") {
+		t.Fatalf("err = %v, want invalid_refund_amount", err)
+	}
+	if len(gw.calls) != 0 {
+		t.Error("provider não deve ser chamado com valor inválido")
+	}
+}
+
+func TestRefundPayment_PagamentoPendente_NaoEstorna(t *testing.T) {
+	p := newPaidPayment(5000)
+	p.Status = models.PaymentStatus(domainPayment.StatusPending)
+	txRepo := &mockTxRepo{payment: p}
+	gw := &fakeRefundGateway{}
+	uc := NewRefundPayment(&mockPaymentRepo{payment: p, txRepo: txRepo}, gw, newTestDispatcher(t))
+
+	_, err := uc.Execute(context.Background(), RefundPaymentInput{BarbershopID: 1, PaymentID: 10})
+	if !apperr.IsBusiness(err, "payment_not_refundable") {
+		t.Fatalf("err = %v, want payment_not_refundable", err)
+	}
+	if len(gw.calls) != 0 {
+		t.Error("provider não deve ser chamado para pagamento pendente")
+	}
+}
+
+func TestSyncPaymentReversal_MesmoTotal_Idempotente(t *testing.T) {
+	p := newPaidPayment(5000)
+	txRepo := &mockTxRepo{payment: p}
+	uc := NewSyncPaymentReversal(&mockPaymentRepo{payment: p, txRepo: txRepo}, newTestDispatcher(t))
+
+	input := PaymentReversalInput{ExternalReference: "10", RefundedTotalCents: 2000}
+	for i := 0; i < 2; i++ {
+		if err := uc.Execute(context.Background(), input); err != nil {
+			t.Fatalf("Execute #%d: %v", i+1, err)
+		}
+	}
+
+	if len(txRepo.refunds) != 1 || txRepo.refunds[0].Amount != 2000 {
+		t.Fatalf("refunds = %+v, want uma devolução de 2000", txRepo.refunds)
+	}
+	if p.Status != models.PaymentStatus(domainPayment.StatusPartiallyRefunded) {
+		t.Errorf("status = %s, want partially_refunded", p.Status)
+	}
+}
+
+func TestSyncPaymentReversal_Chargeback_RegistraSaldoRestante(t *testing.T) {
+	p := newPaidPayment(5000)
+	p.Status = models.PaymentStatus(domainPayment.StatusPartiallyRefunded)
+	p.RefundedAmount = 1000
+	txRepo := &mockTxRepo{payment: p}
+	uc := NewSyncPaymentReversal(&mockPaymentRepo{payment: p, txRepo: txRepo}, newTestDispatcher(t))
+
+	if err := uc.Execute(context.Background(), PaymentReversalInput{ExternalReference: "10", ChargedBack: true}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if p.Status != models.PaymentStatus(domainPayment.StatusChargedBack) {
+		t.Errorf("status = %s, want charged_back", p.Status)
+	}
+	if len(txRepo.refunds) != 1 || txRepo.refunds[0].Kind != models.PaymentRefundKindChargeback || txRepo.refunds[0].Amount != 4000 {
+		t.Errorf("refunds = %+v, want chargeback de 4000", txRepo.refunds)
+	}
+}
@@ -0,0 +1,148 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// SyncPaymentReversal aplica estornos e chargebacks informados pelos webhooks
+// do Mercado Pago e do PagBank — inclusive estornos feitos direto no painel do
+// provider, fora do sistema.
+//
+// Os providers informam o total já devolvido, não cada operação: só a
+// diferença para payments.refunded_amount é registrada, o que torna o
+// processamento idempotente para notificações repetidas.
+type SyncPaymentReversal struct {
+	repo  domain.Repository
+	audit *audit.Dispatcher
+}
+
+func NewSyncPaymentReversal(
+	repo domain.Repository,
+	audit *audit.Dispatcher,
+) *SyncPaymentReversal {
+	return &SyncPaymentReversal{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+type PaymentReversalInput struct {
+	ExternalReference  string // nosso payment.ID
+	ProviderRefundID   string
+	RefundedTotalCents int64 // total devolvido segundo o provider
+	ChargedBack        bool
+}
+
+func (uc *SyncPaymentReversal) Execute(ctx context.Context, input PaymentReversalInput) error {
+	if !input.ChargedBack && input.RefundedTotalCents <= 0 {
+		return nil
+	}
+
+	paymentID, err := strconv.ParseUint(input.ExternalReference, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid external_reference %q: %w", input.ExternalReference, err)
+	}
+
+	paymentBase, err := uc.repo.GetByIDGlobal(ctx, uint(paymentID))
+	if err != nil {
+		return fmt.Errorf("failed to load payment: %w", err)
+	}
+	if paymentBase == nil {
+		return fmt.Errorf("payment not found for external_reference: %s", input.ExternalReference)
+	}
+
+	tx, err := uc.repo.BeginTx(ctx, paymentBase.BarbershopID)
+	if err != nil {
+		return fmt.Errorf("begin tx failed: %w", err)
+	}
+	defer tx.Rollback()
+
+	payment, err := tx.GetByIDForUpdate(ctx, paymentBase.BarbershopID, paymentBase.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock payment: %w", err)
+	}
+	if payment == nil {
+		return fmt.Errorf("payment not found")
+	}
+
+	now := time.Now().UTC()
+	refund := &models.PaymentRefund{
+		BarbershopID: payment.BarbershopID,
+		PaymentID:    payment.ID,
+	}
+	if input.ProviderRefundID != "" {
+		refund.ProviderRefundID = &input.ProviderRefundID
+	}
+
+	action := "payment_refunded"
+
+	switch {
+	case input.ChargedBack:
+		if !domain.Status(payment.Status).CanTransitionTo(domain.StatusChargedBack) {
+			return nil
+		}
+		amount, err := domain.MarkChargedBack(payment, now)
+		if err != nil {
+			return err
+		}
+		refund.Kind = models.PaymentRefundKindChargeback
+		refund.Amount = amount
+		refund.Reason = "chargeback"
+		action = "payment_charged_back"
+
+	default:
+		if !domain.Status(payment.Status).IsRefundable() {
+			return nil
+		}
+		total := input.RefundedTotalCents
+		if total > payment.Amount {
+			total = payment.Amount
+		}
+		delta := total - payment.RefundedAmount
+		if delta <= 0 {
+			return nil
+		}
+		if err := domain.ApplyRefund(payment, delta, now); err != nil {
+			return err
+		}
+		refund.Kind = models.PaymentRefundKindRefund
+		refund.Amount = delta
+		refund.Reason = "provider"
+	}
+
+	// Chargeback sobre pagamento já estornado integralmente: nada mais a devolver.
+	if refund.Amount <= 0 {
+		return nil
+	}
+
+	if err := tx.ApplyRefundTx(ctx, payment, refund); err != nil {
+		return fmt.Errorf("failed to persist refund: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	uc.audit.Dispatch(audit.Event{
+		BarbershopID: payment.BarbershopID,
+		Action:       action,
+		Entity:       "payment",
+		EntityID:     &payment.ID,
+		Metadata: map[string]any{
+			"amount_cents":       refund.Amount,
+			"refunded_total":     payment.RefundedAmount,
+			"status":             string(payment.Status),
+			"provider_refund_id": input.ProviderRefundID,
+			"via":                "webhook",
+		},
+	})
+
+	return nil
+}
@@ -25,6 +25,9 @@ type CreateProductInput struct {
 	Stock         int
 	Active        bool
 	OnlineVisible bool
+
+	// LowStockThreshold: nil = sem alerta de estoque baixo.
+	LowStockThreshold *int
 }
 
 func (uc *CreateProduct) Execute(
@@ -48,6 +51,10 @@ func (uc *CreateProduct) Execute(
 		return nil, errors.New("invalid_stock")
 	}
 
+	if input.LowStockThreshold != nil && *input.LowStockThreshold < 0 {
+		return nil, errors.New("invalid_low_stock_threshold")
+	}
+
 	if input.OnlineVisible && input.Stock <= 0 {
 		return nil, errors.New("invalid_online_visible_without_stock")
 	}
@@ -61,6 +68,8 @@ func (uc *CreateProduct) Execute(
 		Stock:         input.Stock,
 		Active:        input.Active,
 		OnlineVisible: input.OnlineVisible,
+
+		LowStockThreshold: input.LowStockThreshold,
 	}
 
 	if err := uc.repo.Create(ctx, product); err != nil {
@@ -5,20 +5,23 @@ import (
 	"errors"
 	"strings"
 
+	domainInventory "github.com/BruksfildServices01/barber-scheduler/internal/domain/inventory"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/product"
 )
 
 type UpdateProduct struct {
-	repo domain.Repository
+	repo      domain.Repository
+	inventory domainInventory.Repository
 }
 
-func NewUpdateProduct(repo domain.Repository) *UpdateProduct {
-	return &UpdateProduct{repo: repo}
+func NewUpdateProduct(repo domain.Repository, inventory domainInventory.Repository) *UpdateProduct {
+	return &UpdateProduct{repo: repo, inventory: inventory}
 }
 
 type UpdateProductInput struct {
 	BarbershopID uint
 	ProductID    uint
+	UserID       *uint
 
 	Name          *string
 	Description   *string
@@ -27,6 +30,10 @@ type UpdateProductInput struct {
 	Stock         *int
 	Active        *bool
 	OnlineVisible *bool
+
+	// LowStockThreshold define o limite de alerta; RemoveLowStockAlert desativa.
+	LowStockThreshold   *int
+	RemoveLowStockAlert bool
 }
 
 func (uc *UpdateProduct) Execute(
@@ -68,13 +75,27 @@ func (uc *UpdateProduct) Execute(
 		product.Price = *input.Price
 	}
 
+	// Estoque informado no cadastro vira uma contagem: a diferença entra no
+	// razão como ajuste, depois de gravar o restante.
+	stockChanged := false
 	if input.Stock != nil {
 		if *input.Stock < 0 {
 			return nil, errors.New("invalid_stock")
 		}
+		stockChanged = *input.Stock != product.Stock
 		product.Stock = *input.Stock
 	}
 
+	if input.RemoveLowStockAlert {
+		product.LowStockThreshold = nil
+	} else if input.LowStockThreshold != nil {
+		if *input.LowStockThreshold < 0 {
+			return nil, errors.New("invalid_low_stock_threshold")
+		}
+		threshold := *input.LowStockThreshold
+		product.LowStockThreshold = &threshold
+	}
+
 	if input.Active != nil {
 		product.Active = *input.Active
 	}
@@ -92,5 +113,14 @@ func (uc *UpdateProduct) Execute(
 		return nil, err
 	}
 
+	if stockChanged {
+		_, err := uc.inventory.Count(ctx, input.BarbershopID, []domainInventory.CountLine{
+			{ProductID: product.ID, Counted: product.Stock},
+		}, input.UserID, "Ajuste no cadastro do produto")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return product, nil
 }
@@ -8,13 +8,14 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
 	orderDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/order"
 	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 	domainService "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
 	"github.com/BruksfildServices01/barber-scheduler/internal/dto"
-	gcal "github.com/BruksfildServices01/barber-scheduler/internal/integration/calendar"
-	"github.com/BruksfildServices01/barber-scheduler/internal/security/crypt"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	ucAppointment   "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
 	ucCart          "github.com/BruksfildServices01/barber-scheduler/internal/usecase/cart"
 	ucSuggestion    "github.com/BruksfildServices01/barber-scheduler/internal/usecase/servicesuggestion"
@@ -31,8 +32,7 @@ type OrchestratedCheckout struct {
 	db                  *gorm.DB
 	apptNotifier        domainNotification.AppointmentNotifier
 	appURL              string
-	googleCfg           gcal.OAuthConfig
-	googleCipher        *crypt.Cipher
+	couponRepo          couponDomain.Repository
 }
 
 func NewOrchestratedCheckout(
@@ -45,8 +45,6 @@ func NewOrchestratedCheckout(
 	apptNotifier domainNotification.AppointmentNotifier,
 	appURL string,
 	getSuggestionUC *ucSuggestion.GetPublicServiceSuggestion,
-	googleCfg gcal.OAuthConfig,
-	googleCipher *crypt.Cipher,
 ) *OrchestratedCheckout {
 	return &OrchestratedCheckout{
 		createAppointmentUC: createAppointmentUC,
@@ -58,27 +56,89 @@ func NewOrchestratedCheckout(
 		db:                  db,
 		apptNotifier:        apptNotifier,
 		appURL:              appURL,
-		googleCfg:           googleCfg,
-		googleCipher:        googleCipher,
 	}
 }
 
+// WithCoupons habilita coupon_code no checkout.
+func (uc *OrchestratedCheckout) WithCoupons(repo couponDomain.Repository) *OrchestratedCheckout {
+	uc.couponRepo = repo
+	return uc
+}
+
+// couponTarget decide onde o cupom entra: cupons de produtos vão para o
+// pedido do carrinho; os demais, para o agendamento.
+func (uc *OrchestratedCheckout) couponTarget(
+	ctx context.Context,
+	barbershopID uint,
+	code string,
+	hasCart bool,
+) (forOrder bool, err error) {
+	if uc.couponRepo == nil {
+		return false, couponDomain.ErrNotFound
+	}
+	c, err := uc.couponRepo.GetByCode(ctx, barbershopID, code)
+	if err != nil {
+		return false, err
+	}
+	if c == nil {
+		return false, couponDomain.ErrNotFound
+	}
+	if c.AppliesTo != models.CouponAppliesToProducts {
+		return false, nil
+	}
+	if !hasCart {
+		return false, couponDomain.ErrNotApplicable
+	}
+	return true, nil
+}
+
 func (uc *OrchestratedCheckout) Execute(
 	ctx context.Context,
 	barbershopID uint,
 	input dto.PublicOrchestratedCheckoutRequestDTO,
 ) (*dto.PublicOrchestratedCheckoutResponseDTO, error) {
 
-	var barber struct {
-		ID uint `gorm:"column:id"`
+	// Barbeiro escolhido pelo cliente; omitido = qualquer barbeiro disponível
+	// (CreatePrivateAppointment atribui um barbeiro livre).
+	var barberID uint
+	if input.BarberID != nil && *input.BarberID != 0 {
+		var count int64
+		if err := uc.db.WithContext(ctx).
+			Raw("SELECT COUNT(*) FROM users WHERE id = ? AND barbershop_id = ? AND active = true", *input.BarberID, barbershopID).
+			Scan(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to load barber: %w", err)
+		}
+		if count == 0 {
+			return nil, apperr.ErrBusiness("barber_not_found")
+		}
+		barberID = *input.BarberID
 	}
-	if err := uc.db.WithContext(ctx).
-		Raw("SELECT id FROM users WHERE barbershop_id = ? AND role = 'owner' LIMIT 1", barbershopID).
-		Scan(&barber).Error; err != nil || barber.ID == 0 {
-		return nil, fmt.Errorf("barber not found for barbershop %d", barbershopID)
+
+	// Vários serviços: o primeiro é o principal (sugestão de produto).
+	serviceID := input.ServiceID
+	if len(input.ServiceIDs) > 0 {
+		serviceID = input.ServiceIDs[0]
+	}
+	// Combo: o principal é o primeiro item do combo.
+	if input.ComboID != 0 {
+		var first uint
+		if err := uc.db.WithContext(ctx).Raw(`
+			SELECT i.service_id
+			FROM service_combo_items i
+			JOIN service_combos c ON c.id = i.combo_id
+			WHERE c.id = ? AND c.barbershop_id = ? AND c.active = true
+			ORDER BY i.position ASC
+			LIMIT 1
+		`, input.ComboID, barbershopID).Scan(&first).Error; err != nil {
+			return nil, fmt.Errorf("failed to load combo: %w", err)
+		}
+		if first == 0 {
+			return nil, apperr.ErrBusiness("combo_not_found")
+		}
+		serviceID = first
 	}
 
-	service, err := uc.serviceRepo.GetByID(ctx, barbershopID, input.ServiceID)
+	service, err := uc.serviceRepo.GetByID(ctx, barbershopID, serviceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service: %w", err)
 	}
@@ -86,88 +146,93 @@ func (uc *OrchestratedCheckout) Execute(
 		return nil, domainService.ErrServiceNotFound
 	}
 
+	cartKey := ""
+	if input.CartKey != nil {
+		cartKey = strings.TrimSpace(*input.CartKey)
+	}
+
+	couponCode := strings.TrimSpace(input.CouponCode)
+	appointmentCoupon, orderCoupon := "", ""
+	if couponCode != "" {
+		forOrder, err := uc.couponTarget(ctx, barbershopID, couponCode, cartKey != "")
+		if err != nil {
+			return nil, err
+		}
+		if forOrder {
+			orderCoupon = couponCode
+		} else {
+			appointmentCoupon = couponCode
+		}
+	}
+
+	// Ticket e confirmação entram na transação que cria o agendamento: o
+	// cliente só recebe a confirmação de um agendamento que existe.
+	var ticketToken string
+	onCreated := func(ctx context.Context, tx *gorm.DB, ap *models.Appointment) error {
+		if uc.generateTicketUC != nil {
+			// Savepoint: um ticket que falha não derruba o agendamento.
+			if err := tx.Transaction(func(tx *gorm.DB) error {
+				token, err := uc.generateTicketUC.ExecuteTx(ctx, tx, ucTicket.GenerateTicketInput{
+					AppointmentID: ap.ID,
+					BarbershopID:  barbershopID,
+					StartTime:     ap.StartTime,
+				})
+				ticketToken = token
+				return err
+			}); err != nil {
+				log.Printf("[OrchestratedCheckout] failed to generate ticket for appointment %d: %v", ap.ID, err)
+				ticketToken = ""
+			}
+		}
+		return uc.notifyConfirmed(outbox.ContextWithTx(ctx, tx), tx, barbershopID, input, service.Name, ap, ticketToken)
+	}
+
 	appointment, err := uc.createAppointmentUC.Execute(
 		ctx,
 		ucAppointment.CreatePrivateAppointmentInput{
 			BarbershopID:   barbershopID,
-			BarberID:       barber.ID,
+			BarberID:       barberID,
 			ClientName:     input.ClientName,
 			ClientPhone:    input.ClientPhone,
 			ClientEmail:    input.ClientEmail,
 			ProductID:      input.ServiceID,
+			ProductIDs:     input.ServiceIDs,
+			ComboID:        input.ComboID,
 			Date:           input.Date,
 			Time:           input.Time,
 			Notes:          input.Notes,
 			IdempotencyKey: input.IdempotencyKey,
+			CouponCode:     appointmentCoupon,
+			OnCreated:      onCreated,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Sincroniza com Google Calendar do barbeiro de forma assíncrona (best-effort).
-	gcal.SyncAppointmentToGoogle(uc.db, uc.googleCfg, uc.googleCipher, barber.ID, barbershopID, appointment)
-
-	var ticketToken string
-	if uc.generateTicketUC != nil {
-		ticketToken, err = uc.generateTicketUC.Execute(ctx, ucTicket.GenerateTicketInput{
-			AppointmentID: appointment.ID,
-			BarbershopID:  barbershopID,
-			StartTime:     appointment.StartTime,
-		})
-		if err != nil {
-			log.Printf("[OrchestratedCheckout] failed to generate ticket for appointment %d: %v", appointment.ID, err)
-			ticketToken = ""
-		}
+	// Catálogo por barbeiro: preço do barbeiro que ficou com o agendamento.
+	override, err := uc.serviceRepo.GetBarberOverride(ctx, barbershopID, *appointment.BarberID, service.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get barber service: %w", err)
 	}
+	service = override.ApplyTo(service)
 
-	// Fire async appointment confirmation notification
-	// Only notify after confirmed payment — skip if appointment is awaiting payment.
-	if uc.apptNotifier != nil &&
-		(input.ClientEmail != "" || input.ClientPhone != "") &&
-		appointment.Status != models.AppointmentStatusAwaitingPayment {
-		type bsRow struct {
-			Name     string `gorm:"column:name"`
-			Phone    string `gorm:"column:phone"`
-			Slug     string `gorm:"column:slug"`
-			Timezone string `gorm:"column:timezone"`
-		}
-		var bs bsRow
-		if dbErr := uc.db.WithContext(ctx).
-			Raw("SELECT name, phone, slug, timezone FROM barbershops WHERE id = ?", barbershopID).
-			Scan(&bs).Error; dbErr != nil {
-			log.Printf("[OrchestratedCheckout] failed to query barbershop for notification: %v", dbErr)
-		} else {
-			ticketURL := ""
-			if ticketToken != "" {
-				ticketURL = uc.appURL + "/ticket/" + ticketToken
-			}
-			notifyInput := domainNotification.AppointmentConfirmedInput{
-				BarbershopID:    barbershopID,
-				ClientName:      input.ClientName,
-				ClientEmail:     input.ClientEmail,
-				ClientPhone:     input.ClientPhone,
-				BarbershopName:  bs.Name,
-				BarbershopPhone: bs.Phone,
-				BarbershopSlug:  bs.Slug,
-				ServiceName:     service.Name,
-				StartTime:       appointment.StartTime,
-				EndTime:         appointment.EndTime,
-				Timezone:        bs.Timezone,
-				TicketURL:       ticketURL,
-			}
-			_ = uc.apptNotifier.NotifyConfirmed(ctx, notifyInput)
+	// Com vários serviços, nome e valor são os do agendamento inteiro.
+	if len(appointment.Services) > 1 {
+		names := make([]string, 0, len(appointment.Services))
+		var total int64
+		for _, line := range appointment.Services {
+			names = append(names, line.ServiceName)
+			total += line.PriceCents
 		}
+		service.Name = strings.Join(names, " + ")
+		service.Price = total
 	}
 
 	var orderDTO *dto.PublicOrchestratedCheckoutOrderDTO
 	var order *orderDomain.Order
 	var productsAmountCents int64
-
-	cartKey := ""
-	if input.CartKey != nil {
-		cartKey = strings.TrimSpace(*input.CartKey)
-	}
+	discountCents := appointment.DiscountCents
 
 	if cartKey != "" && uc.getCartUC != nil && appointment.Status == models.AppointmentStatusAwaitingPayment {
 		cartView, err := uc.getCartUC.Execute(
@@ -187,6 +252,8 @@ func (uc *OrchestratedCheckout) Execute(
 				ucCart.CheckoutCartInput{
 					CartKey:      cartKey,
 					BarbershopID: barbershopID,
+					CouponCode:   orderCoupon,
+					ClientID:     appointment.ClientID,
 				},
 			)
 			if err != nil {
@@ -199,7 +266,8 @@ func (uc *OrchestratedCheckout) Execute(
 				TotalCents: order.TotalAmount,
 				ItemsCount: len(order.Items),
 			}
-			productsAmountCents = order.TotalAmount
+			productsAmountCents = order.SubtotalAmount
+			discountCents += order.DiscountAmount
 		}
 	}
 
@@ -225,7 +293,7 @@ func (uc *OrchestratedCheckout) Execute(
 	}
 
 	serviceAmountCents := service.Price
-	totalAmountCents := serviceAmountCents + productsAmountCents
+	totalAmountCents := serviceAmountCents + productsAmountCents - discountCents
 
 	appointmentPaymentRequired := appointment.Status == models.AppointmentStatusAwaitingPayment
 	orderPaymentRequired := order != nil && order.Status == orderDomain.OrderStatusPending
@@ -250,6 +318,7 @@ func (uc *OrchestratedCheckout) Execute(
 		Summary: dto.PublicOrchestratedCheckoutSummaryDTO{
 			ServiceAmountCents:  serviceAmountCents,
 			ProductsAmountCents: productsAmountCents,
+			DiscountCents:       discountCents,
 			TotalAmountCents:    totalAmountCents,
 		},
 		Payments: dto.PublicOrchestratedCheckoutPaymentsDTO{
@@ -270,6 +339,70 @@ func (uc *OrchestratedCheckout) Execute(
 	return response, nil
 }
 
+// notifyConfirmed grava a confirmação do agendamento no outbox (ctx ligado à
+// transação do INSERT). Só notifica agendamentos confirmados — o que aguarda
+// pagamento é confirmado quando o pagamento entra.
+func (uc *OrchestratedCheckout) notifyConfirmed(
+	ctx context.Context,
+	tx *gorm.DB,
+	barbershopID uint,
+	input dto.PublicOrchestratedCheckoutRequestDTO,
+	serviceName string,
+	appointment *models.Appointment,
+	ticketToken string,
+) error {
+	if uc.apptNotifier == nil ||
+		(input.ClientEmail == "" && input.ClientPhone == "") ||
+		appointment.Status == models.AppointmentStatusAwaitingPayment {
+		return nil
+	}
+
+	type bsRow struct {
+		Name     string `gorm:"column:name"`
+		Phone    string `gorm:"column:phone"`
+		Slug     string `gorm:"column:slug"`
+		Address  string `gorm:"column:address"`
+		Timezone string `gorm:"column:timezone"`
+	}
+	var bs bsRow
+	if err := tx.WithContext(ctx).
+		Raw("SELECT name, phone, slug, address, timezone FROM barbershops WHERE id = ?", barbershopID).
+		Scan(&bs).Error; err != nil {
+		return fmt.Errorf("failed to query barbershop for notification: %w", err)
+	}
+
+	// Com vários serviços, o nome é o do agendamento inteiro.
+	if len(appointment.Services) > 1 {
+		names := make([]string, 0, len(appointment.Services))
+		for _, line := range appointment.Services {
+			names = append(names, line.ServiceName)
+		}
+		serviceName = strings.Join(names, " + ")
+	}
+
+	ticketURL := ""
+	if ticketToken != "" {
+		ticketURL = uc.appURL + "/ticket/" + ticketToken
+	}
+
+	return uc.apptNotifier.NotifyConfirmed(ctx, domainNotification.AppointmentConfirmedInput{
+		BarbershopID:      barbershopID,
+		AppointmentID:     appointment.ID,
+		ClientName:        input.ClientName,
+		ClientEmail:       input.ClientEmail,
+		ClientPhone:       input.ClientPhone,
+		BarbershopName:    bs.Name,
+		BarbershopPhone:   bs.Phone,
+		BarbershopSlug:    bs.Slug,
+		BarbershopAddress: bs.Address,
+		ServiceName:       serviceName,
+		StartTime:         appointment.StartTime,
+		EndTime:           appointment.EndTime,
+		Timezone:          bs.Timezone,
+		TicketURL:         ticketURL,
+	})
+}
+
 func buildNextStep(appointmentPaymentRequired, orderPaymentRequired bool) dto.PublicOrchestratedCheckoutNextStepDTO {
 	switch {
 	case appointmentPaymentRequired && orderPaymentRequired:
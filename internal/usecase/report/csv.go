@@ -0,0 +1,36 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+)
+
+// EncodeCSV grava o documento em CSV com ponto e vírgula e BOM UTF-8, como
+// o Excel em português abre sem importar: cabeçalho do relatório, depois
+// cada seção com título, colunas e linhas, separadas por uma linha vazia.
+func EncodeCSV(doc *domain.Document) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("\uFEFF")
+
+	w := csv.NewWriter(&b)
+	w.Comma = ';'
+
+	w.Write([]string{doc.Title, doc.BarbershopName})
+	w.Write([]string{"Período", doc.DateFrom + " a " + doc.DateTo})
+	for _, s := range doc.Sections {
+		w.Write(nil)
+		w.Write([]string{s.Title})
+		w.Write(s.Columns)
+		for _, row := range s.Rows {
+			w.Write(row)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
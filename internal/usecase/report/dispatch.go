@@ -0,0 +1,306 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+	"github.com/BruksfildServices01/barber-scheduler/internal/integration/email"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+const (
+	// dueBatch limita as assinaturas processadas por rodada do job.
+	dueBatch       = 50
+	maxErrorLength = 255
+)
+
+var errNoRecipients = errors.New("no recipients")
+
+// reportSlugs nomeiam o anexo; periodNames entram no assunto.
+var (
+	reportSlugs = map[string]string{
+		domain.ReportDashboard: "painel",
+		domain.ReportFinancial: "financeiro",
+		domain.ReportImpact:    "impacto",
+	}
+	periodNames = map[string]string{
+		domain.PeriodDaily:   "diário",
+		domain.PeriodWeekly:  "semanal",
+		domain.PeriodMonthly: "mensal",
+	}
+)
+
+// deliverer monta o relatório do último período fechado, envia a cada
+// destinatário e grava o envio no histórico.
+type deliverer struct {
+	repo    domain.Repository
+	builder Builder
+	sender  email.Sender
+}
+
+func (d deliverer) deliver(
+	ctx context.Context,
+	s *models.ReportSubscription,
+	shop *models.Barbershop,
+	at time.Time,
+	trigger string,
+) (*models.ReportDelivery, error) {
+	loc := timezone.Location(shop.Timezone)
+	start, lastDay := domain.CoveredPeriod(s.Period, at, loc)
+	subscriptionID := s.ID
+
+	rec := &models.ReportDelivery{
+		BarbershopID:   s.BarbershopID,
+		SubscriptionID: &subscriptionID,
+		Report:         s.Report,
+		Period:         s.Period,
+		Format:         s.Format,
+		PeriodStart:    dateOnly(start),
+		PeriodEnd:      dateOnly(lastDay),
+		Recipients:     s.Recipients,
+		Trigger:        trigger,
+		Status:         models.ReportDeliveryFailed,
+	}
+
+	msg, err := d.message(ctx, s, shop, start)
+	if err == nil {
+		rec.AttachmentName = msg.Attachments[0].Filename
+		err = d.send(ctx, rec, msg, decodeRecipients(s.Recipients))
+	}
+	if err != nil {
+		e := err.Error()
+		if len(e) > maxErrorLength {
+			e = e[:maxErrorLength]
+		}
+		rec.Error = &e
+	}
+
+	if err := d.repo.RecordDelivery(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// send envia a mensagem a cada destinatário e define o status: sent
+// quando todos receberam, partial quando só parte. Devolve o último erro.
+func (d deliverer) send(ctx context.Context, rec *models.ReportDelivery, msg email.Message, recipients []string) error {
+	if len(recipients) == 0 {
+		return errNoRecipients
+	}
+
+	sent := 0
+	var lastErr error
+	for _, to := range recipients {
+		msg.To = to
+		if err := d.sender.Send(ctx, msg); err != nil {
+			log.Printf("[ReportDelivery] failed to send report subscription=%d to=%s: %v", *rec.SubscriptionID, to, err)
+			lastErr = fmt.Errorf("%s: %w", to, err)
+			continue
+		}
+		sent++
+	}
+
+	switch {
+	case sent == len(recipients):
+		rec.Status = models.ReportDeliverySent
+	case sent > 0:
+		rec.Status = models.ReportDeliveryPartial
+	}
+	return lastErr
+}
+
+// message monta o email (HTML, texto e anexo) do período que começa em
+// start.
+func (d deliverer) message(ctx context.Context, s *models.ReportSubscription, shop *models.Barbershop, start time.Time) (email.Message, error) {
+	doc, err := d.builder.Build(ctx, s.Report, s.Period, shop.ID, start)
+	if err != nil {
+		return email.Message{}, err
+	}
+	doc.BarbershopName = shop.Name
+
+	var content []byte
+	if s.Format == domain.FormatPDF {
+		content = EncodePDF(doc)
+	} else if content, err = EncodeCSV(doc); err != nil {
+		return email.Message{}, err
+	}
+	filename := attachmentName(doc, s.Format)
+
+	html, err := notification.RenderReport(doc, filename)
+	if err != nil {
+		return email.Message{}, err
+	}
+
+	return email.Message{
+		Subject:     subject(doc),
+		Body:        plainText(doc),
+		HTML:        html,
+		Attachments: []email.Attachment{{Filename: filename, Content: content}},
+	}, nil
+}
+
+func periodLabel(doc *domain.Document) string {
+	if doc.DateFrom == doc.DateTo {
+		return doc.DateFrom
+	}
+	return doc.DateFrom + " a " + doc.DateTo
+}
+
+// subject: "Relatório Financeiro semanal – Barbearia X – 13/10/2025 a 19/10/2025".
+func subject(doc *domain.Document) string {
+	return fmt.Sprintf("Relatório %s %s – %s – %s", doc.Title, periodNames[doc.Period], doc.BarbershopName, periodLabel(doc))
+}
+
+// plainText é a alternativa em texto do email: os destaques.
+func plainText(doc *domain.Document) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Relatório %s – %s\nPeríodo: %s\n\n", doc.Title, doc.BarbershopName, periodLabel(doc))
+	for _, m := range doc.Highlights {
+		fmt.Fprintf(&b, "%s: %s\n", m.Label, m.Value)
+	}
+	b.WriteString("\nO relatório completo segue em anexo.")
+	return b.String()
+}
+
+// attachmentName: relatorio-financeiro-2025-10-13-a-2025-10-19.pdf.
+func attachmentName(doc *domain.Document, format string) string {
+	name := "relatorio-" + reportSlugs[doc.Report] + "-" + isoDate(doc.DateFrom)
+	if doc.DateTo != doc.DateFrom {
+		name += "-a-" + isoDate(doc.DateTo)
+	}
+	return name + "." + format
+}
+
+func isoDate(br string) string {
+	t, err := time.Parse("02/01/2006", br)
+	if err != nil {
+		return br
+	}
+	return t.Format("2006-01-02")
+}
+
+// dateOnly grava o dia local como data pura, sem depender do fuso da
+// conexão.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DispatchResult resume uma rodada do job.
+type DispatchResult struct {
+	Sent    int
+	Partial int
+	Failed  int
+}
+
+// DispatchReports é a rodada do job de relatórios: envia as assinaturas
+// vencidas com o último período fechado e agenda o próximo envio. Um envio
+// que falhou fica no histórico e não é repetido; o dono pode reenviar pelo
+// painel.
+type DispatchReports struct {
+	deliverer
+	now func() time.Time
+}
+
+func NewDispatchReports(repo domain.Repository, builder Builder, sender email.Sender) *DispatchReports {
+	return &DispatchReports{
+		deliverer: deliverer{repo: repo, builder: builder, sender: sender},
+		now:       time.Now,
+	}
+}
+
+func (uc *DispatchReports) Execute(ctx context.Context) (DispatchResult, error) {
+	var res DispatchResult
+	now := uc.now().UTC()
+
+	due, err := uc.repo.DueSubscriptions(ctx, now, dueBatch)
+	if err != nil {
+		return res, err
+	}
+
+	for i := range due {
+		s := &due[i]
+
+		shop, err := uc.repo.GetBarbershop(ctx, s.BarbershopID)
+		if err != nil || shop == nil {
+			log.Printf("[DispatchReports] failed to load barbershop=%d subscription=%d: %v", s.BarbershopID, s.ID, err)
+			continue
+		}
+
+		var lastSent *time.Time
+		rec, err := uc.deliver(ctx, s, shop, now, models.ReportTriggerSchedule)
+		switch {
+		case err != nil:
+			log.Printf("[DispatchReports] failed to record delivery subscription=%d: %v", s.ID, err)
+		case rec.Status == models.ReportDeliveryFailed:
+			res.Failed++
+		default:
+			lastSent = &now
+			if rec.Status == models.ReportDeliveryPartial {
+				res.Partial++
+			} else {
+				res.Sent++
+			}
+		}
+
+		next, err := domain.NextRun(s.Period, s.SendTime, timezone.Location(shop.Timezone), now)
+		if err != nil {
+			log.Printf("[DispatchReports] invalid schedule subscription=%d: %v", s.ID, err)
+			continue
+		}
+		if err := uc.repo.AdvanceSubscription(ctx, s.ID, next, lastSent); err != nil {
+			log.Printf("[DispatchReports] failed to advance subscription=%d: %v", s.ID, err)
+		}
+	}
+
+	return res, nil
+}
+
+// SendNow envia agora o relatório da assinatura com o último período
+// fechado, sem mexer no agendamento. O resultado (inclusive falha de
+// envio) volta no registro do histórico.
+type SendNow struct {
+	deliverer
+	now func() time.Time
+}
+
+// NewSendNow: sender nil (email desabilitado) recusa o envio.
+func NewSendNow(repo domain.Repository, builder Builder, sender email.Sender) *SendNow {
+	return &SendNow{
+		deliverer: deliverer{repo: repo, builder: builder, sender: sender},
+		now:       time.Now,
+	}
+}
+
+func (uc *SendNow) Execute(ctx context.Context, barbershopID, id uint) (*DeliveryView, error) {
+	if uc.sender == nil {
+		return nil, ErrEmailUnavailable
+	}
+
+	s, err := uc.repo.GetSubscription(ctx, barbershopID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	shop, err := uc.repo.GetBarbershop(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, ErrBarbershopNotFound
+	}
+
+	rec, err := uc.deliver(ctx, s, shop, uc.now().UTC(), models.ReportTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	return &DeliveryView{ReportDelivery: *rec, Recipients: decodeRecipients(rec.Recipients)}, nil
+}
@@ -0,0 +1,303 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/dashboard"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/financial"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/impact"
+)
+
+// Builder monta o documento do relatório para o período que contém ref.
+type Builder interface {
+	Build(ctx context.Context, report, period string, barbershopID uint, ref time.Time) (*domain.Document, error)
+}
+
+// QueryBuilder monta os documentos com as mesmas consultas do painel.
+type QueryBuilder struct {
+	dashboard *dashboard.Query
+	financial *financial.Query
+	impact    *impact.Query
+}
+
+func NewQueryBuilder(d *dashboard.Query, f *financial.Query, i *impact.Query) *QueryBuilder {
+	return &QueryBuilder{dashboard: d, financial: f, impact: i}
+}
+
+func (b *QueryBuilder) Build(ctx context.Context, report, period string, barbershopID uint, ref time.Time) (*domain.Document, error) {
+	queryPeriod := domain.QueryPeriod(period)
+
+	var doc *domain.Document
+	switch report {
+	case domain.ReportDashboard:
+		out, err := b.dashboard.Execute(ctx, dashboard.Input{
+			BarbershopID: barbershopID,
+			Period:       dashboard.PeriodType(queryPeriod),
+			Reference:    ref,
+		})
+		if err != nil {
+			return nil, err
+		}
+		doc = DashboardDocument(out)
+	case domain.ReportFinancial:
+		out, err := b.financial.Execute(ctx, financial.Input{
+			BarbershopID: barbershopID,
+			Period:       financial.PeriodType(queryPeriod),
+			Reference:    ref,
+		})
+		if err != nil {
+			return nil, err
+		}
+		doc = FinancialDocument(out)
+	case domain.ReportImpact:
+		out, err := b.impact.Execute(ctx, impact.Input{
+			BarbershopID: barbershopID,
+			Period:       impact.PeriodType(queryPeriod),
+			Reference:    ref,
+		})
+		if err != nil {
+			return nil, err
+		}
+		doc = ImpactDocument(out)
+	default:
+		return nil, domain.ErrInvalidReport
+	}
+
+	doc.Report = report
+	doc.Period = period
+	return doc, nil
+}
+
+var (
+	indicatorColumns = []string{"Indicador", "Valor"}
+	rankColumns      = []string{"Item", "Quantidade", "Faturamento"}
+)
+
+// DashboardDocument converte o painel do período.
+func DashboardDocument(d *dashboard.ResponseDTO) *domain.Document {
+	doc := &domain.Document{
+		Title:    "Painel",
+		DateFrom: brDate(d.DateFrom),
+		DateTo:   brDate(d.DateTo),
+		Highlights: []domain.Metric{
+			{Label: "Faturamento", Value: brl(d.Revenue.TotalCents)},
+			{Label: "Atendimentos concluídos", Value: strconv.Itoa(d.Production.Completed)},
+			{Label: "Ticket médio", Value: brl(d.Revenue.AvgTicketCents)},
+			{Label: "Clientes novos", Value: strconv.Itoa(d.Clients.New)},
+		},
+	}
+
+	services := make([][]string, 0, len(d.TopServices))
+	for _, s := range d.TopServices {
+		services = append(services, []string{s.ServiceName, strconv.Itoa(s.Count), brl(s.RevenueCents)})
+	}
+	products := make([][]string, 0, len(d.TopProducts))
+	for _, p := range d.TopProducts {
+		products = append(products, []string{p.ProductName, strconv.Itoa(p.Quantity), brl(p.RevenueCents)})
+	}
+	coupons := make([][]string, 0, len(d.Coupons.TopCoupons))
+	for _, c := range d.Coupons.TopCoupons {
+		coupons = append(coupons, []string{c.Code, strconv.Itoa(c.Redemptions), brl(c.DiscountCents)})
+	}
+
+	doc.Sections = []domain.Section{
+		indicators("Atendimentos",
+			"Total", strconv.Itoa(d.Production.Total),
+			"Concluídos", strconv.Itoa(d.Production.Completed),
+			"Cancelados", strconv.Itoa(d.Production.Cancelled),
+			"Faltas", strconv.Itoa(d.Production.NoShow),
+			"Agendados", strconv.Itoa(d.Production.Scheduled),
+			"Taxa de comparecimento", percent(d.Production.AttendanceRate*100),
+		),
+		indicators("Faturamento",
+			"Total recebido", brl(d.Revenue.TotalCents),
+			"Serviços", brl(d.Revenue.ServicesCents),
+			"Produtos", brl(d.Revenue.ProductsCents),
+			"Mensalidades de assinatura", brl(d.Revenue.SubscriptionPaymentRevenueCents),
+			"Produção coberta por assinatura", brl(d.Revenue.SubscriptionsCents),
+			"Ticket médio", brl(d.Revenue.AvgTicketCents),
+		),
+		indicators("Clientes",
+			"Atendidos", strconv.Itoa(d.Clients.Total),
+			"Novos", strconv.Itoa(d.Clients.New),
+			"Recorrentes", strconv.Itoa(d.Clients.Returning),
+			"Com assinatura ativa", strconv.Itoa(d.Clients.WithActiveSubscription),
+		),
+		{Title: "Serviços mais vendidos", Columns: rankColumns, Rows: services},
+		{Title: "Produtos mais vendidos", Columns: rankColumns, Rows: products},
+		{Title: "Cupons", Columns: []string{"Cupom", "Usos", "Desconto"}, Rows: coupons},
+	}
+	return doc
+}
+
+// lossLabels nomeia os tipos de perda do financeiro.
+var lossLabels = map[string]string{
+	"no_show":             "Faltas",
+	"cancellation":        "Cancelamentos",
+	"suggestion_not_sold": "Sugestões não vendidas",
+	"product_return":      "Devoluções de produto",
+	"refund":              "Reembolsos",
+	"chargeback":          "Chargebacks",
+}
+
+// FinancialDocument converte o financeiro do período.
+func FinancialDocument(f *financial.ResponseDTO) *domain.Document {
+	doc := &domain.Document{
+		Title:    "Financeiro",
+		DateFrom: brDate(f.DateFrom),
+		DateTo:   brDate(f.DateTo),
+		Highlights: []domain.Metric{
+			{Label: "Realizado", Value: brl(f.Realized.TotalCents)},
+			{Label: "Presumido", Value: brl(f.Presumed.TotalCents)},
+			{Label: "Perdas", Value: brl(f.Losses.TotalCents)},
+			{Label: "Fechamentos", Value: strconv.Itoa(f.Realized.ClosuresCount)},
+		},
+	}
+
+	losses := make([][]string, 0, len(f.Losses.Breakdown))
+	for _, l := range f.Losses.Breakdown {
+		label := lossLabels[l.Type]
+		if label == "" {
+			label = l.Type
+		}
+		losses = append(losses, []string{label, strconv.Itoa(l.Count), brl(l.AmountCents)})
+	}
+
+	doc.Sections = []domain.Section{
+		indicators("Realizado",
+			"Total recebido", brl(f.Realized.TotalCents),
+			"Serviços", brl(f.Realized.ServicesCents),
+			"Produtos", brl(f.Realized.ProductsCents),
+			"Mensalidades de assinatura", brl(f.Realized.SubscriptionPaymentRevenueCents),
+			"Pacotes vendidos", brl(f.Realized.PackagePaymentRevenueCents),
+			"Produção coberta por assinatura", brl(f.Realized.SubscriptionsCents),
+			"Produção coberta por pacote", brl(f.Realized.PackagesCents),
+			"Fechamentos", strconv.Itoa(f.Realized.ClosuresCount),
+			"Pedidos pagos", strconv.Itoa(f.Realized.PaidOrdersCount),
+		),
+		indicators("Presumido e expectativa",
+			"Presumido (atendimentos sem fechamento)", brl(f.Presumed.TotalCents),
+			"Atendimentos sem fechamento", strconv.Itoa(f.Presumed.AppointmentsCount),
+			"Expectativa (agendados)", brl(f.Expectation.TotalCents),
+			"Atendimentos agendados", strconv.Itoa(f.Expectation.AppointmentsCount),
+		),
+		{Title: "Perdas", Columns: []string{"Tipo", "Quantidade", "Valor"}, Rows: losses},
+		rankSection("Serviços mais vendidos", f.TopServices),
+		rankSection("Produtos mais vendidos", f.TopProducts),
+		rankSection("Combos mais vendidos", f.TopCombos),
+	}
+	return doc
+}
+
+func rankSection(title string, items []financial.TopItemDTO) domain.Section {
+	rows := make([][]string, 0, len(items))
+	for _, it := range items {
+		rows = append(rows, []string{it.Name, strconv.Itoa(it.Count), brl(it.RevenueCents)})
+	}
+	return domain.Section{Title: title, Columns: rankColumns, Rows: rows}
+}
+
+// ImpactDocument converte o relatório de impacto do período.
+func ImpactDocument(i *impact.ResponseDTO) *domain.Document {
+	doc := &domain.Document{
+		Title:    "Impacto",
+		DateFrom: brDate(i.DateFrom),
+		DateTo:   brDate(i.DateTo),
+		Highlights: []domain.Metric{
+			{Label: "Faturamento", Value: brl(i.Revenue.CurrentCents)},
+			{Label: "Crescimento", Value: percent(i.Revenue.GrowthPercent)},
+			{Label: "Taxa de retorno", Value: percent(i.Retention.ReturnRatePercent)},
+			{Label: "Valor gerado", Value: brl(i.ROI.ValueGeneratedCents)},
+		},
+	}
+
+	doc.Sections = []domain.Section{
+		indicators("Faturamento",
+			"Período", brl(i.Revenue.CurrentCents),
+			"Período anterior", brl(i.Revenue.PreviousCents),
+			"Crescimento", percent(i.Revenue.GrowthPercent),
+			"Ticket médio", brl(i.Revenue.TicketAverageCents),
+		),
+		indicators("Clientes",
+			"Novos", strconv.Itoa(i.Growth.NewClientsCount),
+			"Recorrentes", strconv.Itoa(i.Growth.ReturningClientsCount),
+			"Ativos", strconv.Itoa(i.Growth.TotalActiveClients),
+			"Taxa de retorno", percent(i.Retention.ReturnRatePercent),
+			"Em risco", strconv.Itoa(i.Retention.AtRiskCount),
+			"Fiéis", strconv.Itoa(i.Retention.TrustedCount),
+			"Inativos", strconv.Itoa(i.Retention.InactiveCount),
+		),
+		indicators("Uso",
+			"Atendimentos", strconv.Itoa(i.Usage.TotalAppointments),
+			"Concluídos", strconv.Itoa(i.Usage.CompletedCount),
+			"Taxa de comparecimento", percent(i.Usage.AttendanceRatePercent),
+			"Fechamentos", strconv.Itoa(i.Usage.ClosuresCount),
+			"Taxa de fechamento", percent(i.Usage.ClosureRatePercent),
+			"Ajustes de fechamento", strconv.Itoa(i.Usage.AdjustmentsCount),
+		),
+		indicators("Perdas",
+			"Total", brl(i.Losses.TotalCents),
+			"Faltas", fmt.Sprintf("%d (%s)", i.Losses.NoShowCount, brl(i.Losses.NoShowCents)),
+			"Cancelamentos", fmt.Sprintf("%d (%s)", i.Losses.CancellationCount, brl(i.Losses.CancellationCents)),
+		),
+		indicators("Ganhos indiretos",
+			"Vendas adicionais", fmt.Sprintf("%d (%s)", i.Indirect.AdditionalSalesCount, brl(i.Indirect.AdditionalSalesCents)),
+			"Assinaturas ativas", strconv.Itoa(i.Indirect.ActiveSubscriptionsCount),
+			"Conversão de sugestões", percent(i.Indirect.SuggestionConversionRate),
+			"Upsell capturado", brl(i.Indirect.UpsellCapturedCents),
+		),
+		indicators("Campanhas",
+			"Mensagens enviadas", strconv.Itoa(i.Campaigns.MessagesSent),
+			"Agendamentos atribuídos", strconv.Itoa(i.Campaigns.AttributedBookings),
+			"Concluídos atribuídos", strconv.Itoa(i.Campaigns.AttributedCompleted),
+			"Faturamento atribuído", brl(i.Campaigns.AttributedRevenue),
+			"Conversão", percent(i.Campaigns.ConversionRatePercent),
+		),
+		indicators("Retorno do sistema",
+			"Valor gerado", brl(i.ROI.ValueGeneratedCents),
+			"Valor das assinaturas", brl(i.ROI.SubscriptionValueCents),
+		),
+	}
+	return doc
+}
+
+// indicators monta uma seção Indicador/Valor a partir de pares.
+func indicators(title string, pairs ...string) domain.Section {
+	rows := make([][]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rows = append(rows, []string{pairs[i], pairs[i+1]})
+	}
+	return domain.Section{Title: title, Columns: indicatorColumns, Rows: rows}
+}
+
+// brl formata centavos como R$ 1.234,56.
+func brl(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	reais := strconv.FormatInt(cents/100, 10)
+	for i := len(reais) - 3; i > 0; i -= 3 {
+		reais = reais[:i] + "." + reais[i:]
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sign, reais, cents%100)
+}
+
+func percent(v float64) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', 1, 64), ".", ",", 1) + "%"
+}
+
+// brDate converte aaaa-mm-dd em dd/mm/aaaa.
+func brDate(s string) string {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return s
+	}
+	return t.Format("02/01/2006")
+}
@@ -0,0 +1,10 @@
+package report
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop    = errors.New("invalid_barbershop")
+	ErrBarbershopNotFound   = errors.New("barbershop_not_found")
+	ErrSubscriptionNotFound = errors.New("report_subscription_not_found")
+	ErrEmailUnavailable     = errors.New("email_unavailable")
+)
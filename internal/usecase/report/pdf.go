@@ -0,0 +1,179 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+)
+
+// Página A4, em pontos.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	// pdfValueX é onde começam as colunas depois da primeira.
+	pdfValueX = 330
+)
+
+type pdfText struct {
+	x    int
+	y    int
+	bold bool
+	size int
+	text string
+}
+
+// EncodePDF grava o documento num PDF de texto simples (Helvetica, sem
+// dependências): cabeçalho, destaques e as seções em colunas, com quantas
+// páginas precisar.
+func EncodePDF(doc *domain.Document) []byte {
+	var pages [][]pdfText
+	var page []pdfText
+	y := pdfPageHeight - pdfMargin
+
+	// line escreve as células numa linha de altura height, quebrando a
+	// página quando não cabe.
+	line := func(height int, cells ...pdfText) {
+		if y-height < pdfMargin {
+			pages = append(pages, page)
+			page = nil
+			y = pdfPageHeight - pdfMargin
+		}
+		y -= height
+		for _, c := range cells {
+			c.y = y
+			page = append(page, c)
+		}
+	}
+
+	line(20, pdfText{x: pdfMargin, bold: true, size: 18, text: "Relatório " + doc.Title})
+	line(18, pdfText{x: pdfMargin, size: 11, text: doc.BarbershopName + " — " + doc.DateFrom + " a " + doc.DateTo})
+
+	if len(doc.Highlights) > 0 {
+		line(26, pdfText{x: pdfMargin, bold: true, size: 12, text: "Destaques"})
+		for _, m := range doc.Highlights {
+			line(15, pdfText{x: pdfMargin, size: 10, text: m.Label}, pdfText{x: pdfValueX, bold: true, size: 10, text: m.Value})
+		}
+	}
+
+	for _, s := range doc.Sections {
+		line(26, pdfText{x: pdfMargin, bold: true, size: 12, text: s.Title})
+		line(15, pdfRow(s.Columns, true)...)
+		if len(s.Rows) == 0 {
+			line(15, pdfText{x: pdfMargin, size: 10, text: "Sem dados no período."})
+		}
+		for _, row := range s.Rows {
+			line(15, pdfRow(row, false)...)
+		}
+	}
+	pages = append(pages, page)
+
+	return writePDF(pages)
+}
+
+// pdfRow posiciona as células: a primeira na margem, as demais divididas
+// entre pdfValueX e a margem direita.
+func pdfRow(cells []string, bold bool) []pdfText {
+	out := make([]pdfText, 0, len(cells))
+	step := 0
+	if len(cells) > 2 {
+		step = (pdfPageWidth - pdfMargin - pdfValueX) / (len(cells) - 1)
+	}
+	for i, c := range cells {
+		x, limit := pdfMargin, 50
+		if i > 0 {
+			x, limit = pdfValueX+step*(i-1), 20
+		}
+		out = append(out, pdfText{x: x, bold: bold, size: 10, text: truncate(c, limit)})
+	}
+	return out
+}
+
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
+
+// writePDF monta os objetos (catálogo, páginas, fontes e um conteúdo por
+// página) e a tabela xref com os offsets.
+func writePDF(pages [][]pdfText) []byte {
+	var b bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, texts := range pages {
+		var content bytes.Buffer
+		for _, t := range texts {
+			font := "F1"
+			if t.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, t.size, t.x, t.y, pdfString(t.text))
+		}
+		footer := fmt.Sprintf("Corteon — página %d de %d", i+1, len(pages))
+		fmt.Fprintf(&content, "BT /F1 8 Tf %d %d Td (%s) Tj ET\n", pdfMargin, pdfMargin/2, pdfString(footer))
+
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return b.Bytes()
+}
+
+// winAnsi são os caracteres fora do Latin-1 que a WinAnsiEncoding tem.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfString codifica o texto em WinAnsi para uma string literal do PDF;
+// o que a codificação não tem vira "?".
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
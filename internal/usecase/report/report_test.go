@@ -0,0 +1,310 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+	"github.com/BruksfildServices01/barber-scheduler/internal/integration/email"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/query/financial"
+)
+
+type fakeRepo struct {
+	shop       *models.Barbershop
+	subs       map[uint]*models.ReportSubscription
+	deliveries []models.ReportDelivery
+	advanced   map[uint]time.Time
+	lastSent   map[uint]*time.Time
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		shop:     &models.Barbershop{ID: 1, Name: "Barbearia Centro", Timezone: "America/Sao_Paulo"},
+		subs:     map[uint]*models.ReportSubscription{},
+		advanced: map[uint]time.Time{},
+		lastSent: map[uint]*time.Time{},
+	}
+}
+
+func (r *fakeRepo) ListSubscriptions(ctx context.Context, barbershopID uint) ([]models.ReportSubscription, error) {
+	var out []models.ReportSubscription
+	for _, s := range r.subs {
+		out = append(out, *s)
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetSubscription(ctx context.Context, barbershopID, id uint) (*models.ReportSubscription, error) {
+	s := r.subs[id]
+	if s == nil || s.BarbershopID != barbershopID {
+		return nil, nil
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *fakeRepo) SaveSubscription(ctx context.Context, s *models.ReportSubscription) error {
+	if s.ID == 0 {
+		s.ID = uint(len(r.subs) + 1)
+	}
+	cp := *s
+	r.subs[s.ID] = &cp
+	return nil
+}
+
+func (r *fakeRepo) DeleteSubscription(ctx context.Context, barbershopID, id uint) (bool, error) {
+	if _, ok := r.subs[id]; !ok {
+		return false, nil
+	}
+	delete(r.subs, id)
+	return true, nil
+}
+
+func (r *fakeRepo) DueSubscriptions(ctx context.Context, now time.Time, limit int) ([]models.ReportSubscription, error) {
+	var out []models.ReportSubscription
+	for _, s := range r.subs {
+		if s.Active && !s.NextRunAt.After(now) {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) AdvanceSubscription(ctx context.Context, id uint, next time.Time, lastSent *time.Time) error {
+	r.advanced[id] = next
+	r.lastSent[id] = lastSent
+	return nil
+}
+
+func (r *fakeRepo) RecordDelivery(ctx context.Context, d *models.ReportDelivery) error {
+	d.ID = uint(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, *d)
+	return nil
+}
+
+func (r *fakeRepo) ListDeliveries(ctx context.Context, barbershopID uint, subscriptionID *uint, limit, offset int) ([]models.ReportDelivery, int64, error) {
+	return r.deliveries, int64(len(r.deliveries)), nil
+}
+
+func (r *fakeRepo) GetBarbershop(ctx context.Context, barbershopID uint) (*models.Barbershop, error) {
+	if barbershopID != r.shop.ID {
+		return nil, nil
+	}
+	return r.shop, nil
+}
+
+// fakeBuilder devolve um financeiro fixo e guarda a referência pedida.
+type fakeBuilder struct {
+	ref time.Time
+}
+
+func (b *fakeBuilder) Build(ctx context.Context, report, period string, barbershopID uint, ref time.Time) (*domain.Document, error) {
+	b.ref = ref
+	doc := FinancialDocument(&financial.ResponseDTO{
+		DateFrom: "2025-10-13",
+		DateTo:   "2025-10-19",
+		Realized: financial.RealizedDTO{TotalCents: 123456, ClosuresCount: 31},
+		Losses: financial.LossesDTO{
+			TotalCents: 9000,
+			Breakdown:  []financial.LossItemDTO{{Type: "no_show", AmountCents: 9000, Count: 2}},
+		},
+		TopServices: []financial.TopItemDTO{{Name: "Corte (degradê)", Count: 20, RevenueCents: 90000}},
+	})
+	doc.Report = report
+	doc.Period = period
+	return doc, nil
+}
+
+type fakeSender struct {
+	fail map[string]bool
+	sent []email.Message
+}
+
+func (s *fakeSender) Send(ctx context.Context, msg email.Message) error {
+	if s.fail[msg.To] {
+		return errors.New("mailbox unavailable")
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestDispatchReportsSendsClosedWeekAndReschedules(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("tzdata indisponível")
+	}
+	repo := newFakeRepo()
+	// segunda-feira, 20/10/2025 08:00 em São Paulo
+	runAt := time.Date(2025, 10, 20, 8, 0, 0, 0, loc)
+	repo.subs[7] = &models.ReportSubscription{
+		ID: 7, BarbershopID: 1, Report: domain.ReportFinancial, Period: domain.PeriodWeekly,
+		Format: domain.FormatPDF, Recipients: `["dono@centro.com","gerente@centro.com"]`,
+		SendTime: "08:00", Active: true, NextRunAt: runAt.UTC(),
+	}
+	repo.subs[8] = &models.ReportSubscription{
+		ID: 8, BarbershopID: 1, Report: domain.ReportDashboard, Period: domain.PeriodDaily,
+		Format: domain.FormatCSV, Recipients: `["dono@centro.com"]`,
+		SendTime: "09:00", Active: true, NextRunAt: runAt.Add(time.Hour).UTC(),
+	}
+
+	builder := &fakeBuilder{}
+	sender := &fakeSender{fail: map[string]bool{"gerente@centro.com": true}}
+	uc := NewDispatchReports(repo, builder, sender)
+	uc.now = func() time.Time { return runAt.Add(3 * time.Minute) }
+
+	res, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Partial != 1 || res.Sent != 0 || res.Failed != 0 {
+		t.Fatalf("resultado inesperado %+v", res)
+	}
+	if _, ok := repo.advanced[8]; ok {
+		t.Error("assinatura ainda não vencida não deveria ser processada")
+	}
+
+	if want := time.Date(2025, 10, 13, 0, 0, 0, 0, loc); !builder.ref.Equal(want) {
+		t.Errorf("referência esperada %s, obtida %s", want, builder.ref)
+	}
+	if want := time.Date(2025, 10, 27, 8, 0, 0, 0, loc); !repo.advanced[7].Equal(want) {
+		t.Errorf("próximo envio esperado %s, obtido %s", want, repo.advanced[7])
+	}
+	if repo.lastSent[7] == nil {
+		t.Error("envio parcial deveria marcar o último envio")
+	}
+
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("esperado 1 registro no histórico, obtidos %d", len(repo.deliveries))
+	}
+	d := repo.deliveries[0]
+	if d.Status != models.ReportDeliveryPartial || d.Trigger != models.ReportTriggerSchedule {
+		t.Errorf("registro inesperado %+v", d)
+	}
+	if d.PeriodStart.Format("2006-01-02") != "2025-10-13" || d.PeriodEnd.Format("2006-01-02") != "2025-10-19" {
+		t.Errorf("período esperado 13 a 19/10, obtido %s a %s", d.PeriodStart, d.PeriodEnd)
+	}
+	if d.Error == nil || !strings.Contains(*d.Error, "gerente@centro.com") {
+		t.Errorf("erro deveria nomear o destinatário, obtido %v", d.Error)
+	}
+	if d.AttachmentName != "relatorio-financeiro-2025-10-13-a-2025-10-19.pdf" {
+		t.Errorf("anexo %q", d.AttachmentName)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("esperado 1 email enviado, obtidos %d", len(sender.sent))
+	}
+	msg := sender.sent[0]
+	if msg.To != "dono@centro.com" || !strings.Contains(msg.Subject, "Financeiro semanal") {
+		t.Errorf("email inesperado to=%s subject=%s", msg.To, msg.Subject)
+	}
+	if !strings.Contains(msg.HTML, "R$ 1.234,56") || !strings.Contains(msg.HTML, "Barbearia Centro") {
+		t.Error("HTML deveria trazer os destaques e a barbearia")
+	}
+	if !bytes.HasPrefix(msg.Attachments[0].Content, []byte("%PDF-")) {
+		t.Error("anexo deveria ser um PDF")
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	repo := newFakeRepo()
+	uc := NewCreateSubscription(repo)
+	uc.now = func() time.Time { return time.Date(2025, 10, 15, 13, 0, 0, 0, time.UTC) }
+
+	_, err := uc.Execute(context.Background(), SubscriptionInput{
+		BarbershopID: 1, Report: domain.ReportImpact, Period: domain.PeriodDaily,
+		Format: domain.FormatCSV, Recipients: []string{"dono@centro.com"}, SendTime: "08:00",
+	})
+	if !errors.Is(err, domain.ErrPeriodUnsupported) {
+		t.Errorf("impacto diário deveria ser recusado, obtido %v", err)
+	}
+
+	out, err := uc.Execute(context.Background(), SubscriptionInput{
+		BarbershopID: 1, Report: domain.ReportDashboard, Period: domain.PeriodMonthly,
+		Format: domain.FormatCSV, Recipients: []string{"Dono@Centro.com"}, SendTime: "07:30",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Active || len(out.Recipients) != 1 || out.Recipients[0] != "dono@centro.com" {
+		t.Errorf("assinatura inesperada %+v", out)
+	}
+	// 1º/11 07:30 em São Paulo (UTC-3)
+	if want := time.Date(2025, 11, 1, 10, 30, 0, 0, time.UTC); !out.NextRunAt.Equal(want) {
+		t.Errorf("primeiro envio esperado %s, obtido %s", want, out.NextRunAt)
+	}
+}
+
+func TestSendNowWithoutEmail(t *testing.T) {
+	repo := newFakeRepo()
+	repo.subs[1] = &models.ReportSubscription{ID: 1, BarbershopID: 1}
+
+	_, err := NewSendNow(repo, &fakeBuilder{}, nil).Execute(context.Background(), 1, 1)
+	if !errors.Is(err, ErrEmailUnavailable) {
+		t.Errorf("esperado %v, obtido %v", ErrEmailUnavailable, err)
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	doc, _ := (&fakeBuilder{}).Build(context.Background(), domain.ReportFinancial, domain.PeriodWeekly, 1, time.Time{})
+	doc.BarbershopName = "Barbearia Centro"
+
+	out, err := EncodeCSV(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	if !strings.HasPrefix(s, "\uFEFFFinanceiro;Barbearia Centro\n") {
+		t.Errorf("cabeçalho inesperado: %q", s[:40])
+	}
+	for _, want := range []string{"Faltas;2;R$ 90,00", "Corte (degradê);20;R$ 900,00", "Total recebido;R$ 1.234,56"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("CSV sem %q", want)
+		}
+	}
+}
+
+func TestEncodePDFXref(t *testing.T) {
+	doc, _ := (&fakeBuilder{}).Build(context.Background(), domain.ReportFinancial, domain.PeriodWeekly, 1, time.Time{})
+	// Linhas suficientes para quebrar a página.
+	for i := 0; i < 60; i++ {
+		doc.Sections[3].Rows = append(doc.Sections[3].Rows, []string{"Serviço " + strconv.Itoa(i), "1", "R$ 10,00"})
+	}
+	out := EncodePDF(doc)
+
+	if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("PDF sem o marcador de fim")
+	}
+	if bytes.Contains(out, []byte("/Count 1 ")) {
+		t.Error("esperada mais de uma página")
+	}
+
+	// Cada entrada da xref aponta para o início do objeto correspondente.
+	xref := bytes.Index(out, []byte("xref\n"))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := strconv.Itoa(i+1) + " 0 obj"
+		if !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("xref do objeto %d aponta para %q", i+1, out[off:off+10])
+		}
+	}
+	if !bytes.Contains(out, []byte(`Corte \(degrad\352\)`)) {
+		t.Error("texto deveria sair em WinAnsi com parênteses escapados")
+	}
+}
+
+func TestBRL(t *testing.T) {
+	cases := map[int64]string{0: "R$ 0,00", 4990: "R$ 49,90", 123456789: "R$ 1.234.567,89", -500: "-R$ 5,00"}
+	for cents, want := range cases {
+		if got := brl(cents); got != want {
+			t.Errorf("brl(%d) = %q, esperado %q", cents, got, want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/report"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+type SubscriptionInput struct {
+	BarbershopID uint
+	ID           uint  // só na alteração
+	UserID       *uint // quem criou
+	Report       string
+	Period       string
+	Format       string
+	Recipients   []string
+	SendTime     string // HH:MM no fuso da barbearia
+	Active       *bool  // nil = ativa na criação, mantém na alteração
+}
+
+// SubscriptionView é a assinatura com os destinatários decodificados.
+type SubscriptionView struct {
+	models.ReportSubscription
+	Recipients []string `json:"recipients"`
+}
+
+func subscriptionView(s models.ReportSubscription) SubscriptionView {
+	return SubscriptionView{ReportSubscription: s, Recipients: decodeRecipients(s.Recipients)}
+}
+
+func decodeRecipients(raw string) []string {
+	out := []string{}
+	_ = json.Unmarshal([]byte(raw), &out)
+	return out
+}
+
+func encodeRecipients(list []string) string {
+	b, _ := json.Marshal(list)
+	return string(b)
+}
+
+// apply valida a entrada, grava os campos na assinatura e recalcula o
+// próximo envio no fuso da barbearia.
+func (in SubscriptionInput) apply(ctx context.Context, repo domain.Repository, s *models.ReportSubscription, now time.Time) error {
+	if in.BarbershopID == 0 {
+		return ErrInvalidBarbershop
+	}
+	if err := domain.Validate(in.Report, in.Period, in.Format, in.SendTime); err != nil {
+		return err
+	}
+	recipients, err := domain.NormalizeRecipients(in.Recipients)
+	if err != nil {
+		return err
+	}
+
+	shop, err := repo.GetBarbershop(ctx, in.BarbershopID)
+	if err != nil {
+		return err
+	}
+	if shop == nil {
+		return ErrBarbershopNotFound
+	}
+	next, err := domain.NextRun(in.Period, in.SendTime, timezone.Location(shop.Timezone), now)
+	if err != nil {
+		return err
+	}
+
+	s.BarbershopID = in.BarbershopID
+	s.Report = in.Report
+	s.Period = in.Period
+	s.Format = in.Format
+	s.Recipients = encodeRecipients(recipients)
+	s.SendTime = in.SendTime
+	s.NextRunAt = next
+	if in.Active != nil {
+		s.Active = *in.Active
+	}
+	return nil
+}
+
+type ListSubscriptions struct {
+	repo domain.Repository
+}
+
+func NewListSubscriptions(repo domain.Repository) *ListSubscriptions {
+	return &ListSubscriptions{repo: repo}
+}
+
+func (uc *ListSubscriptions) Execute(ctx context.Context, barbershopID uint) ([]SubscriptionView, error) {
+	list, err := uc.repo.ListSubscriptions(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SubscriptionView, 0, len(list))
+	for _, s := range list {
+		out = append(out, subscriptionView(s))
+	}
+	return out, nil
+}
+
+// CreateSubscription cria a assinatura com o primeiro envio já agendado.
+type CreateSubscription struct {
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewCreateSubscription(repo domain.Repository) *CreateSubscription {
+	return &CreateSubscription{repo: repo, now: time.Now}
+}
+
+func (uc *CreateSubscription) Execute(ctx context.Context, in SubscriptionInput) (*SubscriptionView, error) {
+	s := &models.ReportSubscription{Active: true, CreatedByUserID: in.UserID}
+	if err := in.apply(ctx, uc.repo, s, uc.now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveSubscription(ctx, s); err != nil {
+		return nil, err
+	}
+	view := subscriptionView(*s)
+	return &view, nil
+}
+
+// UpdateSubscription altera a assinatura; o próximo envio é recalculado a
+// partir de agora.
+type UpdateSubscription struct {
+	repo domain.Repository
+	now  func() time.Time
+}
+
+func NewUpdateSubscription(repo domain.Repository) *UpdateSubscription {
+	return &UpdateSubscription{repo: repo, now: time.Now}
+}
+
+func (uc *UpdateSubscription) Execute(ctx context.Context, in SubscriptionInput) (*SubscriptionView, error) {
+	s, err := uc.repo.GetSubscription(ctx, in.BarbershopID, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	if err := in.apply(ctx, uc.repo, s, uc.now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveSubscription(ctx, s); err != nil {
+		return nil, err
+	}
+	view := subscriptionView(*s)
+	return &view, nil
+}
+
+// DeleteSubscription apaga a assinatura; os envios já feitos continuam no
+// histórico.
+type DeleteSubscription struct {
+	repo domain.Repository
+}
+
+func NewDeleteSubscription(repo domain.Repository) *DeleteSubscription {
+	return &DeleteSubscription{repo: repo}
+}
+
+func (uc *DeleteSubscription) Execute(ctx context.Context, barbershopID, id uint) error {
+	ok, err := uc.repo.DeleteSubscription(ctx, barbershopID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+type ListDeliveriesInput struct {
+	BarbershopID   uint
+	SubscriptionID *uint // nil = todas
+	Limit          int
+	Offset         int
+}
+
+// DeliveryView é o envio com os destinatários decodificados.
+type DeliveryView struct {
+	models.ReportDelivery
+	Recipients []string `json:"recipients"`
+}
+
+type DeliveryPage struct {
+	Total      int64          `json:"total"`
+	Deliveries []DeliveryView `json:"deliveries"`
+}
+
+// ListDeliveries é o histórico de relatórios enviados.
+type ListDeliveries struct {
+	repo domain.Repository
+}
+
+func NewListDeliveries(repo domain.Repository) *ListDeliveries {
+	return &ListDeliveries{repo: repo}
+}
+
+func (uc *ListDeliveries) Execute(ctx context.Context, in ListDeliveriesInput) (*DeliveryPage, error) {
+	list, total, err := uc.repo.ListDeliveries(ctx, in.BarbershopID, in.SubscriptionID, in.Limit, in.Offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]DeliveryView, 0, len(list))
+	for _, d := range list {
+		out = append(out, DeliveryView{ReportDelivery: d, Recipients: decodeRecipients(d.Recipients)})
+	}
+	return &DeliveryPage{Total: total, Deliveries: out}, nil
+}
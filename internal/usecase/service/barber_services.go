@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
+)
+
+// BarberService é um serviço da barbearia visto por um barbeiro: preço e
+// duração efetivos, os valores da barbearia, se ele faz o serviço e se há
+// personalização salva.
+type BarberService struct {
+	Service    *domain.Service
+	Base       *domain.Service
+	Offered    bool
+	Customized bool
+}
+
+// ======================================================
+// LIST
+// ======================================================
+
+type ListBarberServices struct {
+	repo domain.Repository
+}
+
+func NewListBarberServices(repo domain.Repository) *ListBarberServices {
+	return &ListBarberServices{repo: repo}
+}
+
+func (uc *ListBarberServices) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	barberID uint,
+) ([]BarberService, error) {
+	if barbershopID == 0 || barberID == 0 {
+		return nil, domain.ErrInvalidContext
+	}
+
+	exists, err := uc.repo.BarberExists(ctx, barbershopID, barberID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrBarberNotFound
+	}
+
+	services, err := uc.repo.ListByBarbershop(ctx, barbershopID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := uc.repo.ListBarberOverrides(ctx, barbershopID, barberID)
+	if err != nil {
+		return nil, err
+	}
+	byService := make(map[uint]*domain.BarberOverride, len(overrides))
+	for _, o := range overrides {
+		byService[o.ServiceID] = o
+	}
+
+	result := make([]BarberService, 0, len(services))
+	for _, s := range services {
+		o := byService[s.ID]
+		result = append(result, BarberService{
+			Service:    o.ApplyTo(s),
+			Base:       s,
+			Offered:    o == nil || o.Offered,
+			Customized: o != nil,
+		})
+	}
+
+	return result, nil
+}
+
+// ======================================================
+// SET
+// ======================================================
+
+type SetBarberService struct {
+	repo domain.Repository
+}
+
+func NewSetBarberService(repo domain.Repository) *SetBarberService {
+	return &SetBarberService{repo: repo}
+}
+
+type SetBarberServiceInput struct {
+	BarbershopID uint
+	BarberID     uint
+	ServiceID    uint
+
+	Offered     bool
+	Price       *int64 // nil = preço da barbearia
+	DurationMin *int   // nil = duração da barbearia
+}
+
+func (uc *SetBarberService) Execute(
+	ctx context.Context,
+	input SetBarberServiceInput,
+) (*BarberService, error) {
+	if input.BarbershopID == 0 || input.BarberID == 0 || input.ServiceID == 0 {
+		return nil, domain.ErrInvalidContext
+	}
+	if input.Price != nil && *input.Price < 0 {
+		return nil, domain.ErrInvalidPrice
+	}
+	if input.DurationMin != nil && *input.DurationMin <= 0 {
+		return nil, domain.ErrInvalidDuration
+	}
+
+	exists, err := uc.repo.BarberExists(ctx, input.BarbershopID, input.BarberID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrBarberNotFound
+	}
+
+	svc, err := uc.repo.GetByID(ctx, input.BarbershopID, input.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, domain.ErrServiceNotFound
+	}
+
+	o := &domain.BarberOverride{
+		BarbershopID: input.BarbershopID,
+		BarberID:     input.BarberID,
+		ServiceID:    input.ServiceID,
+		Offered:      input.Offered,
+		Price:        input.Price,
+		DurationMin:  input.DurationMin,
+	}
+	if err := uc.repo.UpsertBarberOverride(ctx, o); err != nil {
+		return nil, err
+	}
+
+	return &BarberService{
+		Service:    o.ApplyTo(svc),
+		Base:       svc,
+		Offered:    o.Offered,
+		Customized: true,
+	}, nil
+}
+
+// ======================================================
+// RESET
+// ======================================================
+
+// ResetBarberService remove a personalização: o barbeiro volta a atender o
+// serviço com preço e duração da barbearia.
+type ResetBarberService struct {
+	repo domain.Repository
+}
+
+func NewResetBarberService(repo domain.Repository) *ResetBarberService {
+	return &ResetBarberService{repo: repo}
+}
+
+func (uc *ResetBarberService) Execute(
+	ctx context.Context,
+	barbershopID, barberID, serviceID uint,
+) error {
+	if barbershopID == 0 || barberID == 0 || serviceID == 0 {
+		return domain.ErrInvalidContext
+	}
+
+	return uc.repo.DeleteBarberOverride(ctx, barbershopID, barberID, serviceID)
+}
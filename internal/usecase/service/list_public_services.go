@@ -20,6 +20,10 @@ type ListPublicServicesInput struct {
 	BarbershopID uint
 	Category     string
 	Query        string
+
+	// BarberID != 0 aplica o catálogo do barbeiro: preço e duração próprios
+	// e omite os serviços que ele não faz.
+	BarberID uint
 }
 
 func (uc *ListPublicServices) Execute(
@@ -30,10 +34,33 @@ func (uc *ListPublicServices) Execute(
 		return nil, domain.ErrInvalidContext
 	}
 
-	return uc.repo.ListPublicServices(
+	services, err := uc.repo.ListPublicServices(
 		ctx,
 		input.BarbershopID,
 		input.Category,
 		input.Query,
 	)
+	if err != nil || input.BarberID == 0 {
+		return services, err
+	}
+
+	overrides, err := uc.repo.ListBarberOverrides(ctx, input.BarbershopID, input.BarberID)
+	if err != nil {
+		return nil, err
+	}
+	byService := make(map[uint]*domain.BarberOverride, len(overrides))
+	for _, o := range overrides {
+		byService[o.ServiceID] = o
+	}
+
+	result := make([]*domain.Service, 0, len(services))
+	for _, s := range services {
+		o := byService[s.ID]
+		if o != nil && !o.Offered {
+			continue
+		}
+		result = append(result, o.ApplyTo(s))
+	}
+
+	return result, nil
 }
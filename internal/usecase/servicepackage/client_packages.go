@@ -0,0 +1,35 @@
+package servicepackage
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type ListClientPackages struct {
+	repo domain.Repository
+}
+
+func NewListClientPackages(repo domain.Repository) *ListClientPackages {
+	return &ListClientPackages{repo: repo}
+}
+
+func (uc *ListClientPackages) Execute(ctx context.Context, barbershopID, clientID uint) ([]models.ClientPackage, error) {
+	return uc.repo.ListClientPackages(ctx, barbershopID, clientID)
+}
+
+// ExpireClientPackages encerra os pacotes vencidos. Créditos que sobraram
+// não são devolvidos.
+type ExpireClientPackages struct {
+	repo domain.Repository
+}
+
+func NewExpireClientPackages(repo domain.Repository) *ExpireClientPackages {
+	return &ExpireClientPackages{repo: repo}
+}
+
+func (uc *ExpireClientPackages) Execute(ctx context.Context) (int64, error) {
+	return uc.repo.ExpireClientPackages(ctx, time.Now().UTC())
+}
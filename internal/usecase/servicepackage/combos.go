@@ -0,0 +1,143 @@
+package servicepackage
+
+import (
+	"context"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// ComboInput: PriceCents (preço fixo) ou DiscountPercent (desconto sobre o
+// preço cheio) — exatamente um dos dois.
+type ComboInput struct {
+	BarbershopID    uint
+	Name            string
+	PriceCents      *int64
+	DiscountPercent int
+	ServiceIDs      []uint // na ordem de execução
+}
+
+func validateCombo(ctx context.Context, repo domain.Repository, in ComboInput) (string, error) {
+	if in.BarbershopID == 0 {
+		return "", ErrInvalidBarbershop
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return "", ErrInvalidName
+	}
+
+	if in.DiscountPercent < 0 || in.DiscountPercent > 100 {
+		return "", ErrInvalidDiscount
+	}
+	if in.PriceCents != nil && *in.PriceCents <= 0 {
+		return "", ErrInvalidPrice
+	}
+	if (in.PriceCents == nil) == (in.DiscountPercent == 0) {
+		return "", ErrInvalidComboPrice
+	}
+
+	if len(in.ServiceIDs) < 2 {
+		return "", ErrComboTooFew
+	}
+	if len(in.ServiceIDs) > maxComboServices {
+		return "", ErrComboTooMany
+	}
+	if err := validateServiceIDs(ctx, repo, in.BarbershopID, in.ServiceIDs); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func comboItems(serviceIDs []uint) []models.ServiceComboItem {
+	items := make([]models.ServiceComboItem, 0, len(serviceIDs))
+	for i, id := range serviceIDs {
+		items = append(items, models.ServiceComboItem{ServiceID: id, Position: i})
+	}
+	return items
+}
+
+type CreateCombo struct {
+	repo domain.Repository
+}
+
+func NewCreateCombo(repo domain.Repository) *CreateCombo {
+	return &CreateCombo{repo: repo}
+}
+
+func (uc *CreateCombo) Execute(ctx context.Context, in ComboInput) (*models.ServiceCombo, error) {
+	name, err := validateCombo(ctx, uc.repo, in)
+	if err != nil {
+		return nil, err
+	}
+
+	combo := &models.ServiceCombo{
+		BarbershopID:    in.BarbershopID,
+		Name:            name,
+		PriceCents:      in.PriceCents,
+		DiscountPercent: in.DiscountPercent,
+		Active:          true,
+		Items:           comboItems(in.ServiceIDs),
+	}
+	if err := uc.repo.CreateCombo(ctx, combo); err != nil {
+		return nil, err
+	}
+	return combo, nil
+}
+
+// UpdateCombo altera o combo; agendamentos já feitos guardam o preço do
+// booking nas linhas e não mudam.
+type UpdateCombo struct {
+	repo domain.Repository
+}
+
+func NewUpdateCombo(repo domain.Repository) *UpdateCombo {
+	return &UpdateCombo{repo: repo}
+}
+
+func (uc *UpdateCombo) Execute(
+	ctx context.Context,
+	comboID uint,
+	active *bool, // nil = mantém
+	in ComboInput,
+) (*models.ServiceCombo, error) {
+	combo, err := uc.repo.GetCombo(ctx, in.BarbershopID, comboID)
+	if err != nil {
+		return nil, err
+	}
+	if combo == nil {
+		return nil, ErrComboNotFound
+	}
+
+	name, err := validateCombo(ctx, uc.repo, in)
+	if err != nil {
+		return nil, err
+	}
+
+	combo.Name = name
+	combo.PriceCents = in.PriceCents
+	combo.DiscountPercent = in.DiscountPercent
+	if active != nil {
+		combo.Active = *active
+	}
+	combo.Items = comboItems(in.ServiceIDs)
+
+	if err := uc.repo.UpdateCombo(ctx, combo); err != nil {
+		return nil, err
+	}
+	return combo, nil
+}
+
+type ListCombos struct {
+	repo domain.Repository
+}
+
+func NewListCombos(repo domain.Repository) *ListCombos {
+	return &ListCombos{repo: repo}
+}
+
+// Execute: onlyActive = true para a vitrine pública.
+func (uc *ListCombos) Execute(ctx context.Context, barbershopID uint, onlyActive bool) ([]models.ServiceCombo, error) {
+	return uc.repo.ListCombos(ctx, barbershopID, onlyActive)
+}
@@ -0,0 +1,19 @@
+package servicepackage
+
+import "errors"
+
+var (
+	ErrInvalidBarbershop  = errors.New("invalid_barbershop")
+	ErrInvalidName        = errors.New("invalid_name")
+	ErrInvalidPrice       = errors.New("invalid_price")
+	ErrInvalidCredits     = errors.New("invalid_credits")
+	ErrInvalidValidity    = errors.New("invalid_validity_days")
+	ErrInvalidDiscount    = errors.New("invalid_discount")
+	ErrInvalidComboPrice  = errors.New("invalid_combo_price")
+	ErrServiceIDsRequired = errors.New("service_ids_required")
+	ErrInvalidServiceIDs  = errors.New("invalid_service_ids")
+	ErrComboTooFew        = errors.New("combo_requires_two_services")
+	ErrComboTooMany       = errors.New("too_many_services")
+	ErrPackageNotFound    = errors.New("package_not_found")
+	ErrComboNotFound      = errors.New("combo_not_found")
+)
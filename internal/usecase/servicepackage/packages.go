@@ -0,0 +1,129 @@
+package servicepackage
+
+import (
+	"context"
+	"strings"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type PackageInput struct {
+	BarbershopID uint
+	Name         string
+	PriceCents   int64
+	Credits      int
+	ValidityDays int
+	ServiceIDs   []uint
+}
+
+func validatePackage(ctx context.Context, repo domain.Repository, in PackageInput) (string, error) {
+	if in.BarbershopID == 0 {
+		return "", ErrInvalidBarbershop
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return "", ErrInvalidName
+	}
+	if in.PriceCents <= 0 {
+		return "", ErrInvalidPrice
+	}
+	if in.Credits <= 0 {
+		return "", ErrInvalidCredits
+	}
+	if in.ValidityDays <= 0 {
+		return "", ErrInvalidValidity
+	}
+
+	if err := validateServiceIDs(ctx, repo, in.BarbershopID, in.ServiceIDs); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+type CreatePackage struct {
+	repo domain.Repository
+}
+
+func NewCreatePackage(repo domain.Repository) *CreatePackage {
+	return &CreatePackage{repo: repo}
+}
+
+func (uc *CreatePackage) Execute(ctx context.Context, in PackageInput) (*models.ServicePackage, error) {
+	name, err := validatePackage(ctx, uc.repo, in)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &models.ServicePackage{
+		BarbershopID: in.BarbershopID,
+		Name:         name,
+		PriceCents:   in.PriceCents,
+		Credits:      in.Credits,
+		ValidityDays: in.ValidityDays,
+		Active:       true,
+		ServiceIDs:   in.ServiceIDs,
+	}
+	if err := uc.repo.CreatePackage(ctx, pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// UpdatePackage altera o catálogo; pacotes já comprados guardam o snapshot
+// da compra e não mudam.
+type UpdatePackage struct {
+	repo domain.Repository
+}
+
+func NewUpdatePackage(repo domain.Repository) *UpdatePackage {
+	return &UpdatePackage{repo: repo}
+}
+
+func (uc *UpdatePackage) Execute(
+	ctx context.Context,
+	packageID uint,
+	active *bool, // nil = mantém
+	in PackageInput,
+) (*models.ServicePackage, error) {
+	pkg, err := uc.repo.GetPackage(ctx, in.BarbershopID, packageID)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, ErrPackageNotFound
+	}
+
+	name, err := validatePackage(ctx, uc.repo, in)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg.Name = name
+	pkg.PriceCents = in.PriceCents
+	pkg.Credits = in.Credits
+	pkg.ValidityDays = in.ValidityDays
+	if active != nil {
+		pkg.Active = *active
+	}
+	pkg.ServiceIDs = in.ServiceIDs
+
+	if err := uc.repo.UpdatePackage(ctx, pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+type ListPackages struct {
+	repo domain.Repository
+}
+
+func NewListPackages(repo domain.Repository) *ListPackages {
+	return &ListPackages{repo: repo}
+}
+
+// Execute: onlyActive = true para a vitrine pública.
+func (uc *ListPackages) Execute(ctx context.Context, barbershopID uint, onlyActive bool) ([]models.ServicePackage, error) {
+	return uc.repo.ListPackages(ctx, barbershopID, onlyActive)
+}
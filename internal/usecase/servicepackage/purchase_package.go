@@ -0,0 +1,224 @@
+package servicepackage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+type PurchasePackageInput struct {
+	BarbershopID    uint
+	PackageID       uint
+	ClientName      string
+	ClientPhone     string
+	PayerEmail      string
+	PayerCPF        string
+	PaymentMethodID string
+	Token           string // cartão — vazio para PIX
+	Installments    int
+}
+
+type PurchasePackageResult struct {
+	ClientPackageID uint
+	PaymentID       uint
+	MPPaymentID     int64
+	Status          string // "active" (card approved) ou "pending" (PIX)
+	QRCode          string
+	QRCodeBase64    string
+	TicketURL       string
+}
+
+// PurchasePackage vende um pacote pré-pago pelo mesmo fluxo de pagamento da
+// assinatura: a compra nasce pending_payment e é ativada quando o pagamento
+// é confirmado (cartão aprovado na hora, ou webhook/polling do PIX via
+// MarkMPPaymentAsPaid).
+type PurchasePackage struct {
+	repo        domain.Repository
+	paymentRepo domainPayment.Repository
+	gateway     domainPayment.TransparentGateway
+	audit       *audit.Dispatcher
+	backendURL  string
+}
+
+func NewPurchasePackage(
+	repo domain.Repository,
+	paymentRepo domainPayment.Repository,
+	gateway domainPayment.TransparentGateway,
+	audit *audit.Dispatcher,
+	backendURL string,
+) *PurchasePackage {
+	return &PurchasePackage{
+		repo:        repo,
+		paymentRepo: paymentRepo,
+		gateway:     gateway,
+		audit:       audit,
+		backendURL:  backendURL,
+	}
+}
+
+func (uc *PurchasePackage) Execute(
+	ctx context.Context,
+	in PurchasePackageInput,
+	gatewayOverride ...domainPayment.TransparentGateway,
+) (*PurchasePackageResult, error) {
+	gw := uc.gateway
+	if len(gatewayOverride) > 0 && gatewayOverride[0] != nil {
+		gw = gatewayOverride[0]
+	}
+
+	// ── 1. Valida pacote ─────────────────────────────────────────────────
+	pkg, err := uc.repo.GetPackage(ctx, in.BarbershopID, in.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil || !pkg.Active {
+		return nil, apperr.ErrBusiness("package_not_found")
+	}
+
+	// ── 2. Encontra ou cria cliente ───────────────────────────────────────
+	client, err := uc.repo.FindOrCreateClient(ctx, in.BarbershopID, in.ClientName, in.ClientPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	// ── 3. Cria a compra pending_payment com o snapshot do catálogo ──────
+	cp := &models.ClientPackage{
+		BarbershopID: in.BarbershopID,
+		ClientID:     client.ID,
+		PackageID:    pkg.ID,
+		Status:       models.ClientPackagePendingPayment,
+		PackageName:  pkg.Name,
+		PriceCents:   pkg.PriceCents,
+		CreditsTotal: pkg.Credits,
+		ValidityDays: pkg.ValidityDays,
+	}
+	if err := uc.repo.CreateClientPackage(ctx, cp); err != nil {
+		return nil, err
+	}
+
+	// ── 4. Cria payment pendente vinculado à compra ───────────────────────
+	now := time.Now().UTC()
+	txID := fmt.Sprintf("pkg_pending:%d:%d", cp.ID, now.UnixMilli())
+
+	// Notification URL: omitida em ambiente local (provider rejeita URLs não públicas).
+	notifURL := ""
+	if !strings.Contains(uc.backendURL, "localhost") && !strings.Contains(uc.backendURL, "127.0.0.1") {
+		type webhookPather interface{ WebhookPath() string }
+		webhookPath := "/api/webhooks/mp"
+		if wp, ok := gw.(webhookPather); ok {
+			webhookPath = wp.WebhookPath()
+		}
+		notifURL = strings.TrimRight(uc.backendURL, "/") + webhookPath
+	}
+
+	payment := &models.Payment{
+		BarbershopID:    in.BarbershopID,
+		ClientPackageID: &cp.ID,
+		Amount:          pkg.PriceCents,
+		Status:          models.PaymentStatus(domainPayment.StatusPending),
+		TxID:            &txID,
+	}
+	if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	// ── 5. Chama gateway ──────────────────────────────────────────────────
+	result, err := gw.CreatePayment(domainPayment.TransparentPaymentInput{
+		AmountCents:       pkg.PriceCents,
+		Description:       "Pacote " + pkg.Name,
+		ExternalReference: fmt.Sprintf("%d", payment.ID),
+		NotificationURL:   notifURL,
+		PayerEmail:        in.PayerEmail,
+		PayerCPF:          in.PayerCPF,
+		PaymentMethodID:   in.PaymentMethodID,
+		Token:             in.Token,
+		Installments:      in.Installments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gateway error: %w", err)
+	}
+
+	type providerNamer interface{ ProviderName() string }
+	if pn, ok := gw.(providerNamer); ok {
+		name := pn.ProviderName()
+		payment.Provider = &name
+	}
+	rawID := ""
+	if result.ProviderPaymentID != "" {
+		rawID = strings.TrimPrefix(result.ProviderPaymentID, "mp_pay:")
+	} else if result.MPPaymentID != 0 {
+		rawID = strconv.FormatInt(result.MPPaymentID, 10)
+	}
+	if rawID != "" {
+		payment.ProviderPaymentID = &rawID
+	}
+
+	// ── 6. Resultado por status ───────────────────────────────────────────
+	switch result.Status {
+	case "approved":
+		paidAt := time.Now().UTC()
+		payment.Status = models.PaymentStatus(domainPayment.StatusPaid)
+		payment.PaidAt = &paidAt
+		if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+			return nil, fmt.Errorf("failed to update payment: %w", err)
+		}
+
+		if err := uc.repo.ActivateClientPackage(ctx, cp.ID, paidAt); err != nil {
+			return nil, fmt.Errorf("failed to activate package: %w", err)
+		}
+
+		if uc.audit != nil {
+			uc.audit.Dispatch(audit.Event{
+				BarbershopID: in.BarbershopID,
+				Action:       "client_package_activated",
+				Entity:       "client_package",
+				EntityID:     &cp.ID,
+				Metadata: map[string]any{
+					"package_id": pkg.ID,
+					"client_id":  client.ID,
+					"via":        "card_immediate",
+				},
+			})
+		}
+
+		return &PurchasePackageResult{
+			ClientPackageID: cp.ID,
+			PaymentID:       payment.ID,
+			MPPaymentID:     result.MPPaymentID,
+			Status:          "active",
+		}, nil
+
+	case "rejected":
+		// Recusado: a compra fica pending_payment; o cliente pode tentar de novo.
+		payment.Status = models.PaymentStatus(domainPayment.StatusExpired)
+		_ = uc.paymentRepo.Update(ctx, payment)
+		return nil, apperr.ErrBusiness("payment_rejected")
+
+	default:
+		// PIX ou in_process — retorna dados para o cliente aguardar
+		qrCode := result.QRCode
+		payment.QRCode = &qrCode
+		if result.MPPaymentID != 0 {
+			payment.MPPaymentID = &result.MPPaymentID
+		}
+		_ = uc.paymentRepo.Update(ctx, payment)
+
+		return &PurchasePackageResult{
+			ClientPackageID: cp.ID,
+			PaymentID:       payment.ID,
+			MPPaymentID:     result.MPPaymentID,
+			Status:          "pending",
+			QRCode:          result.QRCode,
+			QRCodeBase64:    result.QRCodeBase64,
+			TicketURL:       result.TicketURL,
+		}, nil
+	}
+}
@@ -0,0 +1,39 @@
+package servicepackage
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+)
+
+// maxComboServices acompanha o limite de serviços por agendamento.
+const maxComboServices = 5
+
+// validateServiceIDs exige serviços não repetidos, todos da barbearia.
+func validateServiceIDs(
+	ctx context.Context,
+	repo domain.Repository,
+	barbershopID uint,
+	serviceIDs []uint,
+) error {
+	if len(serviceIDs) == 0 {
+		return ErrServiceIDsRequired
+	}
+
+	seen := make(map[uint]bool, len(serviceIDs))
+	for _, id := range serviceIDs {
+		if id == 0 || seen[id] {
+			return ErrInvalidServiceIDs
+		}
+		seen[id] = true
+	}
+
+	count, err := repo.CountServices(ctx, barbershopID, serviceIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(serviceIDs)) {
+		return ErrInvalidServiceIDs
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package servicepackage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/servicepackage"
+)
+
+// countingRepo responde só CountServices: existem os serviços 1 a 5.
+type countingRepo struct {
+	domain.Repository
+}
+
+func (countingRepo) CountServices(_ context.Context, _ uint, serviceIDs []uint) (int64, error) {
+	var n int64
+	for _, id := range serviceIDs {
+		if id >= 1 && id <= 5 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestValidatePackage(t *testing.T) {
+	ctx := context.Background()
+	valid := PackageInput{
+		BarbershopID: 1,
+		Name:         "10 cortes",
+		PriceCents:   40000,
+		Credits:      10,
+		ValidityDays: 90,
+		ServiceIDs:   []uint{1},
+	}
+
+	cases := []struct {
+		name    string
+		mutate  func(in *PackageInput)
+		wantErr error
+	}{
+		{"válido", func(in *PackageInput) {}, nil},
+		{"nome vazio", func(in *PackageInput) { in.Name = "  " }, ErrInvalidName},
+		{"preço zero", func(in *PackageInput) { in.PriceCents = 0 }, ErrInvalidPrice},
+		{"sem créditos", func(in *PackageInput) { in.Credits = 0 }, ErrInvalidCredits},
+		{"sem validade", func(in *PackageInput) { in.ValidityDays = 0 }, ErrInvalidValidity},
+		{"sem serviços", func(in *PackageInput) { in.ServiceIDs = nil }, ErrServiceIDsRequired},
+		{"serviço repetido", func(in *PackageInput) { in.ServiceIDs = []uint{1, 1} }, ErrInvalidServiceIDs},
+		{"serviço de outra barbearia", func(in *PackageInput) { in.ServiceIDs = []uint{1, 99} }, ErrInvalidServiceIDs},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := valid
+			in.ServiceIDs = append([]uint(nil), valid.ServiceIDs...)
+			tc.mutate(&in)
+
+			_, err := validatePackage(ctx, countingRepo{}, in)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("esperado %v, obtido %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCombo(t *testing.T) {
+	ctx := context.Background()
+	price := int64(7000)
+	zero := int64(0)
+
+	cases := []struct {
+		name    string
+		in      ComboInput
+		wantErr error
+	}{
+		{"preço fixo", ComboInput{PriceCents: &price, ServiceIDs: []uint{1, 2}}, nil},
+		{"desconto", ComboInput{DiscountPercent: 15, ServiceIDs: []uint{1, 2, 3}}, nil},
+		{"preço e desconto juntos", ComboInput{PriceCents: &price, DiscountPercent: 15, ServiceIDs: []uint{1, 2}}, ErrInvalidComboPrice},
+		{"nem preço nem desconto", ComboInput{ServiceIDs: []uint{1, 2}}, ErrInvalidComboPrice},
+		{"preço zero", ComboInput{PriceCents: &zero, ServiceIDs: []uint{1, 2}}, ErrInvalidPrice},
+		{"desconto acima de 100", ComboInput{DiscountPercent: 101, ServiceIDs: []uint{1, 2}}, ErrInvalidDiscount},
+		{"um serviço só", ComboInput{DiscountPercent: 10, ServiceIDs: []uint{1}}, ErrComboTooFew},
+		{"serviços demais", ComboInput{DiscountPercent: 10, ServiceIDs: []uint{1, 2, 3, 4, 5, 6}}, ErrComboTooMany},
+		{"serviço repetido", ComboInput{DiscountPercent: 10, ServiceIDs: []uint{1, 1}}, ErrInvalidServiceIDs},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := tc.in
+			in.BarbershopID = 1
+			in.Name = "Corte + Barba"
+
+			_, err := validateCombo(ctx, countingRepo{}, in)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("esperado %v, obtido %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ var (
 	ErrServiceIDsRequired                 = errors.New("service_ids_required")
 	ErrInvalidServiceID                   = errors.New("invalid_service_id")
 	ErrInvalidServiceIDs                  = errors.New("invalid_service_ids")
+	ErrCardNotStored                      = errors.New("card_not_stored")
 
 	ErrActivateSubscriptionInvalidInput              = ErrInvalidInput
 	ErrActivateSubscriptionPlanNotFound              = ErrPlanNotFound
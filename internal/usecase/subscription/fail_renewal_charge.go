@@ -0,0 +1,127 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
+)
+
+// FailRenewalCharge registra a recusa de uma cobrança de renovação: expira o
+// pagamento, conta a tentativa, agenda a próxima pelo dunning
+// (RenewalRetryDelays) e avisa o cliente. Chamado pelo job na recusa imediata
+// e pelos webhooks do MP e do PagBank.
+type FailRenewalCharge struct {
+	renewals    domain.RenewalRepository
+	paymentRepo domainPayment.Repository
+	audit       *audit.Dispatcher
+	email       domainNotification.SubscriptionRenewalNotifier
+	whatsapp    domainNotification.SubscriptionRenewalNotifier
+}
+
+// NewFailRenewalCharge cria o use case. email e whatsapp podem ser nil quando
+// o canal não está configurado.
+func NewFailRenewalCharge(
+	renewals domain.RenewalRepository,
+	paymentRepo domainPayment.Repository,
+	audit *audit.Dispatcher,
+	email domainNotification.SubscriptionRenewalNotifier,
+	whatsapp domainNotification.SubscriptionRenewalNotifier,
+) *FailRenewalCharge {
+	return &FailRenewalCharge{
+		renewals:    renewals,
+		paymentRepo: paymentRepo,
+		audit:       audit,
+		email:       email,
+		whatsapp:    whatsapp,
+	}
+}
+
+// Execute recebe o external_reference do provider (nosso payment ID).
+// Pagamentos que não são cobrança de renovação, ou já resolvidos, são
+// ignorados — o webhook chama para toda recusa.
+func (uc *FailRenewalCharge) Execute(ctx context.Context, externalReference, detail string) error {
+	paymentID, err := strconv.ParseUint(externalReference, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	payment, err := uc.paymentRepo.GetByIDGlobal(ctx, uint(paymentID))
+	if err != nil {
+		return fmt.Errorf("failed to load payment: %w", err)
+	}
+	if payment == nil || !payment.SubscriptionRenewal || payment.SubscriptionID == nil {
+		return nil
+	}
+	if domainPayment.Status(payment.Status) != domainPayment.StatusPending {
+		return nil
+	}
+
+	r, err := uc.renewals.GetRenewal(ctx, *payment.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to load renewal: %w", err)
+	}
+	if r == nil {
+		// Sem cartão guardado: só encerra o pagamento.
+		_, err := uc.renewals.FailRenewalPayment(ctx, payment.ID)
+		return err
+	}
+
+	return uc.register(ctx, payment.ID, r, detail)
+}
+
+// register expira o pagamento, agenda a próxima tentativa e avisa o cliente.
+func (uc *FailRenewalCharge) register(
+	ctx context.Context,
+	paymentID uint,
+	r *domain.Renewal,
+	detail string,
+) error {
+	failed, err := uc.renewals.FailRenewalPayment(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to expire renewal payment: %w", err)
+	}
+	if !failed {
+		return nil // outra notificação já registrou a falha
+	}
+
+	sub := r.Subscription
+	now := time.Now().UTC()
+	attempts := sub.RenewalAttempts + 1
+	deadline := sub.RenewalDeadline()
+	next := domain.NextRenewalAttempt(attempts, now, deadline)
+
+	if err := uc.renewals.RecordRenewalFailure(ctx, sub.ID, attempts, next); err != nil {
+		return fmt.Errorf("failed to record renewal failure: %w", err)
+	}
+
+	if uc.audit != nil {
+		meta := map[string]any{
+			"payment_id": paymentID,
+			"attempts":   attempts,
+			"detail":     detail,
+		}
+		if next != nil {
+			meta["next_attempt_at"] = next.Format(time.RFC3339)
+		}
+		uc.audit.Dispatch(audit.Event{
+			BarbershopID: sub.BarbershopID,
+			Action:       "subscription_renewal_failed",
+			Entity:       "subscription",
+			EntityID:     &sub.ID,
+			Metadata:     meta,
+		})
+	}
+
+	log.Printf("[SubscriptionRenewal] subscription=%d payment=%d attempts=%d detail=%q",
+		sub.ID, paymentID, attempts, detail)
+
+	notifyRenewal(ctx, uc.email, uc.whatsapp, r, true, deadline, next)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -11,10 +12,12 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	couponDomain "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
 	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
 	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
 	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucCoupon "github.com/BruksfildServices01/barber-scheduler/internal/usecase/coupon"
 )
 
 type PurchaseSubscriptionInput struct {
@@ -27,6 +30,12 @@ type PurchaseSubscriptionInput struct {
 	PaymentMethodID string
 	Token           string // cartão — vazio para PIX
 	Installments    int
+	// AutoRenew guarda o cartão para a renovação automática. Só vale para
+	// cartão aprovado na hora em provider com cofre (RecurringGateway).
+	AutoRenew bool
+	// CouponCode dá desconto só na primeira cobrança; as renovações cobram
+	// o preço do plano.
+	CouponCode string
 }
 
 type PurchaseSubscriptionResult struct {
@@ -34,6 +43,7 @@ type PurchaseSubscriptionResult struct {
 	PaymentID      uint
 	MPPaymentID    int64
 	Status         string // "active" (card approved) ou "pending" (PIX)
+	AutoRenew      bool   // cartão guardado para a renovação automática
 	QRCode         string
 	QRCodeBase64   string
 	TicketURL      string
@@ -46,6 +56,8 @@ type PurchaseSubscription struct {
 	audit            *audit.Dispatcher
 	db               *gorm.DB
 	backendURL       string
+	renewals         domain.RenewalRepository
+	coupons          *ucCoupon.Applier
 }
 
 func NewPurchaseSubscription(
@@ -66,6 +78,18 @@ func NewPurchaseSubscription(
 	}
 }
 
+// WithRenewals habilita a renovação automática (AutoRenew na compra).
+func (uc *PurchaseSubscription) WithRenewals(repo domain.RenewalRepository) *PurchaseSubscription {
+	uc.renewals = repo
+	return uc
+}
+
+// WithCoupons habilita CouponCode na compra.
+func (uc *PurchaseSubscription) WithCoupons(a *ucCoupon.Applier) *PurchaseSubscription {
+	uc.coupons = a
+	return uc
+}
+
 func (uc *PurchaseSubscription) Execute(
 	ctx context.Context,
 	in PurchaseSubscriptionInput,
@@ -91,6 +115,27 @@ func (uc *PurchaseSubscription) Execute(
 		return nil, err
 	}
 
+	// ── 2b. Cupom (só na primeira cobrança) ───────────────────────────────
+	amountCents := plan.MonthlyPriceCents
+	var quote *ucCoupon.Quote
+	if code := strings.TrimSpace(in.CouponCode); code != "" {
+		if uc.coupons == nil {
+			return nil, couponDomain.ErrNotFound
+		}
+		quote, err = uc.coupons.Quote(ctx, in.BarbershopID, code, &client.ID, couponDomain.Purchase{
+			Kind:  models.CouponAppliesToPlans,
+			Lines: []couponDomain.Line{{ItemID: plan.ID, AmountCents: plan.MonthlyPriceCents}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// A assinatura só ativa pelo pagamento: a cobrança não pode zerar.
+		if quote.DiscountCents >= amountCents {
+			return nil, couponDomain.ErrNotApplicable
+		}
+		amountCents -= quote.DiscountCents
+	}
+
 	// ── 3. Cria subscription pending_payment ──────────────────────────────
 	sub := &domain.Subscription{
 		BarbershopID: in.BarbershopID,
@@ -107,36 +152,51 @@ func (uc *PurchaseSubscription) Execute(
 		return nil, err
 	}
 
+	// O uso do cupom é consumido com a assinatura criada e devolvido se a
+	// cobrança não sair.
+	var redemption *models.CouponRedemption
+	if quote != nil {
+		redemption = &models.CouponRedemption{
+			ClientID:       &client.ID,
+			SubscriptionID: &sub.ID,
+			DiscountCents:  quote.DiscountCents,
+		}
+		if err := uc.coupons.Redeem(ctx, quote.Coupon, redemption); err != nil {
+			return nil, err
+		}
+	}
+	releaseCoupon := func() {
+		if redemption == nil {
+			return
+		}
+		if err := uc.coupons.Release(ctx, redemption.ID); err != nil {
+			log.Printf("[PurchaseSubscription] subscription=%d release_coupon_error=%v", sub.ID, err)
+		}
+	}
+
 	// ── 4. Cria payment pendente vinculado à subscription ─────────────────
 	now := time.Now().UTC()
 	txID := fmt.Sprintf("sub_pending:%d:%d", sub.ID, now.UnixMilli())
 
 	// Notification URL: usa o path correto para o provider em uso.
 	// Omitida em ambiente local (provider rejeita URLs não públicas).
-	notifURL := ""
-	if !strings.Contains(uc.backendURL, "localhost") && !strings.Contains(uc.backendURL, "127.0.0.1") {
-		type webhookPather interface{ WebhookPath() string }
-		webhookPath := "/api/webhooks/mp"
-		if wp, ok := gw.(webhookPather); ok {
-			webhookPath = wp.WebhookPath()
-		}
-		notifURL = strings.TrimRight(uc.backendURL, "/") + webhookPath
-	}
+	notifURL := notificationURL(uc.backendURL, gw)
 
 	payment := &models.Payment{
 		BarbershopID:   in.BarbershopID,
 		SubscriptionID: &sub.ID,
-		Amount:         plan.MonthlyPriceCents,
+		Amount:         amountCents,
 		Status:         models.PaymentStatus(domainPayment.StatusPending),
 		TxID:           &txID,
 	}
 	if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+		releaseCoupon()
 		return nil, fmt.Errorf("failed to create payment: %w", err)
 	}
 
 	// ── 5. Chama gateway ──────────────────────────────────────────────────
 	result, err := gw.CreatePayment(domainPayment.TransparentPaymentInput{
-		AmountCents:       plan.MonthlyPriceCents,
+		AmountCents:       amountCents,
 		Description:       "Assinatura " + plan.Name,
 		ExternalReference: fmt.Sprintf("%d", payment.ID),
 		NotificationURL:   notifURL,
@@ -147,6 +207,7 @@ func (uc *PurchaseSubscription) Execute(
 		Installments:      in.Installments,
 	})
 	if err != nil {
+		releaseCoupon()
 		return nil, fmt.Errorf("gateway error: %w", err)
 	}
 
@@ -197,11 +258,17 @@ func (uc *PurchaseSubscription) Execute(
 			},
 		})
 
+		autoRenew := false
+		if in.AutoRenew {
+			autoRenew = uc.storeCard(ctx, gw, sub.ID, in)
+		}
+
 		return &PurchaseSubscriptionResult{
 			SubscriptionID: sub.ID,
 			PaymentID:      payment.ID,
 			MPPaymentID:    result.MPPaymentID,
 			Status:         "active",
+			AutoRenew:      autoRenew,
 		}, nil
 
 	case "rejected":
@@ -209,6 +276,7 @@ func (uc *PurchaseSubscription) Execute(
 		// (será limpa pelo job de expiração ou pode tentar novamente)
 		payment.Status = models.PaymentStatus(domainPayment.StatusExpired)
 		_ = uc.paymentRepo.Update(ctx, payment)
+		releaseCoupon()
 		return nil, apperr.ErrBusiness("payment_rejected")
 
 	default:
@@ -232,6 +300,61 @@ func (uc *PurchaseSubscription) Execute(
 	}
 }
 
+// storeCard guarda no cofre do provider o cartão da compra aprovada e liga a
+// renovação automática. Falhas não desfazem a compra: a assinatura segue
+// ativa, sem renovação automática.
+func (uc *PurchaseSubscription) storeCard(
+	ctx context.Context,
+	gw domainPayment.TransparentGateway,
+	subscriptionID uint,
+	in PurchaseSubscriptionInput,
+) bool {
+	if uc.renewals == nil || in.Token == "" {
+		return false
+	}
+	recurring, ok := gw.(domainPayment.RecurringGateway)
+	if !ok {
+		return false
+	}
+	type providerNamer interface{ ProviderName() string }
+	pn, ok := gw.(providerNamer)
+	if !ok {
+		return false
+	}
+
+	stored, err := recurring.StoreCard(ctx, domainPayment.StoreCardInput{
+		PayerEmail:      in.PayerEmail,
+		PayerCPF:        in.PayerCPF,
+		CardToken:       in.Token,
+		PaymentMethodID: in.PaymentMethodID,
+	})
+	if err != nil {
+		log.Printf("[PurchaseSubscription] subscription=%d store_card_error=%v", subscriptionID, err)
+		return false
+	}
+
+	card := &models.SubscriptionCard{
+		BarbershopID:   in.BarbershopID,
+		SubscriptionID: subscriptionID,
+		Provider:       pn.ProviderName(),
+		CustomerRef:    stored.CustomerRef,
+		CardRef:        stored.CardRef,
+		Brand:          stored.Brand,
+		LastFour:       stored.LastFour,
+		PayerEmail:     in.PayerEmail,
+		PayerCPF:       in.PayerCPF,
+	}
+	if err := uc.renewals.SaveCard(ctx, card); err != nil {
+		log.Printf("[PurchaseSubscription] subscription=%d save_card_error=%v", subscriptionID, err)
+		return false
+	}
+	if err := uc.renewals.SetAutoRenew(ctx, subscriptionID, true); err != nil {
+		log.Printf("[PurchaseSubscription] subscription=%d auto_renew_error=%v", subscriptionID, err)
+		return false
+	}
+	return true
+}
+
 // findOrCreateClient encontra o cliente por telefone na barbearia ou cria um novo.
 func (uc *PurchaseSubscription) findOrCreateClient(
 	ctx context.Context,
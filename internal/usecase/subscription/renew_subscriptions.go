@@ -0,0 +1,260 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// renewalBatchSize limita as cobranças por execução do job.
+const renewalBatchSize = 100
+
+// renewalPaymentTTL: cobrança de renovação que não for confirmada nesse prazo
+// expira pelo job de pagamentos e volta a ser tentada na execução seguinte.
+const renewalPaymentTTL = 24 * time.Hour
+
+// gatewayResolver resolve o gateway do provider em que o cartão foi guardado
+// (satisfeito por payment.ProviderRegistry).
+type gatewayResolver interface {
+	GatewayForProvider(ctx context.Context, barbershopID uint, providerName string) (domainPayment.TransparentGateway, error)
+}
+
+// paymentConfirmer confirma a cobrança aprovada na hora pelo mesmo caminho do
+// webhook (satisfeito por payment.MarkMPPaymentAsPaid), que aplica a renovação.
+type paymentConfirmer interface {
+	Execute(ctx context.Context, externalReference string, providerPaymentID string) error
+}
+
+// RenewSubscriptions cobra no cartão guardado as assinaturas com renovação
+// automática: a partir de RenewalLeadTime antes do fim do período e, em
+// carência, nas datas do dunning. Aprovação confirma o pagamento e renova;
+// recusa segue por FailRenewalCharge; pendente aguarda o webhook.
+//
+// Também implementa domain.RenewalListener para avisar o cliente quando a
+// renovação é confirmada (na hora ou pelo webhook).
+type RenewSubscriptions struct {
+	renewals    domain.RenewalRepository
+	paymentRepo domainPayment.Repository
+	gateways    gatewayResolver
+	confirm     paymentConfirmer
+	fail        *FailRenewalCharge
+	email       domainNotification.SubscriptionRenewalNotifier
+	whatsapp    domainNotification.SubscriptionRenewalNotifier
+	backendURL  string
+}
+
+// NewRenewSubscriptions cria o use case. email e whatsapp podem ser nil
+// quando o canal não está configurado.
+func NewRenewSubscriptions(
+	renewals domain.RenewalRepository,
+	paymentRepo domainPayment.Repository,
+	gateways gatewayResolver,
+	fail *FailRenewalCharge,
+	email domainNotification.SubscriptionRenewalNotifier,
+	whatsapp domainNotification.SubscriptionRenewalNotifier,
+	backendURL string,
+) *RenewSubscriptions {
+	return &RenewSubscriptions{
+		renewals:    renewals,
+		paymentRepo: paymentRepo,
+		gateways:    gateways,
+		fail:        fail,
+		email:       email,
+		whatsapp:    whatsapp,
+		backendURL:  backendURL,
+	}
+}
+
+// WithConfirmer define quem confirma as cobranças aprovadas na hora. Fica
+// fora do construtor porque o confirmador recebe este use case como listener.
+func (uc *RenewSubscriptions) WithConfirmer(c paymentConfirmer) *RenewSubscriptions {
+	uc.confirm = c
+	return uc
+}
+
+// Execute retorna quantas cobranças foram criadas.
+func (uc *RenewSubscriptions) Execute(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	due, err := uc.renewals.ListDueRenewals(ctx, now, renewalBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	for i := range due {
+		ok, err := uc.charge(ctx, &due[i], now)
+		if err != nil {
+			log.Printf("[RenewSubscriptions] subscription=%d error=%v", due[i].Subscription.ID, err)
+			continue
+		}
+		if ok {
+			charged++
+		}
+	}
+	return charged, nil
+}
+
+func (uc *RenewSubscriptions) charge(ctx context.Context, r *domain.Renewal, now time.Time) (bool, error) {
+	sub := r.Subscription
+	if sub.Plan == nil {
+		return false, fmt.Errorf("plan %d not found", sub.PlanID)
+	}
+
+	gw, err := uc.gateways.GatewayForProvider(ctx, sub.BarbershopID, r.Card.Provider)
+	if err != nil {
+		return false, fmt.Errorf("gateway %s: %w", r.Card.Provider, err)
+	}
+	recurring, ok := gw.(domainPayment.RecurringGateway)
+	if !ok {
+		return false, fmt.Errorf("provider %s does not support stored cards", r.Card.Provider)
+	}
+
+	txID := fmt.Sprintf("sub_renewal:%d:%d", sub.ID, now.UnixMilli())
+	expiresAt := now.Add(renewalPaymentTTL)
+	provider := r.Card.Provider
+	payment := &models.Payment{
+		BarbershopID:        sub.BarbershopID,
+		SubscriptionID:      &sub.ID,
+		SubscriptionRenewal: true,
+		Amount:              sub.Plan.MonthlyPriceCents,
+		Status:              models.PaymentStatus(domainPayment.StatusPending),
+		TxID:                &txID,
+		Provider:            &provider,
+		ExpiresAt:           &expiresAt,
+	}
+	if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+		return false, fmt.Errorf("create payment: %w", err)
+	}
+	externalRef := strconv.FormatUint(uint64(payment.ID), 10)
+
+	result, err := recurring.ChargeStoredCard(ctx, domainPayment.StoredCardChargeInput{
+		AmountCents:       payment.Amount,
+		Description:       "Renovação assinatura " + sub.Plan.Name,
+		ExternalReference: externalRef,
+		NotificationURL:   notificationURL(uc.backendURL, gw),
+		PayerEmail:        r.Card.PayerEmail,
+		PayerCPF:          r.Card.PayerCPF,
+		Card: domainPayment.StoredCard{
+			CustomerRef: r.Card.CustomerRef,
+			CardRef:     r.Card.CardRef,
+			Brand:       r.Card.Brand,
+			LastFour:    r.Card.LastFour,
+		},
+	})
+	if err != nil {
+		// Falha técnica (rede, provider fora): o provider pode ter criado a
+		// cobrança mesmo assim. O pagamento fica pendente para um webhook
+		// tardio confirmar; sem confirmação, expira em renewalPaymentTTL e a
+		// renovação é tentada de novo, sem contar como recusa.
+		return true, fmt.Errorf("charge stored card: %w", err)
+	}
+
+	if result.ProviderPaymentID != "" {
+		payment.ProviderPaymentID = &result.ProviderPaymentID
+		if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+			return true, fmt.Errorf("update payment: %w", err)
+		}
+	}
+
+	switch result.Status {
+	case domainPayment.ProviderStatusApproved:
+		if uc.confirm == nil {
+			return true, fmt.Errorf("no confirmer configured for approved renewal")
+		}
+		providerRef := result.ProviderPaymentID
+		if providerRef == "" {
+			providerRef = txID
+		}
+		if err := uc.confirm.Execute(ctx, externalRef, providerRef); err != nil {
+			return true, fmt.Errorf("confirm renewal: %w", err)
+		}
+	case domainPayment.ProviderStatusRejected, domainPayment.ProviderStatusCancelled:
+		if err := uc.fail.register(ctx, payment.ID, r, result.StatusDetail); err != nil {
+			return true, err
+		}
+	default:
+		// in_process/pending: o webhook decide.
+	}
+	return true, nil
+}
+
+// SubscriptionRenewed implementa domain.RenewalListener: avisa o cliente da
+// renovação confirmada. Erros são apenas logados.
+func (uc *RenewSubscriptions) SubscriptionRenewed(ctx context.Context, subscriptionID uint) {
+	r, err := uc.renewals.GetRenewal(ctx, subscriptionID)
+	if err != nil || r == nil {
+		log.Printf("[RenewSubscriptions] subscription=%d load_renewal_error=%v", subscriptionID, err)
+		return
+	}
+
+	validUntil := r.Subscription.CurrentPeriodEnd
+	if r.Subscription.RenewedPeriodEnd != nil {
+		validUntil = *r.Subscription.RenewedPeriodEnd
+	}
+	notifyRenewal(ctx, uc.email, uc.whatsapp, r, false, validUntil, nil)
+}
+
+// notificationURL monta a URL de webhook do provider do gateway. Vazia em
+// ambiente local: os providers rejeitam URLs não públicas.
+func notificationURL(backendURL string, gw domainPayment.TransparentGateway) string {
+	if strings.Contains(backendURL, "localhost") || strings.Contains(backendURL, "127.0.0.1") {
+		return ""
+	}
+	type webhookPather interface{ WebhookPath() string }
+	webhookPath := "/api/webhooks/mp"
+	if wp, ok := gw.(webhookPather); ok {
+		webhookPath = wp.WebhookPath()
+	}
+	return strings.TrimRight(backendURL, "/") + webhookPath
+}
+
+// notifyRenewal avisa o cliente pelos canais configurados; erros de envio são
+// apenas logados.
+func notifyRenewal(
+	ctx context.Context,
+	email, whatsapp domainNotification.SubscriptionRenewalNotifier,
+	r *domain.Renewal,
+	failed bool,
+	validUntil time.Time,
+	nextAttemptAt *time.Time,
+) {
+	input := domainNotification.SubscriptionRenewalInput{
+		BarbershopID:   r.Subscription.BarbershopID,
+		ClientName:     r.ClientName,
+		ClientEmail:    r.ClientEmail,
+		ClientPhone:    r.ClientPhone,
+		BarbershopName: r.BarbershopName,
+		CardLastFour:   r.Card.LastFour,
+		Failed:         failed,
+		ValidUntil:     validUntil,
+		NextAttemptAt:  nextAttemptAt,
+		Timezone:       r.Timezone,
+	}
+	if input.ClientEmail == "" {
+		input.ClientEmail = r.Card.PayerEmail
+	}
+	if plan := r.Subscription.Plan; plan != nil {
+		input.PlanName = plan.Name
+		input.AmountCents = plan.MonthlyPriceCents
+	}
+
+	if whatsapp != nil && input.ClientPhone != "" {
+		if err := whatsapp.NotifySubscriptionRenewal(ctx, input); err != nil {
+			log.Printf("[SubscriptionRenewal] subscription=%d channel=whatsapp send_error=%v", r.Subscription.ID, err)
+		}
+	}
+	if email != nil && input.ClientEmail != "" {
+		if err := email.NotifySubscriptionRenewal(ctx, input); err != nil {
+			log.Printf("[SubscriptionRenewal] subscription=%d channel=email send_error=%v", r.Subscription.ID, err)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domainPayment "github.com/BruksfildServices01/barber-scheduler/internal/domain/payment"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// ── stubs ─────────────────────────────────────────────────────────────────────────
+
+type stubRenewalRepo struct {
+	due      []domain.Renewal
+	failed   map[uint]bool // pagamentos já expirados
+	attempts int
+	next     *time.Time
+	recorded bool
+}
+
+func (r *stubRenewalRepo) SaveCard(context.Context, *models.SubscriptionCard) error { return nil }
+func (r *stubRenewalRepo) GetCard(context.Context, uint) (*models.SubscriptionCard, error) {
+	return nil, nil
+}
+func (r *stubRenewalRepo) SetAutoRenew(context.Context, uint, bool) error { return nil }
+func (r *stubRenewalRepo) FindRenewableSubscriptionID(context.Context, uint, uint) (uint, error) {
+	return 0, nil
+}
+func (r *stubRenewalRepo) ListDueRenewals(context.Context, time.Time, int) ([]domain.Renewal, error) {
+	return r.due, nil
+}
+func (r *stubRenewalRepo) GetRenewal(_ context.Context, id uint) (*domain.Renewal, error) {
+	for i := range r.due {
+		if r.due[i].Subscription.ID == id {
+			return &r.due[i], nil
+		}
+	}
+	return nil, nil
+}
+func (r *stubRenewalRepo) FailRenewalPayment(_ context.Context, paymentID uint) (bool, error) {
+	if r.failed == nil {
+		r.failed = map[uint]bool{}
+	}
+	if r.failed[paymentID] {
+		return false, nil
+	}
+	r.failed[paymentID] = true
+	return true, nil
+}
+func (r *stubRenewalRepo) RecordRenewalFailure(_ context.Context, _ uint, attempts int, next *time.Time) error {
+	r.recorded = true
+	r.attempts = attempts
+	r.next = next
+	return nil
+}
+
+// stubRenewalPaymentRepo implementa só o que a renovação usa.
+type stubRenewalPaymentRepo struct {
+	domainPayment.Repository
+	created []*models.Payment
+}
+
+func (r *stubRenewalPaymentRepo) Create(_ context.Context, p *models.Payment) error {
+	p.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, p)
+	return nil
+}
+func (r *stubRenewalPaymentRepo) Update(context.Context, *models.Payment) error { return nil }
+func (r *stubRenewalPaymentRepo) GetByIDGlobal(_ context.Context, id uint) (*models.Payment, error) {
+	for _, p := range r.created {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+type stubRecurringGateway struct {
+	stubGatewayNoExtras
+	result *domainPayment.CardPaymentResult
+	err    error
+	input  domainPayment.StoredCardChargeInput
+}
+
+func (g *stubRecurringGateway) StoreCard(context.Context, domainPayment.StoreCardInput) (*domainPayment.StoredCard, error) {
+	return &domainPayment.StoredCard{}, nil
+}
+func (g *stubRecurringGateway) ChargeStoredCard(_ context.Context, in domainPayment.StoredCardChargeInput) (*domainPayment.CardPaymentResult, error) {
+	g.input = in
+	return g.result, g.err
+}
+
+type stubGatewayResolver struct {
+	gw domainPayment.TransparentGateway
+}
+
+func (r stubGatewayResolver) GatewayForProvider(context.Context, uint, string) (domainPayment.TransparentGateway, error) {
+	return r.gw, nil
+}
+
+type stubConfirmer struct{ extRefs []string }
+
+func (c *stubConfirmer) Execute(_ context.Context, extRef, _ string) error {
+	c.extRefs = append(c.extRefs, extRef)
+	return nil
+}
+
+type stubRenewalNotifier struct {
+	sent []domainNotification.SubscriptionRenewalInput
+}
+
+func (n *stubRenewalNotifier) NotifySubscriptionRenewal(_ context.Context, in domainNotification.SubscriptionRenewalInput) error {
+	n.sent = append(n.sent, in)
+	return nil
+}
+
+func dueRenewal(attempts int) domain.Renewal {
+	return domain.Renewal{
+		Subscription: domain.Subscription{
+			ID:               7,
+			BarbershopID:     1,
+			PlanID:           3,
+			Status:           domain.StatusActive,
+			CurrentPeriodEnd: time.Now().UTC().Add(48 * time.Hour),
+			AutoRenew:        true,
+			RenewalAttempts:  attempts,
+			Plan:             &domain.Plan{ID: 3, Name: "Mensal", MonthlyPriceCents: 9900},
+		},
+		Card: models.SubscriptionCard{
+			Provider: "pagbank", CardRef: "CARD_1", LastFour: "4242", PayerEmail: "c@x.com",
+		},
+		ClientName:  "Cliente",
+		ClientPhone: "11999999999",
+	}
+}
+
+type renewFixture struct {
+	uc       *RenewSubscriptions
+	renewals *stubRenewalRepo
+	payments *stubRenewalPaymentRepo
+	gw       *stubRecurringGateway
+	confirm  *stubConfirmer
+	notifier *stubRenewalNotifier
+}
+
+func newRenewFixture(r domain.Renewal, result *domainPayment.CardPaymentResult, gwErr error) *renewFixture {
+	f := &renewFixture{
+		renewals: &stubRenewalRepo{due: []domain.Renewal{r}},
+		payments: &stubRenewalPaymentRepo{},
+		gw:       &stubRecurringGateway{result: result, err: gwErr},
+		confirm:  &stubConfirmer{},
+		notifier: &stubRenewalNotifier{},
+	}
+	fail := NewFailRenewalCharge(f.renewals, f.payments, nil, nil, f.notifier)
+	f.uc = NewRenewSubscriptions(f.renewals, f.payments, stubGatewayResolver{gw: f.gw}, fail, nil, f.notifier, "https://api.example.com").
+		WithConfirmer(f.confirm)
+	return f
+}
+
+// ── testes ────────────────────────────────────────────────────────────────────────
+
+func TestRenewSubscriptions_Approved_ConfirmsPayment(t *testing.T) {
+	f := newRenewFixture(dueRenewal(0), &domainPayment.CardPaymentResult{
+		ProviderPaymentID: "CHAR_1",
+		Status:            domainPayment.ProviderStatusApproved,
+	}, nil)
+
+	n, err := f.uc.Execute(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Execute = %d, %v; want 1, nil", n, err)
+	}
+	if len(f.payments.created) != 1 {
+		t.Fatalf("payments criados = %d, want 1", len(f.payments.created))
+	}
+	p := f.payments.created[0]
+	if !p.SubscriptionRenewal || p.Amount != 9900 {
+		t.Errorf("payment = renewal:%v amount:%d", p.SubscriptionRenewal, p.Amount)
+	}
+	if len(f.confirm.extRefs) != 1 || f.confirm.extRefs[0] != "1" {
+		t.Errorf("confirmer chamado com %v, want [1]", f.confirm.extRefs)
+	}
+	if f.gw.input.Card.CardRef != "CARD_1" {
+		t.Errorf("cobrança usou cartão %q", f.gw.input.Card.CardRef)
+	}
+	if f.renewals.recorded {
+		t.Error("aprovação não deve registrar falha")
+	}
+}
+
+func TestRenewSubscriptions_Rejected_SchedulesRetryAndNotifies(t *testing.T) {
+	f := newRenewFixture(dueRenewal(0), &domainPayment.CardPaymentResult{
+		ProviderPaymentID: "CHAR_1",
+		Status:            domainPayment.ProviderStatusRejected,
+		StatusDetail:      "cc_rejected_insufficient_amount",
+	}, nil)
+
+	before := time.Now().UTC()
+	if _, err := f.uc.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !f.renewals.failed[1] {
+		t.Error("pagamento recusado deve ser expirado")
+	}
+	if f.renewals.attempts != 1 {
+		t.Errorf("attempts = %d, want 1", f.renewals.attempts)
+	}
+	if f.renewals.next == nil || f.renewals.next.Sub(before) < 24*time.Hour {
+		t.Errorf("próxima tentativa = %v, want ~+24h", f.renewals.next)
+	}
+	if len(f.confirm.extRefs) != 0 {
+		t.Error("recusa não deve confirmar pagamento")
+	}
+	if len(f.notifier.sent) != 1 || !f.notifier.sent[0].Failed {
+		t.Fatalf("aviso de falha não enviado: %+v", f.notifier.sent)
+	}
+	if f.notifier.sent[0].NextAttemptAt == nil {
+		t.Error("aviso deve trazer a próxima tentativa")
+	}
+}
+
+func TestRenewSubscriptions_LastAttemptRejected_NoMoreRetries(t *testing.T) {
+	f := newRenewFixture(dueRenewal(domain.MaxRenewalAttempts()-1), &domainPayment.CardPaymentResult{
+		Status: domainPayment.ProviderStatusRejected,
+	}, nil)
+
+	if _, err := f.uc.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if f.renewals.attempts != domain.MaxRenewalAttempts() {
+		t.Errorf("attempts = %d, want %d", f.renewals.attempts, domain.MaxRenewalAttempts())
+	}
+	if f.renewals.next != nil {
+		t.Errorf("sem tentativas restantes next deve ser nil, got %v", f.renewals.next)
+	}
+}
+
+func TestRenewSubscriptions_GatewayError_LeavesPaymentPending(t *testing.T) {
+	f := newRenewFixture(dueRenewal(0), nil, errors.New("timeout"))
+
+	if _, err := f.uc.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if f.renewals.recorded || f.renewals.failed[1] {
+		t.Error("falha técnica não deve contar como recusa")
+	}
+	if got := f.payments.created[0].Status; got != models.PaymentStatus(domainPayment.StatusPending) {
+		t.Errorf("payment status = %q, want pending", got)
+	}
+}
+
+func TestRenewSubscriptions_GatewayWithoutStoredCards_Skipped(t *testing.T) {
+	f := newRenewFixture(dueRenewal(0), nil, nil)
+	fail := NewFailRenewalCharge(f.renewals, f.payments, nil, nil, nil)
+	uc := NewRenewSubscriptions(f.renewals, f.payments, stubGatewayResolver{gw: &stubGatewayNoExtras{}}, fail, nil, nil, "")
+
+	n, err := uc.Execute(context.Background())
+	if err != nil || n != 0 {
+		t.Fatalf("Execute = %d, %v; want 0, nil", n, err)
+	}
+	if len(f.payments.created) != 0 {
+		t.Error("não deve criar pagamento sem gateway recorrente")
+	}
+}
+
+func TestFailRenewalCharge_Idempotent(t *testing.T) {
+	f := newRenewFixture(dueRenewal(0), &domainPayment.CardPaymentResult{
+		Status: domainPayment.ProviderStatusRejected,
+	}, nil)
+	if _, err := f.uc.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// Webhook da mesma recusa chegando depois: já não está pendente.
+	f.payments.created[0].Status = models.PaymentStatus(domainPayment.StatusExpired)
+	fail := NewFailRenewalCharge(f.renewals, f.payments, nil, nil, f.notifier)
+	if err := fail.Execute(context.Background(), "1", "DECLINED"); err != nil {
+		t.Fatalf("FailRenewalCharge: %v", err)
+	}
+	if len(f.notifier.sent) != 1 {
+		t.Errorf("avisos = %d, want 1", len(f.notifier.sent))
+	}
+}
+
+func TestNextRenewalAttempt(t *testing.T) {
+	failedAt := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	farDeadline := failedAt.Add(30 * 24 * time.Hour)
+
+	cases := []struct {
+		name     string
+		attempts int
+		deadline time.Time
+		want     *time.Duration
+	}{
+		{"primeira recusa", 1, farDeadline, ptrDuration(24 * time.Hour)},
+		{"segunda recusa", 2, farDeadline, ptrDuration(48 * time.Hour)},
+		{"terceira recusa", 3, farDeadline, ptrDuration(72 * time.Hour)},
+		{"tentativas esgotadas", 4, farDeadline, nil},
+		{"depois do fim da carência", 1, failedAt.Add(12 * time.Hour), nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := domain.NextRenewalAttempt(tc.attempts, failedAt, tc.deadline)
+			switch {
+			case tc.want == nil && got != nil:
+				t.Errorf("got %v, want nil", got)
+			case tc.want != nil && (got == nil || !got.Equal(failedAt.Add(*tc.want))):
+				t.Errorf("got %v, want %v", got, failedAt.Add(*tc.want))
+			}
+		})
+	}
+}
+
+func ptrDuration(d time.Duration) *time.Duration { return &d }
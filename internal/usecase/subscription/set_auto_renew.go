@@ -0,0 +1,48 @@
+package subscription
+
+import (
+	"context"
+
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/subscription"
+)
+
+// SetAutoRenew liga ou desliga a renovação automática da assinatura do
+// cliente (ativa ou em carência). Ligar exige cartão guardado na compra.
+type SetAutoRenew struct {
+	renewals domain.RenewalRepository
+}
+
+func NewSetAutoRenew(renewals domain.RenewalRepository) *SetAutoRenew {
+	return &SetAutoRenew{renewals: renewals}
+}
+
+func (uc *SetAutoRenew) Execute(
+	ctx context.Context,
+	barbershopID uint,
+	clientID uint,
+	autoRenew bool,
+) error {
+	if barbershopID == 0 || clientID == 0 {
+		return ErrInvalidInput
+	}
+
+	subID, err := uc.renewals.FindRenewableSubscriptionID(ctx, barbershopID, clientID)
+	if err != nil {
+		return err
+	}
+	if subID == 0 {
+		return ErrActiveSubscriptionNotFound
+	}
+
+	if autoRenew {
+		card, err := uc.renewals.GetCard(ctx, subID)
+		if err != nil {
+			return err
+		}
+		if card == nil {
+			return ErrCardNotStored
+		}
+	}
+
+	return uc.renewals.SetAutoRenew(ctx, subID, autoRenew)
+}
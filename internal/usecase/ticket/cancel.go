@@ -9,8 +9,12 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainCoupon "github.com/BruksfildServices01/barber-scheduler/internal/domain/coupon"
 	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
+	domainWaitlist "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 )
 
@@ -24,12 +28,21 @@ const lateCancelWindow = 24 * time.Hour
 // minCancelWindow: janela mínima para permitir cancelamento (2h antes).
 const minCancelWindow = 2 * time.Hour
 
+// txableCouponRepo vincula o repositório de cupons à transação do
+// cancelamento (satisfeito por repository.CouponGormRepository).
+type txableCouponRepo interface {
+	WithTx(tx *gorm.DB) domainCoupon.Repository
+}
+
 type CancelViaTicket struct {
 	db       *gorm.DB
 	repo     domainTicket.Repository
 	notifier domainNotification.AppointmentNotifier
 	metrics  *ucMetrics.UpdateClientMetrics
 	audit    *audit.Dispatcher
+	waitlist domainWaitlist.SlotListener
+	calendar domainAppointment.CalendarSync
+	coupons  txableCouponRepo
 }
 
 func NewCancelViaTicket(
@@ -48,6 +61,24 @@ func NewCancelViaTicket(
 	}
 }
 
+// WithWaitlist oferece o horário liberado à lista de espera após o cancelamento.
+func (uc *CancelViaTicket) WithWaitlist(l domainWaitlist.SlotListener) *CancelViaTicket {
+	uc.waitlist = l
+	return uc
+}
+
+// WithCoupons devolve ao cupom o uso do agendamento cancelado.
+func (uc *CancelViaTicket) WithCoupons(r txableCouponRepo) *CancelViaTicket {
+	uc.coupons = r
+	return uc
+}
+
+// WithCalendarSync remove o evento espelhado no Google Calendar do barbeiro.
+func (uc *CancelViaTicket) WithCalendarSync(c domainAppointment.CalendarSync) *CancelViaTicket {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *CancelViaTicket) Execute(ctx context.Context, token string) error {
 	ticket, err := uc.repo.GetByToken(ctx, token)
 	if err != nil {
@@ -58,6 +89,7 @@ func (uc *CancelViaTicket) Execute(ctx context.Context, token string) error {
 		ID                    uint      `gorm:"column:id"`
 		Status                string    `gorm:"column:status"`
 		StartTime             time.Time `gorm:"column:start_time"`
+		EndTime               time.Time `gorm:"column:end_time"`
 		BarberID              uint      `gorm:"column:barber_id"`
 		BarbershopID          uint      `gorm:"column:barbershop_id"`
 		ClientID              *uint     `gorm:"column:client_id"`
@@ -67,7 +99,7 @@ func (uc *CancelViaTicket) Execute(ctx context.Context, token string) error {
 
 	var appt apptRow
 	err = uc.db.WithContext(ctx).
-		Raw(`SELECT id, status, start_time, barber_id, barbershop_id, client_id,
+		Raw(`SELECT id, status, start_time, end_time, barber_id, barbershop_id, client_id,
 		     reserved_subscription_cut, subscription_id
 		     FROM appointments WHERE id = ?`, ticket.AppointmentID).
 		Scan(&appt).Error
@@ -85,48 +117,91 @@ func (uc *CancelViaTicket) Execute(ctx context.Context, token string) error {
 
 	// Cancela de forma atômica: o UPDATE verifica o status diretamente no banco,
 	// eliminando a race condition de TOCTOU entre a leitura e a escrita.
-	res := uc.db.WithContext(ctx).Exec(
-		`UPDATE appointments
-		 SET status = 'cancelled', cancelled_at = NOW()
-		 WHERE id = ? AND barbershop_id = ? AND status IN ('scheduled', 'awaiting_payment')`,
-		appt.ID, appt.BarbershopID,
-	)
-	if res.Error != nil {
-		return res.Error
-	}
-	if res.RowsAffected == 0 {
-		return ErrCannotCancel
+	// Agenda, auditoria, notificação e lista de espera entram na mesma
+	// transação pelo outbox.
+	err = uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := outbox.ContextWithTx(ctx, tx)
+
+		res := tx.Exec(
+			`UPDATE appointments
+			 SET status = 'cancelled', cancelled_at = NOW()
+			 WHERE id = ? AND barbershop_id = ? AND status IN ('scheduled', 'awaiting_payment')`,
+			appt.ID, appt.BarbershopID,
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCannotCancel
+		}
+
+		if uc.coupons != nil {
+			if err := uc.coupons.WithTx(tx).VoidForAppointment(ctx, appt.ID); err != nil {
+				return err
+			}
+		}
+
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(ctx, appt.BarbershopID, appt.ID)
+		}
+
+		// Lista de espera: oferece o horário liberado
+		if uc.waitlist != nil {
+			if err := uc.waitlist.SlotFreed(ctx, domainWaitlist.FreedSlot{
+				BarbershopID: appt.BarbershopID,
+				BarberID:     appt.BarberID,
+				StartTime:    appt.StartTime,
+				EndTime:      appt.EndTime,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Auditoria
+		if uc.audit != nil {
+			if err := uc.audit.DispatchContext(ctx, audit.Event{
+				BarbershopID: appt.BarbershopID,
+				Action:       "ticket_cancel",
+				Entity:       "appointment",
+				EntityID:     &appt.ID,
+				Metadata: map[string]any{
+					"token":      token,
+					"start_time": appt.StartTime,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.notify(ctx, tx, appt.ID, appt.BarbershopID, appt.StartTime)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Libera reserva de assinatura se existia (best-effort).
+	// Libera reserva de assinatura se existia (best-effort): um corte por
+	// serviço coberto do agendamento.
 	if appt.ReservedSubscriptionCut && appt.ClientID != nil {
+		var reservedCuts int64
+		if err := uc.db.WithContext(ctx).
+			Raw(`SELECT COUNT(*) FROM appointment_services WHERE appointment_id = ? AND reserved_cut`, appt.ID).
+			Scan(&reservedCuts).Error; err != nil || reservedCuts == 0 {
+			reservedCuts = 1
+		}
+
 		releaseRes := uc.db.WithContext(ctx).Exec(`
 			UPDATE subscriptions
-			SET cuts_reserved_in_period = cuts_reserved_in_period - 1
+			SET cuts_reserved_in_period = GREATEST(cuts_reserved_in_period - ?, 0)
 			WHERE barbershop_id = ? AND client_id = ? AND status = 'active'
 			  AND current_period_start <= NOW() AND current_period_end > NOW()
 			  AND cuts_reserved_in_period > 0
-		`, appt.BarbershopID, *appt.ClientID)
+		`, reservedCuts, appt.BarbershopID, *appt.ClientID)
 		if releaseRes.Error != nil {
 			log.Printf("[CancelViaTicket] release subscription cut failed client=%d: %v",
 				*appt.ClientID, releaseRes.Error)
 		}
 	}
 
-	// Auditoria
-	if uc.audit != nil {
-		uc.audit.Dispatch(audit.Event{
-			BarbershopID: appt.BarbershopID,
-			Action:       "ticket_cancel",
-			Entity:       "appointment",
-			EntityID:     &appt.ID,
-			Metadata: map[string]any{
-				"token":      token,
-				"start_time": appt.StartTime,
-			},
-		})
-	}
-
 	// Métrica do cliente: penalidade se cancelamento tardio (< 24h)
 	if uc.metrics != nil && appt.ClientID != nil {
 		eventType := ucMetrics.EventAppointmentCanceled
@@ -141,44 +216,66 @@ func (uc *CancelViaTicket) Execute(ctx context.Context, token string) error {
 		})
 	}
 
-	// Notificação
-	if uc.notifier != nil {
-		type notifyRow struct {
-			ClientName     string `gorm:"column:client_name"`
-			ClientEmail    string `gorm:"column:client_email"`
-			ClientPhone    string `gorm:"column:client_phone"`
-			BarbershopName string `gorm:"column:barbershop_name"`
-			BarbershopSlug string `gorm:"column:barbershop_slug"`
-			Timezone       string `gorm:"column:timezone"`
-		}
-		var notifyData notifyRow
-		queryErr := uc.db.WithContext(ctx).Raw(`
-			SELECT c.name  AS client_name,
-			       c.email AS client_email,
-			       c.phone AS client_phone,
-			       b.name  AS barbershop_name,
-			       b.slug  AS barbershop_slug,
-			       b.timezone
-			FROM appointments a
-			JOIN clients     c ON c.id = a.client_id
-			JOIN barbershops b ON b.id = a.barbershop_id
-			WHERE a.id = ?
-		`, appt.ID).Scan(&notifyData).Error
-		if queryErr != nil {
-			log.Printf("[CancelViaTicket] failed to query notification data: %v", queryErr)
-		} else if notifyData.ClientEmail != "" || notifyData.ClientPhone != "" {
-			_ = uc.notifier.NotifyCancelled(ctx, domainNotification.AppointmentCancelledInput{
-				BarbershopID:   appt.BarbershopID,
-				ClientName:     notifyData.ClientName,
-				ClientEmail:    notifyData.ClientEmail,
-				ClientPhone:    notifyData.ClientPhone,
-				BarbershopName: notifyData.BarbershopName,
-				BarbershopSlug: notifyData.BarbershopSlug,
-				StartTime:      appt.StartTime,
-				Timezone:       notifyData.Timezone,
-			})
-		}
+	return nil
+}
+
+// notify grava o aviso de cancelamento ao cliente, dentro da transação.
+func (uc *CancelViaTicket) notify(ctx context.Context, tx *gorm.DB, appointmentID, barbershopID uint, startTime time.Time) error {
+	if uc.notifier == nil {
+		return nil
 	}
 
-	return nil
+	type notifyRow struct {
+		ClientName        string `gorm:"column:client_name"`
+		ClientEmail       string `gorm:"column:client_email"`
+		ClientPhone       string `gorm:"column:client_phone"`
+		BarbershopName    string `gorm:"column:barbershop_name"`
+		BarbershopPhone   string `gorm:"column:barbershop_phone"`
+		BarbershopSlug    string `gorm:"column:barbershop_slug"`
+		BarbershopAddress string `gorm:"column:barbershop_address"`
+		ServiceName       string `gorm:"column:service_name"`
+		Timezone          string `gorm:"column:timezone"`
+	}
+	var notifyData notifyRow
+	err := tx.Raw(`
+		SELECT c.name  AS client_name,
+		       c.email AS client_email,
+		       c.phone AS client_phone,
+		       b.name  AS barbershop_name,
+		       b.phone AS barbershop_phone,
+		       b.slug  AS barbershop_slug,
+		       b.address AS barbershop_address,
+		       COALESCE(
+		         (SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+		          FROM appointment_services s WHERE s.appointment_id = a.id),
+		         bs.name
+		       ) AS service_name,
+		       b.timezone
+		FROM appointments a
+		JOIN clients             c  ON c.id  = a.client_id
+		JOIN barbershops         b  ON b.id  = a.barbershop_id
+		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		WHERE a.id = ?
+	`, appointmentID).Scan(&notifyData).Error
+	if err != nil {
+		return err
+	}
+	if notifyData.ClientEmail == "" && notifyData.ClientPhone == "" {
+		return nil
+	}
+
+	return uc.notifier.NotifyCancelled(ctx, domainNotification.AppointmentCancelledInput{
+		BarbershopID:      barbershopID,
+		AppointmentID:     appointmentID,
+		ClientName:        notifyData.ClientName,
+		ClientEmail:       notifyData.ClientEmail,
+		ClientPhone:       notifyData.ClientPhone,
+		BarbershopName:    notifyData.BarbershopName,
+		BarbershopPhone:   notifyData.BarbershopPhone,
+		BarbershopSlug:    notifyData.BarbershopSlug,
+		BarbershopAddress: notifyData.BarbershopAddress,
+		ServiceName:       notifyData.ServiceName,
+		StartTime:         startTime,
+		Timezone:          notifyData.Timezone,
+	})
 }
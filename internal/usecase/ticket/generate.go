@@ -6,6 +6,8 @@ import (
 	"encoding/hex"
 	"time"
 
+	"gorm.io/gorm"
+
 	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
 	"github.com/BruksfildServices01/barber-scheduler/internal/models"
 )
@@ -24,7 +26,27 @@ func NewGenerateTicket(repo domainTicket.Repository) *GenerateTicket {
 	return &GenerateTicket{repo: repo}
 }
 
+// txableTicketRepo é o repositório que grava o ticket numa transação do
+// chamador.
+type txableTicketRepo interface {
+	WithTx(tx *gorm.DB) domainTicket.Repository
+}
+
 func (uc *GenerateTicket) Execute(ctx context.Context, input GenerateTicketInput) (string, error) {
+	return generate(ctx, uc.repo, input)
+}
+
+// ExecuteTx gera o ticket na transação tx (ex.: a que cria o agendamento),
+// para o token valer junto com o commit dela.
+func (uc *GenerateTicket) ExecuteTx(ctx context.Context, tx *gorm.DB, input GenerateTicketInput) (string, error) {
+	repo := uc.repo
+	if txRepo, ok := uc.repo.(txableTicketRepo); ok {
+		repo = txRepo.WithTx(tx)
+	}
+	return generate(ctx, repo, input)
+}
+
+func generate(ctx context.Context, repo domainTicket.Repository, input GenerateTicketInput) (string, error) {
 	raw := make([]byte, 32)
 	if _, err := rand.Read(raw); err != nil {
 		return "", err
@@ -40,7 +62,7 @@ func (uc *GenerateTicket) Execute(ctx context.Context, input GenerateTicketInput
 		ExpiresAt: input.StartTime.Add(30 * 24 * time.Hour),
 	}
 
-	if err := uc.repo.Upsert(ctx, ticket); err != nil {
+	if err := repo.Upsert(ctx, ticket); err != nil {
 		return "", err
 	}
 
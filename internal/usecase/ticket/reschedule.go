@@ -6,14 +6,15 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
 	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
 	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
+	"github.com/BruksfildServices01/barber-scheduler/internal/outbox"
 	ucMetrics "github.com/BruksfildServices01/barber-scheduler/internal/usecase/metrics"
 )
 
@@ -30,6 +31,7 @@ type RescheduleViaTicket struct {
 	metrics  *ucMetrics.UpdateClientMetrics
 	audit    *audit.Dispatcher
 	appURL   string
+	calendar domainAppointment.CalendarSync
 }
 
 func NewRescheduleViaTicket(
@@ -50,6 +52,12 @@ func NewRescheduleViaTicket(
 	}
 }
 
+// WithCalendarSync move o evento espelhado no Google Calendar do barbeiro.
+func (uc *RescheduleViaTicket) WithCalendarSync(c domainAppointment.CalendarSync) *RescheduleViaTicket {
+	uc.calendar = c
+	return uc
+}
+
 func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr string) (string, error) {
 	ticket, err := uc.repo.GetByToken(ctx, token)
 	if err != nil {
@@ -123,9 +131,20 @@ func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr
 		DurationMin int `gorm:"column:duration_min"`
 	}
 
+	// Vários serviços: a duração é a soma das linhas do agendamento; sem
+	// linhas, a do serviço no catálogo do barbeiro.
 	var svc serviceRow
 	err = uc.db.WithContext(ctx).
-		Raw("SELECT duration_min FROM barbershop_services WHERE id = ?", appt.BarberProductID).
+		Raw(`
+			SELECT COALESCE(
+			  (SELECT SUM(duration_min) FROM appointment_services WHERE appointment_id = ?),
+			  (SELECT COALESCE(o.duration_min, s.duration_min)
+			   FROM barbershop_services s
+			   LEFT JOIN barber_service_overrides o
+			     ON o.service_id = s.id AND o.barber_id = ?
+			   WHERE s.id = ?)
+			) AS duration_min
+		`, appt.ID, appt.BarberID, appt.BarberProductID).
 		Scan(&svc).Error
 	if err != nil {
 		return "", err
@@ -184,6 +203,23 @@ func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr
 		}
 	}
 
+	// Compromissos pessoais do Google Calendar bloqueiam como o almoço.
+	var busyCount int64
+	err = uc.db.WithContext(ctx).
+		Raw(`
+			SELECT COUNT(*) FROM barber_busy_periods
+			WHERE barber_id = ?
+			  AND start_time < ?
+			  AND end_time > ?
+		`, appt.BarberID, newEnd, newStartUTC).
+		Scan(&busyCount).Error
+	if err != nil {
+		return "", err
+	}
+	if busyCount > 0 {
+		return "", ErrOutsideWorkingHours
+	}
+
 	var conflictCount int64
 	err = uc.db.WithContext(ctx).
 		Raw(`
@@ -209,7 +245,10 @@ func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr
 	}
 	newToken := hex.EncodeToString(raw)
 
+	// Agenda, auditoria e notificação entram na mesma transação pelo outbox.
 	txErr := uc.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := outbox.ContextWithTx(ctx, tx)
+
 		if err := tx.Exec(
 			"UPDATE appointments SET start_time = ?, end_time = ?, reschedule_count = reschedule_count + 1 WHERE id = ?",
 			newStartUTC, newEnd, appt.ID,
@@ -224,27 +263,33 @@ func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr
 			return err
 		}
 
-		return nil
+		if uc.calendar != nil {
+			uc.calendar.AppointmentChanged(ctx, appt.BarbershopID, appt.ID)
+		}
+
+		// Auditoria
+		if uc.audit != nil {
+			if err := uc.audit.DispatchContext(ctx, audit.Event{
+				BarbershopID: appt.BarbershopID,
+				Action:       "ticket_reschedule",
+				Entity:       "appointment",
+				EntityID:     &appt.ID,
+				Metadata: map[string]any{
+					"old_start_time": appt.StartTime,
+					"new_start_time": newStartUTC,
+					"token":          newToken,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.notify(ctx, tx, appt.ID, appt.BarbershopID, appt.StartTime, newStartUTC, newEnd, newToken)
 	})
 	if txErr != nil {
 		return "", txErr
 	}
 
-	// Auditoria
-	if uc.audit != nil {
-		uc.audit.Dispatch(audit.Event{
-			BarbershopID: appt.BarbershopID,
-			Action:       "ticket_reschedule",
-			Entity:       "appointment",
-			EntityID:     &appt.ID,
-			Metadata: map[string]any{
-				"old_start_time": appt.StartTime,
-				"new_start_time": newStartUTC,
-				"token":          newToken,
-			},
-		})
-	}
-
 	// Métrica: reagendamento tardio se estava a menos de 24h
 	if uc.metrics != nil && appt.ClientID != 0 {
 		eventType := ucMetrics.EventAppointmentRescheduled
@@ -259,53 +304,78 @@ func (uc *RescheduleViaTicket) Execute(ctx context.Context, token, date, timeStr
 		})
 	}
 
-	if uc.notifier != nil {
-		type notifyRow struct {
-			ClientName      string `gorm:"column:client_name"`
-			ClientEmail     string `gorm:"column:client_email"`
-			ClientPhone     string `gorm:"column:client_phone"`
-			BarbershopName  string `gorm:"column:barbershop_name"`
-			BarbershopPhone string `gorm:"column:barbershop_phone"`
-			BarbershopSlug  string `gorm:"column:barbershop_slug"`
-			ServiceName     string `gorm:"column:service_name"`
-			Timezone        string `gorm:"column:timezone"`
-		}
-		var notifyData notifyRow
-		queryErr := uc.db.WithContext(ctx).Raw(`
-			SELECT c.name  AS client_name,
-			       c.email AS client_email,
-			       c.phone AS client_phone,
-			       b.name  AS barbershop_name,
-			       b.phone AS barbershop_phone,
-			       b.slug  AS barbershop_slug,
-			       bs.name AS service_name,
-			       b.timezone
-			FROM appointments a
-			JOIN clients             c  ON c.id  = a.client_id
-			JOIN barbershops         b  ON b.id  = a.barbershop_id
-			JOIN barbershop_services bs ON bs.id = a.barber_product_id
-			WHERE a.id = ?
-		`, appt.ID).Scan(&notifyData).Error
-		if queryErr != nil {
-			log.Printf("[RescheduleViaTicket] failed to query notification data: %v", queryErr)
-		} else if notifyData.ClientEmail != "" || notifyData.ClientPhone != "" {
-			_ = uc.notifier.NotifyRescheduled(ctx, domainNotification.AppointmentRescheduledInput{
-				BarbershopID:    appt.BarbershopID,
-				ClientName:      notifyData.ClientName,
-				ClientEmail:     notifyData.ClientEmail,
-				ClientPhone:     notifyData.ClientPhone,
-				BarbershopName:  notifyData.BarbershopName,
-				BarbershopPhone: notifyData.BarbershopPhone,
-				BarbershopSlug:  notifyData.BarbershopSlug,
-				ServiceName:     notifyData.ServiceName,
-				OldStartTime:    appt.StartTime,
-				NewStartTime:    newStartUTC,
-				NewEndTime:      newEnd,
-				Timezone:        notifyData.Timezone,
-				NewTicketURL:    uc.appURL + "/ticket/" + newToken,
-			})
-		}
+	return newToken, nil
+}
+
+// notify grava o aviso de remarcação ao cliente, dentro da transação.
+func (uc *RescheduleViaTicket) notify(
+	ctx context.Context,
+	tx *gorm.DB,
+	appointmentID uint,
+	barbershopID uint,
+	oldStart time.Time,
+	newStart time.Time,
+	newEnd time.Time,
+	newToken string,
+) error {
+	if uc.notifier == nil {
+		return nil
 	}
 
-	return newToken, nil
+	type notifyRow struct {
+		ClientName      string `gorm:"column:client_name"`
+		ClientEmail     string `gorm:"column:client_email"`
+		ClientPhone     string `gorm:"column:client_phone"`
+		BarbershopName  string `gorm:"column:barbershop_name"`
+		BarbershopPhone string `gorm:"column:barbershop_phone"`
+		BarbershopSlug  string `gorm:"column:barbershop_slug"`
+		BarbershopAddr  string `gorm:"column:barbershop_address"`
+		ServiceName     string `gorm:"column:service_name"`
+		Timezone        string `gorm:"column:timezone"`
+	}
+	var notifyData notifyRow
+	err := tx.Raw(`
+		SELECT c.name  AS client_name,
+		       c.email AS client_email,
+		       c.phone AS client_phone,
+		       b.name  AS barbershop_name,
+		       b.phone AS barbershop_phone,
+		       b.slug  AS barbershop_slug,
+		       b.address AS barbershop_address,
+		       COALESCE(
+		         (SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+		          FROM appointment_services s WHERE s.appointment_id = a.id),
+		         bs.name
+		       ) AS service_name,
+		       b.timezone
+		FROM appointments a
+		JOIN clients             c  ON c.id  = a.client_id
+		JOIN barbershops         b  ON b.id  = a.barbershop_id
+		JOIN barbershop_services bs ON bs.id = a.barber_product_id
+		WHERE a.id = ?
+	`, appointmentID).Scan(&notifyData).Error
+	if err != nil {
+		return err
+	}
+	if notifyData.ClientEmail == "" && notifyData.ClientPhone == "" {
+		return nil
+	}
+
+	return uc.notifier.NotifyRescheduled(ctx, domainNotification.AppointmentRescheduledInput{
+		BarbershopID:      barbershopID,
+		AppointmentID:     appointmentID,
+		ClientName:        notifyData.ClientName,
+		ClientEmail:       notifyData.ClientEmail,
+		ClientPhone:       notifyData.ClientPhone,
+		BarbershopName:    notifyData.BarbershopName,
+		BarbershopPhone:   notifyData.BarbershopPhone,
+		BarbershopSlug:    notifyData.BarbershopSlug,
+		BarbershopAddress: notifyData.BarbershopAddr,
+		ServiceName:       notifyData.ServiceName,
+		OldStartTime:      oldStart,
+		NewStartTime:      newStart,
+		NewEndTime:        newEnd,
+		Timezone:          notifyData.Timezone,
+		NewTicketURL:      uc.appURL + "/ticket/" + newToken,
+	})
 }
@@ -27,6 +27,8 @@ type TicketViewDTO struct {
 	RescheduleCount int       `json:"reschedule_count"`
 	CanCancel       bool      `json:"can_cancel"`
 	CanReschedule   bool      `json:"can_reschedule"`
+	// Saldo de pontos de fidelidade do cliente; omitido sem programa ativo.
+	LoyaltyPoints *int `json:"loyalty_points,omitempty"`
 }
 
 type ViewTicket struct {
@@ -54,6 +56,7 @@ func (uc *ViewTicket) Execute(ctx context.Context, token string) (*TicketViewDTO
 		Token           string    `gorm:"column:token"`
 		ExpiresAt       time.Time `gorm:"column:expires_at"`
 		RescheduleCount int       `gorm:"column:reschedule_count"`
+		LoyaltyPoints   *int      `gorm:"column:loyalty_points"`
 	}
 
 	var r row
@@ -65,7 +68,11 @@ func (uc *ViewTicket) Execute(ctx context.Context, token string) (*TicketViewDTO
 			a.status         AS status,
 			a.start_time     AS start_time,
 			a.end_time       AS end_time,
-			bs.name          AS service_name,
+			COALESCE(
+			  (SELECT string_agg(s.service_name, ' + ' ORDER BY s.position)
+			   FROM appointment_services s WHERE s.appointment_id = a.id),
+			  bs.name
+			)                AS service_name,
 			b.name           AS barbershop_name,
 			b.slug           AS barbershop_slug,
 			b.phone          AS barbershop_phone,
@@ -75,7 +82,19 @@ func (uc *ViewTicket) Execute(ctx context.Context, token string) (*TicketViewDTO
 			c.phone          AS client_phone,
 			t.token          AS token,
 			t.expires_at     AS expires_at,
-			a.reschedule_count AS reschedule_count
+			a.reschedule_count AS reschedule_count,
+			CASE WHEN a.client_id IS NOT NULL AND EXISTS (
+			  SELECT 1 FROM loyalty_programs lp
+			  WHERE lp.barbershop_id = a.barbershop_id AND lp.active
+			) THEN (
+			  SELECT COALESCE(SUM(le.remaining_points), 0)
+			  FROM loyalty_entries le
+			  WHERE le.barbershop_id = a.barbershop_id
+			    AND le.client_id = a.client_id
+			    AND le.kind = 'earn'
+			    AND le.remaining_points > 0
+			    AND (le.expires_at IS NULL OR le.expires_at > NOW())
+			) END            AS loyalty_points
 		FROM appointment_tickets t
 		JOIN appointments a         ON a.id = t.appointment_id
 		JOIN barbershops b          ON b.id = t.barbershop_id
@@ -117,5 +136,6 @@ func (uc *ViewTicket) Execute(ctx context.Context, token string) (*TicketViewDTO
 		RescheduleCount: r.RescheduleCount,
 		CanCancel:       canCancel,
 		CanReschedule:   canReschedule,
+		LoyaltyPoints:   r.LoyaltyPoints,
 	}, nil
 }
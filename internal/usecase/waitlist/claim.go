@@ -0,0 +1,127 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+	ucAppointment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+)
+
+// appointmentCreator é o caminho normal de criação (CreatePrivateAppointment):
+// política de pagamento, assinatura, conflito sob lock e idempotência.
+type appointmentCreator interface {
+	Execute(ctx context.Context, in ucAppointment.CreatePrivateAppointmentInput) (*models.Appointment, error)
+}
+
+// ClaimWaitlistOffer aceita a oferta de uma entrada da lista de espera,
+// agendando o horário oferecido em nome do cliente.
+type ClaimWaitlistOffer struct {
+	repo   domain.Repository
+	create appointmentCreator
+	audit  *audit.Dispatcher
+}
+
+func NewClaimWaitlistOffer(
+	repo domain.Repository,
+	create appointmentCreator,
+	audit *audit.Dispatcher,
+) *ClaimWaitlistOffer {
+	return &ClaimWaitlistOffer{
+		repo:   repo,
+		create: create,
+		audit:  audit,
+	}
+}
+
+func (uc *ClaimWaitlistOffer) Execute(ctx context.Context, token string) (*models.Appointment, error) {
+	entry, err := uc.repo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, apperr.ErrBusiness("waitlist_entry_not_found")
+	}
+
+	now := time.Now().UTC()
+
+	switch {
+	case entry.Status == models.WaitlistStatusBooked:
+		return nil, apperr.ErrBusiness("waitlist_offer_already_claimed")
+	case entry.Status != models.WaitlistStatusOffered:
+		return nil, apperr.ErrBusiness("waitlist_offer_not_found")
+	case !entry.OfferActive(now):
+		return nil, apperr.ErrBusiness("waitlist_offer_expired")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, entry.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("barbershop_not_found")
+	}
+
+	startLocal := entry.OfferedStartTime.In(timezone.Location(shop.Timezone))
+
+	ap, err := uc.create.Execute(ctx, ucAppointment.CreatePrivateAppointmentInput{
+		BarbershopID: entry.BarbershopID,
+		BarberID:     *entry.OfferedBarberID,
+		ClientName:   entry.ClientName,
+		ClientPhone:  entry.ClientPhone,
+		ClientEmail:  entry.ClientEmail,
+		ProductID:    entry.ServiceID,
+		Date:         startLocal.Format("2006-01-02"),
+		Time:         startLocal.Format("15:04"),
+		Notes:        "Agendado pela lista de espera",
+		// Cliques repetidos no link não geram dois agendamentos.
+		IdempotencyKey: fmt.Sprintf("waitlist:%d:%d", entry.ID, entry.OfferedStartTime.Unix()),
+	})
+	if err != nil {
+		if slotLost(err) {
+			// Outra pessoa ficou com o horário (ou ele deixou de ser agendável):
+			// a entrada volta a esperar pelo próximo.
+			entry.Status = models.WaitlistStatusWaiting
+			entry.OfferedStartTime = nil
+			entry.OfferedBarberID = nil
+			entry.OfferExpiresAt = nil
+			if err := uc.repo.Update(ctx, entry); err != nil {
+				return nil, err
+			}
+			return nil, apperr.ErrBusiness("waitlist_slot_taken")
+		}
+		return nil, err
+	}
+
+	entry.Status = models.WaitlistStatusBooked
+	entry.AppointmentID = &ap.ID
+	if err := uc.repo.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	if uc.audit != nil {
+		uc.audit.Dispatch(audit.Event{
+			BarbershopID: entry.BarbershopID,
+			Action:       "waitlist_offer_claimed",
+			Entity:       "waitlist_entry",
+			EntityID:     &entry.ID,
+			Metadata: map[string]any{
+				"appointment_id": ap.ID,
+			},
+		})
+	}
+
+	return ap, nil
+}
+
+func slotLost(err error) bool {
+	return apperr.IsBusiness(err, "time_conflict") ||
+		apperr.IsBusiness(err, "too_soon") ||
+		apperr.IsBusiness(err, "outside_working_hours") ||
+		apperr.IsBusiness(err, "service_not_offered")
+}
@@ -0,0 +1,69 @@
+package waitlist
+
+import (
+	"context"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+)
+
+// ======================================================
+// GET
+// ======================================================
+
+type GetWaitlistEntry struct {
+	repo domain.Repository
+}
+
+func NewGetWaitlistEntry(repo domain.Repository) *GetWaitlistEntry {
+	return &GetWaitlistEntry{repo: repo}
+}
+
+func (uc *GetWaitlistEntry) Execute(ctx context.Context, token string) (*models.WaitlistEntry, error) {
+	entry, err := uc.repo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, apperr.ErrBusiness("waitlist_entry_not_found")
+	}
+	return entry, nil
+}
+
+// ======================================================
+// LEAVE
+// ======================================================
+
+type LeaveWaitlist struct {
+	repo domain.Repository
+}
+
+func NewLeaveWaitlist(repo domain.Repository) *LeaveWaitlist {
+	return &LeaveWaitlist{repo: repo}
+}
+
+func (uc *LeaveWaitlist) Execute(ctx context.Context, token string) error {
+	entry, err := uc.repo.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return apperr.ErrBusiness("waitlist_entry_not_found")
+	}
+
+	switch entry.Status {
+	case models.WaitlistStatusCancelled:
+		return nil
+	case models.WaitlistStatusBooked:
+		// Já virou agendamento: o cancelamento é pelo ticket.
+		return apperr.ErrBusiness("waitlist_offer_already_claimed")
+	}
+
+	entry.Status = models.WaitlistStatusCancelled
+	entry.OfferedStartTime = nil
+	entry.OfferedBarberID = nil
+	entry.OfferExpiresAt = nil
+
+	return uc.repo.Update(ctx, entry)
+}
@@ -0,0 +1,209 @@
+package waitlist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+const (
+	// maxRangeDays limita o período de interesse de uma entrada.
+	maxRangeDays = 60
+	// maxTimeWindows limita as faixas de horário preferidas por entrada.
+	maxTimeWindows = 5
+)
+
+type JoinWaitlistInput struct {
+	BarbershopID uint
+	ServiceID    uint
+	BarberID     uint // 0 = qualquer barbeiro
+
+	ClientName  string
+	ClientPhone string
+	ClientEmail string
+
+	DateFrom    string // YYYY-MM-DD
+	DateTo      string // YYYY-MM-DD
+	TimeWindows []models.TimeWindow
+}
+
+type JoinWaitlist struct {
+	repo domain.Repository
+}
+
+func NewJoinWaitlist(repo domain.Repository) *JoinWaitlist {
+	return &JoinWaitlist{repo: repo}
+}
+
+func (uc *JoinWaitlist) Execute(
+	ctx context.Context,
+	in JoinWaitlistInput,
+) (*models.WaitlistEntry, error) {
+
+	name := strings.TrimSpace(in.ClientName)
+	phone := strings.TrimSpace(in.ClientPhone)
+	email := strings.TrimSpace(in.ClientEmail)
+
+	if name == "" {
+		return nil, apperr.ErrBusiness("invalid_client_name")
+	}
+	// Sem telefone nem email não há como enviar a oferta.
+	if phone == "" && email == "" {
+		return nil, apperr.ErrBusiness("contact_required")
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, in.BarbershopID)
+	if err != nil {
+		return nil, err
+	}
+	if shop == nil {
+		return nil, apperr.ErrBusiness("barbershop_not_found")
+	}
+
+	// --------------------------------------------------
+	// Período (no calendário da barbearia)
+	// --------------------------------------------------
+	// Datas são dias de calendário: ficam em meia-noite UTC, como a coluna DATE
+	// devolve, e "hoje" é o dia corrente no fuso da barbearia.
+	from, errFrom := time.Parse("2006-01-02", in.DateFrom)
+	to, errTo := time.Parse("2006-01-02", in.DateTo)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return nil, apperr.ErrBusiness("invalid_date_range")
+	}
+
+	nowLocal := time.Now().In(timezone.Location(shop.Timezone))
+	today := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(today) || to.Sub(from) > maxRangeDays*24*time.Hour {
+		return nil, apperr.ErrBusiness("invalid_date_range")
+	}
+	if from.Before(today) {
+		from = today
+	}
+
+	windows, err := normalizeTimeWindows(in.TimeWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	// --------------------------------------------------
+	// Serviço / barbeiro
+	// --------------------------------------------------
+	svc, err := uc.repo.GetService(ctx, in.BarbershopID, in.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil || !svc.Active {
+		return nil, apperr.ErrBusiness("service_not_found")
+	}
+
+	var barberID *uint
+	if in.BarberID != 0 {
+		ok, err := uc.repo.ActiveBarberExists(ctx, in.BarbershopID, in.BarberID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, apperr.ErrBusiness("barber_not_found")
+		}
+
+		override, err := uc.repo.GetBarberServiceOverride(ctx, in.BarbershopID, in.BarberID, in.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if override != nil && !override.Offered {
+			return nil, apperr.ErrBusiness("service_not_offered")
+		}
+
+		id := in.BarberID
+		barberID = &id
+	}
+
+	exists, err := uc.repo.HasOpenEntry(ctx, in.BarbershopID, in.ServiceID, phone, email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperr.ErrBusiness("waitlist_already_joined")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.WaitlistEntry{
+		BarbershopID: in.BarbershopID,
+		ServiceID:    in.ServiceID,
+		BarberID:     barberID,
+		ClientName:   name,
+		ClientPhone:  phone,
+		ClientEmail:  email,
+		DateFrom:     from,
+		DateTo:       to,
+		TimeWindows:  windows,
+		Status:       models.WaitlistStatusWaiting,
+		Token:        token,
+	}
+
+	if err := uc.repo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// normalizeTimeWindows valida as faixas "HH:MM"–"HH:MM" (início < fim).
+func normalizeTimeWindows(in []models.TimeWindow) (models.TimeWindows, error) {
+	if len(in) > maxTimeWindows {
+		return nil, apperr.ErrBusiness("invalid_time_window")
+	}
+
+	out := make(models.TimeWindows, 0, len(in))
+	for _, w := range in {
+		start, errStart := time.Parse("15:04", w.Start)
+		end, errEnd := time.Parse("15:04", w.End)
+		if errStart != nil || errEnd != nil || !start.Before(end) {
+			return nil, apperr.ErrBusiness("invalid_time_window")
+		}
+		out = append(out, models.TimeWindow{
+			Start: start.Format("15:04"),
+			End:   end.Format("15:04"),
+		})
+	}
+	return out, nil
+}
+
+// fitsTimeWindows indica se [start, end) cabe inteiro em alguma das faixas.
+// Sem faixas, qualquer horário serve.
+func fitsTimeWindows(windows models.TimeWindows, start, end time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	if end.Day() != start.Day() {
+		return false
+	}
+
+	from := start.Format("15:04")
+	to := end.Format("15:04")
+	for _, w := range windows {
+		if from >= w.Start && to <= w.End {
+			return true
+		}
+	}
+	return false
+}
+
+func newToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
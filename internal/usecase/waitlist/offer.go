@@ -0,0 +1,231 @@
+package waitlist
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/audit"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+)
+
+const (
+	// offerTTL é o prazo para o cliente aceitar a oferta. Vencido, a entrada
+	// volta a receber ofertas e o horário segue livre para qualquer pessoa.
+	offerTTL = 30 * time.Minute
+
+	// maxOffersPerSlot: quantas entradas recebem oferta do mesmo horário.
+	// Quem aceitar primeiro fica com ele; os demais recebem slot_taken.
+	maxOffersPerSlot = 3
+
+	// candidateScanLimit limita as entradas avaliadas por horário liberado.
+	candidateScanLimit = 50
+)
+
+// OfferFreedSlot oferece um horário liberado às entradas compatíveis da lista
+// de espera, por ordem de chegada. Implementa domain.SlotListener.
+//
+// Uma entrada é compatível quando aceita o dia e o barbeiro, o serviço dela
+// (com a duração do barbeiro) cabe no intervalo liberado e o horário cai em
+// uma das faixas preferidas.
+type OfferFreedSlot struct {
+	repo     domain.Repository
+	email    domainNotification.WaitlistOfferNotifier
+	whatsapp domainNotification.WaitlistOfferNotifier
+	audit    *audit.Dispatcher
+	appURL   string
+}
+
+// NewOfferFreedSlot cria o use case. email e whatsapp podem ser nil quando o
+// canal não está configurado.
+func NewOfferFreedSlot(
+	repo domain.Repository,
+	email domainNotification.WaitlistOfferNotifier,
+	whatsapp domainNotification.WaitlistOfferNotifier,
+	audit *audit.Dispatcher,
+	appURL string,
+) *OfferFreedSlot {
+	return &OfferFreedSlot{
+		repo:     repo,
+		email:    email,
+		whatsapp: whatsapp,
+		audit:    audit,
+		appURL:   appURL,
+	}
+}
+
+// SlotFreed implementa domain.SlotListener oferecendo o horário na hora.
+func (uc *OfferFreedSlot) SlotFreed(ctx context.Context, slot domain.FreedSlot) error {
+	offered, err := uc.Execute(ctx, slot)
+	if err != nil {
+		return err
+	}
+	if offered > 0 {
+		log.Printf("[Waitlist] barbershop=%d barber=%d start=%s offers=%d",
+			slot.BarbershopID, slot.BarberID, slot.StartTime.Format(time.RFC3339), offered)
+	}
+	return nil
+}
+
+// Execute retorna quantas entradas receberam a oferta.
+func (uc *OfferFreedSlot) Execute(ctx context.Context, slot domain.FreedSlot) (int, error) {
+	if slot.BarbershopID == 0 || slot.BarberID == 0 || !slot.EndTime.After(slot.StartTime) {
+		return 0, nil
+	}
+
+	shop, err := uc.repo.GetBarbershopByID(ctx, slot.BarbershopID)
+	if err != nil || shop == nil {
+		return 0, err
+	}
+
+	loc := timezone.Location(shop.Timezone)
+	now := time.Now().UTC()
+
+	// Mesma antecedência mínima do booking: um horário que não pode mais ser
+	// agendado não vira oferta.
+	minAdvance := shop.MinAdvanceMinutes
+	if minAdvance <= 0 {
+		minAdvance = 120
+	}
+	if slot.StartTime.Before(now.Add(time.Duration(minAdvance) * time.Minute)) {
+		return 0, nil
+	}
+
+	active, err := uc.repo.ActiveBarberExists(ctx, slot.BarbershopID, slot.BarberID)
+	if err != nil || !active {
+		return 0, err
+	}
+
+	startLocal := slot.StartTime.In(loc)
+	day := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, time.UTC)
+
+	entries, err := uc.repo.ListOfferCandidates(ctx, slot.BarbershopID, slot.BarberID, day, now, candidateScanLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	services := map[uint]*models.BarbershopService{}
+	offered := 0
+
+	for _, entry := range entries {
+		svc, cached := services[entry.ServiceID]
+		if !cached {
+			svc, err = uc.serviceForBarber(ctx, slot.BarbershopID, slot.BarberID, entry.ServiceID)
+			if err != nil {
+				return offered, err
+			}
+			services[entry.ServiceID] = svc
+		}
+		if svc == nil || svc.DurationMin <= 0 {
+			continue
+		}
+
+		end := slot.StartTime.Add(time.Duration(svc.DurationMin) * time.Minute)
+		if end.After(slot.EndTime) {
+			continue
+		}
+		if !fitsTimeWindows(entry.TimeWindows, startLocal, end.In(loc)) {
+			continue
+		}
+
+		start := slot.StartTime
+		barberID := slot.BarberID
+		expiresAt := now.Add(offerTTL)
+		entry.OfferedStartTime = &start
+		entry.OfferedBarberID = &barberID
+		entry.OfferExpiresAt = &expiresAt
+
+		ok, err := uc.repo.SetOffer(ctx, entry, now)
+		if err != nil {
+			return offered, err
+		}
+		if !ok {
+			// outra liberação ofertou para esta entrada antes
+			continue
+		}
+		entry.Status = models.WaitlistStatusOffered
+
+		uc.notify(ctx, shop, svc, entry, end)
+
+		if uc.audit != nil {
+			uc.audit.Dispatch(audit.Event{
+				BarbershopID: slot.BarbershopID,
+				Action:       "waitlist_offer_sent",
+				Entity:       "waitlist_entry",
+				EntityID:     &entry.ID,
+				Metadata: map[string]any{
+					"barber_id":  slot.BarberID,
+					"start_time": start,
+					"expires_at": expiresAt,
+				},
+			})
+		}
+
+		offered++
+		if offered >= maxOffersPerSlot {
+			break
+		}
+	}
+
+	return offered, nil
+}
+
+// serviceForBarber devolve o serviço com a duração do barbeiro, ou nil quando
+// o serviço está inativo ou o barbeiro não o faz.
+func (uc *OfferFreedSlot) serviceForBarber(
+	ctx context.Context,
+	barbershopID, barberID, serviceID uint,
+) (*models.BarbershopService, error) {
+	svc, err := uc.repo.GetService(ctx, barbershopID, serviceID)
+	if err != nil || svc == nil || !svc.Active {
+		return nil, err
+	}
+
+	override, err := uc.repo.GetBarberServiceOverride(ctx, barbershopID, barberID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil && !override.Offered {
+		return nil, nil
+	}
+	return override.ApplyTo(svc), nil
+}
+
+// notify envia a oferta por cada canal disponível (best-effort).
+func (uc *OfferFreedSlot) notify(
+	ctx context.Context,
+	shop *models.Barbershop,
+	svc *models.BarbershopService,
+	entry *models.WaitlistEntry,
+	end time.Time,
+) {
+	input := domainNotification.WaitlistOfferInput{
+		BarbershopID:   shop.ID,
+		ClientName:     entry.ClientName,
+		ClientEmail:    entry.ClientEmail,
+		ClientPhone:    entry.ClientPhone,
+		BarbershopName: shop.Name,
+		ServiceName:    svc.Name,
+		StartTime:      *entry.OfferedStartTime,
+		EndTime:        end,
+		ExpiresAt:      *entry.OfferExpiresAt,
+		Timezone:       shop.Timezone,
+	}
+	if uc.appURL != "" {
+		input.ClaimURL = uc.appURL + "/waitlist/" + entry.Token
+	}
+
+	if uc.whatsapp != nil && input.ClientPhone != "" {
+		if err := uc.whatsapp.NotifyWaitlistOffer(ctx, input); err != nil {
+			log.Printf("[Waitlist] entry=%d channel=whatsapp send_error=%v", entry.ID, err)
+		}
+	}
+	if uc.email != nil && input.ClientEmail != "" {
+		if err := uc.email.NotifyWaitlistOffer(ctx, input); err != nil {
+			log.Printf("[Waitlist] entry=%d channel=email send_error=%v", entry.ID, err)
+		}
+	}
+}
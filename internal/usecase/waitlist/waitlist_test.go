@@ -0,0 +1,382 @@
+package waitlist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domainNotification "github.com/BruksfildServices01/barber-scheduler/internal/domain/notification"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/waitlist"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	ucAppointment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+)
+
+// ======================================================
+// MOCKS
+// ======================================================
+
+type mockRepo struct {
+	shop      *models.Barbershop
+	services  map[uint]*models.BarbershopService
+	overrides map[uint]*models.BarberServiceOverride // por service_id
+	entries   []*models.WaitlistEntry
+	updated   []*models.WaitlistEntry
+}
+
+func (m *mockRepo) GetBarbershopByID(_ context.Context, _ uint) (*models.Barbershop, error) {
+	return m.shop, nil
+}
+
+func (m *mockRepo) GetService(_ context.Context, _ uint, serviceID uint) (*models.BarbershopService, error) {
+	return m.services[serviceID], nil
+}
+
+func (m *mockRepo) GetBarberServiceOverride(_ context.Context, _, _ uint, serviceID uint) (*models.BarberServiceOverride, error) {
+	return m.overrides[serviceID], nil
+}
+
+func (m *mockRepo) ActiveBarberExists(_ context.Context, _, _ uint) (bool, error) {
+	return true, nil
+}
+
+func (m *mockRepo) HasOpenEntry(_ context.Context, _, _ uint, _, _ string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockRepo) Create(_ context.Context, e *models.WaitlistEntry) error {
+	e.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *mockRepo) GetByToken(_ context.Context, token string) (*models.WaitlistEntry, error) {
+	for _, e := range m.entries {
+		if e.Token == token {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+// ListOfferCandidates espelha o filtro SQL: dia dentro do período, barbeiro
+// compatível e entrada livre (waiting ou oferta vencida).
+func (m *mockRepo) ListOfferCandidates(_ context.Context, _ uint, barberID uint, day, now time.Time, limit int) ([]*models.WaitlistEntry, error) {
+	var out []*models.WaitlistEntry
+	for _, e := range m.entries {
+		if e.BarberID != nil && *e.BarberID != barberID {
+			continue
+		}
+		if day.Before(e.DateFrom) || day.After(e.DateTo) {
+			continue
+		}
+		if !m.free(e, now) {
+			continue
+		}
+		cp := *e // como o banco: o use case recebe uma cópia
+		out = append(out, &cp)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepo) SetOffer(_ context.Context, offer *models.WaitlistEntry, now time.Time) (bool, error) {
+	for _, e := range m.entries {
+		if e.ID != offer.ID {
+			continue
+		}
+		if !m.free(e, now) {
+			return false, nil
+		}
+		e.Status = models.WaitlistStatusOffered
+		e.OfferedStartTime = offer.OfferedStartTime
+		e.OfferedBarberID = offer.OfferedBarberID
+		e.OfferExpiresAt = offer.OfferExpiresAt
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *mockRepo) free(e *models.WaitlistEntry, now time.Time) bool {
+	return e.Status == models.WaitlistStatusWaiting ||
+		(e.Status == models.WaitlistStatusOffered && e.OfferExpiresAt != nil && !e.OfferExpiresAt.After(now))
+}
+
+func (m *mockRepo) Update(_ context.Context, e *models.WaitlistEntry) error {
+	m.updated = append(m.updated, e)
+	return nil
+}
+
+type fakeNotifier struct {
+	sent []domainNotification.WaitlistOfferInput
+}
+
+func (n *fakeNotifier) NotifyWaitlistOffer(_ context.Context, in domainNotification.WaitlistOfferInput) error {
+	n.sent = append(n.sent, in)
+	return nil
+}
+
+type fakeCreator struct {
+	calls []ucAppointment.CreatePrivateAppointmentInput
+	err   error
+}
+
+func (f *fakeCreator) Execute(_ context.Context, in ucAppointment.CreatePrivateAppointmentInput) (*models.Appointment, error) {
+	f.calls = append(f.calls, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.Appointment{ID: 99}, nil
+}
+
+// ======================================================
+// HELPERS
+// ======================================================
+
+// slotTomorrow devolve um horário de amanhã às hh:mm em São Paulo.
+func slotTomorrow(hh, mm int) time.Time {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	d := time.Now().In(loc).AddDate(0, 0, 1)
+	return time.Date(d.Year(), d.Month(), d.Day(), hh, mm, 0, 0, loc)
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func newRepo() *mockRepo {
+	return &mockRepo{
+		shop: &models.Barbershop{ID: 1, Name: "Barbearia", Timezone: "America/Sao_Paulo", MinAdvanceMinutes: 60},
+		services: map[uint]*models.BarbershopService{
+			10: {ID: 10, BarbershopID: 1, Name: "Corte", DurationMin: 30, Active: true},
+			20: {ID: 20, BarbershopID: 1, Name: "Corte + Barba", DurationMin: 60, Active: true},
+		},
+		overrides: map[uint]*models.BarberServiceOverride{},
+	}
+}
+
+func addEntry(repo *mockRepo, serviceID uint, day time.Time, windows ...models.TimeWindow) *models.WaitlistEntry {
+	e := &models.WaitlistEntry{
+		ID:           uint(len(repo.entries) + 1),
+		BarbershopID: 1,
+		ServiceID:    serviceID,
+		ClientName:   "Cliente",
+		ClientPhone:  "11999990000",
+		DateFrom:     dayOf(day),
+		DateTo:       dayOf(day),
+		TimeWindows:  windows,
+		Status:       models.WaitlistStatusWaiting,
+		Token:        "tok-" + string(rune('a'+len(repo.entries))),
+	}
+	repo.entries = append(repo.entries, e)
+	return e
+}
+
+// ======================================================
+// OFFER
+// ======================================================
+
+func TestOfferFreedSlot(t *testing.T) {
+	t.Run("oferece para quem cabe no horário e na faixa preferida", func(t *testing.T) {
+		repo := newRepo()
+		start := slotTomorrow(10, 0)
+
+		fits := addEntry(repo, 10, start, models.TimeWindow{Start: "09:00", End: "12:00"})
+		tooLong := addEntry(repo, 20, start)
+		wrongWindow := addEntry(repo, 10, start, models.TimeWindow{Start: "14:00", End: "18:00"})
+
+		wa := &fakeNotifier{}
+		uc := NewOfferFreedSlot(repo, nil, wa, nil, "https://app")
+
+		n, err := uc.Execute(context.Background(), domain.FreedSlot{
+			BarbershopID: 1, BarberID: 7, StartTime: start, EndTime: start.Add(30 * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if n != 1 {
+			t.Fatalf("offers = %d, want 1", n)
+		}
+		if fits.Status != models.WaitlistStatusOffered || *fits.OfferedBarberID != 7 || !fits.OfferedStartTime.Equal(start) {
+			t.Errorf("entry compatível = %+v", fits)
+		}
+		if tooLong.Status != models.WaitlistStatusWaiting || wrongWindow.Status != models.WaitlistStatusWaiting {
+			t.Error("entradas incompatíveis não devem receber oferta")
+		}
+		if len(wa.sent) != 1 || wa.sent[0].ClaimURL != "https://app/waitlist/"+fits.Token {
+			t.Errorf("notificações = %+v", wa.sent)
+		}
+	})
+
+	t.Run("barbeiro que não faz o serviço não gera oferta", func(t *testing.T) {
+		repo := newRepo()
+		repo.overrides[10] = &models.BarberServiceOverride{ServiceID: 10, Offered: false}
+		start := slotTomorrow(10, 0)
+		e := addEntry(repo, 10, start)
+
+		uc := NewOfferFreedSlot(repo, nil, &fakeNotifier{}, nil, "")
+		n, _ := uc.Execute(context.Background(), domain.FreedSlot{
+			BarbershopID: 1, BarberID: 7, StartTime: start, EndTime: start.Add(time.Hour),
+		})
+		if n != 0 || e.Status != models.WaitlistStatusWaiting {
+			t.Errorf("offers = %d status = %s, want nenhuma oferta", n, e.Status)
+		}
+	})
+
+	t.Run("horário dentro da antecedência mínima é ignorado", func(t *testing.T) {
+		repo := newRepo()
+		start := time.Now().Add(30 * time.Minute)
+		addEntry(repo, 10, start.In(time.FixedZone("BRT", -3*3600)))
+
+		uc := NewOfferFreedSlot(repo, nil, &fakeNotifier{}, nil, "")
+		n, _ := uc.Execute(context.Background(), domain.FreedSlot{
+			BarbershopID: 1, BarberID: 7, StartTime: start, EndTime: start.Add(time.Hour),
+		})
+		if n != 0 {
+			t.Errorf("offers = %d, want 0", n)
+		}
+	})
+
+	t.Run("oferta ativa não é sobrescrita, vencida é", func(t *testing.T) {
+		repo := newRepo()
+		start := slotTomorrow(15, 0)
+		past := time.Now().Add(-time.Minute)
+		future := time.Now().Add(10 * time.Minute)
+
+		active := addEntry(repo, 10, start)
+		active.Status = models.WaitlistStatusOffered
+		active.OfferExpiresAt = &future
+
+		expired := addEntry(repo, 10, start)
+		expired.Status = models.WaitlistStatusOffered
+		expired.OfferExpiresAt = &past
+
+		uc := NewOfferFreedSlot(repo, nil, &fakeNotifier{}, nil, "")
+		n, _ := uc.Execute(context.Background(), domain.FreedSlot{
+			BarbershopID: 1, BarberID: 7, StartTime: start, EndTime: start.Add(30 * time.Minute),
+		})
+		if n != 1 {
+			t.Fatalf("offers = %d, want 1", n)
+		}
+		if !expired.OfferExpiresAt.After(time.Now()) {
+			t.Error("entrada com oferta vencida deveria receber nova oferta")
+		}
+		if active.OfferExpiresAt != &future {
+			t.Error("oferta ainda ativa não deve ser trocada")
+		}
+	})
+}
+
+// ======================================================
+// CLAIM
+// ======================================================
+
+func offeredEntry(repo *mockRepo, start time.Time, expiresIn time.Duration) *models.WaitlistEntry {
+	e := addEntry(repo, 10, start)
+	barberID := uint(7)
+	expires := time.Now().Add(expiresIn)
+	e.Status = models.WaitlistStatusOffered
+	e.OfferedStartTime = &start
+	e.OfferedBarberID = &barberID
+	e.OfferExpiresAt = &expires
+	return e
+}
+
+func TestClaimWaitlistOffer(t *testing.T) {
+	t.Run("agenda o horário oferecido pelo fluxo normal", func(t *testing.T) {
+		repo := newRepo()
+		start := slotTomorrow(10, 30)
+		e := offeredEntry(repo, start, 10*time.Minute)
+		creator := &fakeCreator{}
+
+		ap, err := NewClaimWaitlistOffer(repo, creator, nil).Execute(context.Background(), e.Token)
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if ap.ID != 99 || e.Status != models.WaitlistStatusBooked || *e.AppointmentID != 99 {
+			t.Errorf("ap = %+v entry = %+v", ap, e)
+		}
+		in := creator.calls[0]
+		if in.BarberID != 7 || in.ProductID != 10 || in.Time != "10:30" || in.Date != start.Format("2006-01-02") {
+			t.Errorf("create input = %+v", in)
+		}
+	})
+
+	t.Run("oferta vencida", func(t *testing.T) {
+		repo := newRepo()
+		e := offeredEntry(repo, slotTomorrow(10, 0), -time.Minute)
+		creator := &fakeCreator{}
+
+		_, err := NewClaimWaitlistOffer(repo, creator, nil).Execute(context.Background(), e.Token)
+		if !apperr.IsBusiness(err, "waitlist_offer_expired") {
+			t.Fatalf("err = %v, want waitlist_offer_expired", err)
+		}
+		if len(creator.calls) != 0 {
+			t.Error("não deve tentar agendar com oferta vencida")
+		}
+	})
+
+	t.Run("horário já ocupado devolve a entrada para a fila", func(t *testing.T) {
+		repo := newRepo()
+		e := offeredEntry(repo, slotTomorrow(10, 0), 10*time.Minute)
+		creator := &fakeCreator{err: apperr.ErrBusiness("time_conflict")}
+
+		_, err := NewClaimWaitlistOffer(repo, creator, nil).Execute(context.Background(), e.Token)
+		if !apperr.IsBusiness(err, "waitlist_slot_taken") {
+			t.Fatalf("err = %v, want waitlist_slot_taken", err)
+		}
+		if e.Status != models.WaitlistStatusWaiting || e.OfferedStartTime != nil {
+			t.Errorf("entry = %+v, want de volta em waiting", e)
+		}
+	})
+}
+
+// ======================================================
+// JOIN
+// ======================================================
+
+func TestJoinWaitlist_Validacoes(t *testing.T) {
+	tomorrow := slotTomorrow(0, 0).Format("2006-01-02")
+
+	cases := []struct {
+		name string
+		in   JoinWaitlistInput
+		code string
+	}{
+		{"sem contato", JoinWaitlistInput{ClientName: "A", ServiceID: 10, DateFrom: tomorrow, DateTo: tomorrow}, "contact_required"},
+		{"período invertido", JoinWaitlistInput{ClientName: "A", ClientPhone: "1", ServiceID: 10, DateFrom: tomorrow, DateTo: "2000-01-01"}, "invalid_date_range"},
+		{"faixa inválida", JoinWaitlistInput{ClientName: "A", ClientPhone: "1", ServiceID: 10, DateFrom: tomorrow, DateTo: tomorrow,
+			TimeWindows: []models.TimeWindow{{Start: "12:00", End: "09:00"}}}, "invalid_time_window"},
+		{"serviço inexistente", JoinWaitlistInput{ClientName: "A", ClientPhone: "1", ServiceID: 99, DateFrom: tomorrow, DateTo: tomorrow}, "service_not_found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.in.BarbershopID = 1
+			_, err := NewJoinWaitlist(newRepo()).Execute(context.Background(), tc.in)
+			if !apperr.IsBusiness(err, tc.code) {
+				t.Fatalf("err = %v, want %s", err, tc.code)
+			}
+		})
+	}
+
+	t.Run("entrada válida fica em waiting com token", func(t *testing.T) {
+		repo := newRepo()
+		e, err := NewJoinWaitlist(repo).Execute(context.Background(), JoinWaitlistInput{
+			BarbershopID: 1, ServiceID: 10, ClientName: " Ana ", ClientEmail: "ana@x.com",
+			DateFrom: tomorrow, DateTo: tomorrow,
+			TimeWindows: []models.TimeWindow{{Start: "9:00", End: "12:00"}},
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if e.Status != models.WaitlistStatusWaiting || len(e.Token) != 64 || e.ClientName != "Ana" {
+			t.Errorf("entry = %+v", e)
+		}
+		if e.TimeWindows[0].Start != "09:00" {
+			t.Errorf("faixa normalizada = %+v", e.TimeWindows)
+		}
+	})
+}
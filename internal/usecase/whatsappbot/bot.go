@@ -0,0 +1,831 @@
+package whatsappbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainService "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
+	domainTicket "github.com/BruksfildServices01/barber-scheduler/internal/domain/ticket"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/whatsappbot"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/timezone"
+	ucAppointment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+	ucService "github.com/BruksfildServices01/barber-scheduler/internal/usecase/service"
+	ucTicket "github.com/BruksfildServices01/barber-scheduler/internal/usecase/ticket"
+)
+
+const (
+	// ConversationTTL é quanto tempo a conversa espera a próxima mensagem;
+	// depois disso ela recomeça do menu.
+	ConversationTTL = 30 * time.Minute
+	// bookingDays é quantos dias, a partir de hoje, o menu de datas oferece.
+	bookingDays = 7
+	// maxSlots limita os horários listados em uma mensagem.
+	maxSlots = 20
+)
+
+// Os use cases abaixo são os mesmos do fluxo web; as interfaces existem
+// para os testes.
+
+type ServiceLister interface {
+	Execute(ctx context.Context, input ucService.ListPublicServicesInput) ([]*domainService.Service, error)
+}
+
+type AvailabilityFinder interface {
+	Execute(ctx context.Context, in domainAppointment.AvailabilityInput) ([]domainAppointment.TimeSlot, error)
+}
+
+type AppointmentCreator interface {
+	Execute(ctx context.Context, in ucAppointment.CreatePrivateAppointmentInput) (*models.Appointment, error)
+}
+
+type TicketGenerator interface {
+	Execute(ctx context.Context, input ucTicket.GenerateTicketInput) (string, error)
+}
+
+type TicketCanceller interface {
+	Execute(ctx context.Context, token string) error
+}
+
+type TicketRescheduler interface {
+	Execute(ctx context.Context, token, date, timeStr string) (string, error)
+}
+
+// MarketingOptOut descadastra o número das campanhas da barbearia.
+type MarketingOptOut interface {
+	Execute(ctx context.Context, barbershopID uint, phone string) (bool, error)
+}
+
+// Sender entrega a resposta pela instância da Evolution API.
+type Sender interface {
+	SendText(ctx context.Context, instanceName, number, text string) error
+}
+
+// Message é uma mensagem de texto recebida de um cliente.
+type Message struct {
+	BarbershopID uint
+	// BarberID vem da instância de um barbeiro: os horários e o agendamento
+	// são com ele. nil = instância da barbearia (qualquer barbeiro).
+	BarberID     *uint
+	InstanceName string
+	Phone        string
+	Text         string
+}
+
+// Bot conduz a conversa de agendamento pelo WhatsApp: menus numerados para
+// agendar, consultar, cancelar e remarcar. O estado fica salvo por número e
+// instância entre uma mensagem e outra.
+type Bot struct {
+	repo         domain.Repository
+	services     ServiceLister
+	availability AvailabilityFinder
+	create       AppointmentCreator
+	tickets      TicketGenerator
+	cancel       TicketCanceller
+	reschedule   TicketRescheduler
+	sender       Sender
+	optOut       MarketingOptOut
+	appURL       string
+	now          func() time.Time
+}
+
+func NewBot(
+	repo domain.Repository,
+	services ServiceLister,
+	availability AvailabilityFinder,
+	create AppointmentCreator,
+	tickets TicketGenerator,
+	cancel TicketCanceller,
+	reschedule TicketRescheduler,
+	sender Sender,
+	appURL string,
+) *Bot {
+	return &Bot{
+		repo:         repo,
+		services:     services,
+		availability: availability,
+		create:       create,
+		tickets:      tickets,
+		cancel:       cancel,
+		reschedule:   reschedule,
+		sender:       sender,
+		appURL:       appURL,
+		now:          time.Now,
+	}
+}
+
+// WithOptOut liga o descadastro das campanhas: PARAR (ou STOP) tira o
+// número da lista em vez de seguir a conversa.
+func (b *Bot) WithOptOut(o MarketingOptOut) *Bot {
+	b.optOut = o
+	return b
+}
+
+// Handle processa uma mensagem: avança a conversa, salva o novo estado e
+// envia a resposta. Falha interna encerra a conversa com uma mensagem de
+// erro, para o cliente não ficar preso em uma etapa.
+//
+// Mensagens do mesmo número chegam em paralelo (o webhook processa cada
+// uma em uma goroutine): a conversa fica travada do carregamento do estado
+// até o envio da resposta, então cada mensagem parte do estado salvo pela
+// anterior e as respostas saem na ordem.
+func (b *Bot) Handle(ctx context.Context, msg Message) error {
+	shop, err := b.repo.GetBarbershop(ctx, msg.BarbershopID)
+	if err != nil {
+		return err
+	}
+	if shop == nil {
+		return nil
+	}
+
+	// Falha no envio não desfaz o estado: a conversa já avançou.
+	var sendErr error
+	err = b.repo.LockConversation(ctx, msg.InstanceName, msg.Phone, func(ctx context.Context, repo domain.Repository) error {
+		reply, err := b.converse(ctx, repo, msg, shop)
+		if err != nil {
+			return err
+		}
+		sendErr = b.sender.SendText(ctx, msg.InstanceName, msg.Phone, reply)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// converse carrega o estado da conversa, processa a mensagem e salva o novo
+// estado com repo (já dentro do lock da conversa). Retorna a resposta.
+func (b *Bot) converse(ctx context.Context, repo domain.Repository, msg Message, shop *models.Barbershop) (string, error) {
+	now := b.now().UTC()
+
+	t := &turn{
+		bot:  b,
+		ctx:  ctx,
+		msg:  msg,
+		shop: shop,
+		loc:  timezone.Location(shop.Timezone),
+		now:  now,
+	}
+
+	conv, err := repo.GetConversation(ctx, msg.InstanceName, msg.Phone)
+	if err != nil {
+		return "", err
+	}
+	if conv != nil && now.Before(conv.ExpiresAt) {
+		t.state = conv.State
+		if err := json.Unmarshal([]byte(conv.Data), &t.data); err != nil {
+			t.state = ""
+			t.data = conversationData{}
+		}
+	}
+
+	reply, stepErr := t.step(strings.TrimSpace(msg.Text))
+	if stepErr != nil {
+		log.Printf("[WhatsApp bot] step failed barbershop=%d state=%s: %v", msg.BarbershopID, t.state, stepErr)
+		reply = "😕 Tive um problema para continuar. Tente de novo em alguns minutos ou fale com a barbearia."
+		t.state = stateDone
+	}
+
+	if err := persist(ctx, repo, t); err != nil {
+		return "", err
+	}
+	return reply, nil
+}
+
+func persist(ctx context.Context, repo domain.Repository, t *turn) error {
+	if t.state == stateDone {
+		return repo.DeleteConversation(ctx, t.msg.InstanceName, t.msg.Phone)
+	}
+
+	raw, err := json.Marshal(t.data)
+	if err != nil {
+		return err
+	}
+
+	return repo.SaveConversation(ctx, &models.WhatsAppConversation{
+		BarbershopID: t.msg.BarbershopID,
+		InstanceName: t.msg.InstanceName,
+		Phone:        t.msg.Phone,
+		State:        t.state,
+		Data:         string(raw),
+		ExpiresAt:    t.now.Add(ConversationTTL),
+		UpdatedAt:    t.now,
+	})
+}
+
+// ======================================================
+// CONVERSATION STATE
+// ======================================================
+
+const (
+	stateMenu          = "menu"
+	stateService       = "service"
+	stateDate          = "date"
+	stateSlot          = "slot"
+	stateName          = "name"
+	stateConfirm       = "confirm"
+	statePick          = "pick_appointment"
+	stateConfirmCancel = "confirm_cancel"
+	// stateDone não é salvo: a conversa é apagada.
+	stateDone = "done"
+)
+
+const (
+	actionBook       = "book"
+	actionCancel     = "cancel"
+	actionReschedule = "reschedule"
+)
+
+// conversationData guarda as escolhas já feitas e as opções do último menu
+// enviado: a resposta numérica é resolvida contra elas.
+type conversationData struct {
+	Action       string              `json:"action,omitempty"`
+	Services     []serviceOption     `json:"services,omitempty"`
+	ServiceID    uint                `json:"service_id,omitempty"`
+	ServiceName  string              `json:"service_name,omitempty"`
+	Dates        []string            `json:"dates,omitempty"`
+	Date         string              `json:"date,omitempty"`
+	Slots        []string            `json:"slots,omitempty"`
+	Time         string              `json:"time,omitempty"`
+	ClientName   string              `json:"client_name,omitempty"`
+	ClientPhone  string              `json:"client_phone,omitempty"`
+	Appointments []appointmentOption `json:"appointments,omitempty"`
+	Appointment  *appointmentOption  `json:"appointment,omitempty"`
+}
+
+type serviceOption struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	DurationMin int    `json:"duration_min"`
+	Price       int64  `json:"price"`
+}
+
+type appointmentOption struct {
+	ID          uint      `json:"id"`
+	BarberID    uint      `json:"barber_id"`
+	ServiceIDs  []uint    `json:"service_ids"`
+	ServiceName string    `json:"service_name"`
+	StartTime   time.Time `json:"start_time"`
+	TicketToken string    `json:"ticket_token"`
+}
+
+// turn é o processamento de uma mensagem.
+type turn struct {
+	bot   *Bot
+	ctx   context.Context
+	msg   Message
+	shop  *models.Barbershop
+	loc   *time.Location
+	now   time.Time
+	state string
+	data  conversationData
+}
+
+func (t *turn) step(text string) (string, error) {
+	cmd := strings.ToLower(text)
+
+	if isOptOutCommand(cmd) && t.bot.optOut != nil {
+		return t.onOptOut()
+	}
+	if cmd == "sair" {
+		t.state = stateDone
+		return "👋 Atendimento encerrado. Quando precisar, é só mandar uma mensagem.", nil
+	}
+	if t.state == "" || isMenuCommand(cmd) {
+		return t.showMenu(""), nil
+	}
+
+	switch t.state {
+	case stateMenu:
+		return t.onMenu(cmd)
+	case stateService:
+		return t.onService(cmd)
+	case stateDate:
+		return t.onDate(cmd)
+	case stateSlot:
+		return t.onSlot(cmd)
+	case stateName:
+		return t.onName(text)
+	case stateConfirm:
+		return t.onConfirm(cmd)
+	case statePick:
+		return t.onPick(cmd)
+	case stateConfirmCancel:
+		return t.onConfirmCancel(cmd)
+	}
+	return t.showMenu(""), nil
+}
+
+func isMenuCommand(cmd string) bool {
+	switch cmd {
+	case "menu", "0", "inicio", "início", "oi", "olá", "ola":
+		return true
+	}
+	return false
+}
+
+func isOptOutCommand(cmd string) bool {
+	switch cmd {
+	case "parar", "stop", "descadastrar":
+		return true
+	}
+	return false
+}
+
+// onOptOut descadastra o número das campanhas e encerra a conversa. Os
+// avisos de agendamento continuam chegando.
+func (t *turn) onOptOut() (string, error) {
+	if _, err := t.bot.optOut.Execute(t.ctx, t.msg.BarbershopID, t.msg.Phone); err != nil {
+		return "", err
+	}
+	t.state = stateDone
+	return "✅ Pronto, você não vai mais receber promoções de *" + t.shop.Name + "*. " +
+		"Os avisos dos seus agendamentos continuam chegando normalmente.", nil
+}
+
+// choice interpreta a resposta como uma opção de 1 a n e retorna o índice.
+func choice(cmd string, n int) (int, bool) {
+	v, err := strconv.Atoi(strings.TrimSpace(cmd))
+	if err != nil || v < 1 || v > n {
+		return 0, false
+	}
+	return v - 1, true
+}
+
+// ======================================================
+// MENU
+// ======================================================
+
+func (t *turn) showMenu(prefix string) string {
+	t.state = stateMenu
+	t.data = conversationData{}
+	return join(prefix,
+		"👋 Olá! Aqui é o atendimento automático da *"+t.shop.Name+"*.",
+		"",
+		"Responda com o número da opção:",
+		"*1* – Agendar um horário",
+		"*2* – Ver meus agendamentos",
+		"*3* – Cancelar um agendamento",
+		"*4* – Remarcar um agendamento",
+		"",
+		footer,
+	)
+}
+
+func (t *turn) onMenu(cmd string) (string, error) {
+	switch cmd {
+	case "1":
+		return t.startBooking()
+	case "2":
+		return t.listAppointments()
+	case "3":
+		return t.startPick(actionCancel)
+	case "4":
+		return t.startPick(actionReschedule)
+	}
+	return t.showMenu(invalidOption), nil
+}
+
+// ======================================================
+// BOOKING
+// ======================================================
+
+func (t *turn) startBooking() (string, error) {
+	input := ucService.ListPublicServicesInput{BarbershopID: t.shop.ID}
+	if t.msg.BarberID != nil {
+		input.BarberID = *t.msg.BarberID
+	}
+
+	list, err := t.bot.services.Execute(t.ctx, input)
+	if err != nil {
+		return "", err
+	}
+	if len(list) == 0 {
+		return t.showMenu("Não há serviços disponíveis para agendar no momento."), nil
+	}
+
+	t.state = stateService
+	t.data = conversationData{Action: actionBook}
+	for _, s := range list {
+		t.data.Services = append(t.data.Services, serviceOption{
+			ID:          s.ID,
+			Name:        s.Name,
+			DurationMin: s.DurationMin,
+			Price:       s.Price,
+		})
+	}
+	return t.servicePrompt(""), nil
+}
+
+func (t *turn) servicePrompt(prefix string) string {
+	lines := []string{"✂️ *Qual serviço você quer agendar?*", ""}
+	for i, s := range t.data.Services {
+		lines = append(lines, "*"+strconv.Itoa(i+1)+"* – "+s.Name+" · "+formatBRL(s.Price)+" · "+strconv.Itoa(s.DurationMin)+" min")
+	}
+	lines = append(lines, "", footer)
+	return join(prefix, lines...)
+}
+
+func (t *turn) onService(cmd string) (string, error) {
+	i, ok := choice(cmd, len(t.data.Services))
+	if !ok {
+		return t.servicePrompt(invalidOption), nil
+	}
+	t.data.ServiceID = t.data.Services[i].ID
+	t.data.ServiceName = t.data.Services[i].Name
+	return t.datePrompt(""), nil
+}
+
+// ======================================================
+// DATE AND SLOT (agendar e remarcar)
+// ======================================================
+
+func (t *turn) datePrompt(prefix string) string {
+	t.state = stateDate
+	t.data.Dates = t.data.Dates[:0]
+
+	today := t.now.In(t.loc)
+	lines := []string{"📅 *Para qual dia?*", ""}
+	for d := 0; d < bookingDays; d++ {
+		day := today.AddDate(0, 0, d)
+		t.data.Dates = append(t.data.Dates, day.Format("2006-01-02"))
+
+		label := formatDay(day)
+		switch d {
+		case 0:
+			label = "Hoje, " + label
+		case 1:
+			label = "Amanhã, " + label
+		}
+		lines = append(lines, "*"+strconv.Itoa(d+1)+"* – "+label)
+	}
+	lines = append(lines, "", footer)
+	return join(prefix, lines...)
+}
+
+func (t *turn) onDate(cmd string) (string, error) {
+	i, ok := choice(cmd, len(t.data.Dates))
+	if !ok {
+		return t.datePrompt(invalidOption), nil
+	}
+	t.data.Date = t.data.Dates[i]
+	return t.slotPrompt("")
+}
+
+// slotPrompt busca os horários livres do dia escolhido. Sem horários, volta
+// ao menu de datas.
+func (t *turn) slotPrompt(prefix string) (string, error) {
+	day, err := time.ParseInLocation("2006-01-02", t.data.Date, t.loc)
+	if err != nil {
+		return t.datePrompt(invalidOption), nil
+	}
+
+	input := domainAppointment.AvailabilityInput{BarbershopID: t.shop.ID, Date: day}
+	if t.data.Action == actionReschedule && t.data.Appointment != nil {
+		input.BarberID = t.data.Appointment.BarberID
+		input.ProductIDs = t.data.Appointment.ServiceIDs
+	} else {
+		input.ProductID = t.data.ServiceID
+		if t.msg.BarberID != nil {
+			input.BarberID = *t.msg.BarberID
+		}
+	}
+
+	slots, err := t.bot.availability.Execute(t.ctx, input)
+	if err != nil && !isBusiness(err) {
+		return "", err
+	}
+	if len(slots) == 0 {
+		return t.datePrompt(join(prefix, "Não há horários livres em "+formatDay(day)+". Escolha outro dia.")), nil
+	}
+
+	t.state = stateSlot
+	t.data.Slots = t.data.Slots[:0]
+	lines := []string{"🕐 *Horários livres em " + formatDay(day) + ":*", ""}
+	for i, s := range slots {
+		if i == maxSlots {
+			break
+		}
+		t.data.Slots = append(t.data.Slots, s.Start)
+		lines = append(lines, "*"+strconv.Itoa(i+1)+"* – "+s.Start)
+	}
+	lines = append(lines, "", footer)
+	return join(prefix, lines...), nil
+}
+
+func (t *turn) onSlot(cmd string) (string, error) {
+	i, ok := choice(cmd, len(t.data.Slots))
+	if !ok {
+		return t.slotPrompt(invalidOption)
+	}
+	t.data.Time = t.data.Slots[i]
+
+	if t.data.Action == actionReschedule {
+		return t.confirmPrompt(""), nil
+	}
+
+	// Cliente já cadastrado agenda com o nome e o telefone do cadastro.
+	client, err := t.bot.repo.FindClient(t.ctx, t.shop.ID, t.msg.Phone)
+	if err != nil {
+		return "", err
+	}
+	if client != nil {
+		t.data.ClientName = client.Name
+		t.data.ClientPhone = client.Phone
+		return t.confirmPrompt(""), nil
+	}
+
+	t.state = stateName
+	t.data.ClientPhone = t.msg.Phone
+	return "📝 Para finalizar, qual é o seu nome?", nil
+}
+
+func (t *turn) onName(text string) (string, error) {
+	name := strings.Join(strings.Fields(text), " ")
+	if len(name) < 2 || len(name) > 100 {
+		return "Não entendi. Me diga seu nome, por favor.", nil
+	}
+	t.data.ClientName = name
+	return t.confirmPrompt(""), nil
+}
+
+// ======================================================
+// CONFIRM
+// ======================================================
+
+func (t *turn) confirmPrompt(prefix string) string {
+	t.state = stateConfirm
+
+	title := "✅ *Confirma o agendamento?*"
+	service := t.data.ServiceName
+	if t.data.Action == actionReschedule && t.data.Appointment != nil {
+		title = "🔁 *Confirma a remarcação?*"
+		service = t.data.Appointment.ServiceName
+	}
+
+	day, _ := time.ParseInLocation("2006-01-02", t.data.Date, t.loc)
+	return join(prefix,
+		title,
+		"",
+		"✂️ "+service,
+		"📅 "+formatDay(day),
+		"🕐 "+t.data.Time,
+		"",
+		"*1* – Confirmar",
+		"*2* – Escolher outro horário",
+		"",
+		footer,
+	)
+}
+
+func (t *turn) onConfirm(cmd string) (string, error) {
+	switch cmd {
+	case "1":
+		if t.data.Action == actionReschedule {
+			return t.doReschedule()
+		}
+		return t.doBook()
+	case "2":
+		return t.slotPrompt("")
+	}
+	return t.confirmPrompt(invalidOption), nil
+}
+
+func (t *turn) doBook() (string, error) {
+	input := ucAppointment.CreatePrivateAppointmentInput{
+		BarbershopID:   t.shop.ID,
+		ClientName:     t.data.ClientName,
+		ClientPhone:    t.data.ClientPhone,
+		ProductID:      t.data.ServiceID,
+		Date:           t.data.Date,
+		Time:           t.data.Time,
+		IdempotencyKey: "whatsapp:" + t.msg.InstanceName + ":" + t.msg.Phone + ":" + t.data.Date + "T" + t.data.Time,
+	}
+	if t.msg.BarberID != nil {
+		input.BarberID = *t.msg.BarberID
+	}
+
+	ap, err := t.bot.create.Execute(t.ctx, input)
+	if isBusiness(err) {
+		log.Printf("[WhatsApp bot] booking refused barbershop=%d: %v", t.shop.ID, err)
+		return t.slotPrompt("😕 Esse horário não está mais disponível.")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{
+		"✅ *Agendamento confirmado, " + t.data.ClientName + "!*",
+		"",
+		"✂️ " + t.data.ServiceName,
+		"📅 " + formatDay(ap.StartTime.In(t.loc)),
+		"🕐 " + ap.StartTime.In(t.loc).Format("15:04"),
+	}
+	if ap.Status == models.AppointmentStatusAwaitingPayment {
+		lines = append(lines, "", "💳 O horário está reservado aguardando o pagamento. Finalize pelo link do ticket.")
+	}
+
+	// O agendamento já existe: sem ticket, a confirmação sai sem o link.
+	token, err := t.bot.tickets.Execute(t.ctx, ucTicket.GenerateTicketInput{
+		AppointmentID: ap.ID,
+		BarbershopID:  t.shop.ID,
+		StartTime:     ap.StartTime,
+	})
+	if err != nil {
+		log.Printf("[WhatsApp bot] ticket failed appointment=%d: %v", ap.ID, err)
+	} else {
+		lines = append(lines, "", "🎫 *Seu ticket:*", t.ticketURL(token), "_(cancelar ou remarcar também pelo link)_")
+	}
+
+	t.state = stateDone
+	return join("", append(lines, "", "_Mensagem automática · "+t.shop.Name+"_")...), nil
+}
+
+func (t *turn) doReschedule() (string, error) {
+	ap := t.data.Appointment
+	if ap == nil {
+		return t.showMenu(""), nil
+	}
+
+	newToken, err := t.bot.reschedule.Execute(t.ctx, ap.TicketToken, t.data.Date, t.data.Time)
+	switch {
+	case errors.Is(err, ucTicket.ErrTooSoon),
+		errors.Is(err, ucTicket.ErrTimeConflict),
+		errors.Is(err, ucTicket.ErrOutsideWorkingHours):
+		return t.slotPrompt("😕 Esse horário não está mais disponível.")
+	case errors.Is(err, ucTicket.ErrRescheduleNotAllowed),
+		errors.Is(err, ucTicket.ErrRescheduleWindowClosed),
+		errors.Is(err, domainTicket.ErrTicketNotFound),
+		errors.Is(err, domainTicket.ErrTokenExpired):
+		t.state = stateDone
+		return "😕 Este agendamento não pode mais ser remarcado pelo WhatsApp. Fale com a barbearia.", nil
+	case err != nil:
+		return "", err
+	}
+
+	day, _ := time.ParseInLocation("2006-01-02", t.data.Date, t.loc)
+	t.state = stateDone
+	return join("",
+		"🔁 *Agendamento remarcado!*",
+		"",
+		"✂️ "+ap.ServiceName,
+		"📅 "+formatDay(day),
+		"🕐 "+t.data.Time,
+		"",
+		"🎫 *Novo ticket:*",
+		t.ticketURL(newToken),
+		"",
+		"_Mensagem automática · "+t.shop.Name+"_",
+	), nil
+}
+
+// ======================================================
+// MY APPOINTMENTS, CANCEL AND RESCHEDULE
+// ======================================================
+
+func (t *turn) upcoming() ([]appointmentOption, error) {
+	client, err := t.bot.repo.FindClient(t.ctx, t.shop.ID, t.msg.Phone)
+	if err != nil || client == nil {
+		return nil, err
+	}
+
+	list, err := t.bot.repo.ListUpcoming(t.ctx, t.shop.ID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]appointmentOption, len(list))
+	for i, a := range list {
+		out[i] = appointmentOption{
+			ID:          a.ID,
+			BarberID:    a.BarberID,
+			ServiceIDs:  a.ServiceIDs,
+			ServiceName: a.ServiceName,
+			StartTime:   a.StartTime,
+			TicketToken: a.TicketToken,
+		}
+	}
+	return out, nil
+}
+
+func (t *turn) describe(a appointmentOption) string {
+	start := a.StartTime.In(t.loc)
+	return a.ServiceName + " · " + formatDay(start) + " às " + start.Format("15:04")
+}
+
+func (t *turn) listAppointments() (string, error) {
+	list, err := t.upcoming()
+	if err != nil {
+		return "", err
+	}
+	if len(list) == 0 {
+		return t.showMenu("Você não tem agendamentos futuros."), nil
+	}
+
+	lines := []string{"📋 *Seus próximos agendamentos:*", ""}
+	for _, a := range list {
+		lines = append(lines, "✂️ "+t.describe(a), "🎫 "+t.ticketURL(a.TicketToken), "")
+	}
+	lines = append(lines, footer)
+
+	t.state = stateMenu
+	t.data = conversationData{}
+	return join("", lines...), nil
+}
+
+func (t *turn) startPick(action string) (string, error) {
+	list, err := t.upcoming()
+	if err != nil {
+		return "", err
+	}
+	if len(list) == 0 {
+		return t.showMenu("Você não tem agendamentos futuros."), nil
+	}
+
+	t.state = statePick
+	t.data = conversationData{Action: action, Appointments: list}
+	return t.pickPrompt(""), nil
+}
+
+func (t *turn) pickPrompt(prefix string) string {
+	title := "❌ *Qual agendamento você quer cancelar?*"
+	if t.data.Action == actionReschedule {
+		title = "🔁 *Qual agendamento você quer remarcar?*"
+	}
+
+	lines := []string{title, ""}
+	for i, a := range t.data.Appointments {
+		lines = append(lines, "*"+strconv.Itoa(i+1)+"* – "+t.describe(a))
+	}
+	lines = append(lines, "", footer)
+	return join(prefix, lines...)
+}
+
+func (t *turn) onPick(cmd string) (string, error) {
+	i, ok := choice(cmd, len(t.data.Appointments))
+	if !ok {
+		return t.pickPrompt(invalidOption), nil
+	}
+	picked := t.data.Appointments[i]
+	t.data.Appointment = &picked
+
+	if t.data.Action == actionReschedule {
+		return t.datePrompt(""), nil
+	}
+
+	t.state = stateConfirmCancel
+	return t.cancelPrompt(""), nil
+}
+
+func (t *turn) cancelPrompt(prefix string) string {
+	return join(prefix,
+		"❌ *Cancelar este agendamento?*",
+		"",
+		t.describe(*t.data.Appointment),
+		"",
+		"*1* – Sim, cancelar",
+		"*2* – Não, voltar ao menu",
+	)
+}
+
+func (t *turn) onConfirmCancel(cmd string) (string, error) {
+	switch cmd {
+	case "2":
+		return t.showMenu(""), nil
+	case "1":
+	default:
+		return t.cancelPrompt(invalidOption), nil
+	}
+
+	err := t.bot.cancel.Execute(t.ctx, t.data.Appointment.TicketToken)
+	t.state = stateDone
+	switch {
+	case errors.Is(err, ucTicket.ErrCancellationWindowClosed):
+		return "😕 O prazo para cancelar pelo WhatsApp já passou. Fale com a barbearia.", nil
+	case errors.Is(err, ucTicket.ErrCannotCancel),
+		errors.Is(err, domainTicket.ErrTicketNotFound),
+		errors.Is(err, domainTicket.ErrTokenExpired):
+		return "😕 Este agendamento não pode mais ser cancelado.", nil
+	case err != nil:
+		return "", err
+	}
+
+	return join("",
+		"✅ *Agendamento cancelado.*",
+		"",
+		t.describe(*t.data.Appointment),
+		"",
+		"Quando quiser marcar de novo, é só mandar uma mensagem.",
+	), nil
+}
+
+func (t *turn) ticketURL(token string) string {
+	return t.bot.appURL + "/ticket/" + token
+}
@@ -0,0 +1,481 @@
+package whatsappbot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+	domainAppointment "github.com/BruksfildServices01/barber-scheduler/internal/domain/appointment"
+	domainService "github.com/BruksfildServices01/barber-scheduler/internal/domain/service"
+	domain "github.com/BruksfildServices01/barber-scheduler/internal/domain/whatsappbot"
+	"github.com/BruksfildServices01/barber-scheduler/internal/models"
+	"github.com/BruksfildServices01/barber-scheduler/internal/notification"
+	ucAppointment "github.com/BruksfildServices01/barber-scheduler/internal/usecase/appointment"
+	ucService "github.com/BruksfildServices01/barber-scheduler/internal/usecase/service"
+	ucTicket "github.com/BruksfildServices01/barber-scheduler/internal/usecase/ticket"
+)
+
+const (
+	instance = "bs1"
+	phone    = "5511999991234"
+)
+
+// fakeEvolution é a Evolution API: guarda o texto de cada sendText.
+type fakeEvolution struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (f *fakeEvolution) last() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sent) == 0 {
+		return ""
+	}
+	return f.sent[len(f.sent)-1]
+}
+
+func useFakeEvolution(t *testing.T) (*fakeEvolution, Sender) {
+	t.Helper()
+	f := &fakeEvolution{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/message/sendText/"+instance {
+			t.Errorf("requisição inesperada: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("apikey") != "key" {
+			t.Errorf("apikey = %q, esperado key", r.Header.Get("apikey"))
+		}
+		var body struct {
+			Number string `json:"number"`
+			Text   string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("body inválido: %v", err)
+		}
+		if body.Number != phone {
+			t.Errorf("number = %q, esperado %s", body.Number, phone)
+		}
+		f.mu.Lock()
+		f.sent = append(f.sent, body.Text)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+	return f, notification.NewEvolutionClient(srv.URL, "key")
+}
+
+type fakeRepo struct {
+	// mu é o lock da conversa (um número só nos testes).
+	mu       sync.Mutex
+	conv     *models.WhatsAppConversation
+	client   *models.Client
+	upcoming []domain.UpcomingAppointment
+	// getDelay segura a leitura do estado, abrindo espaço para uma mensagem
+	// concorrente ler o mesmo estado se o bot não travar a conversa.
+	getDelay time.Duration
+}
+
+func (r *fakeRepo) LockConversation(
+	ctx context.Context,
+	_ string,
+	_ string,
+	fn func(ctx context.Context, repo domain.Repository) error,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fn(ctx, r)
+}
+
+func (r *fakeRepo) GetConversation(context.Context, string, string) (*models.WhatsAppConversation, error) {
+	conv := r.conv
+	time.Sleep(r.getDelay)
+	return conv, nil
+}
+
+func (r *fakeRepo) SaveConversation(_ context.Context, c *models.WhatsAppConversation) error {
+	r.conv = c
+	return nil
+}
+
+func (r *fakeRepo) DeleteConversation(context.Context, string, string) error {
+	r.conv = nil
+	return nil
+}
+
+func (*fakeRepo) GetBarbershop(_ context.Context, id uint) (*models.Barbershop, error) {
+	return &models.Barbershop{ID: id, Name: "Barbearia Teste", Timezone: "America/Sao_Paulo"}, nil
+}
+
+func (r *fakeRepo) FindClient(context.Context, uint, string) (*models.Client, error) {
+	return r.client, nil
+}
+
+func (r *fakeRepo) ListUpcoming(context.Context, uint, uint) ([]domain.UpcomingAppointment, error) {
+	return r.upcoming, nil
+}
+
+type fakeServices struct{}
+
+func (fakeServices) Execute(context.Context, ucService.ListPublicServicesInput) ([]*domainService.Service, error) {
+	return []*domainService.Service{
+		{ID: 10, Name: "Corte", DurationMin: 30, Price: 5000},
+		{ID: 11, Name: "Barba", DurationMin: 20, Price: 3000},
+	}, nil
+}
+
+type fakeAvailability struct {
+	last domainAppointment.AvailabilityInput
+}
+
+func (f *fakeAvailability) Execute(_ context.Context, in domainAppointment.AvailabilityInput) ([]domainAppointment.TimeSlot, error) {
+	f.last = in
+	return []domainAppointment.TimeSlot{{Start: "09:00", End: "09:30"}, {Start: "10:00", End: "10:30"}}, nil
+}
+
+type fakeCreate struct {
+	calls []ucAppointment.CreatePrivateAppointmentInput
+	err   error
+}
+
+func (f *fakeCreate) Execute(_ context.Context, in ucAppointment.CreatePrivateAppointmentInput) (*models.Appointment, error) {
+	f.calls = append(f.calls, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	start, _ := time.ParseInLocation("2006-01-02 15:04", in.Date+" "+in.Time, loc)
+	return &models.Appointment{ID: 77, StartTime: start, Status: models.AppointmentStatusScheduled}, nil
+}
+
+type fakeTickets struct{}
+
+func (fakeTickets) Execute(context.Context, ucTicket.GenerateTicketInput) (string, error) {
+	return "tok-new", nil
+}
+
+type fakeCancel struct{ token string }
+
+func (f *fakeCancel) Execute(_ context.Context, token string) error {
+	f.token = token
+	return nil
+}
+
+type fakeReschedule struct{ token, date, time string }
+
+func (f *fakeReschedule) Execute(_ context.Context, token, date, timeStr string) (string, error) {
+	f.token, f.date, f.time = token, date, timeStr
+	return "tok-rotated", nil
+}
+
+type harness struct {
+	t          *testing.T
+	bot        *Bot
+	evo        *fakeEvolution
+	repo       *fakeRepo
+	avail      *fakeAvailability
+	create     *fakeCreate
+	cancel     *fakeCancel
+	reschedule *fakeReschedule
+	clock      time.Time
+}
+
+func newHarness(t *testing.T) *harness {
+	evo, sender := useFakeEvolution(t)
+	h := &harness{
+		t:          t,
+		evo:        evo,
+		repo:       &fakeRepo{},
+		avail:      &fakeAvailability{},
+		create:     &fakeCreate{},
+		cancel:     &fakeCancel{},
+		reschedule: &fakeReschedule{},
+		// Segunda, 9 de março de 2026, 12:00 em São Paulo.
+		clock: time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC),
+	}
+	h.bot = NewBot(h.repo, fakeServices{}, h.avail, h.create, fakeTickets{}, h.cancel, h.reschedule, sender, "https://app.test")
+	h.bot.now = func() time.Time { return h.clock }
+	return h
+}
+
+// say envia a mensagem e retorna a resposta recebida pela Evolution API.
+func (h *harness) say(text string) string {
+	h.t.Helper()
+	err := h.bot.Handle(context.Background(), Message{
+		BarbershopID: 1,
+		InstanceName: instance,
+		Phone:        phone,
+		Text:         text,
+	})
+	if err != nil {
+		h.t.Fatalf("Handle(%q): %v", text, err)
+	}
+	return h.evo.last()
+}
+
+func (h *harness) state() string {
+	if h.repo.conv == nil {
+		return ""
+	}
+	return h.repo.conv.State
+}
+
+func mustContain(t *testing.T, reply string, parts ...string) {
+	t.Helper()
+	for _, p := range parts {
+		if !strings.Contains(reply, p) {
+			t.Fatalf("resposta sem %q:\n%s", p, reply)
+		}
+	}
+}
+
+func TestBookingFlowNewClient(t *testing.T) {
+	h := newHarness(t)
+
+	mustContain(t, h.say("oi"), "Barbearia Teste", "*1* – Agendar um horário")
+	mustContain(t, h.say("1"), "*1* – Corte · R$ 50,00 · 30 min", "*2* – Barba")
+	mustContain(t, h.say("1"), "*1* – Hoje, seg, 09/03", "*2* – Amanhã, ter, 10/03", "*7* – dom, 15/03")
+	mustContain(t, h.say("2"), "Horários livres em ter, 10/03", "*2* – 10:00")
+
+	if h.avail.last.ProductID != 10 || h.avail.last.BarberID != 0 {
+		t.Fatalf("disponibilidade consultada com %+v", h.avail.last)
+	}
+
+	mustContain(t, h.say("2"), "qual é o seu nome")
+	mustContain(t, h.say("  João   Silva "), "Confirma o agendamento?", "Corte", "ter, 10/03", "10:00")
+	reply := h.say("1")
+	mustContain(t, reply, "Agendamento confirmado, João Silva!", "https://app.test/ticket/tok-new")
+
+	if len(h.create.calls) != 1 {
+		t.Fatalf("esperado 1 agendamento, obtido %d", len(h.create.calls))
+	}
+	got := h.create.calls[0]
+	if got.ClientName != "João Silva" || got.ClientPhone != phone || got.ProductID != 10 ||
+		got.Date != "2026-03-10" || got.Time != "10:00" || got.IdempotencyKey == "" {
+		t.Fatalf("agendamento criado com %+v", got)
+	}
+	if h.repo.conv != nil {
+		t.Fatal("a conversa deve ser encerrada depois de agendar")
+	}
+	if len(h.evo.sent) != 7 {
+		t.Fatalf("esperado 7 respostas, obtido %d", len(h.evo.sent))
+	}
+}
+
+// TestConcurrentMessagesAreSerialized: duas respostas enviadas em sequência
+// rápida chegam ao mesmo tempo. Cada uma precisa partir do estado salvo pela
+// outra — sem o lock da conversa, as duas leriam a escolha de serviço e a
+// segunda resposta seria perdida.
+func TestConcurrentMessagesAreSerialized(t *testing.T) {
+	h := newHarness(t)
+
+	h.say("oi")
+	mustContain(t, h.say("1"), "Qual serviço")
+	h.repo.getDelay = 20 * time.Millisecond
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- h.bot.Handle(context.Background(), Message{
+				BarbershopID: 1,
+				InstanceName: instance,
+				Phone:        phone,
+				Text:         "1",
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+
+	// "1" escolhe o Corte e o segundo "1" escolhe hoje.
+	if h.state() != stateSlot {
+		t.Fatalf("estado = %q, esperado %q", h.state(), stateSlot)
+	}
+	if h.avail.last.ProductID != 10 {
+		t.Fatalf("disponibilidade consultada com %+v", h.avail.last)
+	}
+	mustContain(t, h.evo.last(), "Horários livres em seg, 09/03")
+	if n := len(h.evo.sent); n != 4 {
+		t.Fatalf("esperado 4 respostas, obtido %d", n)
+	}
+}
+
+func TestBookingUsesRegisteredClient(t *testing.T) {
+	h := newHarness(t)
+	h.repo.client = &models.Client{ID: 5, Name: "Ana", Phone: "(11) 99999-1234"}
+
+	h.say("oi")
+	h.say("1")
+	h.say("1")
+	h.say("1")
+	mustContain(t, h.say("1"), "Confirma o agendamento?")
+	h.say("1")
+
+	got := h.create.calls[0]
+	if got.ClientName != "Ana" || got.ClientPhone != "(11) 99999-1234" {
+		t.Fatalf("esperado o contato do cadastro, obtido %q %q", got.ClientName, got.ClientPhone)
+	}
+}
+
+func TestBookingRefusedShowsSlotsAgain(t *testing.T) {
+	h := newHarness(t)
+	h.repo.client = &models.Client{ID: 5, Name: "Ana", Phone: phone}
+	h.create.err = apperr.ErrBusiness("time_conflict")
+
+	h.say("oi")
+	h.say("1")
+	h.say("1")
+	h.say("1")
+	h.say("1")
+	mustContain(t, h.say("1"), "não está mais disponível", "Horários livres")
+	if h.state() != stateSlot {
+		t.Fatalf("estado = %q, esperado %q", h.state(), stateSlot)
+	}
+}
+
+func TestInvalidOptionRepeatsPrompt(t *testing.T) {
+	h := newHarness(t)
+
+	h.say("oi")
+	h.say("1")
+	mustContain(t, h.say("9"), invalidOption, "Qual serviço")
+	if h.state() != stateService {
+		t.Fatalf("estado = %q, esperado %q", h.state(), stateService)
+	}
+
+	mustContain(t, h.say("menu"), "*4* – Remarcar")
+	if h.state() != stateMenu {
+		t.Fatalf("estado = %q, esperado %q", h.state(), stateMenu)
+	}
+}
+
+func TestConversationExpires(t *testing.T) {
+	h := newHarness(t)
+
+	h.say("oi")
+	h.say("1")
+	h.clock = h.clock.Add(ConversationTTL + time.Minute)
+
+	// "1" depois do prazo não escolhe serviço: a conversa recomeça.
+	mustContain(t, h.say("1"), "*1* – Agendar um horário")
+	if h.state() != stateMenu {
+		t.Fatalf("estado = %q, esperado %q", h.state(), stateMenu)
+	}
+}
+
+func TestExitEndsConversation(t *testing.T) {
+	h := newHarness(t)
+
+	h.say("oi")
+	mustContain(t, h.say("Sair"), "Atendimento encerrado")
+	if h.repo.conv != nil {
+		t.Fatal("sair deve apagar a conversa")
+	}
+}
+
+type fakeOptOut struct {
+	barbershopID uint
+	phone        string
+}
+
+func (f *fakeOptOut) Execute(_ context.Context, barbershopID uint, phone string) (bool, error) {
+	f.barbershopID, f.phone = barbershopID, phone
+	return true, nil
+}
+
+func TestStopOptsOutOfCampaigns(t *testing.T) {
+	h := newHarness(t)
+
+	// Sem opt-out ligado, PARAR é só uma mensagem: o bot mostra o menu.
+	mustContain(t, h.say("PARAR"), "Agendar um horário")
+
+	optOut := &fakeOptOut{}
+	h.bot.WithOptOut(optOut)
+
+	h.say("oi")
+	mustContain(t, h.say("Parar"), "não vai mais receber promoções", "Barbearia Teste")
+	if optOut.barbershopID != 1 || optOut.phone != phone {
+		t.Errorf("descadastro com dados inesperados: %+v", optOut)
+	}
+	if h.repo.conv != nil {
+		t.Fatal("PARAR deve encerrar a conversa")
+	}
+}
+
+func upcomingFixture() []domain.UpcomingAppointment {
+	return []domain.UpcomingAppointment{{
+		ID:          40,
+		BarberID:    3,
+		ServiceIDs:  []uint{10, 11},
+		ServiceName: "Corte + Barba",
+		StartTime:   time.Date(2026, 3, 12, 17, 0, 0, 0, time.UTC),
+		TicketToken: "tok-40",
+	}}
+}
+
+func TestMyAppointments(t *testing.T) {
+	h := newHarness(t)
+
+	h.say("oi")
+	mustContain(t, h.say("2"), "Você não tem agendamentos futuros")
+
+	h.repo.client = &models.Client{ID: 5, Name: "Ana"}
+	h.repo.upcoming = upcomingFixture()
+	mustContain(t, h.say("2"), "Corte + Barba · qui, 12/03 às 14:00", "https://app.test/ticket/tok-40")
+}
+
+func TestCancelFlow(t *testing.T) {
+	h := newHarness(t)
+	h.repo.client = &models.Client{ID: 5, Name: "Ana"}
+	h.repo.upcoming = upcomingFixture()
+
+	h.say("oi")
+	mustContain(t, h.say("3"), "Qual agendamento você quer cancelar?", "*1* – Corte + Barba")
+	mustContain(t, h.say("1"), "Cancelar este agendamento?")
+	mustContain(t, h.say("1"), "Agendamento cancelado")
+
+	if h.cancel.token != "tok-40" {
+		t.Fatalf("cancelamento com token %q, esperado tok-40", h.cancel.token)
+	}
+	if h.repo.conv != nil {
+		t.Fatal("a conversa deve ser encerrada depois de cancelar")
+	}
+}
+
+func TestRescheduleFlow(t *testing.T) {
+	h := newHarness(t)
+	h.repo.client = &models.Client{ID: 5, Name: "Ana"}
+	h.repo.upcoming = upcomingFixture()
+
+	h.say("oi")
+	mustContain(t, h.say("4"), "Qual agendamento você quer remarcar?")
+	mustContain(t, h.say("1"), "Para qual dia?")
+	h.say("3")
+
+	if h.avail.last.BarberID != 3 || len(h.avail.last.ProductIDs) != 2 {
+		t.Fatalf("disponibilidade da remarcação consultada com %+v", h.avail.last)
+	}
+
+	mustContain(t, h.say("1"), "Confirma a remarcação?", "Corte + Barba", "qua, 11/03", "09:00")
+	mustContain(t, h.say("1"), "Agendamento remarcado", "https://app.test/ticket/tok-rotated")
+
+	if h.reschedule.token != "tok-40" || h.reschedule.date != "2026-03-11" || h.reschedule.time != "09:00" {
+		t.Fatalf("remarcação com %+v", h.reschedule)
+	}
+	if len(h.create.calls) != 0 {
+		t.Fatal("remarcar não cria agendamento")
+	}
+}
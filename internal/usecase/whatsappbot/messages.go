@@ -0,0 +1,42 @@
+package whatsappbot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BruksfildServices01/barber-scheduler/internal/apperr"
+)
+
+const (
+	footer        = "_Envie *menu* para recomeçar ou *sair* para encerrar._"
+	invalidOption = "Não entendi. Responda com o número de uma das opções."
+)
+
+var weekdaysShortPT = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+// formatDay formata a data como "sex, 16/10".
+func formatDay(t time.Time) string {
+	return fmt.Sprintf("%s, %02d/%02d", weekdaysShortPT[t.Weekday()], t.Day(), int(t.Month()))
+}
+
+func formatBRL(cents int64) string {
+	return fmt.Sprintf("R$ %d,%02d", cents/100, cents%100)
+}
+
+// join monta a mensagem: prefix (aviso, opcional) em uma linha própria antes
+// das demais.
+func join(prefix string, lines ...string) string {
+	if prefix != "" {
+		lines = append([]string{prefix, ""}, lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBusiness indica erro de regra de negócio (horário ocupado, fora do
+// expediente...), que o bot responde em vez de encerrar a conversa.
+func isBusiness(err error) bool {
+	var be apperr.BusinessError
+	return errors.As(err, &be)
+}